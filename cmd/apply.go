@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"strings"
+
+	"github.com/hungthai1401/occtx/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+// applyCmd copies sections of a context into the active config
+var applyCmd = &cobra.Command{
+	Use:   "apply <name> --only <section,...>",
+	Short: "Copy top-level sections of a context into the active config",
+	Long: `Apply copies just the given top-level sections of a context, such as theme or
+agent, into the active config and leaves the rest of it intact, e.g. one
+context's agents on top of another's provider. The context is resolved as a
+switch resolves it, and the current context stays as it is.
+
+Examples:
+  occtx apply review --only agent
+  occtx apply personal --only theme,keybinds
+  occtx apply review --only agent --dry-run   # Show the change first`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeContextArg,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		sections, _ := cmd.Flags().GetStringSlice("only")
+		if len(sections) == 0 {
+			return usageErrorf("--only is required, e.g. --only theme,agent")
+		}
+
+		force, _ := cmd.Flags().GetBool("force")
+		manager, err := newManager()
+		if err != nil {
+			return err
+		}
+		manager.SetForce(force)
+
+		if dryRun(cmd) {
+			output, err := planOutput(cmd)
+			if err != nil {
+				return err
+			}
+			plan, err := manager.PlanApplySections(args[0], sections)
+			if err != nil {
+				return err
+			}
+			return printPlan(cmd.OutOrStdout(), plan, output)
+		}
+
+		if err := manager.ApplySections(args[0], sections); err != nil {
+			return err
+		}
+		printer := ui.NewColorPrinter()
+		printer.PrintSuccess("Applied %s of '%s' to the active config\n", strings.Join(sections, ", "), args[0])
+		return nil
+	},
+}
+
+func init() {
+	applyCmd.Flags().StringSlice("only", nil, "Top-level sections to copy, comma-separated (e.g. theme,agent)")
+	applyCmd.Flags().Bool("dry-run", false, "Print what would change without writing anything")
+	applyCmd.Flags().StringP("output", "o", "text", "Plan output format with --dry-run (text, json)")
+	applyCmd.Flags().Bool("force", false, "Overwrite an active config occtx didn't write")
+	_ = applyCmd.RegisterFlagCompletionFunc("output", completeValues("text", "json"))
+	_ = applyCmd.RegisterFlagCompletionFunc("only", completeValues("agent", "command", "keybinds", "mcp", "model", "permission", "provider", "theme", "tools"))
+	rootCmd.AddCommand(applyCmd)
+}
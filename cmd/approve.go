@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"github.com/hungthai1401/occtx/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+// approveCmd marks contexts as approved (or back to draft) for strict environments
+var approveCmd = &cobra.Command{
+	Use:   "approve <name>",
+	Short: "Approve a context for use in strict mode",
+	Long: `Approve records that a context was reviewed in its current form. Editing
+the file afterwards turns it back into a draft until it is approved again.
+Only the owner of the context file may approve or revoke.
+
+With "approval": {"required": true} in the occtx config, switching to a
+context that isn't approved fails in strict mode (--strict or OCCTX_STRICT=1).
+
+Examples:
+  occtx approve work            # Approve the current content of 'work'
+  occtx approve work --revoke   # Mark 'work' as draft again
+  occtx -s work --meta          # Show approval status`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeContextArg,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		manager, err := newManager()
+		if err != nil {
+			return err
+		}
+
+		printer := ui.NewColorPrinter()
+		if revoke, _ := cmd.Flags().GetBool("revoke"); revoke {
+			if err := manager.RevokeApproval(args[0]); err != nil {
+				return err
+			}
+			printer.PrintSuccess("Context '%s' marked as draft\n", args[0])
+			return nil
+		}
+
+		approval, err := manager.ApproveContext(args[0])
+		if err != nil {
+			return err
+		}
+		printer.PrintSuccess("Context '%s' approved by %s\n", args[0], approval.By)
+		return nil
+	},
+}
+
+func init() {
+	approveCmd.Flags().Bool("revoke", false, "Return the context to draft")
+	rootCmd.AddCommand(approveCmd)
+}
@@ -0,0 +1,107 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/hungthai1401/occtx/internal/context"
+	"github.com/hungthai1401/occtx/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+// archiveCmd moves contexts out of the way without deleting them
+var archiveCmd = &cobra.Command{
+	Use:   "archive [name...]",
+	Short: "Archive contexts to keep the list short",
+	Long: `Archive moves contexts into the archive subdirectory of the settings
+directory. Archived contexts don't show up in listings, can't be switched to
+and are skipped by the picker, but nothing is deleted: unarchive brings them
+back with their metadata.
+
+Examples:
+  occtx archive old-client        # Archive a context
+  occtx archive demo-1 demo-2     # Archive several
+  occtx archive --list            # Show archived contexts
+  occtx unarchive old-client      # Bring one back`,
+	ValidArgsFunction: completeContextArgs,
+	RunE:              runArchive,
+}
+
+// unarchiveCmd restores archived contexts
+var unarchiveCmd = &cobra.Command{
+	Use:               "unarchive <name...>",
+	Short:             "Restore archived contexts",
+	Args:              cobra.MinimumNArgs(1),
+	ValidArgsFunction: completeArchivedArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		manager, err := newManager()
+		if err != nil {
+			return err
+		}
+
+		printer := ui.NewColorPrinter()
+		for _, name := range args {
+			if err := manager.UnarchiveContext(name); err != nil {
+				return err
+			}
+			printer.PrintSuccess("Context '%s' unarchived\n", name)
+		}
+		return nil
+	},
+}
+
+func init() {
+	archiveCmd.Flags().BoolP("list", "l", false, "List archived contexts")
+	rootCmd.AddCommand(archiveCmd)
+	rootCmd.AddCommand(unarchiveCmd)
+}
+
+func runArchive(cmd *cobra.Command, args []string) error {
+	list, _ := cmd.Flags().GetBool("list")
+	if list == (len(args) > 0) {
+		return fmt.Errorf("specify context names to archive or --list")
+	}
+
+	manager, err := newManager()
+	if err != nil {
+		return err
+	}
+
+	if list {
+		return listArchived(manager)
+	}
+
+	printer := ui.NewColorPrinter()
+	for _, name := range args {
+		if err := manager.ArchiveContext(name); err != nil {
+			return err
+		}
+		printer.PrintSuccess("Context '%s' archived\n", name)
+	}
+	return nil
+}
+
+func listArchived(manager *context.Manager) error {
+	contexts, err := manager.ListArchivedContexts()
+	if err != nil {
+		return err
+	}
+
+	if len(contexts) == 0 {
+		fmt.Println("No archived contexts")
+		return nil
+	}
+
+	meta, err := manager.ListContextMeta()
+	if err != nil {
+		return err
+	}
+
+	for _, ctx := range contexts {
+		if entry := meta[ctx.Name]; entry != nil && entry.ArchivedAt != nil {
+			fmt.Printf("%s (archived %s)\n", ctx.Name, entry.ArchivedAt.Format("2006-01-02 15:04:05"))
+		} else {
+			fmt.Println(ctx.Name)
+		}
+	}
+	return nil
+}
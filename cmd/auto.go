@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/hungthai1401/occtx/internal/config"
+	"github.com/hungthai1401/occtx/internal/context"
+	"github.com/hungthai1401/occtx/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+// autoCmd switches to the context the working directory is mapped to
+var autoCmd = &cobra.Command{
+	Use:   "auto",
+	Short: "Switch to the context mapped to the working directory",
+	Long: `Auto switches to the context for the working directory: the one named in
+the nearest ` + config.DirContextFileName + ` file, or by the longest matching prefix
+of the nearest ` + config.DirMapFileName + ` (see 'occtx map'). In a monorepo,
+run it after moving between packages, or let the shell integration
+('occtx init') do it before each prompt.
+
+Examples:
+  occtx auto
+  occtx auto --print   # Show the context without switching`,
+	Args: cobra.NoArgs,
+	RunE: runAuto,
+}
+
+func init() {
+	autoCmd.Flags().Bool("print", false, "Print the mapped context and where it comes from instead of switching")
+	addSwitchFlags(autoCmd.Flags())
+	rootCmd.AddCommand(autoCmd)
+}
+
+func runAuto(cmd *cobra.Command, args []string) error {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+	name, source, err := context.FindDirContext(cwd)
+	if err != nil {
+		return err
+	}
+	if name == "" {
+		return fmt.Errorf("no context is mapped to %s (add one with 'occtx map add <path> <context>' or a %s file)", cwd, config.DirContextFileName)
+	}
+
+	if printOnly, _ := cmd.Flags().GetBool("print"); printOnly {
+		fmt.Printf("%s (%s)\n", name, source)
+		return nil
+	}
+
+	// A qualified name, such as proj:api, switches in its own scope
+	if useProject, plain, ok := splitScopedName(name); ok {
+		inProject = useProject
+		name = plain
+	}
+
+	manager, err := newSwitchManager(switchOptionsFromFlags(cmd))
+	if err != nil {
+		return err
+	}
+	printer := ui.NewColorPrinter()
+	if current, _ := manager.GetCurrentContext(); current == name {
+		printer.PrintInfo("Already on context: %s (%s)\n", current, source)
+		return nil
+	}
+	if err := manager.SwitchToContext(name); err != nil {
+		return err
+	}
+	current, _ := manager.GetCurrentContext()
+	printer.PrintSuccess("Switched to context: %s (%s)\n", current, source)
+	return nil
+}
@@ -0,0 +1,247 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/hungthai1401/occtx/internal/context"
+	"github.com/hungthai1401/occtx/internal/schedule"
+	"github.com/hungthai1401/occtx/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+// backupCmd archives contexts, state and the active config
+var backupCmd = &cobra.Command{
+	Use:   "backup [create|list|restore|install-schedule]",
+	Short: "Back up contexts and the active config",
+	Long: `Backup writes a compressed archive of the settings directory (contexts,
+state, metadata, stashes, archived and trashed contexts), the active
+opencode.json and the occtx config file to <settings>/backups. Archives are readable only by you.
+
+restore puts the files of a backup back in place. The current state is
+backed up first, so a restore can be undone by restoring that backup.
+
+install-schedule sets up the platform scheduler (a launchd agent on macOS, a
+systemd user timer on Linux, Task Scheduler on Windows) to run
+'occtx backup create --prune' for the global scope.
+
+Examples:
+  occtx backup                          # Create a backup
+  occtx backup create --prune           # Create one and keep only the newest backup.keep
+  occtx backup list                     # List backups, newest first
+  occtx backup restore occtx-backup-20260101-120000.tar.gz
+  occtx backup install-schedule         # Back up daily
+  occtx backup install-schedule --every weekly
+  occtx backup install-schedule --uninstall`,
+	Args: subcommandArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return createBackup(false)
+	},
+}
+
+var backupCreateCmd = &cobra.Command{
+	Use:   "create",
+	Short: "Create a backup",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		prune, _ := cmd.Flags().GetBool("prune")
+		return createBackup(prune)
+	},
+}
+
+var backupListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List backups",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		manager, err := newManager()
+		if err != nil {
+			return err
+		}
+
+		backups, err := manager.ListBackups()
+		if err != nil {
+			return err
+		}
+		if len(backups) == 0 {
+			fmt.Println("No backups")
+			return nil
+		}
+
+		for _, backup := range backups {
+			fmt.Printf("%s  %s  %s\n", backup.CreatedAt.Local().Format("2006-01-02 15:04:05"), formatBytes(backup.Size), backup.Path)
+		}
+		return nil
+	},
+}
+
+var backupRestoreCmd = &cobra.Command{
+	Use:   "restore <backup>",
+	Short: "Restore a backup, backing up the current state first",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		manager, err := newManager()
+		if err != nil {
+			return err
+		}
+		return restoreBackup(manager, args[0])
+	},
+}
+
+var backupScheduleCmd = &cobra.Command{
+	Use:   "install-schedule",
+	Short: "Run 'occtx backup create --prune' on a schedule",
+	Args:  cobra.NoArgs,
+	RunE:  runBackupSchedule,
+}
+
+func init() {
+	backupCreateCmd.Flags().Bool("prune", false, "Delete old backups beyond backup.keep from the occtx config")
+	backupScheduleCmd.Flags().String("every", string(schedule.Daily), "How often to back up (hourly, daily, weekly)")
+	backupScheduleCmd.Flags().Bool("uninstall", false, "Remove the scheduled backup")
+
+	backupCmd.AddCommand(backupCreateCmd)
+	backupCmd.AddCommand(backupListCmd)
+	backupCmd.AddCommand(backupRestoreCmd)
+	backupCmd.AddCommand(backupScheduleCmd)
+	rootCmd.AddCommand(backupCmd)
+}
+
+func createBackup(prune bool) error {
+	manager, err := newManager()
+	if err != nil {
+		return err
+	}
+
+	progress := ui.NewProgress("Creating backup")
+	progress.Start()
+	backup, err := manager.CreateBackup()
+	progress.Stop()
+	if err != nil {
+		return err
+	}
+
+	printer := ui.NewColorPrinter()
+	printer.PrintSuccess("Backup written to %s\n", backup.Path)
+
+	if prune {
+		pruned, err := manager.PruneBackups(manager.GetConfig().Backup.EffectiveKeep())
+		if err != nil {
+			return err
+		}
+		if len(pruned) > 0 {
+			fmt.Printf("Pruned %d old backup(s)\n", len(pruned))
+		}
+	}
+	return nil
+}
+
+// restoreBackup restores the named backup and reports how to undo it
+func restoreBackup(manager *context.Manager, name string) error {
+	safety, err := manager.RestoreBackup(name)
+	if safety != nil {
+		fmt.Printf("Backed up the current state to %s first\n", safety.Path)
+	}
+	if err != nil {
+		return err
+	}
+
+	ui.NewColorPrinter().PrintSuccess("Restored backup %s\n", filepath.Base(name))
+	ui.Hintf("Undo with 'occtx backup restore %s'", safety.Name)
+	return nil
+}
+
+// backupJobName identifies the scheduled backup of the current profile
+func backupJobName() string {
+	if profile != "" {
+		return "occtx-backup-" + profile
+	}
+	return "occtx-backup"
+}
+
+func runBackupSchedule(cmd *cobra.Command, args []string) error {
+	if inProject {
+		return fmt.Errorf("scheduled backups cover the global scope; run 'occtx --in-project backup create' from the project instead")
+	}
+
+	printer := ui.NewColorPrinter()
+	name := backupJobName()
+
+	if uninstall, _ := cmd.Flags().GetBool("uninstall"); uninstall {
+		err := schedule.Uninstall(name)
+		if errors.Is(err, schedule.ErrNotInstalled) {
+			return fmt.Errorf("no scheduled backup is installed")
+		}
+		if err != nil {
+			return err
+		}
+		printer.PrintSuccess("Removed the scheduled backup\n")
+		return nil
+	}
+
+	every, _ := cmd.Flags().GetString("every")
+	frequency, err := schedule.ParseFrequency(every)
+	if err != nil {
+		return err
+	}
+
+	manager, err := newManager()
+	if err != nil {
+		return err
+	}
+
+	executable, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	if resolved, err := filepath.EvalSymlinks(executable); err == nil {
+		executable = resolved
+	}
+
+	// Scheduled jobs don't inherit the shell's environment, so pin the
+	// config dir (which also locates the occtx config) and the profile
+	command := []string{executable, "--config-dir", filepath.Dir(manager.GetPaths().ConfigFile)}
+	if profile != "" {
+		command = append(command, "--profile", profile)
+	}
+	command = append(command, "--quiet", "backup", "create", "--prune")
+
+	if err := schedule.Install(schedule.Job{Name: name, Command: command, Frequency: frequency}); err != nil {
+		return err
+	}
+
+	status, err := schedule.Query(name)
+	if err != nil {
+		return err
+	}
+	printer.PrintSuccess("Scheduled %s backups with %s (%s)\n", frequency, status.Scheduler, status.Location)
+	return nil
+}
+
+// formatBytes renders a size for listings
+func formatBytes(size int64) string {
+	switch {
+	case size >= 1<<20:
+		return fmt.Sprintf("%.1f MiB", float64(size)/(1<<20))
+	case size >= 1<<10:
+		return fmt.Sprintf("%.1f KiB", float64(size)/(1<<10))
+	}
+	return fmt.Sprintf("%d B", size)
+}
+
+// formatAge renders how long ago t was, at a coarse granularity
+func formatAge(t time.Time) string {
+	age := time.Since(t)
+	switch {
+	case age < time.Minute:
+		return "just now"
+	case age < time.Hour:
+		return fmt.Sprintf("%d minute(s) ago", int(age.Minutes()))
+	case age < 48*time.Hour:
+		return fmt.Sprintf("%d hour(s) ago", int(age.Hours()))
+	}
+	return fmt.Sprintf("%d day(s) ago", int(age.Hours()/24))
+}
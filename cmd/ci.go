@@ -0,0 +1,141 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+
+	"github.com/fatih/color"
+	"github.com/hungthai1401/occtx/internal/context"
+	"github.com/hungthai1401/occtx/internal/ui"
+)
+
+// CIEnvVar turns CI mode on or off; when unset, CI mode follows the CI
+// variable most CI services set
+const CIEnvVar = "OCCTX_CI"
+
+// Exit codes in CI and strict mode; otherwise every failure exits with exitError
+const (
+	exitError    = 1 // Any other failure
+	exitUsage    = 2 // Invalid flags or arguments
+	exitNotFound = 3 // The context doesn't exist
+	exitRejected = 4 // Refused by policy, approval, validation, a guard or a hook
+	exitReadOnly = 5 // A change was attempted in read-only mode
+	exitWarnings = 6 // Warnings were reported in strict mode
+	exitOffline  = 7 // The network was needed in offline mode
+)
+
+// Kinds of failure CI mode reports with the exit codes above
+const (
+	kindError    = "error"
+	kindUsage    = "usage"
+	kindNotFound = "not_found"
+	kindRejected = "rejected"
+	kindReadOnly = "read_only"
+	kindWarnings = "warnings"
+	kindOffline  = "offline"
+)
+
+// usageError marks errors in how occtx was invoked
+type usageError struct {
+	err error
+}
+
+func (e *usageError) Error() string { return e.err.Error() }
+func (e *usageError) Unwrap() error { return e.err }
+
+// usageErrorf returns a formatted usage error
+func usageErrorf(format string, args ...interface{}) error {
+	return &usageError{fmt.Errorf(format, args...)}
+}
+
+// ciEnabled reports whether CI mode is on: --ci, OCCTX_CI, or else CI
+func ciEnabled() bool {
+	if ciMode {
+		return true
+	}
+	if value, ok := os.LookupEnv(CIEnvVar); ok {
+		enabled, _ := strconv.ParseBool(value)
+		return enabled
+	}
+	enabled, _ := strconv.ParseBool(os.Getenv("CI"))
+	return enabled
+}
+
+// applyCIMode turns on everything CI mode implies for this invocation
+func applyCIMode() {
+	quiet = true
+	color.NoColor = true
+	ui.SetProgressEnabled(false)
+	ui.SetPromptsEnabled(false)
+	ui.SetDiagnosticsJSON(true)
+}
+
+// exitCode classifies err for CI mode
+func exitCode(err error) (int, string) {
+	var (
+		usage       *usageError
+		notFound    *context.NotFoundError
+		violation   *context.PolicyViolationError
+		rejected    *context.ImportRejectedError
+		notApproved *context.NotApprovedError
+		costGuard   *context.CostGuardError
+		requires    *context.RequirementsError
+		foreign     *context.ForeignConfigError
+		hook        *context.HookError
+		review      *context.ReviewDeclinedError
+		warnings    *context.StrictWarningsError
+	)
+	switch {
+	case errors.As(err, &warnings):
+		return exitWarnings, kindWarnings
+	case errors.As(err, &usage):
+		return exitUsage, kindUsage
+	case errors.As(err, &notFound):
+		return exitNotFound, kindNotFound
+	case errors.As(err, &violation), errors.As(err, &rejected), errors.As(err, &notApproved),
+		errors.As(err, &costGuard), errors.As(err, &requires), errors.As(err, &foreign),
+		errors.As(err, &hook), errors.As(err, &review):
+		return exitRejected, kindRejected
+	case errors.Is(err, context.ErrReadOnly):
+		return exitReadOnly, kindReadOnly
+	case errors.Is(err, context.ErrOffline):
+		return exitOffline, kindOffline
+	default:
+		return exitError, kindError
+	}
+}
+
+// ReportError prints err to w and returns the process exit code. In CI and
+// strict mode the exit code tells failures apart. In CI mode the error is a
+// JSON object; with -o json it is a JSON diagnostic.
+func ReportError(w io.Writer, err error) int {
+	ci := ciEnabled()
+	if !ci && !strict && !context.StrictFromEnv() {
+		ui.WriteDiagnostic(w, ui.LevelError, err.Error())
+		return exitError
+	}
+
+	code, kind := exitCode(err)
+	if !ci {
+		ui.WriteDiagnostic(w, ui.LevelError, err.Error())
+		return code
+	}
+
+	report := struct {
+		Error    string   `json:"error"`
+		Kind     string   `json:"kind"`
+		ExitCode int      `json:"exit_code"`
+		Warnings []string `json:"warnings,omitempty"`
+	}{Error: err.Error(), Kind: kind, ExitCode: code}
+	var warnings *context.StrictWarningsError
+	if errors.As(err, &warnings) {
+		report.Warnings = warnings.Warnings
+	}
+	data, _ := json.Marshal(report)
+	fmt.Fprintf(w, "%s\n", data)
+	return code
+}
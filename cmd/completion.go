@@ -0,0 +1,153 @@
+package cmd
+
+import (
+	"strings"
+
+	"github.com/hungthai1401/occtx/internal/context"
+	"github.com/spf13/cobra"
+)
+
+// contextNames lists the names of the selected scope's contexts starting
+// with prefix, for shell completion. A scope-qualified prefix such as
+// "proj:d" completes qualified names of that scope.
+func contextNames(prefix string, archived bool) []string {
+	useProject, plain, qualified := splitScopedName(prefix)
+	if !qualified {
+		useProject = inProject
+	}
+	manager, err := newManagerForScope(useProject)
+	if err != nil {
+		return nil
+	}
+
+	list := manager.ListContexts
+	if archived {
+		list = manager.ListArchivedContexts
+	}
+	contexts, err := list()
+	if err != nil {
+		return nil
+	}
+
+	names := make([]string, 0, len(contexts))
+	for _, ctx := range contexts {
+		if !strings.HasPrefix(ctx.Name, plain) {
+			continue
+		}
+		if qualified {
+			// Keep the prefix as typed, so proj: stays proj:
+			names = append(names, prefix[:len(prefix)-len(plain)]+ctx.Name)
+		} else {
+			names = append(names, ctx.Name)
+		}
+	}
+	return names
+}
+
+// completeContextArg completes the first argument with a context name and
+// leaves later arguments to the shell
+func completeContextArg(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveDefault
+	}
+	return contextNames(toComplete, false), cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeContextArgs completes every argument with a context name not
+// given yet
+func completeContextArgs(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return withoutArgs(contextNames(toComplete, false), args), cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeArchivedArgs completes every argument with an archived context
+// name not given yet
+func completeArchivedArgs(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return withoutArgs(contextNames(toComplete, true), args), cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeContextFlag completes a flag value with a context name
+func completeContextFlag(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return contextNames(toComplete, false), cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeValues completes a flag value from a fixed list
+func completeValues(values ...string) func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return values, cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+// completeTemplates completes a flag value with a context template name
+func completeTemplates(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	manager, err := newManager()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	templates, err := manager.ListTemplates()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	names := make([]string, 0, len(templates))
+	for _, tmpl := range templates {
+		names = append(names, tmpl.Name)
+	}
+	return names, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeProfiles completes --profile with the configured workspace profiles
+func completeProfiles(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	manager, err := context.NewManagerForProfile(inProject, "")
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return manager.ProfileNames(), cobra.ShellCompDirectiveNoFileComp
+}
+
+// registerRootCompletions attaches value completion to root's flags; it runs
+// after they are defined
+func registerRootCompletions() {
+	formats := make([]string, 0, len(context.GetAllFormats()))
+	for _, format := range context.GetAllFormats() {
+		formats = append(formats, format.String())
+	}
+
+	completions := map[string]func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective){
+		"delete":        completeContextFlag,
+		"edit":          completeContextFlag,
+		"show":          completeContextFlag,
+		"export":        completeContextFlag,
+		"switch-to":     completeContextFlag,
+		"from-template": completeTemplates,
+		"format":        completeValues(formats...),
+		"provider":      completeValues(context.SkeletonProviders()...),
+		"output":        completeValues("text", "json"),
+		"sort":          completeValues(string(context.SortName), string(context.SortUsed), string(context.SortFrequency)),
+		"validate":      completeValues("off", "warn", "reject"),
+		"secrets":       completeValues("off", "warn", "reject"),
+		"merge-arrays":  completeValues("replace", "append", "union"),
+		"merge-nulls":   completeValues("set", "delete", "ignore"),
+	}
+	for name, complete := range completions {
+		// Only fails for unknown or already registered flags
+		_ = rootCmd.RegisterFlagCompletionFunc(name, complete)
+	}
+	_ = rootCmd.RegisterFlagCompletionFunc("profile", completeProfiles)
+	rootCmd.ValidArgsFunction = completeContextArg
+}
+
+// withoutArgs drops the names already given as arguments
+func withoutArgs(names, args []string) []string {
+	given := make(map[string]bool, len(args))
+	for _, arg := range args {
+		given[arg] = true
+	}
+
+	kept := names[:0]
+	for _, name := range names {
+		if !given[name] {
+			kept = append(kept, name)
+		}
+	}
+	return kept
+}
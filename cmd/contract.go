@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/hungthai1401/occtx/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+// ContractVersion is the version of the CLI contract: the output and exit
+// codes scripts may rely on. It changes only when covered output changes
+// incompatibly; the golden tests in test/testdata/golden pin it down.
+const ContractVersion = 1
+
+// contractExitCode is one exit code of the contract
+type contractExitCode struct {
+	Code    int    `json:"code"`
+	Kind    string `json:"kind"`
+	Meaning string `json:"meaning"`
+}
+
+// contractCommand is one invocation whose output the contract covers
+type contractCommand struct {
+	Command string `json:"command"`
+	Output  string `json:"output"`
+}
+
+// cliContract is what `occtx contract -o json` prints
+type cliContract struct {
+	Version     int                `json:"version"`
+	ExitCodes   []contractExitCode `json:"exit_codes"`
+	Diagnostics []ui.Level         `json:"diagnostic_levels"`
+	Commands    []contractCommand  `json:"commands"`
+}
+
+// contract is the current CLI contract
+var contract = cliContract{
+	Version: ContractVersion,
+	ExitCodes: []contractExitCode{
+		{0, "ok", "Success"},
+		{exitError, kindError, "Any other failure"},
+		{exitUsage, kindUsage, "Invalid flags or arguments (CI and strict mode)"},
+		{exitNotFound, kindNotFound, "The context doesn't exist (CI and strict mode)"},
+		{exitRejected, kindRejected, "Refused by policy, approval, validation, a guard or a hook (CI and strict mode)"},
+		{exitReadOnly, kindReadOnly, "A change was attempted in read-only mode (CI and strict mode)"},
+		{exitWarnings, kindWarnings, "Warnings were reported in strict mode"},
+		{exitOffline, kindOffline, "The network was needed in offline mode (CI and strict mode)"},
+	},
+	Diagnostics: []ui.Level{ui.LevelError, ui.LevelWarning, ui.LevelHint, ui.LevelProgress, ui.LevelVerbose, ui.LevelDebug},
+	Commands: []contractCommand{
+		{"occtx -o json", "JSON array of contexts: name, format, current, shared, path, use_count; optional scope, mirror, source, freshness, last_used, model, providers"},
+		{"occtx -c", "The current context name and a newline, or 'No current context set'"},
+		{"occtx -s <name>", "The stored context file"},
+		{"occtx --ci <command>", "On failure, one JSON object on stderr: error, kind, exit_code"},
+		{"occtx contract", "The contract version and a newline"},
+		{"occtx render <name>", "The exact bytes a switch to the context would write to the active config"},
+		{"occtx __get <name|current> <path>", "The value at the key path as JSON; a string without quotes with --raw"},
+		{"occtx __select <name|current> <path>", "JSON array of the selected values: path, pointer, value"},
+	},
+}
+
+// contractCmd prints the CLI contract version
+var contractCmd = &cobra.Command{
+	Use:   "contract",
+	Short: "Print the version of the output contract scripts can rely on",
+	Long: `Contract prints the version of occtx's CLI contract: the exit codes, the
+-o json output, and the stdout/stderr split that wrappers and scripts can
+rely on. The version changes only when that output changes incompatibly;
+cosmetic changes to text output are not covered.
+
+Examples:
+  occtx contract            # e.g. 1
+  occtx contract -o json    # Exit codes, diagnostic levels and covered commands`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		output, err := planOutput(cmd)
+		if err != nil {
+			return err
+		}
+		if output == "json" {
+			return printJSON(contract)
+		}
+		fmt.Println(ContractVersion)
+		return nil
+	},
+}
+
+func init() {
+	contractCmd.Flags().StringP("output", "o", "text", "Output format (text, json)")
+	rootCmd.AddCommand(contractCmd)
+}
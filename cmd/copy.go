@@ -0,0 +1,46 @@
+package cmd
+
+import (
+	"github.com/hungthai1401/occtx/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+// copyCmd copies a context, typically to take over a shared read-only one locally
+var copyCmd = &cobra.Command{
+	Use:   "copy <name> [new-name]",
+	Short: "Copy a context to a new local context",
+	Long: `Copy duplicates a context, keeping its format and content. Shared
+read-only contexts (from shared_dirs or /etc/occtx/contexts) can be copied
+under the same name, after which the local copy takes precedence.
+
+Examples:
+  occtx copy baseline            # Take over a shared context locally
+  occtx copy work work-staging   # Duplicate a local context`,
+	Aliases:           []string{"cp"},
+	Args:              cobra.RangeArgs(1, 2),
+	ValidArgsFunction: completeContextArg,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		manager, err := newManager()
+		if err != nil {
+			return err
+		}
+
+		dst := ""
+		if len(args) == 2 {
+			dst = args[1]
+		}
+
+		copied, err := manager.CopyContext(args[0], dst)
+		if err != nil {
+			return err
+		}
+
+		printer := ui.NewColorPrinter()
+		printer.PrintSuccess("Context '%s' copied to '%s'\n", args[0], copied.Name)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(copyCmd)
+}
@@ -0,0 +1,134 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/hungthai1401/occtx/internal/context"
+	"github.com/hungthai1401/occtx/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+// defaultCmd shows or pins the context `occtx reset` returns to
+var defaultCmd = &cobra.Command{
+	Use:   "default [set <name>|unset]",
+	Short: "Show or set the default context",
+	Long: `Default pins a context as the safe baseline of this scope. 'occtx reset'
+switches back to it regardless of the current and previous contexts. The
+default context can't be deleted or archived while it is pinned.
+
+Examples:
+  occtx default             # Show the default context
+  occtx default set work    # Pin 'work' as the default
+  occtx default unset       # Remove the pin
+  occtx reset               # Switch to the default context`,
+	Args: subcommandArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		manager, err := newManager()
+		if err != nil {
+			return err
+		}
+
+		name, err := manager.GetDefaultContext()
+		if err != nil {
+			return err
+		}
+		if name == "" {
+			fmt.Println("No default context set")
+			return nil
+		}
+		fmt.Println(name)
+		return nil
+	},
+}
+
+var defaultSetCmd = &cobra.Command{
+	Use:               "set <name>",
+	Short:             "Pin a context as the default",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeContextArg,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		manager, err := newManager()
+		if err != nil {
+			return err
+		}
+
+		if err := manager.SetDefaultContext(args[0]); err != nil {
+			return err
+		}
+
+		name, _ := manager.GetDefaultContext()
+		printer := ui.NewColorPrinter()
+		printer.PrintSuccess("Default context set to '%s'\n", name)
+		return nil
+	},
+}
+
+var defaultUnsetCmd = &cobra.Command{
+	Use:   "unset",
+	Short: "Remove the default context",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		manager, err := newManager()
+		if err != nil {
+			return err
+		}
+
+		if err := manager.UnsetDefaultContext(); err != nil {
+			return err
+		}
+
+		printer := ui.NewColorPrinter()
+		printer.PrintSuccess("Default context removed\n")
+		return nil
+	},
+}
+
+// resetCmd switches back to the default context
+var resetCmd = &cobra.Command{
+	Use:   "reset",
+	Short: "Switch to the default context",
+	Long: `Reset switches to the context pinned with 'occtx default set', whatever
+the current and previous contexts are.
+
+With --if-set, reset does nothing when no default is pinned, which makes it
+safe to run from a shell logout hook:
+
+  # ~/.bash_logout or ~/.zlogout
+  occtx reset --if-set --quiet`,
+	Args: cobra.NoArgs,
+	RunE: runReset,
+}
+
+func init() {
+	defaultCmd.AddCommand(defaultSetCmd)
+	defaultCmd.AddCommand(defaultUnsetCmd)
+	rootCmd.AddCommand(defaultCmd)
+
+	resetCmd.Flags().Bool("if-set", false, "Do nothing when no default context is set")
+	addSwitchFlags(resetCmd.Flags())
+	rootCmd.AddCommand(resetCmd)
+}
+
+func runReset(cmd *cobra.Command, args []string) error {
+	manager, err := newSwitchManager(switchOptionsFromFlags(cmd))
+	if err != nil {
+		return err
+	}
+
+	name, err := manager.ResetToDefault()
+	if errors.Is(err, context.ErrNoDefault) {
+		if ifSet, _ := cmd.Flags().GetBool("if-set"); ifSet {
+			return nil
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	if !quiet {
+		printer := ui.NewColorPrinter()
+		printer.PrintSuccess("Switched to default context: %s\n", name)
+	}
+	return nil
+}
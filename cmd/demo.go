@@ -0,0 +1,257 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/hungthai1401/occtx/internal/config"
+	"github.com/hungthai1401/occtx/internal/context"
+	"github.com/hungthai1401/occtx/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+// demoDirPattern names demo sandboxes in the temp directory
+const demoDirPattern = "occtx-demo-*"
+
+// demoContexts are the sample contexts a sandbox starts with; the first is current
+var demoContexts = []struct {
+	name string
+	data string
+}{
+	{"personal", `{
+  "$schema": "https://opencode.ai/config.json",
+  "model": "anthropic/claude-sonnet-4-20250514",
+  "theme": "opencode",
+  "autoupdate": true
+}`},
+	{"work", `{
+  "$schema": "https://opencode.ai/config.json",
+  "model": "openai/gpt-4.1",
+  "theme": "github",
+  "autoupdate": false,
+  "provider": {
+    "openai": {
+      "options": {"apiKey": "{env:OPENAI_API_KEY}"}
+    }
+  }
+}`},
+	{"review", `{
+  "$schema": "https://opencode.ai/config.json",
+  "model": "google/gemini-2.5-pro",
+  "theme": "opencode",
+  "autoupdate": false
+}`},
+}
+
+// demoStep is one command of the walkthrough
+type demoStep struct {
+	about string
+	args  []string
+	stdin string // Piped into the command when set
+}
+
+var demoSteps = []demoStep{
+	{about: "List the contexts; the current one is marked"},
+	{about: "Switch to 'work'", args: []string{"work"}},
+	{about: "Print the current context", args: []string{"-c"}},
+	{about: "Compare 'personal' with 'work'", args: []string{"diff", "personal", "work"}},
+	{about: "Change 'work' by merging in a new theme (occtx -e work opens $EDITOR instead)", args: []string{"--import", "work", "--merge"}, stdin: `{"theme": "tokyonight"}`},
+	{about: "See who changed 'work', when, and what", args: []string{"log", "work"}},
+	{about: "Switch back to the previous context", args: []string{"-"}},
+}
+
+// demoCmd walks through occtx in a throwaway sandbox
+var demoCmd = &cobra.Command{
+	Use:   "demo",
+	Short: "Try occtx in a throwaway sandbox with sample contexts",
+	Long: `Demo creates a sandbox config directory in the temp directory with sample
+contexts (personal, work and review) and walks through listing, switching,
+diffing and changing them. Your own configuration is never touched: every
+command runs with the sandbox as its config directory. On a terminal, the
+walkthrough pauses after each step.
+
+The sandbox is kept afterwards, so you can try more commands in it with
+--config-dir. With --shell, a shell is started in the sandbox instead and
+the sandbox is removed when the shell exits. --clean removes the sandboxes
+earlier demos left behind.
+
+Examples:
+  occtx demo             # Walk through the basics
+  occtx demo --shell     # Then explore in a sandboxed shell
+  occtx demo --clean     # Remove old sandboxes`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if clean, _ := cmd.Flags().GetBool("clean"); clean {
+			return cleanDemoSandboxes()
+		}
+		shell, _ := cmd.Flags().GetBool("shell")
+		return runDemo(shell)
+	},
+}
+
+func init() {
+	demoCmd.Flags().Bool("shell", false, "After the walkthrough, start a shell in the sandbox and remove the sandbox when it exits")
+	demoCmd.Flags().Bool("clean", false, "Remove sandboxes left by earlier demos")
+	rootCmd.AddCommand(demoCmd)
+}
+
+func runDemo(shell bool) error {
+	dir, err := os.MkdirTemp("", demoDirPattern)
+	if err != nil {
+		return fmt.Errorf("failed to create the demo sandbox: %v", err)
+	}
+	if err := seedDemoSandbox(dir); err != nil {
+		os.RemoveAll(dir)
+		return err
+	}
+
+	printer := ui.NewColorPrinter()
+	printer.PrintSuccess("Created a demo sandbox in %s\n", dir)
+	fmt.Println("Your own configuration is not touched.")
+
+	executable, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	env := demoEnv(dir)
+	for i, step := range demoSteps {
+		fmt.Println()
+		printer.Info.Printf("# %d/%d %s\n", i+1, len(demoSteps), step.about)
+		fmt.Printf("$ %s\n", demoCommandLine(step))
+
+		run := exec.CommandContext(commandCtx, executable, step.args...)
+		run.Env = env
+		run.Stdout = os.Stdout
+		run.Stderr = os.Stderr
+		if step.stdin != "" {
+			run.Stdin = strings.NewReader(step.stdin)
+		}
+		if err := run.Run(); err != nil {
+			return fmt.Errorf("demo step '%s' failed: %w", demoCommandLine(step), runError(err))
+		}
+
+		if i < len(demoSteps)-1 {
+			ui.Prompt("Press Enter to continue")
+		}
+	}
+	fmt.Println()
+
+	if shell {
+		return runDemoShell(dir, env)
+	}
+
+	fmt.Printf("The sandbox is kept in %s\n", dir)
+	ui.Hintf("Try more commands with 'occtx --config-dir %s', or run 'occtx demo --shell'. Remove sandboxes with 'occtx demo --clean'", dir)
+	return nil
+}
+
+// seedDemoSandbox creates the sample contexts in dir and makes the first
+// current. The config dir is only pointed at the sandbox while seeding, so
+// this command's own bookkeeping stays out of it.
+func seedDemoSandbox(dir string) error {
+	previous, wasSet := os.LookupEnv(config.ConfigDirEnvVar)
+	defer func() {
+		if wasSet {
+			os.Setenv(config.ConfigDirEnvVar, previous)
+		} else {
+			os.Unsetenv(config.ConfigDirEnvVar)
+		}
+	}()
+	if err := os.Setenv(config.ConfigDirEnvVar, dir); err != nil {
+		return err
+	}
+	manager, err := context.NewManager(false)
+	if err != nil {
+		return err
+	}
+	manager.SetContext(commandCtx)
+	// Read-only mode protects the user's own config, not the throwaway sandbox
+	manager.SetReadOnly(false)
+
+	for _, sample := range demoContexts {
+		if err := manager.ImportContextWithSource(sample.name, []byte(sample.data), context.Source{Kind: context.SourceImport, From: "occtx demo"}); err != nil {
+			return fmt.Errorf("failed to create demo context '%s': %v", sample.name, err)
+		}
+	}
+	return manager.SwitchToContext(demoContexts[0].name)
+}
+
+// demoEnv is the environment of commands run in the sandbox: occtx settings
+// from the environment, such as a profile or read-only mode, are left out
+func demoEnv(dir string) []string {
+	var env []string
+	for _, entry := range os.Environ() {
+		if !strings.HasPrefix(entry, "OCCTX_") {
+			env = append(env, entry)
+		}
+	}
+	return append(env, config.ConfigDirEnvVar+"="+dir)
+}
+
+// demoCommandLine shows how a step is run, e.g. "echo '{...}' | occtx --import work"
+func demoCommandLine(step demoStep) string {
+	line := strings.Join(append([]string{"occtx"}, step.args...), " ")
+	if step.stdin != "" {
+		line = fmt.Sprintf("echo '%s' | %s", step.stdin, line)
+	}
+	return line
+}
+
+// runDemoShell starts the user's shell in the sandbox and removes the sandbox
+// when it exits
+func runDemoShell(dir string, env []string) error {
+	shell := os.Getenv("SHELL")
+	if runtime.GOOS == "windows" {
+		shell = os.Getenv("COMSPEC")
+	}
+	if shell == "" {
+		shell = "sh"
+	}
+
+	fmt.Printf("Starting %s in the sandbox; exit the shell to remove it\n", shell)
+	run := exec.CommandContext(commandCtx, shell)
+	run.Env = env
+	run.Stdin = os.Stdin
+	run.Stdout = os.Stdout
+	run.Stderr = os.Stderr
+	runErr := run.Run()
+
+	if err := os.RemoveAll(dir); err != nil {
+		return fmt.Errorf("failed to remove the demo sandbox %s: %v", dir, err)
+	}
+	fmt.Println("Removed the demo sandbox")
+
+	// The exit status of the last command in the shell isn't a demo failure
+	if _, ok := runErr.(*exec.ExitError); ok {
+		return nil
+	}
+	return runErr
+}
+
+// cleanDemoSandboxes removes the sandboxes earlier demos left in the temp directory
+func cleanDemoSandboxes() error {
+	dirs, err := filepath.Glob(filepath.Join(os.TempDir(), demoDirPattern))
+	if err != nil {
+		return err
+	}
+
+	for _, dir := range dirs {
+		if err := os.RemoveAll(dir); err != nil {
+			return fmt.Errorf("failed to remove %s: %v", dir, err)
+		}
+	}
+
+	switch len(dirs) {
+	case 0:
+		fmt.Println("No demo sandboxes to remove")
+	case 1:
+		fmt.Println("Removed 1 demo sandbox")
+	default:
+		fmt.Printf("Removed %d demo sandboxes\n", len(dirs))
+	}
+	return nil
+}
@@ -0,0 +1,115 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/hungthai1401/occtx/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+// describeCmd sets the one-line description of a context
+var describeCmd = &cobra.Command{
+	Use:   "describe <name> [description]",
+	Short: "Show or set what a context is for",
+	Long: `Describe sets a one-line description of a context. For more, write a
+README next to the context file: <name>.md in the settings directory, or in
+a shared directory for shared contexts. The description and the README are
+rendered by 'occtx -s <name> --meta', in the fzf preview and below the
+built-in picker.
+
+Examples:
+  occtx describe work "Company account; pinned to the approved models"
+  occtx describe work            # Show the description
+  occtx describe work --clear    # Remove it`,
+	Args:              cobra.MinimumNArgs(1),
+	ValidArgsFunction: completeContextArg,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		manager, err := newManager()
+		if err != nil {
+			return err
+		}
+
+		printer := ui.NewColorPrinter()
+		if clear, _ := cmd.Flags().GetBool("clear"); clear {
+			if len(args) > 1 {
+				return fmt.Errorf("--clear takes no description")
+			}
+			if err := manager.SetDescription(args[0], ""); err != nil {
+				return err
+			}
+			printer.PrintSuccess("Removed the description of '%s'\n", args[0])
+			return nil
+		}
+
+		if len(args) == 1 {
+			doc, err := manager.GetContextDoc(args[0])
+			if err != nil {
+				return err
+			}
+			if doc.Description == "" {
+				fmt.Println("No description set")
+				return nil
+			}
+			fmt.Println(doc.Description)
+			return nil
+		}
+
+		if err := manager.SetDescription(args[0], strings.Join(args[1:], " ")); err != nil {
+			return err
+		}
+		printer.PrintSuccess("Described '%s'\n", args[0])
+		return nil
+	},
+}
+
+// previewCmd is what fzf shows next to the highlighted context
+var previewCmd = &cobra.Command{
+	Use:    "preview <name>",
+	Short:  "Show a context's documentation and content for the fzf preview",
+	Hidden: true,
+	Args:   cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		manager, err := newManager()
+		if err != nil {
+			return err
+		}
+
+		ctx, err := manager.GetContext(args[0])
+		if err != nil {
+			return err
+		}
+		doc, err := manager.GetContextDoc(ctx.Name)
+		if err != nil {
+			return err
+		}
+		data, err := os.ReadFile(ctx.FilePath)
+		if err != nil {
+			return err
+		}
+
+		// fzf shows the colors of the preview even though it reads a pipe
+		color.NoColor = os.Getenv("NO_COLOR") != ""
+		if !doc.Empty() {
+			if doc.Description != "" {
+				fmt.Println(doc.Description)
+				fmt.Println()
+			}
+			if doc.Readme != "" {
+				fmt.Print(ui.RenderMarkdown(doc.Readme))
+				fmt.Println()
+			}
+			fmt.Println(strings.Repeat("─", 40))
+		}
+		fmt.Print(string(data))
+		return nil
+	},
+}
+
+func init() {
+	describeCmd.Flags().Bool("clear", false, "Remove the description")
+	rootCmd.AddCommand(describeCmd)
+	rootCmd.AddCommand(previewCmd)
+}
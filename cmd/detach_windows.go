@@ -0,0 +1,12 @@
+package cmd
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// detach makes cmd outlive occtx and the console it was started from
+func detach(cmd *exec.Cmd) {
+	const detachedProcess = 0x00000008 // DETACHED_PROCESS
+	cmd.SysProcAttr = &syscall.SysProcAttr{CreationFlags: detachedProcess | syscall.CREATE_NEW_PROCESS_GROUP}
+}
@@ -0,0 +1,176 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/fatih/color"
+	"github.com/hungthai1401/occtx/internal/context"
+	"github.com/hungthai1401/occtx/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+// diffCmd compares two contexts, or a local copy with the shared baseline
+var diffCmd = &cobra.Command{
+	Use:   "diff <name> [other]",
+	Short: "Compare two contexts, or a context with its shared baseline",
+	Long: `Diff lists the values that differ between two contexts by key path:
+'+' only in the second context, '-' only in the first, '~' changed.
+
+With --shared, the local context is compared with the shared (team) context
+of the same name, or of the name given as second argument, from shared_dirs.
+The report says whether the local copy has changes of its own and whether
+the shared context was updated since the copy was taken.
+
+Examples:
+  occtx diff work work-staging    # What staging changes relative to work
+  occtx diff baseline --shared    # Local copy vs the team's baseline
+  occtx update baseline --from-shared`,
+	Args:              cobra.RangeArgs(1, 2),
+	ValidArgsFunction: completeContextArgs,
+	RunE:              runDiff,
+}
+
+// updateCmd fast-forwards a local copy to its shared baseline
+var updateCmd = &cobra.Command{
+	Use:   "update <name> [shared-name] --from-shared",
+	Short: "Update a local copy from the shared baseline",
+	Long: `Update replaces a local copy of a shared context with the current shared
+version when the copy has no local changes. With --force, local changes are
+moved to the trash and replaced as well.
+
+Examples:
+  occtx update baseline --from-shared
+  occtx update baseline --from-shared --force`,
+	Args:              cobra.RangeArgs(1, 2),
+	ValidArgsFunction: completeContextArg,
+	RunE:              runUpdate,
+}
+
+func init() {
+	diffCmd.Flags().Bool("shared", false, "Compare with the shared context from shared_dirs")
+	updateCmd.Flags().Bool("from-shared", false, "Update from the shared context in shared_dirs")
+	updateCmd.Flags().Bool("force", false, "Replace local changes (the local version is moved to the trash)")
+
+	rootCmd.AddCommand(diffCmd)
+	rootCmd.AddCommand(updateCmd)
+}
+
+func runDiff(cmd *cobra.Command, args []string) error {
+	manager, err := newManager()
+	if err != nil {
+		return err
+	}
+
+	printer := ui.NewColorPrinter()
+	if shared, _ := cmd.Flags().GetBool("shared"); !shared {
+		if len(args) != 2 {
+			return fmt.Errorf("specify two contexts, or one with --shared")
+		}
+
+		changes, err := manager.DiffContexts(args[0], args[1])
+		if err != nil {
+			return err
+		}
+		if len(changes) == 0 {
+			fmt.Printf("'%s' and '%s' are identical\n", args[0], args[1])
+			return nil
+		}
+		printChanges(printer, changes)
+		return nil
+	}
+
+	comparison, err := manager.CompareWithShared(args[0], optionalArg(args[1:]))
+	if err != nil {
+		return err
+	}
+
+	if comparison.UpToDate() {
+		printer.PrintSuccess("'%s' matches %s\n", comparison.Name, comparison.SharedPath)
+		return nil
+	}
+
+	fmt.Printf("Comparing %s with local '%s'\n", comparison.SharedPath, comparison.Name)
+	printChanges(printer, comparison.Changes)
+
+	if comparison.SharedUpdated {
+		printer.PrintInfo("The shared context changed since your copy was taken\n")
+	}
+	if comparison.LocalModified {
+		printer.PrintWarning("Your copy has local changes\n")
+	} else {
+		printer.PrintInfo("No local changes; run 'occtx update %s --from-shared' to fast-forward\n", comparison.Name)
+	}
+	return nil
+}
+
+// printChanges lists changes one per line
+func printChanges(printer *ui.ColorPrinter, changes []context.ContextChange) {
+	printChangesTo(color.Output, printer, changes)
+}
+
+// printChangesTo lists changes one per line on w
+func printChangesTo(w io.Writer, printer *ui.ColorPrinter, changes []context.ContextChange) {
+	for _, change := range changes {
+		switch change.Kind {
+		case context.ChangeAdded:
+			printer.Success.Fprintf(w, "%s\n", formatChange(change))
+		case context.ChangeRemoved:
+			printer.Error.Fprintf(w, "%s\n", formatChange(change))
+		default:
+			printer.Warning.Fprintf(w, "%s\n", formatChange(change))
+		}
+	}
+}
+
+// formatChange renders a change as "+ path: value", "- path: value" or
+// "~ path: old -> new"
+func formatChange(change context.ContextChange) string {
+	switch change.Kind {
+	case context.ChangeAdded:
+		return fmt.Sprintf("+ %s: %s", change.Path, formatValue(change.Local))
+	case context.ChangeRemoved:
+		return fmt.Sprintf("- %s: %s", change.Path, formatValue(change.Baseline))
+	default:
+		return fmt.Sprintf("~ %s: %s -> %s", change.Path, formatValue(change.Baseline), formatValue(change.Local))
+	}
+}
+
+// formatValue renders a context value as compact JSON
+func formatValue(value interface{}) string {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(data)
+}
+
+func runUpdate(cmd *cobra.Command, args []string) error {
+	if fromShared, _ := cmd.Flags().GetBool("from-shared"); !fromShared {
+		return fmt.Errorf("specify where to update from: --from-shared")
+	}
+
+	manager, err := newManager()
+	if err != nil {
+		return err
+	}
+
+	force, _ := cmd.Flags().GetBool("force")
+	comparison, err := manager.UpdateFromShared(args[0], optionalArg(args[1:]), force)
+	if err != nil {
+		return err
+	}
+
+	printer := ui.NewColorPrinter()
+	if comparison.UpToDate() {
+		printer.PrintSuccess("'%s' is already up to date\n", comparison.Name)
+		return nil
+	}
+	printer.PrintSuccess("Updated '%s' from %s (%d change(s))\n", comparison.Name, comparison.SharedPath, len(comparison.Changes))
+
+	if current, _ := manager.GetCurrentContext(); current == comparison.Name {
+		ui.Hintf("'%s' is the current context; run 'occtx %s' to apply the update", comparison.Name, comparison.Name)
+	}
+	return nil
+}
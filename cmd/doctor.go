@@ -0,0 +1,96 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/hungthai1401/occtx/internal/schedule"
+	"github.com/hungthai1401/occtx/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+// doctorCmd reports on the occtx setup
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Check the occtx setup and backups",
+	Long: `Doctor reports where occtx keeps contexts, how many there are, when the
+last backup was taken and whether scheduled backups are installed. It also
+lists the files and directories whose mode differs from the one occtx
+gives them: the permissions config with the umask applied. Findings are
+advice; doctor only fails when occtx can't read its own files.
+
+Use 'occtx verify --all' to check the contexts' content.`,
+	Args: cobra.NoArgs,
+	RunE: runDoctor,
+}
+
+func init() {
+	rootCmd.AddCommand(doctorCmd)
+}
+
+func runDoctor(cmd *cobra.Command, args []string) error {
+	manager, err := newManager()
+	if err != nil {
+		return err
+	}
+
+	printer := ui.NewColorPrinter()
+	ok := func(format string, args ...interface{}) { printer.PrintSuccess("✓ "+format+"\n", args...) }
+	advise := func(format string, args ...interface{}) { printer.PrintWarning("! "+format+"\n", args...) }
+
+	paths := manager.GetPaths()
+	if paths.Fallback != "" {
+		advise("Contexts dir: %s (temporary: %s)", paths.GetContextsDir(inProject), paths.Fallback)
+	} else {
+		ok("Contexts dir: %s", paths.GetContextsDir(inProject))
+	}
+
+	contexts, err := manager.ListContexts()
+	if err != nil {
+		return err
+	}
+	if len(contexts) == 0 {
+		advise("Contexts: none yet (create one with 'occtx -n <name>')")
+	} else {
+		ok("Contexts: %d", len(contexts))
+	}
+
+	permissions, err := manager.CheckPermissions()
+	if err != nil {
+		return err
+	}
+	modes := fmt.Sprintf("files %04o, directories %04o, umask %04o", permissions.Files, permissions.Dirs, permissions.Umask)
+	if len(permissions.Deviations) == 0 {
+		ok("Permissions: %s", modes)
+	} else {
+		advise("Permissions: %d paths differ from %s (set permissions in the occtx config, or chmod them)", len(permissions.Deviations), modes)
+		for _, deviation := range permissions.Deviations {
+			fmt.Printf("    %s: %04o, expected %04o\n", deviation.Path, deviation.Mode, deviation.Want)
+		}
+	}
+
+	backups, err := manager.ListBackups()
+	if err != nil {
+		return err
+	}
+	if len(backups) == 0 {
+		advise("Last backup: none (run 'occtx backup create')")
+	} else {
+		ok("Last backup: %s (%s)", backups[0].Name, formatAge(backups[0].CreatedAt))
+	}
+
+	if !inProject {
+		status, err := schedule.Query(backupJobName())
+		switch {
+		case errors.Is(err, schedule.ErrUnsupported):
+			advise("Backup schedule: %v", err)
+		case err != nil:
+			return err
+		case status.Installed:
+			ok("Backup schedule: installed with %s (%s)", status.Scheduler, status.Location)
+		default:
+			advise("Backup schedule: not installed (run 'occtx backup install-schedule')")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,34 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/hungthai1401/occtx/internal/context"
+	"github.com/hungthai1401/occtx/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+// warnActiveDrift prints a one-line warning when the active config was edited
+// since the last switch, so unsaved changes aren't lost by the next switch.
+// In strict mode the drift fails the command, even with --quiet.
+func warnActiveDrift(cmd *cobra.Command) error {
+	manager, err := newManager()
+	if err != nil || (quiet && !manager.IsStrict()) || !manager.GetConfig().Warnings.DriftEnabled() {
+		return nil
+	}
+
+	drifted, err := manager.ActiveDrifted()
+	if err != nil || !drifted {
+		return nil
+	}
+
+	current, _ := manager.GetCurrentContext()
+	message := fmt.Sprintf("active config was modified since switching to '%s'", current)
+	if !quiet {
+		ui.Warnf("%s (save it with 'occtx -n <name>' or silence with --quiet)", message)
+	}
+	if manager.IsStrict() {
+		return &context.StrictWarningsError{Operation: cmd.CommandPath(), Warnings: []string{message}}
+	}
+	return nil
+}
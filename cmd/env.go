@@ -0,0 +1,322 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/hungthai1401/occtx/internal/config"
+	"github.com/hungthai1401/occtx/internal/context"
+	"github.com/hungthai1401/occtx/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+// envCmd manages the environment variables a context declares
+var envCmd = &cobra.Command{
+	Use:   "env [list [name]|set <name> KEY=VALUE...|unset <name> KEY...]",
+	Short: "Manage environment variables exported with a context",
+	Long: `Env declares environment variables (a proxy, ANTHROPIC_BASE_URL, ...) that
+belong with a context. The shell hook installed with 'occtx hook' exports them
+while the context is current and unsets them when it changes; 'occtx exec' and
+'occtx shell' pass them to their command.
+
+Examples:
+  occtx env                                        # Variables of the current context
+  occtx env list work
+  occtx env set work HTTPS_PROXY=http://proxy:3128 ANTHROPIC_BASE_URL=https://gw.example.com
+  occtx env unset work HTTPS_PROXY
+  eval "$(occtx hook zsh)"                         # In ~/.zshrc`,
+	Args: subcommandArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runEnvList(cmd, nil)
+	},
+}
+
+var envListCmd = &cobra.Command{
+	Use:               "list [name]",
+	Short:             "List the variables of a context (default: the current one)",
+	Args:              cobra.MaximumNArgs(1),
+	ValidArgsFunction: completeContextArg,
+	RunE:              runEnvList,
+}
+
+var envSetCmd = &cobra.Command{
+	Use:               "set <name> KEY=VALUE...",
+	Short:             "Declare variables for a context",
+	Args:              cobra.MinimumNArgs(2),
+	ValidArgsFunction: completeContextArg,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		manager, err := newManager()
+		if err != nil {
+			return err
+		}
+
+		vars := make(map[string]string, len(args)-1)
+		for _, arg := range args[1:] {
+			key, value, ok := strings.Cut(arg, "=")
+			if !ok {
+				return fmt.Errorf("expected KEY=VALUE, got '%s'", arg)
+			}
+			vars[key] = value
+		}
+
+		if err := manager.SetContextEnv(args[0], vars); err != nil {
+			return err
+		}
+		printer := ui.NewColorPrinter()
+		printer.PrintSuccess("Set %d variable(s) for '%s'\n", len(vars), args[0])
+		return nil
+	},
+}
+
+var envUnsetCmd = &cobra.Command{
+	Use:               "unset <name> KEY...",
+	Short:             "Remove variables from a context",
+	Args:              cobra.MinimumNArgs(2),
+	ValidArgsFunction: completeContextArg,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		manager, err := newManager()
+		if err != nil {
+			return err
+		}
+
+		if err := manager.UnsetContextEnv(args[0], args[1:]); err != nil {
+			return err
+		}
+		printer := ui.NewColorPrinter()
+		printer.PrintSuccess("Removed %d variable(s) from '%s'\n", len(args)-1, args[0])
+		return nil
+	},
+}
+
+// hookCmd prints the shell integration
+var hookCmd = &cobra.Command{
+	Use:   "hook <bash|zsh|fish> | hook install pre-commit",
+	Short: "Print the shell hook, or install a git pre-commit hook",
+	Long: `Hook prints a snippet that, evaluated in the shell's startup file, runs
+before every prompt and exports the variables of the current context,
+unsetting those of the previous one. Values set before the hook took over a
+variable are not restored.
+
+  # ~/.bashrc
+  eval "$(occtx hook bash)"
+  # ~/.zshrc
+  eval "$(occtx hook zsh)"
+  # ~/.config/fish/config.fish
+  occtx hook fish | source
+
+'occtx init' includes this hook along with completion, auto-switching and
+the prompt. 'occtx hook install pre-commit' installs a git hook instead; see its help.`,
+	Args:      cobra.ExactArgs(1),
+	ValidArgs: []string{"bash", "zsh", "fish"},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		executable, err := os.Executable()
+		if err != nil {
+			executable = "occtx"
+		}
+
+		snippet, err := envHookSnippet(args[0], executable)
+		if err != nil {
+			return err
+		}
+		fmt.Fprint(cmd.OutOrStdout(), snippet)
+		return nil
+	},
+}
+
+// envHookSnippet is the shell code running hook-env before every prompt.
+// hookArgs follow the shell name, unquoted.
+func envHookSnippet(shell, executable string, hookArgs ...string) (string, error) {
+	var snippet string
+	switch shell {
+	case "bash":
+		snippet = `_occtx_hook() {
+  local status=$?
+  eval "$(%[1]s)"
+  return $status
+}
+if [[ ";${PROMPT_COMMAND:-};" != *";_occtx_hook;"* ]]; then
+  PROMPT_COMMAND="_occtx_hook${PROMPT_COMMAND:+;$PROMPT_COMMAND}"
+fi
+`
+	case "zsh":
+		snippet = `_occtx_hook() {
+  eval "$(%[1]s)"
+}
+typeset -ag precmd_functions
+if (( ! ${precmd_functions[(I)_occtx_hook]} )); then
+  precmd_functions=(_occtx_hook $precmd_functions)
+fi
+`
+	case "fish":
+		snippet = `function _occtx_hook --on-event fish_prompt
+  %[1]s | source
+end
+`
+	default:
+		return "", fmt.Errorf("unsupported shell '%s' (expected bash, zsh or fish)", shell)
+	}
+
+	quote := posixQuote
+	if shell == "fish" {
+		quote = fishQuote
+	}
+	parts := []string{quote(executable), "hook-env", shell}
+	parts = append(parts, hookArgs...)
+	return fmt.Sprintf(snippet, strings.Join(parts, " ")), nil
+}
+
+// hookEnvCmd is run by the shell hook before every prompt
+var hookEnvCmd = &cobra.Command{
+	Use:    "hook-env <bash|zsh|fish>",
+	Short:  "Print the commands that apply the current context's variables",
+	Hidden: true,
+	Args:   cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		quote := posixQuote
+		export, unset := "export %s=%s\n", "unset %s\n"
+		switch args[0] {
+		case "bash", "zsh":
+		case "fish":
+			quote = fishQuote
+			export, unset = "set -gx %s %s\n", "set -e %s\n"
+		default:
+			return fmt.Errorf("unsupported shell '%s' (expected bash, zsh or fish)", args[0])
+		}
+
+		manager, err := newManager()
+		if err != nil {
+			return err
+		}
+
+		out := cmd.OutOrStdout()
+		if autoSwitch, _ := cmd.Flags().GetBool("auto-switch"); autoSwitch {
+			if handled := autoSwitchForDir(cmd, manager); handled != os.Getenv(context.AutoSwitchEnvVar) {
+				if handled == "" {
+					fmt.Fprintf(out, unset, context.AutoSwitchEnvVar)
+				} else {
+					fmt.Fprintf(out, export, context.AutoSwitchEnvVar, quote(handled))
+				}
+			}
+		}
+
+		change, err := manager.HookEnv(os.Getenv(context.AppliedEnvVar))
+		if err != nil {
+			return err
+		}
+
+		for _, key := range change.Unset {
+			fmt.Fprintf(out, unset, key)
+		}
+		keys := change.Keys()
+		for _, key := range keys {
+			fmt.Fprintf(out, export, key, quote(change.Set[key]))
+		}
+		if len(keys) > 0 {
+			fmt.Fprintf(out, export, context.AppliedEnvVar, quote(strings.Join(keys, ":")))
+		} else if os.Getenv(context.AppliedEnvVar) != "" {
+			fmt.Fprintf(out, unset, context.AppliedEnvVar)
+		}
+		return nil
+	},
+}
+
+// autoSwitchForDir switches to the context named by the directory context
+// file governing the working directory, once per file and name, and returns
+// the "path=name" to remember ("" outside such directories). Failures are
+// reported on stderr without failing the hook. Session terminals are left
+// alone.
+func autoSwitchForDir(cmd *cobra.Command, manager *context.Manager) string {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return ""
+	}
+	name, path, err := context.FindDirContext(cwd)
+	if err != nil || name == "" {
+		return ""
+	}
+
+	handled := path + "=" + name
+	if handled == os.Getenv(context.AutoSwitchEnvVar) || context.SessionContext() != "" {
+		return handled
+	}
+
+	// A qualified name, such as proj:demo, switches in its own scope
+	if useProject, plain, ok := splitScopedName(name); ok {
+		if manager, err = newManagerForScope(useProject); err != nil {
+			fmt.Fprintf(cmd.ErrOrStderr(), "occtx: can't switch to context '%s' from %s: %v\n", name, path, err)
+			return handled
+		}
+		name = plain
+	}
+
+	current, err := manager.GetCurrentContext()
+	if err == nil && current != name {
+		err = manager.SwitchToContext(name)
+		if err == nil {
+			current, _ = manager.GetCurrentContext()
+			fmt.Fprintf(cmd.ErrOrStderr(), "occtx: switched to context '%s' (%s)\n", current, path)
+		}
+	}
+	if err != nil {
+		fmt.Fprintf(cmd.ErrOrStderr(), "occtx: can't switch to context '%s' from %s: %v\n", name, path, err)
+	}
+	return handled
+}
+
+func init() {
+	hookEnvCmd.Flags().Bool("auto-switch", false, "Switch to the context named by a "+config.DirContextFileName+" file in the working directory or its parents")
+	envCmd.AddCommand(envListCmd)
+	envCmd.AddCommand(envSetCmd)
+	envCmd.AddCommand(envUnsetCmd)
+	rootCmd.AddCommand(envCmd)
+	rootCmd.AddCommand(hookCmd)
+	rootCmd.AddCommand(hookEnvCmd)
+}
+
+func runEnvList(cmd *cobra.Command, args []string) error {
+	manager, err := newManager()
+	if err != nil {
+		return err
+	}
+
+	name := optionalArg(args)
+	if name == "" {
+		if name, _, err = manager.EffectiveCurrentContext(); err != nil {
+			return err
+		}
+		if name == "" {
+			return fmt.Errorf("no current context")
+		}
+	}
+
+	env, err := manager.GetContextEnv(name)
+	if err != nil {
+		return err
+	}
+	if len(env) == 0 {
+		fmt.Fprintf(cmd.OutOrStdout(), "No variables set for '%s'\n", name)
+		return nil
+	}
+
+	keys := make([]string, 0, len(env))
+	for key := range env {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		fmt.Fprintf(cmd.OutOrStdout(), "%s=%s\n", key, env[key])
+	}
+	return nil
+}
+
+// posixQuote single-quotes s for bash and zsh
+func posixQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// fishQuote single-quotes s for fish, where backslash escapes ' and \
+func fishQuote(s string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(s) + "'"
+}
@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/hungthai1401/occtx/internal/context"
+	"github.com/hungthai1401/occtx/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+// exportCmd writes many contexts to a directory at once
+var exportCmd = &cobra.Command{
+	Use:   "export --out-dir <dir> [--tag KEY=VALUE...]",
+	Short: "Export the contexts with some tags to a directory",
+	Long: `Export writes every context carrying all of the given tags to a directory,
+one <name>.json file per context, e.g. into a repository others pull
+contexts from. Without --tag, every context is exported. Shared contexts
+are left out, since they are shared already.
+
+Secrets are redacted as with 'occtx --export --redact'; --no-redact writes
+the contexts as they are. --git-safe applies the git-safe profile of
+'occtx --export --git-safe' and writes <name>.jsonc files. Files already in
+the directory are replaced; files of contexts no longer selected are kept.
+
+To export one context to stdout, use 'occtx --export <name>'.
+
+Examples:
+  occtx tag set work team=platform
+  occtx export --tag team=platform --out-dir ./shared-contexts
+  occtx export --out-dir ./backup --no-redact`,
+	Args: cobra.NoArgs,
+	RunE: runExport,
+}
+
+func init() {
+	exportCmd.Flags().String("out-dir", "", "Directory to write the contexts to (required)")
+	exportCmd.Flags().StringArray("tag", nil, "Only export contexts tagged KEY=VALUE (repeatable; all must match)")
+	exportCmd.Flags().Bool("no-redact", false, "Don't redact secrets")
+	exportCmd.Flags().Bool("git-safe", false, "Export safe to commit: redacted, normalized, with a provenance header")
+	exportCmd.Flags().StringSlice("strip-keys", nil, "Remove values at dotted key paths (e.g. provider.*.options.baseURL)")
+	_ = exportCmd.MarkFlagRequired("out-dir")
+	rootCmd.AddCommand(exportCmd)
+}
+
+func runExport(cmd *cobra.Command, args []string) error {
+	dir, _ := cmd.Flags().GetString("out-dir")
+	var tags []context.TagSelector
+	exprs, _ := cmd.Flags().GetStringArray("tag")
+	for _, expr := range exprs {
+		tag, err := context.ParseTagSelector(expr)
+		if err != nil {
+			return usageErrorf("%v", err)
+		}
+		tags = append(tags, tag)
+	}
+
+	var opts context.ExportOptions
+	noRedact, _ := cmd.Flags().GetBool("no-redact")
+	opts.Redact = !noRedact
+	opts.GitSafe, _ = cmd.Flags().GetBool("git-safe")
+	if opts.GitSafe && noRedact {
+		return usageErrorf("--git-safe always redacts; drop --no-redact")
+	}
+	stripKeys, _ := cmd.Flags().GetStringSlice("strip-keys")
+	for _, expr := range stripKeys {
+		path, err := context.ParseKeyPath(expr)
+		if err != nil {
+			return err
+		}
+		opts.StripKeys = append(opts.StripKeys, path)
+	}
+
+	manager, err := newManager()
+	if err != nil {
+		return err
+	}
+	exported, err := manager.ExportTree(dir, tags, opts)
+	if err != nil {
+		return err
+	}
+	if len(exported) == 0 {
+		fmt.Println("No contexts to export")
+		return nil
+	}
+
+	for _, file := range exported {
+		fmt.Printf("  %s -> %s\n", file.Context, file.Path)
+	}
+	ui.NewColorPrinter().PrintSuccess("Exported %d context(s) to %s\n", len(exported), dir)
+	return nil
+}
@@ -0,0 +1,156 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/hungthai1401/occtx/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+// preCommitMarker identifies hooks written by occtx
+const preCommitMarker = "# occtx pre-commit hook"
+
+// gitHooks are the git hooks occtx can install
+var gitHooks = []string{"pre-commit"}
+
+// hookInstallCmd writes a git hook into the current repository
+var hookInstallCmd = &cobra.Command{
+	Use:   "install pre-commit",
+	Short: "Install a git pre-commit hook that validates project contexts",
+	Long: `Install writes a pre-commit hook into the current git repository that runs
+'occtx --in-project validate --all', so contexts with invalid JSON, schema
+problems or literal secrets can't be committed. The hook honors
+core.hooksPath. An existing hook that occtx didn't write is only replaced
+with --force; it is kept next to the new one with an .orig suffix.
+
+Examples:
+  occtx hook install pre-commit
+  occtx hook uninstall pre-commit`,
+	Args:      cobra.ExactArgs(1),
+	ValidArgs: gitHooks,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path, err := gitHookPath(args[0])
+		if err != nil {
+			return err
+		}
+
+		force, _ := cmd.Flags().GetBool("force")
+		existing, err := os.ReadFile(path)
+		switch {
+		case os.IsNotExist(err):
+		case err != nil:
+			return err
+		case !strings.Contains(string(existing), preCommitMarker):
+			if !force {
+				return fmt.Errorf("a %s hook already exists at %s (use --force to replace it; the old hook is kept as %s.orig)", args[0], path, filepath.Base(path))
+			}
+			if err := os.Rename(path, path+".orig"); err != nil {
+				return err
+			}
+		}
+
+		executable, err := os.Executable()
+		if err != nil {
+			executable = "occtx"
+		}
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(path, []byte(preCommitScript(executable)), 0755); err != nil {
+			return err
+		}
+
+		printer := ui.NewColorPrinter()
+		printer.PrintSuccess("Installed %s hook at %s\n", args[0], path)
+		return nil
+	},
+}
+
+// hookUninstallCmd removes a git hook written by occtx
+var hookUninstallCmd = &cobra.Command{
+	Use:       "uninstall pre-commit",
+	Short:     "Remove the git pre-commit hook installed by occtx",
+	Args:      cobra.ExactArgs(1),
+	ValidArgs: gitHooks,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path, err := gitHookPath(args[0])
+		if err != nil {
+			return err
+		}
+
+		existing, err := os.ReadFile(path)
+		if os.IsNotExist(err) {
+			return fmt.Errorf("no %s hook is installed", args[0])
+		}
+		if err != nil {
+			return err
+		}
+		if !strings.Contains(string(existing), preCommitMarker) {
+			return fmt.Errorf("the %s hook at %s was not installed by occtx; remove it by hand", args[0], path)
+		}
+		if err := os.Remove(path); err != nil {
+			return err
+		}
+
+		// Put back a hook that --force set aside
+		restored := ""
+		if _, err := os.Stat(path + ".orig"); err == nil {
+			if err := os.Rename(path+".orig", path); err != nil {
+				return err
+			}
+			restored = " (restored the previous hook)"
+		}
+
+		printer := ui.NewColorPrinter()
+		printer.PrintSuccess("Removed %s hook%s\n", args[0], restored)
+		return nil
+	},
+}
+
+func init() {
+	hookInstallCmd.Flags().Bool("force", false, "Replace an existing hook occtx didn't write")
+	hookCmd.AddCommand(hookInstallCmd)
+	hookCmd.AddCommand(hookUninstallCmd)
+}
+
+// gitHookPath returns where git looks for the named hook of the repository
+// containing the working directory
+func gitHookPath(name string) (string, error) {
+	if name != "pre-commit" {
+		return "", usageErrorf("unsupported git hook '%s' (supported: %s)", name, strings.Join(gitHooks, ", "))
+	}
+
+	out, err := exec.CommandContext(commandCtx, "git", "rev-parse", "--git-path", "hooks/"+name).Output()
+	if ctxErr := commandCtx.Err(); ctxErr != nil {
+		return "", fmt.Errorf("git rev-parse failed: %w", ctxErr)
+	}
+	if err != nil {
+		return "", fmt.Errorf("not inside a git repository (git rev-parse failed: %v)", err)
+	}
+
+	path := strings.TrimSpace(string(out))
+	if !filepath.IsAbs(path) {
+		cwd, err := os.Getwd()
+		if err != nil {
+			return "", err
+		}
+		path = filepath.Join(cwd, path)
+	}
+	return path, nil
+}
+
+// preCommitScript runs the validation with the occtx on PATH, falling back
+// to the binary that installed the hook
+func preCommitScript(executable string) string {
+	return fmt.Sprintf(`#!/bin/sh
+%s
+# Validates project-level contexts before each commit. Installed by
+# 'occtx hook install pre-commit'; remove with 'occtx hook uninstall pre-commit'.
+occtx=$(command -v occtx 2>/dev/null || echo %s)
+exec "$occtx" --in-project --quiet validate --all
+`, preCommitMarker, posixQuote(executable))
+}
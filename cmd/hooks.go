@@ -0,0 +1,147 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/hungthai1401/occtx/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+// hooksCmd shows and tries the hooks configured in the occtx config
+var hooksCmd = &cobra.Command{
+	Use:   "hooks [list|test]",
+	Short: "List the configured hooks or try one",
+	Long: `Hooks are commands run around switches, configured under "hooks" in the
+occtx config:
+
+  "hooks": [
+    {"name": "vpn", "on": "pre-switch", "run": ["vpn-check"], "timeout": "5s"},
+    {"name": "notify", "on": "post-switch", "run": ["notify-send", "occtx"],
+     "order": 10, "env": ["DISPLAY", "DBUS_*"], "on_failure": "warn"}
+  ]
+
+Hooks for an event run by order (lower first, then as listed), each with
+its timeout (10s by default). A hook only gets the variables its env list
+allows ("AWS_*" matches by prefix; PATH, HOME, USER, LANG and a few more
+by default) plus OCCTX_HOOK, OCCTX_HOOK_EVENT, OCCTX_CONTEXT,
+OCCTX_PREVIOUS_CONTEXT and OCCTX_ACTIVE_CONFIG. A failing pre-switch hook
+aborts the switch unless its on_failure is warn.
+
+Examples:
+  occtx hooks list                   # Hooks in the order they run
+  occtx hooks test vpn               # Run a hook without switching
+  occtx hooks test vpn --context work`,
+	Args: subcommandArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return cmd.Help()
+	},
+}
+
+var hooksListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List hooks in the order they run",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		output, err := planOutput(cmd)
+		if err != nil {
+			return err
+		}
+
+		manager, err := newManager()
+		if err != nil {
+			return err
+		}
+
+		hooks := manager.Hooks()
+		if output == "json" {
+			type hookJSON struct {
+				Name      string   `json:"name"`
+				On        string   `json:"on"`
+				Run       []string `json:"run"`
+				Order     int      `json:"order"`
+				Timeout   string   `json:"timeout"`
+				Env       []string `json:"env"`
+				OnFailure string   `json:"on_failure"`
+			}
+			entries := []hookJSON{}
+			for _, hook := range hooks {
+				onFailure := "abort"
+				if !hook.Aborts() {
+					onFailure = "warn"
+				}
+				entries = append(entries, hookJSON{
+					Name:      hook.Name,
+					On:        hook.On,
+					Run:       hook.Run,
+					Order:     hook.Order,
+					Timeout:   hook.EffectiveTimeout().String(),
+					Env:       hook.EffectiveEnv(),
+					OnFailure: onFailure,
+				})
+			}
+			return printJSON(entries)
+		}
+
+		if len(hooks) == 0 {
+			fmt.Println("No hooks configured")
+			ui.Hintf("Add hooks under \"hooks\" in %s; see 'occtx hooks --help'", manager.GetPaths().ConfigFile)
+			return nil
+		}
+
+		printer := ui.NewColorPrinter()
+		for _, hook := range hooks {
+			printer.Info.Printf("%-16s", hook.Name)
+			fmt.Printf(" %-12s order %-3d timeout %-6s", hook.On, hook.Order, hook.EffectiveTimeout())
+			if hook.Aborts() {
+				fmt.Print(" abort")
+			} else {
+				printer.Warning.Print(" warn ")
+			}
+			fmt.Printf("  %s\n", strings.Join(hook.Run, " "))
+		}
+		return nil
+	},
+}
+
+var hooksTestCmd = &cobra.Command{
+	Use:   "test <name>",
+	Short: "Run a hook without switching",
+	Long: `Test runs a hook the way a switch would, with its timeout and environment,
+and shows its output. Nothing is switched and the failure policy doesn't
+apply: a failing hook fails the test. OCCTX_CONTEXT is the context given
+with --context, or the current one.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		manager, err := newManager()
+		if err != nil {
+			return err
+		}
+
+		target, _ := cmd.Flags().GetString("context")
+		if target == "" {
+			target, _ = manager.GetCurrentContext()
+		}
+
+		out, err := manager.TestHook(args[0], target)
+		os.Stdout.Write(out)
+		if err != nil {
+			return err
+		}
+		ui.NewColorPrinter().PrintSuccess("Hook '%s' succeeded\n", args[0])
+		return nil
+	},
+}
+
+func init() {
+	hooksListCmd.Flags().StringP("output", "o", "text", "Output format (text, json)")
+	hooksTestCmd.Flags().String("context", "", "Context to pass as OCCTX_CONTEXT (default: the current context)")
+	_ = hooksTestCmd.RegisterFlagCompletionFunc("context", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return completeContextArg(cmd, nil, toComplete)
+	})
+
+	hooksCmd.AddCommand(hooksListCmd)
+	hooksCmd.AddCommand(hooksTestCmd)
+	rootCmd.AddCommand(hooksCmd)
+}
@@ -0,0 +1,223 @@
+package cmd
+
+import (
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/hungthai1401/occtx/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+// ideaToolGroup names the External Tools group, and its file in tools/
+const ideaToolGroup = "occtx"
+
+// ideaCmd groups the JetBrains IDE integration
+var ideaCmd = &cobra.Command{
+	Use:   "idea",
+	Short: "Set up JetBrains IDEs to switch contexts from Tools > External Tools",
+}
+
+// ideaInitCmd prints or installs the External Tools group
+var ideaInitCmd = &cobra.Command{
+	Use:   "init",
+	Short: "Print the JetBrains External Tools configuration for occtx",
+	Long: `Init prints an External Tools group for JetBrains IDEs (IntelliJ IDEA,
+GoLand, PyCharm, WebStorm, ...) with these tools, found under
+Tools > External Tools > occtx and in Find Action:
+
+  Switch Context     Asks for a context name and switches to it
+  Preview Switch     Asks for a context name and shows what would change
+  Current Context    Prints the current context (occtx current)
+  List Contexts      Lists contexts
+  Show Context       Asks for a context name and prints it
+
+Save the output as tools/` + ideaToolGroup + `.xml in the IDE's configuration directory,
+or use --install to write it into every JetBrains configuration directory
+found for this user. Restart the IDE to load it.
+
+--in-project, --profile and --config-dir given to init apply to the tools;
+they run in the project directory.
+
+Examples:
+  occtx idea init > ~/.config/JetBrains/GoLand2024.2/tools/occtx.xml
+  occtx idea init --install`,
+	Args: cobra.NoArgs,
+	RunE: runIdeaInit,
+}
+
+func init() {
+	ideaInitCmd.Flags().Bool("install", false, "Write the tools into every JetBrains configuration directory found")
+	ideaCmd.AddCommand(ideaInitCmd)
+	rootCmd.AddCommand(ideaCmd)
+}
+
+// ideaToolSet is the root element of a tools/<group>.xml file
+type ideaToolSet struct {
+	XMLName xml.Name   `xml:"toolSet"`
+	Name    string     `xml:"name,attr"`
+	Tools   []ideaTool `xml:"tool"`
+}
+
+// ideaTool is one external tool, with the attributes the IDE writes
+type ideaTool struct {
+	Name                string       `xml:"name,attr"`
+	Description         string       `xml:"description,attr"`
+	ShowInMainMenu      bool         `xml:"showInMainMenu,attr"`
+	ShowInEditor        bool         `xml:"showInEditor,attr"`
+	ShowInProject       bool         `xml:"showInProject,attr"`
+	ShowInSearchPopup   bool         `xml:"showInSearchPopup,attr"`
+	Disabled            bool         `xml:"disabled,attr"`
+	UseConsole          bool         `xml:"useConsole,attr"`
+	ShowConsoleOnStdOut bool         `xml:"showConsoleOnStdOut,attr"`
+	ShowConsoleOnStdErr bool         `xml:"showConsoleOnStdErr,attr"`
+	SynchronizeAfterRun bool         `xml:"synchronizeAfterRun,attr"`
+	Options             []ideaOption `xml:"exec>option"`
+}
+
+// ideaOption is a COMMAND, PARAMETERS or WORKING_DIRECTORY setting
+type ideaOption struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:"value,attr"`
+}
+
+func runIdeaInit(cmd *cobra.Command, args []string) error {
+	executable, err := os.Executable()
+	if err != nil {
+		executable = "occtx"
+	}
+
+	var scope []string
+	if inProject {
+		scope = append(scope, "--in-project")
+	}
+	if profile != "" {
+		scope = append(scope, "--profile", ideaQuote(profile))
+	}
+	if configDir != "" {
+		dir, err := filepath.Abs(configDir)
+		if err != nil {
+			return err
+		}
+		scope = append(scope, "--config-dir", ideaQuote(dir))
+	}
+
+	// $Prompt$ asks for a value when the tool runs
+	tool := func(name, description string, args ...string) ideaTool {
+		return ideaTool{
+			Name:                name,
+			Description:         description,
+			ShowInMainMenu:      true,
+			ShowInProject:       true,
+			ShowInSearchPopup:   true,
+			UseConsole:          true,
+			ShowConsoleOnStdErr: true,
+			SynchronizeAfterRun: true,
+			Options: []ideaOption{
+				{Name: "COMMAND", Value: executable},
+				{Name: "PARAMETERS", Value: strings.Join(append(append([]string{}, scope...), args...), " ")},
+				{Name: "WORKING_DIRECTORY", Value: "$ProjectFileDir$"},
+			},
+		}
+	}
+	set := ideaToolSet{
+		Name: ideaToolGroup,
+		Tools: []ideaTool{
+			tool("Switch Context", "Switch to an occtx context", "$Prompt$"),
+			tool("Preview Switch", "Show what switching to an occtx context would change", "--dry-run", "$Prompt$"),
+			tool("Current Context", "Print the current occtx context", "current"),
+			tool("List Contexts", "List occtx contexts"),
+			tool("Show Context", "Print an occtx context", "-s", "$Prompt$"),
+		},
+	}
+
+	data, err := xml.MarshalIndent(set, "", "  ")
+	if err != nil {
+		return err
+	}
+	data = append(data, '\n')
+
+	if install, _ := cmd.Flags().GetBool("install"); !install {
+		_, err := cmd.OutOrStdout().Write(data)
+		return err
+	}
+
+	dirs, err := jetbrainsConfigDirs()
+	if err != nil {
+		return err
+	}
+	if len(dirs) == 0 {
+		return fmt.Errorf("no JetBrains configuration directory found; save the output of 'occtx idea init' as tools/%s.xml in the IDE's configuration directory", ideaToolGroup)
+	}
+
+	printer := ui.NewColorPrinter()
+	for _, dir := range dirs {
+		path := filepath.Join(dir, "tools", ideaToolGroup+".xml")
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(path, data, 0644); err != nil {
+			return err
+		}
+		printer.PrintSuccess("Installed External Tools at %s\n", path)
+	}
+	ui.Hintf("Restart the IDE to load the External Tools")
+	return nil
+}
+
+// jetbrainsConfigDirs lists the per-product JetBrains configuration
+// directories of this user, e.g. ~/.config/JetBrains/GoLand2024.2
+func jetbrainsConfigDirs() ([]string, error) {
+	var root string
+	switch runtime.GOOS {
+	case "darwin":
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return nil, err
+		}
+		root = filepath.Join(home, "Library", "Application Support", "JetBrains")
+	case "windows":
+		root = filepath.Join(os.Getenv("APPDATA"), "JetBrains")
+	default:
+		configHome := os.Getenv("XDG_CONFIG_HOME")
+		if configHome == "" || !filepath.IsAbs(configHome) {
+			home, err := os.UserHomeDir()
+			if err != nil {
+				return nil, err
+			}
+			configHome = filepath.Join(home, ".config")
+		}
+		root = filepath.Join(configHome, "JetBrains")
+	}
+
+	entries, err := os.ReadDir(root)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var dirs []string
+	for _, entry := range entries {
+		// Product directories carry a version; skip shared ones like "consentOptions"
+		if entry.IsDir() && strings.ContainsAny(entry.Name(), "0123456789") {
+			dirs = append(dirs, filepath.Join(root, entry.Name()))
+		}
+	}
+	sort.Strings(dirs)
+	return dirs, nil
+}
+
+// ideaQuote quotes a parameter the way External Tools splits them: on
+// whitespace, with double quotes grouping
+func ideaQuote(s string) string {
+	if !strings.ContainsAny(s, " \t\"") {
+		return s
+	}
+	return `"` + strings.ReplaceAll(s, `"`, `\"`) + `"`
+}
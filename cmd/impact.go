@@ -0,0 +1,100 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/hungthai1401/occtx/internal/context"
+	"github.com/hungthai1401/occtx/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+// impactCmd lists what depends on a context before it is edited
+var impactCmd = &cobra.Command{
+	Use:   "impact <name>",
+	Short: "List the copies, override and active config that depend on a context",
+	Long: `Impact lists what depends on a context, so a shared base can be edited
+safely: local copies taken with 'occtx copy' (of local or shared contexts),
+its machine-local override (<name>.local.json) and, when it is the current
+context, the active config.
+
+With --diff, each copy shows what re-copying the context as it is now would
+change, and the active config shows what switching again would change.
+
+Examples:
+  occtx impact baseline
+  occtx impact baseline --diff
+  occtx impact baseline -o json`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeContextArg,
+	RunE:              runImpact,
+}
+
+func init() {
+	impactCmd.Flags().Bool("diff", false, "Show the changes each dependent would see")
+	impactCmd.Flags().StringP("output", "o", "text", "Output format (text, json)")
+	rootCmd.AddCommand(impactCmd)
+}
+
+func runImpact(cmd *cobra.Command, args []string) error {
+	output, err := planOutput(cmd)
+	if err != nil {
+		return err
+	}
+
+	manager, err := newManager()
+	if err != nil {
+		return err
+	}
+
+	dependents, err := manager.Impact(args[0])
+	if err != nil {
+		return err
+	}
+
+	if output == "json" {
+		if dependents == nil {
+			dependents = []context.Dependent{}
+		}
+		data, err := json.MarshalIndent(dependents, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%s\n", data)
+		return nil
+	}
+
+	if len(dependents) == 0 {
+		fmt.Printf("Nothing depends on '%s'\n", args[0])
+		return nil
+	}
+
+	showDiff, _ := cmd.Flags().GetBool("diff")
+	printer := ui.NewColorPrinter()
+	for _, dependent := range dependents {
+		switch dependent.Kind {
+		case context.DependentCopy:
+			fmt.Printf("copy      %s (%s)", dependent.Name, dependent.Path)
+		case context.DependentOverride:
+			fmt.Printf("override  %s", dependent.Path)
+		case context.DependentActive:
+			fmt.Printf("active    %s", dependent.Path)
+		}
+		if dependent.Kind != context.DependentOverride {
+			if len(dependent.Changes) == 0 {
+				fmt.Print(", up to date")
+			} else {
+				fmt.Printf(", %d change(s)", len(dependent.Changes))
+			}
+		}
+		fmt.Println()
+
+		if showDiff {
+			for _, change := range dependent.Changes {
+				fmt.Print("    ")
+				printChanges(printer, []context.ContextChange{change})
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,212 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/hungthai1401/occtx/internal/config"
+	"github.com/hungthai1401/occtx/internal/context"
+	"github.com/spf13/cobra"
+)
+
+// initCmd prints the complete shell integration
+var initCmd = &cobra.Command{
+	Use:   "init <bash|zsh|fish|tmux>",
+	Short: "Print the shell integration: completion, env hook, auto-switch and prompt",
+	Long: `Init prints everything occtx offers the shell as one snippet, so setup is a
+single line in the shell's startup file:
+
+  # ~/.bashrc
+  eval "$(occtx init bash)"
+  # ~/.zshrc (after compinit)
+  eval "$(occtx init zsh)"
+  # ~/.config/fish/config.fish
+  occtx init fish | source
+
+The snippet contains:
+  completion    The same script 'occtx completion <shell>' prints
+  env hook      Exports the current context's variables (see 'occtx hook')
+  auto-switch   Before each prompt, switches to the context named in a
+                ` + config.DirContextFileName + ` file in the working directory or a parent, once
+                per file; leaving the directory doesn't switch back
+  prompt        Defines occtx_prompt_info, printing "(name) " for the context
+                in effect, and prepends it to the prompt
+
+Each part can be left out with its --no-* flag, e.g. --no-prompt when a
+prompt framework such as starship draws the prompt; it can call
+occtx_prompt_info or read 'occtx path --current' instead. --aliases adds
+ocx (occtx), ocxi (occtx -i) and ocxp (occtx -, the previous context).
+
+'occtx init tmux' prints tmux.conf lines showing the current context in the
+status line instead; see 'occtx tmux-status'.
+
+--in-project, --profile and --config-dir given to init apply to everything
+the snippet runs.`,
+	Args:      cobra.ExactArgs(1),
+	ValidArgs: []string{"bash", "zsh", "fish", "tmux"},
+	RunE:      runInit,
+}
+
+func init() {
+	initCmd.Flags().Bool("no-completion", false, "Leave out shell completion")
+	initCmd.Flags().Bool("no-env", false, "Leave out the hook exporting context variables (implies --no-auto-switch)")
+	initCmd.Flags().Bool("no-auto-switch", false, "Leave out switching by "+config.DirContextFileName+" files")
+	initCmd.Flags().Bool("no-prompt", false, "Don't add the context to the prompt (occtx_prompt_info is still defined)")
+	initCmd.Flags().Bool("aliases", false, "Define the ocx, ocxi and ocxp aliases")
+	rootCmd.AddCommand(initCmd)
+}
+
+func runInit(cmd *cobra.Command, args []string) error {
+	shell := args[0]
+	quote := posixQuote
+	switch shell {
+	case "bash", "zsh", "tmux":
+	case "fish":
+		quote = fishQuote
+	default:
+		return fmt.Errorf("unsupported shell '%s' (expected bash, zsh, fish or tmux)", shell)
+	}
+
+	manager, err := newManager()
+	if err != nil {
+		return err
+	}
+
+	executable, err := os.Executable()
+	if err != nil {
+		executable = "occtx"
+	}
+
+	// Everything the snippet runs uses the scope init was run in
+	var scope []string
+	if inProject {
+		scope = append(scope, "--in-project")
+	}
+	if profile != "" {
+		scope = append(scope, "--profile", quote(profile))
+	}
+	if configDir != "" {
+		dir, err := filepath.Abs(configDir)
+		if err != nil {
+			return err
+		}
+		scope = append(scope, "--config-dir", quote(dir))
+	}
+	command := quote(executable)
+	for _, arg := range scope {
+		command += " " + arg
+	}
+
+	if shell == "tmux" {
+		fmt.Fprint(cmd.OutOrStdout(), tmuxSnippet(command))
+		return nil
+	}
+
+	// Project markers are looked up relative to the working directory at
+	// each prompt, like project contexts
+	marker := manager.GetPaths().GetCurrentMarkerPath(false)
+	if inProject {
+		marker = filepath.Join(config.ProjectConfigDir, config.SettingsSubDir, config.CurrentMarkerFileName)
+	}
+
+	var out bytes.Buffer
+	fmt.Fprintf(&out, "# occtx shell integration (occtx init %s)\n", shell)
+
+	if noCompletion, _ := cmd.Flags().GetBool("no-completion"); !noCompletion {
+		switch shell {
+		case "bash":
+			err = rootCmd.GenBashCompletionV2(&out, true)
+		case "zsh":
+			err = rootCmd.GenZshCompletion(&out)
+		case "fish":
+			err = rootCmd.GenFishCompletion(&out, true)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	if noEnv, _ := cmd.Flags().GetBool("no-env"); !noEnv {
+		hookArgs := scope
+		if noAutoSwitch, _ := cmd.Flags().GetBool("no-auto-switch"); !noAutoSwitch {
+			hookArgs = append(append([]string{}, scope...), "--auto-switch")
+		}
+		snippet, err := envHookSnippet(shell, executable, hookArgs...)
+		if err != nil {
+			return err
+		}
+		out.WriteString(snippet)
+	}
+
+	noPrompt, _ := cmd.Flags().GetBool("no-prompt")
+	out.WriteString(promptSnippet(shell, quote(marker), !noPrompt))
+
+	if aliases, _ := cmd.Flags().GetBool("aliases"); aliases {
+		for _, alias := range [][2]string{{"ocx", ""}, {"ocxi", " -i"}, {"ocxp", " -"}} {
+			if shell == "fish" {
+				fmt.Fprintf(&out, "alias %s %s\n", alias[0], fishQuote(command+alias[1]))
+			} else {
+				fmt.Fprintf(&out, "alias %s=%s\n", alias[0], posixQuote(command+alias[1]))
+			}
+		}
+	}
+
+	_, err = cmd.OutOrStdout().Write(out.Bytes())
+	return err
+}
+
+// promptSnippet defines occtx_prompt_info, which prints the session context
+// or, failing that, the name in the quoted marker file, and with install
+// prepends it to the prompt
+func promptSnippet(shell, marker string, install bool) string {
+	var snippet string
+	switch shell {
+	case "bash", "zsh":
+		snippet = fmt.Sprintf(`occtx_prompt_info() {
+  local name="${%[1]s:-}"
+  if [[ -z $name && -r %[2]s ]]; then
+    IFS= read -r name < %[2]s
+  fi
+  if [[ -n $name ]]; then
+    printf '(%%s) ' "$name"
+  fi
+}
+`, context.SessionContextEnvVar, marker)
+		if install && shell == "bash" {
+			snippet += `if [[ $PS1 != *'$(occtx_prompt_info)'* ]]; then
+  PS1='$(occtx_prompt_info)'"$PS1"
+fi
+`
+		} else if install {
+			snippet += `setopt prompt_subst
+if [[ $PROMPT != *'$(occtx_prompt_info)'* ]]; then
+  PROMPT='$(occtx_prompt_info)'"$PROMPT"
+fi
+`
+		}
+	case "fish":
+		snippet = fmt.Sprintf(`function occtx_prompt_info
+  set -l name $%[1]s
+  if test -z "$name"; and test -r %[2]s
+    read name < %[2]s
+  end
+  if test -n "$name"
+    printf '(%%s) ' $name
+  end
+end
+`, context.SessionContextEnvVar, marker)
+		if install {
+			snippet += `if functions -q fish_prompt; and not functions -q _occtx_original_fish_prompt
+  functions -c fish_prompt _occtx_original_fish_prompt
+  function fish_prompt
+    occtx_prompt_info
+    _occtx_original_fish_prompt
+  end
+end
+`
+		}
+	}
+	return snippet
+}
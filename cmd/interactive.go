@@ -1,11 +1,17 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
 
 	"github.com/hungthai1401/occtx/internal/context"
 	"github.com/hungthai1401/occtx/internal/ui"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 )
 
 // interactiveCmd represents the interactive command for context selection
@@ -16,29 +22,147 @@ var interactiveCmd = &cobra.Command{
 or a built-in fuzzy finder. This provides a more user-friendly way to browse 
 and select contexts when you have many available.
 
+Picker and fzf options can also be set under "interactive" in the occtx
+config file; flags take precedence.
+
+With --menu, the picker first offers everyday actions: switch context, unset
+the current context, stash the active config, restore a stash or restore a
+backup. Targets are chosen with the same picker.
+
 Examples:
   occtx interactive           # Interactive selection
-  occtx -i                    # Flag form (same functionality)`,
+  occtx -i                    # Flag form (same functionality)
+  occtx -i --picker builtin   # Never use fzf
+  occtx -i --preview          # Show context docs and content next to the list
+  occtx -i --menu             # Choose what to do first`,
 	Aliases: []string{"i"},
 	RunE: func(cmd *cobra.Command, args []string) error {
-		return runInteractiveSelection()
+		return runInteractiveSelection(cmd)
 	},
 }
 
 func init() {
+	addPickerFlags(interactiveCmd.Flags())
+	interactiveCmd.Flags().Bool("menu", false, "Choose an action first: switch, unset, stash, restore a stash or a backup")
+	addSwitchFlags(interactiveCmd.Flags())
 	rootCmd.AddCommand(interactiveCmd)
 }
 
+// addPickerFlags registers the flags controlling the interactive picker
+func addPickerFlags(flags *pflag.FlagSet) {
+	flags.String("picker", "", "Picker to use: auto, fzf or builtin (default from config, else auto)")
+	flags.String("fzf-height", "", "fzf window height (e.g. 40%, 20)")
+	flags.String("fzf-layout", "", "fzf layout: default, reverse or reverse-list")
+	flags.Bool("preview", false, "Show the highlighted context's README and content in fzf")
+	flags.StringArray("fzf-bind", nil, "Extra fzf key binding (repeatable, e.g. ctrl-j:down)")
+	flags.StringArray("fzf-arg", nil, "Extra argument passed to fzf verbatim (repeatable)")
+}
+
+// pickerOptions merges the occtx config with any picker flags given on cmd
+func pickerOptions(cmd *cobra.Command, configured ui.PickerOptions) ui.PickerOptions {
+	options := configured
+	flags := cmd.Flags()
+
+	if flags.Changed("picker") {
+		options.Picker, _ = flags.GetString("picker")
+	}
+	if flags.Changed("fzf-height") {
+		options.Fzf.Height, _ = flags.GetString("fzf-height")
+	}
+	if flags.Changed("fzf-layout") {
+		options.Fzf.Layout, _ = flags.GetString("fzf-layout")
+	}
+	if flags.Changed("preview") {
+		options.Fzf.Preview, _ = flags.GetBool("preview")
+	}
+	if bind, _ := flags.GetStringArray("fzf-bind"); len(bind) > 0 {
+		options.Fzf.Bind = append(options.Fzf.Bind, bind...)
+	}
+	if args, _ := flags.GetStringArray("fzf-arg"); len(args) > 0 {
+		options.Fzf.Args = append(options.Fzf.Args, args...)
+	}
+
+	options.PreviewCommand = previewCommand()
+	options.SearchCommand = searchCommand()
+	return options
+}
+
+// previewCommand builds the shell command fzf runs to preview a context:
+// its description and README, then its content
+func previewCommand() string {
+	return occtxCommand("--quiet", "preview", "{-1}")
+}
+
+// searchCommand is the command fzf reloads the list with when searching
+// context contents; fzf appends the query
+func searchCommand() string {
+	return occtxCommand("--quiet", "search", "--names")
+}
+
+// occtxCommand is a shell command running this occtx in the current scope
+func occtxCommand(args ...string) string {
+	executable, err := os.Executable()
+	if err != nil {
+		executable = os.Args[0]
+	}
+
+	parts := []string{shellQuote(executable)}
+	if inProject {
+		parts = append(parts, "--in-project")
+	}
+	if profile != "" {
+		parts = append(parts, "--profile", shellQuote(profile))
+	}
+	parts = append(parts, args...)
+	return strings.Join(parts, " ")
+}
+
+// shellQuote quotes s for the shell fzf runs commands with
+func shellQuote(s string) string {
+	if runtime.GOOS == "windows" {
+		return `"` + s + `"`
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
 // runInteractiveSelection is shared between the flag and command forms
-func runInteractiveSelection() error {
-	manager, err := context.NewManager(inProject)
+func runInteractiveSelection(cmd *cobra.Command) error {
+	if !ui.PromptsEnabled() {
+		return usageErrorf("interactive selection is disabled in CI mode; name the context instead")
+	}
+
+	manager, err := newSwitchManager(switchOptionsFromFlags(cmd))
 	if err != nil {
 		return err
 	}
 
-	selector := ui.NewInteractiveSelector(manager)
+	if err := manager.CheckWritable("switch context"); err != nil {
+		return err
+	}
 
+	interactiveConfig := manager.GetConfig().Interactive
+	options := pickerOptions(cmd, ui.PickerOptions{
+		Picker: interactiveConfig.Picker,
+		Fzf:    interactiveConfig.Fzf,
+	})
+	if interactiveConfig.LiveRefreshEnabled() {
+		options.RefreshInterval = ui.DefaultRefreshInterval
+	}
+	selector := ui.NewInteractiveSelectorWithOptions(manager, options)
+
+	if menu, _ := cmd.Flags().GetBool("menu"); menu {
+		return runInteractiveMenu(cmd, manager, selector)
+	}
+	return switchInteractively(cmd, manager, selector)
+}
+
+// switchInteractively lets the user pick a context and switches to it
+func switchInteractively(cmd *cobra.Command, manager *context.Manager, selector *ui.InteractiveSelector) error {
 	contextName, err := selector.SelectContext()
+	if errors.Is(err, ui.ErrAborted) {
+		fmt.Fprintln(cmd.ErrOrStderr(), "Aborted")
+		return nil
+	}
 	if err != nil {
 		return fmt.Errorf("interactive selection failed: %v", err)
 	}
@@ -54,3 +178,99 @@ func runInteractiveSelection() error {
 
 	return nil
 }
+
+// menuAction is one entry of the interactive action menu
+type menuAction struct {
+	label string
+	run   func(cmd *cobra.Command, manager *context.Manager, selector *ui.InteractiveSelector) error
+}
+
+// menuActions are offered by 'occtx -i --menu', most common first
+var menuActions = []menuAction{
+	{"Switch context", switchInteractively},
+	{"Unset the current context", func(cmd *cobra.Command, manager *context.Manager, selector *ui.InteractiveSelector) error {
+		return unsetCurrentContext()
+	}},
+	{"Stash the active config", func(cmd *cobra.Command, manager *context.Manager, selector *ui.InteractiveSelector) error {
+		return stashSave("")
+	}},
+	{"Restore a stash", restoreStashInteractively},
+	{"Restore a backup", restoreBackupInteractively},
+}
+
+// runInteractiveMenu asks what to do, then does it
+func runInteractiveMenu(cmd *cobra.Command, manager *context.Manager, selector *ui.InteractiveSelector) error {
+	labels := make([]string, len(menuActions))
+	for i, action := range menuActions {
+		labels[i] = action.label
+	}
+
+	choice, err := chooseFrom(cmd, selector, "Action", labels)
+	if err != nil || choice < 0 {
+		return err
+	}
+	return menuActions[choice].run(cmd, manager, selector)
+}
+
+// chooseFrom asks the user to pick one of items; -1 means they aborted
+func chooseFrom(cmd *cobra.Command, selector *ui.InteractiveSelector, label string, items []string) (int, error) {
+	choice, err := selector.Choose(label, items)
+	if errors.Is(err, ui.ErrAborted) {
+		fmt.Fprintln(cmd.ErrOrStderr(), "Aborted")
+		return -1, nil
+	}
+	if err != nil {
+		return -1, fmt.Errorf("interactive selection failed: %v", err)
+	}
+	return choice, nil
+}
+
+// restoreStashInteractively lets the user pick a stash, then applies and drops it
+func restoreStashInteractively(cmd *cobra.Command, manager *context.Manager, selector *ui.InteractiveSelector) error {
+	entries, err := manager.ListStashes()
+	if err != nil {
+		return err
+	}
+	if len(entries) == 0 {
+		fmt.Println("No stashes found")
+		return nil
+	}
+
+	labels := make([]string, len(entries))
+	for i, entry := range entries {
+		labels[i] = fmt.Sprintf("stash@{%d}: %s (%s)", i, entry.Name, entry.CreatedAt.Format("2006-01-02 15:04:05"))
+	}
+	choice, err := chooseFrom(cmd, selector, "Restore stash", labels)
+	if err != nil || choice < 0 {
+		return err
+	}
+
+	entry, err := manager.PopStash(strconv.Itoa(choice))
+	if err != nil {
+		return err
+	}
+	ui.NewColorPrinter().PrintSuccess("Restored and dropped stash '%s'\n", entry.Name)
+	return nil
+}
+
+// restoreBackupInteractively lets the user pick a backup and restores it
+func restoreBackupInteractively(cmd *cobra.Command, manager *context.Manager, selector *ui.InteractiveSelector) error {
+	backups, err := manager.ListBackups()
+	if err != nil {
+		return err
+	}
+	if len(backups) == 0 {
+		fmt.Println("No backups")
+		return nil
+	}
+
+	labels := make([]string, len(backups))
+	for i, backup := range backups {
+		labels[i] = fmt.Sprintf("%s  %s  %s", backup.CreatedAt.Local().Format("2006-01-02 15:04:05"), formatBytes(backup.Size), backup.Name)
+	}
+	choice, err := chooseFrom(cmd, selector, "Restore backup", labels)
+	if err != nil || choice < 0 {
+		return err
+	}
+	return restoreBackup(manager, backups[choice].Name)
+}
@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"github.com/hungthai1401/occtx/internal/context"
+	"github.com/hungthai1401/occtx/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+// lsCmd lists contexts, like occtx without arguments
+var lsCmd = &cobra.Command{
+	Use:     "ls",
+	Aliases: []string{"list"},
+	Short:   "List contexts",
+	Long: `Ls lists contexts, like occtx without arguments, with the same filters.
+
+--all-scopes lists global, project and shared contexts in one view, grouped
+by scope. Names are shown qualified, as global:work or project:demo; any
+command accepts them in place of a name and runs in that scope, e.g.
+'occtx switch proj:demo' instead of 'occtx --in-project switch demo'.
+
+Contexts are numbered; 'occtx 3' switches to the third context of the last
+listing, until the next 'occtx ls' numbers them anew. Before the first
+listing, numbers count the most recently used contexts.
+
+Examples:
+  occtx ls
+  occtx 2                 # Switch to the second context listed
+  occtx ls --all-scopes
+  occtx ls --all-scopes -o json`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runList(cmd, true)
+	},
+}
+
+func init() {
+	addListFlags(lsCmd.Flags())
+	lsCmd.Flags().StringP("output", "o", "text", "Output format (text, json)")
+	_ = lsCmd.RegisterFlagCompletionFunc("output", completeValues("text", "json"))
+	_ = lsCmd.RegisterFlagCompletionFunc("sort", completeValues(string(context.SortName), string(context.SortUsed), string(context.SortFrequency)))
+	rootCmd.AddCommand(lsCmd)
+}
+
+// recordListing remembers the order of a numbered listing for 'occtx <n>'.
+// A listing that can't be recorded is still shown.
+func recordListing(contexts []*context.Context) {
+	manager, err := newManager()
+	if err != nil {
+		return
+	}
+	names := make([]string, len(contexts))
+	for i, ctx := range contexts {
+		names[i] = ctx.Name
+	}
+	if err := manager.RecordListing(names); err != nil {
+		ui.Warnf("can't remember the listing order for 'occtx <n>': %v", err)
+	}
+}
+
+// resolvePosition turns a position such as "3" into the name of the context
+// numbered so by the last 'occtx ls'; names are returned unchanged
+func resolvePosition(arg string) (string, error) {
+	if arg == "" || arg[0] < '0' || arg[0] > '9' {
+		return arg, nil
+	}
+	manager, err := newManager()
+	if err != nil {
+		return "", err
+	}
+	return manager.ResolvePosition(arg)
+}
@@ -0,0 +1,115 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hungthai1401/occtx/internal/context"
+	"github.com/hungthai1401/occtx/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+// logCmd shows the changelog of a context
+var logCmd = &cobra.Command{
+	Use:   "log <name>",
+	Short: "Show the changelog of a context",
+	Long: `Log shows who changed a context, when, and what: creation, edits with -e,
+merged imports, updates from the shared baseline, renames, approvals and
+notes added with 'occtx note add'. Changes name the key paths the way diff
+marks them ('+' added, '-' removed, '~' changed); values are left out so
+secrets stay out of the log.
+
+The changelog lives in the context metadata, so it travels with the
+context outside git. Only changes made through occtx are logged.
+
+Examples:
+  occtx log work              # Oldest entry first
+  occtx log work -o json      # Entries as JSON: at, by, kind, summary`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeContextArg,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		output, err := planOutput(cmd)
+		if err != nil {
+			return err
+		}
+
+		manager, err := newManager()
+		if err != nil {
+			return err
+		}
+
+		entries, err := manager.ContextLog(args[0])
+		if err != nil {
+			return err
+		}
+		if output == "json" {
+			if entries == nil {
+				entries = []context.LogEntry{}
+			}
+			return printJSON(entries)
+		}
+		if len(entries) == 0 {
+			fmt.Printf("No changes logged for '%s'\n", args[0])
+			return nil
+		}
+
+		printer := ui.NewColorPrinter()
+		for _, entry := range entries {
+			by := entry.By
+			if by == "" {
+				by = "unknown"
+			}
+			printer.Info.Printf("%s  %s  ", entry.At.Local().Format("2006-01-02 15:04:05"), by)
+			if entry.Kind == context.LogNote {
+				printer.Warning.Printf("note")
+			} else {
+				fmt.Print(entry.Kind)
+			}
+			fmt.Printf("  %s\n", entry.Summary)
+		}
+		return nil
+	},
+}
+
+// noteCmd adds free-form notes to the changelog of a context
+var noteCmd = &cobra.Command{
+	Use:   "note [add]",
+	Short: "Add a note to the changelog of a context",
+	Long: `Note appends a free-form message to the changelog of a context, e.g. why
+it was changed or who to ask about it. Notes are shown by 'occtx log'.
+
+Examples:
+  occtx note add work "Pinned the model for the Q3 release"
+  occtx log work`,
+	Args: subcommandArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return cmd.Help()
+	},
+}
+
+var noteAddCmd = &cobra.Command{
+	Use:               "add <name> <message>",
+	Short:             "Add a note to a context",
+	Args:              cobra.MinimumNArgs(2),
+	ValidArgsFunction: completeContextArg,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		manager, err := newManager()
+		if err != nil {
+			return err
+		}
+
+		if err := manager.AddNote(args[0], strings.Join(args[1:], " ")); err != nil {
+			return err
+		}
+		ui.NewColorPrinter().PrintSuccess("Added a note to '%s'\n", args[0])
+		return nil
+	},
+}
+
+func init() {
+	logCmd.Flags().StringP("output", "o", "text", "Output format (text, json)")
+
+	noteCmd.AddCommand(noteAddCmd)
+	rootCmd.AddCommand(logCmd)
+	rootCmd.AddCommand(noteCmd)
+}
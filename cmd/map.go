@@ -0,0 +1,227 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/hungthai1401/occtx/internal/config"
+	"github.com/hungthai1401/occtx/internal/context"
+	"github.com/hungthai1401/occtx/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+// mapCmd manages the directory map of a repository
+var mapCmd = &cobra.Command{
+	Use:   "map [list|add|remove]",
+	Short: "Map directories of a repository, such as monorepo packages, to contexts",
+	Long: `Map manages ` + config.DirMapFileName + `, which maps directories of a repository to
+the context to use below them. The longest matching prefix wins, so a
+package can override the repository-wide entry ("."). 'occtx auto' and the
+shell integration switch by it; a ` + config.DirContextFileName + ` file in a nearer
+directory takes precedence.
+
+The nearest map file above the working directory is used. 'map add'
+creates one at the root of the git repository (or here, outside one).
+Paths are relative to the working directory; contexts may be qualified,
+e.g. proj:api.
+
+Examples:
+  occtx map add . work
+  occtx map add packages/api proj:api
+  occtx map list
+  occtx map remove packages/api`,
+	Args: cobra.NoArgs,
+	RunE: runMapList,
+}
+
+var mapListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the directory mappings, marking the one in effect here",
+	Args:  cobra.NoArgs,
+	RunE:  runMapList,
+}
+
+var mapAddCmd = &cobra.Command{
+	Use:   "add <path> <context>",
+	Short: "Map a directory to a context",
+	Args:  cobra.ExactArgs(2),
+	ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 1 {
+			return contextNames(toComplete, false), cobra.ShellCompDirectiveNoFileComp
+		}
+		if len(args) == 0 {
+			return nil, cobra.ShellCompDirectiveFilterDirs
+		}
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	},
+	RunE: runMapAdd,
+}
+
+var mapRemoveCmd = &cobra.Command{
+	Use:   "remove <path>",
+	Short: "Remove the mapping of a directory",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runMapRemove,
+}
+
+func init() {
+	mapCmd.AddCommand(mapListCmd)
+	mapCmd.AddCommand(mapAddCmd)
+	mapCmd.AddCommand(mapRemoveCmd)
+	rootCmd.AddCommand(mapCmd)
+}
+
+func runMapList(cmd *cobra.Command, args []string) error {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+	mapPath, err := context.FindDirMap(cwd)
+	if err != nil {
+		return err
+	}
+	if mapPath == "" {
+		fmt.Println("No directory map found")
+		return nil
+	}
+	dirMap, err := context.LoadDirMap(mapPath)
+	if err != nil {
+		return err
+	}
+
+	rel, err := filepath.Rel(filepath.Dir(mapPath), cwd)
+	if err != nil {
+		return err
+	}
+	active, _, _ := dirMap.Match(rel)
+
+	prefixes := make([]string, 0, len(dirMap))
+	for prefix := range dirMap {
+		prefixes = append(prefixes, prefix)
+	}
+	sort.Strings(prefixes)
+
+	printer := ui.NewColorPrinter()
+	fmt.Printf("%s:\n", mapPath)
+	for _, prefix := range prefixes {
+		if prefix == active {
+			printer.PrintCurrent("* %s -> %s\n", prefix, dirMap[prefix])
+		} else {
+			fmt.Printf("  %s -> %s\n", prefix, dirMap[prefix])
+		}
+	}
+	return nil
+}
+
+func runMapAdd(cmd *cobra.Command, args []string) error {
+	name := args[1]
+	useProject, plain, qualified := splitScopedName(name)
+	if !qualified {
+		useProject = inProject
+	}
+	manager, err := newManagerForScope(useProject)
+	if err != nil {
+		return err
+	}
+	if _, err := manager.GetContext(plain); err != nil {
+		var notFound *context.NotFoundError
+		if !errors.As(err, &notFound) {
+			return err
+		}
+		// It may be created later, e.g. by a teammate
+		ui.Warnf("%v", err)
+	}
+
+	mapPath, err := mapFileForWrite()
+	if err != nil {
+		return err
+	}
+	prefix, err := mapPrefix(mapPath, args[0])
+	if err != nil {
+		return err
+	}
+	dirMap, err := context.LoadDirMap(mapPath)
+	if err != nil {
+		return err
+	}
+	dirMap[prefix] = name
+	if err := dirMap.Save(mapPath); err != nil {
+		return err
+	}
+	ui.NewColorPrinter().PrintSuccess("Mapped %s to context '%s' in %s\n", prefix, name, mapPath)
+	return nil
+}
+
+func runMapRemove(cmd *cobra.Command, args []string) error {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+	mapPath, err := context.FindDirMap(cwd)
+	if err != nil {
+		return err
+	}
+	if mapPath == "" {
+		return fmt.Errorf("no directory map found")
+	}
+	prefix, err := mapPrefix(mapPath, args[0])
+	if err != nil {
+		return err
+	}
+	dirMap, err := context.LoadDirMap(mapPath)
+	if err != nil {
+		return err
+	}
+	if _, ok := dirMap[prefix]; !ok {
+		return fmt.Errorf("%s is not mapped in %s", prefix, mapPath)
+	}
+	delete(dirMap, prefix)
+	if err := dirMap.Save(mapPath); err != nil {
+		return err
+	}
+	ui.NewColorPrinter().PrintSuccess("Removed the mapping of %s from %s\n", prefix, mapPath)
+	return nil
+}
+
+// mapFileForWrite returns the nearest map file, or where to create one: the
+// root of the enclosing git repository, else the working directory
+func mapFileForWrite() (string, error) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return "", err
+	}
+	if mapPath, err := context.FindDirMap(cwd); err != nil || mapPath != "" {
+		return mapPath, err
+	}
+
+	for dir := cwd; ; dir = filepath.Dir(dir) {
+		if _, err := os.Stat(filepath.Join(dir, ".git")); err == nil {
+			return filepath.Join(dir, config.DirMapFileName), nil
+		}
+		if filepath.Dir(dir) == dir {
+			return filepath.Join(cwd, config.DirMapFileName), nil
+		}
+	}
+}
+
+// mapPrefix turns a path relative to the working directory into a prefix
+// relative to the map file's directory
+func mapPrefix(mapPath, dir string) (string, error) {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+	rel, err := filepath.Rel(filepath.Dir(mapPath), abs)
+	if err != nil {
+		return "", err
+	}
+	prefix := context.CleanMapPrefix(rel)
+	if prefix == ".." || strings.HasPrefix(prefix, "../") {
+		return "", fmt.Errorf("%s is outside %s", dir, filepath.Dir(mapPath))
+	}
+	return prefix, nil
+}
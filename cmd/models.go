@@ -0,0 +1,151 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/hungthai1401/occtx/internal/context"
+	"github.com/hungthai1401/occtx/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+// modelsCmd manages the cached provider model catalog
+var modelsCmd = &cobra.Command{
+	Use:   "models [update|check]",
+	Short: "Validate model names against a cached provider catalog",
+	Long: `Models keeps a local catalog of the models each provider offers (from
+models.dev unless models.url is set) and checks the model fields of contexts
+against it, catching typos such as "anthropic/claude-4-sonet" before a switch.
+
+The catalog is only downloaded by 'occtx models update', at most once an hour
+unless forced. Set models.validate to warn or reject in the occtx config to
+check contexts on every switch.
+
+Examples:
+  occtx models                # Show the cached catalog
+  occtx models update         # Download the catalog
+  occtx models check          # Check every context
+  occtx models check work     # Check one context`,
+	Args: subcommandArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		manager, err := newManager()
+		if err != nil {
+			return err
+		}
+
+		catalog, err := manager.LoadModelCatalog()
+		if err != nil {
+			return err
+		}
+		if catalog == nil {
+			fmt.Println("No model catalog cached (run 'occtx models update')")
+			return nil
+		}
+
+		models := 0
+		for _, ids := range catalog.Providers {
+			models += len(ids)
+		}
+		fmt.Printf("%d providers, %d models from %s, updated %s\n",
+			len(catalog.Providers), models, catalog.Source, formatAge(catalog.FetchedAt))
+		if catalog.Stale() {
+			ui.Warnf("the catalog is over 30 days old; run 'occtx models update'")
+		}
+		return nil
+	},
+}
+
+var modelsUpdateCmd = &cobra.Command{
+	Use:   "update",
+	Short: "Download the provider model catalog",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		manager, err := newManager()
+		if err != nil {
+			return err
+		}
+
+		force, _ := cmd.Flags().GetBool("force")
+		progress := ui.NewProgress("Downloading model catalog")
+		progress.Start()
+		catalog, err := manager.UpdateModelCatalog(force)
+		progress.Stop()
+		if err != nil {
+			return err
+		}
+
+		printer := ui.NewColorPrinter()
+		printer.PrintSuccess("Model catalog updated: %d providers\n", len(catalog.Providers))
+		return nil
+	},
+}
+
+var modelsCheckCmd = &cobra.Command{
+	Use:               "check [name...]",
+	Short:             "Check contexts' model names against the catalog",
+	ValidArgsFunction: completeContextArgs,
+	RunE:              runModelsCheck,
+}
+
+func init() {
+	modelsUpdateCmd.Flags().Bool("force", false, "Download even if the catalog was updated within the last hour")
+
+	modelsCmd.AddCommand(modelsUpdateCmd)
+	modelsCmd.AddCommand(modelsCheckCmd)
+	rootCmd.AddCommand(modelsCmd)
+}
+
+func runModelsCheck(cmd *cobra.Command, args []string) error {
+	manager, err := newManager()
+	if err != nil {
+		return err
+	}
+
+	catalog, err := manager.LoadModelCatalog()
+	if err != nil {
+		return err
+	}
+	if catalog == nil {
+		return fmt.Errorf("no model catalog cached; run 'occtx models update' first")
+	}
+
+	names := args
+	if len(names) == 0 {
+		contexts, err := manager.ListContexts()
+		if err != nil {
+			return err
+		}
+		for _, ctx := range contexts {
+			names = append(names, ctx.Name)
+		}
+	}
+
+	printer := ui.NewColorPrinter()
+	failed := 0
+	for _, name := range names {
+		findings, err := manager.CheckContextModels(name)
+		if err != nil {
+			return err
+		}
+		if len(findings) == 0 {
+			printer.PrintSuccess("✓ %s\n", name)
+			continue
+		}
+		failed++
+		printer.PrintError("✗ %s\n", name)
+		for _, finding := range findings {
+			fmt.Printf("    %s\n", finding)
+		}
+	}
+
+	stale := catalog.Stale()
+	if stale {
+		ui.Warnf("the catalog is over 30 days old; run 'occtx models update'")
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d context(s) reference unknown models", failed)
+	}
+	if stale && manager.IsStrict() {
+		return &context.StrictWarningsError{Operation: "models check", Warnings: []string{"the model catalog is over 30 days old"}}
+	}
+	return nil
+}
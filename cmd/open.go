@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+
+	"github.com/spf13/cobra"
+)
+
+// openCmd hands the contexts directory or a context file to a GUI opener or $EDITOR
+var openCmd = &cobra.Command{
+	Use:   "open [name]",
+	Short: "Open the contexts directory or a context file",
+	Long: `Open shows the contexts directory, or one context file, with the
+platform's opener (open on macOS, xdg-open on Linux, start on Windows),
+so several contexts can be edited at once in a GUI editor or file manager.
+With --editor the path is given to $EDITOR instead.
+
+Changes made this way happen outside occtx; run "occtx verify --all --accept"
+afterwards to record them.
+
+Examples:
+  occtx open                 # Browse the contexts directory
+  occtx open work            # Open work.json with its default application
+  occtx open --editor        # Open the directory in $EDITOR
+  occtx open --in-project`,
+	Args:              cobra.MaximumNArgs(1),
+	ValidArgsFunction: completeContextArg,
+	RunE:              runOpen,
+}
+
+func init() {
+	openCmd.Flags().Bool("editor", false, "Open with $EDITOR instead of the platform opener")
+	rootCmd.AddCommand(openCmd)
+}
+
+func runOpen(cmd *cobra.Command, args []string) error {
+	manager, err := newManager()
+	if err != nil {
+		return err
+	}
+
+	var target string
+	if len(args) == 1 {
+		ctx, err := manager.GetContext(args[0])
+		if err != nil {
+			return err
+		}
+		target = ctx.FilePath
+	} else {
+		target = manager.GetPaths().GetContextsDir(inProject)
+		if _, err := os.Stat(target); os.IsNotExist(err) {
+			return fmt.Errorf("contexts directory %s does not exist yet; create a context first", target)
+		}
+	}
+
+	var opener *exec.Cmd
+	if useEditor, _ := cmd.Flags().GetBool("editor"); useEditor {
+		// Terminal editors need the terminal
+		opener = exec.CommandContext(commandCtx, editorFromEnv(), target)
+		opener.Stdin = os.Stdin
+		opener.Stdout = os.Stdout
+	} else {
+		name, openerArgs := platformOpener()
+		if _, err := exec.LookPath(name); err != nil {
+			return fmt.Errorf("%s not found; use --editor to open %s with $EDITOR", name, target)
+		}
+		opener = exec.CommandContext(commandCtx, name, append(openerArgs, target)...)
+	}
+	opener.Stderr = os.Stderr
+
+	if err := opener.Run(); err != nil {
+		return fmt.Errorf("failed to open %s: %w", target, runError(err))
+	}
+	return nil
+}
+
+// platformOpener returns the command that opens a file or directory with its
+// default application, and the arguments that precede the path
+func platformOpener() (string, []string) {
+	switch runtime.GOOS {
+	case "darwin":
+		return "open", nil
+	case "windows":
+		// start is a cmd builtin; its first quoted argument is the window title
+		return "cmd", []string{"/c", "start", ""}
+	default:
+		return "xdg-open", nil
+	}
+}
@@ -0,0 +1,115 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+)
+
+// whichCmd prints the file behind a context
+var whichCmd = &cobra.Command{
+	Use:   "which <name>",
+	Short: "Print the absolute path of a context file",
+	Long: `Which prints the absolute path of the file a context is stored in,
+including its extension, so scripts and editors can open it directly.
+
+Examples:
+  occtx which work                  # ~/.config/opencode/settings/work.json
+  occtx which work --in-project     # A project-level context
+  occtx which work --local          # Its machine-local override file
+  $EDITOR "$(occtx which work)"`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeContextArg,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		manager, err := newManager()
+		if err != nil {
+			return err
+		}
+
+		ctx, err := manager.GetContext(args[0])
+		if err != nil {
+			return err
+		}
+
+		path := ctx.FilePath
+		if local, _ := cmd.Flags().GetBool("local"); local {
+			// Printed even when missing, so it can be created
+			path, _ = manager.LocalOverridePath(ctx.Name)
+		}
+		path, err = filepath.Abs(path)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(cmd.OutOrStdout(), path)
+		return nil
+	},
+}
+
+// pathCmd prints the directories and files occtx resolves for the current scope
+var pathCmd = &cobra.Command{
+	Use:   "path",
+	Short: "Print the resolved occtx directories and files",
+	Long: `Path prints where occtx keeps things for the current scope, honoring
+--in-project, --profile, --config-dir and XDG_CONFIG_HOME. With one of
+--contexts, --active, --state or --current it prints just that path.
+
+The --current file holds the current context name followed by a newline and
+is removed while no context is current, so prompts and scripts can read it
+without running occtx.
+
+Examples:
+  occtx path                    # Everything, one per line
+  occtx path --contexts         # The directory holding context files
+  occtx path --active           # The opencode.json occtx switches
+  occtx path --state --in-project
+  cat "$(occtx path --current)"`,
+	Args: cobra.NoArgs,
+	RunE: runPath,
+}
+
+func init() {
+	whichCmd.Flags().Bool("local", false, "Print the context's machine-local override file (<name>.local.json) instead")
+
+	pathCmd.Flags().Bool("contexts", false, "Print only the contexts directory")
+	pathCmd.Flags().Bool("active", false, "Print only the active opencode config file")
+	pathCmd.Flags().Bool("state", false, "Print only the occtx state file")
+	pathCmd.Flags().Bool("current", false, "Print only the plain-text current context marker file")
+	pathCmd.MarkFlagsMutuallyExclusive("contexts", "active", "state", "current")
+
+	rootCmd.AddCommand(whichCmd)
+	rootCmd.AddCommand(pathCmd)
+}
+
+func runPath(cmd *cobra.Command, args []string) error {
+	manager, err := newManager()
+	if err != nil {
+		return err
+	}
+	paths := manager.GetPaths()
+
+	entries := []struct {
+		flag string
+		path string
+	}{
+		{"contexts", paths.GetContextsDir(inProject)},
+		{"active", paths.GetActiveConfigPath(inProject)},
+		{"state", paths.GetStateFilePath(inProject)},
+		{"current", paths.GetCurrentMarkerPath(inProject)},
+	}
+
+	for _, entry := range entries {
+		if only, _ := cmd.Flags().GetBool(entry.flag); only {
+			fmt.Fprintln(cmd.OutOrStdout(), entry.path)
+			return nil
+		}
+	}
+
+	w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+	for _, entry := range entries {
+		fmt.Fprintf(w, "%s\t%s\n", entry.flag, entry.path)
+	}
+	fmt.Fprintf(w, "config\t%s\n", paths.ConfigFile)
+	return w.Flush()
+}
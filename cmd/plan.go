@@ -0,0 +1,161 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/hungthai1401/occtx/internal/context"
+	"github.com/hungthai1401/occtx/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+// dryRun reports whether cmd was asked to plan instead of act
+func dryRun(cmd *cobra.Command) bool {
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	return dryRun
+}
+
+// planOutput returns the validated -o format for a plan
+func planOutput(cmd *cobra.Command) (string, error) {
+	output, _ := cmd.Flags().GetString("output")
+	if output != "text" && output != "json" {
+		return "", fmt.Errorf("invalid output format '%s'. Supported formats: text, json", output)
+	}
+	return output, nil
+}
+
+// planSwitch prints what switching to name would change
+func planSwitch(cmd *cobra.Command, name string, opts switchOptions) error {
+	output, err := planOutput(cmd)
+	if err != nil {
+		return err
+	}
+	if opts.saveAs != "" {
+		return fmt.Errorf("--save-as can't be combined with --dry-run")
+	}
+	if name == "-" {
+		return fmt.Errorf("--dry-run needs a context name, not '-'")
+	}
+
+	manager, err := newSwitchManager(opts)
+	if err != nil {
+		return err
+	}
+	plan, err := manager.PlanSwitch(name)
+	if err != nil {
+		return err
+	}
+	return printPlan(cmd.OutOrStdout(), plan, output)
+}
+
+// planSwitchBoth prints what switching both scopes to name would change
+func planSwitchBoth(cmd *cobra.Command, name string, opts switchOptions) error {
+	output, err := planOutput(cmd)
+	if err != nil {
+		return err
+	}
+
+	// One plan covering both scopes' files
+	combined := &context.Plan{Operation: "switch", Context: name}
+	for _, useProject := range []bool{false, true} {
+		manager, err := newSwitchManagerForScope(useProject, opts)
+		if err != nil {
+			return err
+		}
+		plan, err := manager.PlanSwitch(name)
+		if err != nil {
+			return fmt.Errorf("%s scope: %v", manager.ScopeName(), err)
+		}
+		combined.Context = plan.Context
+		combined.Files = append(combined.Files, plan.Files...)
+		combined.Hooks = append(combined.Hooks, plan.Hooks...)
+		combined.Warnings = append(combined.Warnings, plan.Warnings...)
+	}
+	return printPlan(cmd.OutOrStdout(), combined, output)
+}
+
+// planDelete prints what deleting name would change
+func planDelete(cmd *cobra.Command, name, switchTo string) error {
+	output, err := planOutput(cmd)
+	if err != nil {
+		return err
+	}
+
+	manager, err := newManager()
+	if err != nil {
+		return err
+	}
+	plan, err := manager.PlanDelete(name, switchTo)
+	if err != nil {
+		return err
+	}
+	return printPlan(cmd.OutOrStdout(), plan, output)
+}
+
+// planImport prints what importing stdin as name would change
+func planImport(cmd *cobra.Command, manager *context.Manager, name string, data []byte, opts context.ImportOptions) error {
+	output, err := planOutput(cmd)
+	if err != nil {
+		return err
+	}
+
+	plan, err := manager.PlanImport(name, data, opts)
+	if err != nil {
+		if plan != nil {
+			printWarnings(plan.Warnings)
+		}
+		return err
+	}
+	return printPlan(cmd.OutOrStdout(), plan, output)
+}
+
+// printPlan writes a plan as JSON or as a readable summary with its warnings
+func printPlan(w io.Writer, plan *context.Plan, output string) error {
+	if plan.Files == nil {
+		plan.Files = []context.PlannedFile{}
+	}
+	if output == "json" {
+		data, err := json.MarshalIndent(plan, "", "  ")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintf(w, "%s\n", data)
+		return err
+	}
+
+	printWarnings(plan.Warnings)
+
+	printer := ui.NewColorPrinter()
+	fmt.Fprintf(w, "Plan: %s '%s'\n", plan.Operation, plan.Context)
+	if len(plan.Files) == 0 {
+		fmt.Fprintln(w, "No files would change")
+	}
+	for _, file := range plan.Files {
+		switch file.Action {
+		case context.PlanCreate:
+			printer.Success.Fprintf(w, "+ create %s\n", file.Path)
+		case context.PlanDelete:
+			printer.Error.Fprintf(w, "- delete %s\n", file.Path)
+		default:
+			printer.Warning.Fprintf(w, "~ modify %s\n", file.Path)
+		}
+		for _, change := range file.Changes {
+			fmt.Fprintf(w, "    %s\n", formatChange(change))
+		}
+	}
+	for _, hook := range plan.Hooks {
+		printer.Info.Fprintf(w, "! run %s hook '%s'", hook.On, hook.Name)
+		fmt.Fprintf(w, " (timeout %s): %s\n", hook.Timeout, strings.Join(hook.Run, " "))
+	}
+	fmt.Fprintln(w, "Dry run: nothing was written")
+	return nil
+}
+
+// printWarnings prints plan warnings the way commands print warnings
+func printWarnings(warnings []string) {
+	for _, warning := range warnings {
+		ui.Warnf("%s", warning)
+	}
+}
@@ -0,0 +1,115 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/hungthai1401/occtx/internal/context"
+	"github.com/spf13/cobra"
+)
+
+// currentContextArg names the current context in plumbing commands
+const currentContextArg = "current"
+
+// getCmd prints one value of a context for plugins and hooks
+var getCmd = &cobra.Command{
+	Use:   "__get <name|current> <path>",
+	Short: "Print the value at a key path of a context as JSON",
+	Long: `__get prints the value at a dotted key path or JSON pointer of a context
+as JSON, so plugins and hooks don't have to parse context files themselves.
+'current' names the current context. With '*' in the path, the first match is
+printed; use __select for all of them. A path without a value fails.
+
+Go programs can use the same path engine from pkg/keypath.
+
+Examples:
+  occtx __get current provider.anthropic.api
+  occtx __get work /agent/default/model --raw`,
+	Hidden:            true,
+	Args:              cobra.ExactArgs(2),
+	ValidArgsFunction: completeContextArg,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name, data, path, err := loadPlumbingArgs(args)
+		if err != nil {
+			return err
+		}
+		value, ok := path.Get(data)
+		if !ok {
+			return fmt.Errorf("no value at %s in context '%s'", path, name)
+		}
+		if raw, _ := cmd.Flags().GetBool("raw"); raw {
+			if s, ok := value.(string); ok {
+				fmt.Println(s)
+				return nil
+			}
+		}
+		return printJSON(value)
+	},
+}
+
+// selectCmd prints every value a key path selects
+var selectCmd = &cobra.Command{
+	Use:   "__select <name|current> <path>",
+	Short: "Print every value a key path selects in a context as JSON",
+	Long: `__select prints a JSON array with every value a dotted key path or JSON
+pointer selects in a context, each with the concrete path and pointer to it.
+'*' matches every key of an object and every element of an array. Nothing
+selected prints an empty array.
+
+Example:
+  occtx __select current 'provider.*.options.baseURL'`,
+	Hidden:            true,
+	Args:              cobra.ExactArgs(2),
+	ValidArgsFunction: completeContextArg,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		_, data, path, err := loadPlumbingArgs(args)
+		if err != nil {
+			return err
+		}
+
+		type selected struct {
+			Path    string      `json:"path"`
+			Pointer string      `json:"pointer"`
+			Value   interface{} `json:"value"`
+		}
+		entries := []selected{}
+		for _, match := range path.Select(data) {
+			entries = append(entries, selected{Path: match.Path.String(), Pointer: match.Path.Pointer(), Value: match.Value})
+		}
+		return printJSON(entries)
+	},
+}
+
+func init() {
+	getCmd.Flags().BoolP("raw", "r", false, "Print a string value without JSON quotes")
+	rootCmd.AddCommand(getCmd)
+	rootCmd.AddCommand(selectCmd)
+}
+
+// loadPlumbingArgs resolves the context and key path arguments of a
+// plumbing command
+func loadPlumbingArgs(args []string) (string, map[string]interface{}, context.KeyPath, error) {
+	path, err := context.ParseKeyPath(args[1])
+	if err != nil {
+		return "", nil, nil, usageErrorf("%v", err)
+	}
+
+	manager, err := newManager()
+	if err != nil {
+		return "", nil, nil, err
+	}
+	name := args[0]
+	if name == currentContextArg {
+		if name, _, err = manager.EffectiveCurrentContext(); err != nil {
+			return "", nil, nil, err
+		}
+		if name == "" {
+			return "", nil, nil, fmt.Errorf("no current context")
+		}
+	}
+
+	ctx, err := manager.GetContext(name)
+	if err != nil {
+		return "", nil, nil, err
+	}
+	return ctx.Name, ctx.Data, path, nil
+}
@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/hungthai1401/occtx/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+// profilesCmd lists the workspace profiles defined in the occtx config file
+var profilesCmd = &cobra.Command{
+	Use:   "profiles",
+	Short: "List workspace profiles",
+	Long: `Profiles let one occtx manage several opencode installations, each with
+its own config directory, contexts and state. Define them under "profiles"
+in the occtx config file and select one with --profile or OCCTX_PROFILE.
+
+Examples:
+  occtx profiles                 # List profiles, active one marked
+  occtx --profile work           # List contexts of the work profile
+  OCCTX_PROFILE=personal occtx dev`,
+	Args: cobra.NoArgs,
+	RunE: runProfiles,
+}
+
+func init() {
+	rootCmd.AddCommand(profilesCmd)
+}
+
+func runProfiles(cmd *cobra.Command, args []string) error {
+	manager, err := newManager()
+	if err != nil {
+		return err
+	}
+
+	names := manager.ProfileNames()
+	if len(names) == 0 {
+		fmt.Printf("No profiles configured in %s\n", manager.GetPaths().ConfigFile)
+		return nil
+	}
+
+	printer := ui.NewColorPrinter()
+	for _, name := range names {
+		dir := manager.GetConfig().Profiles[name].ConfigDir
+		if name == manager.Profile() {
+			printer.PrintCurrent("%s %s (%s)\n", ui.CurrentTheme().CurrentMarker, name, dir)
+		} else {
+			fmt.Printf("  %s (%s)\n", name, dir)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,215 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/hungthai1401/occtx/internal/config"
+	"github.com/hungthai1401/occtx/internal/context"
+	"github.com/hungthai1401/occtx/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+// gitignoreHeader starts the block project init appends to .gitignore; it
+// is how a second run recognizes the block
+const gitignoreHeader = "# occtx: per-user state and machine-local overrides"
+
+// projectCmd groups project-level setup
+var projectCmd = &cobra.Command{
+	Use:   "project",
+	Short: "Set up project-level contexts",
+}
+
+// projectInitCmd creates the project settings layout
+var projectInitCmd = &cobra.Command{
+	Use:   "init",
+	Short: "Create the project-level context layout in the current directory",
+	Long: `Init prepares the current directory for project-level contexts:
+
+  ` + config.ProjectConfigDir + `/` + config.SettingsSubDir + `/    Where project contexts live (occtx --in-project)
+  .gitignore           Gets patterns for the files that stay per user: the
+                       state file, the current-context marker, machine-local
+                       overrides (<name>.local.json), stash, trash and backups
+
+--copy-current seeds the project with the current global context, under
+the same name or --name. Its content is checked for literal secrets, since
+project contexts are usually committed. It also writes a ` + config.DirContextFileName + `
+file naming the context as proj:<name>, so the shell integration
+('occtx init') switches to it on entering the directory.
+
+Running init again only adds what is missing.
+
+Examples:
+  occtx project init
+  occtx project init --copy-current
+  occtx project init --copy-current --name team`,
+	Args: cobra.NoArgs,
+	RunE: runProjectInit,
+}
+
+func init() {
+	projectInitCmd.Flags().Bool("copy-current", false, "Seed the project with the current global context")
+	projectInitCmd.Flags().String("name", "", "With --copy-current, name of the project context (default: the global context's name)")
+	projectInitCmd.Flags().Bool("no-gitignore", false, "Don't add patterns to .gitignore")
+	projectCmd.AddCommand(projectInitCmd)
+	rootCmd.AddCommand(projectCmd)
+}
+
+func runProjectInit(cmd *cobra.Command, args []string) error {
+	copyCurrent, _ := cmd.Flags().GetBool("copy-current")
+	name, _ := cmd.Flags().GetString("name")
+	if name != "" && !copyCurrent {
+		return usageErrorf("--name requires --copy-current")
+	}
+
+	manager, err := newManagerForScope(true)
+	if err != nil {
+		return err
+	}
+	if err := manager.CheckWritable("initialize project"); err != nil {
+		return err
+	}
+
+	printer := ui.NewColorPrinter()
+	settingsDir := manager.GetPaths().GetContextsDir(true)
+	if _, err := os.Stat(settingsDir); err == nil {
+		printer.PrintInfo("%s already exists\n", settingsDir)
+	} else {
+		if err := os.MkdirAll(settingsDir, 0755); err != nil {
+			return err
+		}
+		printer.PrintSuccess("Created %s\n", settingsDir)
+	}
+
+	if copyCurrent {
+		if name, err = seedProjectContext(manager, name); err != nil {
+			return err
+		}
+		printer.PrintSuccess("Copied the current global context to project context '%s'\n", name)
+
+		created, err := writeDirContextFile(config.DirContextFileName, "proj:"+name)
+		if err != nil {
+			return err
+		}
+		if created {
+			printer.PrintSuccess("Wrote %s (auto-switch to proj:%s)\n", config.DirContextFileName, name)
+		} else {
+			printer.PrintInfo("%s already exists; left unchanged\n", config.DirContextFileName)
+		}
+	}
+
+	if noGitignore, _ := cmd.Flags().GetBool("no-gitignore"); !noGitignore {
+		added, err := appendGitignore(".gitignore")
+		if err != nil {
+			return err
+		}
+		if added {
+			printer.PrintSuccess("Added occtx patterns to .gitignore\n")
+		} else {
+			printer.PrintInfo(".gitignore already has the occtx patterns\n")
+		}
+	}
+
+	if contexts, err := manager.ListContexts(); err == nil && len(contexts) == 0 {
+		ui.Hintf("Create a project context with 'occtx --in-project -n <name>'")
+	}
+	return nil
+}
+
+// seedProjectContext copies the current global context into the project
+// scope as name, or under its own name, and returns the name used
+func seedProjectContext(project *context.Manager, name string) (string, error) {
+	global, err := newManagerForScope(false)
+	if err != nil {
+		return "", err
+	}
+	current, err := global.GetCurrentContext()
+	if err != nil {
+		return "", err
+	}
+	if current == "" {
+		return "", fmt.Errorf("no global context is current; switch to one or drop --copy-current")
+	}
+	ctx, err := global.GetContext(current)
+	if err != nil {
+		return "", err
+	}
+	data, err := os.ReadFile(ctx.FilePath)
+	if err != nil {
+		return "", err
+	}
+
+	if name == "" {
+		name = ctx.Name
+	}
+	source := context.Source{Kind: context.SourceCopy, From: "global:" + ctx.Name}
+	warnings, err := project.ImportContextWithOptions(name, data, source, context.ImportOptions{
+		Format:  ctx.Format,
+		Secrets: context.PolicyWarn,
+	})
+	printWarnings(warnings)
+	return name, err
+}
+
+// writeDirContextFile writes a directory context file naming name, unless
+// one exists; it reports whether it wrote it
+func writeDirContextFile(filePath, name string) (bool, error) {
+	if _, err := os.Stat(filePath); err == nil {
+		return false, nil
+	} else if !os.IsNotExist(err) {
+		return false, err
+	}
+	return true, os.WriteFile(filePath, []byte(name+"\n"), 0644)
+}
+
+// gitignorePatterns are the project files that stay per user
+func gitignorePatterns() []string {
+	settings := path.Join(config.ProjectConfigDir, config.SettingsSubDir)
+	return []string{
+		path.Join(settings, config.StateFileName),
+		path.Join(settings, config.StateFileName) + ".*",
+		path.Join(settings, config.CurrentMarkerFileName),
+		path.Join(settings, "*"+context.LocalOverrideSuffix+".json"),
+		path.Join(settings, "*"+context.LocalOverrideSuffix+".jsonc"),
+		path.Join(settings, config.StashSubDir) + "/",
+		path.Join(settings, config.TrashSubDir) + "/",
+		path.Join(settings, config.BackupSubDir) + "/",
+	}
+}
+
+// appendGitignore adds the occtx block to the gitignore file unless it is
+// there already; it reports whether it added it
+func appendGitignore(gitignorePath string) (bool, error) {
+	existing, err := os.ReadFile(gitignorePath)
+	if err != nil && !os.IsNotExist(err) {
+		return false, err
+	}
+	if bytes.Contains(existing, []byte(gitignoreHeader)) {
+		return false, nil
+	}
+
+	var block strings.Builder
+	if len(existing) > 0 {
+		if !bytes.HasSuffix(existing, []byte("\n")) {
+			block.WriteString("\n")
+		}
+		block.WriteString("\n")
+	}
+	block.WriteString(gitignoreHeader + "\n")
+	for _, pattern := range gitignorePatterns() {
+		block.WriteString("/" + pattern + "\n")
+	}
+
+	file, err := os.OpenFile(gitignorePath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return false, err
+	}
+	if _, err := file.WriteString(block.String()); err != nil {
+		file.Close()
+		return false, err
+	}
+	return true, file.Close()
+}
@@ -0,0 +1,87 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/hungthai1401/occtx/internal/config"
+	"github.com/hungthai1401/occtx/internal/context"
+	"github.com/hungthai1401/occtx/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+// projectsCmd reports every directory under the workspace roots with project-level contexts
+var projectsCmd = &cobra.Command{
+	Use:   "projects",
+	Short: "Find directories with project-level contexts",
+	Long: `Projects scans workspace roots for directories containing project-level
+contexts (created with --in-project) and prints where they are, how many
+contexts each has, and which one is current.
+
+Roots come from --root or "projects.roots" in the occtx config file.
+
+Examples:
+  occtx projects                     # Scan the configured roots
+  occtx projects --root ~/code       # Scan a specific directory
+  occtx projects --root . --depth 2  # Only look two levels deep`,
+	Args: cobra.NoArgs,
+	RunE: runProjects,
+}
+
+func init() {
+	projectsCmd.Flags().StringArray("root", nil, "Directory to scan (repeatable; overrides projects.roots)")
+	projectsCmd.Flags().Int("depth", 0, fmt.Sprintf("Maximum directory depth below each root (default %d or projects.max_depth)", config.DefaultProjectScanDepth))
+	rootCmd.AddCommand(projectsCmd)
+}
+
+func runProjects(cmd *cobra.Command, args []string) error {
+	manager, err := newManager()
+	if err != nil {
+		return err
+	}
+	projectsConfig := manager.GetConfig().Projects
+
+	roots, _ := cmd.Flags().GetStringArray("root")
+	if len(roots) == 0 {
+		roots = projectsConfig.Roots
+	}
+	if len(roots) == 0 {
+		return fmt.Errorf("no workspace roots configured; pass --root or set projects.roots in %s", manager.GetPaths().ConfigFile)
+	}
+
+	for i, root := range roots {
+		if roots[i], err = config.ExpandHome(root); err != nil {
+			return err
+		}
+	}
+
+	depth, _ := cmd.Flags().GetInt("depth")
+	if depth <= 0 {
+		depth = projectsConfig.EffectiveMaxDepth()
+	}
+
+	progress := ui.NewProgress(fmt.Sprintf("Scanning %d workspace root(s)", len(roots)))
+	progress.Start()
+	projects, err := context.DiscoverProjects(commandCtx, roots, depth)
+	progress.Stop()
+	if err != nil {
+		return err
+	}
+
+	if len(projects) == 0 {
+		fmt.Println("No project contexts found")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "PATH\tCONTEXTS\tCURRENT")
+	for _, project := range projects {
+		current := project.Current
+		if current == "" {
+			current = "-"
+		}
+		fmt.Fprintf(w, "%s\t%d\t%s\n", project.Path, project.Contexts, current)
+	}
+	return w.Flush()
+}
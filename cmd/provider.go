@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"io"
+	"os"
+	"strings"
+
+	"github.com/hungthai1401/occtx/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+// providerCmd moves provider blocks between contexts
+var providerCmd = &cobra.Command{
+	Use:   "provider [export|apply]",
+	Short: "Copy a provider block from one context to another",
+	Long: `Provider moves just the "provider" entry of a context, such as its anthropic
+settings, instead of a whole context with everyone's personal preferences.
+Export writes the block as a JSON object keyed by the provider name; apply
+sets every provider in such a file on a context, replacing the provider of
+the same name and keeping the rest.
+
+Examples:
+  occtx provider export work anthropic > anthropic.json
+  occtx provider export work anthropic --redact > anthropic.json
+  occtx provider apply personal anthropic.json
+  occtx provider export work anthropic | occtx provider apply personal -`,
+	Args: subcommandArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return cmd.Help()
+	},
+}
+
+var providerExportCmd = &cobra.Command{
+	Use:               "export <name> <provider>",
+	Short:             "Write a provider block of a context to stdout",
+	Args:              cobra.ExactArgs(2),
+	ValidArgsFunction: completeContextArg,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		manager, err := newManager()
+		if err != nil {
+			return err
+		}
+
+		redact, _ := cmd.Flags().GetBool("redact")
+		data, err := manager.ExportProvider(args[0], args[1], redact)
+		if err != nil {
+			return err
+		}
+		_, err = cmd.OutOrStdout().Write(data)
+		return err
+	},
+}
+
+var providerApplyCmd = &cobra.Command{
+	Use:               "apply <name> <file|->",
+	Short:             "Set the provider blocks of a file on a context",
+	Args:              cobra.ExactArgs(2),
+	ValidArgsFunction: completeContextArg,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		manager, err := newManager()
+		if err != nil {
+			return err
+		}
+
+		var data []byte
+		if args[1] == "-" {
+			data, err = io.ReadAll(cmd.InOrStdin())
+		} else {
+			data, err = os.ReadFile(args[1])
+		}
+		if err != nil {
+			return err
+		}
+
+		providers, err := manager.ApplyProviders(args[0], data)
+		if err != nil {
+			return err
+		}
+		printer := ui.NewColorPrinter()
+		printer.PrintSuccess("Applied provider %s to '%s'\n", strings.Join(providers, ", "), args[0])
+		return nil
+	},
+}
+
+func init() {
+	providerExportCmd.Flags().Bool("redact", false, "Replace API keys, tokens and passwords with a placeholder")
+
+	providerCmd.AddCommand(providerExportCmd)
+	providerCmd.AddCommand(providerApplyCmd)
+	rootCmd.AddCommand(providerCmd)
+}
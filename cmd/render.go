@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"github.com/hungthai1401/occtx/internal/context"
+	"github.com/spf13/cobra"
+)
+
+// renderCmd prints the active config a switch would write
+var renderCmd = &cobra.Command{
+	Use:   "render <name>",
+	Short: "Print the exact active config a switch to a context would write",
+	Long: `Render resolves a context the way a switch does (machine-local override,
+per-machine sections, comment handling) and prints the exact bytes the switch
+would write to the active config, without switching or writing anything. Use
+it to review a composed context or to feed it to other tools.
+
+--overlay deep-merges other contexts on top, in order and below the local
+override, with the merge settings of the occtx config. --from-template renders
+a template under the name instead of the stored context, as -n would create
+it; --var gives its variables. Policies and approval are not checked.
+
+Examples:
+  occtx render work
+  occtx render work --overlay review --overlay local-proxy
+  occtx render team-api --from-template team --var team=api
+  occtx render work | jq .model`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeContextArg,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		tmpl, err := templateFlags(cmd)
+		if err != nil {
+			return err
+		}
+		overlays, _ := cmd.Flags().GetStringArray("overlay")
+
+		manager, err := newManager()
+		if err != nil {
+			return err
+		}
+
+		opts := context.RenderOptions{Overlays: overlays}
+		if tmpl != nil {
+			opts.Template = tmpl.Name
+			opts.Vars = tmpl.Vars
+		}
+		_, data, err := manager.Render(args[0], opts)
+		if err != nil {
+			return err
+		}
+		_, err = cmd.OutOrStdout().Write(data)
+		return err
+	},
+}
+
+func init() {
+	renderCmd.Flags().StringArray("overlay", nil, "A context to deep-merge on top (repeatable, applied in order)")
+	renderCmd.Flags().String("from-template", "", "Render a context template under the name instead of the stored context")
+	renderCmd.Flags().StringArray("var", nil, "With --from-template, a template variable as name=value (repeatable)")
+	_ = renderCmd.RegisterFlagCompletionFunc("overlay", completeContextFlag)
+	_ = renderCmd.RegisterFlagCompletionFunc("from-template", completeTemplates)
+	rootCmd.AddCommand(renderCmd)
+}
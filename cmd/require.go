@@ -0,0 +1,109 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hungthai1401/occtx/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+// requireCmd declares what a context needs from the machine it runs on
+var requireCmd = &cobra.Command{
+	Use:   "require <name> [requirement...]",
+	Short: "Show, check or declare what a context needs from this machine",
+	Long: `Require declares what a context needs from the machine it is switched to
+on. Switching fails with the unmet requirements listed, instead of opencode
+failing later; --skip-requires switches anyway.
+
+Requirements:
+  env:NAME         The environment variable is set, or exported by the context
+  cmd:NAME         The command is on PATH
+  file:PATH        The file or directory exists ("~/" is expanded)
+  tcp:HOST:PORT    The address accepts connections within 2 seconds
+  NAME             Listed in "provides" of the occtx config, e.g. work-vpn
+
+Without requirements, each declared one is checked and the command fails if
+any is unmet.
+
+Examples:
+  occtx require work env:ANTHROPIC_API_KEY tcp:proxy.corp:3128
+  occtx require work work-vpn         # Needs "provides": ["work-vpn"]
+  occtx require work                  # Check this machine
+  occtx require work --remove work-vpn
+  occtx require work --clear`,
+	Args:              cobra.MinimumNArgs(1),
+	ValidArgsFunction: completeContextArg,
+	RunE:              runRequire,
+}
+
+func init() {
+	requireCmd.Flags().Bool("remove", false, "Remove the given requirements")
+	requireCmd.Flags().Bool("clear", false, "Remove all requirements")
+	rootCmd.AddCommand(requireCmd)
+}
+
+func runRequire(cmd *cobra.Command, args []string) error {
+	name, requirements := args[0], args[1:]
+	remove, _ := cmd.Flags().GetBool("remove")
+	clear, _ := cmd.Flags().GetBool("clear")
+
+	manager, err := newManager()
+	if err != nil {
+		return err
+	}
+
+	printer := ui.NewColorPrinter()
+	switch {
+	case clear:
+		if len(requirements) > 0 || remove {
+			return usageErrorf("--clear takes no requirements")
+		}
+		if err := manager.RemoveRequirements(name, nil); err != nil {
+			return err
+		}
+		printer.PrintSuccess("Removed the requirements of '%s'\n", name)
+		return nil
+	case remove:
+		if len(requirements) == 0 {
+			return usageErrorf("--remove needs the requirements to remove")
+		}
+		if err := manager.RemoveRequirements(name, requirements); err != nil {
+			return err
+		}
+		printer.PrintSuccess("Removed %d requirement(s) from '%s'\n", len(requirements), name)
+		return nil
+	case len(requirements) > 0:
+		if err := manager.AddRequirements(name, requirements); err != nil {
+			return err
+		}
+		printer.PrintSuccess("Context '%s' requires %s\n", name, strings.Join(requirements, ", "))
+		return nil
+	}
+
+	if _, err := manager.GetContext(name); err != nil {
+		return err
+	}
+	results, err := manager.CheckRequirements(name)
+	if err != nil {
+		return err
+	}
+	if len(results) == 0 {
+		fmt.Println("No requirements declared")
+		return nil
+	}
+
+	failed := 0
+	for _, result := range results {
+		if result.Problem == "" {
+			printer.PrintSuccess("✓ %s\n", result.Requirement)
+			continue
+		}
+		failed++
+		printer.PrintError("✗ %s (%s)\n", result.Requirement, result.Problem)
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d requirement(s) of '%s' not met", failed, len(results), name)
+	}
+	return nil
+}
@@ -2,22 +2,35 @@ package cmd
 
 import (
 	"bufio"
+	gocontext "context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
-	"path/filepath"
+	"sort"
 	"strings"
+	"time"
 
+	"github.com/hungthai1401/occtx/internal/config"
 	"github.com/hungthai1401/occtx/internal/context"
 	"github.com/hungthai1401/occtx/internal/ui"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 )
 
 var (
 	// Global flags
 	inProject bool
-	verbose   bool
+	verbose   int // -v verbose, -vv debug
+	readOnly  bool
+	offline   bool
+	timings   bool
+	profile   string
+	quiet     bool
+	strict    bool
+	configDir string
+	ciMode    bool
 )
 
 // rootCmd represents the base command when called without any subcommands
@@ -26,37 +39,172 @@ var rootCmd = &cobra.Command{
 	Short:              "opencode context switcher",
 	Version:            "0.1.0",
 	RunE:               runRoot,
+	PersistentPreRunE:  beforeCommand,
 	DisableFlagParsing: false,
 	DisableAutoGenTag:  true,
 	SilenceUsage:       true,
-	SilenceErrors:      false,
+	SilenceErrors:      true,                // Printed by ReportError
 	Args:               cobra.ArbitraryArgs, // Allow arbitrary args for context names
 }
 
 // Execute adds all child commands to the root command and sets flags appropriately.
 func Execute() error {
-	return rootCmd.Execute()
+	start := time.Now()
+	cmd, err := rootCmd.ExecuteContextC(gocontext.Background())
+	cancelCommand()
+	elapsed := time.Since(start)
+	if timings {
+		printTimings(os.Stderr, elapsed)
+	}
+	recordCommandStats(cmd, elapsed, err)
+	startWebhookDelivery()
+	return explainTimeout(err)
 }
 
 func init() {
 	// Global flags
 	rootCmd.PersistentFlags().BoolVar(&inProject, "in-project", false, "Use project-level contexts (./opencode.json)")
-	rootCmd.PersistentFlags().BoolVarP(&verbose, "verbose", "v", false, "Verbose output")
+	rootCmd.PersistentFlags().CountVarP(&verbose, "verbose", "v", "Verbose output: paths, scope, formats and timing (-vv for debug detail)")
+	rootCmd.PersistentFlags().BoolVarP(&quiet, "quiet", "q", false, "Suppress warnings such as active config drift")
+	rootCmd.PersistentFlags().StringVar(&profile, "profile", "", "Use a workspace profile from the occtx config (or set OCCTX_PROFILE)")
+	rootCmd.PersistentFlags().BoolVar(&readOnly, "read-only", false, "Fail any operation that would modify contexts or the active config (or set OCCTX_READONLY=1)")
+	rootCmd.PersistentFlags().BoolVar(&offline, "offline", false, "Fail any operation that would use the network, such as models update (or set OCCTX_OFFLINE=1)")
+	rootCmd.PersistentFlags().StringVar(&configDir, "config-dir", "", "Global opencode config directory to use (or set OCCTX_CONFIG_DIR)")
+	rootCmd.PersistentFlags().BoolVar(&strict, "strict", false, "Enforce approval.required from the occtx config and fail on warnings (or set OCCTX_STRICT=1)")
+	rootCmd.PersistentFlags().BoolVar(&timings, "timings", false, "Print how long the command and each phase of a switch or listing took to stderr")
+	rootCmd.PersistentFlags().Duration("timeout", 0, "Give up on the command after this long, e.g. 30s, including hung editors, fzf and network access (or set OCCTX_TIMEOUT)")
+	rootCmd.PersistentFlags().BoolVar(&ciMode, "ci", false, "CI mode: quiet, no color, no prompts, distinct exit codes and JSON errors (or set OCCTX_CI=1; on when CI is set)")
 
 	// Local flags for root command
 	rootCmd.Flags().BoolP("current", "c", false, "Show current context name")
 	rootCmd.Flags().BoolP("unset", "u", false, "Unset current context")
+	rootCmd.Flags().Bool("clean", false, "With -u --in-project, also remove the project's opencode directory if it holds nothing but occtx state")
 	rootCmd.Flags().StringP("new", "n", "", "Create new context from current settings")
+	rootCmd.Flags().Bool("empty", false, "With -n, start from a minimal built-in config instead of the active one")
+	rootCmd.Flags().String("provider", "", fmt.Sprintf("With --empty, default model provider (%s)", strings.Join(context.SkeletonProviders(), ", ")))
+	rootCmd.Flags().String("model", "", "With --empty, model ID (or provider/model)")
+	rootCmd.Flags().String("from-template", "", "With -n, render a context template instead of copying the active config")
+	rootCmd.Flags().StringArray("var", nil, "With --from-template, a template variable as name=value (repeatable; missing ones are prompted for)")
+	rootCmd.Flags().Bool("slugify", false, "Convert the -n name into a portable slug (e.g. 'My Work' -> 'my-work')")
 	rootCmd.Flags().StringP("format", "f", "json", fmt.Sprintf("Format for new context (%s)", context.GetSupportedFormats()))
 	rootCmd.Flags().StringP("delete", "d", "", "Delete context")
+	rootCmd.Flags().String("switch-to", "", "With -d on the current context, first switch to this context, 'previous' or 'none'")
 	rootCmd.Flags().StringP("edit", "e", "", "Edit context with $EDITOR")
+	rootCmd.Flags().String("json-path", "", "With -e, edit only the value at this dotted key path (e.g. provider.anthropic)")
 	rootCmd.Flags().StringP("show", "s", "", "Show context content")
+	rootCmd.Flags().Bool("meta", false, "With -s, show context metadata (source, creation time) instead of content")
 	rootCmd.Flags().StringP("export", "", "", "Export context to stdout")
+	rootCmd.Flags().Bool("redact", false, "With --export, replace API keys, tokens and passwords with a placeholder")
+	rootCmd.Flags().StringSlice("strip-keys", nil, "With --export, remove values at these key paths ('*' matches any key)")
+	rootCmd.Flags().Bool("minify", false, "With --export, emit compact JSON")
+	rootCmd.Flags().Bool("include-local", false, "With --export, merge in the context's machine-local override (<name>.local.json)")
+	rootCmd.Flags().Bool("git-safe", false, "With --export, output safe to commit: secrets redacted, no machine-local override, normalized, with a provenance header")
 	rootCmd.Flags().StringP("import", "", "", "Import context from stdin")
+	rootCmd.Flags().String("from-opencode", "", "With --import, read the config from an opencode config directory, config file or session export instead of stdin")
+	rootCmd.Flags().String("validate", "", "With --import, schema check policy (off, warn, reject)")
+	rootCmd.Flags().Lookup("validate").NoOptDefVal = "reject"
+	rootCmd.Flags().String("secrets", "", "With --import, literal secret policy (off, warn, reject)")
+	rootCmd.Flags().Lookup("secrets").NoOptDefVal = "reject"
+	rootCmd.Flags().Bool("dry-run", false, "With a switch, -d or --import, print what would change without writing anything (-o json for a machine-readable plan)")
+	rootCmd.Flags().Bool("merge", false, "With --import, deep-merge into an existing context instead of creating one")
+	rootCmd.Flags().String("merge-arrays", "", "With --merge, how arrays combine (replace, append, union)")
+	rootCmd.Flags().String("merge-nulls", "", "With --merge, what null values do (set, delete, ignore)")
+	rootCmd.Flags().String("merge-delete", "", "With --merge, a string value that deletes its key (e.g. __delete__)")
+	rootCmd.Flags().Bool("force", false, "With -n or --import, replace an existing context (the old one is moved to the trash); when switching, overwrite an active config occtx didn't write")
+	rootCmd.Flags().String("save-as", "", "When switching, first save an active config occtx didn't write as a new context")
+	rootCmd.Flags().BoolP("yes", "y", false, "When switching, don't ask before using a guarded cost tier or after a review; with --clean, don't ask before removing")
+	rootCmd.Flags().Bool("review", false, "When switching, show what changes in the active config and ask first")
+	rootCmd.Flags().Bool("create-missing", false, "When switching to a context that doesn't exist, create it from the active config first")
+	rootCmd.Flags().Bool("skip-requires", false, "When switching, go ahead even if this machine lacks what the context requires")
 	rootCmd.Flags().BoolP("interactive", "i", false, "Interactive context selection")
+	addPickerFlags(rootCmd.Flags())
+	rootCmd.Flags().Bool("menu", false, "With -i, choose an action first: switch, unset, stash, restore a stash or a backup")
+
+	// Listing filters and output
+	addListFlags(rootCmd.Flags())
+	rootCmd.Flags().StringP("output", "o", "text", "Listing and --dry-run plan output format (text, json)")
 
 	// Rename requires two arguments, will handle in runRoot
 	rootCmd.Flags().BoolP("rename", "r", false, "Rename context (usage: occtx -r old new)")
+
+	registerRootCompletions()
+}
+
+// beforeCommand applies the theme and runs housekeeping shared by every command
+func beforeCommand(cmd *cobra.Command, args []string) error {
+	// Scripts reading -o json get diagnostics they can parse as well
+	if output := cmd.Flags().Lookup("output"); output != nil && output.Value.String() == "json" {
+		ui.SetDiagnosticsJSON(true)
+	}
+	if ciEnabled() {
+		applyCIMode()
+	}
+	if err := applyTimeout(cmd); err != nil {
+		return err
+	}
+
+	// Through the environment, so commands run by exec and shell inherit them
+	if configDir != "" {
+		if err := os.Setenv(config.ConfigDirEnvVar, configDir); err != nil {
+			return err
+		}
+	}
+	if readOnly {
+		if err := os.Setenv(config.ReadOnlyEnvVar, "1"); err != nil {
+			return err
+		}
+	}
+	warnConfigDirFallback(cmd)
+	ui.SetProgressEnabled(!quiet)
+
+	// Before anything reads the scope
+	if err := applyScopedNames(cmd, args); err != nil {
+		return err
+	}
+
+	if err := applyTheme(); err != nil {
+		return err
+	}
+
+	if err := revertExpiredSwitch(cmd, args); err != nil {
+		return err
+	}
+
+	return warnActiveDrift(cmd)
+}
+
+// warnConfigDirFallback reports when occtx works in a temporary config
+// directory because no home directory is available or the default config
+// directory can't be created
+func warnConfigDirFallback(cmd *cobra.Command) {
+	if quiet {
+		return
+	}
+
+	paths, err := config.NewPaths()
+	if err != nil || paths.Fallback == "" {
+		return
+	}
+
+	ui.Warnf("%s; using temporary config dir %s (contexts may not persist; set --config-dir or %s)",
+		paths.Fallback, paths.GlobalConfigDir, config.ConfigDirEnvVar)
+}
+
+// applyTheme selects the color theme and symbols from the occtx config
+func applyTheme() error {
+	manager, err := newManager()
+	if err != nil {
+		// The command itself will report the problem
+		return nil
+	}
+
+	theme, err := ui.ThemeFromConfig(manager.GetConfig().UI)
+	if err != nil {
+		return err
+	}
+
+	ui.SetTheme(theme)
+	return nil
 }
 
 func runRoot(cmd *cobra.Command, args []string) error {
@@ -64,7 +212,7 @@ func runRoot(cmd *cobra.Command, args []string) error {
 
 	// Interactive mode
 	if interactive, _ := cmd.Flags().GetBool("interactive"); interactive {
-		return runInteractiveSelection()
+		return runInteractiveSelection(cmd)
 	}
 
 	// Show current context
@@ -74,38 +222,76 @@ func runRoot(cmd *cobra.Command, args []string) error {
 
 	// Unset current context
 	if unset, _ := cmd.Flags().GetBool("unset"); unset {
+		if clean, _ := cmd.Flags().GetBool("clean"); clean {
+			if !inProject {
+				return usageErrorf("--clean only applies to the project scope; add --in-project")
+			}
+			yes, _ := cmd.Flags().GetBool("yes")
+			return unsetAndCleanProject(yes)
+		}
 		return unsetCurrentContext()
 	}
 
 	// Create new context
 	if newName, _ := cmd.Flags().GetString("new"); newName != "" {
 		format, _ := cmd.Flags().GetString("format")
-		return createNewContext(newName, format)
+		slugify, _ := cmd.Flags().GetBool("slugify")
+		var skeleton *context.SkeletonOptions
+		if empty, _ := cmd.Flags().GetBool("empty"); empty {
+			skeleton = &context.SkeletonOptions{}
+			skeleton.Provider, _ = cmd.Flags().GetString("provider")
+			skeleton.Model, _ = cmd.Flags().GetString("model")
+		} else if cmd.Flags().Changed("provider") || cmd.Flags().Changed("model") {
+			return fmt.Errorf("--provider and --model require --empty")
+		}
+		tmpl, err := templateFlags(cmd)
+		if err != nil {
+			return err
+		}
+		if tmpl != nil && skeleton != nil {
+			return usageErrorf("--from-template and --empty can't be combined")
+		}
+		force, _ := cmd.Flags().GetBool("force")
+		return createNewContext(newName, format, slugify, force, skeleton, tmpl)
 	}
 
 	// Delete context
 	if deleteName, _ := cmd.Flags().GetString("delete"); deleteName != "" {
-		return deleteContext(deleteName)
+		switchTo, _ := cmd.Flags().GetString("switch-to")
+		if dryRun(cmd) {
+			return planDelete(cmd, deleteName, switchTo)
+		}
+		return deleteContext(deleteName, switchTo)
 	}
 
 	// Edit context
 	if editName, _ := cmd.Flags().GetString("edit"); editName != "" {
+		if jsonPath, _ := cmd.Flags().GetString("json-path"); jsonPath != "" {
+			return editContextPath(editName, jsonPath)
+		}
 		return editContext(editName)
 	}
 
 	// Show context
 	if showName, _ := cmd.Flags().GetString("show"); showName != "" {
+		if meta, _ := cmd.Flags().GetBool("meta"); meta {
+			return showContextMeta(showName)
+		}
 		return showContext(showName)
 	}
 
 	// Export context
 	if exportName, _ := cmd.Flags().GetString("export"); exportName != "" {
-		return exportContext(exportName)
+		opts, err := exportOptions(cmd)
+		if err != nil {
+			return err
+		}
+		return exportContext(exportName, opts)
 	}
 
 	// Import context
 	if importName, _ := cmd.Flags().GetString("import"); importName != "" {
-		return importContext(importName)
+		return importContext(cmd, importName)
 	}
 
 	// Handle rename (requires special parsing)
@@ -119,28 +305,96 @@ func runRoot(cmd *cobra.Command, args []string) error {
 	// Handle context switching and listing
 	switch len(args) {
 	case 0:
-		// List contexts
-		return listContexts()
+		return runList(cmd, false)
 	case 1:
-		if args[0] == "-" {
+		opts := switchOptionsFromFlags(cmd)
+		name, err := resolvePosition(args[0])
+		if err != nil {
+			return err
+		}
+		if dryRun(cmd) {
+			return withCommandSuggestion(cmd, planSwitch(cmd, name, opts))
+		}
+		if name == "-" {
 			// Switch to previous context
-			return switchToPreviousContext()
+			return switchToPreviousContext(opts)
 		}
 		// Switch to named context
-		return switchToContext(args[0])
+		return withCommandSuggestion(cmd, switchToContext(name, opts))
 	default:
 		return fmt.Errorf("too many arguments")
 	}
 }
 
+// newManager creates a context manager for the selected scope with global flags applied
+func newManager() (*context.Manager, error) {
+	return newManagerForScope(inProject)
+}
+
+// loggedScopes records which scopes verbose mode has already described
+var loggedScopes = map[bool]bool{}
+
+// newManagerForScope creates a context manager for the given scope with global flags applied
+func newManagerForScope(useProject bool) (*context.Manager, error) {
+	var manager *context.Manager
+	var err error
+	if profile != "" {
+		manager, err = context.NewManagerForProfile(useProject, profile)
+	} else {
+		manager, err = context.NewManager(useProject)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if readOnly {
+		manager.SetReadOnly(true)
+	}
+	if offline {
+		manager.SetOffline(true)
+	}
+	manager.SetContext(commandCtx)
+	if timings {
+		manager.SetTimingHandler(recordTiming)
+	}
+	if strict {
+		manager.SetStrict(true)
+	}
+
+	manager.SetWarningHandler(func(message string) {
+		ui.Warnf("%s", message)
+	})
+	manager.SetSpoolHandler(func() {
+		if !manager.IsOffline() {
+			webhookConfigFile = manager.GetPaths().ConfigFile
+		}
+	})
+	if verbose > 0 {
+		manager.SetVerbosity(context.Verbosity(verbose), func(level context.Verbosity, message string) {
+			if level >= context.VerbosityDebug {
+				ui.Diagnose(ui.LevelDebug, "%s", message)
+				return
+			}
+			ui.Diagnose(ui.LevelVerbose, "%s", message)
+		})
+		// Commands often build several managers; describe each scope once
+		if !loggedScopes[useProject] {
+			loggedScopes[useProject] = true
+			manager.LogEnvironment()
+		}
+	}
+
+	return manager, nil
+}
+
 // Implementation functions using context manager
 func showCurrentContext() error {
-	manager, err := context.NewManager(inProject)
+	manager, err := newManager()
 	if err != nil {
 		return err
 	}
 
-	current, err := manager.GetCurrentContext()
+	current, session, err := manager.EffectiveCurrentContext()
 	if err != nil {
 		return err
 	}
@@ -151,11 +405,14 @@ func showCurrentContext() error {
 	}
 
 	fmt.Println(current)
+	if session && verbose > 0 {
+		fmt.Printf("(session context from %s)\n", context.SessionContextEnvVar)
+	}
 	return nil
 }
 
 func unsetCurrentContext() error {
-	manager, err := context.NewManager(inProject)
+	manager, err := newManager()
 	if err != nil {
 		return err
 	}
@@ -168,19 +425,85 @@ func unsetCurrentContext() error {
 	return nil
 }
 
-func createNewContext(name, formatStr string) error {
+// unsetAndCleanProject unsets the project context and removes the project's
+// opencode directory when nothing but occtx state is left in it. Unless yes is
+// set it asks first, and nothing is unset when the answer is no; a directory
+// that has to be kept anyway is only reported after unsetting.
+func unsetAndCleanProject(yes bool) error {
+	manager, err := newManager()
+	if err != nil {
+		return err
+	}
+
+	dir := manager.ProjectDir()
+	blockers, err := manager.ProjectDirBlockers()
+	if errors.Is(err, os.ErrNotExist) {
+		return unsetCurrentContext()
+	}
+	if err != nil {
+		return err
+	}
+	if len(blockers) > 0 {
+		if err := unsetCurrentContext(); err != nil {
+			return err
+		}
+		return fmt.Errorf("kept %s: it holds more than occtx state: %s", dir, strings.Join(blockers, ", "))
+	}
+
+	current, err := manager.GetCurrentContext()
+	if err != nil {
+		return err
+	}
+	question := fmt.Sprintf("Remove %s?", dir)
+	if current != "" {
+		question = fmt.Sprintf("Unset context '%s' and remove %s?", current, dir)
+	}
+	if !yes && !ui.Confirm(question) {
+		return fmt.Errorf("kept %s: unsetting and removing it needs confirmation (pass --yes to skip it)", dir)
+	}
+
+	if err := unsetCurrentContext(); err != nil {
+		return err
+	}
+	if err := manager.CleanProjectDir(); err != nil {
+		return err
+	}
+	fmt.Printf("Removed %s\n", dir)
+	return nil
+}
+
+// createNewContext creates a context from the active config, from a built-in
+// skeleton when skeleton is non-nil or from a template when tmpl is non-nil.
+// With force, an existing context of the same name is replaced.
+func createNewContext(name, formatStr string, slugify, force bool, skeleton *context.SkeletonOptions, tmpl *contextTemplate) error {
 	// Parse and validate format
 	format, err := context.ParseFormat(formatStr)
 	if err != nil {
 		return err
 	}
 
-	manager, err := context.NewManager(inProject)
+	manager, err := newManager()
 	if err != nil {
 		return err
 	}
 
-	if err := manager.CreateContextWithFormat(name, format); err != nil {
+	if slugify || manager.GetConfig().Names.Slugify {
+		name = context.Slugify(name)
+	}
+
+	manager.SetForce(force)
+	switch {
+	case skeleton != nil:
+		err = manager.CreateContextFromSkeleton(name, format, *skeleton)
+	case tmpl != nil:
+		if err := promptTemplateVars(manager, tmpl); err != nil {
+			return err
+		}
+		err = manager.CreateContextFromTemplate(name, format, tmpl.Name, tmpl.Vars)
+	default:
+		err = manager.CreateContextWithFormat(name, format)
+	}
+	if err != nil {
 		return err
 	}
 
@@ -189,13 +512,20 @@ func createNewContext(name, formatStr string) error {
 	return nil
 }
 
-func deleteContext(name string) error {
-	manager, err := context.NewManager(inProject)
+// deleteContext deletes a context; a non-empty switchTo allows deleting the
+// current context by switching to it (or unsetting) first
+func deleteContext(name, switchTo string) error {
+	manager, err := newManager()
 	if err != nil {
 		return err
 	}
 
-	if err := manager.DeleteContext(name); err != nil {
+	if switchTo != "" {
+		err = manager.DeleteContextSwitchingTo(name, switchTo)
+	} else {
+		err = manager.DeleteContext(name)
+	}
+	if err != nil {
 		return err
 	}
 
@@ -203,45 +533,135 @@ func deleteContext(name string) error {
 	return nil
 }
 
+// editorFromEnv returns $EDITOR, falling back to vi
+func editorFromEnv() string {
+	if editor := os.Getenv("EDITOR"); editor != "" {
+		return editor
+	}
+	return "vi"
+}
+
 func editContext(name string) error {
-	manager, err := context.NewManager(inProject)
+	manager, err := newManager()
 	if err != nil {
 		return err
 	}
 
+	if err := manager.CheckWritable("edit context"); err != nil {
+		return err
+	}
+
 	// Get context to ensure it exists
 	ctx, err := manager.GetContext(name)
 	if err != nil {
 		return err
 	}
 
-	// Get editor from environment
-	editor := os.Getenv("EDITOR")
-	if editor == "" {
-		editor = "vi" // fallback to vi
+	if err := ctx.CheckModifiable("edit"); err != nil {
+		return err
+	}
+
+	// The editor owns the terminal, so no spinner; only the outcome is reported
+	progress := ui.NewProgress("Editing context")
+	if err := runEditor(ctx.FilePath); err != nil {
+		progress.Error("Failed to edit context")
+		return err
+	}
+
+	// Edits through occtx are sanctioned; record them in the checksum manifest
+	if err := manager.UpdateChecksum(ctx.Name); err != nil {
+		return err
+	}
+	if err := manager.RecordEdit(ctx.Name, ctx.Data); err != nil {
+		return err
+	}
+
+	progress.Success("Context '%s' edited successfully", name)
+	return nil
+}
+
+// editContextPath edits only the value at a key path of a context, in a
+// temporary file; the rest of the context file is left as it is
+func editContextPath(name, jsonPath string) error {
+	manager, err := newManager()
+	if err != nil {
+		return err
+	}
+
+	ctx, err := manager.GetContext(name)
+	if err != nil {
+		return err
+	}
+	edit, err := manager.BeginScopedEdit(ctx.Name, jsonPath)
+	if err != nil {
+		return err
+	}
+
+	extension := ".json"
+	if edit.JSONC {
+		extension = ".jsonc"
+	}
+	temp, err := os.CreateTemp("", "occtx-"+ctx.Name+"-"+edit.Path.String()+"-*"+extension)
+	if err != nil {
+		return err
+	}
+	tempPath := temp.Name()
+	_, err = temp.Write(append(edit.Value, '\n'))
+	if closeErr := temp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(tempPath)
+		return err
+	}
+
+	progress := ui.NewProgress("Editing context")
+	if err := runEditor(tempPath); err != nil {
+		os.Remove(tempPath)
+		progress.Error("Failed to edit context")
+		return err
+	}
+	value, err := os.ReadFile(tempPath)
+	if err != nil {
+		return err
+	}
+
+	changed, err := manager.FinishScopedEdit(edit, value)
+	if err != nil {
+		progress.Error("Failed to edit context")
+		return fmt.Errorf("%v\nYour edit is kept in %s", err, tempPath)
+	}
+	os.Remove(tempPath)
+	if !changed {
+		progress.Success("No changes to %s of context '%s'", edit.Path, ctx.Name)
+		return nil
 	}
 
-	// Show progress
-	progress := ui.NewProgressIndicator("Opening editor")
-	progress.Show()
+	if err := manager.UpdateChecksum(ctx.Name); err != nil {
+		return err
+	}
+	if err := manager.RecordEdit(ctx.Name, ctx.Data); err != nil {
+		return err
+	}
+	progress.Success("Edited %s of context '%s'", edit.Path, ctx.Name)
+	return nil
+}
 
-	// Open editor
-	cmd := exec.Command(editor, ctx.FilePath)
+// runEditor opens path in $EDITOR and waits for it to exit
+func runEditor(path string) error {
+	cmd := exec.CommandContext(commandCtx, editorFromEnv(), path)
 	cmd.Stdin = os.Stdin
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
 
 	if err := cmd.Run(); err != nil {
-		progress.Error("Failed to edit context")
-		return fmt.Errorf("failed to run editor: %v", err)
+		return fmt.Errorf("failed to run editor: %w", runError(err))
 	}
-
-	progress.Success(fmt.Sprintf("Context '%s' edited successfully", name))
 	return nil
 }
 
 func showContext(name string) error {
-	manager, err := context.NewManager(inProject)
+	manager, err := newManager()
 	if err != nil {
 		return err
 	}
@@ -261,8 +681,8 @@ func showContext(name string) error {
 	return nil
 }
 
-func exportContext(name string) error {
-	manager, err := context.NewManager(inProject)
+func showContextMeta(name string) error {
+	manager, err := newManager()
 	if err != nil {
 		return err
 	}
@@ -272,82 +692,267 @@ func exportContext(name string) error {
 		return err
 	}
 
-	// Read and output to stdout
-	data, err := os.ReadFile(ctx.FilePath)
+	// Metadata is only tracked for local contexts
+	var meta *context.ContextMeta
+	if !ctx.Shared {
+		if meta, err = manager.GetContextMeta(ctx.Name); err != nil {
+			return err
+		}
+	}
+
+	fmt.Printf("Name:    %s\n", ctx.Name)
+	fmt.Printf("Format:  %s\n", ctx.Format.DisplayName())
+	fmt.Printf("Path:    %s\n", ctx.FilePath)
+	if ctx.Mirror != "" {
+		fmt.Printf("Mirror:  %s (read-only)\n", ctx.Mirror)
+	} else if ctx.Shared {
+		fmt.Println("Shared:  yes (read-only)")
+	}
+	if localPath, ok := manager.LocalOverridePath(ctx.Name); ok {
+		fmt.Printf("Local:   %s\n", localPath)
+	}
+	if meta == nil {
+		fmt.Println("Source:  unknown")
+		return showContextDoc(manager, ctx.Name)
+	}
+	fmt.Printf("Source:  %s\n", meta.Source)
+	if !meta.CreatedAt.IsZero() {
+		fmt.Printf("Created: %s\n", meta.CreatedAt.Format("2006-01-02 15:04:05"))
+	}
+	if meta.LastUsed != nil {
+		fmt.Printf("Used:    %d time(s), last %s\n", meta.UseCount, meta.LastUsed.Format("2006-01-02 15:04:05"))
+	}
+	if meta.CostTier != "" {
+		fmt.Printf("Tier:    %s\n", meta.CostTier)
+	}
+	if len(meta.Tags) > 0 {
+		tags := make([]string, 0, len(meta.Tags))
+		for key, value := range meta.Tags {
+			tags = append(tags, key+"="+value)
+		}
+		sort.Strings(tags)
+		fmt.Printf("Tags:    %s\n", strings.Join(tags, ", "))
+	}
+	if len(meta.Env) > 0 {
+		keys := make([]string, 0, len(meta.Env))
+		for key := range meta.Env {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		fmt.Printf("Env:     %s\n", strings.Join(keys, ", "))
+	}
+	if len(meta.Requires) > 0 {
+		fmt.Printf("Needs:   %s\n", strings.Join(meta.Requires, ", "))
+	}
+
+	// Without an approval workflow every context would read as a draft
+	if !manager.GetConfig().Approval.Required {
+		return showContextDoc(manager, ctx.Name)
+	}
+	status, err := manager.ApprovalStatusOf(ctx)
 	if err != nil {
 		return err
 	}
-
-	fmt.Print(string(data))
-	return nil
+	switch status {
+	case context.StatusApproved:
+		fmt.Printf("Status:  approved by %s at %s\n", meta.Approval.By, meta.Approval.At.Format("2006-01-02 15:04:05"))
+	case context.StatusModified:
+		fmt.Printf("Status:  modified since approved by %s at %s\n", meta.Approval.By, meta.Approval.At.Format("2006-01-02 15:04:05"))
+	default:
+		fmt.Printf("Status:  %s\n", status)
+	}
+	return showContextDoc(manager, ctx.Name)
 }
 
-func importContext(name string) error {
-	manager, err := context.NewManager(inProject)
+// showContextDoc prints the description and rendered README of a context
+func showContextDoc(manager *context.Manager, name string) error {
+	doc, err := manager.GetContextDoc(name)
 	if err != nil {
 		return err
 	}
+	if doc.Description != "" {
+		fmt.Printf("About:   %s\n", doc.Description)
+	}
+	if doc.ReadmePath != "" {
+		fmt.Printf("README:  %s\n\n", doc.ReadmePath)
+		fmt.Print(ui.RenderMarkdown(doc.Readme))
+	}
+	return nil
+}
 
-	// Read from stdin
-	var input strings.Builder
-	scanner := bufio.NewScanner(os.Stdin)
-	for scanner.Scan() {
-		input.WriteString(scanner.Text())
-		input.WriteString("\n")
+// exportOptions builds the export transformation pipeline from flags
+func exportOptions(cmd *cobra.Command) (context.ExportOptions, error) {
+	var opts context.ExportOptions
+	opts.Redact, _ = cmd.Flags().GetBool("redact")
+	opts.Minify, _ = cmd.Flags().GetBool("minify")
+	opts.IncludeLocal, _ = cmd.Flags().GetBool("include-local")
+	opts.GitSafe, _ = cmd.Flags().GetBool("git-safe")
+
+	stripKeys, _ := cmd.Flags().GetStringSlice("strip-keys")
+	for _, expr := range stripKeys {
+		path, err := context.ParseKeyPath(expr)
+		if err != nil {
+			return opts, err
+		}
+		opts.StripKeys = append(opts.StripKeys, path)
 	}
 
-	if err := scanner.Err(); err != nil {
-		return fmt.Errorf("failed to read from stdin: %v", err)
+	return opts, nil
+}
+
+func exportContext(name string, opts context.ExportOptions) error {
+	manager, err := newManager()
+	if err != nil {
+		return err
 	}
 
-	jsonData := input.String()
-	if jsonData == "" {
-		return fmt.Errorf("no input provided")
+	data, err := manager.ExportContext(name, opts)
+	if err != nil {
+		return err
 	}
 
-	// Validate JSON
-	var data map[string]interface{}
-	if err := json.Unmarshal([]byte(jsonData), &data); err != nil {
-		return fmt.Errorf("invalid JSON: %v", err)
+	fmt.Print(string(data))
+	return nil
+}
+
+// importOptions builds the import checks from the occtx config, overridden by flags
+func importOptions(cmd *cobra.Command, cfg config.ImportConfig, mergeConfig config.MergeConfig) (context.ImportOptions, error) {
+	var opts context.ImportOptions
+
+	formatStr := cfg.Format
+	if formatStr == "" || cmd.Flags().Changed("format") {
+		formatStr, _ = cmd.Flags().GetString("format")
+	}
+	format, err := context.ParseFormat(formatStr)
+	if err != nil {
+		return opts, err
+	}
+	opts.Format = format
+
+	policies := []struct {
+		flag   string
+		value  string
+		target *context.Policy
+	}{
+		{"validate", cfg.Validate, &opts.Validate},
+		{"secrets", cfg.Secrets, &opts.Secrets},
+	}
+	for _, p := range policies {
+		value := p.value
+		if cmd.Flags().Changed(p.flag) {
+			value, _ = cmd.Flags().GetString(p.flag)
+		}
+		if *p.target, err = context.ParsePolicy(value); err != nil {
+			return opts, fmt.Errorf("--%s: %v", p.flag, err)
+		}
 	}
 
-	// Ensure directories exist
-	if err := manager.GetPaths().EnsureDirectories(inProject); err != nil {
-		return err
+	if merge, _ := cmd.Flags().GetBool("merge"); merge {
+		if opts.Merge, err = mergeOptions(cmd, mergeConfig); err != nil {
+			return opts, err
+		}
 	}
 
-	// Write to context file
-	contextsDir := manager.GetPaths().GetContextsDir(inProject)
-	contextPath := filepath.Join(contextsDir, name+".json")
+	return opts, nil
+}
 
-	// Check if context already exists
-	if _, err := os.Stat(contextPath); err == nil {
-		return fmt.Errorf("context '%s' already exists", name)
+// mergeOptions builds the merge semantics from the occtx config, overridden by flags
+func mergeOptions(cmd *cobra.Command, cfg config.MergeConfig) (*context.MergeOptions, error) {
+	overrides := []struct {
+		flag   string
+		target *string
+	}{
+		{"merge-arrays", &cfg.Arrays},
+		{"merge-nulls", &cfg.Nulls},
+		{"merge-delete", &cfg.DeleteSentinel},
+	}
+	for _, o := range overrides {
+		if cmd.Flags().Changed(o.flag) {
+			*o.target, _ = cmd.Flags().GetString(o.flag)
+		}
 	}
 
-	// Format and write JSON
-	formattedData, err := json.MarshalIndent(data, "", "  ")
+	opts, err := context.ParseMergeOptions(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("merge: %v", err)
+	}
+	return &opts, nil
+}
+
+func importContext(cmd *cobra.Command, name string) error {
+	manager, err := newManager()
 	if err != nil {
 		return err
 	}
+	force, _ := cmd.Flags().GetBool("force")
+	manager.SetForce(force)
 
-	// Write atomically
-	tempPath := contextPath + ".tmp"
-	if err := os.WriteFile(tempPath, formattedData, 0644); err != nil {
+	opts, err := importOptions(cmd, manager.GetConfig().Import, manager.GetConfig().Merge)
+	if err != nil {
 		return err
 	}
 
-	if err := os.Rename(tempPath, contextPath); err != nil {
+	if err := manager.CheckWritable("import context"); err != nil {
 		return err
 	}
 
+	source := context.Source{Kind: context.SourceImport, From: "stdin"}
+	var jsonData string
+	var extracted *context.OpencodeImport
+	if path, _ := cmd.Flags().GetString("from-opencode"); path != "" {
+		if extracted, err = context.ReadOpencodeExport(path); err != nil {
+			return err
+		}
+		jsonData = string(extracted.Data)
+		source.From = path
+	} else {
+		// Read from stdin
+		var input strings.Builder
+		scanner := bufio.NewScanner(os.Stdin)
+		for scanner.Scan() {
+			input.WriteString(scanner.Text())
+			input.WriteString("\n")
+		}
+
+		if err := scanner.Err(); err != nil {
+			return fmt.Errorf("failed to read from stdin: %v", err)
+		}
+
+		jsonData = input.String()
+		if jsonData == "" {
+			return fmt.Errorf("no input provided")
+		}
+	}
+
+	if dryRun(cmd) {
+		return planImport(cmd, manager, name, []byte(jsonData), opts)
+	}
+
 	printer := ui.NewColorPrinter()
-	printer.PrintSuccess("Context '%s' imported successfully\n", name)
+	warnings, err := manager.ImportContextWithOptions(name, []byte(jsonData), source, opts)
+	for _, warning := range warnings {
+		ui.Warnf("%s", warning)
+	}
+	if err != nil {
+		return err
+	}
+
+	if opts.Merge != nil {
+		printer.PrintSuccess("Merged into context '%s'\n", name)
+	} else {
+		printer.PrintSuccess("Context '%s' imported successfully (%s format)\n", name, opts.Format.DisplayName())
+	}
+	if extracted != nil {
+		fmt.Printf("Read the %s:\n", extracted.Kind)
+		for _, note := range extracted.Notes {
+			fmt.Printf("  - %s\n", note)
+		}
+	}
 	return nil
 }
 
 func renameContext(oldName, newName string) error {
-	manager, err := context.NewManager(inProject)
+	manager, err := newManager()
 	if err != nil {
 		return err
 	}
@@ -360,28 +965,275 @@ func renameContext(oldName, newName string) error {
 	return nil
 }
 
-func listContexts() error {
-	manager, err := context.NewManager(inProject)
+// listFilterFromFlags builds the listing filter; --format only filters when given explicitly
+func listFilterFromFlags(cmd *cobra.Command) (context.ListFilter, error) {
+	var filter context.ListFilter
+
+	filter.NameGlob, _ = cmd.Flags().GetString("name-glob")
+
+	if cmd.Flags().Changed("format") {
+		formatStr, _ := cmd.Flags().GetString("format")
+		format, err := context.ParseFormat(formatStr)
+		if err != nil {
+			return filter, err
+		}
+		filter.Format = &format
+	}
+
+	exprs, _ := cmd.Flags().GetStringArray("filter")
+	for _, expr := range exprs {
+		field, err := context.ParseFieldFilter(expr)
+		if err != nil {
+			return filter, err
+		}
+		filter.Fields = append(filter.Fields, field)
+	}
+
+	return filter, nil
+}
+
+// contextListEntry is one context in `-o json` listing output
+type contextListEntry struct {
+	Name      string          `json:"name"`
+	Format    string          `json:"format"`
+	Current   bool            `json:"current"`
+	Shared    bool            `json:"shared"`
+	Mirror    string          `json:"mirror,omitempty"` // Mirror a shared context comes from
+	Path      string          `json:"path"`
+	Scope     string          `json:"scope,omitempty"` // With --all-scopes: global or project
+	Source    *context.Source `json:"source,omitempty"`
+	Freshness string          `json:"freshness,omitempty"` // Of a local copy of a shared or mirrored context
+	UseCount  int             `json:"use_count"`
+	LastUsed  *time.Time      `json:"last_used,omitempty"`
+	Model     string          `json:"model,omitempty"`     // With --long
+	Providers []string        `json:"providers,omitempty"` // With --long
+}
+
+// listOptions controls how a listing is ordered and how much it shows
+type listOptions struct {
+	long      bool             // Show model, providers, use counts and last-used times
+	order     context.ListSort // Order of the contexts
+	allScopes bool             // List global, project and shared contexts together
+	numbered  bool             // Number the contexts for 'occtx <n>'
+}
+
+// addListFlags registers the listing filters and options; -o is registered
+// by each command, since root shares it with --dry-run
+func addListFlags(flags *pflag.FlagSet) {
+	flags.StringArray("filter", nil, "Only list contexts whose content matches key.path=value (repeatable)")
+	flags.String("name-glob", "", "Only list contexts whose name matches a glob (e.g. 'work-*')")
+	flags.BoolP("long", "l", false, "List contexts with how often and when they were last switched to")
+	flags.String("sort", "name", "Listing order (name, used, frequency)")
+	flags.Bool("all-scopes", false, "List global, project and shared contexts together, grouped by scope")
+}
+
+// runList lists contexts as the listing flags of cmd ask; numbered listings
+// number them for 'occtx <n>'
+func runList(cmd *cobra.Command, numbered bool) error {
+	filter, err := listFilterFromFlags(cmd)
 	if err != nil {
 		return err
 	}
+	output, _ := cmd.Flags().GetString("output")
+	opts := listOptions{numbered: numbered}
+	opts.long, _ = cmd.Flags().GetBool("long")
+	opts.allScopes, _ = cmd.Flags().GetBool("all-scopes")
+	sortBy, _ := cmd.Flags().GetString("sort")
+	if opts.order, err = context.ParseListSort(sortBy); err != nil {
+		return usageErrorf("%v", err)
+	}
+	if opts.allScopes && cmd.Flags().Changed("in-project") {
+		return usageErrorf("--all-scopes can't be combined with --in-project")
+	}
+	return listContexts(filter, output, opts)
+}
 
-	contexts, err := manager.ListContexts()
+// scopeListing is the listing of one scope's contexts
+type scopeListing struct {
+	useProject bool
+	scope      string // Prefix of the scope's qualified names
+	contexts   []*context.Context
+	current    string
+	usage      map[string]context.Usage
+	sources    map[string]*context.Source
+	summaries  map[string]*context.Summary  // With --long; nil for unreadable contexts
+	freshness  map[string]context.Freshness // Of local copies of shared or mirrored contexts
+}
+
+// loadScopeListing collects a scope's contexts and what the listing shows
+// about them
+func loadScopeListing(useProject bool, filter context.ListFilter, output string, opts listOptions) (*scopeListing, error) {
+	manager, err := newManagerForScope(useProject)
 	if err != nil {
-		return err
+		return nil, err
+	}
+
+	contexts, err := manager.FilterContexts(filter)
+	if err != nil {
+		return nil, err
 	}
+	listing := &scopeListing{useProject: useProject, scope: manager.ScopeName(), contexts: contexts}
 
 	// Get current context for highlighting
-	currentContext, _ := manager.GetCurrentContext()
+	listing.current, _ = manager.GetCurrentContext()
+
+	listing.usage = map[string]context.Usage{}
+	if output == "json" || opts.long || opts.order != context.SortName {
+		if listing.usage, err = manager.GetUsage(); err != nil {
+			return nil, err
+		}
+	}
+	context.SortContexts(contexts, opts.order, listing.usage)
+
+	if opts.long {
+		listing.summaries = make(map[string]*context.Summary, len(contexts))
+		for _, ctx := range contexts {
+			listing.summaries[ctx.Name], _ = context.SummarizeContext(ctx)
+		}
+	}
+
+	listing.freshness, _ = manager.ContextFreshness(contexts)
+
+	listing.sources = make(map[string]*context.Source)
+	if output == "json" || verbose > 0 {
+		allMeta, _ := manager.ListContextMeta()
+		for _, ctx := range contexts {
+			if meta := allMeta[ctx.Name]; meta != nil && !ctx.Shared && meta.Source.Kind != "" {
+				listing.sources[ctx.Name] = &meta.Source
+			}
+		}
+	}
+	return listing, nil
+}
+
+// notes returns what the text listing shows after each name: how local
+// copies relate to their source, and in verbose listings where each context
+// came from and in long listings what it configures and how it has been used
+func (l *scopeListing) notes(opts listOptions) map[string]string {
+	notes := make(map[string]string, len(l.sources)+len(l.freshness))
+	for name, freshness := range l.freshness {
+		notes[name] = string(freshness)
+	}
+	for name, source := range l.sources {
+		if notes[name] != "" {
+			notes[name] += ", " + source.String()
+		} else {
+			notes[name] = source.String()
+		}
+	}
+	if opts.long {
+		for _, ctx := range l.contexts {
+			note := "never used"
+			if used := l.usage[ctx.Name]; used.LastUsed != nil {
+				note = fmt.Sprintf("used %d time(s), last %s", used.Count, formatAge(*used.LastUsed))
+			}
+			if summary := summaryNote(l.summaries[ctx.Name]); summary != "" {
+				note = summary + ", " + note
+			}
+			if notes[ctx.Name] != "" {
+				note = notes[ctx.Name] + ", " + note
+			}
+			notes[ctx.Name] = note
+		}
+	}
+	return notes
+}
+
+// summaryNote describes a context's model and providers for a long listing
+func summaryNote(summary *context.Summary) string {
+	if summary == nil {
+		return "unreadable"
+	}
+	var parts []string
+	if summary.Model != "" {
+		parts = append(parts, "model "+summary.Model)
+	}
+	if len(summary.Providers) > 0 {
+		parts = append(parts, "providers "+strings.Join(summary.Providers, "+"))
+	}
+	return strings.Join(parts, ", ")
+}
+
+func listContexts(filter context.ListFilter, output string, opts listOptions) error {
+	if output != "text" && output != "json" {
+		return fmt.Errorf("invalid output format '%s'. Supported formats: text, json", output)
+	}
+
+	scopes := []bool{inProject}
+	if opts.allScopes {
+		scopes = []bool{false, true}
+	}
+	var listings []*scopeListing
+	for _, useProject := range scopes {
+		listing, err := loadScopeListing(useProject, filter, output, opts)
+		if err != nil {
+			return err
+		}
+		listings = append(listings, listing)
+	}
+
+	if output == "json" {
+		entries := []contextListEntry{}
+		for _, listing := range listings {
+			for _, ctx := range listing.contexts {
+				entry := contextListEntry{
+					Name:     ctx.Name,
+					Format:   ctx.Format.String(),
+					Current:  ctx.Name == listing.current,
+					Shared:   ctx.Shared,
+					Mirror:   ctx.Mirror,
+					Path:     ctx.FilePath,
+					Source:   listing.sources[ctx.Name],
+					UseCount: listing.usage[ctx.Name].Count,
+					LastUsed: listing.usage[ctx.Name].LastUsed,
+				}
+				entry.Freshness = string(listing.freshness[ctx.Name])
+				if summary := listing.summaries[ctx.Name]; summary != nil {
+					entry.Model = summary.Model
+					entry.Providers = summary.Providers
+				}
+				if opts.allScopes {
+					entry.Scope = listing.scope
+				}
+				entries = append(entries, entry)
+			}
+		}
+
+		data, err := json.MarshalIndent(entries, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+		return nil
+	}
 
-	// Use the new formatter
 	formatter := ui.NewContextListFormatter()
-	formatter.FormatContextList(contexts, currentContext, inProject)
+	if opts.allScopes {
+		groups := make([]ui.ContextGroup, 0, len(listings))
+		for _, listing := range listings {
+			groups = append(groups, ui.ContextGroup{
+				Scope:    listing.scope,
+				Project:  listing.useProject,
+				Contexts: listing.contexts,
+				Current:  listing.current,
+				Notes:    listing.notes(opts),
+			})
+		}
+		formatter.FormatContextGroups(groups)
+		return nil
+	}
+
+	listing := listings[0]
+	if opts.numbered {
+		formatter.Numbered = true
+		recordListing(listing.contexts)
+	}
+	formatter.FormatContextListWithNotes(listing.contexts, listing.current, inProject, listing.notes(opts))
 
 	// Show helpful hints if not using project level
 	if !inProject {
 		// Check if project contexts exist
-		projectManager, _ := context.NewManager(true)
+		projectManager, _ := newManagerForScope(true)
 		if projectManager != nil {
 			projectContexts, _ := projectManager.ListContexts()
 			formatter.ShowHints(inProject, len(projectContexts) > 0)
@@ -391,8 +1243,75 @@ func listContexts() error {
 	return nil
 }
 
-func switchToPreviousContext() error {
-	manager, err := context.NewManager(inProject)
+// switchOptions controls what a switch does with an active config occtx didn't write
+type switchOptions struct {
+	force  bool   // Overwrite it
+	saveAs string // Save it as a new context first
+	yes    bool   // Don't ask before switching to a guarded cost tier
+
+	skipRequires  bool // Switch even if the machine lacks what the context requires
+	createMissing bool // Create a missing context from the active config, then switch to it
+	review        bool // Show the changes to the active config and ask first
+}
+
+// switchOptionsFromFlags reads the switch flags: --force, --save-as, --yes,
+// --skip-requires, --create-missing and --review
+func switchOptionsFromFlags(cmd *cobra.Command) switchOptions {
+	var opts switchOptions
+	opts.force, _ = cmd.Flags().GetBool("force")
+	opts.saveAs, _ = cmd.Flags().GetString("save-as")
+	opts.yes, _ = cmd.Flags().GetBool("yes")
+	opts.skipRequires, _ = cmd.Flags().GetBool("skip-requires")
+	opts.createMissing, _ = cmd.Flags().GetBool("create-missing")
+	opts.review, _ = cmd.Flags().GetBool("review")
+	return opts
+}
+
+// newSwitchManager creates a manager and applies opts before a switch
+func newSwitchManager(opts switchOptions) (*context.Manager, error) {
+	return newSwitchManagerForScope(inProject, opts)
+}
+
+// newSwitchManagerForScope creates a manager for the given scope and applies
+// opts before a switch
+func newSwitchManagerForScope(useProject bool, opts switchOptions) (*context.Manager, error) {
+	manager, err := newManagerForScope(useProject)
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.saveAs != "" {
+		foreign, err := manager.ActiveIsForeign()
+		if err != nil {
+			return nil, err
+		}
+		if foreign {
+			if err := manager.CreateContext(opts.saveAs); err != nil {
+				return nil, fmt.Errorf("failed to save active config as '%s': %v", opts.saveAs, err)
+			}
+			ui.NewColorPrinter().PrintInfo("Saved active config as context '%s'\n", opts.saveAs)
+		}
+	}
+
+	manager.SetForce(opts.force)
+	manager.SetSkipRequirements(opts.skipRequires)
+	manager.SetConfirmHandler(func(question string) bool {
+		return opts.yes || ui.Confirm(question)
+	})
+	manager.SetReview(opts.review)
+	manager.SetReviewHandler(func(name string, changes []context.ContextChange) bool {
+		if opts.yes {
+			return true
+		}
+		fmt.Fprintf(os.Stderr, "Switching to '%s' changes the active config:\n", name)
+		printChangesTo(os.Stderr, ui.NewColorPrinter(), changes)
+		return ui.Confirm("Switch?")
+	})
+	return manager, nil
+}
+
+func switchToPreviousContext(opts switchOptions) error {
+	manager, err := newSwitchManager(opts)
 	if err != nil {
 		return err
 	}
@@ -408,17 +1327,58 @@ func switchToPreviousContext() error {
 	return nil
 }
 
-func switchToContext(name string) error {
-	manager, err := context.NewManager(inProject)
+// createMissingContext offers to snapshot the active config as the missing
+// context name, creating it without asking with --create-missing. A name
+// close to an existing one is more likely a typo, so it is only created with
+// the flag. It reports whether the context was created.
+func createMissingContext(manager *context.Manager, notFound *context.NotFoundError, opts switchOptions) (bool, error) {
+	if notFound.Archived {
+		return false, nil
+	}
+	if _, err := os.Stat(manager.GetPaths().GetActiveConfigPath(inProject)); err != nil {
+		return false, nil
+	}
+	if !opts.createMissing {
+		question := fmt.Sprintf("Context '%s' doesn't exist. Create it from the active config and switch to it?", notFound.Name)
+		if len(notFound.Suggestions) > 0 || !ui.Confirm(question) {
+			return false, nil
+		}
+	}
+
+	if err := manager.CreateContext(notFound.Name); err != nil {
+		return false, fmt.Errorf("failed to create context '%s': %v", notFound.Name, err)
+	}
+	ui.NewColorPrinter().PrintInfo("Created context '%s' from the active config\n", notFound.Name)
+	return true, nil
+}
+
+func switchToContext(name string, opts switchOptions) error {
+	manager, err := newSwitchManager(opts)
 	if err != nil {
 		return err
 	}
 
-	if err := manager.SwitchToContext(name); err != nil {
+	err = manager.SwitchToContext(name)
+	var notFound *context.NotFoundError
+	if errors.As(err, &notFound) {
+		created, createErr := createMissingContext(manager, notFound, opts)
+		if createErr != nil {
+			return createErr
+		}
+		if created {
+			err = manager.SwitchToContext(name)
+		}
+	}
+	if err != nil {
 		return err
 	}
 
+	// Report the stored name, which may differ in case from the input
+	current, _ := manager.GetCurrentContext()
 	printer := ui.NewColorPrinter()
-	printer.PrintSuccess("Switched to context: %s\n", name)
+	printer.PrintSuccess("Switched to context: %s\n", current)
+	if session := context.SessionContext(); session != "" {
+		ui.Warnf("this terminal still uses session context '%s' (%s)", session, context.SessionContextEnvVar)
+	}
 	return nil
 }
@@ -0,0 +1,112 @@
+package cmd
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// scopePrefixes are the prefixes of scope-qualified context names, such as
+// proj:demo, and whether they select the project scope. Context names can't
+// contain ':', so a qualified name is never mistaken for a plain one.
+var scopePrefixes = map[string]bool{
+	"global":  false,
+	"project": true,
+	"proj":    true,
+}
+
+// contextFlags are the flags whose value is a context name
+var contextFlags = []string{"new", "import", "delete", "edit", "show", "export", "switch-to"}
+
+// splitScopedName splits a scope-qualified name into its scope and the
+// plain name; ok is false for names without a known prefix
+func splitScopedName(name string) (useProject bool, plain string, ok bool) {
+	prefix, plain, found := strings.Cut(name, ":")
+	if !found {
+		return false, name, false
+	}
+	useProject, ok = scopePrefixes[prefix]
+	if !ok {
+		return false, name, false
+	}
+	return useProject, plain, true
+}
+
+// contextArgCount returns how many leading arguments of cmd are context
+// names, judged by how they are completed; -1 means all of them
+func contextArgCount(cmd *cobra.Command) int {
+	if cmd.ValidArgsFunction == nil {
+		return 0
+	}
+	switch reflect.ValueOf(cmd.ValidArgsFunction).Pointer() {
+	case reflect.ValueOf(completeContextArg).Pointer():
+		return 1
+	case reflect.ValueOf(completeContextArgs).Pointer(), reflect.ValueOf(completeArchivedArgs).Pointer():
+		return -1
+	}
+	return 0
+}
+
+// applyScopedNames strips the scope prefix from context names given as
+// arguments or flag values and selects that scope for the command, as if
+// --in-project had been given or left out. All qualified names of a command
+// must agree with each other and with --in-project.
+func applyScopedNames(cmd *cobra.Command, args []string) error {
+	var scoped []string
+	selected := -1 // Unset, else 0 for global and 1 for project
+	use := func(name string) (string, error) {
+		useProject, plain, ok := splitScopedName(name)
+		if !ok {
+			return name, nil
+		}
+		scope := 0
+		if useProject {
+			scope = 1
+		}
+		if selected >= 0 && scope != selected {
+			return "", usageErrorf("'%s' and '%s' name different scopes; run one command per scope", scoped[0], name)
+		}
+		if cmd.Flags().Changed("in-project") && useProject != inProject {
+			return "", usageErrorf("'%s' contradicts --in-project", name)
+		}
+		selected = scope
+		scoped = append(scoped, name)
+		return plain, nil
+	}
+
+	count := contextArgCount(cmd)
+	if dash := cmd.ArgsLenAtDash(); dash >= 0 && (count < 0 || count > dash) {
+		count = dash
+	}
+	if count < 0 || count > len(args) {
+		count = len(args)
+	}
+	for i := 0; i < count; i++ {
+		plain, err := use(args[i])
+		if err != nil {
+			return err
+		}
+		// RunE receives the same slice
+		args[i] = plain
+	}
+
+	for _, name := range contextFlags {
+		flag := cmd.Flags().Lookup(name)
+		if flag == nil || !flag.Changed || flag.Value.Type() != "string" {
+			continue
+		}
+		plain, err := use(flag.Value.String())
+		if err != nil {
+			return err
+		}
+		if err := flag.Value.Set(plain); err != nil {
+			return err
+		}
+	}
+
+	if selected >= 0 {
+		inProject = selected == 1
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/hungthai1401/occtx/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+// searchCmd finds contexts by content
+var searchCmd = &cobra.Command{
+	Use:   "search <query>...",
+	Short: "Find contexts by their keys and values",
+	Long: `Search lists the contexts whose content matches a query, with the key
+paths that matched. Terms are matched case-insensitively and must all match:
+free text matches any key or value containing it, and key:text matches the
+keys and values below a key of that name.
+
+The interactive picker uses the same search as you type; with fzf, set
+"interactive.fzf.content_search" in the occtx config to use it there too.
+
+Examples:
+  occtx search old-proxy.internal        # The context still using the old proxy
+  occtx search provider:openai
+  occtx search model:sonnet agent:build
+  occtx search provider:ollama -o json`,
+	RunE: runSearch,
+}
+
+func init() {
+	searchCmd.Flags().Bool("names", false, "Print only the names of matching contexts, also matching names; an empty query lists every context")
+	searchCmd.Flags().StringP("output", "o", "text", "Output format (text, json)")
+	rootCmd.AddCommand(searchCmd)
+}
+
+func runSearch(cmd *cobra.Command, args []string) error {
+	output, err := planOutput(cmd)
+	if err != nil {
+		return err
+	}
+	query := strings.TrimSpace(strings.Join(args, " "))
+	names, _ := cmd.Flags().GetBool("names")
+	if query == "" && !names {
+		return usageErrorf("specify a search query")
+	}
+
+	manager, err := newManager()
+	if err != nil {
+		return err
+	}
+
+	matches, err := manager.NewContentIndex().Search(query)
+	if err != nil {
+		return err
+	}
+
+	if names {
+		// The picker's list: name matches and content matches, or everything
+		contexts, err := manager.ListContexts()
+		if err != nil {
+			return err
+		}
+		found := make(map[string]bool, len(matches))
+		for _, match := range matches {
+			found[match.Context] = true
+		}
+		var listed []string
+		for _, ctx := range contexts {
+			if query == "" || found[ctx.Name] || strings.Contains(strings.ToLower(ctx.Name), strings.ToLower(query)) {
+				listed = append(listed, ctx.Name)
+			}
+		}
+		sort.Strings(listed)
+		for _, name := range listed {
+			fmt.Println(name)
+		}
+		return nil
+	}
+
+	if output == "json" {
+		data, err := json.MarshalIndent(matches, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%s\n", data)
+		return nil
+	}
+
+	if len(matches) == 0 {
+		fmt.Fprintf(os.Stderr, "No contexts match '%s'\n", query)
+		return nil
+	}
+	printer := ui.NewColorPrinter()
+	for _, match := range matches {
+		printer.PrintInfo("%s\n", match.Context)
+		for _, path := range match.Paths {
+			fmt.Printf("    %s\n", path)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,118 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+
+	"github.com/hungthai1401/occtx/internal/context"
+	"github.com/hungthai1401/occtx/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+// currentCmd prints the context in effect for this terminal
+var currentCmd = &cobra.Command{
+	Use:   "current",
+	Short: "Show the context in effect for this terminal",
+	Long: `Current prints the session context (` + context.SessionContextEnvVar + `) when this
+terminal was started by 'occtx shell' or 'occtx exec', and the global current
+context otherwise. 'occtx -c' behaves the same way.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return showCurrentContext()
+	},
+}
+
+// execCmd runs a command with a context, isolated from other terminals
+var execCmd = &cobra.Command{
+	Use:   "exec <name> -- <command> [args...]",
+	Short: "Run a command with a context without switching globally",
+	Long: `Exec writes the context to a private temporary file and runs the command
+with ` + context.OpencodeConfigEnvVar + ` pointing at it, leaving the global opencode.json
+and other terminals untouched. The file is removed when the command exits.
+
+Examples:
+  occtx exec work -- opencode
+  occtx exec personal -- opencode run "summarize README.md"`,
+	Args:              cobra.MinimumNArgs(2),
+	ValidArgsFunction: completeContextArg,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		command := args[1:]
+		if command[0] == "--" {
+			command = command[1:]
+		}
+		if len(command) == 0 {
+			return fmt.Errorf("no command given")
+		}
+		return runInSession(args[0], command[0], command[1:])
+	},
+}
+
+// shellCmd starts a shell with a context, isolated from other terminals
+var shellCmd = &cobra.Command{
+	Use:   "shell <name>",
+	Short: "Start a shell using a context for this terminal only",
+	Long: `Shell starts $SHELL with the context active for everything run inside it,
+via a private temporary config file and ` + context.OpencodeConfigEnvVar + `. Exit the shell
+to end the session.
+
+Examples:
+  occtx shell work`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeContextArg,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		shell := os.Getenv("SHELL")
+		if shell == "" {
+			shell = "/bin/sh"
+			if runtime.GOOS == "windows" {
+				shell = os.Getenv("COMSPEC")
+			}
+		}
+
+		ui.Progressf("Starting %s with context '%s' (exit to leave)", shell, args[0])
+		return runInSession(args[0], shell, nil)
+	},
+}
+
+func init() {
+	// Everything after the context name belongs to the command
+	execCmd.Flags().SetInterspersed(false)
+
+	rootCmd.AddCommand(currentCmd)
+	rootCmd.AddCommand(execCmd)
+	rootCmd.AddCommand(shellCmd)
+}
+
+// runInSession runs name with the session config for context, exiting with
+// the command's exit code so occtx is transparent in scripts
+func runInSession(contextName, name string, args []string) error {
+	manager, err := newManager()
+	if err != nil {
+		return err
+	}
+
+	session, err := manager.NewSessionConfig(contextName)
+	if err != nil {
+		return err
+	}
+	defer session.Remove()
+
+	command := exec.Command(name, args...)
+	command.Env = append(os.Environ(), session.Env()...)
+	command.Stdin = os.Stdin
+	command.Stdout = os.Stdout
+	command.Stderr = os.Stderr
+
+	err = command.Run()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		session.Remove()
+		os.Exit(exitErr.ExitCode())
+	}
+	if err != nil {
+		return fmt.Errorf("failed to run %s: %v", name, err)
+	}
+	return nil
+}
@@ -0,0 +1,128 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/hungthai1401/occtx/internal/context"
+	"github.com/hungthai1401/occtx/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+// settingsCmd moves occtx's own configuration between machines
+var settingsCmd = &cobra.Command{
+	Use:   "settings [export|import]",
+	Short: "Export or import occtx's own configuration",
+	Long: `Settings exports the occtx config file, the pinned default context and
+context metadata (sources and approvals) as one JSON bundle, and imports it
+on another machine. Contexts themselves are not included: restore them first
+(e.g. with --import) so their pins and metadata can be applied.
+
+Examples:
+  occtx settings export > occtx-settings.json
+  occtx settings export -o occtx-settings.json
+  occtx settings import occtx-settings.json
+  occtx settings import --force < occtx-settings.json`,
+	Args: subcommandArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return cmd.Help()
+	},
+}
+
+var settingsExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Write the settings bundle to stdout or a file",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		manager, err := newManager()
+		if err != nil {
+			return err
+		}
+
+		bundle, err := manager.ExportSettings()
+		if err != nil {
+			return err
+		}
+
+		data, err := json.MarshalIndent(bundle, "", "  ")
+		if err != nil {
+			return err
+		}
+		data = append(data, '\n')
+
+		output, _ := cmd.Flags().GetString("output")
+		if output == "" || output == "-" {
+			_, err = cmd.OutOrStdout().Write(data)
+			return err
+		}
+		if err := os.WriteFile(output, data, 0644); err != nil {
+			return err
+		}
+
+		printer := ui.NewColorPrinter()
+		printer.PrintSuccess("Settings exported to %s\n", output)
+		return nil
+	},
+}
+
+var settingsImportCmd = &cobra.Command{
+	Use:   "import [file]",
+	Short: "Apply a settings bundle (from stdin without a file)",
+	Args:  cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		manager, err := newManager()
+		if err != nil {
+			return err
+		}
+
+		var data []byte
+		if file := optionalArg(args); file != "" && file != "-" {
+			data, err = os.ReadFile(file)
+		} else {
+			data, err = io.ReadAll(cmd.InOrStdin())
+		}
+		if err != nil {
+			return err
+		}
+
+		bundle, err := context.ParseSettingsBundle(data)
+		if err != nil {
+			return err
+		}
+
+		force, _ := cmd.Flags().GetBool("force")
+		result, err := manager.ImportSettings(bundle, force)
+		if err != nil {
+			return err
+		}
+
+		printer := ui.NewColorPrinter()
+		if result.ConfigWritten {
+			printer.PrintSuccess("Restored occtx config\n")
+		}
+		if result.Default != "" {
+			printer.PrintSuccess("Default context set to '%s'\n", result.Default)
+		}
+		if result.Metadata > 0 {
+			printer.PrintSuccess("Restored metadata of %d context(s)\n", result.Metadata)
+		}
+		if len(result.Skipped) > 0 {
+			ui.Warnf("skipped %d context(s) that don't exist here: %v", len(result.Skipped), result.Skipped)
+		}
+		if !result.ConfigWritten && result.Default == "" && result.Metadata == 0 {
+			fmt.Println("Nothing to import")
+		}
+		return nil
+	},
+}
+
+func init() {
+	settingsExportCmd.Flags().StringP("output", "o", "", "Write the bundle to a file instead of stdout")
+	settingsImportCmd.Flags().Bool("force", false, "Replace an existing occtx config that differs from the bundle")
+
+	settingsCmd.AddCommand(settingsExportCmd)
+	settingsCmd.AddCommand(settingsImportCmd)
+	rootCmd.AddCommand(settingsCmd)
+}
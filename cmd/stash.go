@@ -0,0 +1,169 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/hungthai1401/occtx/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+// stashCmd represents the stash command for parking the active config
+var stashCmd = &cobra.Command{
+	Use:   "stash [save|list|pop|apply|drop] [name]",
+	Short: "Stash changes to the active config",
+	Long: `Stash parks the live opencode.json without creating a formal context,
+mirroring git stash. Saving restores the active config from the current
+context; pop and apply bring a stash back.
+
+Stashes can be referenced by name, position (0 is the newest) or stash@{n}.
+
+Examples:
+  occtx stash                 # Stash the active config
+  occtx stash save experiment # Stash under a name
+  occtx stash list            # List stashes
+  occtx stash pop             # Apply and drop the newest stash
+  occtx stash apply stash@{1} # Apply a stash and keep it
+  occtx stash drop experiment # Delete a stash`,
+	Args: subcommandArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return stashSave("")
+	},
+}
+
+var stashSaveCmd = &cobra.Command{
+	Use:   "save [name]",
+	Short: "Stash the active config",
+	Args:  cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return stashSave(optionalArg(args))
+	},
+}
+
+var stashListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List stashes",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return stashList()
+	},
+}
+
+var stashPopCmd = &cobra.Command{
+	Use:   "pop [name]",
+	Short: "Apply a stash and remove it",
+	Args:  cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		manager, err := newManager()
+		if err != nil {
+			return err
+		}
+
+		entry, err := manager.PopStash(optionalArg(args))
+		if err != nil {
+			return err
+		}
+
+		printer := ui.NewColorPrinter()
+		printer.PrintSuccess("Restored and dropped stash '%s'\n", entry.Name)
+		return nil
+	},
+}
+
+var stashApplyCmd = &cobra.Command{
+	Use:   "apply [name]",
+	Short: "Apply a stash and keep it",
+	Args:  cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		manager, err := newManager()
+		if err != nil {
+			return err
+		}
+
+		entry, err := manager.ApplyStash(optionalArg(args))
+		if err != nil {
+			return err
+		}
+
+		printer := ui.NewColorPrinter()
+		printer.PrintSuccess("Applied stash '%s'\n", entry.Name)
+		return nil
+	},
+}
+
+var stashDropCmd = &cobra.Command{
+	Use:   "drop [name]",
+	Short: "Remove a stash",
+	Args:  cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		manager, err := newManager()
+		if err != nil {
+			return err
+		}
+
+		entry, err := manager.DropStash(optionalArg(args))
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("Dropped stash '%s'\n", entry.Name)
+		return nil
+	},
+}
+
+func init() {
+	stashCmd.AddCommand(stashSaveCmd, stashListCmd, stashPopCmd, stashApplyCmd, stashDropCmd)
+	rootCmd.AddCommand(stashCmd)
+}
+
+func stashSave(name string) error {
+	manager, err := newManager()
+	if err != nil {
+		return err
+	}
+
+	entry, err := manager.StashSave(name)
+	if err != nil {
+		return err
+	}
+
+	printer := ui.NewColorPrinter()
+	printer.PrintSuccess("Saved active config to stash '%s'\n", entry.Name)
+	if entry.Context != "" {
+		fmt.Printf("Active config restored from context: %s\n", entry.Context)
+	}
+	return nil
+}
+
+func stashList() error {
+	manager, err := newManager()
+	if err != nil {
+		return err
+	}
+
+	entries, err := manager.ListStashes()
+	if err != nil {
+		return err
+	}
+
+	if len(entries) == 0 {
+		fmt.Println("No stashes found")
+		return nil
+	}
+
+	for i, entry := range entries {
+		on := "no context"
+		if entry.Context != "" {
+			on = "on " + entry.Context
+		}
+		fmt.Printf("stash@{%d}: %s (%s, %s)\n", i, entry.Name, on, entry.CreatedAt.Format("2006-01-02 15:04:05"))
+	}
+	return nil
+}
+
+// optionalArg returns the first argument or an empty string
+func optionalArg(args []string) string {
+	if len(args) == 0 {
+		return ""
+	}
+	return args[0]
+}
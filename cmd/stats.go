@@ -0,0 +1,195 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/hungthai1401/occtx/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+// statsCmd shows usage statistics kept on this machine
+var statsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Show how contexts and occtx itself are used",
+	Long: `Stats shows how often each context was switched to. With --self it shows
+how often each occtx command ran, how often it failed and how long it took,
+which helps spot slow operations.
+
+The statistics are recorded in the occtx config dir and never sent anywhere.
+Set stats.disabled in the occtx config to stop recording commands.
+
+Examples:
+  occtx stats                 # Switches per context
+  occtx stats --self          # Runs and timing per command
+  occtx stats --self -o json
+  occtx stats --self --reset  # Start over`,
+	Args: cobra.NoArgs,
+	RunE: runStats,
+}
+
+func init() {
+	statsCmd.Flags().Bool("self", false, "Show occtx's own command statistics")
+	statsCmd.Flags().Bool("reset", false, "With --self, forget the command statistics")
+	statsCmd.Flags().StringP("output", "o", "text", "Output format (text, json)")
+	rootCmd.AddCommand(statsCmd)
+}
+
+func runStats(cmd *cobra.Command, args []string) error {
+	output, err := planOutput(cmd)
+	if err != nil {
+		return err
+	}
+	self, _ := cmd.Flags().GetBool("self")
+	reset, _ := cmd.Flags().GetBool("reset")
+	if reset && !self {
+		return usageErrorf("--reset requires --self")
+	}
+
+	manager, err := newManager()
+	if err != nil {
+		return err
+	}
+
+	if reset {
+		if err := manager.ResetSelfStats(); err != nil {
+			return err
+		}
+		ui.NewColorPrinter().PrintSuccess("Command statistics reset\n")
+		return nil
+	}
+	if self {
+		stats, err := manager.LoadSelfStats()
+		if err != nil {
+			return err
+		}
+		if output == "json" {
+			return printJSON(stats)
+		}
+		if len(stats.Commands) == 0 {
+			fmt.Println("No commands recorded yet")
+			return nil
+		}
+
+		fmt.Printf("Since %s (recorded locally, never sent)\n\n", stats.Since.Local().Format("2006-01-02 15:04"))
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "COMMAND\tRUNS\tFAILED\tMEAN\tMAX")
+		for _, name := range stats.SortedCommands() {
+			entry := stats.Commands[name]
+			fmt.Fprintf(w, "%s\t%d\t%d\t%s\t%s\n", name, entry.Count, entry.Failures,
+				roundDuration(entry.Mean()), roundDuration(time.Duration(entry.Max)))
+		}
+		return w.Flush()
+	}
+
+	usage, err := manager.GetUsage()
+	if err != nil {
+		return err
+	}
+	names := make([]string, 0, len(usage))
+	for name := range usage {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if usage[names[i]].Count != usage[names[j]].Count {
+			return usage[names[i]].Count > usage[names[j]].Count
+		}
+		return names[i] < names[j]
+	})
+
+	if output == "json" {
+		return printJSON(usage)
+	}
+	if len(names) == 0 {
+		fmt.Println("No switches recorded yet")
+		return nil
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "CONTEXT\tSWITCHES\tLAST USED")
+	for _, name := range names {
+		last := "-"
+		if used := usage[name].LastUsed; used != nil {
+			last = used.Local().Format("2006-01-02 15:04")
+		}
+		fmt.Fprintf(w, "%s\t%d\t%s\n", name, usage[name].Count, last)
+	}
+	return w.Flush()
+}
+
+// printJSON prints value as indented JSON
+func printJSON(value interface{}) error {
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(value)
+}
+
+// roundDuration keeps durations readable: microseconds below 10ms, else milliseconds
+func roundDuration(d time.Duration) time.Duration {
+	if d < 10*time.Millisecond {
+		return d.Round(time.Microsecond)
+	}
+	return d.Round(time.Millisecond)
+}
+
+// untrackedCommands run too often, or too mechanically, to be worth counting
+var untrackedCommands = map[string]bool{
+	"tmux-status":                   true, // Every few seconds from tmux
+	"completion":                    true,
+	"help":                          true,
+	"stats":                         true, // Looking at the statistics doesn't count
+	cobra.ShellCompRequestCmd:       true,
+	cobra.ShellCompNoDescRequestCmd: true,
+}
+
+// rootActions are the root flags that select what the root command does, in
+// the order runRoot checks them
+var rootActions = []string{"interactive", "current", "unset", "new", "delete", "edit", "show", "export", "import", "rename"}
+
+// statsCommandName names a finished command for the statistics, e.g.
+// "switch", "new" or "models update"; "" means it isn't recorded
+func statsCommandName(cmd *cobra.Command) string {
+	if cmd == nil || cmd.Hidden {
+		return ""
+	}
+	if help, _ := cmd.Flags().GetBool("help"); help {
+		return ""
+	}
+
+	path := strings.Fields(cmd.CommandPath())[1:]
+	if len(path) > 0 {
+		if untrackedCommands[path[0]] {
+			return ""
+		}
+		return strings.Join(path, " ")
+	}
+
+	for _, action := range rootActions {
+		if cmd.Flags().Changed(action) {
+			return action
+		}
+	}
+	if cmd.Flags().NArg() == 0 {
+		return "list"
+	}
+	return "switch"
+}
+
+// recordCommandStats adds the finished command to the local statistics.
+// Failing to record is never reported; statistics must not get in the way.
+func recordCommandStats(cmd *cobra.Command, elapsed time.Duration, err error) {
+	name := statsCommandName(cmd)
+	if name == "" {
+		return
+	}
+	manager, mgrErr := newManager()
+	if mgrErr != nil {
+		return
+	}
+	_ = manager.RecordCommand(name, elapsed, err != nil)
+}
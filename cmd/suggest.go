@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/hungthai1401/occtx/internal/context"
+	"github.com/hungthai1401/occtx/internal/suggest"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+func init() {
+	rootCmd.SetFlagErrorFunc(suggestFlag)
+}
+
+// suggestFlag adds the closest known flag to unknown-flag parse errors and
+// marks flag errors as usage errors
+func suggestFlag(cmd *cobra.Command, err error) error {
+	const prefix = "unknown flag: --"
+	if !strings.HasPrefix(err.Error(), prefix) {
+		return &usageError{err}
+	}
+
+	var names []string
+	collect := func(flag *pflag.Flag) { names = append(names, "--"+flag.Name) }
+	cmd.Flags().VisitAll(collect)
+	cmd.InheritedFlags().VisitAll(collect)
+
+	typed := "--" + strings.TrimPrefix(err.Error(), prefix)
+	return usageErrorf("%v%s", err, suggest.Hint(firstSuggestion(suggest.Closest(typed, names))))
+}
+
+// subcommandArgs rejects stray arguments to a command that only groups
+// subcommands, suggesting the subcommand that was probably meant
+func subcommandArgs(cmd *cobra.Command, args []string) error {
+	if len(args) == 0 {
+		return nil
+	}
+
+	return usageErrorf("unknown command %q for %q%s", args[0], cmd.CommandPath(),
+		suggest.Hint(firstSuggestion(suggest.Closest(args[0], commandNames(cmd)))))
+}
+
+// withCommandSuggestion extends a context-not-found error for a name that
+// looks like a mistyped subcommand of cmd, since the root command treats
+// unknown words as context names
+func withCommandSuggestion(cmd *cobra.Command, err error) error {
+	var notFound *context.NotFoundError
+	if !errors.As(err, &notFound) || len(notFound.Suggestions) > 0 {
+		return err
+	}
+
+	commands := suggest.Closest(notFound.Name, commandNames(cmd))
+	if len(commands) == 0 {
+		return err
+	}
+
+	return fmt.Errorf("%v; did you mean the '%s' command?", err, commands[0])
+}
+
+// commandNames lists the names and aliases of the visible subcommands of cmd
+func commandNames(cmd *cobra.Command) []string {
+	var names []string
+	for _, sub := range cmd.Commands() {
+		if !sub.IsAvailableCommand() {
+			continue
+		}
+		names = append(names, sub.Name())
+		names = append(names, sub.Aliases...)
+	}
+	return names
+}
+
+// firstSuggestion trims suggestions to the single best match
+func firstSuggestion(suggestions []string) []string {
+	if len(suggestions) > 1 {
+		return suggestions[:1]
+	}
+	return suggestions
+}
@@ -0,0 +1,230 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hungthai1401/occtx/internal/context"
+	"github.com/hungthai1401/occtx/internal/ui"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// switchCmd represents the explicit switch command
+var switchCmd = &cobra.Command{
+	Use:   "switch <name>",
+	Short: "Switch to a context",
+	Long: `Switch to a context, optionally only for a limited time. When a temporary
+switch expires, the next occtx invocation (or a running 'occtx watch')
+reverts to the context that was current before.
+
+--both switches the global and the project scope together, each to its
+context of that name, for projects whose config must mirror the global one.
+The context must exist in both scopes. Either both switch or, if one fails,
+both are left as they were.
+
+Examples:
+  occtx switch work           # Same as 'occtx work'
+  occtx switch demo --for 2h  # Switch to demo, revert after two hours
+  occtx switch work --both    # Switch global and project to work
+  occtx switch work --dry-run -o json  # Show the change as a JSON plan`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeContextArg,
+	RunE:              runSwitch,
+}
+
+func init() {
+	switchCmd.Flags().Duration("for", 0, "Revert to the prior context after this duration (e.g. 30m, 2h)")
+	switchCmd.Flags().Bool("both", false, "Switch the global and project scopes together")
+	switchCmd.Flags().Bool("dry-run", false, "Print what would change without writing anything")
+	switchCmd.Flags().StringP("output", "o", "text", "Plan output format with --dry-run (text, json)")
+	addSwitchFlags(switchCmd.Flags())
+	rootCmd.AddCommand(switchCmd)
+}
+
+// addSwitchFlags registers the flags handling an active config occtx didn't
+// write, guarded cost tiers and context requirements
+func addSwitchFlags(flags *pflag.FlagSet) {
+	flags.Bool("force", false, "Overwrite an active config occtx didn't write")
+	flags.String("save-as", "", "First save an active config occtx didn't write as a new context")
+	flags.BoolP("yes", "y", false, "Don't ask before switching to a guarded cost tier or after a review")
+	flags.Bool("skip-requires", false, "Switch even if this machine lacks what the context requires")
+	flags.Bool("create-missing", false, "Create a context that doesn't exist from the active config first")
+	flags.Bool("review", false, "Show what the switch changes in the active config and ask first")
+}
+
+func runSwitch(cmd *cobra.Command, args []string) error {
+	name, err := resolvePosition(args[0])
+	if err != nil {
+		return err
+	}
+	duration, _ := cmd.Flags().GetDuration("for")
+	opts := switchOptionsFromFlags(cmd)
+
+	if both, _ := cmd.Flags().GetBool("both"); both {
+		if duration != 0 {
+			return fmt.Errorf("--for can't be combined with --both")
+		}
+		if name == "-" {
+			return fmt.Errorf("--both needs a context name, not '-'")
+		}
+		if opts.saveAs != "" {
+			return fmt.Errorf("--save-as can't be combined with --both")
+		}
+		if dryRun(cmd) {
+			return planSwitchBoth(cmd, name, opts)
+		}
+		return switchBothScopes(name, opts)
+	}
+
+	if dryRun(cmd) {
+		if duration != 0 {
+			return fmt.Errorf("--for can't be combined with --dry-run")
+		}
+		return planSwitch(cmd, name, opts)
+	}
+
+	if duration == 0 {
+		if name == "-" {
+			return switchToPreviousContext(opts)
+		}
+		return switchToContext(name, opts)
+	}
+
+	manager, err := newSwitchManager(opts)
+	if err != nil {
+		return err
+	}
+
+	if err := manager.SwitchToContextFor(name, duration); err != nil {
+		return err
+	}
+
+	temporary, err := manager.GetTemporarySwitch()
+	if err != nil {
+		return err
+	}
+
+	printer := ui.NewColorPrinter()
+	printer.PrintSuccess("Switched to context: %s\n", temporary.Context)
+	fmt.Printf("Reverting to %s at %s\n", describeRevertTarget(temporary.RevertTo), temporary.ExpiresAt.Format("15:04"))
+	return nil
+}
+
+// switchBothScopes switches the global and project scopes to name together
+func switchBothScopes(name string, opts switchOptions) error {
+	var managers []*context.Manager
+	for _, useProject := range []bool{false, true} {
+		manager, err := newSwitchManagerForScope(useProject, opts)
+		if err != nil {
+			return err
+		}
+		managers = append(managers, manager)
+	}
+
+	if err := context.SwitchScopes(name, managers...); err != nil {
+		return err
+	}
+
+	printer := ui.NewColorPrinter()
+	for _, manager := range managers {
+		current, _ := manager.GetCurrentContext()
+		printer.PrintSuccess("Switched %s scope to context: %s\n", manager.ScopeName(), current)
+	}
+	if session := context.SessionContext(); session != "" {
+		ui.Warnf("this terminal still uses session context '%s' (%s)", session, context.SessionContextEnvVar)
+	}
+	return nil
+}
+
+// revertExpiredSwitch restores the prior context when a temporary switch has
+// expired; it runs before every command and only warns on failure. In strict
+// mode a revert fails the command, since it ran with a different context
+// than the caller expected.
+func revertExpiredSwitch(cmd *cobra.Command, args []string) error {
+	manager, err := newManager()
+	if err != nil {
+		// The command itself will report the problem
+		return nil
+	}
+
+	// Expired switches are reverted on the next writable invocation
+	if manager.IsReadOnly() {
+		return nil
+	}
+	// Looking never writes: dry runs and commands that only read report it
+	if dryRun(cmd) || inspectOnly(cmd, args) {
+		if temporary, err := manager.GetTemporarySwitch(); err == nil && temporary != nil && temporary.Expired(time.Now()) {
+			ui.Warnf("Temporary context '%s' expired at %s; the next change reverts to %s",
+				temporary.Context, temporary.ExpiresAt.Format("2006-01-02 15:04"), describeRevertTarget(temporary.RevertTo))
+		}
+		return nil
+	}
+
+	reverted, err := manager.RevertExpired()
+	if err != nil {
+		ui.Warnf("%v", err)
+		return nil
+	}
+
+	if reverted != nil {
+		message := fmt.Sprintf("Temporary context '%s' expired at %s; reverted to %s",
+			reverted.Context, reverted.ExpiresAt.Format("2006-01-02 15:04"), describeRevertTarget(reverted.RevertTo))
+		ui.Warnf("%s", message)
+		if manager.IsStrict() {
+			return &context.StrictWarningsError{Operation: cmd.CommandPath(), Warnings: []string{message}}
+		}
+	}
+	return nil
+}
+
+// inspectCommands only read contexts and state, by path below occtx
+var inspectCommands = map[string]bool{
+	"backup list": true, "completion": true, "contract": true, "current": true,
+	"diff": true, "doctor": true, "env list": true, "help": true, "hooks list": true,
+	"impact": true, "log": true, "ls": true, "map list": true, "models check": true,
+	"path": true, "profiles": true, "projects": true, "render": true, "search": true,
+	"stats": true, "tag list": true, "templates": true, "tmux-status": true,
+	"validate": true, "webhooks list": true, "which": true,
+}
+
+// rootChangeFlags are the flags that make the root command change something
+var rootChangeFlags = []string{"unset", "new", "delete", "edit", "import", "rename", "interactive"}
+
+// inspectOnly reports whether cmd, run with args, only reads: a listing, -c,
+// -s or --export at the root, or one of inspectCommands
+func inspectOnly(cmd *cobra.Command, args []string) bool {
+	if cmd.HasParent() {
+		return inspectCommands[strings.TrimPrefix(cmd.CommandPath(), cmd.Root().Name()+" ")]
+	}
+	if len(args) > 0 {
+		return false
+	}
+	for _, name := range rootChangeFlags {
+		if cmd.Flags().Changed(name) {
+			return false
+		}
+	}
+	return true
+}
+
+// describeRevertTarget names the context a temporary switch reverts to
+func describeRevertTarget(name string) string {
+	if name == "" {
+		return "no context"
+	}
+	return fmt.Sprintf("context '%s'", name)
+}
+
+// untilExpiry returns how long to wait before checking a temporary switch again
+func untilExpiry(temporary *context.TemporarySwitch, interval time.Duration) time.Duration {
+	remaining := time.Until(temporary.ExpiresAt)
+	if remaining < 0 {
+		return 0
+	}
+	if remaining > interval {
+		return interval
+	}
+	return remaining
+}
@@ -0,0 +1,130 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hungthai1401/occtx/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+// tagCmd labels contexts so they can be selected together
+var tagCmd = &cobra.Command{
+	Use:   "tag [list [name]|set <name> KEY=VALUE...|unset <name> KEY...]",
+	Short: "Manage the tags of a context",
+	Long: `Tag labels contexts with key=value pairs, such as team=platform, so they can
+be selected together, e.g. by 'occtx export --tag'. Tags are kept with the
+other context metadata and follow a context when it is renamed.
+
+Examples:
+  occtx tag                          # Tags of the current context
+  occtx tag list work
+  occtx tag set work team=platform env=prod
+  occtx tag unset work env
+  occtx export --tag team=platform --out-dir ./shared-contexts`,
+	Args: subcommandArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runTagList(cmd, nil)
+	},
+}
+
+var tagListCmd = &cobra.Command{
+	Use:               "list [name]",
+	Short:             "List the tags of a context (default: the current one)",
+	Args:              cobra.MaximumNArgs(1),
+	ValidArgsFunction: completeContextArg,
+	RunE:              runTagList,
+}
+
+var tagSetCmd = &cobra.Command{
+	Use:               "set <name> KEY=VALUE...",
+	Short:             "Tag a context",
+	Args:              cobra.MinimumNArgs(2),
+	ValidArgsFunction: completeContextArg,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		manager, err := newManager()
+		if err != nil {
+			return err
+		}
+
+		tags := make(map[string]string, len(args)-1)
+		for _, arg := range args[1:] {
+			key, value, ok := strings.Cut(arg, "=")
+			if !ok {
+				return fmt.Errorf("expected KEY=VALUE, got '%s'", arg)
+			}
+			tags[key] = value
+		}
+
+		if err := manager.SetContextTags(args[0], tags); err != nil {
+			return err
+		}
+		printer := ui.NewColorPrinter()
+		printer.PrintSuccess("Set %d tag(s) on '%s'\n", len(tags), args[0])
+		return nil
+	},
+}
+
+var tagUnsetCmd = &cobra.Command{
+	Use:               "unset <name> KEY...",
+	Short:             "Remove tags from a context",
+	Args:              cobra.MinimumNArgs(2),
+	ValidArgsFunction: completeContextArg,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		manager, err := newManager()
+		if err != nil {
+			return err
+		}
+
+		if err := manager.UntagContext(args[0], args[1:]); err != nil {
+			return err
+		}
+		printer := ui.NewColorPrinter()
+		printer.PrintSuccess("Removed %d tag(s) from '%s'\n", len(args)-1, args[0])
+		return nil
+	},
+}
+
+func init() {
+	tagCmd.AddCommand(tagListCmd)
+	tagCmd.AddCommand(tagSetCmd)
+	tagCmd.AddCommand(tagUnsetCmd)
+	rootCmd.AddCommand(tagCmd)
+}
+
+func runTagList(cmd *cobra.Command, args []string) error {
+	manager, err := newManager()
+	if err != nil {
+		return err
+	}
+
+	name := optionalArg(args)
+	if name == "" {
+		if name, _, err = manager.EffectiveCurrentContext(); err != nil {
+			return err
+		}
+		if name == "" {
+			return fmt.Errorf("no current context")
+		}
+	}
+
+	tags, err := manager.GetContextTags(name)
+	if err != nil {
+		return err
+	}
+	if len(tags) == 0 {
+		fmt.Fprintf(cmd.OutOrStdout(), "No tags on '%s'\n", name)
+		return nil
+	}
+
+	keys := make([]string, 0, len(tags))
+	for key := range tags {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		fmt.Fprintf(cmd.OutOrStdout(), "%s=%s\n", key, tags[key])
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hungthai1401/occtx/internal/context"
+	"github.com/hungthai1401/occtx/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+// contextTemplate is the template and variables given to -n --from-template
+type contextTemplate struct {
+	Name string
+	Vars map[string]string
+}
+
+// templatesCmd lists the context templates of the scope
+var templatesCmd = &cobra.Command{
+	Use:   "templates",
+	Short: "List context templates and the variables they use",
+	Long: `Templates lists the context templates of the current scope. A template is
+a context file in the templates subdirectory of the settings directory
+(see 'occtx path --contexts') with Go text/template placeholders such as
+"{{ .project_name }}". Rendering one with -n --from-template fills the
+variables in once; the new context doesn't change afterwards.
+
+Examples:
+  occtx templates
+  occtx -n billing --from-template service --var project_name=billing
+  occtx -n billing --from-template service    # Prompts for missing variables`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		manager, err := newManager()
+		if err != nil {
+			return err
+		}
+
+		templates, err := manager.ListTemplates()
+		if err != nil {
+			return err
+		}
+		if len(templates) == 0 {
+			fmt.Printf("No templates found in %s\n", manager.GetPaths().GetTemplatesDir(inProject))
+			return nil
+		}
+
+		for _, tmpl := range templates {
+			if len(tmpl.Variables) == 0 {
+				fmt.Println(tmpl.Name)
+				continue
+			}
+			fmt.Printf("%s (%s)\n", tmpl.Name, strings.Join(tmpl.Variables, ", "))
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(templatesCmd)
+}
+
+// templateFlags reads --from-template and --var; it returns nil without a template
+func templateFlags(cmd *cobra.Command) (*contextTemplate, error) {
+	name, _ := cmd.Flags().GetString("from-template")
+	assignments, _ := cmd.Flags().GetStringArray("var")
+	if name == "" {
+		if len(assignments) > 0 {
+			return nil, usageErrorf("--var requires --from-template")
+		}
+		return nil, nil
+	}
+
+	tmpl := &contextTemplate{Name: name, Vars: make(map[string]string, len(assignments))}
+	for _, assignment := range assignments {
+		key, value, ok := strings.Cut(assignment, "=")
+		if !ok || key == "" {
+			return nil, usageErrorf("expected --var name=value, got '%s'", assignment)
+		}
+		tmpl.Vars[key] = value
+	}
+	return tmpl, nil
+}
+
+// promptTemplateVars asks for the variables the template uses that weren't
+// given with --var. Without a terminal nothing is asked and rendering
+// reports what is missing.
+func promptTemplateVars(manager *context.Manager, tmpl *contextTemplate) error {
+	info, err := manager.GetTemplate(tmpl.Name)
+	if err != nil {
+		return err
+	}
+
+	for _, variable := range info.Variables {
+		if _, ok := tmpl.Vars[variable]; ok {
+			continue
+		}
+		value, ok := ui.Prompt(variable)
+		if !ok {
+			return nil
+		}
+		tmpl.Vars[variable] = value
+	}
+	return nil
+}
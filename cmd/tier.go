@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/hungthai1401/occtx/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+// tierCmd tags contexts with a cost tier
+var tierCmd = &cobra.Command{
+	Use:   "tier <name> [tier]",
+	Short: "Show or set the cost tier of a context",
+	Long: `Tier tags a context with a cost tier such as "expensive" or "cheap".
+Tiers listed in cost.guarded_tiers of the occtx config trigger a warning, or
+a confirmation with cost.action set to confirm, when switching to them
+outside cost.working_hours or from inside one of cost.dirs.
+
+Examples:
+  occtx tier opus expensive   # Tag 'opus' as expensive
+  occtx tier opus             # Show its tier
+  occtx tier opus --clear     # Remove the tag
+  occtx opus --yes            # Switch without the confirmation`,
+	Args:              cobra.RangeArgs(1, 2),
+	ValidArgsFunction: completeContextArg,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		manager, err := newManager()
+		if err != nil {
+			return err
+		}
+
+		printer := ui.NewColorPrinter()
+		if clear, _ := cmd.Flags().GetBool("clear"); clear {
+			if len(args) > 1 {
+				return fmt.Errorf("--clear takes no tier")
+			}
+			if err := manager.SetCostTier(args[0], ""); err != nil {
+				return err
+			}
+			printer.PrintSuccess("Removed the cost tier of '%s'\n", args[0])
+			return nil
+		}
+
+		if len(args) == 1 {
+			tier, err := manager.GetCostTier(args[0])
+			if err != nil {
+				return err
+			}
+			if tier == "" {
+				fmt.Println("No cost tier set")
+				return nil
+			}
+			fmt.Println(tier)
+			return nil
+		}
+
+		if err := manager.SetCostTier(args[0], args[1]); err != nil {
+			return err
+		}
+		printer.PrintSuccess("Context '%s' tagged as %s tier\n", args[0], args[1])
+		return nil
+	},
+}
+
+func init() {
+	tierCmd.Flags().Bool("clear", false, "Remove the cost tier")
+	rootCmd.AddCommand(tierCmd)
+}
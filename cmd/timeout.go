@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	gocontext "context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// TimeoutEnvVar sets --timeout for every command
+const TimeoutEnvVar = "OCCTX_TIMEOUT"
+
+var (
+	// commandCtx bounds the running command: managers, the commands occtx
+	// runs (editor, fzf, git) and network access stop when it is done
+	commandCtx    = gocontext.Background()
+	cancelCommand = func() {}
+	timeoutLimit  time.Duration // The --timeout in effect; 0 means none
+)
+
+// applyTimeout starts the --timeout / OCCTX_TIMEOUT clock for the command
+func applyTimeout(cmd *cobra.Command) error {
+	limit, _ := cmd.Flags().GetDuration("timeout")
+	if !cmd.Flags().Changed("timeout") {
+		if value := os.Getenv(TimeoutEnvVar); value != "" {
+			parsed, err := time.ParseDuration(value)
+			if err != nil {
+				return usageErrorf("invalid %s '%s': %v", TimeoutEnvVar, value, err)
+			}
+			limit = parsed
+		}
+	}
+	if limit < 0 {
+		return usageErrorf("--timeout must not be negative")
+	}
+	if limit == 0 {
+		return nil
+	}
+
+	timeoutLimit = limit
+	ctx, cancel := gocontext.WithTimeout(cmd.Context(), limit)
+	commandCtx, cancelCommand = ctx, cancel
+	cmd.SetContext(ctx)
+	return nil
+}
+
+// explainTimeout says which limit a deadline error came from
+func explainTimeout(err error) error {
+	if err != nil && timeoutLimit > 0 && errors.Is(err, gocontext.DeadlineExceeded) {
+		return fmt.Errorf("%w (gave up after --timeout %s)", err, timeoutLimit)
+	}
+	return err
+}
+
+// runError returns why a command occtx ran failed: the deadline if it was
+// killed for --timeout, else err
+func runError(err error) error {
+	if ctxErr := commandCtx.Err(); ctxErr != nil {
+		return ctxErr
+	}
+	return err
+}
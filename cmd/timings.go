@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+)
+
+// operationTiming sums the runs of one operation reported for --timings
+type operationTiming struct {
+	op      string
+	calls   int
+	elapsed time.Duration
+}
+
+// timingsRecorded collects operation timings in the order they first ran
+var timingsRecorded []*operationTiming
+
+// recordTiming is the managers' timing handler with --timings
+func recordTiming(op string, elapsed time.Duration) {
+	for _, timing := range timingsRecorded {
+		if timing.op == op {
+			timing.calls++
+			timing.elapsed += elapsed
+			return
+		}
+	}
+	timingsRecorded = append(timingsRecorded, &operationTiming{op: op, calls: 1, elapsed: elapsed})
+}
+
+// printTimings writes the recorded timings and the command's total run time
+func printTimings(w io.Writer, total time.Duration) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "Timings:")
+	for _, timing := range timingsRecorded {
+		op := timing.op
+		if timing.calls > 1 {
+			op = fmt.Sprintf("%s (%d calls)", op, timing.calls)
+		}
+		fmt.Fprintf(tw, "  %s\t%s\n", op, roundDuration(timing.elapsed))
+	}
+	fmt.Fprintf(tw, "  total\t%s\n", roundDuration(total))
+	tw.Flush()
+}
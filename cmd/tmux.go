@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"unicode"
+
+	"github.com/hungthai1401/occtx/internal/config"
+	"github.com/spf13/cobra"
+)
+
+// tmuxStatusCmd prints the current context for tmux's status line
+var tmuxStatusCmd = &cobra.Command{
+	Use:   "tmux-status",
+	Short: "Print the current context for the tmux status line",
+	Long: `Tmux-status prints the current context, read from the current-context
+marker file, in a form safe to embed in a tmux status line: '#' is doubled
+and control characters are dropped. Nothing is printed while no context is
+current. It skips the housekeeping other commands do, so tmux can run it
+every few seconds; 'occtx init tmux' prints the configuration.
+
+--format wraps the name, with #{ctx} standing for it. Because tmux expands
+#{...} inside #() commands, write it as ##{ctx} in tmux.conf, or style the
+output outside the command as 'occtx init tmux' does.
+
+Examples:
+  occtx tmux-status
+  occtx tmux-status --format '#[fg=green]#{ctx}#[default] '`,
+	Args: cobra.NoArgs,
+	// Only the scope flags matter; see beforeTmuxStatus
+	PersistentPreRunE: beforeTmuxStatus,
+	RunE:              runTmuxStatus,
+}
+
+func init() {
+	tmuxStatusCmd.Flags().String("format", "#{ctx}", "Output with #{ctx} replaced by the context name")
+	rootCmd.AddCommand(tmuxStatusCmd)
+}
+
+// beforeTmuxStatus replaces beforeCommand: theme, expired switches and drift
+// warnings would only slow down a command tmux runs constantly
+func beforeTmuxStatus(cmd *cobra.Command, args []string) error {
+	if configDir != "" {
+		return os.Setenv(config.ConfigDirEnvVar, configDir)
+	}
+	return nil
+}
+
+func runTmuxStatus(cmd *cobra.Command, args []string) error {
+	manager, err := newManager()
+	if err != nil {
+		return err
+	}
+
+	data, err := os.ReadFile(manager.GetPaths().GetCurrentMarkerPath(inProject))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	name := tmuxEscape(strings.TrimSpace(string(data)))
+	if name == "" {
+		return nil
+	}
+	format, _ := cmd.Flags().GetString("format")
+	fmt.Fprint(cmd.OutOrStdout(), strings.ReplaceAll(format, "#{ctx}", name))
+	return nil
+}
+
+// tmuxEscape makes s print literally in a tmux format: '#' starts formats
+// and styles, and control characters would break the status line
+func tmuxEscape(s string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) {
+			return -1
+		}
+		return r
+	}, strings.ReplaceAll(s, "#", "##"))
+}
+
+// tmuxSnippet is the tmux.conf configuration 'occtx init tmux' prints.
+// command runs occtx in the scope init was run in.
+func tmuxSnippet(command string) string {
+	return fmt.Sprintf(`# occtx tmux integration (occtx init tmux); add to ~/.tmux.conf
+set -g status-interval 5
+set -ag status-right "#[fg=green]#(%s tmux-status)#[default] "
+`, command)
+}
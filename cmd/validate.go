@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/hungthai1401/occtx/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+// validateCmd checks context content
+var validateCmd = &cobra.Command{
+	Use:   "validate [name...] [--all]",
+	Short: "Check contexts for invalid JSON, schema problems and literal secrets",
+	Long: `Validate checks that contexts parse, match the opencode config schema and
+hold no literal API keys, tokens or passwords ({env:...} and {file:...}
+references are fine). It exits non-zero if any context has findings, which
+makes it suitable for git hooks and CI.
+
+Examples:
+  occtx validate work
+  occtx validate --all
+  occtx --in-project validate --all   # What the pre-commit hook runs`,
+	ValidArgsFunction: completeContextArgs,
+	RunE:              runValidate,
+}
+
+func init() {
+	validateCmd.Flags().Bool("all", false, "Validate every context of the scope")
+	rootCmd.AddCommand(validateCmd)
+}
+
+func runValidate(cmd *cobra.Command, args []string) error {
+	all, _ := cmd.Flags().GetBool("all")
+	if all == (len(args) > 0) {
+		return usageErrorf("specify context names or --all")
+	}
+
+	manager, err := newManager()
+	if err != nil {
+		return err
+	}
+
+	results, err := manager.ValidateContexts(args)
+	if err != nil {
+		return err
+	}
+	if len(results) == 0 {
+		fmt.Println("No contexts to validate")
+		return nil
+	}
+
+	printer := ui.NewColorPrinter()
+	failed := 0
+	for _, result := range results {
+		if result.Valid() {
+			printer.PrintSuccess("✓ %s\n", result.Context)
+			continue
+		}
+		failed++
+		printer.PrintError("✗ %s (%s)\n", result.Context, result.Path)
+		for _, finding := range result.Findings {
+			fmt.Printf("    %s\n", finding)
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d context(s) failed validation", failed)
+	}
+	return nil
+}
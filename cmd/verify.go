@@ -0,0 +1,113 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/hungthai1401/occtx/internal/context"
+	"github.com/hungthai1401/occtx/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+// verifyCmd checks contexts against the checksum manifest
+var verifyCmd = &cobra.Command{
+	Use:   "verify [name...]",
+	Short: "Detect contexts modified outside occtx or corrupted",
+	Long: `Verify compares contexts with the checksums occtx records on every write,
+reporting contexts changed outside occtx, files that went missing or were
+never tracked, and files that are no longer valid JSON.
+
+--restore puts back the last good copy of each failing context from the
+backups or the trash: the newest one matching the recorded checksum, or for a
+corrupt file occtx never recorded, the newest one that parses. The replaced
+file is kept in the trash. --accept records changed files as known-good, but
+never a corrupt one.
+
+Examples:
+  occtx verify --all             # Check every context
+  occtx verify work              # Check one context
+  occtx verify --all --restore   # Restore failing contexts from backups
+  occtx verify --all --accept    # Trust the files as they are now`,
+	ValidArgsFunction: completeContextArgs,
+	RunE:              runVerify,
+}
+
+func init() {
+	verifyCmd.Flags().Bool("all", false, "Verify all contexts")
+	verifyCmd.Flags().Bool("accept", false, "Record the current files as known-good (corrupt files are refused)")
+	verifyCmd.Flags().Bool("restore", false, "Restore failing contexts from the newest good copy in the backups or the trash")
+	verifyCmd.MarkFlagsMutuallyExclusive("accept", "restore")
+	rootCmd.AddCommand(verifyCmd)
+}
+
+func runVerify(cmd *cobra.Command, args []string) error {
+	all, _ := cmd.Flags().GetBool("all")
+	accept, _ := cmd.Flags().GetBool("accept")
+	restore, _ := cmd.Flags().GetBool("restore")
+
+	if all == (len(args) > 0) {
+		return fmt.Errorf("specify context names or --all")
+	}
+
+	manager, err := newManager()
+	if err != nil {
+		return err
+	}
+
+	progress := ui.NewProgress("Verifying contexts")
+	progress.Start()
+	results, err := manager.VerifyContexts(args...)
+	progress.Stop()
+	if err != nil {
+		return err
+	}
+
+	if len(results) == 0 {
+		fmt.Println("No contexts to verify")
+		return nil
+	}
+
+	printer := ui.NewColorPrinter()
+	failed := 0
+	for _, result := range results {
+		switch result.Status {
+		case context.VerifyOK:
+			printer.PrintSuccess("✓ %s\n", result.Name)
+		case context.VerifyUntracked:
+			printer.PrintWarning("? %s (untracked)\n", result.Name)
+		case context.VerifyCorrupt:
+			failed++
+			printer.PrintError("✗ %s (corrupt: %s)\n", result.Name, result.Detail)
+		default:
+			failed++
+			printer.PrintError("✗ %s (%s)\n", result.Name, result.Status)
+		}
+	}
+
+	if accept {
+		if err := manager.AcceptChecksums(results); err != nil {
+			return err
+		}
+		fmt.Println("Checksums updated")
+		return nil
+	}
+
+	if restore && failed > 0 {
+		repaired, err := manager.RepairContexts(results)
+		if err != nil {
+			return err
+		}
+		for _, repair := range repaired {
+			if repair.From == "" {
+				printer.PrintWarning("No good copy of %s in the backups or the trash\n", repair.Name)
+				continue
+			}
+			failed--
+			printer.PrintSuccess("Restored %s from %s\n", repair.Name, repair.From)
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d context(s) failed verification", failed)
+	}
+	return nil
+}
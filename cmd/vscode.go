@@ -0,0 +1,329 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/hungthai1401/occtx/internal/config"
+	"github.com/hungthai1401/occtx/internal/context"
+	"github.com/hungthai1401/occtx/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+const (
+	// vscodeTaskPrefix starts the label of every task occtx writes, so
+	// re-running init replaces them and leaves other tasks alone
+	vscodeTaskPrefix = "occtx: "
+	// vscodeContextInput is the id of the input prompting for a context
+	vscodeContextInput = "occtxContext"
+)
+
+// vscodeCmd groups the VS Code integration
+var vscodeCmd = &cobra.Command{
+	Use:   "vscode",
+	Short: "Set up VS Code to switch contexts from the command palette",
+}
+
+// vscodeInitCmd writes tasks and settings into the project's .vscode directory
+var vscodeInitCmd = &cobra.Command{
+	Use:   "init",
+	Short: "Write occtx tasks and settings into .vscode in the current directory",
+	Long: `Init adds tasks to .vscode/tasks.json so contexts can be switched from the
+command palette (Tasks: Run Task):
+
+  occtx: Switch context    Pick a context from a list and switch to it
+  occtx: Pick context      Open the interactive picker in the terminal
+  occtx: Preview switch    Show what switching to a context would change
+
+It also adds a json.schemas entry to .vscode/settings.json so project
+context files get opencode's schema for completion and validation.
+
+Tasks use --in-project when the project has contexts. The list of contexts is
+taken when init runs; run it again after adding or removing contexts. Tasks
+and settings occtx wrote before are replaced; everything else in the files is
+kept. Files with comments can't be merged; use --print and add the entries
+by hand.
+
+Examples:
+  occtx vscode init
+  occtx vscode init --print`,
+	Args: cobra.NoArgs,
+	RunE: runVSCodeInit,
+}
+
+func init() {
+	vscodeInitCmd.Flags().Bool("print", false, "Print the tasks and settings instead of writing them")
+	vscodeInitCmd.Flags().Bool("no-settings", false, "Only write tasks.json")
+	vscodeCmd.AddCommand(vscodeInitCmd)
+	rootCmd.AddCommand(vscodeCmd)
+}
+
+// vscodeTask is a task in tasks.json, in the order VS Code writes its keys
+type vscodeTask struct {
+	Label          string                 `json:"label"`
+	Type           string                 `json:"type"`
+	Command        string                 `json:"command"`
+	Args           []string               `json:"args"`
+	ProblemMatcher []string               `json:"problemMatcher"`
+	Presentation   map[string]interface{} `json:"presentation,omitempty"`
+}
+
+// vscodeInput is an input in tasks.json
+type vscodeInput struct {
+	ID          string   `json:"id"`
+	Type        string   `json:"type"`
+	Description string   `json:"description"`
+	Options     []string `json:"options,omitempty"`
+}
+
+// vscodeSchema is a json.schemas entry in settings.json
+type vscodeSchema struct {
+	FileMatch []string `json:"fileMatch"`
+	URL       string   `json:"url"`
+}
+
+func runVSCodeInit(cmd *cobra.Command, args []string) error {
+	// Project contexts are what teammates share, so prefer them
+	useProject := inProject
+	if !useProject {
+		if paths, err := config.NewPaths(); err == nil && paths.ProjectContextsExist() {
+			useProject = true
+		}
+	}
+	manager, err := newManagerForScope(useProject)
+	if err != nil {
+		return err
+	}
+	contexts, err := manager.ListContexts()
+	if err != nil {
+		return err
+	}
+	names := make([]string, 0, len(contexts))
+	for _, ctx := range contexts {
+		names = append(names, ctx.Name)
+	}
+
+	var scope []string
+	if useProject {
+		scope = append(scope, "--in-project")
+	}
+	if profile != "" {
+		scope = append(scope, "--profile", profile)
+	}
+	withScope := func(args ...string) []string {
+		return append(append([]string{}, scope...), args...)
+	}
+
+	contextArg := "${input:" + vscodeContextInput + "}"
+	tasks := []vscodeTask{
+		{
+			Label:        vscodeTaskPrefix + "Switch context",
+			Args:         withScope(contextArg),
+			Presentation: map[string]interface{}{"reveal": "always", "panel": "shared"},
+		},
+		{
+			Label:        vscodeTaskPrefix + "Pick context",
+			Args:         withScope("-i"),
+			Presentation: map[string]interface{}{"reveal": "always", "focus": true, "panel": "shared"},
+		},
+		{
+			Label:        vscodeTaskPrefix + "Preview switch",
+			Args:         withScope("--dry-run", contextArg),
+			Presentation: map[string]interface{}{"reveal": "always", "panel": "shared"},
+		},
+	}
+	for i := range tasks {
+		tasks[i].Type = "shell"
+		tasks[i].Command = "occtx"
+		tasks[i].ProblemMatcher = []string{}
+	}
+
+	input := vscodeInput{ID: vscodeContextInput, Type: "pickString", Description: "Context", Options: names}
+	if len(names) == 0 {
+		input = vscodeInput{ID: vscodeContextInput, Type: "promptString", Description: "Context name"}
+	}
+
+	schema := vscodeSchema{
+		FileMatch: []string{
+			"**/" + config.ProjectConfigDir + "/" + config.SettingsSubDir + "/*.json",
+			"**/" + config.ProjectConfigDir + "/" + config.SettingsSubDir + "/*.jsonc",
+			"!**/" + config.ProjectConfigDir + "/" + config.SettingsSubDir + "/.occtx-*.json",
+		},
+		URL: context.OpencodeSchemaURL,
+	}
+
+	// Printed entries aren't merged, so they can be pasted into any file
+	printOnly, _ := cmd.Flags().GetBool("print")
+	tasksPath := filepath.Join(".vscode", "tasks.json")
+	settingsPath := filepath.Join(".vscode", "settings.json")
+	mergeTasks, mergeSettings := tasksPath, settingsPath
+	if printOnly {
+		mergeTasks, mergeSettings = "", ""
+	}
+
+	tasksData, err := mergeVSCodeTasks(mergeTasks, tasks, input)
+	if err != nil {
+		return err
+	}
+	var settingsData []byte
+	if noSettings, _ := cmd.Flags().GetBool("no-settings"); !noSettings {
+		if settingsData, err = mergeVSCodeSettings(mergeSettings, schema); err != nil {
+			return err
+		}
+	}
+
+	if printOnly {
+		fmt.Printf("// %s\n%s", tasksPath, tasksData)
+		if settingsData != nil {
+			fmt.Printf("\n// %s\n%s", settingsPath, settingsData)
+		}
+		return nil
+	}
+
+	if err := os.MkdirAll(".vscode", 0755); err != nil {
+		return err
+	}
+	printer := ui.NewColorPrinter()
+	if err := os.WriteFile(tasksPath, tasksData, 0644); err != nil {
+		return err
+	}
+	printer.PrintSuccess("Wrote %d occtx tasks to %s\n", len(tasks), tasksPath)
+	if settingsData != nil {
+		if err := os.WriteFile(settingsPath, settingsData, 0644); err != nil {
+			return err
+		}
+		printer.PrintSuccess("Added the opencode schema for context files to %s\n", settingsPath)
+	}
+	ui.Hintf("Run the tasks with Tasks: Run Task; run 'occtx vscode init' again after adding contexts")
+	return nil
+}
+
+// readVSCodeFile reads a .vscode JSON file as its top-level keys, keeping
+// their values as written. A missing file, or no path, is empty.
+func readVSCodeFile(path string) (map[string]json.RawMessage, error) {
+	if path == "" {
+		return map[string]json.RawMessage{}, nil
+	}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return map[string]json.RawMessage{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	fields := map[string]json.RawMessage{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, fmt.Errorf("%s can't be merged (comments and trailing commas aren't supported): %v; use --print and add the entries by hand", path, err)
+	}
+	return fields, nil
+}
+
+// mergeVSCodeTasks replaces the occtx tasks and input in the tasks file
+func mergeVSCodeTasks(path string, tasks []vscodeTask, input vscodeInput) ([]byte, error) {
+	fields, err := readVSCodeFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := fields["version"]; !ok {
+		fields["version"] = json.RawMessage(`"2.0.0"`)
+	}
+
+	var existing []json.RawMessage
+	if raw, ok := fields["tasks"]; ok {
+		if err := json.Unmarshal(raw, &existing); err != nil {
+			return nil, fmt.Errorf("%s: tasks must be an array", path)
+		}
+	}
+	merged := make([]interface{}, 0, len(existing)+len(tasks))
+	for _, raw := range existing {
+		var task struct {
+			Label string `json:"label"`
+		}
+		if json.Unmarshal(raw, &task) == nil && strings.HasPrefix(task.Label, vscodeTaskPrefix) {
+			continue
+		}
+		merged = append(merged, raw)
+	}
+	for _, task := range tasks {
+		merged = append(merged, task)
+	}
+
+	var existingInputs []json.RawMessage
+	if raw, ok := fields["inputs"]; ok {
+		if err := json.Unmarshal(raw, &existingInputs); err != nil {
+			return nil, fmt.Errorf("%s: inputs must be an array", path)
+		}
+	}
+	inputs := make([]interface{}, 0, len(existingInputs)+1)
+	for _, raw := range existingInputs {
+		var other struct {
+			ID string `json:"id"`
+		}
+		if json.Unmarshal(raw, &other) == nil && other.ID == input.ID {
+			continue
+		}
+		inputs = append(inputs, raw)
+	}
+	inputs = append(inputs, input)
+
+	if err := setVSCodeField(fields, "tasks", merged); err != nil {
+		return nil, err
+	}
+	if err := setVSCodeField(fields, "inputs", inputs); err != nil {
+		return nil, err
+	}
+	return marshalVSCodeFile(fields)
+}
+
+// mergeVSCodeSettings replaces the opencode schema entry for context files
+// in the settings file
+func mergeVSCodeSettings(path string, schema vscodeSchema) ([]byte, error) {
+	fields, err := readVSCodeFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var existing []json.RawMessage
+	if raw, ok := fields["json.schemas"]; ok {
+		if err := json.Unmarshal(raw, &existing); err != nil {
+			return nil, fmt.Errorf("%s: json.schemas must be an array", path)
+		}
+	}
+	schemas := make([]interface{}, 0, len(existing)+1)
+	for _, raw := range existing {
+		var other vscodeSchema
+		if json.Unmarshal(raw, &other) == nil && other.URL == schema.URL && len(other.FileMatch) > 0 && other.FileMatch[0] == schema.FileMatch[0] {
+			continue
+		}
+		schemas = append(schemas, raw)
+	}
+	schemas = append(schemas, schema)
+
+	if err := setVSCodeField(fields, "json.schemas", schemas); err != nil {
+		return nil, err
+	}
+	return marshalVSCodeFile(fields)
+}
+
+// setVSCodeField stores value under key
+func setVSCodeField(fields map[string]json.RawMessage, key string, value interface{}) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	fields[key] = data
+	return nil
+}
+
+// marshalVSCodeFile formats fields the way VS Code writes its files
+func marshalVSCodeFile(fields map[string]json.RawMessage) ([]byte, error) {
+	data, err := json.MarshalIndent(fields, "", "\t")
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// watchCmd waits for a temporary switch to expire and reverts it
+var watchCmd = &cobra.Command{
+	Use:   "watch",
+	Short: "Revert temporary context switches when they expire",
+	Long: `Watch stays running until the pending temporary switch (see 'occtx switch
+--for') expires, reverts it, and exits. Run it in the background so the
+revert happens on time even if occtx is not invoked again.
+
+Examples:
+  occtx switch demo --for 2h && occtx watch &`,
+	Args: cobra.NoArgs,
+	RunE: runWatch,
+}
+
+func init() {
+	watchCmd.Flags().Duration("interval", time.Minute, "How often to re-check the state file")
+	rootCmd.AddCommand(watchCmd)
+}
+
+func runWatch(cmd *cobra.Command, args []string) error {
+	interval, _ := cmd.Flags().GetDuration("interval")
+	if interval <= 0 {
+		return fmt.Errorf("interval must be positive, got %s", interval)
+	}
+
+	manager, err := newManager()
+	if err != nil {
+		return err
+	}
+
+	// Waiting ends on --timeout or Ctrl-C
+	ctx, stop := signal.NotifyContext(commandCtx, os.Interrupt)
+	defer stop()
+
+	for {
+		temporary, err := manager.GetTemporarySwitch()
+		if err != nil {
+			return err
+		}
+
+		if temporary == nil {
+			fmt.Println("No temporary context switch pending")
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			// Ctrl-C is how a foreground watch is stopped; --timeout is an error
+			if commandCtx.Err() == nil {
+				return nil
+			}
+			return ctx.Err()
+		case <-time.After(untilExpiry(temporary, interval)):
+		}
+
+		reverted, err := manager.RevertExpired()
+		if err != nil {
+			return err
+		}
+
+		if reverted != nil {
+			fmt.Printf("Temporary context '%s' expired; reverted to %s\n", reverted.Context, describeRevertTarget(reverted.RevertTo))
+			return nil
+		}
+	}
+}
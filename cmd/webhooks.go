@@ -0,0 +1,149 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/hungthai1401/occtx/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+// webhookConfigFile is the occtx config whose webhooks have events spooled
+// by this command; when set, they are delivered in the background once the
+// command is done
+var webhookConfigFile string
+
+// webhooksCmd shows the configured webhooks and delivers their events
+var webhooksCmd = &cobra.Command{
+	Use:   "webhooks [list|flush]",
+	Short: "List webhooks or deliver their pending events",
+	Long: `Webhooks receive events as Slack-compatible JSON: a "text" message plus an
+"event" object with the details. They are configured under "webhooks" in
+the occtx config:
+
+  "webhooks": [
+    {"name": "platform", "url": "https://hooks.slack.com/services/...",
+     "events": ["switch", "policy_violation"], "contexts": ["prod-*"]}
+  ]
+
+Events are switch, delete and policy_violation; a webhook without "events"
+gets all of them, one without "contexts" gets them for every context.
+
+Events are written to a spool next to the occtx config and delivered in the
+background after the command, so a slow or unreachable webhook never holds
+up occtx. Events that could not be delivered, or that were spooled in
+offline mode, stay in the spool until the next delivery.
+
+Examples:
+  occtx webhooks list      # Webhooks and their pending events
+  occtx webhooks flush     # Deliver pending events now`,
+	Args: subcommandArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return cmd.Help()
+	},
+}
+
+var webhooksListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List webhooks and their pending events",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		manager, err := newManager()
+		if err != nil {
+			return err
+		}
+
+		pending, err := manager.PendingWebhookEvents()
+		if err != nil {
+			return err
+		}
+		webhooks := manager.GetConfig().Webhooks
+		if len(webhooks) == 0 {
+			fmt.Println("No webhooks configured")
+			ui.Hintf("Add webhooks under \"webhooks\" in %s; see 'occtx webhooks --help'", manager.GetPaths().ConfigFile)
+			return nil
+		}
+
+		printer := ui.NewColorPrinter()
+		for _, webhook := range webhooks {
+			events := "all events"
+			if len(webhook.Events) > 0 {
+				events = strings.Join(webhook.Events, ", ")
+			}
+			contexts := "all contexts"
+			if len(webhook.Contexts) > 0 {
+				contexts = strings.Join(webhook.Contexts, ", ")
+			}
+			printer.Info.Printf("%-16s", webhook.Name)
+			fmt.Printf(" %s for %s", events, contexts)
+			if count := pending[webhook.Name]; count > 0 {
+				printer.Warning.Printf(" (%d pending)", count)
+			}
+			fmt.Println()
+		}
+		return nil
+	},
+}
+
+var webhooksFlushCmd = &cobra.Command{
+	Use:   "flush",
+	Short: "Deliver pending webhook events now",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		manager, err := newManager()
+		if err != nil {
+			return err
+		}
+
+		delivery, err := manager.FlushWebhooks()
+		if err != nil {
+			return err
+		}
+		if !quiet {
+			fmt.Printf("Delivered %d event(s)", delivery.Delivered)
+			if delivery.Dropped > 0 {
+				fmt.Printf(", dropped %d for webhooks no longer configured", delivery.Dropped)
+			}
+			fmt.Println()
+		}
+		if len(delivery.Failed) > 0 {
+			return fmt.Errorf("some events stay spooled:\n  %s", strings.Join(delivery.Failed, "\n  "))
+		}
+		return nil
+	},
+}
+
+func init() {
+	webhooksCmd.AddCommand(webhooksListCmd)
+	webhooksCmd.AddCommand(webhooksFlushCmd)
+	rootCmd.AddCommand(webhooksCmd)
+}
+
+// startWebhookDelivery starts 'occtx webhooks flush' in the background if
+// the command spooled events. Failures are left to the next delivery: the
+// events stay spooled.
+func startWebhookDelivery() {
+	if webhookConfigFile == "" {
+		return
+	}
+	executable, err := os.Executable()
+	if err != nil {
+		return
+	}
+
+	args := []string{"--config-dir", filepath.Dir(webhookConfigFile)}
+	if profile != "" {
+		args = append(args, "--profile", profile)
+	}
+	args = append(args, "--quiet", "webhooks", "flush")
+
+	deliver := exec.Command(executable, args...)
+	detach(deliver)
+	if err := deliver.Start(); err != nil {
+		return
+	}
+	deliver.Process.Release()
+}
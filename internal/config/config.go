@@ -0,0 +1,524 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultMaxNameLength is the longest context name accepted unless configured otherwise
+const DefaultMaxNameLength = 64
+
+// DefaultBackupKeep is how many backups `occtx backup create --prune` keeps unless configured otherwise
+const DefaultBackupKeep = 10
+
+// DefaultProjectScanDepth is how many directory levels below a root `occtx projects` searches
+const DefaultProjectScanDepth = 4
+
+// Config holds user preferences read from the occtx config file
+type Config struct {
+	Names    NamesConfig              `json:"names"`
+	Projects ProjectsConfig           `json:"projects"`
+	Profiles map[string]ProfileConfig `json:"profiles,omitempty"`
+
+	// SharedDirs are read-only context directories, e.g. IT-managed baselines.
+	// Unset uses DefaultSharedContextsDir; an empty list disables them.
+	SharedDirs []string `json:"shared_dirs,omitempty"`
+
+	// Mirrors are read-only context directories on mounted team folders,
+	// listed after the shared directories with the mirror's name as a badge
+	Mirrors []MirrorConfig `json:"mirrors,omitempty"`
+
+	Warnings WarningsConfig `json:"warnings"`
+
+	Interactive InteractiveConfig `json:"interactive"`
+
+	UI UIConfig `json:"ui"`
+
+	Import ImportConfig `json:"import"`
+
+	Approval ApprovalConfig `json:"approval"`
+
+	Backup BackupConfig `json:"backup"`
+
+	Models ModelsConfig `json:"models"`
+
+	Cost CostConfig `json:"cost"`
+
+	Switch SwitchConfig `json:"switch"`
+
+	Merge MergeConfig `json:"merge"`
+
+	ActiveConfig ActiveConfigConfig `json:"active_config"`
+
+	Permissions PermissionsConfig `json:"permissions"`
+
+	// Hooks are commands run around switches, in order
+	Hooks []HookConfig `json:"hooks,omitempty"`
+
+	// Webhooks receive events such as switches as Slack-compatible JSON
+	Webhooks []WebhookConfig `json:"webhooks,omitempty"`
+
+	Stats StatsConfig `json:"stats"`
+
+	// Provides lists capabilities of this machine, e.g. "work-vpn", that
+	// meet context requirements of the same name
+	Provides []string `json:"provides,omitempty"`
+}
+
+// StatsConfig controls the command statistics occtx keeps locally for
+// `occtx stats --self`; they are never sent anywhere
+type StatsConfig struct {
+	Disabled bool `json:"disabled,omitempty"` // Don't record commands
+}
+
+// SwitchConfig controls how switches are confirmed
+type SwitchConfig struct {
+	Review bool `json:"review,omitempty"` // Show what a switch changes in the active config and ask first
+}
+
+// CostConfig guards switches to contexts tagged with a costly tier. The guard
+// trips outside working hours or inside one of the directories; with neither
+// set, it trips on every switch to a guarded tier.
+type CostConfig struct {
+	GuardedTiers []string `json:"guarded_tiers,omitempty"` // e.g. ["expensive"]
+	WorkingHours string   `json:"working_hours,omitempty"` // Local time, e.g. "09:00-18:00"
+	WorkingDays  []string `json:"working_days,omitempty"`  // e.g. ["mon", "tue", "wed", "thu", "fri"]; empty means every day
+	Dirs         []string `json:"dirs,omitempty"`          // Switching from inside these trips the guard; "~/" is expanded
+	Action       string   `json:"action,omitempty"`        // warn (default) or confirm
+}
+
+// DefaultModelsURL is the provider model catalog fetched by `occtx models update`
+const DefaultModelsURL = "https://models.dev/api.json"
+
+// ModelsConfig controls model name validation against the cached catalog
+type ModelsConfig struct {
+	Validate string `json:"validate,omitempty"` // Policy on switch: off (default), warn or reject
+	URL      string `json:"url,omitempty"`      // Catalog to fetch; empty uses DefaultModelsURL
+}
+
+// EffectiveURL returns the configured catalog URL or the default
+func (m ModelsConfig) EffectiveURL() string {
+	if m.URL != "" {
+		return m.URL
+	}
+	return DefaultModelsURL
+}
+
+// BackupConfig controls `occtx backup`
+type BackupConfig struct {
+	Keep int `json:"keep,omitempty"` // Backups kept by --prune; 0 uses DefaultBackupKeep
+}
+
+// EffectiveKeep returns the configured number of backups to keep or the default
+func (b BackupConfig) EffectiveKeep() int {
+	if b.Keep > 0 {
+		return b.Keep
+	}
+	return DefaultBackupKeep
+}
+
+// ApprovalConfig controls the context approval workflow
+type ApprovalConfig struct {
+	Required bool `json:"required,omitempty"` // In strict mode, only approved contexts can be switched to
+}
+
+// ActiveConfigConfig names the file a switch writes, the one opencode reads.
+// Unset names are detected: the first candidate that exists, else opencode.json.
+type ActiveConfigConfig struct {
+	Global     string `json:"global,omitempty"`      // Relative to the global config dir, e.g. "opencode.jsonc"
+	Project    string `json:"project,omitempty"`     // Relative to the project root, e.g. ".opencode/opencode.json"
+	StrictJSON bool   `json:"strict_json,omitempty"` // Always write plain JSON, for opencode versions without JSONC support
+
+	// Mode is "preserve" (default) to keep the permissions of the file a
+	// switch replaces, or an octal mode such as "0600" to normalize them
+	Mode          string `json:"mode,omitempty"`
+	PreserveOwner bool   `json:"preserve_owner,omitempty"` // On Unix, also keep the owner and group of the replaced file
+}
+
+// ActiveModePreserve keeps the permissions of the active config a switch replaces
+const ActiveModePreserve = "preserve"
+
+// FileMode returns the permissions configured for the active config, and
+// false when those of the replaced file are kept
+func (a ActiveConfigConfig) FileMode() (os.FileMode, bool, error) {
+	if a.Mode == "" || a.Mode == ActiveModePreserve {
+		return 0, false, nil
+	}
+	mode, err := parseMode(a.Mode)
+	if err != nil {
+		return 0, false, fmt.Errorf("active_config.mode: invalid mode '%s' (expected preserve or an octal mode such as 0600)", a.Mode)
+	}
+	return mode, true, nil
+}
+
+// Default modes of the files and directories occtx writes, before the umask
+const (
+	DefaultFileMode os.FileMode = 0644
+	DefaultDirMode  os.FileMode = 0755
+)
+
+// PermissionsConfig sets the modes of the files and directories occtx writes
+// for itself, such as contexts, state and metadata. The process umask still
+// applies. Backups and session files stay private to the user.
+type PermissionsConfig struct {
+	Files string `json:"files,omitempty"` // Octal, e.g. "0600"; empty uses DefaultFileMode
+	Dirs  string `json:"dirs,omitempty"`  // Octal, e.g. "0700"; empty uses DefaultDirMode
+}
+
+// Validate checks that the modes are octal and leave the owner able to use
+// the files and directories
+func (p PermissionsConfig) Validate() error {
+	if p.Files != "" {
+		mode, err := parseMode(p.Files)
+		if err != nil || mode&0600 != 0600 {
+			return fmt.Errorf("permissions.files: invalid mode '%s' (expected an octal mode the owner can read and write, such as 0600)", p.Files)
+		}
+	}
+	if p.Dirs != "" {
+		mode, err := parseMode(p.Dirs)
+		if err != nil || mode&0700 != 0700 {
+			return fmt.Errorf("permissions.dirs: invalid mode '%s' (expected an octal mode the owner has full access to, such as 0700)", p.Dirs)
+		}
+	}
+	return nil
+}
+
+// FileMode returns the configured file mode or the default
+func (p PermissionsConfig) FileMode() os.FileMode {
+	if mode, err := parseMode(p.Files); err == nil {
+		return mode
+	}
+	return DefaultFileMode
+}
+
+// DirMode returns the configured directory mode or the default
+func (p PermissionsConfig) DirMode() os.FileMode {
+	if mode, err := parseMode(p.Dirs); err == nil {
+		return mode
+	}
+	return DefaultDirMode
+}
+
+// Hook events
+const (
+	HookPreSwitch  = "pre-switch"  // Before the active config is written; a failure can abort the switch
+	HookPostSwitch = "post-switch" // After the switch
+)
+
+// Hook failure policies
+const (
+	HookAbort = "abort" // Fail the switch (after a post-switch hook, the command)
+	HookWarn  = "warn"  // Report a warning and go on
+)
+
+// DefaultHookTimeout bounds a hook without a timeout of its own
+const DefaultHookTimeout = 10 * time.Second
+
+// DefaultHookEnv are the variables a hook without an env list gets from the
+// environment, besides the OCCTX_* variables describing the switch
+var DefaultHookEnv = []string{"PATH", "HOME", "USER", "LOGNAME", "LANG", "LC_*", "TMPDIR", "TEMP", "TMP", "SYSTEMROOT"}
+
+// HookConfig is a command run when an event happens
+type HookConfig struct {
+	Name      string   `json:"name"`
+	On        string   `json:"on"`                   // pre-switch or post-switch
+	Run       []string `json:"run"`                  // Command and arguments, run without a shell
+	Order     int      `json:"order,omitempty"`      // Lower runs first; equal orders keep config order
+	Timeout   string   `json:"timeout,omitempty"`    // e.g. "5s"; empty uses DefaultHookTimeout
+	Env       []string `json:"env,omitempty"`        // Variables passed from the environment, "AWS_*" for a prefix; empty uses DefaultHookEnv
+	OnFailure string   `json:"on_failure,omitempty"` // abort (default) or warn
+}
+
+// EffectiveTimeout returns the configured timeout or the default
+func (h HookConfig) EffectiveTimeout() time.Duration {
+	if timeout, err := time.ParseDuration(h.Timeout); err == nil && timeout > 0 {
+		return timeout
+	}
+	return DefaultHookTimeout
+}
+
+// EffectiveEnv returns the configured environment allowlist or the default
+func (h HookConfig) EffectiveEnv() []string {
+	if len(h.Env) > 0 {
+		return h.Env
+	}
+	return DefaultHookEnv
+}
+
+// Aborts reports whether a failure of the hook fails the switch
+func (h HookConfig) Aborts() bool {
+	return h.OnFailure != HookWarn
+}
+
+// ValidateHooks checks that hooks are named uniquely and fully specified
+func ValidateHooks(hooks []HookConfig) error {
+	seen := make(map[string]bool)
+	for i, hook := range hooks {
+		if hook.Name == "" {
+			return fmt.Errorf("hooks[%d]: missing name", i)
+		}
+		if seen[hook.Name] {
+			return fmt.Errorf("hooks: duplicate name '%s'", hook.Name)
+		}
+		seen[hook.Name] = true
+
+		switch {
+		case hook.On != HookPreSwitch && hook.On != HookPostSwitch:
+			return fmt.Errorf("hook '%s': invalid event '%s' (expected %s or %s)", hook.Name, hook.On, HookPreSwitch, HookPostSwitch)
+		case len(hook.Run) == 0 || hook.Run[0] == "":
+			return fmt.Errorf("hook '%s': missing command in run", hook.Name)
+		case hook.OnFailure != "" && hook.OnFailure != HookAbort && hook.OnFailure != HookWarn:
+			return fmt.Errorf("hook '%s': invalid on_failure '%s' (expected %s or %s)", hook.Name, hook.OnFailure, HookAbort, HookWarn)
+		}
+		if hook.Timeout != "" {
+			if timeout, err := time.ParseDuration(hook.Timeout); err != nil || timeout <= 0 {
+				return fmt.Errorf("hook '%s': invalid timeout '%s' (expected a duration such as 5s)", hook.Name, hook.Timeout)
+			}
+		}
+	}
+	return nil
+}
+
+// Events sent to webhooks
+const (
+	EventSwitch          = "switch"
+	EventDelete          = "delete"
+	EventPolicyViolation = "policy_violation"
+)
+
+// WebhookEvents lists the events a webhook can subscribe to
+var WebhookEvents = []string{EventSwitch, EventDelete, EventPolicyViolation}
+
+// WebhookConfig is a URL events are posted to
+type WebhookConfig struct {
+	Name     string   `json:"name"`
+	URL      string   `json:"url"`
+	Events   []string `json:"events,omitempty"`   // Empty sends every event
+	Contexts []string `json:"contexts,omitempty"` // Glob patterns such as "prod-*"; empty matches every context
+}
+
+// Wants reports whether the webhook subscribes to event for the context
+func (w WebhookConfig) Wants(event, context string) bool {
+	if len(w.Events) > 0 && !containsString(w.Events, event) {
+		return false
+	}
+	if len(w.Contexts) == 0 {
+		return true
+	}
+	for _, pattern := range w.Contexts {
+		if matched, _ := path.Match(pattern, context); matched {
+			return true
+		}
+	}
+	return false
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateWebhooks checks that webhooks are named uniquely, have an HTTP(S)
+// URL and subscribe to known events
+func ValidateWebhooks(webhooks []WebhookConfig) error {
+	seen := make(map[string]bool)
+	for i, webhook := range webhooks {
+		if webhook.Name == "" {
+			return fmt.Errorf("webhooks[%d]: missing name", i)
+		}
+		if seen[webhook.Name] {
+			return fmt.Errorf("webhooks: duplicate name '%s'", webhook.Name)
+		}
+		seen[webhook.Name] = true
+
+		if u, err := url.Parse(webhook.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("webhook '%s': invalid url '%s' (expected http or https)", webhook.Name, webhook.URL)
+		}
+		for _, event := range webhook.Events {
+			if !containsString(WebhookEvents, event) {
+				return fmt.Errorf("webhook '%s': unknown event '%s' (expected %s)", webhook.Name, event, strings.Join(WebhookEvents, ", "))
+			}
+		}
+		for _, pattern := range webhook.Contexts {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return fmt.Errorf("webhook '%s': invalid context pattern '%s'", webhook.Name, pattern)
+			}
+		}
+	}
+	return nil
+}
+
+// parseMode parses an octal permission mode such as "0600"
+func parseMode(value string) (os.FileMode, error) {
+	mode, err := strconv.ParseUint(value, 8, 32)
+	if err != nil {
+		return 0, err
+	}
+	if mode > 0777 {
+		return 0, fmt.Errorf("mode %s out of range", value)
+	}
+	return os.FileMode(mode), nil
+}
+
+// MergeConfig sets the default semantics for merging one context's content
+// into another (e.g. `--import --merge`); flags override it
+type MergeConfig struct {
+	Arrays         string `json:"arrays,omitempty"`          // replace (default), append or union
+	Nulls          string `json:"nulls,omitempty"`           // set (default) keeps null values, delete removes the key, ignore keeps the base value
+	DeleteSentinel string `json:"delete_sentinel,omitempty"` // A string value that removes the key, e.g. "__delete__"
+}
+
+// ImportConfig sets the default checks and format for `--import`; flags override it
+type ImportConfig struct {
+	Format   string `json:"format,omitempty"`   // json (default) or jsonc
+	Validate string `json:"validate,omitempty"` // Schema check policy: off (default), warn or reject
+	Secrets  string `json:"secrets,omitempty"`  // Literal secret policy: off (default), warn or reject
+}
+
+// UIConfig customizes colors and symbols
+type UIConfig struct {
+	Theme     string            `json:"theme,omitempty"`  // default, high-contrast or monochrome
+	Colors    map[string]string `json:"colors,omitempty"` // Role (success, error, info, warning, current) to e.g. "blue,bold"
+	Symbols   SymbolsConfig     `json:"symbols"`
+	Templates TemplatesConfig   `json:"templates"`
+}
+
+// SymbolsConfig overrides the markers used in lists and the built-in picker
+type SymbolsConfig struct {
+	Current  string `json:"current,omitempty"`  // Marks the current context
+	Active   string `json:"active,omitempty"`   // Picker cursor
+	Selected string `json:"selected,omitempty"` // Shown after picking
+}
+
+// TemplatesConfig overrides the built-in picker's promptui templates
+type TemplatesConfig struct {
+	Active   string `json:"active,omitempty"`
+	Inactive string `json:"inactive,omitempty"`
+	Selected string `json:"selected,omitempty"`
+}
+
+// InteractiveConfig controls the interactive context picker
+type InteractiveConfig struct {
+	Picker      string    `json:"picker,omitempty"` // auto (default), fzf or builtin
+	Fzf         FzfConfig `json:"fzf"`
+	LiveRefresh *bool     `json:"live_refresh,omitempty"` // Built-in picker follows added and removed contexts (default on)
+}
+
+// LiveRefreshEnabled reports whether the built-in picker watches the contexts directory
+func (i InteractiveConfig) LiveRefreshEnabled() bool {
+	return i.LiveRefresh == nil || *i.LiveRefresh
+}
+
+// FzfConfig holds options passed to fzf; empty values use occtx's defaults
+type FzfConfig struct {
+	Height  string   `json:"height,omitempty"`  // e.g. "40%"
+	Layout  string   `json:"layout,omitempty"`  // default, reverse or reverse-list
+	Preview bool     `json:"preview,omitempty"` // Show the highlighted context's content
+	Bind    []string `json:"bind,omitempty"`    // Key bindings, e.g. "ctrl-j:down"
+	Args    []string `json:"args,omitempty"`    // Extra arguments appended verbatim
+	// ContentSearch has occtx filter by context content as you type, instead
+	// of fzf's fuzzy matching on names
+	ContentSearch bool `json:"content_search,omitempty"`
+}
+
+// WarningsConfig turns individual warnings on or off
+type WarningsConfig struct {
+	Drift *bool `json:"drift,omitempty"` // Warn when the active config was edited since the last switch (default on)
+}
+
+// DriftEnabled reports whether the active config drift warning is on
+func (w WarningsConfig) DriftEnabled() bool {
+	return w.Drift == nil || *w.Drift
+}
+
+// ProfileConfig points occtx at a separate opencode installation
+type ProfileConfig struct {
+	ConfigDir   string `json:"config_dir"`             // opencode config dir holding opencode.json; "~/" is expanded
+	SettingsDir string `json:"settings_dir,omitempty"` // Defaults to <config_dir>/settings
+}
+
+// ProjectsConfig controls where `occtx projects` looks for project-level contexts
+type ProjectsConfig struct {
+	Roots          []string `json:"roots,omitempty"`           // Workspace roots to scan; "~/" is expanded
+	MaxDepth       int      `json:"max_depth,omitempty"`       // 0 uses DefaultProjectScanDepth
+	ShareWorktrees *bool    `json:"share_worktrees,omitempty"` // Linked git worktrees use the main worktree's contexts and state (default on)
+
+	// Guard is what happens when a project scope would be created in the home
+	// directory, a filesystem root, the global config dir or outside
+	// AllowedRoots: reject (default), warn or off
+	Guard        string   `json:"guard,omitempty"`
+	AllowedRoots []string `json:"allowed_roots,omitempty"` // Project scopes may only be created at or below these; "~/" is expanded
+}
+
+// WorktreesShared reports whether linked git worktrees share the main
+// worktree's project contexts
+func (p ProjectsConfig) WorktreesShared() bool {
+	return p.ShareWorktrees == nil || *p.ShareWorktrees
+}
+
+// MirrorConfig is a read-only context source, e.g. a Dropbox or NFS folder
+type MirrorConfig struct {
+	Name string `json:"name"` // Badge in listings, e.g. "team"
+	Dir  string `json:"dir"`  // "~/" is expanded
+}
+
+// EffectiveSharedDirs returns the configured shared context directories or the default
+func (c *Config) EffectiveSharedDirs() []string {
+	if c.SharedDirs != nil {
+		return c.SharedDirs
+	}
+	return []string{DefaultSharedContextsDir()}
+}
+
+// EffectiveMaxDepth returns the configured scan depth or the default
+func (p ProjectsConfig) EffectiveMaxDepth() int {
+	if p.MaxDepth > 0 {
+		return p.MaxDepth
+	}
+	return DefaultProjectScanDepth
+}
+
+// NamesConfig controls how new context names are validated
+type NamesConfig struct {
+	MaxLength int  `json:"max_length,omitempty"` // 0 uses DefaultMaxNameLength
+	Slugify   bool `json:"slugify,omitempty"`    // Slugify names passed to -n
+
+	// CaseInsensitive resolves context names ignoring case when looking them up
+	CaseInsensitive bool `json:"case_insensitive,omitempty"`
+}
+
+// EffectiveMaxLength returns the configured maximum name length or the default
+func (n NamesConfig) EffectiveMaxLength() int {
+	if n.MaxLength > 0 {
+		return n.MaxLength
+	}
+	return DefaultMaxNameLength
+}
+
+// LoadConfig reads the occtx config file, returning defaults if it doesn't exist
+func LoadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return &Config{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var cfg Config
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("invalid occtx config %s: %v", path, err)
+	}
+
+	return &cfg, nil
+}
@@ -1,25 +1,78 @@
 package config
 
 import (
+	"fmt"
 	"os"
+	"os/user"
 	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
 )
 
 const (
-	// OpenCodeConfigDir is the default directory for opencode configurations
+	// OpenCodeConfigDir is the default directory for opencode configurations,
+	// relative to the home directory
 	OpenCodeConfigDir = ".config/opencode"
+	// OpenCodeAppDir is the opencode directory inside XDG_CONFIG_HOME
+	OpenCodeAppDir = "opencode"
 	// SettingsSubDir is the subdirectory where contexts are stored
 	SettingsSubDir = "settings"
 	// StateFileName is the hidden state file that tracks current/previous contexts
 	StateFileName = ".occtx-state.json"
+	// DirContextFileName names the context the shell hook switches to in a
+	// directory and below
+	DirContextFileName = ".occtx-context"
+	// DirMapFileName maps paths inside a repository, such as the packages of
+	// a monorepo, to the context the shell hook switches to below them
+	DirMapFileName = ".occtx-map.json"
+	// CurrentMarkerFileName is the plain-text file in a settings dir holding the
+	// current context name, for tools that can't run occtx
+	CurrentMarkerFileName = "current-context"
 	// ActiveConfigFileName is the active opencode.json file
 	ActiveConfigFileName = "opencode.json"
 	// ProjectConfigFileName is the project-level config file
 	ProjectConfigFileName = "opencode.json"
 	// ProjectConfigDir is the project-level config directory
 	ProjectConfigDir = "opencode"
+	// ConfigFileName is the occtx configuration file kept in the global config dir
+	ConfigFileName = ".occtx-config.json"
+	// MetadataFileName is the hidden file in a settings dir holding per-context metadata
+	MetadataFileName = ".occtx-meta.json"
+	// ChecksumsFileName is the hidden manifest of context checksums in a settings dir
+	ChecksumsFileName = ".occtx-checksums.json"
+	// PolicyFileName is the organization policy file kept in a scope's config dir
+	PolicyFileName = ".occtx-policy.json"
+	// ModelsCacheFileName is the cached provider model catalog kept next to the occtx config
+	ModelsCacheFileName = ".occtx-models.json"
+	// SelfStatsFileName holds the local command statistics kept next to the occtx config
+	SelfStatsFileName = ".occtx-stats.json"
+	// WebhookSpoolDirName holds events waiting for webhook delivery, next to the occtx config
+	WebhookSpoolDirName = ".occtx-spool"
+	// StashSubDir is the subdirectory of settings where stashed configs are kept
+	StashSubDir = "stash"
+	// TrashSubDir is the subdirectory of settings where replaced contexts are backed up
+	TrashSubDir = "trash"
+	// ArchiveSubDir is the subdirectory of settings where archived contexts are kept
+	ArchiveSubDir = "archive"
+	// BackupSubDir is the subdirectory of settings where backup archives are kept
+	BackupSubDir = "backups"
+	// TemplatesSubDir is the subdirectory of settings where context templates are kept
+	TemplatesSubDir = "templates"
+	// ConfigDirEnvVar overrides the global opencode config directory
+	ConfigDirEnvVar = "OCCTX_CONFIG_DIR"
+	// ReadOnlyEnvVar enables read-only mode when set to a true value
+	ReadOnlyEnvVar = "OCCTX_READONLY"
 )
 
+// ReadOnlyFromEnv reports whether the read-only environment variable is set to true
+func ReadOnlyFromEnv() bool {
+	value, err := strconv.ParseBool(os.Getenv(ReadOnlyEnvVar))
+	return err == nil && value
+}
+
 // Paths holds all the important file paths for occtx
 type Paths struct {
 	// Global level paths (default)
@@ -27,22 +80,35 @@ type Paths struct {
 	GlobalSettingsDir  string // ~/.config/opencode/settings/
 	GlobalActiveConfig string // ~/.config/opencode/opencode.json
 	GlobalStateFile    string // ~/.config/opencode/settings/.occtx-state.json
+	ConfigFile         string // ~/.config/opencode/.occtx-config.json
 
 	// Project level paths
 	ProjectConfigDir    string // ./opencode/
 	ProjectSettingsDir  string // ./opencode/settings/
 	ProjectActiveConfig string // ./opencode.json
 	ProjectStateFile    string // ./opencode/settings/.occtx-state.json
+
+	// Fallback explains why GlobalConfigDir is a temporary directory; empty normally
+	Fallback string
 }
 
 // NewPaths creates a new Paths struct with all paths initialized
 func NewPaths() (*Paths, error) {
-	homeDir, err := os.UserHomeDir()
+	// Without a home directory (some containers and CI runners), keep working
+	// in a temporary directory rather than failing outright
+	var fallback string
+	globalConfigDir, err := OpenCodeGlobalConfigDir()
 	if err != nil {
-		return nil, err
+		fallback = fmt.Sprintf("home directory unavailable (%v)", err)
+	} else if reason := unusableConfigDir(globalConfigDir); reason != "" {
+		fallback = reason
+	}
+	if fallback != "" {
+		if globalConfigDir, err = TemporaryConfigDir(); err != nil {
+			return nil, fmt.Errorf("%s, and no temporary config dir: %v; set --config-dir or %s", fallback, err, ConfigDirEnvVar)
+		}
 	}
 
-	globalConfigDir := filepath.Join(homeDir, OpenCodeConfigDir)
 	globalSettingsDir := filepath.Join(globalConfigDir, SettingsSubDir)
 
 	currentDir, err := os.Getwd()
@@ -58,14 +124,207 @@ func NewPaths() (*Paths, error) {
 		GlobalSettingsDir:  globalSettingsDir,
 		GlobalActiveConfig: filepath.Join(globalConfigDir, ActiveConfigFileName),
 		GlobalStateFile:    filepath.Join(globalSettingsDir, StateFileName),
+		ConfigFile:         filepath.Join(globalConfigDir, ConfigFileName),
 
 		ProjectConfigDir:    projectConfigDir,
 		ProjectSettingsDir:  projectSettingsDir,
 		ProjectActiveConfig: filepath.Join(currentDir, ProjectConfigFileName),
 		ProjectStateFile:    filepath.Join(projectSettingsDir, StateFileName),
+
+		Fallback: fallback,
 	}, nil
 }
 
+// OpenCodeGlobalConfigDir returns the global opencode config directory:
+// $OCCTX_CONFIG_DIR if set, else $XDG_CONFIG_HOME/opencode when
+// XDG_CONFIG_HOME is an absolute path, else ~/.config/opencode. opencode uses
+// the same location on every OS, so on Windows this is
+// %USERPROFILE%\.config\opencode, not %APPDATA%.
+func OpenCodeGlobalConfigDir() (string, error) {
+	if dir := os.Getenv(ConfigDirEnvVar); dir != "" {
+		dir, err := ExpandHome(dir)
+		if err != nil {
+			return "", err
+		}
+		return filepath.Abs(dir)
+	}
+
+	if xdg := os.Getenv("XDG_CONFIG_HOME"); xdg != "" && filepath.IsAbs(xdg) {
+		return filepath.Join(xdg, OpenCodeAppDir), nil
+	}
+
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(homeDir, filepath.FromSlash(OpenCodeConfigDir)), nil
+}
+
+// unusableConfigDir explains why occtx can't keep its contexts in the
+// default config dir, or returns "" if it can. Only a settings dir that
+// doesn't exist yet and can't be created counts: an existing one is still
+// read, with writes reported by the manager, and read-only mode never writes.
+// A directory chosen with --config-dir is reported when written to, since the
+// user asked for it.
+func unusableConfigDir(configDir string) string {
+	if os.Getenv(ConfigDirEnvVar) != "" || ReadOnlyFromEnv() {
+		return ""
+	}
+	settingsDir := filepath.Join(configDir, SettingsSubDir)
+	if info, err := os.Stat(settingsDir); err == nil && info.IsDir() {
+		return ""
+	}
+	for _, dir := range []string{configDir, settingsDir} {
+		if blocked, err := ProbeWritable(dir); err != nil {
+			return fmt.Sprintf("%s is not writable (%v)", blocked, err)
+		}
+	}
+	return ""
+}
+
+// TemporaryConfigDir returns the per-user config directory used when no home
+// directory is available or the config directory can't be created. Its
+// parent is created private to the current user; one that already exists
+// must be, since anyone can create it first in a shared temp dir.
+func TemporaryConfigDir() (string, error) {
+	parent := filepath.Dir(temporaryConfigDir())
+	if err := os.Mkdir(parent, 0700); err != nil && !os.IsExist(err) {
+		return "", err
+	}
+	if err := checkPrivateDir(parent); err != nil {
+		return "", err
+	}
+	return temporaryConfigDir(), nil
+}
+
+// temporaryConfigDir names the per-user config directory in the temp dir
+func temporaryConfigDir() string {
+	name := "occtx"
+	if current, err := user.Current(); err == nil && current.Username != "" {
+		// Windows user names look like DOMAIN\user
+		name += "-" + strings.Map(func(r rune) rune {
+			if r == '-' || r == '_' || r == '.' || unicode.IsLetter(r) || unicode.IsDigit(r) {
+				return r
+			}
+			return '_'
+		}, current.Username)
+	}
+	return filepath.Join(os.TempDir(), name, OpenCodeAppDir)
+}
+
+var (
+	probesMu sync.Mutex
+	probes   = map[string]probeResult{} // ProbeWritable results by directory
+)
+
+type probeResult struct {
+	blocked string
+	err     error
+}
+
+// ProbeWritable checks that a file can be created in dir, or in its nearest
+// existing ancestor when dir doesn't exist yet, and returns the directory
+// that refused it. Results are kept for the life of the process.
+func ProbeWritable(dir string) (string, error) {
+	probesMu.Lock()
+	defer probesMu.Unlock()
+	if result, ok := probes[dir]; ok {
+		return result.blocked, result.err
+	}
+
+	existing := dir
+	for {
+		if _, err := os.Stat(existing); err == nil {
+			break
+		}
+		parent := filepath.Dir(existing)
+		if parent == existing {
+			return "", nil
+		}
+		existing = parent
+	}
+
+	var result probeResult
+	if probe, err := os.CreateTemp(existing, ".occtx-probe-*"); err != nil {
+		result = probeResult{blocked: existing, err: err}
+	} else {
+		probe.Close()
+		os.Remove(probe.Name())
+	}
+	probes[dir] = result
+	return result.blocked, result.err
+}
+
+// ApplyProfile points the global paths at the profile's directories.
+// The occtx config file itself stays in the default location.
+func (p *Paths) ApplyProfile(profile ProfileConfig) error {
+	configDir, err := ExpandHome(profile.ConfigDir)
+	if err != nil {
+		return err
+	}
+
+	settingsDir := filepath.Join(configDir, SettingsSubDir)
+	if profile.SettingsDir != "" {
+		if settingsDir, err = ExpandHome(profile.SettingsDir); err != nil {
+			return err
+		}
+	}
+
+	p.GlobalConfigDir = configDir
+	p.GlobalSettingsDir = settingsDir
+	p.GlobalActiveConfig = filepath.Join(configDir, ActiveConfigFileName)
+	p.GlobalStateFile = filepath.Join(settingsDir, StateFileName)
+	return nil
+}
+
+// GlobalActiveConfigCandidates are the global config files opencode reads,
+// in the order they are detected
+var GlobalActiveConfigCandidates = []string{"opencode.json", "opencode.jsonc"}
+
+// ProjectActiveConfigCandidates are the project config files opencode reads,
+// relative to the project root, in the order they are detected
+var ProjectActiveConfigCandidates = []string{"opencode.json", "opencode.jsonc", ".opencode/opencode.json", ".opencode/opencode.jsonc"}
+
+// ResolveActiveConfigs points the active config paths at the configured
+// files, detecting unset ones among the candidates
+func (p *Paths) ResolveActiveConfigs(active ActiveConfigConfig) error {
+	global, err := resolveActiveConfig(p.GlobalConfigDir, active.Global, GlobalActiveConfigCandidates)
+	if err != nil {
+		return fmt.Errorf("active_config.global: %v", err)
+	}
+	project, err := resolveActiveConfig(filepath.Dir(p.ProjectConfigDir), active.Project, ProjectActiveConfigCandidates)
+	if err != nil {
+		return fmt.Errorf("active_config.project: %v", err)
+	}
+
+	p.GlobalActiveConfig = global
+	p.ProjectActiveConfig = project
+	return nil
+}
+
+// resolveActiveConfig returns dir/name, or the first candidate in dir that
+// exists when name is empty, defaulting to the first candidate
+func resolveActiveConfig(dir, name string, candidates []string) (string, error) {
+	if name != "" {
+		clean := filepath.Clean(filepath.FromSlash(name))
+		if filepath.IsAbs(clean) || clean == ".." || strings.HasPrefix(clean, ".."+string(filepath.Separator)) {
+			return "", fmt.Errorf("'%s' must be a path inside %s", name, dir)
+		}
+		if ext := filepath.Ext(clean); ext != ".json" && ext != ".jsonc" {
+			return "", fmt.Errorf("'%s' must be a .json or .jsonc file", name)
+		}
+		return filepath.Join(dir, clean), nil
+	}
+
+	for _, candidate := range candidates {
+		path := filepath.Join(dir, filepath.FromSlash(candidate))
+		if info, err := os.Stat(path); err == nil && !info.IsDir() {
+			return path, nil
+		}
+	}
+	return filepath.Join(dir, filepath.FromSlash(candidates[0])), nil
+}
+
 // GetContextsDir returns the appropriate contexts directory based on level
 func (p *Paths) GetContextsDir(useProject bool) string {
 	if useProject {
@@ -90,8 +349,77 @@ func (p *Paths) GetStateFilePath(useProject bool) string {
 	return p.GlobalStateFile
 }
 
+// GetCurrentMarkerPath returns the appropriate current-context marker file based on level
+func (p *Paths) GetCurrentMarkerPath(useProject bool) string {
+	return filepath.Join(p.GetContextsDir(useProject), CurrentMarkerFileName)
+}
+
+// GetStashDir returns the appropriate stash directory based on level
+func (p *Paths) GetStashDir(useProject bool) string {
+	return filepath.Join(p.GetContextsDir(useProject), StashSubDir)
+}
+
+// GetTrashDir returns the appropriate trash directory based on level
+func (p *Paths) GetTrashDir(useProject bool) string {
+	return filepath.Join(p.GetContextsDir(useProject), TrashSubDir)
+}
+
+// GetArchiveDir returns the appropriate archive directory based on level
+func (p *Paths) GetArchiveDir(useProject bool) string {
+	return filepath.Join(p.GetContextsDir(useProject), ArchiveSubDir)
+}
+
+// GetBackupDir returns the appropriate backup directory based on level
+func (p *Paths) GetBackupDir(useProject bool) string {
+	return filepath.Join(p.GetContextsDir(useProject), BackupSubDir)
+}
+
+// GetTemplatesDir returns the appropriate context templates directory based on level
+func (p *Paths) GetTemplatesDir(useProject bool) string {
+	return filepath.Join(p.GetContextsDir(useProject), TemplatesSubDir)
+}
+
+// GetSelfStatsPath returns the local command statistics, shared by all scopes and profiles
+func (p *Paths) GetSelfStatsPath() string {
+	return filepath.Join(filepath.Dir(p.ConfigFile), SelfStatsFileName)
+}
+
+// GetModelsCachePath returns the cached model catalog, shared by all scopes and profiles
+func (p *Paths) GetModelsCachePath() string {
+	return filepath.Join(filepath.Dir(p.ConfigFile), ModelsCacheFileName)
+}
+
+// GetWebhookSpoolDir returns the events waiting for delivery, shared by all scopes and profiles
+func (p *Paths) GetWebhookSpoolDir() string {
+	return filepath.Join(filepath.Dir(p.ConfigFile), WebhookSpoolDirName)
+}
+
+// GetPolicyFilePath returns the appropriate policy file based on level
+func (p *Paths) GetPolicyFilePath(useProject bool) string {
+	if useProject {
+		return filepath.Join(p.ProjectConfigDir, PolicyFileName)
+	}
+	return filepath.Join(p.GlobalConfigDir, PolicyFileName)
+}
+
+// GetMetadataFilePath returns the appropriate context metadata file based on level
+func (p *Paths) GetMetadataFilePath(useProject bool) string {
+	return filepath.Join(p.GetContextsDir(useProject), MetadataFileName)
+}
+
+// GetChecksumsFilePath returns the appropriate checksum manifest based on level
+func (p *Paths) GetChecksumsFilePath(useProject bool) string {
+	return filepath.Join(p.GetContextsDir(useProject), ChecksumsFileName)
+}
+
 // EnsureDirectories creates all necessary directories
 func (p *Paths) EnsureDirectories(useProject bool) error {
+	return p.EnsureDirectoriesMode(useProject, DefaultDirMode)
+}
+
+// EnsureDirectoriesMode creates all necessary directories with the given
+// mode, before the umask
+func (p *Paths) EnsureDirectoriesMode(useProject bool, perm os.FileMode) error {
 	var dirs []string
 
 	if useProject {
@@ -101,7 +429,7 @@ func (p *Paths) EnsureDirectories(useProject bool) error {
 	}
 
 	for _, dir := range dirs {
-		if err := os.MkdirAll(dir, 0755); err != nil {
+		if err := os.MkdirAll(dir, perm); err != nil {
 			return err
 		}
 	}
@@ -120,12 +448,38 @@ func (p *Paths) ProjectContextsExist() bool {
 		return false
 	}
 
-	// Check if there are any .json files (excluding state file)
+	// Check if there are any .json files (excluding hidden occtx files)
 	for _, entry := range entries {
-		if !entry.IsDir() && filepath.Ext(entry.Name()) == ".json" && entry.Name() != StateFileName {
+		if !entry.IsDir() && filepath.Ext(entry.Name()) == ".json" && !strings.HasPrefix(entry.Name(), ".") {
 			return true
 		}
 	}
 
 	return false
 }
+
+// DefaultSharedContextsDir returns the system-wide read-only context directory
+func DefaultSharedContextsDir() string {
+	if runtime.GOOS == "windows" {
+		programData := os.Getenv("ProgramData")
+		if programData == "" {
+			programData = `C:\ProgramData`
+		}
+		return filepath.Join(programData, "occtx", "contexts")
+	}
+	return "/etc/occtx/contexts"
+}
+
+// ExpandHome replaces a leading "~" in path with the user's home directory
+func ExpandHome(path string) (string, error) {
+	if path != "~" && !strings.HasPrefix(path, "~/") {
+		return path, nil
+	}
+
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(homeDir, strings.TrimPrefix(path, "~")), nil
+}
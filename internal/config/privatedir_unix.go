@@ -0,0 +1,30 @@
+//go:build !windows
+
+package config
+
+import (
+	"fmt"
+	"os"
+	"syscall"
+)
+
+// checkPrivateDir returns an error unless dir is a directory, not a symlink,
+// owned by the current user and closed to everyone else
+func checkPrivateDir(dir string) error {
+	info, err := os.Lstat(dir)
+	if err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("%s is not a directory", dir)
+	}
+	if stat, ok := info.Sys().(*syscall.Stat_t); ok {
+		if uid := os.Getuid(); int(stat.Uid) != uid {
+			return fmt.Errorf("%s is owned by uid %d, not the current user (uid %d)", dir, stat.Uid, uid)
+		}
+	}
+	if perm := info.Mode().Perm(); perm != 0700 {
+		return fmt.Errorf("%s has mode %04o, expected 0700", dir, perm)
+	}
+	return nil
+}
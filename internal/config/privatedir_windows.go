@@ -0,0 +1,21 @@
+//go:build windows
+
+package config
+
+import (
+	"fmt"
+	"os"
+)
+
+// checkPrivateDir returns an error unless dir is a directory. The temp dir
+// is already per user on Windows, and ACLs, not modes, guard it.
+func checkPrivateDir(dir string) error {
+	info, err := os.Lstat(dir)
+	if err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("%s is not a directory", dir)
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// MainWorktreeRoot returns the root of the main worktree when dir is the
+// root of a linked git worktree, whose .git is a file pointing into the main
+// repository's .git/worktrees. It returns "" for any other directory, and
+// for worktrees of a bare repository, which have no main worktree.
+func MainWorktreeRoot(dir string) (string, error) {
+	dotGit := filepath.Join(dir, ".git")
+	info, err := os.Stat(dotGit)
+	if os.IsNotExist(err) || (err == nil && info.IsDir()) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	data, err := os.ReadFile(dotGit)
+	if err != nil {
+		return "", err
+	}
+	gitDir, ok := strings.CutPrefix(strings.TrimSpace(string(data)), "gitdir:")
+	if !ok {
+		return "", fmt.Errorf("%s: not a gitdir file", dotGit)
+	}
+	gitDir = strings.TrimSpace(gitDir)
+	if !filepath.IsAbs(gitDir) {
+		gitDir = filepath.Join(dir, gitDir)
+	}
+
+	// Submodules have a gitdir file too, but no commondir
+	data, err = os.ReadFile(filepath.Join(gitDir, "commondir"))
+	if os.IsNotExist(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	commonDir := strings.TrimSpace(string(data))
+	if !filepath.IsAbs(commonDir) {
+		commonDir = filepath.Join(gitDir, commonDir)
+	}
+	commonDir = filepath.Clean(commonDir)
+
+	if filepath.Base(commonDir) != ".git" {
+		return "", nil
+	}
+	return filepath.Dir(commonDir), nil
+}
+
+// UseMainWorktree points the project contexts, state and policy at the main
+// worktree when the working directory is a linked git worktree without
+// contexts of its own. The project active config stays in the worktree,
+// where opencode reads it. It returns the main worktree's root, or "" when
+// nothing changed.
+func (p *Paths) UseMainWorktree() (string, error) {
+	if _, err := os.Stat(p.ProjectSettingsDir); err == nil {
+		return "", nil
+	}
+
+	root, err := MainWorktreeRoot(filepath.Dir(p.ProjectConfigDir))
+	if err != nil || root == "" {
+		return "", err
+	}
+
+	p.ProjectConfigDir = filepath.Join(root, ProjectConfigDir)
+	p.ProjectSettingsDir = filepath.Join(p.ProjectConfigDir, SettingsSubDir)
+	p.ProjectStateFile = filepath.Join(p.ProjectSettingsDir, StateFileName)
+	return root, nil
+}
@@ -0,0 +1,67 @@
+package context
+
+import (
+	"fmt"
+	"os"
+	"sort"
+)
+
+// writeActiveConfig writes the active config atomically. The new file takes
+// over what the file it replaces had: its permissions (unless
+// active_config.mode normalizes them), its extended attributes where the
+// platform has them, and with active_config.preserve_owner its owner and
+// group. The file replaced is the current active config, which differs from
+// path when a switch moves it between opencode.json and opencode.jsonc.
+func (m *Manager) writeActiveConfig(path string, data []byte) error {
+	mode, normalized, err := m.config.ActiveConfig.FileMode()
+	if err != nil {
+		return err
+	}
+
+	replaced := m.paths.GetActiveConfigPath(m.useProject)
+	info, err := os.Stat(replaced)
+	if os.IsNotExist(err) && replaced != path {
+		replaced = path
+		info, err = os.Stat(path)
+	}
+	if os.IsNotExist(err) {
+		if normalized {
+			return writeFileAtomicWith(path, data, mode, nil, nil)
+		}
+		return writeFileAtomic(path, data, m.fileMode())
+	}
+	if err != nil {
+		return err
+	}
+	if !normalized {
+		mode = info.Mode().Perm()
+	}
+
+	attrs, err := readXattrs(replaced)
+	if err != nil {
+		m.logf(VerbosityDebug, "cannot read extended attributes of %s: %v", replaced, err)
+	}
+
+	prepare := func(tempPath string) error {
+		if m.config.ActiveConfig.PreserveOwner {
+			if err := copyFileOwner(info, tempPath); err != nil {
+				return fmt.Errorf("active_config.preserve_owner: cannot keep the owner of %s: %v", replaced, err)
+			}
+		}
+
+		names := make([]string, 0, len(attrs))
+		for name := range attrs {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			if err := writeXattr(tempPath, name, attrs[name]); err != nil {
+				m.warnf("cannot keep extended attribute %s of %s: %v", name, replaced, err)
+			}
+		}
+		return nil
+	}
+
+	m.logf(VerbosityDebug, "writing %s with mode %04o", path, mode)
+	return writeFileAtomicWith(path, data, mode, prepare, nil)
+}
@@ -0,0 +1,80 @@
+package context
+
+import (
+	"encoding/json"
+	"os"
+	"strings"
+)
+
+// activeConfigFor returns the active config file a switch to ctx writes and
+// its contents, resolved as resolveForActive does. A JSONC context keeps its
+// comments in opencode.jsonc; where the file can only hold JSON (a configured
+// .json name, or active_config.strict_json) they are stripped.
+func (m *Manager) activeConfigFor(ctx *Context) (string, []byte, error) {
+	// Local overrides and conditional sections are resolved to plain JSON
+	conditional, err := ctx.hasConditionals()
+	if err != nil {
+		return "", nil, err
+	}
+	comments := ctx.Format == FormatJSONC && !conditional
+	if _, ok := m.LocalOverridePath(ctx.Name); ok {
+		comments = false
+	}
+	data, err := m.resolveForActive(ctx)
+	if err != nil {
+		return "", nil, err
+	}
+
+	path, keepComments := m.activeTarget(comments)
+	if comments && !keepComments {
+		parsed, err := ctx.parsed()
+		if err != nil {
+			return "", nil, err
+		}
+		if data, err = json.MarshalIndent(parsed, "", "  "); err != nil {
+			return "", nil, err
+		}
+		data = append(data, '\n')
+	}
+	return path, data, nil
+}
+
+// activeTarget returns the active config file to write content with or
+// without comments to, and whether the comments can be kept there
+func (m *Manager) activeTarget(comments bool) (string, bool) {
+	path := m.paths.GetActiveConfigPath(m.useProject)
+	keepComments := strings.HasSuffix(path, ".jsonc")
+	configured := m.config.ActiveConfig.Global
+	if m.useProject {
+		configured = m.config.ActiveConfig.Project
+	}
+	if m.config.ActiveConfig.StrictJSON {
+		keepComments = false
+	} else if configured == "" && comments && !keepComments {
+		// Plain JSON is valid JSONC, so only JSONC contexts move the file
+		path = strings.TrimSuffix(path, ".json") + ".jsonc"
+		keepComments = true
+	}
+	return path, keepComments
+}
+
+// retireActiveConfig removes the active config a switch replaced with a file
+// of another name, so opencode doesn't read both, and points the manager at
+// the new one
+func (m *Manager) retireActiveConfig(path string) error {
+	old := m.paths.GetActiveConfigPath(m.useProject)
+	if old == path {
+		return nil
+	}
+	if err := os.Remove(old); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	m.logf(VerbosityVerbose, "replaced %s with %s", old, path)
+
+	if m.useProject {
+		m.paths.ProjectActiveConfig = path
+	} else {
+		m.paths.GlobalActiveConfig = path
+	}
+	return nil
+}
@@ -0,0 +1,177 @@
+package context
+
+import (
+	"fmt"
+	"os"
+	"os/user"
+	"strconv"
+	"time"
+)
+
+// StrictEnvVar enables strict mode when set to a true value
+const StrictEnvVar = "OCCTX_STRICT"
+
+// Approval records who approved a context and the content they approved
+type Approval struct {
+	By       string    `json:"by"`
+	At       time.Time `json:"at"`
+	Checksum string    `json:"checksum"` // Digest of the approved file; any change makes it a draft again
+}
+
+// ApprovalStatus describes a context's place in the approval workflow
+type ApprovalStatus string
+
+const (
+	StatusDraft    ApprovalStatus = "draft"    // Never approved, or approval revoked
+	StatusApproved ApprovalStatus = "approved" // Approved and unchanged since
+	StatusModified ApprovalStatus = "modified" // Approved, but the file changed afterwards
+)
+
+// SetStrict enables or disables strict mode
+func (m *Manager) SetStrict(strict bool) {
+	m.strict = strict
+}
+
+// IsStrict reports whether strict mode is on
+func (m *Manager) IsStrict() bool {
+	return m.strict
+}
+
+// StrictFromEnv reports whether the strict environment variable is set to true
+func StrictFromEnv() bool {
+	value, err := strconv.ParseBool(os.Getenv(StrictEnvVar))
+	return err == nil && value
+}
+
+// ApproveContext marks a context as approved in its current form. Only the
+// owner of the context file may approve it.
+func (m *Manager) ApproveContext(name string) (*Approval, error) {
+	if err := m.CheckWritable("approve context"); err != nil {
+		return nil, err
+	}
+
+	ctx, err := m.GetContext(name)
+	if err != nil {
+		return nil, err
+	}
+	if err := ctx.CheckModifiable("approve"); err != nil {
+		return nil, err
+	}
+	if err := checkFileOwner(ctx.FilePath); err != nil {
+		return nil, fmt.Errorf("cannot approve '%s': %v", ctx.Name, err)
+	}
+
+	data, err := os.ReadFile(ctx.FilePath)
+	if err != nil {
+		return nil, err
+	}
+
+	approval := &Approval{By: currentUserName(), At: time.Now(), Checksum: checksum(data)}
+	err = m.updateMetadata(func(meta *metadataFile) {
+		entry := meta.Contexts[ctx.Name]
+		if entry == nil {
+			entry = &ContextMeta{}
+			meta.Contexts[ctx.Name] = entry
+		}
+		entry.Approval = approval
+		entry.Log = appendLogEntry(entry.Log, newLogEntry(LogApproved, "approved"))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return approval, nil
+}
+
+// RevokeApproval returns a context to draft. Like approving, only the owner
+// of the context file may do this.
+func (m *Manager) RevokeApproval(name string) error {
+	if err := m.CheckWritable("revoke approval"); err != nil {
+		return err
+	}
+
+	ctx, err := m.GetContext(name)
+	if err != nil {
+		return err
+	}
+	if err := ctx.CheckModifiable("revoke approval of"); err != nil {
+		return err
+	}
+	if err := checkFileOwner(ctx.FilePath); err != nil {
+		return fmt.Errorf("cannot revoke approval of '%s': %v", ctx.Name, err)
+	}
+
+	return m.updateMetadata(func(meta *metadataFile) {
+		if entry := meta.Contexts[ctx.Name]; entry != nil {
+			entry.Approval = nil
+			entry.Log = appendLogEntry(entry.Log, newLogEntry(LogApproved, "marked as draft"))
+		}
+	})
+}
+
+// ApprovalStatusOf returns the approval status of a context. Shared contexts
+// are managed elsewhere and count as approved.
+func (m *Manager) ApprovalStatusOf(ctx *Context) (ApprovalStatus, error) {
+	if ctx.Shared {
+		return StatusApproved, nil
+	}
+
+	meta, err := m.GetContextMeta(ctx.Name)
+	if err != nil {
+		return "", err
+	}
+	if meta == nil || meta.Approval == nil {
+		return StatusDraft, nil
+	}
+
+	data := ctx.raw
+	if data == nil {
+		if data, err = os.ReadFile(ctx.FilePath); err != nil {
+			return "", err
+		}
+	}
+	if checksum(data) != meta.Approval.Checksum {
+		return StatusModified, nil
+	}
+	return StatusApproved, nil
+}
+
+// checkApproved enforces approval.required in strict mode
+func (m *Manager) checkApproved(ctx *Context) error {
+	if !m.strict || !m.config.Approval.Required {
+		return nil
+	}
+
+	status, err := m.ApprovalStatusOf(ctx)
+	if err != nil {
+		return err
+	}
+	if status == StatusApproved {
+		return nil
+	}
+	return &NotApprovedError{Context: ctx.Name, Modified: status == StatusModified}
+}
+
+// NotApprovedError is returned in strict mode for contexts that aren't approved
+type NotApprovedError struct {
+	Context  string
+	Modified bool // Approved once, but changed since
+}
+
+func (e *NotApprovedError) Error() string {
+	if e.Modified {
+		return fmt.Sprintf("context '%s' changed since it was approved; re-approve it with 'occtx approve %s' (strict mode)", e.Context, e.Context)
+	}
+	return fmt.Sprintf("context '%s' is a draft; approve it with 'occtx approve %s' before switching (strict mode)", e.Context, e.Context)
+}
+
+// currentUserName names the approving user
+func currentUserName() string {
+	if u, err := user.Current(); err == nil && u.Username != "" {
+		return u.Username
+	}
+	if name := os.Getenv("USER"); name != "" {
+		return name
+	}
+	return os.Getenv("USERNAME")
+}
@@ -0,0 +1,128 @@
+package context
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// ArchiveContext moves a context into the archive directory, where it is left
+// out of listings, switching and the picker until it is unarchived. Its
+// metadata, including approval, is kept.
+func (m *Manager) ArchiveContext(name string) error {
+	if err := m.CheckWritable("archive context"); err != nil {
+		return err
+	}
+
+	context, err := m.GetContext(name)
+	if err != nil {
+		return err
+	}
+	if err := context.CheckModifiable("archive"); err != nil {
+		return err
+	}
+
+	state, err := m.loadState()
+	if err != nil {
+		return err
+	}
+	if state.Current == context.Name {
+		return fmt.Errorf("cannot archive current context '%s'. Switch to another context first", context.Name)
+	}
+	if state.Default == context.Name {
+		return fmt.Errorf("cannot archive default context '%s'. Run 'occtx default unset' first", context.Name)
+	}
+
+	if existing, ok := m.findArchivedContext(context.Name); ok {
+		return fmt.Errorf("an archived context named '%s' already exists (%s); unarchive or rename one of them first", context.Name, existing)
+	}
+
+	archiveDir := m.paths.GetArchiveDir(m.useProject)
+	if err := os.MkdirAll(archiveDir, m.dirMode()); err != nil {
+		return err
+	}
+
+	if err := os.Rename(context.FilePath, filepath.Join(archiveDir, filepath.Base(context.FilePath))); err != nil {
+		return err
+	}
+	if err := m.forgetChecksum(context.FilePath); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	if err := m.updateMetadata(func(meta *metadataFile) {
+		if entry, ok := meta.Contexts[context.Name]; ok {
+			entry.ArchivedAt = &now
+		} else {
+			meta.Contexts[context.Name] = &ContextMeta{ArchivedAt: &now}
+		}
+	}); err != nil {
+		return err
+	}
+
+	// Don't leave `occtx -` pointing at an archived context
+	return m.updateState(func(state *State) error {
+		if state.Previous != context.Name {
+			return errStateUnchanged
+		}
+		state.Previous = ""
+		return nil
+	})
+}
+
+// UnarchiveContext moves an archived context back into the contexts directory
+func (m *Manager) UnarchiveContext(name string) error {
+	if err := m.CheckWritable("unarchive context"); err != nil {
+		return err
+	}
+	if err := validateContextName(name); err != nil {
+		return err
+	}
+
+	archivedPath, ok := m.findArchivedContext(name)
+	if !ok {
+		return fmt.Errorf("no archived context named '%s'", name)
+	}
+
+	contextsDir := m.paths.GetContextsDir(m.useProject)
+	for _, format := range GetAllFormats() {
+		if _, err := os.Stat(filepath.Join(contextsDir, name+format.FileExtension())); err == nil {
+			return fmt.Errorf("context '%s' already exists; rename or delete it before unarchiving", name)
+		}
+	}
+	if err := m.checkCaseConflict(name, ""); err != nil {
+		return err
+	}
+
+	contextPath := filepath.Join(contextsDir, filepath.Base(archivedPath))
+	if err := os.Rename(archivedPath, contextPath); err != nil {
+		return err
+	}
+	if err := m.recordChecksum(contextPath); err != nil {
+		return err
+	}
+
+	return m.updateMetadata(func(meta *metadataFile) {
+		if entry, ok := meta.Contexts[name]; ok {
+			entry.ArchivedAt = nil
+		}
+	})
+}
+
+// ListArchivedContexts returns the contexts in the archive
+func (m *Manager) ListArchivedContexts() ([]*Context, error) {
+	return listContextsIn(m.paths.GetArchiveDir(m.useProject))
+}
+
+// findArchivedContext returns the path of the archived context name, if any
+func (m *Manager) findArchivedContext(name string) (string, bool) {
+	archiveDir := m.paths.GetArchiveDir(m.useProject)
+	for _, format := range GetAllFormats() {
+		path := filepath.Join(archiveDir, name+format.FileExtension())
+		if _, err := os.Stat(path); err == nil {
+			return path, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,54 @@
+package context
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/hungthai1401/occtx/internal/config"
+)
+
+// AutoSwitchEnvVar remembers the directory context file the shell hook last
+// acted on, as "path=name", so a failed or undone switch isn't retried before
+// every prompt
+const AutoSwitchEnvVar = "OCCTX_AUTO_SWITCHED"
+
+// FindDirContext looks for a directory context file in dir and its parents
+// and returns the context it names with the file's path. The first line of
+// the file is the name; blank files are ignored. A directory map file whose
+// longest matching prefix covers dir counts too; in one directory, the
+// context file wins. name is "" when there is none.
+func FindDirContext(dir string) (name, path string, err error) {
+	dir, err = filepath.Abs(dir)
+	if err != nil {
+		return "", "", err
+	}
+
+	start := dir
+	for {
+		path = filepath.Join(dir, config.DirContextFileName)
+		file, err := os.Open(path)
+		if err == nil {
+			scanner := bufio.NewScanner(file)
+			scanner.Scan()
+			name = strings.TrimSpace(scanner.Text())
+			file.Close()
+			if name != "" {
+				return name, path, nil
+			}
+		} else if !os.IsNotExist(err) {
+			return "", "", err
+		}
+
+		if name, path, err = matchDirMap(dir, start); err != nil || name != "" {
+			return name, path, err
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", "", nil
+		}
+		dir = parent
+	}
+}
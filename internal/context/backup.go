@@ -0,0 +1,354 @@
+package context
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hungthai1401/occtx/internal/config"
+)
+
+const (
+	// backupPrefix and backupExt frame backup archive names:
+	// occtx-backup-<UTC stamp>.tar.gz
+	backupPrefix = "occtx-backup-"
+	backupExt    = ".tar.gz"
+	// backupStampLayout orders backups by name as well as by time
+	backupStampLayout = "20060102-150405"
+)
+
+// BackupInfo describes a backup archive
+type BackupInfo struct {
+	Name      string    // File name inside the backup directory
+	Path      string    // Absolute path of the archive
+	CreatedAt time.Time // When the backup was taken
+	Size      int64     // Archive size in bytes
+}
+
+// CreateBackup archives the scope's settings directory (contexts, state,
+// metadata, stashes, archived and trashed contexts) together with the active
+// config and the occtx config file into a compressed tarball in the backup directory. Backups hold API
+// keys like the contexts themselves, so the archive is readable only by the
+// current user.
+func (m *Manager) CreateBackup() (*BackupInfo, error) {
+	defer m.timeOperation("backup")()
+
+	if err := m.CheckWritable("create backup"); err != nil {
+		return nil, err
+	}
+
+	backupDir := m.paths.GetBackupDir(m.useProject)
+	if err := os.MkdirAll(backupDir, privateDirMode); err != nil {
+		return nil, err
+	}
+
+	data, err := m.buildBackupArchive()
+	if err != nil {
+		return nil, err
+	}
+	if err := CheckFreeSpace(backupDir, uint64(len(data))); err != nil {
+		return nil, err
+	}
+
+	// Two backups in the same second get a numeric suffix
+	now := time.Now().UTC()
+	stamp := now.Format(backupStampLayout)
+	name := backupPrefix + stamp + backupExt
+	for i := 2; ; i++ {
+		if _, err := os.Stat(filepath.Join(backupDir, name)); os.IsNotExist(err) {
+			break
+		}
+		name = fmt.Sprintf("%s%s-%d%s", backupPrefix, stamp, i, backupExt)
+	}
+
+	path := filepath.Join(backupDir, name)
+	if err := writeFileAtomic(path, data, privateFileMode); err != nil {
+		return nil, err
+	}
+	m.logf(VerbosityVerbose, "backup written to %s (%d bytes)", path, len(data))
+
+	return &BackupInfo{
+		Name:      name,
+		Path:      path,
+		CreatedAt: now,
+		Size:      int64(len(data)),
+	}, nil
+}
+
+// buildBackupArchive returns the gzipped tarball of the scope. Settings files
+// are stored under "settings/", the active config as "opencode.json" and the
+// occtx config file under its own name.
+func (m *Manager) buildBackupArchive() ([]byte, error) {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+
+	contextsDir := m.paths.GetContextsDir(m.useProject)
+	backupDir := m.paths.GetBackupDir(m.useProject)
+	err := filepath.WalkDir(contextsDir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			if path == backupDir {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !entry.Type().IsRegular() || strings.HasSuffix(entry.Name(), ".tmp") {
+			return nil
+		}
+
+		rel, err := filepath.Rel(contextsDir, path)
+		if err != nil {
+			return err
+		}
+		return addBackupFile(tw, path, filepath.ToSlash(filepath.Join(config.SettingsSubDir, rel)))
+	})
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	activeConfigPath := m.paths.GetActiveConfigPath(m.useProject)
+	if err := addBackupFile(tw, activeConfigPath, config.ActiveConfigFileName); err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	if err := addBackupFile(tw, m.paths.ConfigFile, config.ConfigFileName); err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	if err := tw.Close(); err != nil {
+		return nil, err
+	}
+	if err := gz.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// addBackupFile copies the file at path into the archive under name
+func addBackupFile(tw *tar.Writer, path, name string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+
+	header := &tar.Header{
+		Name:    name,
+		Mode:    int64(info.Mode().Perm()),
+		Size:    int64(len(data)),
+		ModTime: info.ModTime(),
+	}
+	if err := tw.WriteHeader(header); err != nil {
+		return err
+	}
+	_, err = tw.Write(data)
+	return err
+}
+
+// ListBackups returns the scope's backups, newest first
+func (m *Manager) ListBackups() ([]BackupInfo, error) {
+	backupDir := m.paths.GetBackupDir(m.useProject)
+	entries, err := os.ReadDir(backupDir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var backups []BackupInfo
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasPrefix(name, backupPrefix) || !strings.HasSuffix(name, backupExt) {
+			continue
+		}
+
+		stamp := strings.TrimSuffix(strings.TrimPrefix(name, backupPrefix), backupExt)
+		if len(stamp) < len(backupStampLayout) {
+			continue
+		}
+		createdAt, err := time.Parse(backupStampLayout, stamp[:len(backupStampLayout)])
+		if err != nil {
+			continue
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+
+		backups = append(backups, BackupInfo{
+			Name:      name,
+			Path:      filepath.Join(backupDir, name),
+			CreatedAt: createdAt,
+			Size:      info.Size(),
+		})
+	}
+
+	sort.Slice(backups, func(i, j int) bool {
+		if !backups[i].CreatedAt.Equal(backups[j].CreatedAt) {
+			return backups[i].CreatedAt.After(backups[j].CreatedAt)
+		}
+		return backupSequence(backups[i].Name) > backupSequence(backups[j].Name)
+	})
+	return backups, nil
+}
+
+// backupSequence returns the numeric suffix of a same-second backup, or 1
+func backupSequence(name string) int {
+	stamp := strings.TrimSuffix(strings.TrimPrefix(name, backupPrefix), backupExt)
+	sequence := 1
+	if rest := stamp[len(backupStampLayout):]; rest != "" {
+		fmt.Sscanf(rest, "-%d", &sequence)
+	}
+	return sequence
+}
+
+// PruneBackups deletes all but the newest keep backups and returns the deleted ones
+func (m *Manager) PruneBackups(keep int) ([]BackupInfo, error) {
+	if err := m.CheckWritable("prune backups"); err != nil {
+		return nil, err
+	}
+	if keep < 1 {
+		return nil, fmt.Errorf("must keep at least one backup")
+	}
+
+	backups, err := m.ListBackups()
+	if err != nil || len(backups) <= keep {
+		return nil, err
+	}
+
+	var pruned []BackupInfo
+	for _, backup := range backups[keep:] {
+		if err := os.Remove(backup.Path); err != nil {
+			return pruned, err
+		}
+		m.logf(VerbosityDebug, "pruned backup %s", backup.Path)
+		pruned = append(pruned, backup)
+	}
+	return pruned, nil
+}
+
+// backupFile is one file read back from a backup archive
+type backupFile struct {
+	path string // Where the file is restored to
+	data []byte
+	mode os.FileMode
+}
+
+// RestoreBackup puts the files of the named backup back in place: the
+// settings directory's files, the active config and the occtx config. Files
+// created since the backup are left alone. The current state is backed up
+// first, so a restore can be undone; that backup is returned.
+func (m *Manager) RestoreBackup(name string) (*BackupInfo, error) {
+	if err := m.CheckWritable("restore backup"); err != nil {
+		return nil, err
+	}
+	defer m.timeOperation("restore")()
+
+	backups, err := m.ListBackups()
+	if err != nil {
+		return nil, err
+	}
+	var backup *BackupInfo
+	for i := range backups {
+		if backups[i].Name == name || backups[i].Path == name {
+			backup = &backups[i]
+			break
+		}
+	}
+	if backup == nil {
+		return nil, fmt.Errorf("backup '%s' not found; run 'occtx backup list'", name)
+	}
+
+	// Read the whole archive before anything is overwritten
+	files, err := m.readBackupArchive(backup.Path)
+	if err != nil {
+		return nil, fmt.Errorf("backup '%s': %v", backup.Name, err)
+	}
+
+	safety, err := m.CreateBackup()
+	if err != nil {
+		return nil, fmt.Errorf("cannot back up the current state before restoring: %v", err)
+	}
+
+	for _, file := range files {
+		if err := os.MkdirAll(filepath.Dir(file.path), m.dirMode()); err != nil {
+			return safety, err
+		}
+		if err := writeFileAtomic(file.path, file.data, file.mode); err != nil {
+			return safety, err
+		}
+		m.logf(VerbosityDebug, "restored %s", file.path)
+	}
+	m.logf(VerbosityVerbose, "restored %d file(s) from %s", len(files), backup.Path)
+	return safety, nil
+}
+
+// readBackupArchive reads the files of a backup archive and where each is
+// restored to, the reverse of buildBackupArchive
+func (m *Manager) readBackupArchive(path string) ([]backupFile, error) {
+	archive, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer archive.Close()
+
+	gz, err := gzip.NewReader(archive)
+	if err != nil {
+		return nil, err
+	}
+	tr := tar.NewReader(gz)
+
+	contextsDir := m.paths.GetContextsDir(m.useProject)
+	var files []backupFile
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		var target string
+		switch rel, inSettings := strings.CutPrefix(header.Name, config.SettingsSubDir+"/"); {
+		case inSettings && filepath.IsLocal(filepath.FromSlash(rel)):
+			target = filepath.Join(contextsDir, filepath.FromSlash(rel))
+		case header.Name == config.ActiveConfigFileName:
+			target = m.paths.GetActiveConfigPath(m.useProject)
+		case header.Name == config.ConfigFileName:
+			target = m.paths.ConfigFile
+		default:
+			return nil, fmt.Errorf("unexpected file '%s' in archive", header.Name)
+		}
+
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			return nil, err
+		}
+		mode := os.FileMode(header.Mode).Perm()
+		if mode == 0 {
+			mode = m.fileMode()
+		}
+		files = append(files, backupFile{path: target, data: data, mode: mode})
+	}
+	return files, nil
+}
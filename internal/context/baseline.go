@@ -0,0 +1,232 @@
+package context
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+)
+
+// ChangeKind classifies a difference between two contexts
+type ChangeKind string
+
+const (
+	ChangeAdded   ChangeKind = "added"   // Only in the local context
+	ChangeRemoved ChangeKind = "removed" // Only in the baseline
+	ChangeChanged ChangeKind = "changed" // In both, with different values
+)
+
+// ContextChange is one difference between a baseline and a local context
+// (or any two contexts, the second taking the local role)
+type ContextChange struct {
+	Path     string      `json:"path"`           // Dotted key path of the value
+	Kind     ChangeKind  `json:"kind"`           // How the local context differs
+	Baseline interface{} `json:"from,omitempty"` // Value in the baseline, nil when added
+	Local    interface{} `json:"to,omitempty"`   // Value in the local context, nil when removed
+}
+
+// BaselineComparison compares a local context with the shared (team)
+// context it tracks
+type BaselineComparison struct {
+	Name          string          // Local context
+	SharedPath    string          // File of the shared context
+	Changes       []ContextChange // From the shared context to the local one, by path
+	LocalModified bool            // The local copy was edited since it was taken
+	SharedUpdated bool            // The shared context changed since the local copy was taken
+
+	local         *Context
+	sharedData    []byte
+	sharedContent map[string]interface{}
+}
+
+// UpToDate reports whether the local context matches the shared one
+func (c *BaselineComparison) UpToDate() bool {
+	return len(c.Changes) == 0
+}
+
+// CompareWithShared compares the local context name with the shared context
+// sharedName (the same name when empty) from shared_dirs. Whether the local
+// copy was modified is judged against the shared content it was copied or
+// last updated from; contexts that predate that tracking count as modified
+// whenever they differ.
+func (m *Manager) CompareWithShared(name, sharedName string) (*BaselineComparison, error) {
+	local, err := m.GetContext(name)
+	if err != nil {
+		return nil, err
+	}
+	if local.Shared {
+		return nil, fmt.Errorf("'%s' is a shared context with no local copy (take one with 'occtx copy %s')", local.Name, local.Name)
+	}
+
+	if sharedName == "" {
+		sharedName = local.Name
+	}
+	sharedPath, _, ok := m.findSharedContext(sharedName)
+	if !ok {
+		return nil, fmt.Errorf("no shared context '%s' in the shared directories", sharedName)
+	}
+
+	sharedData, err := os.ReadFile(sharedPath)
+	if err != nil {
+		return nil, err
+	}
+	sharedContent, err := parseContextData(sharedPath, sharedData)
+	if err != nil {
+		return nil, fmt.Errorf("invalid JSON in shared context '%s': %v", sharedName, err)
+	}
+
+	comparison := &BaselineComparison{
+		Name:       local.Name,
+		SharedPath: sharedPath,
+		Changes:    diffContextData(sharedContent, local.Data),
+
+		local:         local,
+		sharedData:    sharedData,
+		sharedContent: sharedContent,
+	}
+
+	meta, err := m.GetContextMeta(local.Name)
+	if err != nil {
+		return nil, err
+	}
+	if meta != nil && meta.Baseline != "" {
+		comparison.LocalModified = contentChecksum(local.Data) != meta.Baseline
+		comparison.SharedUpdated = contentChecksum(sharedContent) != meta.Baseline
+	} else {
+		comparison.LocalModified = !comparison.UpToDate()
+	}
+
+	return comparison, nil
+}
+
+// UpdateFromShared replaces the local context with the shared one when the
+// local copy has no changes of its own (a fast-forward). With force, local
+// changes are moved to the trash and replaced too. It returns the comparison
+// made before updating.
+func (m *Manager) UpdateFromShared(name, sharedName string, force bool) (*BaselineComparison, error) {
+	if err := m.CheckWritable("update context"); err != nil {
+		return nil, err
+	}
+
+	comparison, err := m.CompareWithShared(name, sharedName)
+	if err != nil {
+		return nil, err
+	}
+	local, sharedContent := comparison.local, comparison.sharedContent
+	if comparison.UpToDate() {
+		return comparison, m.recordBaseline(local.Name, sharedContent)
+	}
+	if comparison.LocalModified && !force {
+		return nil, fmt.Errorf("context '%s' has local changes since it was copied from %s; review them with 'occtx diff %s --shared' or use --force to replace them (the local version is moved to the trash)",
+			local.Name, comparison.SharedPath, local.Name)
+	}
+
+	contextsDir := m.paths.GetContextsDir(m.useProject)
+	target := filepath.Join(contextsDir, local.Name+filepath.Ext(comparison.SharedPath))
+	if comparison.LocalModified {
+		if _, err := m.trashContextFile(local.Name, local.FilePath); err != nil {
+			return nil, fmt.Errorf("failed to back up context '%s': %v", local.Name, err)
+		}
+		if err := m.recordSource(local.Name, Source{Kind: SourceCopy, From: comparison.SharedPath}); err != nil {
+			return nil, err
+		}
+	} else if target != local.FilePath {
+		// The shared context changed format; don't leave the old file shadowing it
+		if err := os.Remove(local.FilePath); err != nil {
+			return nil, err
+		}
+		if err := m.forgetChecksum(local.FilePath); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := writeFileAtomic(target, comparison.sharedData, m.fileMode()); err != nil {
+		return nil, err
+	}
+	if err := m.recordChecksum(target); err != nil {
+		return nil, err
+	}
+	m.logf(VerbosityVerbose, "updated '%s' from %s", local.Name, comparison.SharedPath)
+	if err := m.logChanges(local.Name, LogUpdated, "from "+comparison.SharedPath+": ", local.Data, sharedContent); err != nil {
+		return nil, err
+	}
+
+	return comparison, m.recordBaseline(local.Name, sharedContent)
+}
+
+// recordBaseline remembers the shared content a local context now matches
+func (m *Manager) recordBaseline(name string, content map[string]interface{}) error {
+	return m.updateMetadata(func(meta *metadataFile) {
+		entry, ok := meta.Contexts[name]
+		if !ok {
+			entry = &ContextMeta{Source: Source{Kind: SourceCopy}}
+			meta.Contexts[name] = entry
+		}
+		entry.Baseline = contentChecksum(content)
+	})
+}
+
+// contentChecksum digests parsed context content, so formatting and comments
+// don't count as changes
+func contentChecksum(content map[string]interface{}) string {
+	// Marshal sorts object keys, giving a canonical encoding
+	data, err := json.Marshal(content)
+	if err != nil {
+		return ""
+	}
+	return checksum(data)
+}
+
+// DiffContexts lists the differences from context from to context to
+func (m *Manager) DiffContexts(from, to string) ([]ContextChange, error) {
+	fromContext, err := m.GetContext(from)
+	if err != nil {
+		return nil, err
+	}
+	toContext, err := m.GetContext(to)
+	if err != nil {
+		return nil, err
+	}
+
+	return diffContextData(fromContext.Data, toContext.Data), nil
+}
+
+// diffContextData lists the differences from base to local. Objects are
+// compared key by key; arrays and scalars are compared as whole values.
+func diffContextData(base, local map[string]interface{}) []ContextChange {
+	var changes []ContextChange
+	diffValues(nil, base, local, &changes)
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Path < changes[j].Path
+	})
+	return changes
+}
+
+func diffValues(path KeyPath, base, local interface{}, changes *[]ContextChange) {
+	baseObject, baseIsObject := base.(map[string]interface{})
+	localObject, localIsObject := local.(map[string]interface{})
+	if !baseIsObject || !localIsObject {
+		if !reflect.DeepEqual(base, local) {
+			*changes = append(*changes, ContextChange{Path: path.String(), Kind: ChangeChanged, Baseline: base, Local: local})
+		}
+		return
+	}
+
+	for key, baseValue := range baseObject {
+		child := append(append(KeyPath{}, path...), key)
+		localValue, ok := localObject[key]
+		if !ok {
+			*changes = append(*changes, ContextChange{Path: child.String(), Kind: ChangeRemoved, Baseline: baseValue})
+			continue
+		}
+		diffValues(child, baseValue, localValue, changes)
+	}
+	for key, localValue := range localObject {
+		if _, ok := baseObject[key]; !ok {
+			child := append(append(KeyPath{}, path...), key)
+			*changes = append(*changes, ContextChange{Path: child.String(), Kind: ChangeAdded, Local: localValue})
+		}
+	}
+}
@@ -0,0 +1,140 @@
+package context
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// LogKind classifies an entry of a context's changelog
+type LogKind string
+
+const (
+	LogCreated  LogKind = "created"  // Created, imported or copied
+	LogEdited   LogKind = "edited"   // Edited with occtx -e
+	LogMerged   LogKind = "merged"   // Imported data was merged in
+	LogApplied  LogKind = "applied"  // A provider block was applied
+	LogUpdated  LogKind = "updated"  // Updated from the shared baseline
+	LogRenamed  LogKind = "renamed"  // Renamed from another name
+	LogApproved LogKind = "approved" // Approved, or returned to draft
+	LogNote     LogKind = "note"     // Free-form note added with 'occtx note add'
+)
+
+// maxLogEntries bounds the changelog of a context; the oldest entries go first
+const maxLogEntries = 200
+
+// maxSummaryPaths is how many changed key paths a summary names
+const maxSummaryPaths = 5
+
+// LogEntry is one entry of a context's changelog: who did what, and when
+type LogEntry struct {
+	At      time.Time `json:"at"`
+	By      string    `json:"by,omitempty"`
+	Kind    LogKind   `json:"kind"`
+	Summary string    `json:"summary"`
+}
+
+// ContextLog returns the changelog of a context, oldest entry first.
+// Contexts created before changes were logged start with their first
+// change since.
+func (m *Manager) ContextLog(name string) ([]LogEntry, error) {
+	ctx, err := m.GetContext(name)
+	if err != nil {
+		return nil, err
+	}
+
+	meta, err := m.GetContextMeta(ctx.Name)
+	if err != nil || meta == nil {
+		return nil, err
+	}
+	return meta.Log, nil
+}
+
+// AddNote appends a free-form note to the changelog of a context
+func (m *Manager) AddNote(name, note string) error {
+	if err := m.CheckWritable("add note"); err != nil {
+		return err
+	}
+
+	note = strings.TrimSpace(note)
+	if note == "" {
+		return fmt.Errorf("the note is empty")
+	}
+
+	ctx, err := m.GetContext(name)
+	if err != nil {
+		return err
+	}
+	if err := ctx.CheckModifiable("add a note to"); err != nil {
+		return err
+	}
+
+	return m.appendLog(ctx.Name, LogNote, note)
+}
+
+// RecordEdit logs the changes made to a context since its content was
+// before, e.g. by an editor. Nothing is logged when the content is unchanged.
+func (m *Manager) RecordEdit(name string, before map[string]interface{}) error {
+	ctx, err := m.GetContext(name)
+	if err != nil {
+		return err
+	}
+	return m.logChanges(ctx.Name, LogEdited, "", before, ctx.Data)
+}
+
+// logChanges logs the differences from before to after, prefixed by
+// prefix. Nothing is logged when there are none.
+func (m *Manager) logChanges(name string, kind LogKind, prefix string, before, after map[string]interface{}) error {
+	changes := diffContextData(before, after)
+	if len(changes) == 0 {
+		return nil
+	}
+	return m.appendLog(name, kind, prefix+summarizeChanges(changes))
+}
+
+// appendLog adds an entry by the current user to the changelog of a context
+func (m *Manager) appendLog(name string, kind LogKind, summary string) error {
+	return m.updateMetadata(func(meta *metadataFile) {
+		entry := meta.Contexts[name]
+		if entry == nil {
+			entry = &ContextMeta{}
+			meta.Contexts[name] = entry
+		}
+		entry.Log = appendLogEntry(entry.Log, newLogEntry(kind, summary))
+	})
+}
+
+// newLogEntry returns an entry by the current user, made now
+func newLogEntry(kind LogKind, summary string) LogEntry {
+	return LogEntry{At: time.Now(), By: currentUserName(), Kind: kind, Summary: summary}
+}
+
+// appendLogEntry appends entry, dropping the oldest entries past maxLogEntries
+func appendLogEntry(log []LogEntry, entry LogEntry) []LogEntry {
+	log = append(log, entry)
+	if len(log) > maxLogEntries {
+		log = append([]LogEntry(nil), log[len(log)-maxLogEntries:]...)
+	}
+	return log
+}
+
+// summarizeChanges names the changed key paths the way diff marks them, e.g.
+// "~model, +provider.openai". Values are left out so secrets stay out of the log.
+func summarizeChanges(changes []ContextChange) string {
+	var paths []string
+	for i, change := range changes {
+		if i == maxSummaryPaths {
+			paths = append(paths, fmt.Sprintf("and %d more", len(changes)-maxSummaryPaths))
+			break
+		}
+		mark := "~"
+		switch change.Kind {
+		case ChangeAdded:
+			mark = "+"
+		case ChangeRemoved:
+			mark = "-"
+		}
+		paths = append(paths, mark+change.Path)
+	}
+	return strings.Join(paths, ", ")
+}
@@ -0,0 +1,264 @@
+package context
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// VerifyStatus is the outcome of checking a context against the checksum manifest
+type VerifyStatus string
+
+const (
+	VerifyOK        VerifyStatus = "ok"        // Content matches the recorded checksum
+	VerifyModified  VerifyStatus = "modified"  // Changed outside occtx since the last write
+	VerifyMissing   VerifyStatus = "missing"   // Recorded in the manifest but the file is gone
+	VerifyUntracked VerifyStatus = "untracked" // File exists but was never recorded
+	VerifyCorrupt   VerifyStatus = "corrupt"   // File is not valid JSON
+)
+
+// VerifyResult reports the integrity of one context file
+type VerifyResult struct {
+	Name   string
+	File   string
+	Status VerifyStatus
+	Detail string
+}
+
+// checksumManifest maps context file names (e.g. "work.json") to SHA-256 digests
+type checksumManifest struct {
+	Files map[string]string `json:"files"`
+}
+
+// UpdateChecksum records the current content of a context as known-good,
+// e.g. after editing it or to accept an out-of-band change
+func (m *Manager) UpdateChecksum(name string) error {
+	if err := m.CheckWritable("update checksum"); err != nil {
+		return err
+	}
+
+	ctx, err := m.GetContext(name)
+	if err != nil {
+		return err
+	}
+	if ctx.Shared {
+		return nil
+	}
+
+	return m.recordChecksum(ctx.FilePath)
+}
+
+// AcceptChecksums makes the manifest match the files on disk for the given
+// results: modified and untracked files are re-recorded, missing ones dropped.
+// Corrupt files are never accepted; they are returned as an error once the
+// others are recorded.
+func (m *Manager) AcceptChecksums(results []VerifyResult) error {
+	if err := m.CheckWritable("update checksums"); err != nil {
+		return err
+	}
+
+	contextsDir := m.paths.GetContextsDir(m.useProject)
+	var corrupt []string
+	for _, result := range results {
+		path := filepath.Join(contextsDir, result.File)
+		var err error
+		switch result.Status {
+		case VerifyModified, VerifyUntracked:
+			err = m.recordChecksum(path)
+		case VerifyMissing:
+			err = m.forgetChecksum(path)
+		case VerifyCorrupt:
+			corrupt = append(corrupt, result.Name)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(corrupt) > 0 {
+		return fmt.Errorf("cannot accept corrupt context(s) %s; fix them or restore them with --restore", strings.Join(corrupt, ", "))
+	}
+	return nil
+}
+
+// VerifyContexts checks the named contexts, or all local contexts and manifest
+// entries when no names are given, against the checksum manifest
+func (m *Manager) VerifyContexts(names ...string) ([]VerifyResult, error) {
+	manifest, err := m.loadChecksums()
+	if err != nil {
+		return nil, err
+	}
+
+	contextsDir := m.paths.GetContextsDir(m.useProject)
+	var files []string
+	if len(names) == 0 {
+		contexts, err := listContextsIn(contextsDir)
+		if err != nil {
+			return nil, err
+		}
+		seen := make(map[string]bool)
+		for _, ctx := range contexts {
+			files = append(files, filepath.Base(ctx.FilePath))
+			seen[filepath.Base(ctx.FilePath)] = true
+		}
+		for file := range manifest.Files {
+			if !seen[file] {
+				files = append(files, file)
+			}
+		}
+		sort.Strings(files)
+	} else {
+		for _, name := range names {
+			ctx, err := m.GetContext(name)
+			if err != nil {
+				return nil, err
+			}
+			if err := ctx.CheckModifiable("verify"); err != nil {
+				return nil, err
+			}
+			files = append(files, filepath.Base(ctx.FilePath))
+		}
+	}
+
+	results := make([]VerifyResult, 0, len(files))
+	for _, file := range files {
+		if err := m.checkCanceled("verify contexts"); err != nil {
+			return nil, err
+		}
+		results = append(results, verifyFile(filepath.Join(contextsDir, file), manifest.Files[file]))
+	}
+
+	return results, nil
+}
+
+// verifyFile checks one context file against its recorded digest ("" if untracked)
+func verifyFile(path, recorded string) VerifyResult {
+	file := filepath.Base(path)
+	result := VerifyResult{Name: contextNameFromFile(file), File: file}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		result.Status = VerifyMissing
+		return result
+	}
+	if err != nil {
+		result.Status = VerifyCorrupt
+		result.Detail = err.Error()
+		return result
+	}
+
+	if _, err := parseContextData(path, data); err != nil {
+		result.Status = VerifyCorrupt
+		result.Detail = err.Error()
+		return result
+	}
+
+	switch recorded {
+	case "":
+		result.Status = VerifyUntracked
+	case checksum(data):
+		result.Status = VerifyOK
+	default:
+		result.Status = VerifyModified
+	}
+	return result
+}
+
+// contextNameFromFile strips the context file extension
+func contextNameFromFile(file string) string {
+	for _, format := range GetAllFormats() {
+		if ext := format.FileExtension(); filepath.Ext(file) == ext {
+			return file[:len(file)-len(ext)]
+		}
+	}
+	return file
+}
+
+// checksum returns the hex SHA-256 digest of data
+func checksum(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// recordChecksum stores the digest of the context file at path
+func (m *Manager) recordChecksum(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	return m.updateChecksums(func(manifest *checksumManifest) {
+		manifest.Files[filepath.Base(path)] = checksum(data)
+	})
+}
+
+// moveChecksum carries a digest over when a context file is renamed
+func (m *Manager) moveChecksum(oldPath, newPath string) error {
+	return m.updateChecksums(func(manifest *checksumManifest) {
+		if sum, ok := manifest.Files[filepath.Base(oldPath)]; ok {
+			manifest.Files[filepath.Base(newPath)] = sum
+			delete(manifest.Files, filepath.Base(oldPath))
+		}
+	})
+}
+
+// forgetChecksum drops the digest of a deleted context file
+func (m *Manager) forgetChecksum(path string) error {
+	return m.updateChecksums(func(manifest *checksumManifest) {
+		delete(manifest.Files, filepath.Base(path))
+	})
+}
+
+// updateChecksums loads the manifest, applies change and saves it
+func (m *Manager) updateChecksums(change func(manifest *checksumManifest)) error {
+	manifest, err := m.loadChecksums()
+	if err != nil {
+		return err
+	}
+
+	change(manifest)
+	return m.saveChecksums(manifest)
+}
+
+// loadChecksums reads the manifest, returning an empty one if none exists
+func (m *Manager) loadChecksums() (*checksumManifest, error) {
+	manifestPath := m.paths.GetChecksumsFilePath(m.useProject)
+
+	manifest := &checksumManifest{Files: make(map[string]string)}
+	data, err := os.ReadFile(manifestPath)
+	if os.IsNotExist(err) {
+		return manifest, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(data, manifest); err != nil {
+		return nil, fmt.Errorf("invalid checksum manifest %s: %v", manifestPath, err)
+	}
+	if manifest.Files == nil {
+		manifest.Files = make(map[string]string)
+	}
+
+	return manifest, nil
+}
+
+// saveChecksums writes the manifest atomically
+func (m *Manager) saveChecksums(manifest *checksumManifest) error {
+	manifestPath := m.paths.GetChecksumsFilePath(m.useProject)
+	if err := os.MkdirAll(filepath.Dir(manifestPath), m.dirMode()); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return writeFileAtomic(manifestPath, data, m.fileMode())
+}
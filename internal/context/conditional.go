@@ -0,0 +1,281 @@
+package context
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"runtime"
+	"sort"
+	"strings"
+)
+
+const (
+	// whenKey makes the object holding it conditional: it is dropped unless
+	// every condition matches the machine
+	whenKey = "$when"
+	// overridesKey lists conditional objects deep-merged into the object
+	// holding it when their $when matches
+	overridesKey = "$overrides"
+)
+
+// Platform is what $when conditions are matched against
+type Platform struct {
+	OS   string // runtime.GOOS, e.g. "darwin"
+	Arch string // runtime.GOARCH, e.g. "arm64"
+	Host string // Hostname, matched case-insensitively and with globs
+}
+
+// CurrentPlatform describes the machine occtx runs on
+func CurrentPlatform() Platform {
+	host, _ := os.Hostname()
+	return Platform{OS: runtime.GOOS, Arch: runtime.GOARCH, Host: host}
+}
+
+// HasConditionals reports whether data uses $when or $overrides anywhere
+func HasConditionals(data map[string]interface{}) bool {
+	var walk func(value interface{}) bool
+	walk = func(value interface{}) bool {
+		switch v := value.(type) {
+		case map[string]interface{}:
+			if _, ok := v[whenKey]; ok {
+				return true
+			}
+			if _, ok := v[overridesKey]; ok {
+				return true
+			}
+			for _, child := range v {
+				if walk(child) {
+					return true
+				}
+			}
+		case []interface{}:
+			for _, item := range v {
+				if walk(item) {
+					return true
+				}
+			}
+		}
+		return false
+	}
+	return walk(data)
+}
+
+// hasConditionals reports whether ctx has conditional sections. A context
+// loaded undecoded is only decoded when its text mentions one of the keys.
+func (ctx *Context) hasConditionals() (bool, error) {
+	if ctx.Data == nil && ctx.raw != nil &&
+		!bytes.Contains(ctx.raw, []byte(`"`+whenKey+`"`)) && !bytes.Contains(ctx.raw, []byte(`"`+overridesKey+`"`)) {
+		return false, nil
+	}
+	data, err := ctx.parsed()
+	if err != nil {
+		return false, err
+	}
+	return HasConditionals(data), nil
+}
+
+// ResolveConditionals returns data as it applies to platform: objects whose
+// $when doesn't match are removed (from their parent object or array), the
+// matching entries of $overrides are deep-merged in order into the object
+// holding them, and the $when and $overrides keys themselves are dropped.
+// data is left untouched.
+func ResolveConditionals(data map[string]interface{}, platform Platform) (map[string]interface{}, error) {
+	if _, ok := data[whenKey]; ok {
+		return nil, fmt.Errorf("%s is not allowed at the top level of a context", whenKey)
+	}
+	resolved, _, err := resolveConditional("", data, platform)
+	if err != nil {
+		return nil, err
+	}
+	return resolved.(map[string]interface{}), nil
+}
+
+// resolveConditional resolves value at path; keep is false when a $when
+// removes it
+func resolveConditional(at string, value interface{}, platform Platform) (resolved interface{}, keep bool, err error) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if when, ok := v[whenKey]; ok {
+			match, err := matchWhen(joinPath(at, whenKey), when, platform)
+			if err != nil || !match {
+				return nil, false, err
+			}
+		}
+
+		object := make(map[string]interface{}, len(v))
+		for key, child := range v {
+			if key != whenKey && key != overridesKey {
+				object[key] = child
+			}
+		}
+
+		if overrides, ok := v[overridesKey]; ok {
+			entries, ok := overrides.([]interface{})
+			if !ok {
+				return nil, false, fmt.Errorf("%s must be an array of objects", joinPath(at, overridesKey))
+			}
+			for i, entry := range entries {
+				entryPath := fmt.Sprintf("%s.%d", joinPath(at, overridesKey), i)
+				override, ok := entry.(map[string]interface{})
+				if !ok {
+					return nil, false, fmt.Errorf("%s must be an object", entryPath)
+				}
+				if _, ok := override[whenKey]; !ok {
+					return nil, false, fmt.Errorf("%s needs a %s condition", entryPath, whenKey)
+				}
+				match, err := matchWhen(joinPath(entryPath, whenKey), override[whenKey], platform)
+				if err != nil {
+					return nil, false, err
+				}
+				if !match {
+					continue
+				}
+				overlay := make(map[string]interface{}, len(override))
+				for key, child := range override {
+					if key != whenKey {
+						overlay[key] = child
+					}
+				}
+				object = MergeData(object, overlay, MergeOptions{Arrays: ArraysReplace, Nulls: NullsDelete})
+			}
+		}
+
+		for key, child := range object {
+			resolvedChild, keepChild, err := resolveConditional(joinPath(at, key), child, platform)
+			if err != nil {
+				return nil, false, err
+			}
+			if keepChild {
+				object[key] = resolvedChild
+			} else {
+				delete(object, key)
+			}
+		}
+		return object, true, nil
+
+	case []interface{}:
+		items := make([]interface{}, 0, len(v))
+		for i, item := range v {
+			resolvedItem, keepItem, err := resolveConditional(fmt.Sprintf("%s.%d", at, i), item, platform)
+			if err != nil {
+				return nil, false, err
+			}
+			if keepItem {
+				items = append(items, resolvedItem)
+			}
+		}
+		return items, true, nil
+	}
+	return value, true, nil
+}
+
+// matchWhen reports whether every condition of a $when object matches.
+// Each condition is a string or an array of strings, any of which may match.
+func matchWhen(at string, when interface{}, platform Platform) (bool, error) {
+	conditions, ok := when.(map[string]interface{})
+	if !ok {
+		return false, fmt.Errorf("%s must be an object such as {\"os\": \"darwin\"}", at)
+	}
+
+	keys := make([]string, 0, len(conditions))
+	for key := range conditions {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		var actual string
+		switch key {
+		case "os":
+			actual = platform.OS
+		case "arch":
+			actual = platform.Arch
+		case "host":
+			actual = strings.ToLower(platform.Host)
+		default:
+			return false, fmt.Errorf("%s: unknown condition '%s' (expected os, arch or host)", at, key)
+		}
+
+		var patterns []string
+		switch v := conditions[key].(type) {
+		case string:
+			patterns = []string{v}
+		case []interface{}:
+			for _, item := range v {
+				pattern, ok := item.(string)
+				if !ok {
+					return false, fmt.Errorf("%s.%s must be a string or an array of strings", at, key)
+				}
+				patterns = append(patterns, pattern)
+			}
+		default:
+			return false, fmt.Errorf("%s.%s must be a string or an array of strings", at, key)
+		}
+
+		matched := false
+		for _, pattern := range patterns {
+			if key == "host" {
+				ok, err := path.Match(strings.ToLower(pattern), actual)
+				if err != nil {
+					return false, fmt.Errorf("%s.host: invalid pattern '%s'", at, pattern)
+				}
+				matched = matched || ok
+			} else {
+				matched = matched || pattern == actual
+			}
+		}
+		if !matched {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// joinPath appends key to a dotted path
+func joinPath(at, key string) string {
+	if at == "" {
+		return key
+	}
+	return at + "." + key
+}
+
+// resolveForActive merges the local override of ctx and resolves its
+// conditional sections for this machine, replacing ctx.Data, and returns the
+// bytes to write as the active config. Contexts with neither are written as
+// stored, comments included.
+func (m *Manager) resolveForActive(ctx *Context) ([]byte, error) {
+	overridden, err := m.applyLocalOverride(ctx)
+	if err != nil {
+		return nil, err
+	}
+	conditional, err := ctx.hasConditionals()
+	if err != nil {
+		return nil, err
+	}
+	if !overridden && !conditional {
+		if ctx.raw != nil {
+			return ctx.raw, nil
+		}
+		return os.ReadFile(ctx.FilePath)
+	}
+
+	data, err := ctx.parsed()
+	if err != nil {
+		return nil, err
+	}
+	resolved, err := ResolveConditionals(data, CurrentPlatform())
+	if err != nil {
+		return nil, fmt.Errorf("context '%s': %v", ctx.Name, err)
+	}
+	out, err := json.MarshalIndent(resolved, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	m.logf(VerbosityVerbose, "resolved conditional sections of '%s' for %s/%s", ctx.Name, runtime.GOOS, runtime.GOARCH)
+
+	ctx.Data = resolved
+	ctx.raw = nil
+	return append(out, '\n'), nil
+}
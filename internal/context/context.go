@@ -1,6 +1,8 @@
 package context
 
 import (
+	"bytes"
+	gocontext "context"
 	"encoding/json"
 	"fmt"
 	"os"
@@ -9,6 +11,7 @@ import (
 	"time"
 
 	"github.com/hungthai1401/occtx/internal/config"
+	"github.com/hungthai1401/occtx/internal/util"
 )
 
 // Context represents an opencode context
@@ -16,12 +19,39 @@ type Context struct {
 	Name     string                 `json:"-"` // Name is derived from filename
 	Data     map[string]interface{} `json:"-"` // Raw JSON data
 	FilePath string                 `json:"-"` // Full path to the context file
+	Format   ContextFormat          `json:"-"` // Format implied by the file extension
+	Shared   bool                   `json:"-"` // Read-only context from a shared directory or a mirror
+	Mirror   string                 `json:"-"` // Name of the mirror a shared context comes from
+
+	raw []byte // File contents, set until Data is decoded
 }
 
 // Manager handles context operations
 type Manager struct {
-	paths      *config.Paths
-	useProject bool
+	paths        *config.Paths
+	config       *config.Config
+	useProject   bool
+	readOnly     bool
+	offline      bool
+	force        bool
+	strict       bool
+	skipRequires bool
+	profile      string
+	worktree     string            // Main worktree whose project contexts a linked worktree shares
+	ctx          gocontext.Context // Bounds slow work; nil is never done
+
+	writableChecked bool                                            // checkDirWritable ran
+	rootChecked     bool                                            // checkProjectRoot ran
+	writableErr     error                                           // Its result
+	warn            func(message string)                            // Receives non-fatal warnings, e.g. policy findings
+	warned          []string                                        // Warnings reported so far; fatal in strict mode
+	verbosity       Verbosity                                       // How much logf reports
+	logger          func(level Verbosity, message string)           // Receives diagnostics from logf
+	timing          func(op string, elapsed time.Duration)          // Receives operation timings for --timings
+	confirm         func(question string) bool                      // Asks before guarded switches; nil declines
+	review          bool                                            // Review every switch, as with switch.review
+	reviewer        func(name string, changes []ContextChange) bool // Confirms a reviewed switch; nil declines
+	spooled         func()                                          // Told when events were spooled for webhooks
 }
 
 // GetPaths returns the paths configuration
@@ -29,23 +59,76 @@ func (m *Manager) GetPaths() *config.Paths {
 	return m.paths
 }
 
-// NewManager creates a new context manager
+// GetConfig returns the occtx configuration
+func (m *Manager) GetConfig() *config.Config {
+	return m.config
+}
+
+// NewManager creates a new context manager using the profile from OCCTX_PROFILE, if any
 func NewManager(useProject bool) (*Manager, error) {
+	return NewManagerForProfile(useProject, profileFromEnv())
+}
+
+// NewManagerForProfile creates a new context manager for the named workspace
+// profile; an empty name uses the default paths
+func NewManagerForProfile(useProject bool, profile string) (*Manager, error) {
 	paths, err := config.NewPaths()
 	if err != nil {
 		return nil, err
 	}
 
-	return &Manager{
+	cfg, err := config.LoadConfig(paths.ConfigFile)
+	if err != nil {
+		return nil, err
+	}
+
+	manager := &Manager{
 		paths:      paths,
+		config:     cfg,
 		useProject: useProject,
-	}, nil
+		readOnly:   readOnlyFromEnv(),
+		offline:    offlineFromEnv(),
+		strict:     StrictFromEnv(),
+		warn:       printWarning,
+	}
+
+	if err := manager.UseProfile(profile); err != nil {
+		return nil, err
+	}
+	if err := paths.ResolveActiveConfigs(cfg.ActiveConfig); err != nil {
+		return nil, fmt.Errorf("invalid occtx config %s: %v", paths.ConfigFile, err)
+	}
+	if err := cfg.Permissions.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid occtx config %s: %v", paths.ConfigFile, err)
+	}
+	if err := config.ValidateHooks(cfg.Hooks); err != nil {
+		return nil, fmt.Errorf("invalid occtx config %s: %v", paths.ConfigFile, err)
+	}
+	if err := config.ValidateWebhooks(cfg.Webhooks); err != nil {
+		return nil, fmt.Errorf("invalid occtx config %s: %v", paths.ConfigFile, err)
+	}
+	// After resolving the active config, which stays in the worktree. A .git
+	// occtx can't make sense of leaves the worktree on its own contexts.
+	if cfg.Projects.WorktreesShared() {
+		manager.worktree, _ = paths.UseMainWorktree()
+	}
+
+	return manager, nil
 }
 
 // ListContexts returns all available contexts
 func (m *Manager) ListContexts() ([]*Context, error) {
-	contextsDir := m.paths.GetContextsDir(m.useProject)
+	defer m.timeOperation("list")()
+	contexts, err := listContextsIn(m.paths.GetContextsDir(m.useProject))
+	if err != nil {
+		return nil, err
+	}
+
+	return m.appendSharedContexts(contexts), nil
+}
 
+// listContextsIn returns the contexts stored in contextsDir
+func listContextsIn(contextsDir string) ([]*Context, error) {
 	// Check if directory exists
 	if _, err := os.Stat(contextsDir); os.IsNotExist(err) {
 		return []*Context{}, nil
@@ -62,26 +145,33 @@ func (m *Manager) ListContexts() ([]*Context, error) {
 			continue
 		}
 
-		// Skip state file
-		if entry.Name() == config.StateFileName {
+		// Skip hidden files such as the state and metadata files
+		if strings.HasPrefix(entry.Name(), ".") {
 			continue
 		}
 
 		// Check for both .json and .jsonc files
 		var name string
+		var format ContextFormat
 		if strings.HasSuffix(entry.Name(), ".json") {
 			name = strings.TrimSuffix(entry.Name(), ".json")
+			format = FormatJSON
 		} else if strings.HasSuffix(entry.Name(), ".jsonc") {
 			name = strings.TrimSuffix(entry.Name(), ".jsonc")
+			format = FormatJSONC
 		} else {
 			continue // Skip non-JSON files
 		}
+		if isLocalOverrideName(name) {
+			continue // Machine-local overrides are merged in when switching
+		}
 
 		contextPath := filepath.Join(contextsDir, entry.Name())
 
 		context := &Context{
 			Name:     name,
 			FilePath: contextPath,
+			Format:   format,
 		}
 
 		contexts = append(contexts, context)
@@ -92,14 +182,59 @@ func (m *Manager) ListContexts() ([]*Context, error) {
 
 // GetContext loads a specific context by name
 func (m *Manager) GetContext(name string) (*Context, error) {
+	ctx, err := m.readContext(name)
+	if err != nil {
+		return nil, err
+	}
+
+	contextData, err := parseContextData(ctx.FilePath, ctx.raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid JSON in context '%s': %v", ctx.Name, err)
+	}
+	ctx.Data = contextData
+	ctx.raw = nil
+	return ctx, nil
+}
+
+// loadContextRaw loads a context for writing it out as-is: the file is
+// validated but not decoded, so large contexts don't cost a JSON tree unless
+// something asks for ctx.Data through parsed
+func (m *Manager) loadContextRaw(name string) (*Context, error) {
+	ctx, err := m.readContext(name)
+	if err != nil {
+		return nil, err
+	}
+
+	data := ctx.raw
+	if ctx.Format == FormatJSONC {
+		data = util.StripJSONCComments(data)
+	}
+	if trimmed := bytes.TrimLeft(data, " \t\r\n"); len(trimmed) == 0 || trimmed[0] != '{' || !json.Valid(data) {
+		// Decode after all, for the same error (or result) GetContext gives
+		if _, err := ctx.parsed(); err != nil {
+			return nil, fmt.Errorf("invalid JSON in context '%s': %v", ctx.Name, err)
+		}
+	}
+	return ctx, nil
+}
+
+// readContext finds a context by name and reads its file, leaving it undecoded
+func (m *Manager) readContext(name string) (*Context, error) {
 	if err := validateContextName(name); err != nil {
 		return nil, err
 	}
 
+	name, err := m.resolveContextName(name)
+	if err != nil {
+		return nil, err
+	}
+
 	contextsDir := m.paths.GetContextsDir(m.useProject)
 
-	// Try .json first, then .jsonc
+	// Try .json first, then .jsonc, then the shared directories
 	var contextPath string
+	var shared bool
+	var mirror string
 	jsonPath := filepath.Join(contextsDir, name+".json")
 	jsoncPath := filepath.Join(contextsDir, name+".jsonc")
 
@@ -107,45 +242,71 @@ func (m *Manager) GetContext(name string) (*Context, error) {
 		contextPath = jsonPath
 	} else if _, err := os.Stat(jsoncPath); err == nil {
 		contextPath = jsoncPath
+	} else if sharedPath, sharedMirror, ok := m.findSharedContext(name); ok {
+		contextPath = sharedPath
+		shared = true
+		mirror = sharedMirror
 	} else {
-		return nil, fmt.Errorf("context '%s' not found", name)
+		return nil, m.notFound(name)
 	}
 
 	data, err := os.ReadFile(contextPath)
 	if err != nil {
 		return nil, err
 	}
+	m.logf(VerbosityDebug, "read %d bytes from %s", len(data), contextPath)
 
-	// For JSONC, we need to strip comments before parsing
-	var contextData map[string]interface{}
+	format := FormatJSON
 	if strings.HasSuffix(contextPath, ".jsonc") {
-		// Simple comment removal for JSONC (remove lines starting with //)
-		lines := strings.Split(string(data), "\n")
-		var cleanLines []string
-		for _, line := range lines {
-			trimmed := strings.TrimSpace(line)
-			if !strings.HasPrefix(trimmed, "//") {
-				cleanLines = append(cleanLines, line)
-			}
-		}
-		cleanData := strings.Join(cleanLines, "\n")
-
-		if err := json.Unmarshal([]byte(cleanData), &contextData); err != nil {
-			return nil, fmt.Errorf("invalid JSON in context '%s': %v", name, err)
-		}
+		format = FormatJSONC
+	}
+	if mirror != "" {
+		m.logf(VerbosityVerbose, "context '%s': %s (%s, mirror %s)", name, contextPath, format.DisplayName(), mirror)
+	} else if shared {
+		m.logf(VerbosityVerbose, "context '%s': %s (%s, shared)", name, contextPath, format.DisplayName())
 	} else {
-		if err := json.Unmarshal(data, &contextData); err != nil {
-			return nil, fmt.Errorf("invalid JSON in context '%s': %v", name, err)
-		}
+		m.logf(VerbosityVerbose, "context '%s': %s (%s)", name, contextPath, format.DisplayName())
 	}
 
 	return &Context{
 		Name:     name,
-		Data:     contextData,
 		FilePath: contextPath,
+		Format:   format,
+		Shared:   shared,
+		Mirror:   mirror,
+		raw:      data,
 	}, nil
 }
 
+// parsed returns ctx.Data, decoding the file contents first if the context
+// was loaded with loadContextRaw
+func (ctx *Context) parsed() (map[string]interface{}, error) {
+	if ctx.Data != nil || ctx.raw == nil {
+		return ctx.Data, nil
+	}
+	data, err := parseContextData(ctx.FilePath, ctx.raw)
+	if err != nil {
+		return nil, err
+	}
+	ctx.Data = data
+	return data, nil
+}
+
+// parseContextData decodes the contents of the context file at path,
+// stripping comments when it is JSONC
+func parseContextData(path string, data []byte) (map[string]interface{}, error) {
+	if util.IsJSONCPath(path) {
+		return util.ParseJSONC(data)
+	}
+
+	var contextData map[string]interface{}
+	if err := json.Unmarshal(data, &contextData); err != nil {
+		return nil, err
+	}
+
+	return contextData, nil
+}
+
 // CreateContext creates a new context from current active config (JSON format)
 func (m *Manager) CreateContext(name string) error {
 	return m.CreateContextWithFormat(name, FormatJSON)
@@ -153,56 +314,120 @@ func (m *Manager) CreateContext(name string) error {
 
 // CreateContextWithFormat creates a new context with specified format
 func (m *Manager) CreateContextWithFormat(name string, format ContextFormat) error {
+	if err := m.CheckWritable("create context"); err != nil {
+		return err
+	}
+	defer m.timeOperation("create")()
 
-	if err := validateContextName(name); err != nil {
+	if err := m.prepareNewContext(name); err != nil {
 		return err
 	}
 
-	// Ensure directories exist
-	if err := m.paths.EnsureDirectories(m.useProject); err != nil {
+	// Read current active config
+	activeConfigPath := m.paths.GetActiveConfigPath(m.useProject)
+	if _, err := os.Stat(activeConfigPath); os.IsNotExist(err) {
+		return fmt.Errorf("no active opencode.json found at %s", activeConfigPath)
+	}
+
+	data, err := os.ReadFile(activeConfigPath)
+	if err != nil {
 		return err
 	}
+	m.logf(VerbosityDebug, "read %d bytes from %s", len(data), activeConfigPath)
 
-	// Determine file extension using enum
-	fileExt := format.FileExtension()
+	// Validate JSON; an opencode.jsonc active config may have comments
+	jsonData, err := parseContextData(activeConfigPath, data)
+	if err != nil {
+		return fmt.Errorf("current opencode.json is not valid JSON: %v", err)
+	}
 
-	// Check if context already exists (check both .json and .jsonc)
-	contextsDir := m.paths.GetContextsDir(m.useProject)
-	contextPath := filepath.Join(contextsDir, name+fileExt)
+	return m.writeNewContext(name, format, jsonData, Source{Kind: SourceActive})
+}
+
+// prepareNewContext validates a new context name, makes sure it is not taken
+// in any format (unless force is set) and creates the contexts directory
+func (m *Manager) prepareNewContext(name string) error {
+	if err := m.checkNewContext(name); err != nil {
+		return err
+	}
+
+	// Ensure directories exist
+	return m.paths.EnsureDirectoriesMode(m.useProject, m.dirMode())
+}
+
+// checkNewContext validates a new context name and, unless force is set,
+// that no context of that name exists in any format
+func (m *Manager) checkNewContext(name string) error {
+	if err := m.validateNewContextName(name); err != nil {
+		return err
+	}
+	if err := m.checkCaseConflict(name, ""); err != nil {
+		return err
+	}
 
 	// Check if context exists in any format
+	contextsDir := m.paths.GetContextsDir(m.useProject)
 	for _, f := range GetAllFormats() {
 		existingPath := filepath.Join(contextsDir, name+f.FileExtension())
-		if _, err := os.Stat(existingPath); err == nil {
-			return fmt.Errorf("context '%s' already exists (%s format)", name, f.DisplayName())
+		if _, err := os.Stat(existingPath); err == nil && !m.force {
+			return fmt.Errorf("context '%s' already exists (%s format). Use --force to replace it", name, f.DisplayName())
 		}
 	}
 
-	// Read current active config
-	activeConfigPath := m.paths.GetActiveConfigPath(m.useProject)
-	if _, err := os.Stat(activeConfigPath); os.IsNotExist(err) {
-		return fmt.Errorf("no active opencode.json found at %s", activeConfigPath)
+	return nil
+}
+
+// writeNewContext checks jsonData against the policy, formats it for the
+// given format, writes it atomically and records its checksum and source.
+// With force set, an existing context of the same name is moved to the trash first.
+func (m *Manager) writeNewContext(name string, format ContextFormat, jsonData map[string]interface{}, source Source) error {
+	since := len(m.warned)
+	if err := m.enforcePolicy("create", name, jsonData); err != nil {
+		return err
+	}
+	if err := m.checkWarnings("create", since); err != nil {
+		return err
 	}
 
-	data, err := os.ReadFile(activeConfigPath)
+	contextsDir := m.paths.GetContextsDir(m.useProject)
+	if m.force {
+		for _, f := range GetAllFormats() {
+			existingPath := filepath.Join(contextsDir, name+f.FileExtension())
+			if _, err := os.Stat(existingPath); err != nil {
+				continue
+			}
+			if _, err := m.trashContextFile(name, existingPath); err != nil {
+				return fmt.Errorf("failed to back up existing context '%s': %v", name, err)
+			}
+		}
+	}
+
+	contextPath := filepath.Join(contextsDir, name+format.FileExtension())
+	formattedData, err := encodeContextData(name, format, jsonData)
 	if err != nil {
 		return err
 	}
 
-	// Validate JSON
-	var jsonData map[string]interface{}
-	if err := json.Unmarshal(data, &jsonData); err != nil {
-		return fmt.Errorf("current opencode.json is not valid JSON: %v", err)
+	if err := writeFileAtomic(contextPath, formattedData, m.fileMode()); err != nil {
+		return err
+	}
+	m.logf(VerbosityDebug, "wrote %d bytes to %s", len(formattedData), contextPath)
+
+	if err := m.recordChecksum(contextPath); err != nil {
+		return err
 	}
 
-	// Format content based on format type
-	var formattedData []byte
+	return m.recordSource(name, source)
+}
+
+// encodeContextData formats jsonData for storage in the given format
+func encodeContextData(name string, format ContextFormat, jsonData map[string]interface{}) ([]byte, error) {
 	switch format {
 	case FormatJSONC:
 		// For JSONC, add a comment header and format nicely
 		formattedJSON, err := json.MarshalIndent(jsonData, "", "  ")
 		if err != nil {
-			return err
+			return nil, err
 		}
 
 		comment := fmt.Sprintf("// opencode context: %s\n// Format: %s\n// Created: %s\n",
@@ -210,69 +435,159 @@ func (m *Manager) CreateContextWithFormat(name string, format ContextFormat) err
 			format.DisplayName(),
 			time.Now().Format("2006-01-02 15:04:05"))
 
-		formattedData = append([]byte(comment), formattedJSON...)
+		return append([]byte(comment), formattedJSON...), nil
 	case FormatJSON:
 		// Standard JSON formatting
-		var err error
-		formattedData, err = json.MarshalIndent(jsonData, "", "  ")
-		if err != nil {
-			return err
-		}
+		return json.MarshalIndent(jsonData, "", "  ")
 	default:
-		return fmt.Errorf("unsupported format: %s", format)
+		return nil, fmt.Errorf("unsupported format: %s", format)
 	}
+}
 
-	// Write atomically
-	tempPath := contextPath + ".tmp"
-	if err := os.WriteFile(tempPath, formattedData, 0644); err != nil {
-		return err
-	}
+// ImportContext creates a new JSON context from the given data
+func (m *Manager) ImportContext(name string, data []byte) error {
+	return m.ImportContextWithSource(name, data, Source{Kind: SourceImport})
+}
 
-	return os.Rename(tempPath, contextPath)
+// ImportContextWithSource creates a new JSON context from the given data and
+// records where the data came from
+func (m *Manager) ImportContextWithSource(name string, data []byte, source Source) error {
+	_, err := m.ImportContextWithOptions(name, data, source, ImportOptions{Format: FormatJSON})
+	return err
 }
 
 // SwitchToContext switches to the specified context
 func (m *Manager) SwitchToContext(name string) error {
-	// Get the context to ensure it exists and is valid
-	context, err := m.GetContext(name)
-	if err != nil {
+	if err := m.CheckWritable("switch context"); err != nil {
 		return err
 	}
+	defer m.timeOperation("switch")()
 
-	// Ensure active config directory exists
-	activeConfigPath := m.paths.GetActiveConfigPath(m.useProject)
-	if err := os.MkdirAll(filepath.Dir(activeConfigPath), 0755); err != nil {
+	pending, err := m.prepareSwitch(name)
+	if err != nil {
+		return err
+	}
+	if err := m.writeSwitch(pending); err != nil {
 		return err
 	}
+	return m.finishSwitch(pending)
+}
 
-	// Copy context file to active config (atomic operation)
-	data, err := os.ReadFile(context.FilePath)
+// pendingSwitch is a switch that passed its checks and pre-switch hooks but
+// has not written anything yet
+type pendingSwitch struct {
+	context          *Context
+	activeConfigPath string
+	data             []byte
+	previous         string
+}
+
+// prepareSwitch loads the context called name, runs every check of a switch
+// to it and the pre-switch hooks, and returns what the switch would write
+func (m *Manager) prepareSwitch(name string) (*pendingSwitch, error) {
+	since := len(m.warned)
+
+	// Get the context to ensure it exists and is valid
+	loaded := m.timeOperation("switch/load")
+	context, err := m.loadContextRaw(name)
 	if err != nil {
-		return err
+		return nil, err
+	}
+	loaded()
+
+	defer m.timeOperation("switch/checks")()
+	if err := m.checkApproved(context); err != nil {
+		return nil, err
 	}
+	activeConfigPath, data, err := m.activeConfigFor(context)
+	if err != nil {
+		return nil, err
+	}
+	if err := m.checkActiveOwnership(); err != nil {
+		return nil, err
+	}
+	if err := m.enforceContextPolicy("switch", context); err != nil {
+		return nil, err
+	}
+	if err := m.checkModels(context); err != nil {
+		return nil, err
+	}
+	if err := m.checkRequirements(context); err != nil {
+		return nil, err
+	}
+	if err := m.checkCostGuard(context); err != nil {
+		return nil, err
+	}
+	if err := m.checkReview(context, activeConfigPath, data); err != nil {
+		return nil, err
+	}
+	previous := ""
+	if state, err := m.loadState(); err == nil {
+		previous = state.Current
+	}
+	if err := m.runHooks(config.HookPreSwitch, context.Name, previous); err != nil {
+		return nil, err
+	}
+	if err := m.checkWarnings("switch", since); err != nil {
+		return nil, err
+	}
+	// The checks above can take a while; don't write after the deadline
+	if err := m.checkCanceled("switch context"); err != nil {
+		return nil, err
+	}
+
+	return &pendingSwitch{
+		context:          context,
+		activeConfigPath: activeConfigPath,
+		data:             data,
+		previous:         previous,
+	}, nil
+}
 
-	tempPath := activeConfigPath + ".tmp"
-	if err := os.WriteFile(tempPath, data, 0644); err != nil {
+// writeSwitch writes the active config and state of a prepared switch
+func (m *Manager) writeSwitch(pending *pendingSwitch) error {
+	// Ensure active config directory exists
+	written := m.timeOperation("switch/write")
+	if err := os.MkdirAll(filepath.Dir(pending.activeConfigPath), m.dirMode()); err != nil {
 		return err
 	}
 
-	if err := os.Rename(tempPath, activeConfigPath); err != nil {
+	// Copy context file to active config (atomic operation)
+	if err := m.writeActiveConfig(pending.activeConfigPath, pending.data); err != nil {
 		return err
 	}
+	m.logf(VerbosityDebug, "wrote %d bytes to %s", len(pending.data), pending.activeConfigPath)
+	if err := m.retireActiveConfig(pending.activeConfigPath); err != nil {
+		return err
+	}
+	written()
 
 	// Update state
-	stateFilePath := m.paths.GetStateFilePath(m.useProject)
-	state, err := LoadState(stateFilePath)
-	if err != nil {
+	defer m.timeOperation("switch/state")()
+	active := fingerprintActive(pending.activeConfigPath, pending.data)
+	if err := m.updateState(func(state *State) error {
+		state.SetCurrent(pending.context.Name)
+		state.Active = active
+		return nil
+	}); err != nil {
 		return err
 	}
 
-	state.SetCurrent(name)
-	return state.SaveState(stateFilePath)
+	return m.recordUse(pending.context.Name)
+}
+
+// finishSwitch announces a written switch and runs the post-switch hooks
+func (m *Manager) finishSwitch(pending *pendingSwitch) error {
+	m.emit(Event{Kind: config.EventSwitch, Context: pending.context.Name, Previous: pending.previous})
+	return m.runHooks(config.HookPostSwitch, pending.context.Name, pending.previous)
 }
 
 // DeleteContext deletes the specified context
 func (m *Manager) DeleteContext(name string) error {
+	if err := m.CheckWritable("delete context"); err != nil {
+		return err
+	}
+
 	if err := validateContextName(name); err != nil {
 		return err
 	}
@@ -284,76 +599,203 @@ func (m *Manager) DeleteContext(name string) error {
 	}
 
 	// Check if it's the current context
-	stateFilePath := m.paths.GetStateFilePath(m.useProject)
-	state, err := LoadState(stateFilePath)
+	state, err := m.loadState()
 	if err != nil {
 		return err
 	}
 
-	if state.Current == name {
-		return fmt.Errorf("cannot delete current context '%s'. Switch to another context first", name)
+	if err := context.CheckModifiable("delete"); err != nil {
+		return err
+	}
+
+	if state.Current == context.Name {
+		return fmt.Errorf("cannot delete current context '%s'. Switch to another context first, or use --switch-to", context.Name)
+	}
+	if state.Default == context.Name {
+		return fmt.Errorf("cannot delete default context '%s'. Run 'occtx default unset' first", context.Name)
 	}
 
 	// Delete the file
-	return os.Remove(context.FilePath)
-}
+	if err := os.Remove(context.FilePath); err != nil {
+		return err
+	}
 
-// RenameContext renames a context
-func (m *Manager) RenameContext(oldName, newName string) error {
-	if err := validateContextName(oldName); err != nil {
-		return fmt.Errorf("invalid old name: %v", err)
+	if err := m.forgetChecksum(context.FilePath); err != nil {
+		return err
 	}
-	if err := validateContextName(newName); err != nil {
-		return fmt.Errorf("invalid new name: %v", err)
+	if err := m.deleteMetadata(context.Name); err != nil {
+		return err
 	}
 
-	// Check if old context exists
-	oldContext, err := m.GetContext(oldName)
-	if err != nil {
+	// Don't leave `occtx -` or `occtx <n>` pointing at a deleted context
+	if err := m.updateState(func(state *State) error {
+		listing := state.Listing[:0:0]
+		for _, name := range state.Listing {
+			if name != context.Name {
+				listing = append(listing, name)
+			}
+		}
+		if state.Previous != context.Name && len(listing) == len(state.Listing) {
+			return errStateUnchanged
+		}
+		if state.Previous == context.Name {
+			state.Previous = ""
+		}
+		state.Listing = listing
+		return nil
+	}); err != nil {
 		return err
 	}
 
-	// Check if new name already exists
-	contextsDir := m.paths.GetContextsDir(m.useProject)
-	newContextPath := filepath.Join(contextsDir, newName+".json")
+	m.emit(Event{Kind: config.EventDelete, Context: context.Name})
+	return nil
+}
+
+// Fallbacks accepted by DeleteContextSwitchingTo besides a context name
+const (
+	FallbackPrevious = "previous" // Switch to the previous context
+	FallbackNone     = "none"     // Unset the current context
+)
 
-	if _, err := os.Stat(newContextPath); err == nil {
-		return fmt.Errorf("context '%s' already exists", newName)
+// DeleteContextSwitchingTo deletes a context that may be current: if it is,
+// occtx first switches to fallback (a context name, FallbackPrevious or
+// FallbackNone) and then deletes it. If the delete fails, the context is
+// switched back to.
+func (m *Manager) DeleteContextSwitchingTo(name, fallback string) error {
+	if err := m.CheckWritable("delete context"); err != nil {
+		return err
 	}
+	defer m.timeOperation("delete")()
 
-	// Rename the file
-	if err := os.Rename(oldContext.FilePath, newContextPath); err != nil {
+	context, err := m.GetContext(name)
+	if err != nil {
+		return err
+	}
+	if err := context.CheckModifiable("delete"); err != nil {
 		return err
 	}
 
-	// Update state if the renamed context is current or previous
-	stateFilePath := m.paths.GetStateFilePath(m.useProject)
-	state, err := LoadState(stateFilePath)
+	state, err := m.loadState()
 	if err != nil {
 		return err
 	}
 
-	updated := false
-	if state.Current == oldName {
-		state.Current = newName
-		updated = true
+	if state.Current != context.Name {
+		return m.DeleteContext(context.Name)
 	}
-	if state.Previous == oldName {
-		state.Previous = newName
-		updated = true
+
+	// Resolve and validate the fallback before touching anything
+	switch fallback {
+	case FallbackNone:
+	case FallbackPrevious:
+		if state.Previous == "" || state.Previous == context.Name {
+			return fmt.Errorf("no previous context to switch to")
+		}
+		fallback = state.Previous
+		fallthrough
+	default:
+		target, err := m.GetContext(fallback)
+		if err != nil {
+			return err
+		}
+		if target.Name == context.Name {
+			return fmt.Errorf("cannot switch to '%s': it is the context being deleted", target.Name)
+		}
+		fallback = target.Name
+	}
+
+	if fallback == FallbackNone {
+		err = m.UnsetCurrentContext()
+	} else {
+		err = m.SwitchToContext(fallback)
+	}
+	if err != nil {
+		return err
 	}
 
-	if updated {
-		return state.SaveState(stateFilePath)
+	if err := m.DeleteContext(context.Name); err != nil {
+		if restoreErr := m.SwitchToContext(context.Name); restoreErr != nil {
+			return fmt.Errorf("%v (restoring '%s' also failed: %v)", err, context.Name, restoreErr)
+		}
+		return err
 	}
 
 	return nil
 }
 
+// CopyContext copies a context (local or shared) to a new local context,
+// keeping its format and content byte for byte. An empty dst keeps the name,
+// which is how shared contexts are taken over locally.
+func (m *Manager) CopyContext(src, dst string) (*Context, error) {
+	if err := m.CheckWritable("copy context"); err != nil {
+		return nil, err
+	}
+	defer m.timeOperation("copy")()
+
+	source, err := m.GetContext(src)
+	if err != nil {
+		return nil, err
+	}
+
+	if dst == "" {
+		dst = source.Name
+	}
+	if err := m.validateNewContextName(dst); err != nil {
+		return nil, err
+	}
+	if err := m.checkCaseConflict(dst, source.Name); err != nil {
+		return nil, err
+	}
+
+	contextsDir := m.paths.GetContextsDir(m.useProject)
+	for _, f := range GetAllFormats() {
+		if _, err := os.Stat(filepath.Join(contextsDir, dst+f.FileExtension())); err == nil {
+			return nil, fmt.Errorf("context '%s' already exists (%s format)", dst, f.DisplayName())
+		}
+	}
+
+	data, err := os.ReadFile(source.FilePath)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := m.paths.EnsureDirectoriesMode(m.useProject, m.dirMode()); err != nil {
+		return nil, err
+	}
+
+	contextPath := filepath.Join(contextsDir, dst+source.Format.FileExtension())
+	if err := writeFileAtomic(contextPath, data, m.fileMode()); err != nil {
+		return nil, err
+	}
+
+	if err := m.recordChecksum(contextPath); err != nil {
+		return nil, err
+	}
+
+	from := source.Name
+	if source.Shared {
+		from = source.FilePath
+	}
+	if err := m.recordSource(dst, Source{Kind: SourceCopy, From: from}); err != nil {
+		return nil, err
+	}
+	if source.Shared {
+		if err := m.recordBaseline(dst, source.Data); err != nil {
+			return nil, err
+		}
+	}
+
+	return &Context{
+		Name:     dst,
+		Data:     source.Data,
+		FilePath: contextPath,
+		Format:   source.Format,
+	}, nil
+}
+
 // GetCurrentContext returns the current context name
 func (m *Manager) GetCurrentContext() (string, error) {
-	stateFilePath := m.paths.GetStateFilePath(m.useProject)
-	state, err := LoadState(stateFilePath)
+	state, err := m.loadState()
 	if err != nil {
 		return "", err
 	}
@@ -363,8 +805,11 @@ func (m *Manager) GetCurrentContext() (string, error) {
 
 // SwitchToPrevious switches to the previous context
 func (m *Manager) SwitchToPrevious() error {
-	stateFilePath := m.paths.GetStateFilePath(m.useProject)
-	state, err := LoadState(stateFilePath)
+	if err := m.CheckWritable("switch context"); err != nil {
+		return err
+	}
+
+	state, err := m.loadState()
 	if err != nil {
 		return err
 	}
@@ -388,6 +833,10 @@ func (m *Manager) SwitchToPrevious() error {
 
 // UnsetCurrentContext removes the current context
 func (m *Manager) UnsetCurrentContext() error {
+	if err := m.CheckWritable("unset context"); err != nil {
+		return err
+	}
+
 	activeConfigPath := m.paths.GetActiveConfigPath(m.useProject)
 
 	// Remove active config file if it exists
@@ -398,14 +847,10 @@ func (m *Manager) UnsetCurrentContext() error {
 	}
 
 	// Update state
-	stateFilePath := m.paths.GetStateFilePath(m.useProject)
-	state, err := LoadState(stateFilePath)
-	if err != nil {
-		return err
-	}
-
-	state.Unset()
-	return state.SaveState(stateFilePath)
+	return m.updateState(func(state *State) error {
+		state.Unset()
+		return nil
+	})
 }
 
 // validateContextName validates that a context name is safe
@@ -426,5 +871,9 @@ func validateContextName(name string) error {
 		return fmt.Errorf("context name cannot start with '.'")
 	}
 
+	if isLocalOverrideName(name) {
+		return fmt.Errorf("context name cannot end with '%s' (reserved for machine-local overrides)", LocalOverrideSuffix)
+	}
+
 	return nil
 }
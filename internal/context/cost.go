@@ -0,0 +1,190 @@
+package context
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/hungthai1401/occtx/internal/config"
+	"github.com/hungthai1401/occtx/internal/util"
+)
+
+// Cost guard actions
+const (
+	CostActionWarn    = "warn"    // Warn and switch
+	CostActionConfirm = "confirm" // Ask first; decline when nobody can answer
+)
+
+// CostGuardError is returned when a guarded switch was not confirmed
+type CostGuardError struct {
+	Context string
+	Tier    string
+	Reason  string
+}
+
+func (e *CostGuardError) Error() string {
+	return fmt.Sprintf("switch to '%s' (%s tier) %s was not confirmed; pass --yes to switch anyway", e.Context, e.Tier, e.Reason)
+}
+
+// SetConfirmHandler sets how guarded switches are confirmed. Without one,
+// guards configured to confirm decline the switch.
+func (m *Manager) SetConfirmHandler(handler func(question string) bool) {
+	m.confirm = handler
+}
+
+// SetCostTier tags a context with a cost tier; an empty tier removes it
+func (m *Manager) SetCostTier(name, tier string) error {
+	if err := m.CheckWritable("set cost tier"); err != nil {
+		return err
+	}
+
+	ctx, err := m.GetContext(name)
+	if err != nil {
+		return err
+	}
+	if err := ctx.CheckModifiable("tag"); err != nil {
+		return err
+	}
+
+	tier = strings.ToLower(strings.TrimSpace(tier))
+	return m.updateMetadata(func(meta *metadataFile) {
+		entry := meta.Contexts[ctx.Name]
+		if entry == nil {
+			if tier == "" {
+				return
+			}
+			entry = &ContextMeta{}
+			meta.Contexts[ctx.Name] = entry
+		}
+		entry.CostTier = tier
+	})
+}
+
+// GetCostTier returns the cost tier of a context, or "" if it has none
+func (m *Manager) GetCostTier(name string) (string, error) {
+	meta, err := m.GetContextMeta(name)
+	if err != nil || meta == nil {
+		return "", err
+	}
+	return meta.CostTier, nil
+}
+
+// checkCostGuard warns or asks before switching to a guarded cost tier
+// outside working hours or inside a guarded directory
+func (m *Manager) checkCostGuard(ctx *Context) error {
+	guard := m.config.Cost
+	if len(guard.GuardedTiers) == 0 || ctx.Shared {
+		return nil
+	}
+
+	tier, err := m.GetCostTier(ctx.Name)
+	if err != nil || tier == "" || !util.ContainsFold(guard.GuardedTiers, tier) {
+		return err
+	}
+
+	reason, err := costGuardReason(guard, time.Now())
+	if err != nil || reason == "" {
+		return err
+	}
+	m.logf(VerbosityVerbose, "cost guard: '%s' is %s tier, switching %s", ctx.Name, tier, reason)
+
+	switch strings.ToLower(guard.Action) {
+	case "", CostActionWarn:
+		m.warnf("'%s' is a %s-tier context and you are switching %s", ctx.Name, tier, reason)
+		return nil
+	case CostActionConfirm:
+		question := fmt.Sprintf("'%s' is a %s-tier context and you are switching %s. Switch anyway?", ctx.Name, tier, reason)
+		if m.confirm != nil && m.confirm(question) {
+			return nil
+		}
+		return &CostGuardError{Context: ctx.Name, Tier: tier, Reason: reason}
+	default:
+		return fmt.Errorf("cost.action: invalid action '%s' (expected warn or confirm)", guard.Action)
+	}
+}
+
+// costGuardReason explains why the guard trips at now in the current
+// directory, or returns "" if it doesn't
+func costGuardReason(guard config.CostConfig, now time.Time) (string, error) {
+	if cwd, err := os.Getwd(); err == nil {
+		for _, dir := range guard.Dirs {
+			expanded, err := config.ExpandHome(dir)
+			if err != nil {
+				return "", err
+			}
+			if isWithin(cwd, expanded) {
+				return "inside " + dir, nil
+			}
+		}
+	}
+
+	if guard.WorkingHours == "" && len(guard.WorkingDays) == 0 {
+		if len(guard.Dirs) == 0 {
+			return "at any time", nil
+		}
+		return "", nil
+	}
+
+	if len(guard.WorkingDays) > 0 {
+		day := strings.ToLower(now.Weekday().String()[:3])
+		working := false
+		for _, d := range guard.WorkingDays {
+			if strings.HasPrefix(strings.ToLower(d), day) {
+				working = true
+			}
+		}
+		if !working {
+			return "outside working days", nil
+		}
+	}
+
+	if guard.WorkingHours != "" {
+		inside, err := withinHours(guard.WorkingHours, now)
+		if err != nil {
+			return "", err
+		}
+		if !inside {
+			return "outside working hours (" + guard.WorkingHours + ")", nil
+		}
+	}
+	return "", nil
+}
+
+// withinHours reports whether now falls in a "HH:MM-HH:MM" window; windows
+// ending before they start span midnight
+func withinHours(window string, now time.Time) (bool, error) {
+	startText, endText, ok := strings.Cut(window, "-")
+	if !ok {
+		return false, fmt.Errorf("cost.working_hours: invalid window '%s' (expected HH:MM-HH:MM)", window)
+	}
+	start, err := time.Parse("15:04", strings.TrimSpace(startText))
+	if err != nil {
+		return false, fmt.Errorf("cost.working_hours: invalid time '%s'", startText)
+	}
+	end, err := time.Parse("15:04", strings.TrimSpace(endText))
+	if err != nil {
+		return false, fmt.Errorf("cost.working_hours: invalid time '%s'", endText)
+	}
+
+	minute := func(t time.Time) int { return t.Hour()*60 + t.Minute() }
+	current, from, to := minute(now), minute(start), minute(end)
+	if from <= to {
+		return current >= from && current < to, nil
+	}
+	return current >= from || current < to, nil
+}
+
+// isWithin reports whether path is dir or inside it, following symlinks
+func isWithin(path, dir string) bool {
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		path = resolved
+	}
+	if resolved, err := filepath.EvalSymlinks(dir); err == nil {
+		dir = resolved
+	}
+
+	rel, err := filepath.Rel(dir, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
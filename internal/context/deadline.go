@@ -0,0 +1,30 @@
+package context
+
+import (
+	gocontext "context"
+	"fmt"
+)
+
+// SetContext bounds the manager's slow work, such as network access,
+// requirement checks, scans over many files and the commands occtx runs, by
+// the deadline and cancellation of ctx
+func (m *Manager) SetContext(ctx gocontext.Context) {
+	m.ctx = ctx
+}
+
+// Context returns the context set with SetContext, or one that is never done
+func (m *Manager) Context() gocontext.Context {
+	if m.ctx == nil {
+		return gocontext.Background()
+	}
+	return m.ctx
+}
+
+// checkCanceled returns an error naming the operation once the manager's
+// context is done, e.g. because --timeout elapsed
+func (m *Manager) checkCanceled(operation string) error {
+	if err := m.Context().Err(); err != nil {
+		return fmt.Errorf("cannot %s: %w", operation, err)
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+package context
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrNoDefault is returned by ResetToDefault when no default context is set
+var ErrNoDefault = errors.New("no default context set; use 'occtx default set <name>'")
+
+// SetDefaultContext pins name as the context ResetToDefault switches back to
+func (m *Manager) SetDefaultContext(name string) error {
+	if err := m.CheckWritable("set default context"); err != nil {
+		return err
+	}
+
+	context, err := m.GetContext(name)
+	if err != nil {
+		return err
+	}
+
+	return m.updateState(func(state *State) error {
+		state.Default = context.Name
+		return nil
+	})
+}
+
+// UnsetDefaultContext removes the pinned default context
+func (m *Manager) UnsetDefaultContext() error {
+	if err := m.CheckWritable("unset default context"); err != nil {
+		return err
+	}
+
+	return m.updateState(func(state *State) error {
+		if state.Default == "" {
+			return errStateUnchanged
+		}
+		state.Default = ""
+		return nil
+	})
+}
+
+// GetDefaultContext returns the pinned default context, or "" if none is set
+func (m *Manager) GetDefaultContext() (string, error) {
+	state, err := m.loadState()
+	if err != nil {
+		return "", err
+	}
+
+	return state.Default, nil
+}
+
+// ResetToDefault switches to the default context, whatever the current and
+// previous contexts are, and returns its name
+func (m *Manager) ResetToDefault() (string, error) {
+	name, err := m.GetDefaultContext()
+	if err != nil {
+		return "", err
+	}
+	if name == "" {
+		return "", ErrNoDefault
+	}
+
+	if err := m.SwitchToContext(name); err != nil {
+		return "", fmt.Errorf("failed to reset to default context '%s': %w", name, err)
+	}
+	return name, nil
+}
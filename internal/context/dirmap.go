@@ -0,0 +1,123 @@
+package context
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/hungthai1401/occtx/internal/config"
+)
+
+// DirMap maps path prefixes to context names. Prefixes are relative to the
+// directory of the map file and use '/'; "." covers the whole tree.
+type DirMap map[string]string
+
+// CleanMapPrefix normalizes a path prefix for a DirMap key
+func CleanMapPrefix(prefix string) string {
+	return path.Clean(filepath.ToSlash(prefix))
+}
+
+// LoadDirMap reads a directory map file; a missing file is an empty map
+func LoadDirMap(mapPath string) (DirMap, error) {
+	data, err := os.ReadFile(mapPath)
+	if os.IsNotExist(err) {
+		return DirMap{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var raw DirMap
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("invalid directory map %s: %v", mapPath, err)
+	}
+	dirMap := make(DirMap, len(raw))
+	for prefix, name := range raw {
+		dirMap[CleanMapPrefix(prefix)] = strings.TrimSpace(name)
+	}
+	return dirMap, nil
+}
+
+// Save writes the map to mapPath, keys sorted
+func (d DirMap) Save(mapPath string) error {
+	data, err := json.MarshalIndent(d, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(mapPath, append(data, '\n'), 0644)
+}
+
+// Match returns the longest prefix covering rel, a '/' path relative to the
+// map file's directory, and the context it maps to
+func (d DirMap) Match(rel string) (prefix, name string, ok bool) {
+	rel = CleanMapPrefix(rel)
+	for candidate, mapped := range d {
+		if mapped == "" {
+			continue
+		}
+		covers := candidate == "." || rel == candidate || strings.HasPrefix(rel, candidate+"/")
+		if !covers {
+			continue
+		}
+		if !ok || mapLonger(candidate, prefix) {
+			prefix, name, ok = candidate, mapped, true
+		}
+	}
+	return prefix, name, ok
+}
+
+// mapLonger reports whether prefix a is more specific than b; both cover
+// the same path, so the longer one is nested in the other
+func mapLonger(a, b string) bool {
+	if a == "." {
+		return false
+	}
+	return b == "." || len(a) > len(b)
+}
+
+// FindDirMap returns the nearest directory map file in dir or its parents,
+// or "" when there is none
+func FindDirMap(dir string) (string, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+	for {
+		mapPath := filepath.Join(dir, config.DirMapFileName)
+		if _, err := os.Stat(mapPath); err == nil {
+			return mapPath, nil
+		} else if !os.IsNotExist(err) {
+			return "", err
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", nil
+		}
+		dir = parent
+	}
+}
+
+// matchDirMap looks up dir in the map file in mapDir; name is "" when no
+// prefix covers it
+func matchDirMap(mapDir, dir string) (name, mapPath string, err error) {
+	mapPath = filepath.Join(mapDir, config.DirMapFileName)
+	if _, err := os.Stat(mapPath); os.IsNotExist(err) {
+		return "", "", nil
+	}
+	dirMap, err := LoadDirMap(mapPath)
+	if err != nil {
+		return "", "", err
+	}
+	rel, err := filepath.Rel(mapDir, dir)
+	if err != nil {
+		return "", "", err
+	}
+	if _, name, ok := dirMap.Match(rel); ok {
+		return name, mapPath, nil
+	}
+	return "", "", nil
+}
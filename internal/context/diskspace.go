@@ -0,0 +1,73 @@
+package context
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// diskSpaceMargin is kept free on top of what a write needs, since other
+// files (metadata, checksums, state) are written right after
+const diskSpaceMargin = 64 * 1024
+
+// ErrDiskFull is matched by errors.Is for DiskFullError
+var ErrDiskFull = errors.New("not enough disk space")
+
+// DiskFullError reports a write that failed, or would fail, for lack of space
+type DiskFullError struct {
+	Path      string // File or directory being written
+	Needed    uint64 // Bytes the write needs
+	Available uint64 // Bytes free, or 0 if unknown
+}
+
+func (e *DiskFullError) Error() string {
+	if e.Available > 0 {
+		return fmt.Sprintf("not enough disk space to write %s: %d bytes needed, %d available; free up space or use --config-dir to choose another location",
+			e.Path, e.Needed, e.Available)
+	}
+	return fmt.Sprintf("not enough disk space to write %s (%d bytes needed); free up space or use --config-dir to choose another location",
+		e.Path, e.Needed)
+}
+
+// Is makes errors.Is(err, ErrDiskFull) match
+func (e *DiskFullError) Is(target error) bool {
+	return target == ErrDiskFull
+}
+
+// CheckFreeSpace fails with a DiskFullError when the filesystem holding path
+// (a file or directory; the nearest existing parent is used) has less than
+// needed bytes, plus a margin, available to the current user. It is the
+// preflight for operations writing many files at once, such as exporting or
+// backing up every context. Filesystems that can't report free space pass.
+func CheckFreeSpace(path string, needed uint64) error {
+	dir := path
+	for {
+		if info, err := os.Stat(dir); err == nil && info.IsDir() {
+			break
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return nil
+		}
+		dir = parent
+	}
+
+	available, err := freeSpace(dir)
+	if err != nil {
+		return nil
+	}
+	if available < needed+diskSpaceMargin {
+		return &DiskFullError{Path: path, Needed: needed, Available: available}
+	}
+	return nil
+}
+
+// writeError turns a failed write of size bytes to path into a DiskFullError
+// when the disk is full, and otherwise names the file in the error
+func writeError(path string, size int, err error) error {
+	if isDiskFull(err) {
+		return &DiskFullError{Path: path, Needed: uint64(size)}
+	}
+	return fmt.Errorf("failed to write %s: %w", path, err)
+}
@@ -0,0 +1,22 @@
+//go:build !windows
+
+package context
+
+import (
+	"errors"
+	"syscall"
+)
+
+// freeSpace returns the bytes available to unprivileged users on dir's filesystem
+func freeSpace(dir string) (uint64, error) {
+	var stat syscall.Statfs_t
+	if err := syscall.Statfs(dir, &stat); err != nil {
+		return 0, err
+	}
+	return uint64(stat.Bavail) * uint64(stat.Bsize), nil
+}
+
+// isDiskFull reports whether err means the disk or quota is full
+func isDiskFull(err error) bool {
+	return errors.Is(err, syscall.ENOSPC) || errors.Is(err, syscall.EDQUOT)
+}
@@ -0,0 +1,46 @@
+package context
+
+import "os"
+
+// ActiveDrifted reports whether the active config was modified since occtx last
+// switched to the current context. It only hashes the file when its modification
+// time changed, so it is cheap enough to run on every invocation.
+func (m *Manager) ActiveDrifted() (bool, error) {
+	state, err := m.loadState()
+	if err != nil {
+		return false, err
+	}
+
+	if state.Current == "" || state.Active == nil {
+		return false, nil
+	}
+
+	activeConfigPath := m.paths.GetActiveConfigPath(m.useProject)
+	info, err := os.Stat(activeConfigPath)
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	if info.ModTime().Equal(state.Active.ModTime) {
+		return false, nil
+	}
+
+	data, err := os.ReadFile(activeConfigPath)
+	if err != nil {
+		return false, err
+	}
+
+	return checksum(data) != state.Active.Checksum, nil
+}
+
+// fingerprintActive records the active config just written from data
+func fingerprintActive(activeConfigPath string, data []byte) *ActiveRecord {
+	record := &ActiveRecord{Checksum: checksum(data)}
+	if info, err := os.Stat(activeConfigPath); err == nil {
+		record.ModTime = info.ModTime()
+	}
+	return record
+}
@@ -0,0 +1,186 @@
+package context
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// AppliedEnvVar lists the variables the shell hook exported for the current
+// context, so they can be unset when the context changes
+const AppliedEnvVar = "OCCTX_ENV_KEYS"
+
+// envNamePattern matches names every supported shell can export
+var envNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// validateEnvName rejects names shells can't export and names occtx uses itself
+func validateEnvName(name string) error {
+	if !envNamePattern.MatchString(name) {
+		return fmt.Errorf("invalid environment variable name '%s'", name)
+	}
+	if strings.HasPrefix(strings.ToUpper(name), "OCCTX_") || name == OpencodeConfigEnvVar {
+		return fmt.Errorf("environment variable '%s' is managed by occtx", name)
+	}
+	return nil
+}
+
+// SetContextEnv declares environment variables for a context, adding to or
+// replacing the ones already declared
+func (m *Manager) SetContextEnv(name string, vars map[string]string) error {
+	if err := m.CheckWritable("set environment"); err != nil {
+		return err
+	}
+
+	ctx, err := m.GetContext(name)
+	if err != nil {
+		return err
+	}
+	if err := ctx.CheckModifiable("set environment of"); err != nil {
+		return err
+	}
+	for key := range vars {
+		if err := validateEnvName(key); err != nil {
+			return err
+		}
+	}
+
+	return m.updateMetadata(func(meta *metadataFile) {
+		entry := meta.Contexts[ctx.Name]
+		if entry == nil {
+			entry = &ContextMeta{}
+			meta.Contexts[ctx.Name] = entry
+		}
+		if entry.Env == nil {
+			entry.Env = make(map[string]string, len(vars))
+		}
+		for key, value := range vars {
+			entry.Env[key] = value
+		}
+	})
+}
+
+// UnsetContextEnv removes declared environment variables from a context
+func (m *Manager) UnsetContextEnv(name string, keys []string) error {
+	if err := m.CheckWritable("unset environment"); err != nil {
+		return err
+	}
+
+	ctx, err := m.GetContext(name)
+	if err != nil {
+		return err
+	}
+
+	var missing []string
+	err = m.updateMetadata(func(meta *metadataFile) {
+		entry := meta.Contexts[ctx.Name]
+		for _, key := range keys {
+			if entry == nil {
+				missing = append(missing, key)
+				continue
+			}
+			if _, ok := entry.Env[key]; !ok {
+				missing = append(missing, key)
+			}
+			delete(entry.Env, key)
+		}
+		if entry != nil && len(entry.Env) == 0 {
+			entry.Env = nil
+		}
+	})
+	if err != nil {
+		return err
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("context '%s' does not declare %s", ctx.Name, strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// GetContextEnv returns the environment variables declared for a context.
+// Names that couldn't be declared with SetContextEnv, e.g. from a metadata
+// file edited by hand, are left out with a warning: they end up in shell
+// code.
+func (m *Manager) GetContextEnv(name string) (map[string]string, error) {
+	ctx, err := m.GetContext(name)
+	if err != nil {
+		return nil, err
+	}
+
+	meta, err := m.GetContextMeta(ctx.Name)
+	if err != nil || meta == nil {
+		return nil, err
+	}
+	env := make(map[string]string, len(meta.Env))
+	for key, value := range meta.Env {
+		if err := validateEnvName(key); err != nil {
+			m.warnf("context '%s': ignoring %v", ctx.Name, err)
+			continue
+		}
+		env[key] = value
+	}
+	return env, nil
+}
+
+// EnvChange is what the shell hook must do to move the environment from the
+// variables applied previously to the ones of the current context
+type EnvChange struct {
+	Set   map[string]string // Variables to export
+	Unset []string          // Variables the previous context exported and this one doesn't
+}
+
+// Keys returns the sorted names of the variables to export
+func (c *EnvChange) Keys() []string {
+	keys := make([]string, 0, len(c.Set))
+	for key := range c.Set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// HookEnv computes the environment change for the context in effect in this
+// terminal. applied is the value of AppliedEnvVar: the variables exported by
+// the previous run of the hook. A context that no longer exists exports nothing.
+func (m *Manager) HookEnv(applied string) (*EnvChange, error) {
+	change := &EnvChange{Set: map[string]string{}}
+
+	name, _, err := m.EffectiveCurrentContext()
+	if err != nil {
+		return nil, err
+	}
+	if name != "" {
+		env, err := m.GetContextEnv(name)
+		var notFound *NotFoundError
+		if err != nil && !errors.As(err, &notFound) {
+			return nil, err
+		}
+		for key, value := range env {
+			change.Set[key] = value
+		}
+	}
+
+	for _, key := range strings.Split(applied, ":") {
+		if _, ok := change.Set[key]; !ok && validateEnvName(key) == nil {
+			change.Unset = append(change.Unset, key)
+		}
+	}
+	sort.Strings(change.Unset)
+	return change, nil
+}
+
+// sessionEnv returns the context's declared variables in os/exec form
+func (m *Manager) sessionEnv(name string) ([]string, error) {
+	env, err := m.GetContextEnv(name)
+	if err != nil {
+		return nil, err
+	}
+
+	vars := make([]string, 0, len(env))
+	for key, value := range env {
+		vars = append(vars, key+"="+value)
+	}
+	sort.Strings(vars)
+	return vars, nil
+}
@@ -0,0 +1,276 @@
+package context
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hungthai1401/occtx/internal/config"
+)
+
+// spoolClaimTimeout is how long an event claimed by a delivery stays claimed;
+// a delivery that crashed releases its events after this
+const spoolClaimTimeout = 5 * time.Minute
+
+// webhookClient posts events; the timeout bounds each delivery
+var webhookClient = &http.Client{Timeout: 10 * time.Second}
+
+// Event is something that happened to a context, sent to the webhooks that
+// subscribe to it
+type Event struct {
+	Kind       string    `json:"kind"` // config.EventSwitch, EventDelete or EventPolicyViolation
+	At         time.Time `json:"at"`
+	User       string    `json:"user,omitempty"`
+	Host       string    `json:"host,omitempty"`
+	Scope      string    `json:"scope"` // global or project
+	Profile    string    `json:"profile,omitempty"`
+	Context    string    `json:"context"`
+	Previous   string    `json:"previous,omitempty"`   // For a switch, the context switched from
+	Operation  string    `json:"operation,omitempty"`  // For a policy violation, what was denied
+	Violations []string  `json:"violations,omitempty"` // For a policy violation, the rules that failed
+}
+
+// Text describes the event in a sentence, the message Slack shows
+func (e Event) Text() string {
+	who := e.User
+	if e.Host != "" {
+		who += "@" + e.Host
+	}
+	switch e.Kind {
+	case config.EventSwitch:
+		if e.Previous != "" {
+			return fmt.Sprintf("%s switched to '%s' (from '%s')", who, e.Context, e.Previous)
+		}
+		return fmt.Sprintf("%s switched to '%s'", who, e.Context)
+	case config.EventDelete:
+		return fmt.Sprintf("%s deleted '%s'", who, e.Context)
+	case config.EventPolicyViolation:
+		return fmt.Sprintf("policy denied %s of '%s' for %s: %s", e.Operation, e.Context, who, strings.Join(e.Violations, "; "))
+	}
+	return fmt.Sprintf("%s: %s '%s'", who, e.Kind, e.Context)
+}
+
+// spooledEvent is an event waiting in the spool for one webhook
+type spooledEvent struct {
+	Webhook string `json:"webhook"`
+	Event   Event  `json:"event"`
+}
+
+// webhookPayload is the body posted to a webhook: "text" is what Slack
+// incoming webhooks display, "event" carries the details for other receivers
+type webhookPayload struct {
+	Text  string `json:"text"`
+	Event Event  `json:"event"`
+}
+
+// SetSpoolHandler sets what is told when events were spooled for webhooks,
+// e.g. to start delivering them once the command is done
+func (m *Manager) SetSpoolHandler(handler func()) {
+	m.spooled = handler
+}
+
+// emit spools event for every webhook that subscribes to it. Nothing is sent
+// here, so an unreachable webhook never slows a command down or fails it;
+// FlushWebhooks delivers the spool.
+func (m *Manager) emit(event Event) {
+	var targets []string
+	for _, webhook := range m.config.Webhooks {
+		if webhook.Wants(event.Kind, event.Context) {
+			targets = append(targets, webhook.Name)
+		}
+	}
+	if len(targets) == 0 {
+		return
+	}
+
+	event.At = time.Now().UTC()
+	event.User = currentUserName()
+	event.Host, _ = os.Hostname()
+	event.Scope = "global"
+	if m.useProject {
+		event.Scope = "project"
+	}
+	event.Profile = m.profile
+
+	dir := m.paths.GetWebhookSpoolDir()
+	if err := os.MkdirAll(dir, privateDirMode); err != nil {
+		m.warnf("cannot spool %s event for webhooks: %v", event.Kind, err)
+		return
+	}
+	for _, name := range targets {
+		data, err := json.Marshal(spooledEvent{Webhook: name, Event: event})
+		if err != nil {
+			m.warnf("cannot spool %s event for webhook '%s': %v", event.Kind, name, err)
+			continue
+		}
+		path := filepath.Join(dir, fmt.Sprintf("%d-%s.json", event.At.UnixNano(), name))
+		if err := writeFileAtomic(path, data, privateFileMode); err != nil {
+			m.warnf("cannot spool %s event for webhook '%s': %v", event.Kind, name, err)
+			continue
+		}
+		m.logf(VerbosityDebug, "spooled %s event for webhook '%s' in %s", event.Kind, name, path)
+	}
+	if m.spooled != nil {
+		m.spooled()
+	}
+}
+
+// PendingWebhookEvents returns how many spooled events wait for each webhook
+func (m *Manager) PendingWebhookEvents() (map[string]int, error) {
+	files, err := m.spoolFiles()
+	if err != nil {
+		return nil, err
+	}
+	pending := make(map[string]int)
+	for _, path := range files {
+		if spooled, err := readSpooledEvent(path); err == nil {
+			pending[spooled.Webhook]++
+		}
+	}
+	return pending, nil
+}
+
+// WebhookDelivery is the result of FlushWebhooks
+type WebhookDelivery struct {
+	Delivered int
+	Dropped   int      // Events for webhooks no longer configured, or unreadable
+	Failed    []string // Why events could not be delivered; they stay spooled
+}
+
+// FlushWebhooks posts the spooled events, oldest first. Delivered events are
+// removed from the spool; events a webhook didn't accept stay for the next
+// flush. Concurrent flushes each claim the events they send, so none is
+// sent twice.
+func (m *Manager) FlushWebhooks() (*WebhookDelivery, error) {
+	if err := m.CheckOnline("deliver webhook events"); err != nil {
+		return nil, err
+	}
+	files, err := m.spoolFiles()
+	if err != nil {
+		return nil, err
+	}
+
+	webhooks := make(map[string]config.WebhookConfig)
+	for _, webhook := range m.config.Webhooks {
+		webhooks[webhook.Name] = webhook
+	}
+
+	delivery := &WebhookDelivery{}
+	failing := make(map[string]bool) // Keep the order of a webhook's events: stop at its first failure
+	for _, path := range files {
+		if err := m.checkCanceled("deliver webhook events"); err != nil {
+			return delivery, err
+		}
+
+		claimed := path + ".sending"
+		if err := os.Rename(path, claimed); err != nil {
+			continue // Another flush got it
+		}
+		// The claim expires spoolClaimTimeout after it was made, not after the event
+		now := time.Now()
+		os.Chtimes(claimed, now, now)
+
+		spooled, err := readSpooledEvent(claimed)
+		webhook, configured := webhooks[spooled.Webhook]
+		if err != nil || !configured {
+			if err == nil {
+				err = fmt.Errorf("webhook '%s' is not configured", spooled.Webhook)
+			}
+			m.logf(VerbosityVerbose, "dropping spooled event %s: %v", filepath.Base(path), err)
+			os.Remove(claimed)
+			delivery.Dropped++
+			continue
+		}
+		if failing[webhook.Name] {
+			os.Rename(claimed, path)
+			continue
+		}
+
+		if err := m.postEvent(webhook, spooled.Event); err != nil {
+			os.Rename(claimed, path)
+			failing[webhook.Name] = true
+			delivery.Failed = append(delivery.Failed, fmt.Sprintf("webhook '%s': %v", webhook.Name, err))
+			continue
+		}
+		os.Remove(claimed)
+		delivery.Delivered++
+	}
+	return delivery, nil
+}
+
+// postEvent sends one event to a webhook
+func (m *Manager) postEvent(webhook config.WebhookConfig, event Event) error {
+	body, err := json.Marshal(webhookPayload{Text: event.Text(), Event: event})
+	if err != nil {
+		return err
+	}
+	m.logf(VerbosityVerbose, "posting %s event to webhook '%s'", event.Kind, webhook.Name)
+	request, err := http.NewRequestWithContext(m.Context(), http.MethodPost, webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("User-Agent", "occtx")
+
+	response, err := webhookClient.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(response.Body, 64<<10))
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		return fmt.Errorf("%s", response.Status)
+	}
+	return nil
+}
+
+// spoolFiles returns the spooled events, oldest first. Claims older than
+// spoolClaimTimeout are released first.
+func (m *Manager) spoolFiles() ([]string, error) {
+	dir := m.paths.GetWebhookSpoolDir()
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var files []string
+	for _, entry := range entries {
+		name := entry.Name()
+		path := filepath.Join(dir, name)
+		if strings.HasSuffix(name, ".json.sending") {
+			if info, err := entry.Info(); err == nil && time.Since(info.ModTime()) > spoolClaimTimeout {
+				released := strings.TrimSuffix(path, ".sending")
+				if os.Rename(path, released) == nil {
+					files = append(files, released)
+				}
+			}
+			continue
+		}
+		if strings.HasSuffix(name, ".json") {
+			files = append(files, path)
+		}
+	}
+	// Names start with the time in nanoseconds, all of the same width
+	sort.Strings(files)
+	return files, nil
+}
+
+func readSpooledEvent(path string) (spooledEvent, error) {
+	var spooled spooledEvent
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return spooled, err
+	}
+	err = json.Unmarshal(data, &spooled)
+	return spooled, err
+}
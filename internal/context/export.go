@@ -0,0 +1,244 @@
+package context
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// RedactedValue replaces secrets in redacted exports
+const RedactedValue = "<redacted>"
+
+// secretKeyPattern matches key names whose values are treated as secrets
+var secretKeyPattern = regexp.MustCompile(`(?i)(api_?key|token|secret|password|authorization)`)
+
+// secretValuePattern matches values that look like well-known API keys and
+// tokens whatever key they are under, e.g. in a header map
+var secretValuePattern = regexp.MustCompile(`^(sk-[A-Za-z0-9_-]{16,}|gh[pousr]_[A-Za-z0-9]{20,}|xox[abpr]-[A-Za-z0-9-]{10,}|AKIA[0-9A-Z]{16}|AIza[0-9A-Za-z_-]{35}|(?i:bearer) [A-Za-z0-9._~+/=-]{16,})$`)
+
+// ExportOptions is the transformation pipeline applied by ExportContext:
+// keys are stripped first, then secrets redacted, then the result formatted.
+// The zero value exports the file unchanged.
+type ExportOptions struct {
+	StripKeys    []KeyPath // Values to remove entirely
+	Redact       bool      // Replace secret-looking string values with RedactedValue
+	Minify       bool      // Emit compact JSON instead of indented JSON
+	IncludeLocal bool      // Merge in the machine-local override, left out by default
+	GitSafe      bool      // Output safe to commit to a shared repo; see GitSafe
+}
+
+// transforms reports whether the options change the exported content
+func (o ExportOptions) transforms() bool {
+	return len(o.StripKeys) > 0 || o.Redact || o.Minify || o.IncludeLocal || o.GitSafe
+}
+
+// checkGitSafe rejects options that contradict the git-safe profile: it
+// redacts, leaves out the machine-local override and formats the output the
+// same way every time
+func (o ExportOptions) checkGitSafe() error {
+	switch {
+	case !o.GitSafe:
+		return nil
+	case o.IncludeLocal:
+		return fmt.Errorf("a git-safe export leaves out the machine-local override; drop --include-local")
+	case o.Minify:
+		return fmt.Errorf("a git-safe export is always indented; drop --minify")
+	}
+	return nil
+}
+
+// ExportContext returns the content of a context after applying opts. Any
+// transformation re-encodes the context as plain JSON, dropping JSONC comments.
+func (m *Manager) ExportContext(name string, opts ExportOptions) ([]byte, error) {
+	if err := opts.checkGitSafe(); err != nil {
+		return nil, err
+	}
+	ctx, err := m.GetContext(name)
+	if err != nil {
+		return nil, err
+	}
+
+	raw, err := os.ReadFile(ctx.FilePath)
+	if err != nil {
+		return nil, err
+	}
+	if !opts.transforms() {
+		return raw, nil
+	}
+
+	data, err := parseContextData(ctx.FilePath, raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid JSON in context '%s': %v", ctx.Name, err)
+	}
+	if opts.IncludeLocal {
+		ctx.Data = data
+		if _, err := m.applyLocalOverride(ctx); err != nil {
+			return nil, err
+		}
+		data = ctx.Data
+	}
+
+	for _, path := range opts.StripKeys {
+		path.Delete(data)
+	}
+	if opts.Redact || opts.GitSafe {
+		redactSecrets(data)
+	}
+	if opts.GitSafe {
+		redactSecretValues(data)
+	}
+
+	// Encoder appends the trailing newline; keep "<" and ">" readable
+	var out bytes.Buffer
+	if opts.GitSafe {
+		out.WriteString(gitSafeHeader(ctx.Name))
+	}
+	encoder := json.NewEncoder(&out)
+	encoder.SetEscapeHTML(false)
+	if !opts.Minify {
+		encoder.SetIndent("", "  ")
+	}
+	if err := encoder.Encode(data); err != nil {
+		return nil, err
+	}
+	return out.Bytes(), nil
+}
+
+// redactSecrets replaces string values under secret-looking keys, keeping
+// opencode variable references such as "{env:OPENAI_API_KEY}" since they
+// hold no secret themselves
+func redactSecrets(node interface{}) {
+	switch v := node.(type) {
+	case map[string]interface{}:
+		for key, child := range v {
+			if s, ok := child.(string); ok && secretKeyPattern.MatchString(key) {
+				if s != "" && !isVariableReference(s) {
+					v[key] = RedactedValue
+				}
+				continue
+			}
+			redactSecrets(child)
+		}
+	case []interface{}:
+		for _, item := range v {
+			redactSecrets(item)
+		}
+	}
+}
+
+// redactSecretValues replaces string values that look like API keys or
+// tokens, wherever they are
+func redactSecretValues(node interface{}) {
+	switch v := node.(type) {
+	case map[string]interface{}:
+		for key, child := range v {
+			if s, ok := child.(string); ok && secretValuePattern.MatchString(strings.TrimSpace(s)) {
+				v[key] = RedactedValue
+				continue
+			}
+			redactSecretValues(child)
+		}
+	case []interface{}:
+		for i, item := range v {
+			if s, ok := item.(string); ok && secretValuePattern.MatchString(strings.TrimSpace(s)) {
+				v[i] = RedactedValue
+				continue
+			}
+			redactSecretValues(item)
+		}
+	}
+}
+
+// gitSafeHeader is the provenance comment a git-safe export starts with,
+// which makes the output JSONC. It carries the day rather than the time, so
+// exporting an unchanged context again on the same day gives the same file.
+func gitSafeHeader(name string) string {
+	by := currentUserName()
+	if by == "" {
+		by = "unknown"
+	}
+	return fmt.Sprintf("// opencode context: %s\n// Exported with occtx --git-safe by %s on %s\n// Secrets are redacted and machine-local overrides left out\n",
+		name, by, time.Now().Format("2006-01-02"))
+}
+
+// isVariableReference reports whether s is an opencode {env:...} or {file:...} substitution
+func isVariableReference(s string) bool {
+	s = strings.TrimSpace(s)
+	return strings.HasPrefix(s, "{env:") || strings.HasPrefix(s, "{file:")
+}
+
+// ExportedFile is a context written by ExportTree
+type ExportedFile struct {
+	Context string `json:"context"`
+	Path    string `json:"path"`
+}
+
+// ExportTree writes every context carrying all of the tags to dir, one file
+// per context, after applying opts. Shared contexts are left out: they are
+// already shared. Files of other contexts in dir are left alone.
+func (m *Manager) ExportTree(dir string, tags []TagSelector, opts ExportOptions) ([]ExportedFile, error) {
+	if err := opts.checkGitSafe(); err != nil {
+		return nil, err
+	}
+	contexts, err := m.ListContexts()
+	if err != nil {
+		return nil, err
+	}
+	meta, err := m.loadMetadata()
+	if err != nil {
+		return nil, err
+	}
+
+	var selected []*Context
+	for _, ctx := range contexts {
+		if ctx.Shared {
+			continue
+		}
+		var tagged map[string]string
+		if entry := meta.Contexts[ctx.Name]; entry != nil {
+			tagged = entry.Tags
+		}
+		if MatchTags(tagged, tags) {
+			selected = append(selected, ctx)
+		}
+	}
+	if len(selected) == 0 {
+		return nil, nil
+	}
+
+	if err := os.MkdirAll(dir, m.dirMode()); err != nil {
+		return nil, err
+	}
+	var exported []ExportedFile
+	for _, ctx := range selected {
+		if err := m.checkCanceled("export contexts"); err != nil {
+			return exported, err
+		}
+		data, err := m.ExportContext(ctx.Name, opts)
+		if err != nil {
+			return exported, err
+		}
+
+		// Untransformed exports are the file as it is, comments and all;
+		// git-safe ones start with a comment header
+		format := FormatJSON
+		switch {
+		case opts.GitSafe:
+			format = FormatJSONC
+		case !opts.transforms():
+			format = ctx.Format
+		}
+		path := filepath.Join(dir, ctx.Name+format.FileExtension())
+		if err := writeFileAtomic(path, data, m.fileMode()); err != nil {
+			return exported, err
+		}
+		m.logf(VerbosityVerbose, "exported '%s' to %s", ctx.Name, path)
+		exported = append(exported, ExportedFile{Context: ctx.Name, Path: path})
+	}
+	return exported, nil
+}
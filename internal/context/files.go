@@ -0,0 +1,68 @@
+package context
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// writeFileAtomic writes data to a temp file next to path and renames it
+// into place. Like os.WriteFile, the process umask applies to perm.
+func writeFileAtomic(path string, data []byte, perm os.FileMode) error {
+	return writeFileAtomicIf(path, data, perm, nil)
+}
+
+// writeFileAtomicIf is writeFileAtomic with a last-moment check: the rename
+// only happens if check (when not nil) returns nil. Each call uses its own
+// hidden temp file, so concurrent writers never share one, and the temp file
+// is removed on any failure. The data is synced before the rename, so a full
+// disk is reported here instead of leaving a truncated file in place.
+func writeFileAtomicIf(path string, data []byte, perm os.FileMode, check func() error) error {
+	return writeFileAtomicWith(path, data, perm&^processUmask(), nil, check)
+}
+
+// writeFileAtomicWith is writeFileAtomicIf that also lets prepare (when not
+// nil) adjust the temp file, e.g. its owner, before it is renamed into place.
+// perm is used as is, without the umask, to reproduce a given mode exactly.
+func writeFileAtomicWith(path string, data []byte, perm os.FileMode, prepare func(tempPath string) error, check func() error) error {
+	if err := CheckFreeSpace(path, uint64(len(data))); err != nil {
+		return err
+	}
+
+	temp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+"-*.tmp")
+	if err != nil {
+		return writeError(path, len(data), err)
+	}
+	tempPath := temp.Name()
+	defer os.Remove(tempPath)
+
+	n, err := temp.Write(data)
+	if err == nil && n < len(data) {
+		err = io.ErrShortWrite
+	}
+	if err == nil {
+		err = temp.Sync()
+	}
+	if closeErr := temp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return writeError(path, len(data), err)
+	}
+
+	if err := os.Chmod(tempPath, perm); err != nil {
+		return err
+	}
+	if prepare != nil {
+		if err := prepare(tempPath); err != nil {
+			return err
+		}
+	}
+	if check != nil {
+		if err := check(); err != nil {
+			return err
+		}
+	}
+
+	return os.Rename(tempPath, path)
+}
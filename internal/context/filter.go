@@ -0,0 +1,121 @@
+package context
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ListFilter narrows the contexts returned by FilterContexts; zero values match everything
+type ListFilter struct {
+	NameGlob string         // Shell pattern matched against the context name
+	Format   *ContextFormat // Only contexts stored in this format
+	Fields   []FieldFilter  // All must match the context content
+}
+
+// FieldFilter matches a value at a dotted key path inside a context
+type FieldFilter struct {
+	Path  []string
+	Value string
+}
+
+// ParseFieldFilter parses "key.path=value". The value matches a scalar equal
+// to it, an object with that key, or an array containing it.
+func ParseFieldFilter(expr string) (FieldFilter, error) {
+	key, value, ok := strings.Cut(expr, "=")
+	key = strings.TrimSpace(key)
+	if !ok || key == "" {
+		return FieldFilter{}, fmt.Errorf("invalid filter '%s': expected key=value", expr)
+	}
+
+	return FieldFilter{Path: strings.Split(key, "."), Value: value}, nil
+}
+
+// Matches reports whether the context data satisfies the filter
+func (f FieldFilter) Matches(data map[string]interface{}) bool {
+	var node interface{} = data
+	for _, key := range f.Path {
+		object, ok := node.(map[string]interface{})
+		if !ok {
+			return false
+		}
+		if node, ok = object[key]; !ok {
+			return false
+		}
+	}
+
+	switch v := node.(type) {
+	case map[string]interface{}:
+		_, ok := v[f.Value]
+		return ok
+	case []interface{}:
+		for _, item := range v {
+			if fmt.Sprint(item) == f.Value {
+				return true
+			}
+		}
+		return false
+	case nil:
+		return f.Value == "null"
+	default:
+		return fmt.Sprint(v) == f.Value
+	}
+}
+
+// FilterContexts returns the contexts matching filter. Content is only parsed
+// when field filters are given; matching contexts then have Data populated.
+func (m *Manager) FilterContexts(filter ListFilter) ([]*Context, error) {
+	if filter.NameGlob != "" {
+		if _, err := filepath.Match(filter.NameGlob, ""); err != nil {
+			return nil, fmt.Errorf("invalid name glob '%s': %v", filter.NameGlob, err)
+		}
+	}
+
+	contexts, err := m.ListContexts()
+	if err != nil {
+		return nil, err
+	}
+
+	var matched []*Context
+	for _, ctx := range contexts {
+		if filter.NameGlob != "" {
+			if ok, _ := filepath.Match(filter.NameGlob, ctx.Name); !ok {
+				continue
+			}
+		}
+
+		if filter.Format != nil && ctx.Format != *filter.Format {
+			continue
+		}
+
+		if len(filter.Fields) > 0 {
+			raw, err := os.ReadFile(ctx.FilePath)
+			if err != nil {
+				return nil, err
+			}
+			data, err := parseContextData(ctx.FilePath, raw)
+			if err != nil {
+				return nil, fmt.Errorf("invalid JSON in context '%s': %v", ctx.Name, err)
+			}
+			if !matchesAll(filter.Fields, data) {
+				continue
+			}
+			ctx.Data = data
+		}
+
+		matched = append(matched, ctx)
+	}
+
+	return matched, nil
+}
+
+// matchesAll reports whether data satisfies every field filter
+func matchesAll(filters []FieldFilter, data map[string]interface{}) bool {
+	for _, f := range filters {
+		if !f.Matches(data) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,89 @@
+package context
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// Freshness tells how a local copy relates to the shared or mirrored context
+// it was taken from
+type Freshness string
+
+const (
+	FreshnessInSync      Freshness = "in-sync"      // Same content as the source
+	FreshnessLocalAhead  Freshness = "local-ahead"  // Only the local copy changed since it was taken
+	FreshnessRemoteAhead Freshness = "remote-ahead" // Only the source changed; 'occtx update --from-shared' fast-forwards
+	FreshnessConflict    Freshness = "conflict"     // Both changed
+)
+
+// ContextFreshness returns the freshness of the local contexts among
+// contexts that track a shared or mirrored context, keyed by name. It is
+// computed from the baseline digest recorded when the copy was taken or last
+// updated. Contexts that track nothing, or whose source can't be read, are
+// left out.
+func (m *Manager) ContextFreshness(contexts []*Context) (map[string]Freshness, error) {
+	allMeta, err := m.ListContextMeta()
+	if err != nil {
+		return nil, err
+	}
+
+	freshness := make(map[string]Freshness)
+	for _, ctx := range contexts {
+		meta := allMeta[ctx.Name]
+		if ctx.Shared || meta == nil || meta.Baseline == "" {
+			continue
+		}
+		sourcePath, ok := m.trackedSourcePath(ctx.Name, meta.Source)
+		if !ok {
+			continue
+		}
+		source, err := readContentFile(sourcePath)
+		if err != nil {
+			continue
+		}
+		local, err := readContentFile(ctx.FilePath)
+		if err != nil {
+			continue
+		}
+		freshness[ctx.Name] = compareFreshness(contentChecksum(local), contentChecksum(source), meta.Baseline)
+	}
+	return freshness, nil
+}
+
+// trackedSourcePath returns the file a local copy was taken from: the path
+// recorded with the copy, else the shared context of the same name, which is
+// what 'occtx update --from-shared' uses
+func (m *Manager) trackedSourcePath(name string, source Source) (string, bool) {
+	if source.Kind == SourceCopy && filepath.IsAbs(source.From) {
+		if _, err := os.Stat(source.From); err == nil {
+			return source.From, true
+		}
+	}
+	path, _, ok := m.findSharedContext(name)
+	return path, ok
+}
+
+// compareFreshness classifies the local and source digests against the
+// baseline both started from
+func compareFreshness(local, source, baseline string) Freshness {
+	localChanged, sourceChanged := local != baseline, source != baseline
+	switch {
+	case local == source:
+		return FreshnessInSync
+	case localChanged && sourceChanged:
+		return FreshnessConflict
+	case sourceChanged:
+		return FreshnessRemoteAhead
+	default:
+		return FreshnessLocalAhead
+	}
+}
+
+// readContentFile reads and decodes a context file
+func readContentFile(path string) (map[string]interface{}, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return parseContextData(path, data)
+}
@@ -0,0 +1,17 @@
+//go:build !windows
+
+package context
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// isolateHook starts a hook in its own process group and makes cancelling it
+// kill the whole group, so children it leaves behind don't outlive it
+func isolateHook(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	cmd.Cancel = func() error {
+		return syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+	}
+}
@@ -0,0 +1,14 @@
+//go:build windows
+
+package context
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// isolateHook starts a hook in its own process group. Cancelling it kills the
+// hook only; WaitDelay stops waiting for children that keep its output open.
+func isolateHook(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{CreationFlags: syscall.CREATE_NEW_PROCESS_GROUP}
+}
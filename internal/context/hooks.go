@@ -0,0 +1,135 @@
+package context
+
+import (
+	"bytes"
+	gocontext "context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hungthai1401/occtx/internal/config"
+)
+
+// HookError is returned when a hook whose on_failure is abort fails. A
+// failing pre-switch hook leaves the active config as it was; after a
+// post-switch hook the switch has already happened.
+type HookError struct {
+	Hook  string
+	Event string
+	Err   error
+}
+
+func (e *HookError) Error() string {
+	if e.Event == config.HookPreSwitch {
+		return fmt.Sprintf("%s hook '%s' failed, switch aborted: %v", e.Event, e.Hook, e.Err)
+	}
+	return fmt.Sprintf("%s hook '%s' failed: %v", e.Event, e.Hook, e.Err)
+}
+
+func (e *HookError) Unwrap() error {
+	return e.Err
+}
+
+// Hooks returns the configured hooks in the order they run: by order, then
+// as listed in the config
+func (m *Manager) Hooks() []config.HookConfig {
+	hooks := append([]config.HookConfig(nil), m.config.Hooks...)
+	sort.SliceStable(hooks, func(i, j int) bool {
+		return hooks[i].Order < hooks[j].Order
+	})
+	return hooks
+}
+
+// runHooks runs the hooks for event in order, with their output on stderr.
+// A failing hook set to warn is reported and the next one runs; one set to
+// abort stops the run and is returned as a *HookError.
+func (m *Manager) runHooks(event, context, previous string) error {
+	for _, hook := range m.Hooks() {
+		if hook.On != event {
+			continue
+		}
+		m.logf(VerbosityVerbose, "running %s hook '%s'", event, hook.Name)
+		err := m.runHook(hook, context, previous, os.Stderr)
+		if err == nil {
+			continue
+		}
+		if !hook.Aborts() {
+			m.warnf("%s hook '%s' failed: %v", event, hook.Name, err)
+			continue
+		}
+		return &HookError{Hook: hook.Name, Event: event, Err: err}
+	}
+	return nil
+}
+
+// TestHook runs the named hook as if switching to context and returns its
+// combined output, so a hook can be tried without switching. The failure
+// policy doesn't apply: any failure is returned.
+func (m *Manager) TestHook(name, context string) ([]byte, error) {
+	for _, hook := range m.config.Hooks {
+		if hook.Name != name {
+			continue
+		}
+		previous := ""
+		if state, err := m.loadState(); err == nil {
+			previous = state.Current
+		}
+		var out bytes.Buffer
+		if err := m.runHook(hook, context, previous, &out); err != nil {
+			return out.Bytes(), fmt.Errorf("hook '%s' failed: %v", name, err)
+		}
+		return out.Bytes(), nil
+	}
+	return nil, fmt.Errorf("no hook named '%s' in %s", name, m.paths.ConfigFile)
+}
+
+// hookWaitDelay bounds the wait for a killed hook's output to close
+const hookWaitDelay = time.Second
+
+// runHook runs one hook with its timeout and environment allowlist
+func (m *Manager) runHook(hook config.HookConfig, context, previous string, output io.Writer) error {
+	timeout := hook.EffectiveTimeout()
+	ctx, cancel := gocontext.WithTimeout(m.Context(), timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, hook.Run[0], hook.Run[1:]...)
+	cmd.Env = append(hookEnv(os.Environ(), hook.EffectiveEnv()),
+		"OCCTX_HOOK="+hook.Name,
+		"OCCTX_HOOK_EVENT="+hook.On,
+		"OCCTX_CONTEXT="+context,
+		"OCCTX_PREVIOUS_CONTEXT="+previous,
+		"OCCTX_ACTIVE_CONFIG="+m.paths.GetActiveConfigPath(m.useProject),
+	)
+	cmd.Stdout = output
+	cmd.Stderr = output
+	isolateHook(cmd)
+	// Don't wait on the output of anything still running after the hook was killed
+	cmd.WaitDelay = hookWaitDelay
+
+	err := cmd.Run()
+	if errors.Is(ctx.Err(), gocontext.DeadlineExceeded) {
+		return fmt.Errorf("timed out after %s", timeout)
+	}
+	return err
+}
+
+// hookEnv keeps the entries of environ whose names are in allow. An allowlist
+// entry ending in "*" matches names by prefix.
+func hookEnv(environ, allow []string) []string {
+	var env []string
+	for _, entry := range environ {
+		name, _, _ := strings.Cut(entry, "=")
+		for _, pattern := range allow {
+			if prefix, ok := strings.CutSuffix(pattern, "*"); ok && strings.HasPrefix(name, prefix) || name == pattern {
+				env = append(env, entry)
+				break
+			}
+		}
+	}
+	return env
+}
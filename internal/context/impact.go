@@ -0,0 +1,93 @@
+package context
+
+import (
+	"sort"
+)
+
+// DependentKind says how something depends on a context
+type DependentKind string
+
+const (
+	DependentCopy     DependentKind = "copy"     // A local copy taken with 'occtx copy'
+	DependentOverride DependentKind = "override" // The machine-local override merged on top of it
+	DependentActive   DependentKind = "active"   // The active config, written from the current context
+)
+
+// Dependent is a context or file affected by changes to another context
+type Dependent struct {
+	Name string        `json:"name"` // Context name; the override's and active config's base name
+	Kind DependentKind `json:"kind"`
+	Path string        `json:"path"`
+	// Changes are what the dependent would see from the context as it is
+	// now: for copies, what re-copying would change; for the active config,
+	// what switching again would change. Overrides apply on every switch and
+	// have none.
+	Changes []ContextChange `json:"changes,omitempty"`
+}
+
+// Impact lists what depends on context name: local copies of it, its local
+// override and, when it is the current context, the active config. occtx has
+// no inheritance between contexts, so these are the only dependents.
+func (m *Manager) Impact(name string) ([]Dependent, error) {
+	base, err := m.GetContext(name)
+	if err != nil {
+		return nil, err
+	}
+
+	// Copies record a local source by name and a shared one by path
+	sources := map[string]bool{base.FilePath: true}
+	if !base.Shared {
+		sources[base.Name] = true
+	}
+
+	var dependents []Dependent
+
+	meta, err := m.loadMetadata()
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(meta.Contexts))
+	for contextName, entry := range meta.Contexts {
+		if contextName != base.Name && entry.Source.Kind == SourceCopy && sources[entry.Source.From] {
+			names = append(names, contextName)
+		}
+	}
+	sort.Strings(names)
+	for _, contextName := range names {
+		copied, err := m.GetContext(contextName)
+		if err != nil {
+			// Metadata can outlive its context
+			continue
+		}
+		dependents = append(dependents, Dependent{
+			Name:    copied.Name,
+			Kind:    DependentCopy,
+			Path:    copied.FilePath,
+			Changes: diffContextData(copied.Data, base.Data),
+		})
+	}
+
+	if path, ok := m.LocalOverridePath(base.Name); ok {
+		dependents = append(dependents, Dependent{Name: base.Name, Kind: DependentOverride, Path: path})
+	}
+
+	state, err := m.loadState()
+	if err != nil {
+		return nil, err
+	}
+	if state.Current == base.Name {
+		path, data, err := m.activeConfigFor(base)
+		if err != nil {
+			return nil, err
+		}
+		dependent := Dependent{Name: base.Name, Kind: DependentActive, Path: path}
+		if file, err := m.planActiveConfig(path, data, base.Data); err != nil {
+			return nil, err
+		} else if file != nil {
+			dependent.Changes = file.Changes
+		}
+		dependents = append(dependents, dependent)
+	}
+
+	return dependents, nil
+}
@@ -0,0 +1,268 @@
+package context
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hungthai1401/occtx/internal/util"
+)
+
+// Policy says what an import check does with its findings
+type Policy string
+
+const (
+	PolicyOff    Policy = "off"    // Don't run the check
+	PolicyWarn   Policy = "warn"   // Report findings but import anyway
+	PolicyReject Policy = "reject" // Refuse the import on any finding
+)
+
+// ParsePolicy parses a policy name; empty means off and "deny" is an alias of reject
+func ParsePolicy(s string) (Policy, error) {
+	switch Policy(strings.ToLower(strings.TrimSpace(s))) {
+	case "", PolicyOff:
+		return PolicyOff, nil
+	case PolicyWarn:
+		return PolicyWarn, nil
+	case PolicyReject, "deny":
+		return PolicyReject, nil
+	default:
+		return "", fmt.Errorf("invalid policy '%s' (expected off, warn or reject/deny)", s)
+	}
+}
+
+// ImportOptions controls the checks and conversion applied on import.
+// Imported content is always re-encoded with sorted keys.
+type ImportOptions struct {
+	Format   ContextFormat // Format the context is stored in
+	Validate Policy        // Check the content against the opencode config schema
+	Secrets  Policy        // Look for literal API keys, tokens and passwords
+	Merge    *MergeOptions // Merge into an existing context of the same name instead of creating one
+}
+
+// ImportRejectedError is returned when a check with PolicyReject has findings
+type ImportRejectedError struct {
+	Findings []string
+}
+
+func (e *ImportRejectedError) Error() string {
+	return fmt.Sprintf("import rejected by policy:\n  %s", strings.Join(e.Findings, "\n  "))
+}
+
+// ImportContextWithOptions creates a new context from JSON or JSONC data,
+// or with opts.Merge merges the data into the existing context, running the
+// checks in opts on the result first. Findings of checks with PolicyWarn are
+// returned as warnings; any finding of a PolicyReject check aborts the import,
+// as does any warning in strict mode.
+func (m *Manager) ImportContextWithOptions(name string, data []byte, source Source, opts ImportOptions) ([]string, error) {
+	if err := m.CheckWritable("import context"); err != nil {
+		return nil, err
+	}
+	defer m.timeOperation("import")()
+
+	jsonData, existing, warnings, err := m.prepareImport(name, data, opts)
+	if err != nil {
+		return warnings, err
+	}
+
+	if existing != nil {
+		return warnings, m.rewriteContext(existing, jsonData, "merge", LogMerged, "")
+	}
+
+	if err := m.prepareNewContext(name); err != nil {
+		return warnings, err
+	}
+
+	return warnings, m.writeNewContext(name, opts.Format, jsonData, source)
+}
+
+// prepareImport parses imported data, merges it into the existing context
+// with opts.Merge (returning that context) and runs the checks in opts
+func (m *Manager) prepareImport(name string, data []byte, opts ImportOptions) (map[string]interface{}, *Context, []string, error) {
+	// JSONC comments are accepted; the stored format is chosen by opts.Format
+	jsonData, err := util.ParseJSONC(data)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("invalid JSON: %v", err)
+	}
+	m.logf(VerbosityVerbose, "import: parsed %d bytes, storing as %s", len(data), opts.Format.DisplayName())
+
+	var existing *Context
+	if opts.Merge != nil {
+		if existing, err = m.GetContext(name); err == nil {
+			if err := existing.CheckModifiable("merge into"); err != nil {
+				return nil, nil, nil, err
+			}
+			jsonData = MergeData(existing.Data, jsonData, *opts.Merge)
+			m.logf(VerbosityVerbose, "import: merging into %s", existing.FilePath)
+		} else {
+			var notFound *NotFoundError
+			if !errors.As(err, &notFound) {
+				return nil, nil, nil, err
+			}
+			existing = nil
+			jsonData = MergeData(map[string]interface{}{}, jsonData, *opts.Merge)
+		}
+	}
+
+	var warnings, rejected []string
+	checks := []struct {
+		policy Policy
+		run    func(map[string]interface{}) []string
+	}{
+		{opts.Validate, validateOpencodeConfig},
+		{opts.Secrets, findSecrets},
+	}
+	for _, check := range checks {
+		if check.policy == PolicyOff || check.policy == "" {
+			continue
+		}
+		findings := check.run(jsonData)
+		if check.policy == PolicyReject {
+			rejected = append(rejected, findings...)
+		} else {
+			warnings = append(warnings, findings...)
+		}
+	}
+	if len(rejected) > 0 {
+		return nil, nil, warnings, &ImportRejectedError{Findings: rejected}
+	}
+	if m.strict && len(warnings) > 0 {
+		return nil, nil, warnings, &StrictWarningsError{Operation: "import", Warnings: warnings}
+	}
+
+	return jsonData, existing, warnings, nil
+}
+
+// opencodeKeyKinds lists the top-level opencode config keys and their JSON kinds
+var opencodeKeyKinds = map[string][]string{
+	"$schema":            {"string"},
+	"theme":              {"string"},
+	"model":              {"string"},
+	"small_model":        {"string"},
+	"username":           {"string"},
+	"share":              {"string"},
+	"layout":             {"string"},
+	"autoupdate":         {"boolean", "string"},
+	"autoshare":          {"boolean"},
+	"snapshot":           {"boolean"},
+	"provider":           {"object"},
+	"mcp":                {"object"},
+	"agent":              {"object"},
+	"mode":               {"object"},
+	"command":            {"object"},
+	"tools":              {"object"},
+	"permission":         {"object"},
+	"keybinds":           {"object"},
+	"formatter":          {"object"},
+	"lsp":                {"object"},
+	"experimental":       {"object"},
+	"tui":                {"object"},
+	"watcher":            {"object"},
+	"disabled_providers": {"array"},
+	"enabled_providers":  {"array"},
+	"instructions":       {"array"},
+	"plugin":             {"array"},
+}
+
+// validateOpencodeConfig checks top-level keys and value kinds against the
+// opencode config schema
+func validateOpencodeConfig(data map[string]interface{}) []string {
+	var findings []string
+	for _, key := range sortedKeys(data) {
+		if key == overridesKey {
+			// Conditional overrides, resolved when switching
+			continue
+		}
+		kinds, ok := opencodeKeyKinds[key]
+		if !ok {
+			findings = append(findings, fmt.Sprintf("schema: unknown key '%s'", key))
+			continue
+		}
+		kind := jsonKind(data[key])
+		if !containsKind(kinds, kind) {
+			findings = append(findings, fmt.Sprintf("schema: '%s' must be %s, got %s", key, strings.Join(kinds, " or "), kind))
+		}
+	}
+
+	for _, key := range []string{"model", "small_model"} {
+		if model, ok := data[key].(string); ok && !strings.Contains(model, "/") {
+			findings = append(findings, fmt.Sprintf("schema: '%s' must be provider/model, got '%s'", key, model))
+		}
+	}
+	if share, ok := data["share"].(string); ok && share != "manual" && share != "auto" && share != "disabled" {
+		findings = append(findings, fmt.Sprintf("schema: 'share' must be manual, auto or disabled, got '%s'", share))
+	}
+
+	return findings
+}
+
+// findSecrets reports literal values under secret-looking keys; opencode
+// {env:...} and {file:...} references are fine
+func findSecrets(data map[string]interface{}) []string {
+	var findings []string
+	var walk func(node interface{}, path string)
+	walk = func(node interface{}, path string) {
+		switch v := node.(type) {
+		case map[string]interface{}:
+			for _, key := range sortedKeys(v) {
+				child := v[key]
+				childPath := key
+				if path != "" {
+					childPath = path + "." + key
+				}
+				if s, ok := child.(string); ok && secretKeyPattern.MatchString(key) {
+					if s != "" && !isVariableReference(s) {
+						findings = append(findings, fmt.Sprintf("secret: literal value at '%s' (use {env:VAR} instead)", childPath))
+					}
+					continue
+				}
+				walk(child, childPath)
+			}
+		case []interface{}:
+			for i, item := range v {
+				walk(item, fmt.Sprintf("%s.%d", path, i))
+			}
+		}
+	}
+	walk(data, "")
+	return findings
+}
+
+// jsonKind names the JSON type of a decoded value
+func jsonKind(value interface{}) string {
+	switch value.(type) {
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case float64:
+		return "number"
+	case map[string]interface{}:
+		return "object"
+	case []interface{}:
+		return "array"
+	default:
+		return "null"
+	}
+}
+
+// containsKind reports whether kinds includes kind
+func containsKind(kinds []string, kind string) bool {
+	for _, k := range kinds {
+		if k == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// sortedKeys returns the keys of an object in order, for stable output
+func sortedKeys(object map[string]interface{}) []string {
+	keys := make([]string, 0, len(object))
+	for key := range object {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,14 @@
+package context
+
+import "github.com/hungthai1401/occtx/pkg/keypath"
+
+// KeyPath selects values inside a context by dotted keys, e.g.
+// "provider.*.options.apiKey", or by JSON pointer. A "*" segment matches
+// every key of an object and every element of an array. The engine lives in
+// pkg/keypath so plugins can use it too.
+type KeyPath = keypath.Path
+
+// ParseKeyPath parses a dotted key path, or a JSON pointer when expr starts with "/"
+func ParseKeyPath(expr string) (KeyPath, error) {
+	return keypath.Parse(expr)
+}
@@ -0,0 +1,76 @@
+package context
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// LocalOverrideSuffix marks a machine-local override file: <name>.local.json
+// (or .jsonc) in the contexts directory is merged on top of context name
+// when it is written to the active config. Context names can't end with it.
+const LocalOverrideSuffix = ".local"
+
+// isLocalOverrideName reports whether a context file name (without
+// extension) is a local override rather than a context
+func isLocalOverrideName(name string) bool {
+	return strings.HasSuffix(name, LocalOverrideSuffix) && name != LocalOverrideSuffix
+}
+
+// LocalOverridePath returns the local override file of a context and
+// whether it exists. Overrides always live in the local contexts directory,
+// so shared contexts can have them too. Without one, the returned path is
+// where a JSON override would go.
+func (m *Manager) LocalOverridePath(name string) (string, bool) {
+	contextsDir := m.paths.GetContextsDir(m.useProject)
+	for _, f := range GetAllFormats() {
+		path := filepath.Join(contextsDir, name+LocalOverrideSuffix+f.FileExtension())
+		if _, err := os.Stat(path); err == nil {
+			return path, true
+		}
+	}
+	return filepath.Join(contextsDir, name+LocalOverrideSuffix+FormatJSON.FileExtension()), false
+}
+
+// LocalOverride returns the parsed local override of a context, or nil when
+// it has none
+func (m *Manager) LocalOverride(name string) (map[string]interface{}, error) {
+	path, ok := m.LocalOverridePath(name)
+	if !ok {
+		return nil, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	override, err := parseContextData(path, data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid JSON in local override %s: %v", path, err)
+	}
+	return override, nil
+}
+
+// applyLocalOverride merges the context's local override into ctx.Data,
+// using the merge settings of the occtx config. It reports whether there was
+// an override.
+func (m *Manager) applyLocalOverride(ctx *Context) (bool, error) {
+	override, err := m.LocalOverride(ctx.Name)
+	if err != nil || override == nil {
+		return false, err
+	}
+
+	opts, err := ParseMergeOptions(m.config.Merge)
+	if err != nil {
+		return false, fmt.Errorf("merge: %v", err)
+	}
+	data, err := ctx.parsed()
+	if err != nil {
+		return false, err
+	}
+	ctx.Data = MergeData(data, override, opts)
+	ctx.raw = nil
+	m.logf(VerbosityVerbose, "merged local override into '%s'", ctx.Name)
+	return true, nil
+}
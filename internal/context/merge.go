@@ -0,0 +1,179 @@
+package context
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/hungthai1401/occtx/internal/config"
+)
+
+// ArrayMerge says how an array in the overlay combines with the base array
+type ArrayMerge string
+
+const (
+	ArraysReplace ArrayMerge = "replace" // The overlay array wins
+	ArraysAppend  ArrayMerge = "append"  // Base elements, then the overlay's
+	ArraysUnion   ArrayMerge = "union"   // Like append, skipping elements already present
+)
+
+// NullMerge says what a null in the overlay does
+type NullMerge string
+
+const (
+	NullsSet    NullMerge = "set"    // The key is set to null
+	NullsDelete NullMerge = "delete" // The key is removed, as in JSON merge patch
+	NullsIgnore NullMerge = "ignore" // The base value is kept
+)
+
+// MergeOptions controls how MergeData combines two contexts. Objects are
+// always merged key by key; the options cover arrays, nulls and deletions.
+type MergeOptions struct {
+	Arrays         ArrayMerge
+	Nulls          NullMerge
+	DeleteSentinel string // Overlay string value that removes the key; empty disables it
+}
+
+// ParseMergeOptions builds merge options from the occtx config, defaulting
+// to replacing arrays and keeping nulls
+func ParseMergeOptions(cfg config.MergeConfig) (MergeOptions, error) {
+	opts := MergeOptions{DeleteSentinel: cfg.DeleteSentinel}
+
+	switch arrays := ArrayMerge(strings.ToLower(strings.TrimSpace(cfg.Arrays))); arrays {
+	case "":
+		opts.Arrays = ArraysReplace
+	case ArraysReplace, ArraysAppend, ArraysUnion:
+		opts.Arrays = arrays
+	default:
+		return opts, fmt.Errorf("invalid array merge '%s' (expected replace, append or union)", cfg.Arrays)
+	}
+
+	switch nulls := NullMerge(strings.ToLower(strings.TrimSpace(cfg.Nulls))); nulls {
+	case "":
+		opts.Nulls = NullsSet
+	case NullsSet, NullsDelete, NullsIgnore:
+		opts.Nulls = nulls
+	default:
+		return opts, fmt.Errorf("invalid null merge '%s' (expected set, delete or ignore)", cfg.Nulls)
+	}
+
+	return opts, nil
+}
+
+// MergeData deep-merges overlay into base and returns the result, leaving
+// both inputs untouched
+func MergeData(base, overlay map[string]interface{}, opts MergeOptions) map[string]interface{} {
+	return mergeObjects(base, overlay, opts)
+}
+
+func mergeObjects(base, overlay map[string]interface{}, opts MergeOptions) map[string]interface{} {
+	merged := make(map[string]interface{}, len(base)+len(overlay))
+	for key, value := range base {
+		merged[key] = value
+	}
+
+	for key, value := range overlay {
+		if opts.DeleteSentinel != "" && value == opts.DeleteSentinel {
+			delete(merged, key)
+			continue
+		}
+		if value == nil {
+			switch opts.Nulls {
+			case NullsDelete:
+				delete(merged, key)
+				continue
+			case NullsIgnore:
+				if _, ok := merged[key]; ok {
+					continue
+				}
+			}
+			merged[key] = nil
+			continue
+		}
+
+		baseValue, exists := merged[key]
+		if !exists {
+			merged[key] = stripMergeMarkers(value, opts)
+			continue
+		}
+
+		switch overlayValue := value.(type) {
+		case map[string]interface{}:
+			if baseObject, ok := baseValue.(map[string]interface{}); ok {
+				merged[key] = mergeObjects(baseObject, overlayValue, opts)
+				continue
+			}
+		case []interface{}:
+			if baseArray, ok := baseValue.([]interface{}); ok {
+				merged[key] = mergeArrays(baseArray, overlayValue, opts.Arrays)
+				continue
+			}
+		}
+		merged[key] = stripMergeMarkers(value, opts)
+	}
+
+	return merged
+}
+
+func mergeArrays(base, overlay []interface{}, mode ArrayMerge) []interface{} {
+	switch mode {
+	case ArraysAppend:
+		return append(append([]interface{}{}, base...), overlay...)
+	case ArraysUnion:
+		merged := append([]interface{}{}, base...)
+		for _, value := range overlay {
+			if !containsValue(merged, value) {
+				merged = append(merged, value)
+			}
+		}
+		return merged
+	default:
+		return overlay
+	}
+}
+
+// stripMergeMarkers drops sentinel values (and nulls with NullsDelete) from
+// overlay content that has no base counterpart
+func stripMergeMarkers(value interface{}, opts MergeOptions) interface{} {
+	object, ok := value.(map[string]interface{})
+	if !ok {
+		return value
+	}
+	return mergeObjects(map[string]interface{}{}, object, opts)
+}
+
+func containsValue(values []interface{}, value interface{}) bool {
+	for _, v := range values {
+		if reflect.DeepEqual(v, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// rewriteContext replaces the content of an existing context in its own
+// format, checking it against the policies for operation and logging the
+// changes as kind, prefixed by prefix. JSONC comments are not carried over.
+func (m *Manager) rewriteContext(ctx *Context, jsonData map[string]interface{}, operation string, kind LogKind, prefix string) error {
+	since := len(m.warned)
+	if err := m.enforcePolicy(operation, ctx.Name, jsonData); err != nil {
+		return err
+	}
+	if err := m.checkWarnings(operation, since); err != nil {
+		return err
+	}
+
+	data, err := encodeContextData(ctx.Name, ctx.Format, jsonData)
+	if err != nil {
+		return err
+	}
+	if err := writeFileAtomic(ctx.FilePath, data, m.fileMode()); err != nil {
+		return err
+	}
+	m.logf(VerbosityDebug, "wrote %d bytes to %s", len(data), ctx.FilePath)
+
+	if err := m.recordChecksum(ctx.FilePath); err != nil {
+		return err
+	}
+	return m.logChanges(ctx.Name, kind, prefix, ctx.Data, jsonData)
+}
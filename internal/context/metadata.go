@@ -0,0 +1,172 @@
+package context
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Source kinds describing where a context came from
+const (
+	SourceActive   = "active"   // Created from the active config
+	SourceImport   = "import"   // Imported from stdin, a file or a URL
+	SourceCopy     = "copy"     // Copied from another context
+	SourceRemote   = "remote"   // Pulled from a remote/sync source
+	SourceSkeleton = "skeleton" // Generated from a built-in skeleton
+	SourceTemplate = "template" // Rendered from a context template
+)
+
+// Source records the origin of a context
+type Source struct {
+	Kind string `json:"kind"`
+	From string `json:"from,omitempty"` // Origin detail: context name, URL, path...
+}
+
+// String describes the source for humans
+func (s Source) String() string {
+	switch s.Kind {
+	case SourceActive:
+		return "created from active config"
+	case SourceImport:
+		if s.From == "" {
+			return "imported"
+		}
+		return "imported from " + s.From
+	case SourceCopy:
+		return fmt.Sprintf("copied from '%s'", s.From)
+	case SourceRemote:
+		return "pulled from " + s.From
+	case SourceSkeleton:
+		return "created from built-in skeleton"
+	case SourceTemplate:
+		return fmt.Sprintf("rendered from template '%s'", s.From)
+	case "":
+		return "unknown"
+	default:
+		return s.Kind
+	}
+}
+
+// ContextMeta is the metadata occtx keeps about a context
+type ContextMeta struct {
+	Source      Source            `json:"source"`
+	CreatedAt   time.Time         `json:"created_at"`
+	Approval    *Approval         `json:"approval,omitempty"`    // Nil means draft
+	ArchivedAt  *time.Time        `json:"archived_at,omitempty"` // Set while the context is archived
+	Baseline    string            `json:"baseline,omitempty"`    // Digest of the shared content a local copy was taken from
+	CostTier    string            `json:"cost_tier,omitempty"`   // e.g. "expensive"; guarded by the cost config
+	Env         map[string]string `json:"env,omitempty"`         // Exported by the shell hook while the context is current
+	Requires    []string          `json:"requires,omitempty"`    // Checked on switch, e.g. "env:ANTHROPIC_API_KEY"
+	UseCount    int               `json:"use_count,omitempty"`   // Number of switches to the context
+	LastUsed    *time.Time        `json:"last_used,omitempty"`   // Time of the last switch to the context
+	Log         []LogEntry        `json:"log,omitempty"`         // Changelog, oldest first
+	Description string            `json:"description,omitempty"` // What the context is for, shown with its README
+	Tags        map[string]string `json:"tags,omitempty"`        // Labels such as team=platform, to select contexts by
+}
+
+// metadataFile is the on-disk form of the metadata file, keyed by context name
+type metadataFile struct {
+	Contexts map[string]*ContextMeta `json:"contexts"`
+}
+
+// GetContextMeta returns the metadata recorded for a context, or nil if there is none
+// (e.g. contexts created by hand or before provenance was tracked)
+func (m *Manager) GetContextMeta(name string) (*ContextMeta, error) {
+	meta, err := m.loadMetadata()
+	if err != nil {
+		return nil, err
+	}
+
+	return meta.Contexts[name], nil
+}
+
+// ListContextMeta returns the recorded metadata of all contexts, keyed by name
+func (m *Manager) ListContextMeta() (map[string]*ContextMeta, error) {
+	meta, err := m.loadMetadata()
+	if err != nil {
+		return nil, err
+	}
+
+	return meta.Contexts, nil
+}
+
+// recordSource stores the origin of a newly created context. The changelog
+// of a context it replaces is kept.
+func (m *Manager) recordSource(name string, source Source) error {
+	return m.updateMetadata(func(meta *metadataFile) {
+		entry := &ContextMeta{Source: source, CreatedAt: time.Now()}
+		if old := meta.Contexts[name]; old != nil {
+			entry.Log = old.Log
+		}
+		entry.Log = appendLogEntry(entry.Log, newLogEntry(LogCreated, source.String()))
+		meta.Contexts[name] = entry
+	})
+}
+
+// moveMetadata carries metadata over when a context is renamed
+func (m *Manager) moveMetadata(oldName, newName string) error {
+	return m.updateMetadata(func(meta *metadataFile) {
+		if entry, ok := meta.Contexts[oldName]; ok {
+			meta.Contexts[newName] = entry
+			delete(meta.Contexts, oldName)
+		}
+	})
+}
+
+// deleteMetadata forgets the metadata of a deleted context
+func (m *Manager) deleteMetadata(name string) error {
+	return m.updateMetadata(func(meta *metadataFile) {
+		delete(meta.Contexts, name)
+	})
+}
+
+// updateMetadata loads the metadata file, applies change and saves it
+func (m *Manager) updateMetadata(change func(meta *metadataFile)) error {
+	meta, err := m.loadMetadata()
+	if err != nil {
+		return err
+	}
+
+	change(meta)
+	return m.saveMetadata(meta)
+}
+
+// loadMetadata reads the metadata file, returning empty metadata if none exists
+func (m *Manager) loadMetadata() (*metadataFile, error) {
+	metaPath := m.paths.GetMetadataFilePath(m.useProject)
+
+	meta := &metadataFile{Contexts: make(map[string]*ContextMeta)}
+	data, err := os.ReadFile(metaPath)
+	if os.IsNotExist(err) {
+		return meta, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(data, meta); err != nil {
+		return nil, fmt.Errorf("invalid context metadata %s: %v", metaPath, err)
+	}
+	if meta.Contexts == nil {
+		meta.Contexts = make(map[string]*ContextMeta)
+	}
+
+	return meta, nil
+}
+
+// saveMetadata writes the metadata file atomically
+func (m *Manager) saveMetadata(meta *metadataFile) error {
+	metaPath := m.paths.GetMetadataFilePath(m.useProject)
+	if err := os.MkdirAll(filepath.Dir(metaPath), m.dirMode()); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(meta, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return writeFileAtomic(metaPath, data, m.fileMode())
+}
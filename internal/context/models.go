@@ -0,0 +1,291 @@
+package context
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hungthai1401/occtx/internal/suggest"
+	"github.com/hungthai1401/occtx/internal/util"
+)
+
+const (
+	// modelCatalogMinRefresh rate-limits `occtx models update`
+	modelCatalogMinRefresh = time.Hour
+	// modelCatalogStaleAfter is when validation suggests refreshing the catalog
+	modelCatalogStaleAfter = 30 * 24 * time.Hour
+	// modelCatalogMaxSize bounds the downloaded catalog
+	modelCatalogMaxSize = 32 << 20
+)
+
+// modelCatalogClient fetches the catalog; a slow server must not hang occtx
+var modelCatalogClient = &http.Client{Timeout: 30 * time.Second}
+
+// ModelCatalog is the cached list of known models per provider
+type ModelCatalog struct {
+	Source    string              `json:"source"`
+	FetchedAt time.Time           `json:"fetched_at"`
+	Providers map[string][]string `json:"providers"` // Provider ID to sorted model IDs
+}
+
+// RefreshTooSoonError is returned when the catalog was fetched too recently
+type RefreshTooSoonError struct {
+	FetchedAt time.Time
+	RetryIn   time.Duration
+}
+
+func (e *RefreshTooSoonError) Error() string {
+	return fmt.Sprintf("model catalog was updated %s ago; try again in %s or use --force",
+		time.Since(e.FetchedAt).Round(time.Minute), e.RetryIn.Round(time.Minute))
+}
+
+// LoadModelCatalog returns the cached catalog, or nil if none was fetched yet
+func (m *Manager) LoadModelCatalog() (*ModelCatalog, error) {
+	path := m.paths.GetModelsCachePath()
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var catalog ModelCatalog
+	if err := json.Unmarshal(data, &catalog); err != nil {
+		return nil, fmt.Errorf("invalid model catalog %s: %v (run 'occtx models update --force')", path, err)
+	}
+	return &catalog, nil
+}
+
+// UpdateModelCatalog downloads the catalog from the configured URL (a
+// models.dev style document) and caches it. Unless force is set, it refuses
+// to download again within an hour of the last update.
+func (m *Manager) UpdateModelCatalog(force bool) (*ModelCatalog, error) {
+	if err := m.CheckWritable("update model catalog"); err != nil {
+		return nil, err
+	}
+	if err := m.CheckOnline("update model catalog"); err != nil {
+		return nil, err
+	}
+
+	if !force {
+		if cached, err := m.LoadModelCatalog(); err == nil && cached != nil {
+			if age := time.Since(cached.FetchedAt); age < modelCatalogMinRefresh {
+				return nil, &RefreshTooSoonError{FetchedAt: cached.FetchedAt, RetryIn: modelCatalogMinRefresh - age}
+			}
+		}
+	}
+
+	url := m.config.Models.EffectiveURL()
+	m.logf(VerbosityVerbose, "fetching model catalog from %s", url)
+	request, err := http.NewRequestWithContext(m.Context(), http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Set("User-Agent", "occtx")
+
+	response, err := modelCatalogClient.Do(request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch model catalog: %w", err)
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch model catalog from %s: %s", url, response.Status)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(response.Body, modelCatalogMaxSize))
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch model catalog: %w", err)
+	}
+
+	catalog, err := parseModelCatalog(body)
+	if err != nil {
+		return nil, fmt.Errorf("invalid model catalog from %s: %v", url, err)
+	}
+	catalog.Source = url
+	catalog.FetchedAt = time.Now().UTC()
+
+	data, err := json.MarshalIndent(catalog, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	path := m.paths.GetModelsCachePath()
+	if err := os.MkdirAll(filepath.Dir(path), m.dirMode()); err != nil {
+		return nil, err
+	}
+	if err := writeFileAtomic(path, data, m.fileMode()); err != nil {
+		return nil, err
+	}
+
+	return catalog, nil
+}
+
+// parseModelCatalog reads the models.dev format: an object of providers,
+// each with an object of models keyed by model ID
+func parseModelCatalog(data []byte) (*ModelCatalog, error) {
+	var document map[string]struct {
+		Models map[string]json.RawMessage `json:"models"`
+	}
+	if err := json.Unmarshal(data, &document); err != nil {
+		return nil, err
+	}
+
+	catalog := &ModelCatalog{Providers: make(map[string][]string, len(document))}
+	for provider, entry := range document {
+		models := make([]string, 0, len(entry.Models))
+		for model := range entry.Models {
+			models = append(models, model)
+		}
+		sort.Strings(models)
+		catalog.Providers[provider] = models
+	}
+	if len(catalog.Providers) == 0 {
+		return nil, fmt.Errorf("no providers found")
+	}
+	return catalog, nil
+}
+
+// Stale reports whether the catalog is old enough to be worth refreshing
+func (c *ModelCatalog) Stale() bool {
+	return time.Since(c.FetchedAt) > modelCatalogStaleAfter
+}
+
+// CheckModels returns a finding for every "provider/model" reference in data
+// that the catalog doesn't know: the top-level model and small_model and the
+// model of each agent and mode. Providers and models declared in the
+// context's own "provider" section are accepted, as are providers missing
+// from the catalog, since custom providers can't be checked.
+func (c *ModelCatalog) CheckModels(data map[string]interface{}) []string {
+	references := map[string]string{}
+	for _, key := range []string{"model", "small_model"} {
+		if model, ok := data[key].(string); ok {
+			references[key] = model
+		}
+	}
+	for _, section := range []string{"agent", "mode"} {
+		entries, _ := data[section].(map[string]interface{})
+		for name, entry := range entries {
+			if object, ok := entry.(map[string]interface{}); ok {
+				if model, ok := object["model"].(string); ok {
+					references[section+"."+name+".model"] = model
+				}
+			}
+		}
+	}
+
+	custom, _ := data["provider"].(map[string]interface{})
+
+	keys := make([]string, 0, len(references))
+	for key := range references {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var findings []string
+	for _, key := range keys {
+		reference := references[key]
+		provider, model, ok := strings.Cut(reference, "/")
+		if !ok || provider == "" || model == "" {
+			findings = append(findings, fmt.Sprintf("%s: '%s' is not in provider/model form", key, reference))
+			continue
+		}
+
+		if declared, ok := custom[provider].(map[string]interface{}); ok {
+			if models, ok := declared["models"].(map[string]interface{}); ok {
+				if _, ok := models[model]; ok {
+					continue
+				}
+			}
+		}
+
+		known, ok := c.Providers[provider]
+		if !ok {
+			if _, declared := custom[provider]; declared {
+				continue
+			}
+			providers := make([]string, 0, len(c.Providers))
+			for id := range c.Providers {
+				providers = append(providers, id)
+			}
+			if hint := suggest.Hint(suggest.Closest(provider, providers)); hint != "" {
+				findings = append(findings, fmt.Sprintf("%s: unknown provider '%s'%s", key, provider, hint))
+			}
+			continue
+		}
+
+		if !util.ContainsSorted(known, model) {
+			findings = append(findings, fmt.Sprintf("%s: unknown model '%s' for provider '%s'%s",
+				key, model, provider, suggest.Hint(firstN(suggest.Closest(model, known), 3))))
+		}
+	}
+	return findings
+}
+
+// CheckContextModels checks a context's model references against the cached
+// catalog. It returns no findings when no catalog has been fetched.
+func (m *Manager) CheckContextModels(name string) ([]string, error) {
+	ctx, err := m.GetContext(name)
+	if err != nil {
+		return nil, err
+	}
+
+	catalog, err := m.LoadModelCatalog()
+	if err != nil || catalog == nil {
+		return nil, err
+	}
+	return catalog.CheckModels(ctx.Data), nil
+}
+
+// checkModels applies the models.validate policy before a switch
+func (m *Manager) checkModels(ctx *Context) error {
+	policy, err := ParsePolicy(m.config.Models.Validate)
+	if err != nil {
+		return fmt.Errorf("models.validate: %v", err)
+	}
+	if policy == PolicyOff {
+		return nil
+	}
+
+	catalog, err := m.LoadModelCatalog()
+	if err != nil {
+		return err
+	}
+	if catalog == nil {
+		m.logf(VerbosityVerbose, "no model catalog cached; skipping model validation")
+		return nil
+	}
+
+	data, err := ctx.parsed()
+	if err != nil {
+		return err
+	}
+	findings := catalog.CheckModels(data)
+	if len(findings) == 0 {
+		return nil
+	}
+	if catalog.Stale() {
+		findings = append(findings, "the model catalog is over 30 days old; run 'occtx models update'")
+	}
+	if policy == PolicyReject {
+		return fmt.Errorf("context '%s' references unknown models (set models.validate to warn to switch anyway):\n  %s",
+			ctx.Name, strings.Join(findings, "\n  "))
+	}
+	for _, finding := range findings {
+		m.warnf("context '%s': %s", ctx.Name, finding)
+	}
+	return nil
+}
+
+// firstN returns at most n leading elements
+func firstN(values []string, n int) []string {
+	if len(values) > n {
+		return values[:n]
+	}
+	return values
+}
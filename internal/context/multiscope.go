@@ -0,0 +1,134 @@
+package context
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// ScopeName names the scope of m in messages
+func (m *Manager) ScopeName() string {
+	if m.useProject {
+		return "project"
+	}
+	return "global"
+}
+
+// fileSnapshot is the content of a file before a change, or its absence
+type fileSnapshot struct {
+	path   string
+	data   []byte
+	mode   os.FileMode
+	exists bool
+}
+
+// scopeSnapshot holds the files a switch may write in one scope
+type scopeSnapshot struct {
+	activeConfigPath string
+	files            []fileSnapshot
+}
+
+// snapshotSwitch records the files SwitchToContext may write: the active
+// config (under either extension), the state file and its backup, the
+// current-context marker and the metadata file
+func (m *Manager) snapshotSwitch() (*scopeSnapshot, error) {
+	activeConfigPath := m.paths.GetActiveConfigPath(m.useProject)
+	base := strings.TrimSuffix(strings.TrimSuffix(activeConfigPath, ".jsonc"), ".json")
+	paths := []string{
+		base + ".json",
+		base + ".jsonc",
+		m.paths.GetStateFilePath(m.useProject),
+		m.paths.GetStateFilePath(m.useProject) + stateBackupSuffix,
+		m.paths.GetCurrentMarkerPath(m.useProject),
+		m.paths.GetMetadataFilePath(m.useProject),
+	}
+
+	snapshot := &scopeSnapshot{activeConfigPath: activeConfigPath}
+	for _, path := range paths {
+		file := fileSnapshot{path: path}
+		info, err := os.Stat(path)
+		switch {
+		case err == nil:
+			if file.data, err = os.ReadFile(path); err != nil {
+				return nil, err
+			}
+			file.mode = info.Mode().Perm()
+			file.exists = true
+		case !os.IsNotExist(err):
+			return nil, err
+		}
+		snapshot.files = append(snapshot.files, file)
+	}
+	return snapshot, nil
+}
+
+// restoreSwitch puts the files of snapshot back as they were
+func (m *Manager) restoreSwitch(snapshot *scopeSnapshot) error {
+	for _, file := range snapshot.files {
+		if !file.exists {
+			if err := os.Remove(file.path); err != nil && !os.IsNotExist(err) {
+				return err
+			}
+			continue
+		}
+		if err := writeFileAtomic(file.path, file.data, file.mode); err != nil {
+			return err
+		}
+	}
+
+	if m.useProject {
+		m.paths.ProjectActiveConfig = snapshot.activeConfigPath
+	} else {
+		m.paths.GlobalActiveConfig = snapshot.activeConfigPath
+	}
+	m.logf(VerbosityVerbose, "restored the %s scope", m.ScopeName())
+	return nil
+}
+
+// SwitchScopes switches each manager's scope to its context called name, as
+// one change. Every scope is checked and its pre-switch hooks run before
+// anything is written; if a write fails, the scopes written so far are
+// restored. Events and post-switch hooks run once every scope has switched.
+func SwitchScopes(name string, managers ...*Manager) error {
+	pending := make([]*pendingSwitch, 0, len(managers))
+	for _, m := range managers {
+		if err := m.CheckWritable("switch context"); err != nil {
+			return err
+		}
+		p, err := m.prepareSwitch(name)
+		if err != nil {
+			return fmt.Errorf("%s scope: %v", m.ScopeName(), err)
+		}
+		pending = append(pending, p)
+	}
+
+	snapshots := make([]*scopeSnapshot, 0, len(managers))
+	for _, m := range managers {
+		snapshot, err := m.snapshotSwitch()
+		if err != nil {
+			return err
+		}
+		snapshots = append(snapshots, snapshot)
+	}
+
+	for i, m := range managers {
+		err := m.writeSwitch(pending[i])
+		if err == nil {
+			continue
+		}
+		err = fmt.Errorf("%s scope: %v", m.ScopeName(), err)
+		for j := i; j >= 0; j-- {
+			if undoErr := managers[j].restoreSwitch(snapshots[j]); undoErr != nil {
+				return fmt.Errorf("switch failed: %v (rollback also failed: %v)", err, undoErr)
+			}
+		}
+		return fmt.Errorf("switch failed, no scope was changed: %v", err)
+	}
+
+	for i, m := range managers {
+		if err := m.finishSwitch(pending[i]); err != nil {
+			return fmt.Errorf("%s scope: %v", m.ScopeName(), err)
+		}
+	}
+	return nil
+}
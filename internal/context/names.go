@@ -0,0 +1,165 @@
+package context
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/hungthai1401/occtx/internal/suggest"
+	"github.com/hungthai1401/occtx/internal/util"
+)
+
+// maxSuggestions caps how many similar names a not-found error lists
+const maxSuggestions = 3
+
+// NotFoundError is returned when a context doesn't exist; it carries the
+// existing names that look like what was meant
+type NotFoundError struct {
+	Name        string
+	Suggestions []string
+	Archived    bool // The context exists in the archive
+}
+
+func (e *NotFoundError) Error() string {
+	if e.Archived {
+		return fmt.Sprintf("context '%s' is archived; run 'occtx unarchive %s' to restore it", e.Name, e.Name)
+	}
+	return fmt.Sprintf("context '%s' not found%s", e.Name, suggest.Hint(e.Suggestions))
+}
+
+// windowsReservedNames are device names that cannot be used as file names on Windows,
+// with or without an extension
+var windowsReservedNames = map[string]bool{
+	"CON": true, "PRN": true, "AUX": true, "NUL": true,
+	"COM1": true, "COM2": true, "COM3": true, "COM4": true, "COM5": true,
+	"COM6": true, "COM7": true, "COM8": true, "COM9": true,
+	"LPT1": true, "LPT2": true, "LPT3": true, "LPT4": true, "LPT5": true,
+	"LPT6": true, "LPT7": true, "LPT8": true, "LPT9": true,
+}
+
+// windowsInvalidChars cannot appear in file names on Windows
+const windowsInvalidChars = `<>:"|?*`
+
+// validateNewContextName applies the portability rules for names being created,
+// so a context made on one platform can still be used on the others
+func (m *Manager) validateNewContextName(name string) error {
+	if err := validateContextName(name); err != nil {
+		return err
+	}
+
+	for _, r := range name {
+		if unicode.IsControl(r) {
+			return fmt.Errorf("context name cannot contain control characters")
+		}
+	}
+
+	if strings.ContainsAny(name, windowsInvalidChars) {
+		return fmt.Errorf("context name cannot contain any of %s", windowsInvalidChars)
+	}
+
+	if strings.HasSuffix(name, " ") || strings.HasSuffix(name, ".") {
+		return fmt.Errorf("context name cannot end with a space or '.'")
+	}
+
+	base := strings.ToUpper(strings.SplitN(name, ".", 2)[0])
+	if windowsReservedNames[base] {
+		return fmt.Errorf("context name '%s' is reserved on Windows", name)
+	}
+
+	maxLength := m.config.Names.EffectiveMaxLength()
+	if utf8.RuneCountInString(name) > maxLength {
+		return fmt.Errorf("context name is longer than %d characters", maxLength)
+	}
+
+	return nil
+}
+
+// checkCaseConflict rejects names that differ only by case from an existing
+// context (other than except), since they collide on case-insensitive filesystems
+func (m *Manager) checkCaseConflict(name, except string) error {
+	contexts, err := m.ListContexts()
+	if err != nil {
+		return err
+	}
+
+	for _, ctx := range contexts {
+		if ctx.Name != name && ctx.Name != except && strings.EqualFold(ctx.Name, name) {
+			return fmt.Errorf("context name '%s' differs only by case from existing context '%s'", name, ctx.Name)
+		}
+	}
+
+	return nil
+}
+
+// resolveContextName maps a requested name onto an existing context name when
+// case-insensitive lookup is enabled; exact matches always win
+func (m *Manager) resolveContextName(name string) (string, error) {
+	if !m.config.Names.CaseInsensitive {
+		return name, nil
+	}
+
+	contexts, err := m.ListContexts()
+	if err != nil {
+		return "", err
+	}
+
+	var matches []string
+	for _, ctx := range contexts {
+		if ctx.Name == name {
+			return name, nil
+		}
+		if strings.EqualFold(ctx.Name, name) && !util.Contains(matches, ctx.Name) {
+			matches = append(matches, ctx.Name)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return name, nil
+	case 1:
+		return matches[0], nil
+	default:
+		return "", fmt.Errorf("context name '%s' is ambiguous: matches %s", name, strings.Join(matches, ", "))
+	}
+}
+
+// notFound builds a NotFoundError suggesting existing contexts close to name
+func (m *Manager) notFound(name string) error {
+	if _, ok := m.findArchivedContext(name); ok {
+		return &NotFoundError{Name: name, Archived: true}
+	}
+
+	var names []string
+	if contexts, err := m.ListContexts(); err == nil {
+		for _, ctx := range contexts {
+			names = append(names, ctx.Name)
+		}
+	}
+
+	suggestions := suggest.Closest(name, names)
+	if len(suggestions) > maxSuggestions {
+		suggestions = suggestions[:maxSuggestions]
+	}
+
+	return &NotFoundError{Name: name, Suggestions: suggestions}
+}
+
+// Slugify turns arbitrary input into a portable context name: lowercase letters,
+// digits, '-', '_' and '.', with other runs of characters collapsed to '-'
+func Slugify(name string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(name) {
+		switch {
+		case r < utf8.RuneSelf && (unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || r == '.'):
+			b.WriteRune(r)
+			dash = false
+		case !dash:
+			b.WriteRune('-')
+			dash = true
+		}
+	}
+
+	return strings.Trim(b.String(), "-.")
+}
@@ -0,0 +1,40 @@
+package context
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+)
+
+// OfflineEnvVar enables offline mode when set to a true value
+const OfflineEnvVar = "OCCTX_OFFLINE"
+
+// ErrOffline is returned by operations that need the network in offline mode
+var ErrOffline = errors.New("occtx is in offline mode")
+
+// SetOffline enables or disables offline mode
+func (m *Manager) SetOffline(offline bool) {
+	m.offline = offline
+}
+
+// IsOffline reports whether network access is disabled
+func (m *Manager) IsOffline() bool {
+	return m.offline
+}
+
+// CheckOnline returns an error naming the operation if offline mode is on.
+// Every network access goes through it, so offline mode fails the same way
+// whether or not the network is reachable.
+func (m *Manager) CheckOnline(operation string) error {
+	if m.offline {
+		return fmt.Errorf("cannot %s: %w (unset --offline / %s to allow network access)", operation, ErrOffline, OfflineEnvVar)
+	}
+	return nil
+}
+
+// offlineFromEnv reports whether the offline environment variable is set to true
+func offlineFromEnv() bool {
+	value, err := strconv.ParseBool(os.Getenv(OfflineEnvVar))
+	return err == nil && value
+}
@@ -0,0 +1,276 @@
+package context
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hungthai1401/occtx/internal/util"
+)
+
+// opencodeSchemaURL is the $schema of configs built from opencode exports
+const opencodeSchemaURL = "https://opencode.ai/config.json"
+
+// opencodeConfigNames are the config files of an opencode config directory, by precedence
+var opencodeConfigNames = []string{"opencode.jsonc", "opencode.json", "config.json"}
+
+// OpencodeImport is the config extracted from something opencode wrote
+type OpencodeImport struct {
+	Data  []byte   // The config as JSON
+	Kind  string   // What was read, e.g. "opencode session export"
+	Notes []string // What was mapped or left out
+}
+
+// ReadOpencodeExport extracts the config portion of opencode's own files:
+//   - a config directory such as ~/.config/opencode: its opencode.json(c),
+//     with markdown agents and commands from agent/ and command/ inlined
+//   - a config file, JSON or JSONC, possibly wrapped in a bundle as "config"
+//   - a session export from 'opencode export': the model of its last
+//     assistant message
+//
+// Credentials (auth.json), sessions and message content are never copied.
+func ReadOpencodeExport(path string) (*OpencodeImport, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if info.IsDir() {
+		return readOpencodeConfigDir(path)
+	}
+
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	data, err := util.ParseJSONC(raw)
+	if err != nil {
+		return nil, fmt.Errorf("%s is not an opencode export: %v", path, err)
+	}
+
+	result := &OpencodeImport{}
+	switch {
+	case isOpencodeSession(data):
+		result.Kind = "opencode session export"
+		data, result.Notes, err = configFromSession(data)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", path, err)
+		}
+	case isConfigBundle(data):
+		result.Kind = "opencode settings bundle"
+		data = data["config"].(map[string]interface{})
+		result.Notes = append(result.Notes, "took the \"config\" section of the bundle")
+	default:
+		result.Kind = "opencode config file"
+	}
+	return result.encode(data)
+}
+
+// encode stores data as the import's JSON
+func (r *OpencodeImport) encode(data map[string]interface{}) (*OpencodeImport, error) {
+	encoded, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	r.Data = encoded
+	return r, nil
+}
+
+// isOpencodeSession reports whether data is the output of 'opencode export'
+func isOpencodeSession(data map[string]interface{}) bool {
+	_, hasInfo := data["info"].(map[string]interface{})
+	_, hasMessages := data["messages"].([]interface{})
+	return hasInfo && hasMessages
+}
+
+// isConfigBundle reports whether data wraps a config under "config", with
+// nothing else that looks like a config at the top level
+func isConfigBundle(data map[string]interface{}) bool {
+	if _, ok := data["config"].(map[string]interface{}); !ok {
+		return false
+	}
+	for key := range data {
+		if _, known := opencodeKeyKinds[key]; known {
+			return false
+		}
+	}
+	return true
+}
+
+// configFromSession maps a session export to a config: the provider and
+// model the session last answered with
+func configFromSession(session map[string]interface{}) (map[string]interface{}, []string, error) {
+	messages := session["messages"].([]interface{})
+	model, from := "", 0
+	for i := len(messages) - 1; i >= 0 && model == ""; i-- {
+		message, _ := messages[i].(map[string]interface{})
+		info, _ := message["info"].(map[string]interface{})
+		if info == nil {
+			continue
+		}
+		provider, _ := info["providerID"].(string)
+		id, _ := info["modelID"].(string)
+		if nested, ok := info["model"].(map[string]interface{}); ok {
+			provider, _ = nested["providerID"].(string)
+			id, _ = nested["modelID"].(string)
+		}
+		if provider != "" && id != "" {
+			model, from = provider+"/"+id, i+1
+		}
+	}
+	if model == "" {
+		return nil, nil, fmt.Errorf("the session names no model")
+	}
+
+	notes := []string{fmt.Sprintf("model %s from message %d of %d", model, from, len(messages))}
+	if info, ok := session["info"].(map[string]interface{}); ok {
+		if title, _ := info["title"].(string); title != "" {
+			notes = append(notes, fmt.Sprintf("session '%s'; messages are not imported", title))
+		}
+	}
+	return map[string]interface{}{"$schema": opencodeSchemaURL, "model": model}, notes, nil
+}
+
+// readOpencodeConfigDir reads an opencode config directory
+func readOpencodeConfigDir(dir string) (*OpencodeImport, error) {
+	result := &OpencodeImport{Kind: "opencode config directory"}
+
+	data := map[string]interface{}{}
+	for _, name := range opencodeConfigNames {
+		path := filepath.Join(dir, name)
+		raw, err := os.ReadFile(path)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if data, err = util.ParseJSONC(raw); err != nil {
+			return nil, fmt.Errorf("invalid opencode config %s: %v", path, err)
+		}
+		result.Notes = append(result.Notes, "config from "+name)
+		break
+	}
+
+	sections := []struct {
+		key   string
+		dirs  []string
+		body  string // Field the markdown body maps to
+		label string
+	}{
+		{"agent", []string{"agent", "agents"}, "prompt", "agent"},
+		{"command", []string{"command", "commands"}, "template", "command"},
+	}
+	for _, section := range sections {
+		for _, sub := range section.dirs {
+			files, _ := filepath.Glob(filepath.Join(dir, sub, "*.md"))
+			sort.Strings(files)
+			for _, file := range files {
+				name := strings.TrimSuffix(filepath.Base(file), ".md")
+				existing, ok := data[section.key].(map[string]interface{})
+				if !ok && data[section.key] != nil {
+					return nil, fmt.Errorf("invalid opencode config in %s: '%s' must be an object", dir, section.key)
+				}
+				if _, defined := existing[name]; defined {
+					result.Notes = append(result.Notes, fmt.Sprintf("kept %s '%s' from the config over %s", section.label, name, filepath.Join(sub, filepath.Base(file))))
+					continue
+				}
+
+				raw, err := os.ReadFile(file)
+				if err != nil {
+					return nil, err
+				}
+				fields, body, err := parseFrontmatter(string(raw))
+				if err != nil {
+					return nil, fmt.Errorf("%s: %v", file, err)
+				}
+				if body != "" {
+					fields[section.body] = body
+				}
+
+				if existing == nil {
+					existing = map[string]interface{}{}
+					data[section.key] = existing
+				}
+				existing[name] = fields
+				result.Notes = append(result.Notes, fmt.Sprintf("%s '%s' from %s", section.label, name, filepath.Join(sub, filepath.Base(file))))
+			}
+		}
+	}
+
+	if len(result.Notes) == 0 {
+		return nil, fmt.Errorf("%s holds no opencode config (%s) or markdown agents and commands", dir, strings.Join(opencodeConfigNames, ", "))
+	}
+	if _, err := os.Stat(filepath.Join(dir, "auth.json")); err == nil {
+		result.Notes = append(result.Notes, "left out auth.json: credentials stay on this machine")
+	}
+	return result.encode(data)
+}
+
+// parseFrontmatter splits a markdown file into its YAML frontmatter and
+// body. The frontmatter of opencode agents and commands is flat: scalars,
+// and maps of scalars one level deep such as "tools".
+func parseFrontmatter(text string) (map[string]interface{}, string, error) {
+	fields := map[string]interface{}{}
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	if !strings.HasPrefix(text, "---\n") {
+		return fields, strings.TrimSpace(text), nil
+	}
+	header, body, found := strings.Cut(strings.TrimPrefix(text, "---\n"), "\n---")
+	if !found {
+		return nil, "", fmt.Errorf("unterminated frontmatter")
+	}
+	body = strings.TrimPrefix(body, "\n")
+
+	var nested map[string]interface{}
+	for i, line := range strings.Split(header, "\n") {
+		if strings.TrimSpace(line) == "" || strings.HasPrefix(strings.TrimSpace(line), "#") {
+			continue
+		}
+		key, value, ok := strings.Cut(strings.TrimSpace(line), ":")
+		if !ok {
+			return nil, "", fmt.Errorf("frontmatter line %d: expected key: value", i+1)
+		}
+		key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+
+		if line[0] == ' ' || line[0] == '\t' {
+			if nested == nil {
+				return nil, "", fmt.Errorf("frontmatter line %d: unexpected indentation", i+1)
+			}
+			nested[key] = frontmatterScalar(value)
+			continue
+		}
+		if value == "" {
+			nested = map[string]interface{}{}
+			fields[key] = nested
+			continue
+		}
+		nested = nil
+		fields[key] = frontmatterScalar(value)
+	}
+	return fields, strings.TrimSpace(body), nil
+}
+
+// frontmatterScalar decodes a YAML scalar: booleans, numbers and strings,
+// quoted or not
+func frontmatterScalar(value string) interface{} {
+	if unquoted, err := strconv.Unquote(value); err == nil && strings.HasPrefix(value, `"`) {
+		return unquoted
+	}
+	if len(value) >= 2 && value[0] == '\'' && value[len(value)-1] == '\'' {
+		return strings.ReplaceAll(value[1:len(value)-1], "''", "'")
+	}
+	switch value {
+	case "true":
+		return true
+	case "false":
+		return false
+	}
+	if number, err := strconv.ParseFloat(value, 64); err == nil {
+		return number
+	}
+	return value
+}
@@ -0,0 +1,43 @@
+//go:build !windows
+
+package context
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"syscall"
+)
+
+// checkFileOwner returns an error unless the current user owns the file
+func checkFileOwner(path string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return nil
+	}
+	if uid := os.Getuid(); int(stat.Uid) != uid {
+		return fmt.Errorf("%s is owned by uid %d, not the current user (uid %d)", filepath.Base(path), stat.Uid, uid)
+	}
+	return nil
+}
+
+// copyFileOwner gives path the owner and group recorded in info
+func copyFileOwner(info os.FileInfo, path string) error {
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return nil
+	}
+	current, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	if now, ok := current.Sys().(*syscall.Stat_t); ok && now.Uid == stat.Uid && now.Gid == stat.Gid {
+		return nil
+	}
+	return os.Chown(path, int(stat.Uid), int(stat.Gid))
+}
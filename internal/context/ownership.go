@@ -0,0 +1,108 @@
+package context
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// ErrForeignActiveConfig is returned when switching would overwrite an active
+// config that occtx didn't write
+var ErrForeignActiveConfig = errors.New("active config was not written by occtx")
+
+// ForeignConfigError names the active config a switch refused to overwrite
+type ForeignConfigError struct {
+	Path string
+}
+
+func (e *ForeignConfigError) Error() string {
+	return fmt.Sprintf("%s: %v (edited by hand or by another tool). Save it with --save-as <name>, or overwrite it with --force", e.Path, ErrForeignActiveConfig)
+}
+
+func (e *ForeignConfigError) Unwrap() error {
+	return ErrForeignActiveConfig
+}
+
+// ActiveIsForeign reports whether the active config exists and matches
+// neither what occtx last wrote nor any known context
+func (m *Manager) ActiveIsForeign() (bool, error) {
+	data, err := os.ReadFile(m.paths.GetActiveConfigPath(m.useProject))
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	sum := checksum(data)
+
+	state, err := m.loadState()
+	if err != nil {
+		return false, err
+	}
+	if state.Active != nil && state.Active.Checksum == sum {
+		return false, nil
+	}
+
+	manifest, err := m.loadChecksums()
+	if err != nil {
+		return false, err
+	}
+	for _, known := range manifest.Files {
+		if known == sum {
+			return false, nil
+		}
+	}
+
+	// Compare content rather than bytes, since saving a context reformats it.
+	// Contexts are only parsed when the cheap checks fail.
+	active, err := canonicalChecksum(".jsonc", data)
+	if err != nil {
+		return true, nil
+	}
+	contexts, err := m.ListContexts()
+	if err != nil {
+		return false, err
+	}
+	for _, ctx := range contexts {
+		content, err := os.ReadFile(ctx.FilePath)
+		if err != nil {
+			continue
+		}
+		if sum, err := canonicalChecksum(ctx.FilePath, content); err == nil && sum == active {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+// canonicalChecksum hashes the parsed content of a config, ignoring
+// formatting, key order and JSONC comments
+func canonicalChecksum(path string, data []byte) (string, error) {
+	parsed, err := parseContextData(path, data)
+	if err != nil {
+		return "", err
+	}
+	canonical, err := json.Marshal(parsed)
+	if err != nil {
+		return "", err
+	}
+	return checksum(canonical), nil
+}
+
+// checkActiveOwnership refuses to overwrite a foreign active config unless force is set
+func (m *Manager) checkActiveOwnership() error {
+	if m.force {
+		return nil
+	}
+
+	foreign, err := m.ActiveIsForeign()
+	if err != nil {
+		return err
+	}
+	if foreign {
+		return &ForeignConfigError{Path: m.paths.GetActiveConfigPath(m.useProject)}
+	}
+	return nil
+}
@@ -0,0 +1,104 @@
+package context
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+)
+
+// privateFileMode and privateDirMode are used for backups and session files,
+// which stay private to the user whatever the permissions config says
+const (
+	privateFileMode os.FileMode = 0600
+	privateDirMode  os.FileMode = 0700
+)
+
+// fileMode is the mode of the files occtx writes for itself, before the umask
+func (m *Manager) fileMode() os.FileMode {
+	return m.config.Permissions.FileMode()
+}
+
+// dirMode is the mode of the directories occtx creates, before the umask
+func (m *Manager) dirMode() os.FileMode {
+	return m.config.Permissions.DirMode()
+}
+
+// PermissionDeviation is a file or directory occtx manages whose mode
+// differs from the one occtx would give it now
+type PermissionDeviation struct {
+	Path string
+	Mode os.FileMode // Actual
+	Want os.FileMode // The configured mode with the umask applied
+}
+
+// PermissionReport describes the modes occtx uses and where they aren't met
+type PermissionReport struct {
+	Files      os.FileMode // Configured file mode, before the umask
+	Dirs       os.FileMode // Configured directory mode, before the umask
+	Umask      os.FileMode
+	Deviations []PermissionDeviation
+}
+
+// CheckPermissions compares the modes of the settings directory and the
+// files occtx keeps next to its config with the permissions config and the
+// umask. The active config is left out: switches keep its mode. Windows has
+// no Unix modes, so nothing deviates there.
+func (m *Manager) CheckPermissions() (*PermissionReport, error) {
+	report := &PermissionReport{Files: m.fileMode(), Dirs: m.dirMode(), Umask: processUmask()}
+	if runtime.GOOS == "windows" {
+		return report, nil
+	}
+
+	want := func(mode os.FileMode) os.FileMode { return mode &^ report.Umask }
+	check := func(path string, info fs.FileInfo, mode os.FileMode) {
+		if info.Mode().Perm() != want(mode) {
+			report.Deviations = append(report.Deviations, PermissionDeviation{Path: path, Mode: info.Mode().Perm(), Want: want(mode)})
+		}
+	}
+
+	backupDir := m.paths.GetBackupDir(m.useProject)
+	err := filepath.WalkDir(m.paths.GetContextsDir(m.useProject), func(path string, entry fs.DirEntry, err error) error {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if entry.Type()&fs.ModeSymlink != 0 {
+			return nil
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+
+		private := path == backupDir || filepath.Dir(path) == backupDir
+		switch {
+		case entry.IsDir() && private:
+			check(path, info, privateDirMode)
+		case entry.IsDir():
+			check(path, info, m.dirMode())
+		case private:
+			check(path, info, privateFileMode)
+		default:
+			check(path, info, m.fileMode())
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, path := range []string{m.paths.GetSelfStatsPath(), m.paths.GetModelsCachePath()} {
+		if info, err := os.Stat(path); err == nil {
+			check(path, info, m.fileMode())
+		}
+	}
+
+	sort.Slice(report.Deviations, func(i, j int) bool {
+		return report.Deviations[i].Path < report.Deviations[j].Path
+	})
+	return report, nil
+}
@@ -0,0 +1,246 @@
+package context
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/hungthai1401/occtx/internal/config"
+)
+
+// PlanAction says what an operation would do to a file
+type PlanAction string
+
+const (
+	PlanCreate PlanAction = "create"
+	PlanModify PlanAction = "modify"
+	PlanDelete PlanAction = "delete" // Context files are moved to the trash, others removed
+)
+
+// PlannedFile is a config file an operation would write or remove
+type PlannedFile struct {
+	Path    string          `json:"path"`
+	Action  PlanAction      `json:"action"`
+	Changes []ContextChange `json:"changes,omitempty"` // Content changes, from the current file to the planned one
+}
+
+// PlannedHook is a configured hook an operation would run
+type PlannedHook struct {
+	Name    string   `json:"name"`
+	On      string   `json:"on"`
+	Run     []string `json:"run"`
+	Timeout string   `json:"timeout"`
+}
+
+// Plan describes what a mutating operation would do without doing it. It
+// lists context files, the active config and the hooks that would run;
+// occtx's own bookkeeping (state, metadata, checksums) is left out.
+type Plan struct {
+	Operation string        `json:"operation"`
+	Context   string        `json:"context"`
+	Files     []PlannedFile `json:"files"`
+	Hooks     []PlannedHook `json:"hooks,omitempty"`
+	Warnings  []string      `json:"warnings,omitempty"`
+}
+
+// PlanSwitch runs the checks of SwitchToContext and returns the change it
+// would make to the active config. The cost guard is not consulted, since it
+// may need to ask.
+func (m *Manager) PlanSwitch(name string) (*Plan, error) {
+	ctx, err := m.GetContext(name)
+	if err != nil {
+		return nil, err
+	}
+
+	plan := &Plan{Operation: "switch", Context: ctx.Name}
+	var path string
+	var data []byte
+	if err := m.planChecks(plan, func() error {
+		if err := m.checkApproved(ctx); err != nil {
+			return err
+		}
+		if path, data, err = m.activeConfigFor(ctx); err != nil {
+			return err
+		}
+		if err := m.checkActiveOwnership(); err != nil {
+			return err
+		}
+		if err := m.enforcePolicy("switch", ctx.Name, ctx.Data); err != nil {
+			return err
+		}
+		return m.checkModels(ctx)
+	}); err != nil {
+		return nil, err
+	}
+
+	if file, err := m.planActiveConfig(path, data, ctx.Data); err != nil {
+		return nil, err
+	} else if file != nil {
+		plan.Files = append(plan.Files, *file)
+	}
+	plan.Hooks = m.planHooks(config.HookPreSwitch, config.HookPostSwitch)
+	return plan, nil
+}
+
+// PlanImport runs the checks of ImportContextWithOptions and returns the
+// files the import would write
+func (m *Manager) PlanImport(name string, data []byte, opts ImportOptions) (*Plan, error) {
+	plan := &Plan{Operation: "import", Context: name}
+
+	jsonData, existing, warnings, err := m.prepareImport(name, data, opts)
+	plan.Warnings = append(plan.Warnings, warnings...)
+	if err != nil {
+		return plan, err
+	}
+
+	if existing != nil {
+		plan.Operation = "merge"
+		plan.Context = existing.Name
+		if err := m.planChecks(plan, func() error { return m.enforcePolicy("merge", existing.Name, jsonData) }); err != nil {
+			return plan, err
+		}
+		plan.Files = append(plan.Files, PlannedFile{
+			Path:    existing.FilePath,
+			Action:  PlanModify,
+			Changes: diffContextData(existing.Data, jsonData),
+		})
+		return plan, nil
+	}
+
+	if err := m.checkNewContext(name); err != nil {
+		return plan, err
+	}
+	if err := m.planChecks(plan, func() error { return m.enforcePolicy("create", name, jsonData) }); err != nil {
+		return plan, err
+	}
+
+	contextsDir := m.paths.GetContextsDir(m.useProject)
+	path := filepath.Join(contextsDir, name+opts.Format.FileExtension())
+	for _, f := range GetAllFormats() {
+		existingPath := filepath.Join(contextsDir, name+f.FileExtension())
+		if _, err := os.Stat(existingPath); err == nil {
+			plan.Files = append(plan.Files, PlannedFile{Path: existingPath, Action: PlanDelete})
+		}
+	}
+	plan.Files = append(plan.Files, PlannedFile{
+		Path:    path,
+		Action:  PlanCreate,
+		Changes: diffContextData(map[string]interface{}{}, jsonData),
+	})
+	return plan, nil
+}
+
+// PlanDelete runs the checks of DeleteContextSwitchingTo and returns the
+// files it would change: the context file and, when the context is current,
+// the active config it switches away from
+func (m *Manager) PlanDelete(name, fallback string) (*Plan, error) {
+	ctx, err := m.GetContext(name)
+	if err != nil {
+		return nil, err
+	}
+	if err := ctx.CheckModifiable("delete"); err != nil {
+		return nil, err
+	}
+
+	state, err := m.loadState()
+	if err != nil {
+		return nil, err
+	}
+	if state.Default == ctx.Name {
+		return nil, fmt.Errorf("cannot delete default context '%s'. Run 'occtx default unset' first", ctx.Name)
+	}
+
+	plan := &Plan{Operation: "delete", Context: ctx.Name}
+	if state.Current == ctx.Name {
+		switch fallback {
+		case "":
+			return nil, fmt.Errorf("cannot delete current context '%s'. Switch to another context first, or use --switch-to", ctx.Name)
+		case FallbackNone:
+			activeConfigPath := m.paths.GetActiveConfigPath(m.useProject)
+			if _, err := os.Stat(activeConfigPath); err == nil {
+				plan.Files = append(plan.Files, PlannedFile{Path: activeConfigPath, Action: PlanDelete})
+			}
+		default:
+			if fallback == FallbackPrevious {
+				if state.Previous == "" || state.Previous == ctx.Name {
+					return nil, fmt.Errorf("no previous context to switch to")
+				}
+				fallback = state.Previous
+			}
+			switchPlan, err := m.PlanSwitch(fallback)
+			if err != nil {
+				return nil, err
+			}
+			if switchPlan.Context == ctx.Name {
+				return nil, fmt.Errorf("cannot switch to '%s': it is the context being deleted", ctx.Name)
+			}
+			plan.Files = append(plan.Files, switchPlan.Files...)
+			plan.Hooks = append(plan.Hooks, switchPlan.Hooks...)
+			plan.Warnings = append(plan.Warnings, switchPlan.Warnings...)
+		}
+	}
+
+	plan.Files = append(plan.Files, PlannedFile{Path: ctx.FilePath, Action: PlanDelete})
+	return plan, nil
+}
+
+// planActiveConfig describes writing data (parsed as content) to the active
+// config at activeConfigPath, or returns nil when the file already holds
+// exactly that. Replacing the active config of another name reads as a
+// change from it.
+func (m *Manager) planActiveConfig(activeConfigPath string, data []byte, content map[string]interface{}) (*PlannedFile, error) {
+	replaced := m.paths.GetActiveConfigPath(m.useProject)
+	current, err := os.ReadFile(replaced)
+	if os.IsNotExist(err) {
+		return &PlannedFile{
+			Path:    activeConfigPath,
+			Action:  PlanCreate,
+			Changes: diffContextData(map[string]interface{}{}, content),
+		}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if replaced == activeConfigPath && bytes.Equal(current, data) {
+		return nil, nil
+	}
+
+	file := &PlannedFile{Path: activeConfigPath, Action: PlanModify}
+	// An active config that doesn't parse is replaced wholesale
+	if currentContent, err := parseContextData(replaced, current); err == nil {
+		file.Changes = diffContextData(currentContent, content)
+	}
+	return file, nil
+}
+
+// planHooks lists the hooks for events in the order they would run
+func (m *Manager) planHooks(events ...string) []PlannedHook {
+	var planned []PlannedHook
+	for _, event := range events {
+		for _, hook := range m.Hooks() {
+			if hook.On != event {
+				continue
+			}
+			planned = append(planned, PlannedHook{
+				Name:    hook.Name,
+				On:      hook.On,
+				Run:     hook.Run,
+				Timeout: hook.EffectiveTimeout().String(),
+			})
+		}
+	}
+	return planned
+}
+
+// planChecks runs checks, collecting the warnings they emit into the plan
+// instead of printing them
+func (m *Manager) planChecks(plan *Plan, checks func() error) error {
+	warn := m.warn
+	m.warn = func(message string) {
+		plan.Warnings = append(plan.Warnings, message)
+	}
+	defer func() { m.warn = warn }()
+
+	return checks()
+}
@@ -0,0 +1,223 @@
+package context
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/hungthai1401/occtx/internal/config"
+)
+
+// Policy rule types
+const (
+	RuleForbidPlaintextSecrets = "forbid_plaintext_secrets" // No literal API keys, tokens or passwords
+	RuleProviderAllowlist      = "provider_allowlist"       // Only the listed providers may be configured or used
+	RuleMaxTimeout             = "max_timeout"              // No "timeout" value above Max
+	RuleForbiddenKeys          = "forbidden_keys"           // None of the key paths may be set
+)
+
+// PolicyFile is an organization policy evaluated on create, import and switch
+type PolicyFile struct {
+	Rules []PolicyRule `json:"rules"`
+}
+
+// PolicyRule is one guardrail. Only the fields used by its type are read.
+type PolicyRule struct {
+	Type      string   `json:"type"`
+	Mode      string   `json:"mode,omitempty"`      // warn or deny (default)
+	Providers []string `json:"providers,omitempty"` // provider_allowlist
+	Max       float64  `json:"max,omitempty"`       // max_timeout
+	Keys      []string `json:"keys,omitempty"`      // forbidden_keys, e.g. "provider.*.options.baseURL"
+}
+
+// PolicyViolationError is returned when a deny rule fails
+type PolicyViolationError struct {
+	Operation  string
+	Context    string
+	Violations []string
+}
+
+func (e *PolicyViolationError) Error() string {
+	return fmt.Sprintf("policy denies %s of '%s':\n  %s", e.Operation, e.Context, strings.Join(e.Violations, "\n  "))
+}
+
+// LoadPolicy reads the policy file of the manager's scope, returning nil if there is none
+func (m *Manager) LoadPolicy() (*PolicyFile, error) {
+	policyPath := m.paths.GetPolicyFilePath(m.useProject)
+	data, err := os.ReadFile(policyPath)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var policy PolicyFile
+	if err := json.Unmarshal(data, &policy); err != nil {
+		return nil, fmt.Errorf("invalid policy file %s: %v", policyPath, err)
+	}
+	for i, rule := range policy.Rules {
+		if err := rule.validate(); err != nil {
+			return nil, fmt.Errorf("invalid policy file %s: rule %d: %v", policyPath, i+1, err)
+		}
+	}
+	m.logf(VerbosityVerbose, "policy: %d rule(s) from %s", len(policy.Rules), policyPath)
+
+	return &policy, nil
+}
+
+// Check evaluates the policy against context data, returning the
+// findings of warn rules and of deny rules separately
+func (p *PolicyFile) Check(data map[string]interface{}) (warnings, violations []string) {
+	for _, rule := range p.Rules {
+		findings := rule.check(data)
+		if mode, _ := ParsePolicy(rule.Mode); mode == PolicyWarn {
+			warnings = append(warnings, findings...)
+		} else {
+			violations = append(violations, findings...)
+		}
+	}
+	return warnings, violations
+}
+
+// enforcePolicy applies the scope's policy to an operation on a context:
+// warn findings are reported, deny findings abort the operation
+func (m *Manager) enforcePolicy(operation, name string, data map[string]interface{}) error {
+	policy, err := m.LoadPolicy()
+	if err != nil || policy == nil {
+		return err
+	}
+	return m.applyPolicy(policy, operation, name, data)
+}
+
+// applyPolicy reports the warn findings of policy and fails on deny findings
+func (m *Manager) applyPolicy(policy *PolicyFile, operation, name string, data map[string]interface{}) error {
+	warnings, violations := policy.Check(data)
+	for _, warning := range warnings {
+		m.warnf("policy: %s", warning)
+	}
+	if len(violations) > 0 {
+		m.emit(Event{Kind: config.EventPolicyViolation, Context: name, Operation: operation, Violations: violations})
+		return &PolicyViolationError{Operation: operation, Context: name, Violations: violations}
+	}
+	return nil
+}
+
+// enforceContextPolicy is enforcePolicy for a context that may not be
+// decoded yet; it is only decoded when the scope has a policy
+func (m *Manager) enforceContextPolicy(operation string, ctx *Context) error {
+	policy, err := m.LoadPolicy()
+	if err != nil || policy == nil {
+		return err
+	}
+	data, err := ctx.parsed()
+	if err != nil {
+		return err
+	}
+	return m.applyPolicy(policy, operation, ctx.Name, data)
+}
+
+// validate checks that the rule is well-formed
+func (r PolicyRule) validate() error {
+	if r.Mode == "" {
+		r.Mode = "deny"
+	}
+	if mode, err := ParsePolicy(r.Mode); err != nil || mode == PolicyOff {
+		return fmt.Errorf("invalid mode '%s' (expected warn or deny)", r.Mode)
+	}
+
+	switch r.Type {
+	case RuleForbidPlaintextSecrets:
+	case RuleProviderAllowlist:
+		if len(r.Providers) == 0 {
+			return fmt.Errorf("%s needs a non-empty \"providers\" list", r.Type)
+		}
+	case RuleMaxTimeout:
+		if r.Max <= 0 {
+			return fmt.Errorf("%s needs a positive \"max\"", r.Type)
+		}
+	case RuleForbiddenKeys:
+		if len(r.Keys) == 0 {
+			return fmt.Errorf("%s needs a non-empty \"keys\" list", r.Type)
+		}
+		for _, key := range r.Keys {
+			if _, err := ParseKeyPath(key); err != nil {
+				return err
+			}
+		}
+	default:
+		return fmt.Errorf("unknown rule type '%s'", r.Type)
+	}
+	return nil
+}
+
+// check returns the rule's findings for data
+func (r PolicyRule) check(data map[string]interface{}) []string {
+	switch r.Type {
+	case RuleForbidPlaintextSecrets:
+		return findSecrets(data)
+	case RuleProviderAllowlist:
+		return r.checkProviders(data)
+	case RuleMaxTimeout:
+		return r.checkTimeouts(data, "")
+	case RuleForbiddenKeys:
+		var findings []string
+		for _, key := range r.Keys {
+			path, _ := ParseKeyPath(key)
+			if path.Matches(data) {
+				findings = append(findings, fmt.Sprintf("forbidden key '%s' is set", key))
+			}
+		}
+		return findings
+	}
+	return nil
+}
+
+// checkProviders reports configured or referenced providers outside the allowlist
+func (r PolicyRule) checkProviders(data map[string]interface{}) []string {
+	allowed := make(map[string]bool, len(r.Providers))
+	for _, provider := range r.Providers {
+		allowed[provider] = true
+	}
+
+	var findings []string
+	if providers, ok := data["provider"].(map[string]interface{}); ok {
+		for _, provider := range sortedKeys(providers) {
+			if !allowed[provider] {
+				findings = append(findings, fmt.Sprintf("provider '%s' is not in the allowlist", provider))
+			}
+		}
+	}
+	for _, key := range []string{"model", "small_model"} {
+		model, _ := data[key].(string)
+		if provider, _, ok := strings.Cut(model, "/"); ok && !allowed[provider] {
+			findings = append(findings, fmt.Sprintf("'%s' uses provider '%s', which is not in the allowlist", key, provider))
+		}
+	}
+	return findings
+}
+
+// checkTimeouts reports numeric "timeout" values above the maximum anywhere in node
+func (r PolicyRule) checkTimeouts(node interface{}, path string) []string {
+	var findings []string
+	switch v := node.(type) {
+	case map[string]interface{}:
+		for _, key := range sortedKeys(v) {
+			childPath := key
+			if path != "" {
+				childPath = path + "." + key
+			}
+			if timeout, ok := v[key].(float64); ok && strings.EqualFold(key, "timeout") && timeout > r.Max {
+				findings = append(findings, fmt.Sprintf("'%s' is %v, above the maximum of %v", childPath, timeout, r.Max))
+				continue
+			}
+			findings = append(findings, r.checkTimeouts(v[key], childPath)...)
+		}
+	case []interface{}:
+		for i, item := range v {
+			findings = append(findings, r.checkTimeouts(item, fmt.Sprintf("%s.%d", path, i))...)
+		}
+	}
+	return findings
+}
@@ -0,0 +1,96 @@
+package context
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// RecordListing remembers the order of a numbered listing, so a later
+// 'occtx 3' switches to the third context shown. Nothing is recorded in
+// read-only mode, and the state is not saved again for the same listing.
+func (m *Manager) RecordListing(names []string) error {
+	if m.IsReadOnly() {
+		return nil
+	}
+	if state, err := m.loadState(); err == nil && equalStrings(state.Listing, names) {
+		return nil
+	}
+	return m.updateState(func(state *State) error {
+		if equalStrings(state.Listing, names) {
+			return errStateUnchanged
+		}
+		state.Listing = append([]string(nil), names...)
+		return nil
+	})
+}
+
+// ResolvePosition turns a 1-based position such as "3" into the name of the
+// context at that position of the last numbered listing, or of the contexts
+// most recently used first if nothing was listed yet. Other arguments, and
+// a number that is itself the name of a context, are returned unchanged.
+func (m *Manager) ResolvePosition(arg string) (string, error) {
+	position, err := strconv.Atoi(arg)
+	if err != nil || strconv.Itoa(position) != arg {
+		return arg, nil
+	}
+	// A context called exactly so wins, even if it doesn't load
+	contexts, err := m.ListContexts()
+	if err != nil {
+		return "", err
+	}
+	for _, ctx := range contexts {
+		if ctx.Name == arg {
+			return arg, nil
+		}
+	}
+
+	state, err := m.loadState()
+	if err != nil {
+		return "", err
+	}
+	order, from := state.Listing, "the last listing"
+	if len(order) == 0 {
+		if order, err = m.recentOrder(); err != nil {
+			return "", err
+		}
+		from = "the recently used contexts"
+	}
+
+	if position < 1 || position > len(order) {
+		return "", fmt.Errorf("no context at position %d: %s has %d; run 'occtx ls' to number them", position, from, len(order))
+	}
+	name := order[position-1]
+	m.logf(VerbosityVerbose, "position %d of %s is '%s'", position, from, name)
+	return name, nil
+}
+
+// recentOrder lists the contexts most recently used first
+func (m *Manager) recentOrder() ([]string, error) {
+	contexts, err := m.ListContexts()
+	if err != nil {
+		return nil, err
+	}
+	usage, err := m.GetUsage()
+	if err != nil {
+		return nil, err
+	}
+	SortContexts(contexts, SortUsed, usage)
+
+	names := make([]string, len(contexts))
+	for i, ctx := range contexts {
+		names[i] = ctx.Name
+	}
+	return names, nil
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
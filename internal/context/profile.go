@@ -0,0 +1,59 @@
+package context
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// ProfileEnvVar selects a workspace profile when --profile isn't given
+const ProfileEnvVar = "OCCTX_PROFILE"
+
+// UseProfile switches the manager to the named workspace profile from the
+// occtx config file. An empty name keeps the current paths.
+func (m *Manager) UseProfile(name string) error {
+	if name == "" {
+		return nil
+	}
+
+	profile, ok := m.config.Profiles[name]
+	if !ok {
+		available := m.ProfileNames()
+		if len(available) == 0 {
+			return fmt.Errorf("profile '%s' not found: no profiles configured in %s", name, m.paths.ConfigFile)
+		}
+		return fmt.Errorf("profile '%s' not found (available: %s)", name, strings.Join(available, ", "))
+	}
+
+	if profile.ConfigDir == "" {
+		return fmt.Errorf("profile '%s' has no config_dir", name)
+	}
+
+	if err := m.paths.ApplyProfile(profile); err != nil {
+		return err
+	}
+
+	m.profile = name
+	return nil
+}
+
+// Profile returns the active workspace profile name, or "" for the default paths
+func (m *Manager) Profile() string {
+	return m.profile
+}
+
+// ProfileNames returns the configured profile names in sorted order
+func (m *Manager) ProfileNames() []string {
+	names := make([]string, 0, len(m.config.Profiles))
+	for name := range m.config.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// profileFromEnv returns the profile selected by the environment
+func profileFromEnv() string {
+	return strings.TrimSpace(os.Getenv(ProfileEnvVar))
+}
@@ -0,0 +1,109 @@
+package context
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/hungthai1401/occtx/internal/config"
+)
+
+// projectStateFiles are the files occtx keeps in a project settings dir for
+// itself; a project directory holding only these can be removed
+var projectStateFiles = map[string]bool{
+	config.StateFileName:                     true,
+	config.StateFileName + stateBackupSuffix: true,
+	config.CurrentMarkerFileName:             true,
+	config.MetadataFileName:                  true,
+	config.ChecksumsFileName:                 true,
+}
+
+// ProjectDir returns the project scope's opencode directory
+func (m *Manager) ProjectDir() string {
+	return m.paths.ProjectConfigDir
+}
+
+// ProjectDirBlockers lists what keeps the project scope's opencode directory
+// from being removed by CleanProjectDir: contexts, stashes, trash and any file
+// other than occtx's own state, relative to the directory. It returns
+// os.ErrNotExist if there is no directory.
+func (m *Manager) ProjectDirBlockers() ([]string, error) {
+	if !m.useProject {
+		return nil, fmt.Errorf("only the project scope has a directory to clean")
+	}
+	if m.worktree != "" {
+		return nil, fmt.Errorf("the project contexts belong to the main worktree %s; clean them up there", m.worktree)
+	}
+
+	dir := m.paths.ProjectConfigDir
+	if _, err := os.Stat(dir); err != nil {
+		return nil, err
+	}
+
+	var blockers []string
+	err := filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			return nil
+		}
+		if filepath.Dir(path) == m.paths.ProjectSettingsDir && projectStateFiles[entry.Name()] {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		blockers = append(blockers, filepath.ToSlash(rel))
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(blockers)
+	return blockers, nil
+}
+
+// CleanProjectDir removes the project scope's opencode directory when it
+// holds nothing but occtx state and empty directories. Files and directories
+// are removed one by one, deepest first, so anything that appears in the
+// meantime stops the removal instead of being lost.
+func (m *Manager) CleanProjectDir() error {
+	if err := m.CheckWritable("clean project directory"); err != nil {
+		return err
+	}
+
+	blockers, err := m.ProjectDirBlockers()
+	if err != nil {
+		return err
+	}
+	dir := m.paths.ProjectConfigDir
+	if len(blockers) > 0 {
+		return fmt.Errorf("%s holds more than occtx state: %s", dir, strings.Join(blockers, ", "))
+	}
+
+	var paths []string
+	err = filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		paths = append(paths, path)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	// Walk order lists parents first
+	for i := len(paths) - 1; i >= 0; i-- {
+		if err := os.Remove(paths[i]); err != nil {
+			return fmt.Errorf("failed to clean %s: %v", dir, err)
+		}
+		m.logf(VerbosityDebug, "removed %s", paths[i])
+	}
+	m.logf(VerbosityVerbose, "removed %s", dir)
+	return nil
+}
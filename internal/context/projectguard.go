@@ -0,0 +1,78 @@
+package context
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/hungthai1401/occtx/internal/config"
+)
+
+// checkProjectRoot refuses, or warns about, creating a project scope where
+// it makes no sense: in the home directory, a filesystem root, the global
+// config dir, or outside projects.allowed_roots when they are set. A project
+// scope that already exists is left alone, so creating its opencode
+// directory by hand is the way past the guard. It runs once per manager.
+func (m *Manager) checkProjectRoot() error {
+	if !m.useProject || m.rootChecked {
+		return nil
+	}
+	m.rootChecked = true
+
+	policy := PolicyReject
+	if m.config.Projects.Guard != "" {
+		var err error
+		if policy, err = ParsePolicy(m.config.Projects.Guard); err != nil {
+			return fmt.Errorf("projects.guard: %v", err)
+		}
+	}
+	if policy == PolicyOff {
+		return nil
+	}
+	if _, err := os.Stat(m.paths.ProjectConfigDir); err == nil {
+		return nil
+	}
+
+	root := filepath.Dir(m.paths.ProjectConfigDir)
+	reason := m.projectRootProblem(root)
+	if reason == "" {
+		return nil
+	}
+	if policy == PolicyWarn {
+		m.warnf("creating a project scope in %s, %s", root, reason)
+		return nil
+	}
+	return fmt.Errorf("%s is not a project directory: %s (run occtx in a project, or create %s to use it anyway)",
+		root, reason, m.paths.ProjectConfigDir)
+}
+
+// projectRootProblem says why root shouldn't hold a project scope, or
+// returns "" if it may
+func (m *Manager) projectRootProblem(root string) string {
+	if filepath.Dir(root) == root {
+		return "it is a filesystem root"
+	}
+	if home, err := os.UserHomeDir(); err == nil && samePath(root, home) {
+		return "it is the home directory"
+	}
+	if isWithin(m.paths.ProjectConfigDir, m.paths.GlobalConfigDir) {
+		return "it is inside the global config dir " + m.paths.GlobalConfigDir
+	}
+
+	allowed := m.config.Projects.AllowedRoots
+	if len(allowed) == 0 {
+		return ""
+	}
+	for _, dir := range allowed {
+		expanded, err := config.ExpandHome(dir)
+		if err == nil && isWithin(root, expanded) {
+			return ""
+		}
+	}
+	return "it is outside projects.allowed_roots"
+}
+
+// samePath reports whether a and b name the same directory, following symlinks
+func samePath(a, b string) bool {
+	return isWithin(a, b) && isWithin(b, a)
+}
@@ -0,0 +1,145 @@
+package context
+
+import (
+	gocontext "context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/hungthai1401/occtx/internal/config"
+)
+
+// scanWorkers bounds how many directories DiscoverProjects reads at once
+const scanWorkers = 8
+
+// skippedScanDirs are never descended into when discovering projects
+var skippedScanDirs = map[string]bool{
+	"node_modules": true,
+	"vendor":       true,
+}
+
+// ProjectInfo describes a directory that holds project-level contexts
+type ProjectInfo struct {
+	Path     string
+	Contexts int
+	Current  string
+}
+
+// DiscoverProjects searches roots, up to maxDepth levels deep, for directories
+// with project-level contexts. Hidden and dependency directories are skipped,
+// as are subdirectories that can't be read. The scan stops when ctx is done.
+func DiscoverProjects(ctx gocontext.Context, roots []string, maxDepth int) ([]ProjectInfo, error) {
+	for _, root := range roots {
+		info, err := os.Stat(root)
+		if err != nil {
+			return nil, fmt.Errorf("workspace root %s: %v", root, err)
+		}
+		if !info.IsDir() {
+			return nil, fmt.Errorf("workspace root %s is not a directory", root)
+		}
+	}
+
+	s := &projectScanner{
+		ctx:      ctx,
+		sem:      make(chan struct{}, scanWorkers),
+		maxDepth: maxDepth,
+		seen:     make(map[string]bool),
+	}
+
+	for _, root := range roots {
+		s.wg.Add(1)
+		go s.scan(filepath.Clean(root), 0)
+	}
+	s.wg.Wait()
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("cannot discover projects: %w", err)
+	}
+
+	sort.Slice(s.projects, func(i, j int) bool {
+		return s.projects[i].Path < s.projects[j].Path
+	})
+
+	return s.projects, nil
+}
+
+// projectScanner walks directory trees concurrently, collecting projects
+type projectScanner struct {
+	ctx      gocontext.Context
+	sem      chan struct{}
+	wg       sync.WaitGroup
+	maxDepth int
+
+	mu       sync.Mutex
+	seen     map[string]bool // Guards against overlapping roots
+	projects []ProjectInfo
+}
+
+// scan inspects dir and schedules its subdirectories
+func (s *projectScanner) scan(dir string, depth int) {
+	defer s.wg.Done()
+
+	s.mu.Lock()
+	if s.seen[dir] {
+		s.mu.Unlock()
+		return
+	}
+	s.seen[dir] = true
+	s.mu.Unlock()
+
+	if s.ctx.Err() != nil {
+		return
+	}
+	s.sem <- struct{}{}
+	entries, err := os.ReadDir(dir)
+	<-s.sem
+	if err != nil {
+		return
+	}
+
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+
+		name := entry.Name()
+		if name == config.ProjectConfigDir {
+			s.inspect(dir)
+			continue
+		}
+
+		if depth >= s.maxDepth || strings.HasPrefix(name, ".") || skippedScanDirs[name] {
+			continue
+		}
+
+		s.wg.Add(1)
+		go s.scan(filepath.Join(dir, name), depth+1)
+	}
+}
+
+// inspect records dir as a project if its settings directory holds contexts
+func (s *projectScanner) inspect(dir string) {
+	settingsDir := filepath.Join(dir, config.ProjectConfigDir, config.SettingsSubDir)
+
+	s.sem <- struct{}{}
+	contexts, err := listContextsIn(settingsDir)
+	var state *State
+	if err == nil && len(contexts) > 0 {
+		state, err = peekState(filepath.Join(settingsDir, config.StateFileName))
+	}
+	<-s.sem
+
+	if err != nil || len(contexts) == 0 {
+		return
+	}
+
+	s.mu.Lock()
+	s.projects = append(s.projects, ProjectInfo{
+		Path:     dir,
+		Contexts: len(contexts),
+		Current:  state.Current,
+	})
+	s.mu.Unlock()
+}
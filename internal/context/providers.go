@@ -0,0 +1,141 @@
+package context
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hungthai1401/occtx/internal/util"
+)
+
+// providerPath is the key path of a provider block in a context
+func providerPath(provider string) KeyPath {
+	return KeyPath{"provider", provider}
+}
+
+// ExportProvider returns the block of provider in the context name as a
+// JSON object keyed by the provider name, e.g. {"anthropic": {...}}, which
+// ApplyProviders reads back. With redact, secrets are replaced as in a
+// redacted export.
+func (m *Manager) ExportProvider(name, provider string, redact bool) ([]byte, error) {
+	ctx, err := m.GetContext(name)
+	if err != nil {
+		return nil, err
+	}
+	block, ok := providerPath(provider).Get(ctx.Data)
+	if !ok {
+		return nil, fmt.Errorf("context '%s' has no provider '%s'%s", ctx.Name, provider, providerHint(ctx.Data))
+	}
+
+	profile := map[string]interface{}{provider: block}
+	if redact {
+		redactSecrets(profile)
+	}
+	var out bytes.Buffer
+	encoder := json.NewEncoder(&out)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(profile); err != nil {
+		return nil, err
+	}
+	return out.Bytes(), nil
+}
+
+// ApplyProviders sets the provider blocks in data, a JSON or JSONC object
+// keyed by provider name as ExportProvider writes it, in the context name.
+// Each block replaces the provider of the same name whole; other providers
+// and the rest of the context are kept, though JSONC comments are not. It
+// returns the names of the providers applied.
+func (m *Manager) ApplyProviders(name string, data []byte) ([]string, error) {
+	if err := m.CheckWritable("apply provider"); err != nil {
+		return nil, err
+	}
+
+	ctx, err := m.GetContext(name)
+	if err != nil {
+		return nil, err
+	}
+	if err := ctx.CheckModifiable("apply a provider to"); err != nil {
+		return nil, err
+	}
+
+	profile, err := util.ParseJSONC(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid provider profile: %v", err)
+	}
+	if len(profile) == 0 {
+		return nil, fmt.Errorf("the provider profile is empty; expected an object such as {\"anthropic\": {...}}")
+	}
+	providers := make([]string, 0, len(profile))
+	for provider, block := range profile {
+		if _, ok := block.(map[string]interface{}); !ok {
+			return nil, fmt.Errorf("provider '%s' in the profile is not an object", provider)
+		}
+		if hasRedactedValue(block) {
+			return nil, fmt.Errorf("provider '%s' holds %s values; fill them in (or use {env:...} references) before applying it", provider, RedactedValue)
+		}
+		providers = append(providers, provider)
+	}
+	sort.Strings(providers)
+
+	// Shallow copies keep ctx.Data as it was, for the changelog
+	updated := make(map[string]interface{}, len(ctx.Data)+1)
+	for key, value := range ctx.Data {
+		updated[key] = value
+	}
+	if existing, ok := ctx.Data["provider"].(map[string]interface{}); ok {
+		copied := make(map[string]interface{}, len(existing)+len(profile))
+		for key, value := range existing {
+			copied[key] = value
+		}
+		updated["provider"] = copied
+	}
+	for _, provider := range providers {
+		if err := providerPath(provider).Set(updated, profile[provider]); err != nil {
+			return nil, fmt.Errorf("context '%s': %v", ctx.Name, err)
+		}
+	}
+
+	if err := m.rewriteContext(ctx, updated, "apply provider", LogApplied, "provider "+strings.Join(providers, ", ")+": "); err != nil {
+		return nil, err
+	}
+	m.logf(VerbosityVerbose, "applied provider %s to '%s'", strings.Join(providers, ", "), ctx.Name)
+	return providers, nil
+}
+
+// providerHint names the providers a context has, for not-found errors
+func providerHint(data map[string]interface{}) string {
+	providers, _ := data["provider"].(map[string]interface{})
+	if len(providers) == 0 {
+		return ""
+	}
+	names := make([]string, 0, len(providers))
+	for name := range providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return " (it has " + strings.Join(names, ", ") + ")"
+}
+
+// hasRedactedValue reports whether a redacted export left placeholders in node
+func hasRedactedValue(node interface{}) bool {
+	switch v := node.(type) {
+	case map[string]interface{}:
+		for _, child := range v {
+			if hasRedactedValue(child) {
+				return true
+			}
+		}
+	case []interface{}:
+		for _, item := range v {
+			if hasRedactedValue(item) {
+				return true
+			}
+		}
+	case string:
+		return v == RedactedValue
+	}
+	return false
+}
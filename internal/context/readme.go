@@ -0,0 +1,102 @@
+package context
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ReadmeExtension marks the README of a context: <name>.md next to its file
+const ReadmeExtension = ".md"
+
+// ContextDoc documents a context: its description from the metadata and the
+// README next to its file, which shared contexts can carry as well
+type ContextDoc struct {
+	Description string
+	Readme      string // Markdown
+	ReadmePath  string // Set when the context has a README
+}
+
+// Empty reports whether the context is undocumented
+func (d *ContextDoc) Empty() bool {
+	return d.Description == "" && d.Readme == ""
+}
+
+// ReadmePath returns the README file of the context at contextPath
+func ReadmePath(contextPath string) string {
+	return strings.TrimSuffix(contextPath, filepath.Ext(contextPath)) + ReadmeExtension
+}
+
+// GetContextDoc returns the description and README of a context
+func (m *Manager) GetContextDoc(name string) (*ContextDoc, error) {
+	ctx, err := m.GetContext(name)
+	if err != nil {
+		return nil, err
+	}
+
+	doc := &ContextDoc{}
+	if !ctx.Shared {
+		meta, err := m.GetContextMeta(ctx.Name)
+		if err != nil {
+			return nil, err
+		}
+		if meta != nil {
+			doc.Description = meta.Description
+		}
+	}
+
+	path := ReadmePath(ctx.FilePath)
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return doc, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	doc.Readme = string(data)
+	doc.ReadmePath = path
+	return doc, nil
+}
+
+// SetDescription sets the one-line description of a context; an empty
+// description removes it
+func (m *Manager) SetDescription(name, description string) error {
+	if err := m.CheckWritable("set description"); err != nil {
+		return err
+	}
+
+	ctx, err := m.GetContext(name)
+	if err != nil {
+		return err
+	}
+	if err := ctx.CheckModifiable("describe"); err != nil {
+		return err
+	}
+
+	description = strings.Join(strings.Fields(description), " ")
+	return m.updateMetadata(func(meta *metadataFile) {
+		entry := meta.Contexts[ctx.Name]
+		if entry == nil {
+			if description == "" {
+				return
+			}
+			entry = &ContextMeta{}
+			meta.Contexts[ctx.Name] = entry
+		}
+		entry.Description = description
+	})
+}
+
+// moveReadme renames the README of the context file at oldPath, if it has
+// one, without replacing a file already at the new name
+func moveReadme(oldPath, newPath string) error {
+	from, to := ReadmePath(oldPath), ReadmePath(newPath)
+	if _, err := os.Stat(from); os.IsNotExist(err) {
+		return nil
+	}
+	if _, err := os.Lstat(to); err == nil && !sameFile(from, to) {
+		return fmt.Errorf("%s already exists", to)
+	}
+	return os.Rename(from, to)
+}
@@ -0,0 +1,60 @@
+package context
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/hungthai1401/occtx/internal/config"
+)
+
+// ReadOnlyEnvVar enables read-only mode when set to a true value
+const ReadOnlyEnvVar = config.ReadOnlyEnvVar
+
+// ErrReadOnly is returned by mutating operations in read-only mode
+var ErrReadOnly = errors.New("occtx is in read-only mode")
+
+// SetReadOnly enables or disables read-only mode
+func (m *Manager) SetReadOnly(readOnly bool) {
+	m.readOnly = readOnly
+}
+
+// IsReadOnly reports whether mutating operations are disabled
+func (m *Manager) IsReadOnly() bool {
+	return m.readOnly
+}
+
+// CheckWritable returns an error naming the operation if read-only mode is on,
+// the contexts directory can't be written (e.g. a read-only mount) or a
+// project scope would be created where it makes no sense
+func (m *Manager) CheckWritable(operation string) error {
+	if m.readOnly {
+		return fmt.Errorf("cannot %s: %w (unset --read-only / %s to allow changes)", operation, ErrReadOnly, ReadOnlyEnvVar)
+	}
+	if err := m.checkDirWritable(); err != nil {
+		return fmt.Errorf("cannot %s: %v", operation, err)
+	}
+	if err := m.checkProjectRoot(); err != nil {
+		return fmt.Errorf("cannot %s: %v", operation, err)
+	}
+	return nil
+}
+
+// checkDirWritable probes the contexts directory, or its nearest existing
+// ancestor, once per manager
+func (m *Manager) checkDirWritable() error {
+	if m.writableChecked {
+		return m.writableErr
+	}
+	m.writableChecked = true
+
+	dir, err := config.ProbeWritable(m.paths.GetContextsDir(m.useProject))
+	if err != nil {
+		m.writableErr = fmt.Errorf("%s is not writable (%v); use --config-dir or %s to choose a writable directory", dir, err, config.ConfigDirEnvVar)
+	}
+	return m.writableErr
+}
+
+// readOnlyFromEnv reports whether the read-only environment variable is set to true
+func readOnlyFromEnv() bool {
+	return config.ReadOnlyFromEnv()
+}
@@ -0,0 +1,159 @@
+package context
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// renameStep is one artifact update of a rename, with its inverse
+type renameStep struct {
+	do   func() error
+	undo func() error
+}
+
+// RenameContext renames a context together with everything keyed by its
+// name: the file (keeping its format), checksum, metadata and state. If any
+// step fails, the steps already applied are rolled back.
+func (m *Manager) RenameContext(oldName, newName string) error {
+	if err := m.CheckWritable("rename context"); err != nil {
+		return err
+	}
+	defer m.timeOperation("rename")()
+
+	if err := validateContextName(oldName); err != nil {
+		return fmt.Errorf("invalid old name: %v", err)
+	}
+	if err := m.validateNewContextName(newName); err != nil {
+		return fmt.Errorf("invalid new name: %v", err)
+	}
+
+	// Check if old context exists
+	oldContext, err := m.GetContext(oldName)
+	if err != nil {
+		return err
+	}
+
+	if err := oldContext.CheckModifiable("rename"); err != nil {
+		return err
+	}
+
+	if err := m.checkCaseConflict(newName, oldContext.Name); err != nil {
+		return err
+	}
+
+	// The new name must be free in every format, not just the one being moved
+	contextsDir := m.paths.GetContextsDir(m.useProject)
+	for _, f := range GetAllFormats() {
+		existingPath := filepath.Join(contextsDir, newName+f.FileExtension())
+		if sameFile(existingPath, oldContext.FilePath) {
+			// Case-only rename on a case-insensitive filesystem
+			continue
+		}
+		if _, err := os.Stat(existingPath); err == nil {
+			return fmt.Errorf("context '%s' already exists (%s format)", newName, f.DisplayName())
+		}
+	}
+
+	oldPath := oldContext.FilePath
+	newPath := filepath.Join(contextsDir, newName+oldContext.Format.FileExtension())
+
+	steps := []renameStep{
+		{
+			do:   func() error { return os.Rename(oldPath, newPath) },
+			undo: func() error { return os.Rename(newPath, oldPath) },
+		},
+		{
+			do:   func() error { return m.moveChecksum(oldPath, newPath) },
+			undo: func() error { return m.moveChecksum(newPath, oldPath) },
+		},
+		{
+			do:   func() error { return m.moveLocalOverride(oldContext.Name, newName) },
+			undo: func() error { return m.moveLocalOverride(newName, oldContext.Name) },
+		},
+		{
+			do:   func() error { return moveReadme(oldPath, newPath) },
+			undo: func() error { return moveReadme(newPath, oldPath) },
+		},
+		{
+			do:   func() error { return m.moveMetadata(oldContext.Name, newName) },
+			undo: func() error { return m.moveMetadata(newName, oldContext.Name) },
+		},
+		{
+			do:   func() error { return m.renameInState(oldContext.Name, newName) },
+			undo: func() error { return m.renameInState(newName, oldContext.Name) },
+		},
+	}
+
+	for i, step := range steps {
+		if err := step.do(); err != nil {
+			for j := i - 1; j >= 0; j-- {
+				if undoErr := steps[j].undo(); undoErr != nil {
+					return fmt.Errorf("rename failed: %v (rollback also failed: %v)", err, undoErr)
+				}
+			}
+			return fmt.Errorf("rename failed: %v", err)
+		}
+	}
+
+	// The rename is done; failing to log it doesn't undo it
+	if err := m.appendLog(newName, LogRenamed, fmt.Sprintf("renamed from '%s'", oldContext.Name)); err != nil {
+		m.warnf("could not log the rename of '%s': %v", newName, err)
+	}
+	return nil
+}
+
+// renameInState replaces references to a context in the current, previous
+// and temporary switch entries of the state file
+func (m *Manager) renameInState(oldName, newName string) error {
+	return m.updateState(func(state *State) error {
+		updated := false
+		for _, ref := range state.contextRefs() {
+			if *ref == oldName {
+				*ref = newName
+				updated = true
+			}
+		}
+
+		if !updated {
+			return errStateUnchanged
+		}
+		return nil
+	})
+}
+
+// contextRefs returns pointers to every context name held in the state
+func (s *State) contextRefs() []*string {
+	refs := []*string{&s.Current, &s.Previous, &s.Default}
+	if s.Temporary != nil {
+		refs = append(refs, &s.Temporary.Context, &s.Temporary.RevertTo)
+	}
+	for i := range s.Listing {
+		refs = append(refs, &s.Listing[i])
+	}
+	return refs
+}
+
+// sameFile reports whether both paths exist and name the same file
+func sameFile(a, b string) bool {
+	infoA, err := os.Stat(a)
+	if err != nil {
+		return false
+	}
+	infoB, err := os.Stat(b)
+	if err != nil {
+		return false
+	}
+	return os.SameFile(infoA, infoB)
+}
+
+// moveLocalOverride renames the local override of a context, if it has one
+func (m *Manager) moveLocalOverride(oldName, newName string) error {
+	oldPath, ok := m.LocalOverridePath(oldName)
+	if !ok {
+		return nil
+	}
+	suffix := strings.TrimPrefix(filepath.Base(oldPath), oldName)
+	return os.Rename(oldPath, filepath.Join(filepath.Dir(oldPath), newName+suffix))
+}
@@ -0,0 +1,86 @@
+package context
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// RenderOptions changes what Render starts from
+type RenderOptions struct {
+	Template string            // Render this template as the context, as -n --from-template would create it
+	Vars     map[string]string // The template's variables
+	Overlays []string          // Contexts deep-merged on top, in order, below the local override
+}
+
+// Render returns the active config file a switch to name would write and
+// the exact bytes it would write, without writing anything: the context
+// (or the template rendered under that name), then the overlays, then the
+// machine-local override, merged with the merge settings of the occtx
+// config, with per-machine sections resolved. Checks a switch runs, such as
+// policies and approval, are left to the caller.
+func (m *Manager) Render(name string, opts RenderOptions) (string, []byte, error) {
+	defer m.timeOperation("render")()
+
+	var ctx *Context
+	var err error
+	if opts.Template != "" {
+		if err := validateContextName(name); err != nil {
+			return "", nil, err
+		}
+		data, err := m.RenderTemplate(opts.Template, opts.Vars)
+		if err != nil {
+			return "", nil, err
+		}
+		if ctx, err = m.composedContext(name, data); err != nil {
+			return "", nil, err
+		}
+	} else if ctx, err = m.loadContextRaw(name); err != nil {
+		return "", nil, err
+	}
+
+	if len(opts.Overlays) > 0 {
+		mergeOpts, err := ParseMergeOptions(m.config.Merge)
+		if err != nil {
+			return "", nil, fmt.Errorf("merge: %v", err)
+		}
+		data, err := ctx.parsed()
+		if err != nil {
+			return "", nil, err
+		}
+		for _, overlay := range opts.Overlays {
+			overlayCtx, err := m.GetContext(overlay)
+			if err != nil {
+				return "", nil, err
+			}
+			data = MergeData(data, overlayCtx.Data, mergeOpts)
+		}
+		m.logf(VerbosityVerbose, "merged %s into '%s'", strings.Join(opts.Overlays, ", "), ctx.Name)
+		if ctx, err = m.composedContext(ctx.Name, data); err != nil {
+			return "", nil, err
+		}
+	}
+
+	path, data, err := m.activeConfigFor(ctx)
+	if err != nil {
+		return "", nil, err
+	}
+	m.logf(VerbosityVerbose, "rendered '%s' as %s", ctx.Name, path)
+	return path, data, nil
+}
+
+// composedContext returns a context of data that exists only in memory,
+// encoded as a new JSON context file of that name would be
+func (m *Manager) composedContext(name string, data map[string]interface{}) (*Context, error) {
+	raw, err := encodeContextData(name, FormatJSON, data)
+	if err != nil {
+		return nil, err
+	}
+	return &Context{
+		Name:     name,
+		Data:     data,
+		FilePath: filepath.Join(m.paths.GetContextsDir(m.useProject), name+FormatJSON.FileExtension()),
+		Format:   FormatJSON,
+		raw:      raw,
+	}, nil
+}
@@ -0,0 +1,158 @@
+package context
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// RepairResult says where a context that failed verification was restored from
+type RepairResult struct {
+	Name string
+	File string
+	From string // The backup archive or trashed file restored, "" if none could repair it
+}
+
+// repairCandidate is a copy of a context file found in a backup or the trash
+type repairCandidate struct {
+	from    string
+	data    []byte
+	modTime time.Time
+}
+
+// RepairContexts restores the modified, missing and corrupt contexts among
+// results from the newest copy in the scope's backups or trash that matches
+// the recorded checksum. A corrupt file occtx never recorded gets the newest
+// copy that parses. The file being replaced is moved to the trash first;
+// contexts no copy can repair are left alone and reported with an empty From.
+func (m *Manager) RepairContexts(results []VerifyResult) ([]RepairResult, error) {
+	if err := m.CheckWritable("repair contexts"); err != nil {
+		return nil, err
+	}
+
+	manifest, err := m.loadChecksums()
+	if err != nil {
+		return nil, err
+	}
+	backups, err := m.ListBackups()
+	if err != nil {
+		return nil, err
+	}
+
+	contextsDir := m.paths.GetContextsDir(m.useProject)
+	var repaired []RepairResult
+	for _, result := range results {
+		switch result.Status {
+		case VerifyModified, VerifyMissing, VerifyCorrupt:
+		default:
+			continue
+		}
+
+		path := filepath.Join(contextsDir, result.File)
+		candidates, err := m.repairCandidates(result.File, backups)
+		if err != nil {
+			return repaired, err
+		}
+		recorded := manifest.Files[result.File]
+		repair := RepairResult{Name: result.Name, File: result.File}
+		for _, candidate := range candidates {
+			if recorded != "" && checksum(candidate.data) != recorded {
+				continue
+			}
+			if _, err := parseContextData(path, candidate.data); err != nil {
+				continue
+			}
+			if err := m.trashCopy(result.Name, path); err != nil {
+				return repaired, err
+			}
+			if err := writeFileAtomic(path, candidate.data, m.fileMode()); err != nil {
+				return repaired, err
+			}
+			if err := m.recordChecksum(path); err != nil {
+				return repaired, err
+			}
+			m.logf(VerbosityVerbose, "restored %s from %s", path, candidate.from)
+			repair.From = candidate.from
+			break
+		}
+		repaired = append(repaired, repair)
+	}
+	return repaired, nil
+}
+
+// repairCandidates collects the copies of a context file in backups and the
+// trash, newest first
+func (m *Manager) repairCandidates(file string, backups []BackupInfo) ([]repairCandidate, error) {
+	var candidates []repairCandidate
+
+	target := filepath.Join(m.paths.GetContextsDir(m.useProject), file)
+	for _, backup := range backups {
+		files, err := m.readBackupArchive(backup.Path)
+		if err != nil {
+			m.warnf("skipping backup '%s': %v", backup.Name, err)
+			continue
+		}
+		for _, f := range files {
+			if f.path == target {
+				candidates = append(candidates, repairCandidate{from: backup.Name, data: f.data, modTime: backup.CreatedAt})
+			}
+		}
+	}
+
+	// Trashed copies are named <name>-<stamp><ext>; parsing the stamp keeps
+	// "work" from picking up "work-dev-<stamp>.json"
+	ext := filepath.Ext(file)
+	prefix := strings.TrimSuffix(file, ext) + "-"
+	trashDir := m.paths.GetTrashDir(m.useProject)
+	entries, err := os.ReadDir(trashDir)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasPrefix(name, prefix) || filepath.Ext(name) != ext {
+			continue
+		}
+		trashedAt, err := time.ParseInLocation(trashStampLayout, strings.TrimSuffix(strings.TrimPrefix(name, prefix), ext), time.Local)
+		if err != nil {
+			continue
+		}
+		path := filepath.Join(trashDir, name)
+		data, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		candidates = append(candidates, repairCandidate{from: path, data: data, modTime: trashedAt})
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].modTime.After(candidates[j].modTime)
+	})
+	return candidates, nil
+}
+
+// trashCopy copies a context file about to be overwritten into the trash,
+// keeping its checksum and metadata; a missing file is skipped
+func (m *Manager) trashCopy(name, path string) error {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	trashDir := m.paths.GetTrashDir(m.useProject)
+	if err := os.MkdirAll(trashDir, m.dirMode()); err != nil {
+		return err
+	}
+	stamp := time.Now().Format(trashStampLayout)
+	trashPath := filepath.Join(trashDir, name+"-"+stamp+filepath.Ext(path))
+	if err := writeFileAtomic(trashPath, data, m.fileMode()); err != nil {
+		return fmt.Errorf("cannot keep %s before restoring it: %v", path, err)
+	}
+	return nil
+}
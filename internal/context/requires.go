@@ -0,0 +1,268 @@
+package context
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hungthai1401/occtx/internal/config"
+	"github.com/hungthai1401/occtx/internal/util"
+)
+
+// Requirement kinds, written as "<kind>:<target>". A requirement without a
+// kind names a capability the machine must list in "provides".
+const (
+	RequireEnv  = "env"  // The environment variable is set and not empty
+	RequireCmd  = "cmd"  // The command is on PATH
+	RequireFile = "file" // The file or directory exists; "~/" is expanded
+	RequireTCP  = "tcp"  // host:port accepts connections
+)
+
+// requirementDialTimeout bounds each tcp requirement check
+const requirementDialTimeout = 2 * time.Second
+
+// RequirementsError is returned when a switch target needs capabilities this
+// machine doesn't have
+type RequirementsError struct {
+	Context string
+	Missing []string
+}
+
+func (e *RequirementsError) Error() string {
+	return fmt.Sprintf("'%s' needs what this machine doesn't have:\n  %s\npass --skip-requires to switch anyway",
+		e.Context, strings.Join(e.Missing, "\n  "))
+}
+
+// RequirementResult is the outcome of checking one requirement
+type RequirementResult struct {
+	Requirement string
+	Problem     string // Empty when the requirement is met
+}
+
+// ValidateRequirement checks that a requirement is well-formed
+func ValidateRequirement(requirement string) error {
+	kind, target, ok := strings.Cut(requirement, ":")
+	if !ok {
+		if strings.TrimSpace(requirement) == "" || strings.ContainsAny(requirement, " \t") {
+			return fmt.Errorf("invalid requirement '%s'", requirement)
+		}
+		return nil
+	}
+	if target == "" {
+		return fmt.Errorf("invalid requirement '%s': nothing after '%s:'", requirement, kind)
+	}
+
+	switch kind {
+	case RequireEnv:
+		if !envNamePattern.MatchString(target) {
+			return fmt.Errorf("invalid requirement '%s': bad environment variable name", requirement)
+		}
+	case RequireCmd, RequireFile:
+	case RequireTCP:
+		if _, _, err := net.SplitHostPort(target); err != nil {
+			return fmt.Errorf("invalid requirement '%s': expected tcp:host:port", requirement)
+		}
+	default:
+		return fmt.Errorf("invalid requirement '%s': unknown kind '%s' (expected env, cmd, file or tcp)", requirement, kind)
+	}
+	return nil
+}
+
+// AddRequirements declares what a context needs from the machine it is
+// switched to on, keeping the ones already declared
+func (m *Manager) AddRequirements(name string, requirements []string) error {
+	if err := m.CheckWritable("set requirements"); err != nil {
+		return err
+	}
+
+	ctx, err := m.GetContext(name)
+	if err != nil {
+		return err
+	}
+	if err := ctx.CheckModifiable("set requirements of"); err != nil {
+		return err
+	}
+	for _, requirement := range requirements {
+		if err := ValidateRequirement(requirement); err != nil {
+			return err
+		}
+	}
+
+	return m.updateMetadata(func(meta *metadataFile) {
+		entry := meta.Contexts[ctx.Name]
+		if entry == nil {
+			entry = &ContextMeta{}
+			meta.Contexts[ctx.Name] = entry
+		}
+		for _, requirement := range requirements {
+			if !util.Contains(entry.Requires, requirement) {
+				entry.Requires = append(entry.Requires, requirement)
+			}
+		}
+		sort.Strings(entry.Requires)
+	})
+}
+
+// RemoveRequirements drops declared requirements from a context; with none
+// given it drops them all
+func (m *Manager) RemoveRequirements(name string, requirements []string) error {
+	if err := m.CheckWritable("remove requirements"); err != nil {
+		return err
+	}
+
+	ctx, err := m.GetContext(name)
+	if err != nil {
+		return err
+	}
+
+	var missing []string
+	err = m.updateMetadata(func(meta *metadataFile) {
+		entry := meta.Contexts[ctx.Name]
+		if entry == nil {
+			missing = requirements
+			return
+		}
+		if len(requirements) == 0 {
+			entry.Requires = nil
+			return
+		}
+
+		for _, requirement := range requirements {
+			if !util.Contains(entry.Requires, requirement) {
+				missing = append(missing, requirement)
+			}
+		}
+		kept := entry.Requires[:0]
+		for _, requirement := range entry.Requires {
+			if !util.Contains(requirements, requirement) {
+				kept = append(kept, requirement)
+			}
+		}
+		entry.Requires = kept
+		if len(entry.Requires) == 0 {
+			entry.Requires = nil
+		}
+	})
+	if err != nil {
+		return err
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("context '%s' doesn't require %s", ctx.Name, strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// GetRequirements returns what a context needs, or nil if it declares nothing
+func (m *Manager) GetRequirements(name string) ([]string, error) {
+	meta, err := m.GetContextMeta(name)
+	if err != nil || meta == nil {
+		return nil, err
+	}
+	return meta.Requires, nil
+}
+
+// CheckRequirements checks each requirement of a context against this machine
+func (m *Manager) CheckRequirements(name string) ([]RequirementResult, error) {
+	meta, err := m.GetContextMeta(name)
+	if err != nil || meta == nil {
+		return nil, err
+	}
+
+	results := make([]RequirementResult, 0, len(meta.Requires))
+	for _, requirement := range meta.Requires {
+		if err := m.checkCanceled("check requirements"); err != nil {
+			return nil, err
+		}
+		problem := m.checkRequirement(requirement, meta.Env)
+		m.logf(VerbosityDebug, "requirement %s of '%s': %s", requirement, name, orOK(problem))
+		results = append(results, RequirementResult{Requirement: requirement, Problem: problem})
+	}
+	return results, nil
+}
+
+// SetSkipRequirements lets switches go ahead when requirements aren't met
+func (m *Manager) SetSkipRequirements(skip bool) {
+	m.skipRequires = skip
+}
+
+// checkRequirements refuses a switch to a context whose requirements this
+// machine doesn't meet
+func (m *Manager) checkRequirements(ctx *Context) error {
+	if m.skipRequires {
+		return nil
+	}
+
+	results, err := m.CheckRequirements(ctx.Name)
+	if err != nil {
+		return err
+	}
+	var missing []string
+	for _, result := range results {
+		if result.Problem != "" {
+			missing = append(missing, result.Requirement+": "+result.Problem)
+		}
+	}
+	if len(missing) > 0 {
+		return &RequirementsError{Context: ctx.Name, Missing: missing}
+	}
+	return nil
+}
+
+// checkRequirement returns why requirement isn't met, or "" if it is. env
+// holds the variables the context itself exports.
+func (m *Manager) checkRequirement(requirement string, env map[string]string) string {
+	kind, target, ok := strings.Cut(requirement, ":")
+	if !ok {
+		if util.ContainsFold(m.config.Provides, requirement) {
+			return ""
+		}
+		return "not listed in \"provides\" of the occtx config"
+	}
+
+	switch kind {
+	case RequireEnv:
+		if os.Getenv(target) != "" || env[target] != "" {
+			return ""
+		}
+		return "not set"
+	case RequireCmd:
+		if _, err := exec.LookPath(target); err != nil {
+			return "not found on PATH"
+		}
+		return ""
+	case RequireFile:
+		expanded, err := config.ExpandHome(target)
+		if err != nil {
+			return err.Error()
+		}
+		if _, err := os.Stat(expanded); err != nil {
+			return "does not exist"
+		}
+		return ""
+	case RequireTCP:
+		if m.offline {
+			return "not checked in offline mode"
+		}
+		dialer := net.Dialer{Timeout: requirementDialTimeout}
+		conn, err := dialer.DialContext(m.Context(), "tcp", target)
+		if err != nil {
+			return "unreachable"
+		}
+		conn.Close()
+		return ""
+	default:
+		return fmt.Sprintf("unknown kind '%s'", kind)
+	}
+}
+
+// orOK returns problem, or "ok" if it is empty
+func orOK(problem string) string {
+	if problem == "" {
+		return "ok"
+	}
+	return problem
+}
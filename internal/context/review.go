@@ -0,0 +1,59 @@
+package context
+
+import (
+	"fmt"
+	"os"
+)
+
+// ReviewDeclinedError is returned when a reviewed switch was not confirmed
+type ReviewDeclinedError struct {
+	Context string
+	Changes int // Changes the switch would make to the active config
+}
+
+func (e *ReviewDeclinedError) Error() string {
+	return fmt.Sprintf("switch to '%s' (%d change(s) to the active config) was not confirmed; pass --yes to switch anyway", e.Context, e.Changes)
+}
+
+// SetReview makes every switch show its changes and ask first, as
+// switch.review does in the occtx config
+func (m *Manager) SetReview(review bool) {
+	m.review = review
+}
+
+// SetReviewHandler sets how reviewed switches are confirmed: the handler is
+// shown the changes to the active config and reports whether to switch.
+// Without one, reviewed switches that change anything are declined.
+func (m *Manager) SetReviewHandler(handler func(name string, changes []ContextChange) bool) {
+	m.reviewer = handler
+}
+
+// checkReview asks before a switch that changes the active config, when
+// switches are reviewed. data is what the switch would write.
+func (m *Manager) checkReview(ctx *Context, activeConfigPath string, data []byte) error {
+	if !m.review && !m.config.Switch.Review {
+		return nil
+	}
+
+	target, err := parseContextData(activeConfigPath, data)
+	if err != nil {
+		return err
+	}
+	// A missing or unreadable active config is reviewed as empty
+	active := map[string]interface{}{}
+	if existing, err := os.ReadFile(activeConfigPath); err == nil {
+		if parsed, err := parseContextData(activeConfigPath, existing); err == nil {
+			active = parsed
+		}
+	}
+
+	changes := diffContextData(active, target)
+	if len(changes) == 0 {
+		m.logf(VerbosityVerbose, "review: switching to '%s' doesn't change the active config", ctx.Name)
+		return nil
+	}
+	if m.reviewer != nil && m.reviewer(ctx.Name, changes) {
+		return nil
+	}
+	return &ReviewDeclinedError{Context: ctx.Name, Changes: len(changes)}
+}
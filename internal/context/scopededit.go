@@ -0,0 +1,285 @@
+package context
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/hungthai1401/occtx/internal/util"
+)
+
+// ScopedEdit is the value at a key path of a context, taken out of the
+// context file for editing on its own. Only that value is replaced when the
+// edit is finished; the rest of the file, comments included, stays as it was.
+type ScopedEdit struct {
+	Context string
+	Path    KeyPath
+	Value   []byte // The value as written in the file, dedented to start at column 0
+	JSONC   bool   // The context is JSONC, so the value may hold comments
+
+	filePath string
+	file     []byte // The context file when the edit began
+	start    int    // Span of the value in file
+	end      int
+	indent   string // Indentation of the line the value starts on
+}
+
+// BeginScopedEdit extracts the value at the dotted key path expr of the
+// context name. The path must name a single value: "*" is not allowed.
+func (m *Manager) BeginScopedEdit(name, expr string) (*ScopedEdit, error) {
+	if err := m.CheckWritable("edit context"); err != nil {
+		return nil, err
+	}
+	path, err := ParseKeyPath(expr)
+	if err != nil {
+		return nil, err
+	}
+	for _, segment := range path {
+		if segment == "*" {
+			return nil, fmt.Errorf("invalid key path '%s': editing needs a single value, not '*'", expr)
+		}
+	}
+
+	ctx, err := m.GetContext(name)
+	if err != nil {
+		return nil, err
+	}
+	if err := ctx.CheckModifiable("edit"); err != nil {
+		return nil, err
+	}
+	file, err := os.ReadFile(ctx.FilePath)
+	if err != nil {
+		return nil, err
+	}
+
+	start, end, err := findValueSpan(file, path)
+	if err != nil {
+		return nil, fmt.Errorf("context '%s': %v", ctx.Name, err)
+	}
+	lineStart := bytes.LastIndexByte(file[:start], '\n') + 1
+	indent := string(file[lineStart:start])
+	indent = indent[:len(indent)-len(strings.TrimLeft(indent, " \t"))]
+
+	edit := &ScopedEdit{
+		Context:  ctx.Name,
+		Path:     path,
+		JSONC:    ctx.Format == FormatJSONC,
+		filePath: ctx.FilePath,
+		file:     file,
+		start:    start,
+		end:      end,
+		indent:   indent,
+	}
+	edit.Value = []byte(strings.ReplaceAll(string(file[start:end]), "\n"+indent, "\n"))
+	m.logf(VerbosityDebug, "extracted %s of '%s': bytes %d-%d of %s", path, ctx.Name, start, end, ctx.FilePath)
+	return edit, nil
+}
+
+// FinishScopedEdit puts value back at the edit's key path and reports
+// whether the context changed. The value must be valid JSON (with comments,
+// for a JSONC context), and the context file must not have changed since
+// the edit began.
+func (m *Manager) FinishScopedEdit(edit *ScopedEdit, value []byte) (bool, error) {
+	if err := m.CheckWritable("edit context"); err != nil {
+		return false, err
+	}
+
+	value = bytes.TrimSpace(value)
+	if bytes.Equal(value, bytes.TrimSpace(edit.Value)) {
+		return false, nil
+	}
+	clean := value
+	if edit.JSONC {
+		clean = util.StripJSONCComments(value)
+	}
+	if err := json.Unmarshal(clean, new(interface{})); err != nil {
+		return false, fmt.Errorf("invalid value for %s: %v", edit.Path, err)
+	}
+
+	indented := strings.ReplaceAll(string(value), "\n", "\n"+edit.indent)
+	var data []byte
+	data = append(data, edit.file[:edit.start]...)
+	data = append(data, indented...)
+	data = append(data, edit.file[edit.end:]...)
+	if _, err := parseContextData(edit.filePath, data); err != nil {
+		return false, fmt.Errorf("edited context '%s' is invalid: %v", edit.Context, err)
+	}
+
+	err := writeFileAtomicIf(edit.filePath, data, m.fileMode(), func() error {
+		current, err := os.ReadFile(edit.filePath)
+		if err != nil {
+			return err
+		}
+		if !bytes.Equal(current, edit.file) {
+			return fmt.Errorf("context '%s' was changed while %s was being edited; edit it again", edit.Context, edit.Path)
+		}
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+	m.logf(VerbosityVerbose, "replaced %s of '%s' in %s", edit.Path, edit.Context, edit.filePath)
+	return true, nil
+}
+
+// findValueSpan locates the value at path in JSON or JSONC data and returns
+// its byte span, so it can be replaced without re-encoding the rest
+func findValueSpan(data []byte, path KeyPath) (int, int, error) {
+	s := &jsonScanner{data: data}
+	s.skipSpace()
+	for depth, segment := range path {
+		if !s.consume('{') {
+			if depth == 0 {
+				return 0, 0, fmt.Errorf("not a JSON object")
+			}
+			return 0, 0, fmt.Errorf("'%s' is not an object", path[:depth])
+		}
+		found := false
+		for s.skipSpace(); !s.consume('}'); s.skipSpace() {
+			key, err := s.readString()
+			if err != nil {
+				return 0, 0, err
+			}
+			s.skipSpace()
+			if !s.consume(':') {
+				return 0, 0, s.errorf("expected ':'")
+			}
+			s.skipSpace()
+			if key == segment {
+				found = true
+				break
+			}
+			if err := s.skipValue(); err != nil {
+				return 0, 0, err
+			}
+			s.skipSpace()
+			s.consume(',')
+		}
+		if !found {
+			return 0, 0, fmt.Errorf("key path '%s' not found", path[:depth+1])
+		}
+	}
+
+	start := s.pos
+	if err := s.skipValue(); err != nil {
+		return 0, 0, err
+	}
+	return start, s.pos, nil
+}
+
+// jsonScanner walks JSON data that may hold the line comments of JSONC
+type jsonScanner struct {
+	data []byte
+	pos  int
+}
+
+func (s *jsonScanner) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("offset %d: %s", s.pos, fmt.Sprintf(format, args...))
+}
+
+// skipSpace skips whitespace and "//" comment lines
+func (s *jsonScanner) skipSpace() {
+	for s.pos < len(s.data) {
+		switch {
+		case strings.IndexByte(" \t\r\n", s.data[s.pos]) >= 0:
+			s.pos++
+		case bytes.HasPrefix(s.data[s.pos:], []byte("//")):
+			if i := bytes.IndexByte(s.data[s.pos:], '\n'); i >= 0 {
+				s.pos += i + 1
+			} else {
+				s.pos = len(s.data)
+			}
+		default:
+			return
+		}
+	}
+}
+
+// consume skips c if it is next
+func (s *jsonScanner) consume(c byte) bool {
+	if s.pos < len(s.data) && s.data[s.pos] == c {
+		s.pos++
+		return true
+	}
+	return false
+}
+
+// readString reads a string token and returns it decoded
+func (s *jsonScanner) readString() (string, error) {
+	start := s.pos
+	if err := s.skipString(); err != nil {
+		return "", err
+	}
+	var value string
+	if err := json.Unmarshal(s.data[start:s.pos], &value); err != nil {
+		return "", s.errorf("%v", err)
+	}
+	return value, nil
+}
+
+func (s *jsonScanner) skipString() error {
+	if !s.consume('"') {
+		return s.errorf("expected a string")
+	}
+	for s.pos < len(s.data) {
+		switch s.data[s.pos] {
+		case '\\':
+			s.pos += 2
+		case '"':
+			s.pos++
+			return nil
+		default:
+			s.pos++
+		}
+	}
+	return s.errorf("unterminated string")
+}
+
+// skipValue skips one value: an object, array, string or literal
+func (s *jsonScanner) skipValue() error {
+	if s.pos >= len(s.data) {
+		return s.errorf("unexpected end of data")
+	}
+	switch s.data[s.pos] {
+	case '"':
+		return s.skipString()
+	case '{', '[':
+		closing := byte('}')
+		if s.data[s.pos] == '[' {
+			closing = ']'
+		}
+		s.pos++
+		for s.skipSpace(); !s.consume(closing); s.skipSpace() {
+			if s.pos >= len(s.data) {
+				return s.errorf("unexpected end of data")
+			}
+			if closing == '}' {
+				if err := s.skipString(); err != nil {
+					return err
+				}
+				s.skipSpace()
+				if !s.consume(':') {
+					return s.errorf("expected ':'")
+				}
+				s.skipSpace()
+			}
+			if err := s.skipValue(); err != nil {
+				return err
+			}
+			s.skipSpace()
+			s.consume(',')
+		}
+		return nil
+	default:
+		start := s.pos
+		for s.pos < len(s.data) && strings.IndexByte(",}] \t\r\n", s.data[s.pos]) < 0 {
+			s.pos++
+		}
+		if s.pos == start {
+			return s.errorf("unexpected '%c'", s.data[s.pos])
+		}
+		return nil
+	}
+}
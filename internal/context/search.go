@@ -0,0 +1,286 @@
+package context
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ContentIndex is an inverted index from the keys and values of contexts to
+// the contexts holding them. It is built on the first search and refreshed
+// lazily: each search re-reads only the context files whose size or
+// modification time changed.
+//
+// A query is whitespace-separated terms that must all match. A term is free
+// text, matching any key or value containing it, or "key:text", matching
+// keys and values below a key of that name, e.g. "provider:openai" or
+// "baseURL:old-proxy". Matching is case-insensitive.
+type ContentIndex struct {
+	manager *Manager
+	files   map[string]*indexedFile // By context name
+
+	terms  map[string]map[string]bool            // Key or value to contexts
+	scoped map[string]map[string]map[string]bool // Key to the keys and values below it to contexts
+}
+
+// indexedFile is the indexed content of one context file
+type indexedFile struct {
+	path    string
+	modTime time.Time
+	size    int64
+	leaves  []indexLeaf
+}
+
+// indexLeaf is one value of a context with the keys leading to it, lowercased
+type indexLeaf struct {
+	path  string   // Dotted path as written
+	keys  []string // Object keys along the path
+	value string   // Scalar value; empty for empty objects and arrays
+}
+
+// SearchMatch is a context matching a query with the paths of the values
+// that matched
+type SearchMatch struct {
+	Context string   `json:"context"`
+	Paths   []string `json:"paths"`
+}
+
+// NewContentIndex returns an empty index over the scope's contexts, local
+// and shared
+func (m *Manager) NewContentIndex() *ContentIndex {
+	return &ContentIndex{manager: m, files: make(map[string]*indexedFile)}
+}
+
+// Search returns the contexts matching query, sorted by name. An empty query
+// matches nothing.
+func (ix *ContentIndex) Search(query string) ([]SearchMatch, error) {
+	if err := ix.refresh(); err != nil {
+		return nil, err
+	}
+
+	names := ix.lookup(query)
+	matches := make([]SearchMatch, 0, len(names))
+	for name := range names {
+		matches = append(matches, SearchMatch{Context: name, Paths: ix.matchingPaths(name, query)})
+	}
+	sort.Slice(matches, func(i, j int) bool {
+		return matches[i].Context < matches[j].Context
+	})
+	return matches, nil
+}
+
+// Matches returns the names of the contexts matching query
+func (ix *ContentIndex) Matches(query string) (map[string]bool, error) {
+	if err := ix.refresh(); err != nil {
+		return nil, err
+	}
+	return ix.lookup(query), nil
+}
+
+// refresh re-indexes changed context files and drops removed ones
+func (ix *ContentIndex) refresh() error {
+	contexts, err := ix.manager.ListContexts()
+	if err != nil {
+		return err
+	}
+
+	changed := ix.terms == nil
+	seen := make(map[string]bool, len(contexts))
+	for _, ctx := range contexts {
+		if err := ix.manager.checkCanceled("search contexts"); err != nil {
+			return err
+		}
+		seen[ctx.Name] = true
+		info, err := os.Stat(ctx.FilePath)
+		if err != nil {
+			continue
+		}
+
+		file := ix.files[ctx.Name]
+		if file != nil && file.path == ctx.FilePath && file.modTime.Equal(info.ModTime()) && file.size == info.Size() {
+			continue
+		}
+
+		file = &indexedFile{path: ctx.FilePath, modTime: info.ModTime(), size: info.Size()}
+		if data, err := os.ReadFile(ctx.FilePath); err == nil {
+			// Contexts that don't parse are indexed empty until fixed
+			if content, err := parseContextData(ctx.FilePath, data); err == nil {
+				collectLeaves(nil, nil, content, &file.leaves)
+			}
+		}
+		ix.files[ctx.Name] = file
+		changed = true
+	}
+	for name := range ix.files {
+		if !seen[name] {
+			delete(ix.files, name)
+			changed = true
+		}
+	}
+
+	if changed {
+		ix.rebuild()
+	}
+	return nil
+}
+
+// rebuild recomputes the postings from the indexed files
+func (ix *ContentIndex) rebuild() {
+	ix.terms = make(map[string]map[string]bool)
+	ix.scoped = make(map[string]map[string]map[string]bool)
+
+	add := func(postings map[string]map[string]bool, term, name string) {
+		if postings[term] == nil {
+			postings[term] = make(map[string]bool)
+		}
+		postings[term][name] = true
+	}
+
+	for name, file := range ix.files {
+		for _, leaf := range file.leaves {
+			for i, key := range leaf.keys {
+				add(ix.terms, key, name)
+
+				below := ix.scoped[key]
+				if below == nil {
+					below = make(map[string]map[string]bool)
+					ix.scoped[key] = below
+				}
+				for _, descendant := range leaf.keys[i+1:] {
+					add(below, descendant, name)
+				}
+				add(below, leaf.value, name)
+			}
+			if leaf.value != "" {
+				add(ix.terms, leaf.value, name)
+			}
+		}
+	}
+}
+
+// lookup intersects the contexts matching each term of query
+func (ix *ContentIndex) lookup(query string) map[string]bool {
+	var result map[string]bool
+	for _, term := range strings.Fields(strings.ToLower(query)) {
+		matched := make(map[string]bool)
+		key, text, scoped := ix.splitTerm(term)
+		postings := ix.terms
+		if scoped {
+			postings = ix.scoped[key]
+		}
+		for indexed, names := range postings {
+			if strings.Contains(indexed, text) {
+				for name := range names {
+					matched[name] = true
+				}
+			}
+		}
+
+		if result == nil {
+			result = matched
+			continue
+		}
+		for name := range result {
+			if !matched[name] {
+				delete(result, name)
+			}
+		}
+	}
+	if result == nil {
+		result = map[string]bool{}
+	}
+	return result
+}
+
+// splitTerm splits a lowercased "key:text" term. Terms whose part before the
+// colon isn't an indexed key, such as URLs, are free text.
+func (ix *ContentIndex) splitTerm(term string) (key, text string, scoped bool) {
+	key, text, ok := strings.Cut(term, ":")
+	if !ok || key == "" {
+		return "", term, false
+	}
+	if _, indexed := ix.scoped[key]; !indexed {
+		return "", term, false
+	}
+	return key, text, true
+}
+
+// matchingPaths lists the paths of the context's values matching any term
+func (ix *ContentIndex) matchingPaths(name, query string) []string {
+	file := ix.files[name]
+	if file == nil {
+		return nil
+	}
+
+	terms := strings.Fields(strings.ToLower(query))
+	var paths []string
+	for _, leaf := range file.leaves {
+		for _, term := range terms {
+			if ix.leafMatches(leaf, term) {
+				paths = append(paths, leaf.path)
+				break
+			}
+		}
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// leafMatches reports whether a term matches one value and its keys
+func (ix *ContentIndex) leafMatches(leaf indexLeaf, term string) bool {
+	key, text, scoped := ix.splitTerm(term)
+	if !scoped {
+		if strings.Contains(leaf.value, text) {
+			return true
+		}
+		for _, k := range leaf.keys {
+			if strings.Contains(k, text) {
+				return true
+			}
+		}
+		return false
+	}
+
+	for i, k := range leaf.keys {
+		if k != key {
+			continue
+		}
+		if strings.Contains(leaf.value, text) {
+			return true
+		}
+		for _, descendant := range leaf.keys[i+1:] {
+			if strings.Contains(descendant, text) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// collectLeaves appends the scalar values below node, and empty objects and
+// arrays, with the keys leading to them
+func collectLeaves(path, keys []string, node interface{}, leaves *[]indexLeaf) {
+	switch v := node.(type) {
+	case map[string]interface{}:
+		if len(v) == 0 && len(path) > 0 {
+			*leaves = append(*leaves, indexLeaf{path: strings.Join(path, "."), keys: keys})
+		}
+		for key, child := range v {
+			collectLeaves(append(append([]string{}, path...), key), append(append([]string{}, keys...), strings.ToLower(key)), child, leaves)
+		}
+	case []interface{}:
+		if len(v) == 0 {
+			*leaves = append(*leaves, indexLeaf{path: strings.Join(path, "."), keys: keys})
+		}
+		for i, item := range v {
+			collectLeaves(append(append([]string{}, path...), strconv.Itoa(i)), keys, item, leaves)
+		}
+	case nil:
+		*leaves = append(*leaves, indexLeaf{path: strings.Join(path, "."), keys: keys, value: "null"})
+	default:
+		*leaves = append(*leaves, indexLeaf{path: strings.Join(path, "."), keys: keys, value: strings.ToLower(fmt.Sprint(v))})
+	}
+}
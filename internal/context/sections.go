@@ -0,0 +1,148 @@
+package context
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ApplySections copies the top-level sections of context name, such as
+// "theme" or "agent", into the active config and leaves the rest of it as
+// it is. The context is resolved as a switch resolves it. The current
+// context doesn't change; the active config is recorded as written by
+// occtx, so it isn't reported as drifted. JSONC comments of the active
+// config are not kept.
+func (m *Manager) ApplySections(name string, sections []string) error {
+	if err := m.CheckWritable("apply sections"); err != nil {
+		return err
+	}
+	since := len(m.warned)
+
+	ctx, path, data, content, err := m.resolveSections(name, sections)
+	if err != nil {
+		return err
+	}
+	if err := m.checkApproved(ctx); err != nil {
+		return err
+	}
+	if err := m.checkActiveOwnership(); err != nil {
+		return err
+	}
+	if err := m.enforcePolicy("apply", ctx.Name, content); err != nil {
+		return err
+	}
+	if err := m.checkWarnings("apply", since); err != nil {
+		return err
+	}
+	if err := m.checkCanceled("apply sections"); err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), m.dirMode()); err != nil {
+		return err
+	}
+	if err := m.writeActiveConfig(path, data); err != nil {
+		return err
+	}
+	m.logf(VerbosityVerbose, "applied %s of '%s' to %s", strings.Join(sections, ", "), ctx.Name, path)
+
+	active := fingerprintActive(path, data)
+	return m.updateState(func(state *State) error {
+		state.Active = active
+		return nil
+	})
+}
+
+// PlanApplySections runs the checks of ApplySections and returns the change
+// it would make to the active config
+func (m *Manager) PlanApplySections(name string, sections []string) (*Plan, error) {
+	plan := &Plan{Operation: "apply", Context: name}
+	var ctx *Context
+	var path string
+	var data []byte
+	var content map[string]interface{}
+	if err := m.planChecks(plan, func() error {
+		var err error
+		if ctx, path, data, content, err = m.resolveSections(name, sections); err != nil {
+			return err
+		}
+		if err := m.checkApproved(ctx); err != nil {
+			return err
+		}
+		if err := m.checkActiveOwnership(); err != nil {
+			return err
+		}
+		return m.enforcePolicy("apply", ctx.Name, content)
+	}); err != nil {
+		return nil, err
+	}
+
+	plan.Context = ctx.Name
+	if file, err := m.planActiveConfig(path, data, content); err != nil {
+		return nil, err
+	} else if file != nil {
+		plan.Files = append(plan.Files, *file)
+	}
+	return plan, nil
+}
+
+// resolveSections returns the context, the active config path, and the
+// active config with the sections of the context copied in, encoded and
+// parsed. A section the context doesn't have is an error, as is an active
+// config that doesn't parse.
+func (m *Manager) resolveSections(name string, sections []string) (*Context, string, []byte, map[string]interface{}, error) {
+	if len(sections) == 0 {
+		return nil, "", nil, nil, fmt.Errorf("no sections to apply")
+	}
+	for _, section := range sections {
+		if section == "" || strings.Contains(section, ".") {
+			return nil, "", nil, nil, fmt.Errorf("invalid section '%s': sections are top-level keys such as theme or agent", section)
+		}
+	}
+
+	ctx, err := m.GetContext(name)
+	if err != nil {
+		return nil, "", nil, nil, err
+	}
+	resolved, err := m.resolveForActive(ctx)
+	if err != nil {
+		return nil, "", nil, nil, err
+	}
+	source, err := parseContextData(ctx.FilePath, resolved)
+	if err != nil {
+		return nil, "", nil, nil, fmt.Errorf("context '%s': %v", ctx.Name, err)
+	}
+	var missing []string
+	for _, section := range sections {
+		if _, ok := source[section]; !ok {
+			missing = append(missing, section)
+		}
+	}
+	if len(missing) > 0 {
+		return nil, "", nil, nil, fmt.Errorf("context '%s' has no %s", ctx.Name, strings.Join(missing, ", "))
+	}
+
+	path := m.paths.GetActiveConfigPath(m.useProject)
+	content := map[string]interface{}{}
+	if existing, err := os.ReadFile(path); err == nil {
+		if content, err = parseContextData(path, existing); err != nil {
+			return nil, "", nil, nil, fmt.Errorf("%s: %v; switch to a context instead", path, err)
+		}
+	} else if !os.IsNotExist(err) {
+		return nil, "", nil, nil, err
+	}
+	if content == nil {
+		content = map[string]interface{}{}
+	}
+	for _, section := range sections {
+		content[section] = source[section]
+	}
+
+	data, err := json.MarshalIndent(content, "", "  ")
+	if err != nil {
+		return nil, "", nil, nil, err
+	}
+	return ctx, path, append(data, '\n'), content, nil
+}
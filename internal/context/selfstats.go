@@ -0,0 +1,153 @@
+package context
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// CommandStats is what occtx records about one command. The statistics stay
+// in the occtx config dir; nothing is ever sent anywhere.
+type CommandStats struct {
+	Count    int       `json:"count"`
+	Failures int       `json:"failures,omitempty"`
+	Total    Duration  `json:"total"` // Summed run time
+	Max      Duration  `json:"max"`   // Slowest run
+	LastRun  time.Time `json:"last_run"`
+}
+
+// Mean returns the average run time
+func (s *CommandStats) Mean() time.Duration {
+	if s.Count == 0 {
+		return 0
+	}
+	return time.Duration(s.Total) / time.Duration(s.Count)
+}
+
+// Duration is a time.Duration stored in JSON as a string such as "12.5ms"
+type Duration time.Duration
+
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var text string
+	if err := json.Unmarshal(data, &text); err != nil {
+		return err
+	}
+	parsed, err := time.ParseDuration(text)
+	if err != nil {
+		return err
+	}
+	*d = Duration(parsed)
+	return nil
+}
+
+// SelfStats is the on-disk form of the local command statistics, keyed by
+// command, e.g. "switch" or "models update"
+type SelfStats struct {
+	Since    time.Time                `json:"since"`
+	Commands map[string]*CommandStats `json:"commands"`
+}
+
+// SortedCommands returns the recorded commands, most run first
+func (s *SelfStats) SortedCommands() []string {
+	names := make([]string, 0, len(s.Commands))
+	for name := range s.Commands {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		a, b := s.Commands[names[i]], s.Commands[names[j]]
+		if a.Count != b.Count {
+			return a.Count > b.Count
+		}
+		return names[i] < names[j]
+	})
+	return names
+}
+
+// LoadSelfStats reads the local command statistics; with none recorded yet
+// the result is empty
+func (m *Manager) LoadSelfStats() (*SelfStats, error) {
+	path := m.paths.GetSelfStatsPath()
+	stats := &SelfStats{Commands: make(map[string]*CommandStats)}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return stats, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, stats); err != nil {
+		return nil, fmt.Errorf("invalid statistics file %s: %v (delete it or run 'occtx stats --self --reset')", path, err)
+	}
+	if stats.Commands == nil {
+		stats.Commands = make(map[string]*CommandStats)
+	}
+	return stats, nil
+}
+
+// RecordCommand adds one run of a command to the local statistics. Nothing
+// is recorded in read-only mode or with stats.disabled set in the occtx config.
+func (m *Manager) RecordCommand(command string, elapsed time.Duration, failed bool) error {
+	if m.readOnly || m.config.Stats.Disabled {
+		return nil
+	}
+
+	stats, err := m.LoadSelfStats()
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	if stats.Since.IsZero() {
+		stats.Since = now
+	}
+	entry := stats.Commands[command]
+	if entry == nil {
+		entry = &CommandStats{}
+		stats.Commands[command] = entry
+	}
+	entry.Count++
+	if failed {
+		entry.Failures++
+	}
+	entry.Total += Duration(elapsed)
+	if Duration(elapsed) > entry.Max {
+		entry.Max = Duration(elapsed)
+	}
+	entry.LastRun = now
+
+	return m.saveSelfStats(stats)
+}
+
+// ResetSelfStats forgets the local command statistics
+func (m *Manager) ResetSelfStats() error {
+	if err := m.CheckWritable("reset statistics"); err != nil {
+		return err
+	}
+
+	err := os.Remove(m.paths.GetSelfStatsPath())
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// saveSelfStats writes the local command statistics
+func (m *Manager) saveSelfStats(stats *SelfStats) error {
+	data, err := json.MarshalIndent(stats, "", "  ")
+	if err != nil {
+		return err
+	}
+	path := m.paths.GetSelfStatsPath()
+	if err := os.MkdirAll(filepath.Dir(path), m.dirMode()); err != nil {
+		return err
+	}
+	return writeFileAtomic(path, data, m.fileMode())
+}
@@ -0,0 +1,103 @@
+package context
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+)
+
+const (
+	// SessionContextEnvVar names the context of the current terminal session,
+	// overriding the global current context for `occtx current`
+	SessionContextEnvVar = "OCCTX_ACTIVE_CONTEXT"
+	// OpencodeConfigEnvVar points opencode at a config file other than opencode.json
+	OpencodeConfigEnvVar = "OPENCODE_CONFIG"
+)
+
+// SessionConfig is a per-terminal copy of a context, used instead of the
+// global active config
+type SessionConfig struct {
+	Context string // Resolved context name
+	Path    string // Config file to pass via OpencodeConfigEnvVar
+	vars    []string
+	dir     string
+}
+
+// Env returns the environment variables that select this session config,
+// followed by the ones the context declares
+func (s *SessionConfig) Env() []string {
+	return append([]string{
+		SessionContextEnvVar + "=" + s.Context,
+		OpencodeConfigEnvVar + "=" + s.Path,
+	}, s.vars...)
+}
+
+// Remove deletes the session config file
+func (s *SessionConfig) Remove() error {
+	return os.RemoveAll(s.dir)
+}
+
+// SessionContext returns the context selected for this terminal session, if any
+func SessionContext() string {
+	return os.Getenv(SessionContextEnvVar)
+}
+
+// EffectiveCurrentContext returns the session context when one is set,
+// otherwise the current context from the state file
+func (m *Manager) EffectiveCurrentContext() (name string, session bool, err error) {
+	if name := SessionContext(); name != "" {
+		return name, true, nil
+	}
+
+	name, err = m.GetCurrentContext()
+	return name, false, err
+}
+
+// NewSessionConfig writes a context to a private temporary file so a single
+// terminal or command can use it without touching the global active config.
+// The same approval and policy checks as switching apply. Call Remove when
+// the session ends.
+func (m *Manager) NewSessionConfig(name string) (*SessionConfig, error) {
+	ctx, err := m.GetContext(name)
+	if err != nil {
+		return nil, err
+	}
+
+	since := len(m.warned)
+	if err := m.checkApproved(ctx); err != nil {
+		return nil, err
+	}
+	if _, err := m.resolveForActive(ctx); err != nil {
+		return nil, err
+	}
+	if err := m.enforcePolicy("switch", ctx.Name, ctx.Data); err != nil {
+		return nil, err
+	}
+	if err := m.checkWarnings("session", since); err != nil {
+		return nil, err
+	}
+	vars, err := m.sessionEnv(ctx.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	// Contexts may hold secrets, so the directory is private to the user
+	dir, err := os.MkdirTemp("", "occtx-session-*")
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := json.MarshalIndent(ctx.Data, "", "  ")
+	if err != nil {
+		os.RemoveAll(dir)
+		return nil, err
+	}
+
+	session := &SessionConfig{Context: ctx.Name, Path: filepath.Join(dir, "opencode.json"), vars: vars, dir: dir}
+	if err := os.WriteFile(session.Path, data, privateFileMode); err != nil {
+		os.RemoveAll(dir)
+		return nil, writeError(session.Path, len(data), err)
+	}
+
+	return session, nil
+}
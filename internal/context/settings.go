@@ -0,0 +1,215 @@
+package context
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/hungthai1401/occtx/internal/config"
+)
+
+// SettingsBundleVersion is the format version written by ExportSettings
+const SettingsBundleVersion = 1
+
+// SettingsBundle carries occtx's own configuration between machines: the
+// occtx config file, the pinned default context and the per-context metadata
+// (sources, approvals). The contexts themselves travel separately, e.g. with
+// --export or a backup.
+type SettingsBundle struct {
+	Version    int                     `json:"version"`
+	ExportedAt time.Time               `json:"exported_at"`
+	Config     json.RawMessage         `json:"config,omitempty"`  // The occtx config file, verbatim
+	Default    string                  `json:"default,omitempty"` // Pinned default context of the scope
+	Contexts   map[string]*ContextMeta `json:"contexts,omitempty"`
+}
+
+// SettingsImportResult reports what ImportSettings applied
+type SettingsImportResult struct {
+	ConfigWritten bool     // The occtx config file was written
+	Default       string   // Default context pinned, if any
+	Metadata      int      // Contexts whose metadata was restored
+	Skipped       []string // Contexts in the bundle that don't exist here
+}
+
+// ExportSettings collects the occtx config file and the scope's pins and
+// metadata into a bundle
+func (m *Manager) ExportSettings() (*SettingsBundle, error) {
+	bundle := &SettingsBundle{
+		Version:    SettingsBundleVersion,
+		ExportedAt: time.Now().UTC(),
+	}
+
+	data, err := os.ReadFile(m.paths.ConfigFile)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	if len(bytes.TrimSpace(data)) > 0 {
+		if !json.Valid(data) {
+			return nil, fmt.Errorf("invalid occtx config %s", m.paths.ConfigFile)
+		}
+		var compact bytes.Buffer
+		if err := json.Compact(&compact, data); err != nil {
+			return nil, err
+		}
+		bundle.Config = compact.Bytes()
+	}
+
+	state, err := m.loadState()
+	if err != nil {
+		return nil, err
+	}
+	bundle.Default = state.Default
+
+	meta, err := m.loadMetadata()
+	if err != nil {
+		return nil, err
+	}
+	if len(meta.Contexts) > 0 {
+		bundle.Contexts = meta.Contexts
+	}
+
+	return bundle, nil
+}
+
+// ParseSettingsBundle decodes and checks a bundle written by ExportSettings
+func ParseSettingsBundle(data []byte) (*SettingsBundle, error) {
+	var bundle SettingsBundle
+	if err := json.Unmarshal(data, &bundle); err != nil {
+		return nil, fmt.Errorf("invalid settings bundle: %v", err)
+	}
+	if bundle.Version == 0 || bundle.Version > SettingsBundleVersion {
+		return nil, fmt.Errorf("unsupported settings bundle version %d (this occtx reads version %d)", bundle.Version, SettingsBundleVersion)
+	}
+
+	if len(bundle.Config) > 0 {
+		var cfg config.Config
+		if err := json.Unmarshal(bundle.Config, &cfg); err != nil {
+			return nil, fmt.Errorf("invalid occtx config in settings bundle: %v", err)
+		}
+	}
+	if err := bundle.checkMetadata(); err != nil {
+		return nil, err
+	}
+
+	return &bundle, nil
+}
+
+// checkMetadata applies the checks of the commands that set metadata (env,
+// requires, tag) to the bundle's, since a bundle may come from anywhere
+func (b *SettingsBundle) checkMetadata() error {
+	for name, entry := range b.Contexts {
+		if err := validateContextName(name); err != nil {
+			return fmt.Errorf("invalid settings bundle: %v", err)
+		}
+		if entry == nil {
+			continue
+		}
+		for key := range entry.Env {
+			if err := validateEnvName(key); err != nil {
+				return fmt.Errorf("invalid settings bundle: context '%s': %v", name, err)
+			}
+		}
+		for _, requirement := range entry.Requires {
+			if err := ValidateRequirement(requirement); err != nil {
+				return fmt.Errorf("invalid settings bundle: context '%s': %v", name, err)
+			}
+		}
+		for key := range entry.Tags {
+			if err := validateTagKey(key); err != nil {
+				return fmt.Errorf("invalid settings bundle: context '%s': %v", name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// ImportSettings applies a bundle. An existing occtx config file that differs
+// from the bundle's is only replaced with force. Pins and metadata are
+// restored for contexts that exist in this scope; the others are reported as
+// skipped, so contexts should be restored first.
+func (m *Manager) ImportSettings(bundle *SettingsBundle, force bool) (*SettingsImportResult, error) {
+	if err := m.CheckWritable("import settings"); err != nil {
+		return nil, err
+	}
+	if err := bundle.checkMetadata(); err != nil {
+		return nil, err
+	}
+
+	result := &SettingsImportResult{}
+
+	if len(bundle.Config) > 0 {
+		var formatted bytes.Buffer
+		if err := json.Indent(&formatted, bundle.Config, "", "  "); err != nil {
+			return nil, fmt.Errorf("invalid occtx config in settings bundle: %v", err)
+		}
+		formatted.WriteByte('\n')
+
+		existing, err := os.ReadFile(m.paths.ConfigFile)
+		if err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+		switch {
+		case err == nil && sameJSON(existing, bundle.Config):
+			// Nothing to do
+		case err == nil && !force:
+			return nil, fmt.Errorf("occtx config %s already exists and differs from the bundle (use --force to replace it)", m.paths.ConfigFile)
+		default:
+			if err := os.MkdirAll(filepath.Dir(m.paths.ConfigFile), m.dirMode()); err != nil {
+				return nil, err
+			}
+			if err := writeFileAtomic(m.paths.ConfigFile, formatted.Bytes(), m.fileMode()); err != nil {
+				return nil, err
+			}
+			result.ConfigWritten = true
+		}
+	}
+
+	skipped := make(map[string]bool)
+	exists := func(name string) bool {
+		if _, err := m.GetContext(name); err != nil {
+			skipped[name] = true
+			return false
+		}
+		return true
+	}
+
+	if len(bundle.Contexts) > 0 {
+		err := m.updateMetadata(func(meta *metadataFile) {
+			for name, entry := range bundle.Contexts {
+				if entry != nil && exists(name) {
+					meta.Contexts[name] = entry
+					result.Metadata++
+				}
+			}
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if bundle.Default != "" && exists(bundle.Default) {
+		if err := m.SetDefaultContext(bundle.Default); err != nil {
+			return nil, err
+		}
+		result.Default = bundle.Default
+	}
+
+	for name := range skipped {
+		result.Skipped = append(result.Skipped, name)
+	}
+	sort.Strings(result.Skipped)
+	return result, nil
+}
+
+// sameJSON reports whether two JSON documents are equal ignoring formatting
+func sameJSON(a, b []byte) bool {
+	var compactA, compactB bytes.Buffer
+	if json.Compact(&compactA, a) != nil || json.Compact(&compactB, b) != nil {
+		return false
+	}
+	return bytes.Equal(compactA.Bytes(), compactB.Bytes())
+}
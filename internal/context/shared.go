@@ -0,0 +1,103 @@
+package context
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/hungthai1401/occtx/internal/config"
+)
+
+// sharedSource is a read-only context directory: one of shared_dirs, or a
+// mirror of a mounted folder when mirror is set
+type sharedSource struct {
+	dir    string
+	mirror string // Name of the mirror, shown as the badge of its contexts
+}
+
+// sharedSources returns the existing read-only context directories for this
+// scope, shared directories first, then mirrors in configured order. Shared
+// contexts only apply to the global level.
+func (m *Manager) sharedSources() []sharedSource {
+	if m.useProject {
+		return nil
+	}
+
+	var sources []sharedSource
+	add := func(dir, mirror string) {
+		expanded, err := config.ExpandHome(dir)
+		if err != nil {
+			return
+		}
+		// A mirror whose mount is gone is skipped like a missing directory
+		if info, err := os.Stat(expanded); err == nil && info.IsDir() {
+			sources = append(sources, sharedSource{dir: expanded, mirror: mirror})
+		}
+	}
+	for _, dir := range m.config.EffectiveSharedDirs() {
+		add(dir, "")
+	}
+	for _, mirror := range m.config.Mirrors {
+		add(mirror.Dir, mirror.Name)
+	}
+	return sources
+}
+
+// sharedDirs returns the directories of sharedSources
+func (m *Manager) sharedDirs() []string {
+	var dirs []string
+	for _, source := range m.sharedSources() {
+		dirs = append(dirs, source.dir)
+	}
+	return dirs
+}
+
+// appendSharedContexts adds shared and mirrored contexts not shadowed by a
+// local context (or an earlier shared directory or mirror) of the same name
+func (m *Manager) appendSharedContexts(contexts []*Context) []*Context {
+	seen := make(map[string]bool, len(contexts))
+	for _, ctx := range contexts {
+		seen[ctx.Name] = true
+	}
+
+	for _, source := range m.sharedSources() {
+		shared, err := listContextsIn(source.dir)
+		if err != nil {
+			// An unreadable mount shouldn't break listing local contexts
+			continue
+		}
+		for _, ctx := range shared {
+			if seen[ctx.Name] {
+				continue
+			}
+			seen[ctx.Name] = true
+			ctx.Shared = true
+			ctx.Mirror = source.mirror
+			contexts = append(contexts, ctx)
+		}
+	}
+
+	return contexts
+}
+
+// findSharedContext returns the path of a shared context file, if there is
+// one, and the mirror it is in
+func (m *Manager) findSharedContext(name string) (path, mirror string, ok bool) {
+	for _, source := range m.sharedSources() {
+		for _, format := range GetAllFormats() {
+			path := filepath.Join(source.dir, name+format.FileExtension())
+			if _, err := os.Stat(path); err == nil {
+				return path, source.mirror, true
+			}
+		}
+	}
+	return "", "", false
+}
+
+// CheckModifiable rejects changing a shared context in place
+func (c *Context) CheckModifiable(operation string) error {
+	if c.Shared {
+		return fmt.Errorf("cannot %s '%s': it is a shared read-only context (copy it locally with 'occtx copy %s')", operation, c.Name, c.Name)
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+package context
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// OpencodeSchemaURL is the JSON schema opencode configs declare
+const OpencodeSchemaURL = "https://opencode.ai/config.json"
+
+// defaultModels are the models used when a skeleton names a provider but no model
+var defaultModels = map[string]string{
+	"anthropic": "claude-sonnet-4-20250514",
+	"openai":    "gpt-4.1",
+	"google":    "gemini-2.5-pro",
+}
+
+// SkeletonOptions selects what goes into a built-in skeleton config
+type SkeletonOptions struct {
+	Provider string // e.g. "anthropic"; optional
+	Model    string // Model ID, or "provider/model" when Provider is empty
+}
+
+// Skeleton returns a minimal valid opencode config for the options
+func Skeleton(opts SkeletonOptions) (map[string]interface{}, error) {
+	data := map[string]interface{}{
+		"$schema": OpencodeSchemaURL,
+	}
+
+	model := opts.Model
+	switch {
+	case opts.Provider == "" && model == "":
+		return data, nil
+	case opts.Provider == "":
+		if !strings.Contains(model, "/") {
+			return nil, fmt.Errorf("model '%s' needs a provider: use --provider or 'provider/model'", model)
+		}
+	case model == "":
+		defaultModel, ok := defaultModels[opts.Provider]
+		if !ok {
+			return nil, fmt.Errorf("no default model for provider '%s' (known: %s); pass --model", opts.Provider, strings.Join(SkeletonProviders(), ", "))
+		}
+		model = opts.Provider + "/" + defaultModel
+	default:
+		model = opts.Provider + "/" + strings.TrimPrefix(model, opts.Provider+"/")
+	}
+
+	data["model"] = model
+	return data, nil
+}
+
+// SkeletonProviders returns the providers with a built-in default model
+func SkeletonProviders() []string {
+	providers := make([]string, 0, len(defaultModels))
+	for provider := range defaultModels {
+		providers = append(providers, provider)
+	}
+	sort.Strings(providers)
+	return providers
+}
+
+// CreateContextFromSkeleton creates a new context from a built-in skeleton,
+// without needing an active opencode.json
+func (m *Manager) CreateContextFromSkeleton(name string, format ContextFormat, opts SkeletonOptions) error {
+	if err := m.CheckWritable("create context"); err != nil {
+		return err
+	}
+
+	jsonData, err := Skeleton(opts)
+	if err != nil {
+		return err
+	}
+
+	if err := m.prepareNewContext(name); err != nil {
+		return err
+	}
+
+	return m.writeNewContext(name, format, jsonData, Source{Kind: SourceSkeleton})
+}
@@ -0,0 +1,279 @@
+package context
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hungthai1401/occtx/internal/util"
+)
+
+// stashIndexFileName is the metadata file kept inside the stash directory
+const stashIndexFileName = "index.json"
+
+// StashEntry describes a stashed copy of the active config
+type StashEntry struct {
+	Name      string    `json:"name"`
+	Context   string    `json:"context,omitempty"` // Context that was current when stashing
+	File      string    `json:"file"`              // File name inside the stash directory
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// stashIndex holds stash entries, newest first (like git's stash@{0})
+type stashIndex struct {
+	Entries []StashEntry `json:"entries"`
+}
+
+// StashSave parks the active config under the given name (generated if empty)
+// and restores the active config from the current context, if any
+func (m *Manager) StashSave(name string) (*StashEntry, error) {
+	if err := m.CheckWritable("stash active config"); err != nil {
+		return nil, err
+	}
+
+	if name == "" {
+		name = "stash-" + time.Now().Format("20060102-150405")
+	}
+	if err := validateContextName(name); err != nil {
+		return nil, err
+	}
+
+	activeConfigPath := m.paths.GetActiveConfigPath(m.useProject)
+	data, err := os.ReadFile(activeConfigPath)
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("no active opencode.json found at %s", activeConfigPath)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	index, err := m.loadStashIndex()
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range index.Entries {
+		if entry.Name == name {
+			return nil, fmt.Errorf("stash '%s' already exists", name)
+		}
+	}
+
+	stashDir := m.paths.GetStashDir(m.useProject)
+	if err := os.MkdirAll(stashDir, m.dirMode()); err != nil {
+		return nil, err
+	}
+
+	current, err := m.GetCurrentContext()
+	if err != nil {
+		return nil, err
+	}
+
+	// Keep the extension so a JSONC config is restored as JSONC
+	extension := FormatJSON.FileExtension()
+	if util.IsJSONCPath(activeConfigPath) {
+		extension = FormatJSONC.FileExtension()
+	}
+	entry := StashEntry{
+		Name:      name,
+		Context:   current,
+		File:      name + extension,
+		CreatedAt: time.Now(),
+	}
+
+	if err := writeFileAtomic(filepath.Join(stashDir, entry.File), data, m.fileMode()); err != nil {
+		return nil, err
+	}
+
+	index.Entries = append([]StashEntry{entry}, index.Entries...)
+	if err := m.saveStashIndex(index); err != nil {
+		return nil, err
+	}
+
+	// Revert the active config to the current context, like git stash resets to HEAD
+	if current != "" {
+		if ctx, err := m.GetContext(current); err == nil {
+			path, contextData, err := m.activeConfigFor(ctx)
+			if err != nil {
+				return nil, err
+			}
+			if err := m.writeActiveConfig(path, contextData); err != nil {
+				return nil, err
+			}
+			if err := m.retireActiveConfig(path); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return &entry, nil
+}
+
+// ListStashes returns all stash entries, newest first
+func (m *Manager) ListStashes() ([]StashEntry, error) {
+	index, err := m.loadStashIndex()
+	if err != nil {
+		return nil, err
+	}
+
+	return index.Entries, nil
+}
+
+// ApplyStash writes the referenced stash to the active config and keeps the entry
+func (m *Manager) ApplyStash(ref string) (*StashEntry, error) {
+	if err := m.CheckWritable("apply stash"); err != nil {
+		return nil, err
+	}
+
+	index, err := m.loadStashIndex()
+	if err != nil {
+		return nil, err
+	}
+
+	i, err := resolveStashRef(index, ref)
+	if err != nil {
+		return nil, err
+	}
+	entry := index.Entries[i]
+
+	data, err := os.ReadFile(filepath.Join(m.paths.GetStashDir(m.useProject), entry.File))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read stash '%s': %v", entry.Name, err)
+	}
+
+	// A JSONC stash goes back to opencode.jsonc unless the active config
+	// must be plain JSON
+	comments := util.IsJSONCPath(entry.File)
+	activeConfigPath, keepComments := m.activeTarget(comments)
+	if comments && !keepComments {
+		parsed, err := util.ParseJSONC(data)
+		if err != nil {
+			return nil, fmt.Errorf("invalid stash '%s': %v", entry.Name, err)
+		}
+		if data, err = json.MarshalIndent(parsed, "", "  "); err != nil {
+			return nil, err
+		}
+		data = append(data, '\n')
+	}
+	if err := os.MkdirAll(filepath.Dir(activeConfigPath), m.dirMode()); err != nil {
+		return nil, err
+	}
+
+	if err := m.writeActiveConfig(activeConfigPath, data); err != nil {
+		return nil, err
+	}
+	if err := m.retireActiveConfig(activeConfigPath); err != nil {
+		return nil, err
+	}
+
+	return &entry, nil
+}
+
+// PopStash applies the referenced stash and then drops it
+func (m *Manager) PopStash(ref string) (*StashEntry, error) {
+	entry, err := m.ApplyStash(ref)
+	if err != nil {
+		return nil, err
+	}
+
+	return m.DropStash(entry.Name)
+}
+
+// DropStash removes the referenced stash entry and its file
+func (m *Manager) DropStash(ref string) (*StashEntry, error) {
+	if err := m.CheckWritable("drop stash"); err != nil {
+		return nil, err
+	}
+
+	index, err := m.loadStashIndex()
+	if err != nil {
+		return nil, err
+	}
+
+	i, err := resolveStashRef(index, ref)
+	if err != nil {
+		return nil, err
+	}
+	entry := index.Entries[i]
+
+	stashPath := filepath.Join(m.paths.GetStashDir(m.useProject), entry.File)
+	if err := os.Remove(stashPath); err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	index.Entries = append(index.Entries[:i], index.Entries[i+1:]...)
+	if err := m.saveStashIndex(index); err != nil {
+		return nil, err
+	}
+
+	return &entry, nil
+}
+
+// resolveStashRef finds a stash by name, position ("1") or git-style ref ("stash@{1}").
+// An empty ref selects the most recent stash.
+func resolveStashRef(index *stashIndex, ref string) (int, error) {
+	if len(index.Entries) == 0 {
+		return 0, fmt.Errorf("no stashes found")
+	}
+
+	if ref == "" {
+		return 0, nil
+	}
+
+	for i, entry := range index.Entries {
+		if entry.Name == ref {
+			return i, nil
+		}
+	}
+
+	position := ref
+	if strings.HasPrefix(ref, "stash@{") && strings.HasSuffix(ref, "}") {
+		position = strings.TrimSuffix(strings.TrimPrefix(ref, "stash@{"), "}")
+	}
+
+	if i, err := strconv.Atoi(position); err == nil {
+		if i < 0 || i >= len(index.Entries) {
+			return 0, fmt.Errorf("stash@{%d} does not exist", i)
+		}
+		return i, nil
+	}
+
+	return 0, fmt.Errorf("stash '%s' not found", ref)
+}
+
+// loadStashIndex reads the stash index, returning an empty index if none exists
+func (m *Manager) loadStashIndex() (*stashIndex, error) {
+	indexPath := filepath.Join(m.paths.GetStashDir(m.useProject), stashIndexFileName)
+
+	data, err := os.ReadFile(indexPath)
+	if os.IsNotExist(err) {
+		return &stashIndex{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var index stashIndex
+	if err := json.Unmarshal(data, &index); err != nil {
+		return nil, fmt.Errorf("invalid stash index %s: %v", indexPath, err)
+	}
+
+	return &index, nil
+}
+
+// saveStashIndex writes the stash index atomically
+func (m *Manager) saveStashIndex(index *stashIndex) error {
+	stashDir := m.paths.GetStashDir(m.useProject)
+	if err := os.MkdirAll(stashDir, m.dirMode()); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(index, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return writeFileAtomic(filepath.Join(stashDir, stashIndexFileName), data, m.fileMode())
+}
@@ -1,42 +1,222 @@
 package context
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
+	"time"
+
+	"github.com/hungthai1401/occtx/internal/config"
 )
 
+// stateUpdateAttempts bounds how often updateState retries after another
+// process changed the state file first
+const stateUpdateAttempts = 5
+
+// ErrStateConflict is returned by SaveState when the state file changed
+// since it was loaded, e.g. by two switches run at the same time
+var ErrStateConflict = errors.New("state file was changed by another occtx process")
+
+// errStateUnchanged lets an updateState change skip saving
+var errStateUnchanged = errors.New("state unchanged")
+
+// stateBackupSuffix names the copy of the last good state kept next to the
+// state file, used to recover from a corrupt one
+const stateBackupSuffix = ".bak"
+
 // State represents the current state of occtx (current and previous context)
 type State struct {
-	Current  string `json:"current,omitempty"`
-	Previous string `json:"previous,omitempty"`
+	Current   string           `json:"current,omitempty"`
+	Previous  string           `json:"previous,omitempty"`
+	Default   string           `json:"default,omitempty"` // Pinned context `occtx reset` returns to
+	Temporary *TemporarySwitch `json:"temporary,omitempty"`
+	Active    *ActiveRecord    `json:"active,omitempty"`
+	Listing   []string         `json:"listing,omitempty"` // Names in the order 'occtx ls' last numbered them
+
+	// Recovery is set when LoadState found the state file corrupt
+	Recovery *StateRecovery `json:"-"`
+
+	// The checksum of the file content this state was loaded from ("" if
+	// there was no file), checked by SaveState to detect concurrent updates
+	loaded   bool
+	baseline string
+
+	// Modes SaveState writes with, from the permissions config; zero uses the defaults
+	fileMode os.FileMode
+	dirMode  os.FileMode
+}
+
+// ActiveRecord fingerprints the active config as written by the last switch,
+// so later edits to it can be detected
+type ActiveRecord struct {
+	Checksum string    `json:"checksum"`
+	ModTime  time.Time `json:"mod_time"`
+}
+
+// TemporarySwitch records a context switch that reverts once it expires
+type TemporarySwitch struct {
+	Context   string    `json:"context"`
+	RevertTo  string    `json:"revert_to,omitempty"` // Empty means unset on revert
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// Expired reports whether the temporary switch should be reverted
+func (t *TemporarySwitch) Expired(now time.Time) bool {
+	return !now.Before(t.ExpiresAt)
+}
+
+// StateRecovery describes how a corrupt state file was dealt with
+type StateRecovery struct {
+	Cause         string // Why the file could not be parsed
+	QuarantinedTo string // Where the corrupt file was moved
+	FromBackup    bool   // Whether the last good state was restored
+}
+
+// String describes the recovery for a warning
+func (r *StateRecovery) String() string {
+	if r.QuarantinedTo == "" {
+		if r.FromBackup {
+			return fmt.Sprintf("state file is corrupt (%s); using the last backup without repairing it in read-only mode", r.Cause)
+		}
+		return fmt.Sprintf("state file is corrupt (%s); no backup is usable, so no context is current, and it is left as is in read-only mode", r.Cause)
+	}
+	if r.FromBackup {
+		return fmt.Sprintf("state file was corrupt (%s); moved it to %s and restored the last backup", r.Cause, r.QuarantinedTo)
+	}
+	return fmt.Sprintf("state file was corrupt (%s); moved it to %s, no backup was usable so current and previous contexts were reset", r.Cause, r.QuarantinedTo)
 }
 
-// LoadState loads the state from the state file
+// LoadState loads the state from the state file. A corrupt state file is
+// moved aside to <file>.corrupt-<timestamp> and the last backup restored;
+// the returned state's Recovery says what happened.
 func LoadState(stateFilePath string) (*State, error) {
+	return loadStateFile(stateFilePath, true, config.DefaultFileMode)
+}
+
+// loadStateFile is LoadState, restoring the backup of a corrupt state file
+// with fileMode. Without repair, a corrupt file is left alone and the backup
+// only read.
+func loadStateFile(stateFilePath string, repair bool, fileMode os.FileMode) (*State, error) {
 	// If state file doesn't exist, return empty state
-	if _, err := os.Stat(stateFilePath); os.IsNotExist(err) {
-		return &State{}, nil
+	data, err := os.ReadFile(stateFilePath)
+	if os.IsNotExist(err) {
+		return &State{loaded: true}, nil
+	}
+	if err != nil {
+		return nil, err
 	}
 
+	var state State
+	if err := json.Unmarshal(data, &state); err != nil {
+		if !repair {
+			return readBackupState(stateFilePath, data, err), nil
+		}
+		return recoverState(stateFilePath, err, fileMode)
+	}
+
+	state.loaded = true
+	state.baseline = checksum(data)
+	return &state, nil
+}
+
+// peekState reads a state file without repairing it, for looking at other
+// scopes; a missing or corrupt file reads as an empty state
+func peekState(stateFilePath string) (*State, error) {
 	data, err := os.ReadFile(stateFilePath)
+	if os.IsNotExist(err) {
+		return &State{}, nil
+	}
 	if err != nil {
 		return nil, err
 	}
 
 	var state State
 	if err := json.Unmarshal(data, &state); err != nil {
-		// If JSON is invalid, return empty state instead of failing
 		return &State{}, nil
 	}
-
 	return &state, nil
 }
 
-// SaveState saves the state to the state file
+// recoverState quarantines a corrupt state file and restores the backup, if
+// any, with fileMode
+func recoverState(stateFilePath string, cause error, fileMode os.FileMode) (*State, error) {
+	quarantine := stateFilePath + ".corrupt-" + time.Now().UTC().Format("20060102T150405Z")
+	if err := os.Rename(stateFilePath, quarantine); err != nil {
+		if os.IsNotExist(err) {
+			// Another occtx process got there first
+			return loadStateFile(stateFilePath, true, fileMode)
+		}
+		return nil, fmt.Errorf("state file %s is corrupt (%v) and could not be moved aside: %v", stateFilePath, cause, err)
+	}
+
+	recovery := &StateRecovery{Cause: cause.Error(), QuarantinedTo: quarantine}
+	state := &State{}
+
+	backup, err := os.ReadFile(stateFilePath + stateBackupSuffix)
+	if err == nil && json.Unmarshal(backup, state) == nil {
+		if err := writeFileAtomic(stateFilePath, backup, fileMode); err != nil {
+			return nil, err
+		}
+		recovery.FromBackup = true
+		state.baseline = checksum(backup)
+	} else {
+		state = &State{}
+	}
+
+	state.loaded = true
+	state.Recovery = recovery
+	return state, nil
+}
+
+// readBackupState is the state of a corrupt state file holding data when it
+// can't be repaired: the backup if it is usable, else an empty state. The
+// baseline stays that of the corrupt file, so nothing is saved over it unseen.
+func readBackupState(stateFilePath string, data []byte, cause error) *State {
+	recovery := &StateRecovery{Cause: cause.Error()}
+	state := &State{}
+	backup, err := os.ReadFile(stateFilePath + stateBackupSuffix)
+	if err == nil && json.Unmarshal(backup, state) == nil {
+		recovery.FromBackup = true
+	} else {
+		state = &State{}
+	}
+
+	state.loaded = true
+	state.baseline = checksum(data)
+	state.Recovery = recovery
+	return state
+}
+
+// loadState loads the scope's state file, warning if it had to be recovered
+func (m *Manager) loadState() (*State, error) {
+	state, err := loadStateFile(m.paths.GetStateFilePath(m.useProject), !m.readOnly, m.fileMode())
+	if err != nil {
+		return nil, err
+	}
+	if state.Recovery != nil {
+		m.warnf("%s", state.Recovery)
+	}
+	state.fileMode, state.dirMode = m.fileMode(), m.dirMode()
+	return state, nil
+}
+
+// SaveState saves the state to the state file. A state returned by LoadState
+// is only saved if the file still holds what was loaded; otherwise
+// ErrStateConflict is returned and nothing is written.
 func (s *State) SaveState(stateFilePath string) error {
+	fileMode, dirMode := s.fileMode, s.dirMode
+	if fileMode == 0 {
+		fileMode = config.DefaultFileMode
+	}
+	if dirMode == 0 {
+		dirMode = config.DefaultDirMode
+	}
+
 	// Ensure the directory exists
-	if err := os.MkdirAll(filepath.Dir(stateFilePath), 0755); err != nil {
+	if err := os.MkdirAll(filepath.Dir(stateFilePath), dirMode); err != nil {
 		return err
 	}
 
@@ -45,26 +225,102 @@ func (s *State) SaveState(stateFilePath string) error {
 		return err
 	}
 
-	// Write atomically by writing to temp file first
-	tempFile := stateFilePath + ".tmp"
-	if err := os.WriteFile(tempFile, data, 0644); err != nil {
+	// Compare and swap: as late as possible, check nobody saved since we loaded
+	err = writeFileAtomicIf(stateFilePath, data, fileMode, func() error {
+		if !s.loaded {
+			return nil
+		}
+
+		current := ""
+		onDisk, err := os.ReadFile(stateFilePath)
+		switch {
+		case err == nil:
+			current = checksum(onDisk)
+		case !os.IsNotExist(err):
+			return err
+		}
+		if current != s.baseline {
+			return fmt.Errorf("%w (%s)", ErrStateConflict, stateFilePath)
+		}
+		return nil
+	})
+	if err != nil {
 		return err
 	}
 
-	// Move temp file to actual location
-	return os.Rename(tempFile, stateFilePath)
+	s.loaded = true
+	s.baseline = checksum(data)
+
+	// Keep a copy of the last good state for recoverState. Failing to write it
+	// (e.g. on a full disk) must not fail the save that already succeeded.
+	_ = writeFileAtomic(stateFilePath+stateBackupSuffix, data, fileMode)
+	return nil
+}
+
+// updateState loads the state, applies change and saves it. If another
+// process saved the state in between, the update is retried on the fresh
+// state. change may return errStateUnchanged to skip saving.
+func (m *Manager) updateState(change func(state *State) error) error {
+	stateFilePath := m.paths.GetStateFilePath(m.useProject)
+
+	for attempt := 1; ; attempt++ {
+		state, err := m.loadState()
+		if err != nil {
+			return err
+		}
+
+		if err := change(state); err != nil {
+			if errors.Is(err, errStateUnchanged) {
+				return nil
+			}
+			return err
+		}
+
+		err = state.SaveState(stateFilePath)
+		if err == nil {
+			return m.writeCurrentMarker(state.Current)
+		}
+		if !errors.Is(err, ErrStateConflict) || attempt == stateUpdateAttempts {
+			return err
+		}
+		m.logf(VerbosityDebug, "state changed concurrently, retrying (%d/%d)", attempt, stateUpdateAttempts)
+	}
+}
+
+// writeCurrentMarker mirrors the current context name into the marker file,
+// "name\n", or removes it when no context is current. It is only rewritten
+// when the name changes, so tools watching it see one event per switch.
+func (m *Manager) writeCurrentMarker(current string) error {
+	markerPath := m.paths.GetCurrentMarkerPath(m.useProject)
+	if current == "" {
+		if err := os.Remove(markerPath); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	}
+
+	content := []byte(current + "\n")
+	if existing, err := os.ReadFile(markerPath); err == nil && bytes.Equal(existing, content) {
+		return nil
+	}
+	m.logf(VerbosityDebug, "wrote current context '%s' to %s", current, markerPath)
+	return writeFileAtomic(markerPath, content, m.fileMode())
 }
 
-// SetCurrent updates the current context and moves old current to previous
+// SetCurrent updates the current context, moves old current to previous
+// and cancels any pending temporary switch
 func (s *State) SetCurrent(contextName string) {
 	s.Previous = s.Current
 	s.Current = contextName
+	s.Temporary = nil
 }
 
 // Unset clears the current context but keeps previous
 func (s *State) Unset() {
 	s.Previous = s.Current
 	s.Current = ""
+	s.Temporary = nil
+	s.Active = nil
 }
 
 // SwitchToPrevious switches current and previous
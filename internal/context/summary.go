@@ -0,0 +1,157 @@
+package context
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+
+	"github.com/hungthai1401/occtx/internal/util"
+)
+
+// Summary is what a long listing shows about a context's contents
+type Summary struct {
+	Model     string   `json:"model,omitempty"`     // Top-level "model"
+	Providers []string `json:"providers,omitempty"` // Keys of "provider", sorted
+}
+
+// SummarizeContext reads the model and provider names of a context. The file
+// is read as a token stream: other values are skipped without being decoded,
+// and reading stops once both keys are seen, so large contexts stay cheap to
+// list.
+func SummarizeContext(ctx *Context) (*Summary, error) {
+	file, err := os.Open(ctx.FilePath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var r io.Reader = bufio.NewReader(file)
+	if ctx.Format == FormatJSONC {
+		// Comment lines can't be skipped by the decoder
+		data, err := io.ReadAll(r)
+		if err != nil {
+			return nil, err
+		}
+		r = bytes.NewReader(util.StripJSONCComments(data))
+	}
+
+	summary, err := readSummary(json.NewDecoder(r))
+	if err != nil {
+		return nil, fmt.Errorf("invalid JSON in context '%s': %v", ctx.Name, err)
+	}
+	return summary, nil
+}
+
+// readSummary reads the top-level "model" and "provider" keys from dec
+func readSummary(dec *json.Decoder) (*Summary, error) {
+	if err := expectDelim(dec, '{'); err != nil {
+		return nil, err
+	}
+
+	summary := &Summary{}
+	var seenModel, seenProvider bool
+	for dec.More() && !(seenModel && seenProvider) {
+		key, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		switch key {
+		case "model":
+			seenModel = true
+			token, err := dec.Token()
+			if err != nil {
+				return nil, err
+			}
+			if model, ok := token.(string); ok {
+				summary.Model = model
+			} else if err := skipRest(dec, token); err != nil {
+				return nil, err
+			}
+		case "provider":
+			seenProvider = true
+			if summary.Providers, err = readKeys(dec); err != nil {
+				return nil, err
+			}
+		default:
+			if err := skipValue(dec); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return summary, nil
+}
+
+// readKeys reads an object from dec and returns its keys, sorted; any other
+// value is skipped and has no keys
+func readKeys(dec *json.Decoder) ([]string, error) {
+	token, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	if token != json.Delim('{') {
+		return nil, skipRest(dec, token)
+	}
+
+	var keys []string
+	for dec.More() {
+		key, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key.(string))
+		if err := skipValue(dec); err != nil {
+			return nil, err
+		}
+	}
+	if _, err := dec.Token(); err != nil { // Closing '}'
+		return nil, err
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
+
+// skipValue reads the next value from dec without keeping it
+func skipValue(dec *json.Decoder) error {
+	token, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	return skipRest(dec, token)
+}
+
+// skipRest skips the rest of the value that token started
+func skipRest(dec *json.Decoder, token json.Token) error {
+	delim, ok := token.(json.Delim)
+	if !ok || (delim != '{' && delim != '[') {
+		return nil
+	}
+	for depth := 1; depth > 0; {
+		token, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		switch token {
+		case json.Delim('{'), json.Delim('['):
+			depth++
+		case json.Delim('}'), json.Delim(']'):
+			depth--
+		}
+	}
+	return nil
+}
+
+// expectDelim reads the next token from dec and fails unless it is delim
+func expectDelim(dec *json.Decoder, delim json.Delim) error {
+	token, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if token != delim {
+		return fmt.Errorf("expected '%s', found %v", delim, token)
+	}
+	return nil
+}
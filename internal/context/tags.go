@@ -0,0 +1,125 @@
+package context
+
+import (
+	"fmt"
+	"strings"
+)
+
+// TagSelector matches contexts tagged key=value
+type TagSelector struct {
+	Key   string
+	Value string
+}
+
+// ParseTagSelector parses "key=value"
+func ParseTagSelector(expr string) (TagSelector, error) {
+	key, value, ok := strings.Cut(expr, "=")
+	key = strings.TrimSpace(key)
+	if !ok || key == "" {
+		return TagSelector{}, fmt.Errorf("invalid tag '%s': expected key=value", expr)
+	}
+	return TagSelector{Key: key, Value: strings.TrimSpace(value)}, nil
+}
+
+// MatchTags reports whether tags has every selector's key with its value
+func MatchTags(tags map[string]string, selectors []TagSelector) bool {
+	for _, selector := range selectors {
+		if value, ok := tags[selector.Key]; !ok || value != selector.Value {
+			return false
+		}
+	}
+	return true
+}
+
+// validateTagKey rejects keys that can't be written back as key=value
+func validateTagKey(key string) error {
+	if key == "" || strings.ContainsAny(key, "= \t\n") {
+		return fmt.Errorf("invalid tag key '%s'", key)
+	}
+	return nil
+}
+
+// SetContextTags tags a context, adding to or replacing its tags
+func (m *Manager) SetContextTags(name string, tags map[string]string) error {
+	if err := m.CheckWritable("tag context"); err != nil {
+		return err
+	}
+
+	ctx, err := m.GetContext(name)
+	if err != nil {
+		return err
+	}
+	if err := ctx.CheckModifiable("tag"); err != nil {
+		return err
+	}
+	for key := range tags {
+		if err := validateTagKey(key); err != nil {
+			return err
+		}
+	}
+
+	return m.updateMetadata(func(meta *metadataFile) {
+		entry := meta.Contexts[ctx.Name]
+		if entry == nil {
+			entry = &ContextMeta{}
+			meta.Contexts[ctx.Name] = entry
+		}
+		if entry.Tags == nil {
+			entry.Tags = make(map[string]string, len(tags))
+		}
+		for key, value := range tags {
+			entry.Tags[key] = value
+		}
+	})
+}
+
+// UntagContext removes tags from a context
+func (m *Manager) UntagContext(name string, keys []string) error {
+	if err := m.CheckWritable("untag context"); err != nil {
+		return err
+	}
+
+	ctx, err := m.GetContext(name)
+	if err != nil {
+		return err
+	}
+
+	var missing []string
+	err = m.updateMetadata(func(meta *metadataFile) {
+		entry := meta.Contexts[ctx.Name]
+		for _, key := range keys {
+			if entry == nil {
+				missing = append(missing, key)
+				continue
+			}
+			if _, ok := entry.Tags[key]; !ok {
+				missing = append(missing, key)
+			}
+			delete(entry.Tags, key)
+		}
+		if entry != nil && len(entry.Tags) == 0 {
+			entry.Tags = nil
+		}
+	})
+	if err != nil {
+		return err
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("context '%s' is not tagged %s", ctx.Name, strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// GetContextTags returns the tags of a context
+func (m *Manager) GetContextTags(name string) (map[string]string, error) {
+	ctx, err := m.GetContext(name)
+	if err != nil {
+		return nil, err
+	}
+
+	meta, err := m.GetContextMeta(ctx.Name)
+	if err != nil || meta == nil {
+		return nil, err
+	}
+	return meta.Tags, nil
+}
@@ -0,0 +1,217 @@
+package context
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+	"text/template/parse"
+
+	"github.com/hungthai1401/occtx/internal/suggest"
+)
+
+// ContextTemplate is a context file with {{ .variable }} placeholders, kept
+// in the templates directory and rendered once when a context is created
+type ContextTemplate struct {
+	Name      string
+	Path      string
+	Variables []string // Top-level variables the template uses, sorted
+}
+
+// ListTemplates returns the templates of the scope, sorted by name
+func (m *Manager) ListTemplates() ([]*ContextTemplate, error) {
+	templatesDir := m.paths.GetTemplatesDir(m.useProject)
+	entries, err := os.ReadDir(templatesDir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var templates []*ContextTemplate
+	for _, entry := range entries {
+		if entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		name, ok := templateName(entry.Name())
+		if !ok {
+			continue
+		}
+		tmpl, err := loadTemplate(name, filepath.Join(templatesDir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		templates = append(templates, tmpl)
+	}
+
+	sort.Slice(templates, func(i, j int) bool {
+		return templates[i].Name < templates[j].Name
+	})
+	return templates, nil
+}
+
+// GetTemplate returns the named template
+func (m *Manager) GetTemplate(name string) (*ContextTemplate, error) {
+	templatesDir := m.paths.GetTemplatesDir(m.useProject)
+	for _, f := range GetAllFormats() {
+		path := filepath.Join(templatesDir, name+f.FileExtension())
+		if _, err := os.Stat(path); err == nil {
+			return loadTemplate(name, path)
+		}
+	}
+
+	templates, err := m.ListTemplates()
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(templates))
+	for _, tmpl := range templates {
+		names = append(names, tmpl.Name)
+	}
+	return nil, fmt.Errorf("template '%s' not found in %s%s", name, templatesDir, suggest.Hint(suggest.Closest(name, names)))
+}
+
+// RenderTemplate fills in the template's variables and parses the result.
+// Values are inserted JSON-escaped, so they belong inside string literals
+// ("{{ .name }}"); every variable the template uses must be given.
+func (m *Manager) RenderTemplate(name string, vars map[string]string) (map[string]interface{}, error) {
+	tmpl, err := m.GetTemplate(name)
+	if err != nil {
+		return nil, err
+	}
+
+	var missing []string
+	for _, variable := range tmpl.Variables {
+		if _, ok := vars[variable]; !ok {
+			missing = append(missing, variable)
+		}
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("template '%s' needs values for: %s (use --var name=value)", name, strings.Join(missing, ", "))
+	}
+
+	source, err := os.ReadFile(tmpl.Path)
+	if err != nil {
+		return nil, err
+	}
+	parsed, err := template.New(name).Option("missingkey=error").Parse(string(source))
+	if err != nil {
+		return nil, fmt.Errorf("invalid template '%s': %v", name, err)
+	}
+
+	escaped := make(map[string]string, len(vars))
+	for key, value := range vars {
+		encoded, err := json.Marshal(value)
+		if err != nil {
+			return nil, err
+		}
+		escaped[key] = string(encoded[1 : len(encoded)-1])
+	}
+
+	var rendered bytes.Buffer
+	if err := parsed.Execute(&rendered, escaped); err != nil {
+		return nil, fmt.Errorf("failed to render template '%s': %v", name, err)
+	}
+
+	data, err := parseContextData(tmpl.Path, rendered.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("template '%s' did not render valid JSON: %v", name, err)
+	}
+	return data, nil
+}
+
+// CreateContextFromTemplate creates a new context by rendering a template.
+// The variables are resolved now; the context doesn't change when they do.
+func (m *Manager) CreateContextFromTemplate(name string, format ContextFormat, templateName string, vars map[string]string) error {
+	if err := m.CheckWritable("create context"); err != nil {
+		return err
+	}
+	defer m.timeOperation("create")()
+
+	jsonData, err := m.RenderTemplate(templateName, vars)
+	if err != nil {
+		return err
+	}
+
+	if err := m.prepareNewContext(name); err != nil {
+		return err
+	}
+
+	return m.writeNewContext(name, format, jsonData, Source{Kind: SourceTemplate, From: templateName})
+}
+
+// templateName returns the template name of a file in the templates directory
+func templateName(fileName string) (string, bool) {
+	for _, f := range GetAllFormats() {
+		if name := strings.TrimSuffix(fileName, f.FileExtension()); name != fileName {
+			return name, true
+		}
+	}
+	return "", false
+}
+
+// loadTemplate parses a template file to find its variables
+func loadTemplate(name, path string) (*ContextTemplate, error) {
+	source, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	parsed, err := template.New(name).Parse(string(source))
+	if err != nil {
+		return nil, fmt.Errorf("invalid template '%s': %v", name, err)
+	}
+
+	found := map[string]bool{}
+	templateVariables(parsed.Tree.Root, found)
+	variables := make([]string, 0, len(found))
+	for variable := range found {
+		variables = append(variables, variable)
+	}
+	sort.Strings(variables)
+
+	return &ContextTemplate{Name: name, Path: path, Variables: variables}, nil
+}
+
+// templateVariables collects the top-level fields a template node refers
+// to. The bodies of range and with are skipped, since dot changes there.
+func templateVariables(node parse.Node, found map[string]bool) {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return
+		}
+		for _, child := range n.Nodes {
+			templateVariables(child, found)
+		}
+	case *parse.ActionNode:
+		templateVariables(n.Pipe, found)
+	case *parse.PipeNode:
+		if n == nil {
+			return
+		}
+		for _, command := range n.Cmds {
+			templateVariables(command, found)
+		}
+	case *parse.CommandNode:
+		for _, arg := range n.Args {
+			templateVariables(arg, found)
+		}
+	case *parse.FieldNode:
+		found[n.Ident[0]] = true
+	case *parse.IfNode:
+		templateVariables(n.Pipe, found)
+		templateVariables(n.List, found)
+		templateVariables(n.ElseList, found)
+	case *parse.RangeNode:
+		templateVariables(n.Pipe, found)
+	case *parse.WithNode:
+		templateVariables(n.Pipe, found)
+	case *parse.TemplateNode:
+		templateVariables(n.Pipe, found)
+	}
+}
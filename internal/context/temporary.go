@@ -0,0 +1,87 @@
+package context
+
+import (
+	"fmt"
+	"time"
+)
+
+// SwitchToContextFor switches to the specified context and records an expiry
+// after which the previously current context is restored
+func (m *Manager) SwitchToContextFor(name string, duration time.Duration) error {
+	if duration <= 0 {
+		return fmt.Errorf("duration must be positive, got %s", duration)
+	}
+
+	revertTo, err := m.GetCurrentContext()
+	if err != nil {
+		return err
+	}
+
+	if err := m.SwitchToContext(name); err != nil {
+		return err
+	}
+
+	expiresAt := time.Now().Add(duration)
+	return m.updateState(func(state *State) error {
+		state.Temporary = &TemporarySwitch{
+			Context:   state.Current,
+			RevertTo:  revertTo,
+			ExpiresAt: expiresAt,
+		}
+		return nil
+	})
+}
+
+// GetTemporarySwitch returns the pending temporary switch, or nil if there is none
+func (m *Manager) GetTemporarySwitch() (*TemporarySwitch, error) {
+	state, err := m.loadState()
+	if err != nil {
+		return nil, err
+	}
+
+	return state.Temporary, nil
+}
+
+// RevertExpired restores the prior context if a temporary switch has expired.
+// It returns the reverted switch, or nil if nothing was due.
+func (m *Manager) RevertExpired() (*TemporarySwitch, error) {
+	state, err := m.loadState()
+	if err != nil {
+		return nil, err
+	}
+
+	temporary := state.Temporary
+	if temporary == nil || !temporary.Expired(time.Now()) {
+		return nil, nil
+	}
+	if err := m.CheckWritable("revert temporary switch"); err != nil {
+		return nil, err
+	}
+
+	// The context was changed by hand since; just forget the pending revert
+	if state.Current != temporary.Context {
+		return nil, m.clearTemporary()
+	}
+
+	if temporary.RevertTo == "" {
+		return temporary, m.UnsetCurrentContext()
+	}
+
+	if err := m.SwitchToContext(temporary.RevertTo); err != nil {
+		// Don't retry a revert that can never succeed
+		if saveErr := m.clearTemporary(); saveErr != nil {
+			return nil, saveErr
+		}
+		return nil, fmt.Errorf("failed to revert to context '%s': %v", temporary.RevertTo, err)
+	}
+
+	return temporary, nil
+}
+
+// clearTemporary forgets the pending temporary switch
+func (m *Manager) clearTemporary() error {
+	return m.updateState(func(state *State) error {
+		state.Temporary = nil
+		return nil
+	})
+}
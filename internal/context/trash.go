@@ -0,0 +1,39 @@
+package context
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// trashStampLayout timestamps trashed context files
+const trashStampLayout = "20060102-150405.000000000"
+
+// SetForce lets create and import replace an existing context of the same
+// name (the replaced file is moved to the trash first) and lets switching
+// overwrite an active config occtx didn't write
+func (m *Manager) SetForce(force bool) {
+	m.force = force
+}
+
+// trashContextFile moves a context file into the trash directory under a
+// timestamped name and forgets its checksum and metadata. It returns the
+// path of the trashed copy.
+func (m *Manager) trashContextFile(name, path string) (string, error) {
+	trashDir := m.paths.GetTrashDir(m.useProject)
+	if err := os.MkdirAll(trashDir, m.dirMode()); err != nil {
+		return "", err
+	}
+
+	stamp := time.Now().Format(trashStampLayout)
+	trashPath := filepath.Join(trashDir, name+"-"+stamp+filepath.Ext(path))
+	if err := os.Rename(path, trashPath); err != nil {
+		return "", err
+	}
+
+	if err := m.forgetChecksum(path); err != nil {
+		return "", err
+	}
+
+	return trashPath, m.deleteMetadata(name)
+}
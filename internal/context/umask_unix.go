@@ -0,0 +1,51 @@
+//go:build !windows
+
+package context
+
+import (
+	"bufio"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+)
+
+var (
+	umaskOnce sync.Once
+	umask     os.FileMode
+)
+
+// processUmask returns the umask occtx was started with. Linux reports it
+// in /proc; elsewhere it is read by setting it, once, before any file is
+// written.
+func processUmask() os.FileMode {
+	umaskOnce.Do(func() {
+		if mask, ok := procUmask(); ok {
+			umask = mask
+			return
+		}
+		mask := syscall.Umask(0)
+		syscall.Umask(mask)
+		umask = os.FileMode(mask)
+	})
+	return umask
+}
+
+// procUmask reads the umask from /proc/self/status
+func procUmask() (os.FileMode, bool) {
+	file, err := os.Open("/proc/self/status")
+	if err != nil {
+		return 0, false
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if value, ok := strings.CutPrefix(scanner.Text(), "Umask:"); ok {
+			mask, err := strconv.ParseUint(strings.TrimSpace(value), 8, 32)
+			return os.FileMode(mask), err == nil
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,10 @@
+//go:build windows
+
+package context
+
+import "os"
+
+// processUmask is always 0 on Windows, which has no umask
+func processUmask() os.FileMode {
+	return 0
+}
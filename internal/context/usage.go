@@ -0,0 +1,89 @@
+package context
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// ListSort orders a context listing
+type ListSort string
+
+const (
+	SortName      ListSort = "name"      // Alphabetical
+	SortUsed      ListSort = "used"      // Most recently switched to first
+	SortFrequency ListSort = "frequency" // Most often switched to first
+)
+
+// ParseListSort validates a --sort value
+func ParseListSort(value string) (ListSort, error) {
+	switch ListSort(value) {
+	case SortName, SortUsed, SortFrequency:
+		return ListSort(value), nil
+	}
+	return "", fmt.Errorf("invalid sort '%s'. Supported orders: name, used, frequency", value)
+}
+
+// Usage is how often and how recently a context was switched to
+type Usage struct {
+	Count    int        `json:"use_count"`
+	LastUsed *time.Time `json:"last_used,omitempty"`
+}
+
+// GetUsage returns the recorded usage of each context, keyed by name.
+// Contexts never switched to have no entry.
+func (m *Manager) GetUsage() (map[string]Usage, error) {
+	meta, err := m.loadMetadata()
+	if err != nil {
+		return nil, err
+	}
+
+	usage := make(map[string]Usage)
+	for name, entry := range meta.Contexts {
+		if entry.UseCount > 0 {
+			usage[name] = Usage{Count: entry.UseCount, LastUsed: entry.LastUsed}
+		}
+	}
+	return usage, nil
+}
+
+// SortContexts orders contexts in place by order using usage. Ties, and
+// contexts never used, keep name order.
+func SortContexts(contexts []*Context, order ListSort, usage map[string]Usage) {
+	lastUsed := func(name string) time.Time {
+		if used := usage[name].LastUsed; used != nil {
+			return *used
+		}
+		return time.Time{}
+	}
+
+	sort.SliceStable(contexts, func(i, j int) bool {
+		a, b := contexts[i].Name, contexts[j].Name
+		switch order {
+		case SortUsed:
+			if ta, tb := lastUsed(a), lastUsed(b); !ta.Equal(tb) {
+				return ta.After(tb)
+			}
+		case SortFrequency:
+			if ca, cb := usage[a].Count, usage[b].Count; ca != cb {
+				return ca > cb
+			}
+		}
+		return a < b
+	})
+}
+
+// recordUse counts a switch to a context. Contexts created outside occtx get
+// a metadata entry on their first use.
+func (m *Manager) recordUse(name string) error {
+	now := time.Now()
+	return m.updateMetadata(func(meta *metadataFile) {
+		entry := meta.Contexts[name]
+		if entry == nil {
+			entry = &ContextMeta{}
+			meta.Contexts[name] = entry
+		}
+		entry.UseCount++
+		entry.LastUsed = &now
+	})
+}
@@ -0,0 +1,83 @@
+package context
+
+import (
+	"fmt"
+	"os"
+)
+
+// ValidationResult is the outcome of validating one context file
+type ValidationResult struct {
+	Context  string
+	Path     string
+	Findings []string // Parse errors, schema problems and literal secrets
+}
+
+// Valid reports whether the context has no findings
+func (r *ValidationResult) Valid() bool {
+	return len(r.Findings) == 0
+}
+
+// ValidateContexts checks the named contexts, or every local context of the
+// scope when names is empty: each must parse, match the opencode config
+// schema and hold no literal secrets. A context that doesn't parse is a
+// finding, not an error.
+func (m *Manager) ValidateContexts(names []string) ([]*ValidationResult, error) {
+	var contexts []*Context
+	if len(names) == 0 {
+		var err error
+		if contexts, err = listContextsIn(m.paths.GetContextsDir(m.useProject)); err != nil {
+			return nil, err
+		}
+	} else {
+		local, err := listContextsIn(m.paths.GetContextsDir(m.useProject))
+		if err != nil {
+			return nil, err
+		}
+		for _, name := range names {
+			ctx := findListedContext(local, name)
+			if ctx == nil {
+				// Names differing in case, shared contexts and unknown names go through GetContext
+				if ctx, err = m.GetContext(name); err != nil {
+					return nil, err
+				}
+			}
+			contexts = append(contexts, ctx)
+		}
+	}
+
+	results := make([]*ValidationResult, 0, len(contexts))
+	for _, ctx := range contexts {
+		results = append(results, validateContextFile(ctx))
+	}
+	return results, nil
+}
+
+// findListedContext returns the listed context named name, or nil
+func findListedContext(contexts []*Context, name string) *Context {
+	for _, ctx := range contexts {
+		if ctx.Name == name {
+			return ctx
+		}
+	}
+	return nil
+}
+
+// validateContextFile reads and checks one context file
+func validateContextFile(ctx *Context) *ValidationResult {
+	result := &ValidationResult{Context: ctx.Name, Path: ctx.FilePath}
+
+	data, err := os.ReadFile(ctx.FilePath)
+	if err != nil {
+		result.Findings = append(result.Findings, err.Error())
+		return result
+	}
+	content, err := parseContextData(ctx.FilePath, data)
+	if err != nil {
+		result.Findings = append(result.Findings, fmt.Sprintf("invalid JSON: %v", err))
+		return result
+	}
+
+	result.Findings = append(result.Findings, validateOpencodeConfig(content)...)
+	result.Findings = append(result.Findings, findSecrets(content)...)
+	return result
+}
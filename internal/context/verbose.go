@@ -0,0 +1,76 @@
+package context
+
+import (
+	"fmt"
+	"time"
+)
+
+// Verbosity controls how much the manager explains what it is doing
+type Verbosity int
+
+const (
+	VerbosityNormal  Verbosity = iota // No diagnostics
+	VerbosityVerbose                  // -v: paths, scope decisions, format detection and timing
+	VerbosityDebug                    // -vv: also individual file reads and writes
+)
+
+// SetVerbosity routes diagnostics up to level to handler
+func (m *Manager) SetVerbosity(level Verbosity, handler func(level Verbosity, message string)) {
+	m.verbosity = level
+	m.logger = handler
+}
+
+// LogEnvironment reports the scope and resolved paths the manager works with
+func (m *Manager) LogEnvironment() {
+	switch {
+	case m.useProject:
+		m.logf(VerbosityVerbose, "scope: project (--in-project)")
+	case m.profile != "":
+		m.logf(VerbosityVerbose, "scope: global, profile '%s'", m.profile)
+	default:
+		m.logf(VerbosityVerbose, "scope: global")
+	}
+	if m.paths.Fallback != "" {
+		m.logf(VerbosityVerbose, "config dir fallback: %s", m.paths.Fallback)
+	}
+
+	if m.useProject && m.worktree != "" {
+		m.logf(VerbosityVerbose, "git worktree: sharing the contexts of %s", m.worktree)
+	}
+	m.logf(VerbosityVerbose, "contexts dir: %s", m.paths.GetContextsDir(m.useProject))
+	m.logf(VerbosityVerbose, "active config: %s", m.paths.GetActiveConfigPath(m.useProject))
+	m.logf(VerbosityDebug, "state file: %s", m.paths.GetStateFilePath(m.useProject))
+	m.logf(VerbosityDebug, "occtx config: %s", m.paths.ConfigFile)
+	m.logf(VerbosityDebug, "policy file: %s", m.paths.GetPolicyFilePath(m.useProject))
+	m.logf(VerbosityDebug, "read-only: %t, offline: %t, strict: %t", m.readOnly, m.offline, m.strict)
+}
+
+// logf reports a diagnostic when the verbosity is at least level
+func (m *Manager) logf(level Verbosity, format string, args ...interface{}) {
+	if m.logger != nil && m.verbosity >= level {
+		m.logger(level, fmt.Sprintf(format, args...))
+	}
+}
+
+// SetTimingHandler receives how long each operation, and each phase of a
+// switch such as "switch/checks", took
+func (m *Manager) SetTimingHandler(handler func(op string, elapsed time.Duration)) {
+	m.timing = handler
+}
+
+// timeOperation logs how long an operation took and reports it to the timing
+// handler; use as defer m.timeOperation("switch")()
+func (m *Manager) timeOperation(op string) func() {
+	if m.verbosity < VerbosityVerbose && m.timing == nil {
+		return func() {}
+	}
+
+	start := time.Now()
+	return func() {
+		elapsed := time.Since(start)
+		m.logf(VerbosityVerbose, "%s took %s", op, elapsed.Round(time.Microsecond))
+		if m.timing != nil {
+			m.timing(op, elapsed)
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package context
+
+import (
+	"fmt"
+	"os"
+)
+
+// SetWarningHandler routes non-fatal warnings, such as policy findings in
+// warn mode, to handler instead of stderr
+func (m *Manager) SetWarningHandler(handler func(message string)) {
+	m.warn = handler
+}
+
+// StrictWarningsError is returned in strict mode when an operation produced
+// warnings, which were reported as usual before it failed
+type StrictWarningsError struct {
+	Operation string
+	Warnings  []string
+}
+
+func (e *StrictWarningsError) Error() string {
+	return fmt.Sprintf("%s refused in strict mode: %d warning(s)", e.Operation, len(e.Warnings))
+}
+
+// warnf reports a non-fatal warning
+func (m *Manager) warnf(format string, args ...interface{}) {
+	message := fmt.Sprintf(format, args...)
+	m.warned = append(m.warned, message)
+	if m.warn != nil {
+		m.warn(message)
+	}
+}
+
+// checkWarnings fails operation in strict mode if warnings were reported
+// since the operation began, when since of them had been reported
+func (m *Manager) checkWarnings(operation string, since int) error {
+	if !m.strict || len(m.warned) <= since {
+		return nil
+	}
+	return &StrictWarningsError{Operation: operation, Warnings: append([]string(nil), m.warned[since:]...)}
+}
+
+// printWarning is the default warning handler
+func printWarning(message string) {
+	fmt.Fprintf(os.Stderr, "Warning: %s\n", message)
+}
@@ -0,0 +1,118 @@
+package context
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// ContextWatcher reports when contexts are added, removed or rewritten by
+// another process, e.g. a git pull in a synced settings directory. It polls
+// instead of relying on OS file notifications, which behave differently per
+// platform and are missing on many network filesystems.
+type ContextWatcher struct {
+	changes chan struct{}
+	stop    chan struct{}
+	done    chan struct{}
+}
+
+// contextsSnapshot maps each context file in the watched directories to its
+// modification time and size
+type contextsSnapshot map[string]string
+
+// WatchContexts starts watching the contexts directory and shared directories,
+// checking every interval until Stop is called
+func (m *Manager) WatchContexts(interval time.Duration) *ContextWatcher {
+	w := &ContextWatcher{
+		changes: make(chan struct{}, 1),
+		stop:    make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+
+	dirs := append([]string{m.paths.GetContextsDir(m.useProject)}, m.sharedDirs()...)
+	go w.run(dirs, interval)
+	return w
+}
+
+// Changes receives a value after the watched contexts changed. Changes seen
+// before the previous one was received are coalesced.
+func (w *ContextWatcher) Changes() <-chan struct{} {
+	return w.changes
+}
+
+// Stop ends the watch
+func (w *ContextWatcher) Stop() {
+	select {
+	case <-w.stop:
+	default:
+		close(w.stop)
+	}
+	<-w.done
+}
+
+// run polls dirs until stopped
+func (w *ContextWatcher) run(dirs []string, interval time.Duration) {
+	defer close(w.done)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	last := snapshotContexts(dirs)
+	for {
+		select {
+		case <-w.stop:
+			return
+		case <-ticker.C:
+		}
+
+		current := snapshotContexts(dirs)
+		if current.equal(last) {
+			continue
+		}
+		last = current
+
+		select {
+		case w.changes <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// snapshotContexts records the context files in dirs; unreadable directories
+// count as empty
+func snapshotContexts(dirs []string) contextsSnapshot {
+	snapshot := make(contextsSnapshot)
+	for _, dir := range dirs {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			name := entry.Name()
+			if entry.IsDir() || strings.HasPrefix(name, ".") || contextNameFromFile(name) == name {
+				continue
+			}
+			info, err := entry.Info()
+			if err != nil {
+				continue
+			}
+			snapshot[filepath.Join(dir, name)] = fmt.Sprintf("%d/%d", info.ModTime().UnixNano(), info.Size())
+		}
+	}
+	return snapshot
+}
+
+// equal reports whether two snapshots describe the same files
+func (s contextsSnapshot) equal(other contextsSnapshot) bool {
+	if len(s) != len(other) {
+		return false
+	}
+	for path, stamp := range s {
+		if other[path] != stamp {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,14 @@
+//go:build !linux && !darwin
+
+package context
+
+// readXattrs returns no attributes on platforms occtx doesn't read extended
+// attributes on
+func readXattrs(path string) (map[string][]byte, error) {
+	return nil, nil
+}
+
+// writeXattr is never called where readXattrs returns nothing
+func writeXattr(path, name string, value []byte) error {
+	return nil
+}
@@ -0,0 +1,48 @@
+//go:build linux || darwin
+
+package context
+
+import (
+	"bytes"
+	"errors"
+
+	"golang.org/x/sys/unix"
+)
+
+// readXattrs returns the extended attributes of path by name. Filesystems
+// without extended attributes have none.
+func readXattrs(path string) (map[string][]byte, error) {
+	size, err := unix.Listxattr(path, nil)
+	if err != nil || size == 0 {
+		if errors.Is(err, unix.ENOTSUP) {
+			err = nil
+		}
+		return nil, err
+	}
+	list := make([]byte, size)
+	if size, err = unix.Listxattr(path, list); err != nil {
+		return nil, err
+	}
+
+	attrs := make(map[string][]byte)
+	for _, name := range bytes.Split(list[:size], []byte{0}) {
+		if len(name) == 0 {
+			continue
+		}
+		valueSize, err := unix.Getxattr(path, string(name), nil)
+		if err != nil {
+			return nil, err
+		}
+		value := make([]byte, valueSize)
+		if valueSize, err = unix.Getxattr(path, string(name), value); err != nil {
+			return nil, err
+		}
+		attrs[string(name)] = value[:valueSize]
+	}
+	return attrs, nil
+}
+
+// writeXattr sets an extended attribute of path
+func writeXattr(path, name string, value []byte) error {
+	return unix.Setxattr(path, name, value, 0)
+}
@@ -0,0 +1,84 @@
+// Package schedule installs recurring occtx jobs with the platform's own
+// scheduler: a launchd agent on macOS, a systemd user timer on Linux and a
+// Task Scheduler task on Windows. Jobs run as the current user.
+package schedule
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// Frequency is how often a job runs
+type Frequency string
+
+const (
+	Hourly Frequency = "hourly"
+	Daily  Frequency = "daily"
+	Weekly Frequency = "weekly"
+)
+
+// ParseFrequency validates a frequency name
+func ParseFrequency(s string) (Frequency, error) {
+	switch f := Frequency(strings.ToLower(s)); f {
+	case Hourly, Daily, Weekly:
+		return f, nil
+	}
+	return "", fmt.Errorf("invalid frequency '%s' (supported: hourly, daily, weekly)", s)
+}
+
+// Job is a command run on a schedule
+type Job struct {
+	Name      string    // Identifies the job with the scheduler, e.g. "occtx-backup"
+	Command   []string  // Absolute path of the executable, then its arguments
+	Frequency Frequency // How often the command runs
+}
+
+// Status describes what the scheduler knows about a job
+type Status struct {
+	Installed bool
+	Scheduler string // launchd, systemd or Task Scheduler
+	Location  string // Unit file, property list or task name
+}
+
+// ErrUnsupported is returned on platforms without a supported scheduler
+var ErrUnsupported = errors.New("scheduled jobs are not supported on this platform")
+
+// ErrNotInstalled is returned by Uninstall when the job isn't scheduled
+var ErrNotInstalled = errors.New("no such scheduled job is installed")
+
+// Install registers job with the platform scheduler, replacing an existing
+// job of the same name
+func Install(job Job) error {
+	if job.Name == "" || len(job.Command) == 0 {
+		return fmt.Errorf("a scheduled job needs a name and a command")
+	}
+	if job.Frequency == "" {
+		job.Frequency = Daily
+	}
+	return install(job)
+}
+
+// Uninstall removes the named job
+func Uninstall(name string) error {
+	return uninstall(name)
+}
+
+// Query reports whether the named job is installed
+func Query(name string) (Status, error) {
+	return query(name)
+}
+
+// run executes a scheduler command, including its output in any error
+func run(name string, args ...string) error {
+	output, err := exec.Command(name, args...).CombinedOutput()
+	if err != nil {
+		detail := strings.TrimSpace(string(output))
+		if detail == "" {
+			detail = err.Error()
+		}
+		return fmt.Errorf("%s %s failed: %s", name, strings.Join(args, " "), detail)
+	}
+	return nil
+}
@@ -0,0 +1,106 @@
+//go:build darwin
+
+package schedule
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// launchdIntervals maps frequencies to StartInterval seconds
+var launchdIntervals = map[Frequency]int{
+	Hourly: 60 * 60,
+	Daily:  24 * 60 * 60,
+	Weekly: 7 * 24 * 60 * 60,
+}
+
+// launchdLabel is the agent label for a job name
+func launchdLabel(name string) string {
+	return "com.github.hungthai1401." + name
+}
+
+// launchAgentPath is the property list for a job name
+func launchAgentPath(name string) (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, "Library", "LaunchAgents", launchdLabel(name)+".plist"), nil
+}
+
+// install writes a launch agent and loads it
+func install(job Job) error {
+	path, err := launchAgentPath(job.Name)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	var args bytes.Buffer
+	for _, arg := range job.Command {
+		args.WriteString("\t\t<string>")
+		if err := xml.EscapeText(&args, []byte(arg)); err != nil {
+			return err
+		}
+		args.WriteString("</string>\n")
+	}
+
+	plist := fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+<plist version="1.0">
+<dict>
+	<key>Label</key>
+	<string>%s</string>
+	<key>ProgramArguments</key>
+	<array>
+%s	</array>
+	<key>StartInterval</key>
+	<integer>%d</integer>
+	<key>RunAtLoad</key>
+	<false/>
+</dict>
+</plist>
+`, launchdLabel(job.Name), args.String(), launchdIntervals[job.Frequency])
+
+	// Replacing a loaded agent requires unloading it first
+	if _, err := os.Stat(path); err == nil {
+		_ = run("launchctl", "unload", path)
+	}
+	if err := os.WriteFile(path, []byte(plist), 0644); err != nil {
+		return err
+	}
+	return run("launchctl", "load", "-w", path)
+}
+
+func uninstall(name string) error {
+	status, err := query(name)
+	if err != nil {
+		return err
+	}
+	if !status.Installed {
+		return ErrNotInstalled
+	}
+
+	_ = run("launchctl", "unload", "-w", status.Location)
+	return os.Remove(status.Location)
+}
+
+func query(name string) (Status, error) {
+	path, err := launchAgentPath(name)
+	if err != nil {
+		return Status{}, err
+	}
+
+	status := Status{Scheduler: "launchd", Location: path}
+	if _, err := os.Stat(path); err == nil {
+		status.Installed = true
+	} else if !os.IsNotExist(err) {
+		return status, err
+	}
+	return status, nil
+}
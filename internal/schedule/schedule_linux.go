@@ -0,0 +1,120 @@
+//go:build linux
+
+package schedule
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// systemdCalendars maps frequencies to OnCalendar expressions
+var systemdCalendars = map[Frequency]string{
+	Hourly: "hourly",
+	Daily:  "daily",
+	Weekly: "weekly",
+}
+
+// systemdUserDir is where per-user units live
+func systemdUserDir() (string, error) {
+	if xdg := os.Getenv("XDG_CONFIG_HOME"); xdg != "" && filepath.IsAbs(xdg) {
+		return filepath.Join(xdg, "systemd", "user"), nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".config", "systemd", "user"), nil
+}
+
+// install writes a oneshot service and a persistent timer for it, so runs
+// missed while the machine was off happen at the next boot
+func install(job Job) error {
+	dir, err := systemdUserDir()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	service := fmt.Sprintf(`[Unit]
+Description=%[1]s (installed by occtx)
+
+[Service]
+Type=oneshot
+ExecStart=%[2]s
+`, job.Name, systemdCommand(job.Command))
+
+	timer := fmt.Sprintf(`[Unit]
+Description=Run %[1]s %[2]s (installed by occtx)
+
+[Timer]
+OnCalendar=%[3]s
+Persistent=true
+
+[Install]
+WantedBy=timers.target
+`, job.Name, job.Frequency, systemdCalendars[job.Frequency])
+
+	if err := os.WriteFile(filepath.Join(dir, job.Name+".service"), []byte(service), 0644); err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(dir, job.Name+".timer"), []byte(timer), 0644); err != nil {
+		return err
+	}
+
+	if err := run("systemctl", "--user", "daemon-reload"); err != nil {
+		return err
+	}
+	return run("systemctl", "--user", "enable", "--now", job.Name+".timer")
+}
+
+func uninstall(name string) error {
+	status, err := query(name)
+	if err != nil {
+		return err
+	}
+	if !status.Installed {
+		return ErrNotInstalled
+	}
+
+	// The timer may already be stopped; removing the units is what matters
+	_ = run("systemctl", "--user", "disable", "--now", name+".timer")
+
+	dir := filepath.Dir(status.Location)
+	for _, unit := range []string{name + ".timer", name + ".service"} {
+		if err := os.Remove(filepath.Join(dir, unit)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return run("systemctl", "--user", "daemon-reload")
+}
+
+func query(name string) (Status, error) {
+	dir, err := systemdUserDir()
+	if err != nil {
+		return Status{}, err
+	}
+
+	status := Status{Scheduler: "systemd", Location: filepath.Join(dir, name+".timer")}
+	if _, err := os.Stat(status.Location); err == nil {
+		status.Installed = true
+	} else if !os.IsNotExist(err) {
+		return status, err
+	}
+	return status, nil
+}
+
+// systemdCommand quotes each argument for an ExecStart line
+func systemdCommand(command []string) string {
+	quoted := make([]string, len(command))
+	for i, arg := range command {
+		arg = strings.ReplaceAll(arg, `\`, `\\`)
+		arg = strings.ReplaceAll(arg, `"`, `\"`)
+		arg = strings.ReplaceAll(arg, "%", "%%")
+		quoted[i] = `"` + arg + `"`
+	}
+	return strings.Join(quoted, " ")
+}
@@ -0,0 +1,15 @@
+//go:build !linux && !darwin && !windows
+
+package schedule
+
+func install(job Job) error {
+	return ErrUnsupported
+}
+
+func uninstall(name string) error {
+	return ErrUnsupported
+}
+
+func query(name string) (Status, error) {
+	return Status{}, ErrUnsupported
+}
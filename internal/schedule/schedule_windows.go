@@ -0,0 +1,53 @@
+//go:build windows
+
+package schedule
+
+import (
+	"os/exec"
+	"strings"
+)
+
+// schtasksSchedules maps frequencies to schtasks /SC values
+var schtasksSchedules = map[Frequency]string{
+	Hourly: "HOURLY",
+	Daily:  "DAILY",
+	Weekly: "WEEKLY",
+}
+
+// install creates (or with /F replaces) a Task Scheduler task
+func install(job Job) error {
+	return run("schtasks", "/Create", "/F",
+		"/TN", job.Name,
+		"/SC", schtasksSchedules[job.Frequency],
+		"/TR", windowsCommand(job.Command))
+}
+
+func uninstall(name string) error {
+	status, err := query(name)
+	if err != nil {
+		return err
+	}
+	if !status.Installed {
+		return ErrNotInstalled
+	}
+	return run("schtasks", "/Delete", "/F", "/TN", name)
+}
+
+func query(name string) (Status, error) {
+	status := Status{Scheduler: "Task Scheduler", Location: name}
+	// schtasks exits non-zero when the task doesn't exist
+	status.Installed = exec.Command("schtasks", "/Query", "/TN", name).Run() == nil
+	return status, nil
+}
+
+// windowsCommand joins the command for /TR, quoting arguments with spaces
+func windowsCommand(command []string) string {
+	quoted := make([]string, len(command))
+	for i, arg := range command {
+		if strings.ContainsAny(arg, " \t") {
+			arg = `"` + arg + `"`
+		}
+		quoted[i] = arg
+	}
+	return strings.Join(quoted, " ")
+}
@@ -0,0 +1,90 @@
+package suggest
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// maxDistance is the largest edit distance still considered a likely typo
+const maxDistance = 2
+
+// Distance returns the Levenshtein distance between a and b, ignoring case
+func Distance(a, b string) int {
+	ra := []rune(strings.ToLower(a))
+	rb := []rune(strings.ToLower(b))
+
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+
+	return prev[len(rb)]
+}
+
+// Closest returns the candidates that look like typos of input, nearest first.
+// Exact matches are skipped since there is nothing to suggest.
+func Closest(input string, candidates []string) []string {
+	// Allow fewer edits for short input so "ab" doesn't match everything
+	limit := maxDistance
+	if len([]rune(input)) <= 3 {
+		limit = 1
+	}
+
+	distances := make(map[string]int)
+	var matches []string
+	for _, candidate := range candidates {
+		if candidate == input {
+			continue
+		}
+		if _, seen := distances[candidate]; seen {
+			continue
+		}
+
+		d := Distance(input, candidate)
+		if d > limit && !strings.HasPrefix(strings.ToLower(candidate), strings.ToLower(input)) {
+			continue
+		}
+
+		distances[candidate] = d
+		matches = append(matches, candidate)
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		if distances[matches[i]] != distances[matches[j]] {
+			return distances[matches[i]] < distances[matches[j]]
+		}
+		return matches[i] < matches[j]
+	})
+
+	return matches
+}
+
+// Hint formats suggestions as an error message suffix, or returns "" if there are none
+func Hint(suggestions []string) string {
+	switch len(suggestions) {
+	case 0:
+		return ""
+	case 1:
+		return fmt.Sprintf("; did you mean '%s'?", suggestions[0])
+	default:
+		quoted := make([]string, len(suggestions))
+		for i, s := range suggestions {
+			quoted[i] = "'" + s + "'"
+		}
+		return fmt.Sprintf("; did you mean one of %s?", strings.Join(quoted, ", "))
+	}
+}
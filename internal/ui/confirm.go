@@ -0,0 +1,58 @@
+package ui
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// promptsEnabled is turned off in CI mode
+var promptsEnabled = true
+
+// SetPromptsEnabled turns confirmations and pickers on or off
+func SetPromptsEnabled(enabled bool) {
+	promptsEnabled = enabled
+}
+
+// PromptsEnabled reports whether occtx may ask the user anything
+func PromptsEnabled() bool {
+	return promptsEnabled
+}
+
+// Confirm asks a yes/no question on stderr and reads the answer from stdin.
+// It returns false without asking when stdin is not a terminal or prompts
+// are disabled, so scripts never block on a prompt.
+func Confirm(question string) bool {
+	if !promptsEnabled || !isTerminal(os.Stdin) {
+		return false
+	}
+
+	fmt.Fprintf(os.Stderr, "%s [y/N] ", question)
+	answer, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil {
+		return false
+	}
+
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return true
+	}
+	return false
+}
+
+// Prompt asks for a line of text on stderr and reads it from stdin. It
+// returns false without asking when stdin is not a terminal or prompts are
+// disabled.
+func Prompt(question string) (string, bool) {
+	if !promptsEnabled || !isTerminal(os.Stdin) {
+		return "", false
+	}
+
+	fmt.Fprintf(os.Stderr, "%s: ", question)
+	answer, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && answer == "" {
+		return "", false
+	}
+	return strings.TrimSpace(answer), true
+}
@@ -0,0 +1,120 @@
+package ui
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/fatih/color"
+)
+
+// Level classifies a diagnostic message. Diagnostics go to stderr so that
+// stdout only carries what a command produces.
+type Level string
+
+const (
+	LevelError    Level = "error"    // Why a command failed
+	LevelWarning  Level = "warning"  // Something the user should look at; the command went ahead
+	LevelHint     Level = "hint"     // A suggestion of what to do next
+	LevelProgress Level = "progress" // What a command is doing
+	LevelVerbose  Level = "verbose"  // -v output
+	LevelDebug    Level = "debug"    // -vv output
+)
+
+// levelPrefixes start each text diagnostic of the level
+var levelPrefixes = map[Level]string{
+	LevelError:   "Error: ",
+	LevelWarning: "Warning: ",
+	LevelHint:    "Hint: ",
+	LevelDebug:   "debug: ",
+}
+
+var (
+	diagMu          sync.Mutex
+	diagOutput      io.Writer = os.Stderr
+	diagnosticsJSON bool
+)
+
+// SetDiagnosticsJSON writes diagnostics as one JSON object per line, e.g.
+// {"level":"warning","message":"..."}, for -o json and CI mode
+func SetDiagnosticsJSON(enabled bool) {
+	diagMu.Lock()
+	defer diagMu.Unlock()
+	diagnosticsJSON = enabled
+}
+
+// DiagnosticsJSON reports whether diagnostics are written as JSON
+func DiagnosticsJSON() bool {
+	diagMu.Lock()
+	defer diagMu.Unlock()
+	return diagnosticsJSON
+}
+
+// SetDiagnosticsOutput redirects diagnostics, which go to stderr by default
+func SetDiagnosticsOutput(w io.Writer) {
+	diagMu.Lock()
+	defer diagMu.Unlock()
+	diagOutput = w
+}
+
+// Diagnose writes a diagnostic message. Hints and progress are left out
+// when progress is disabled, as with --quiet.
+func Diagnose(level Level, format string, args ...interface{}) {
+	if !progressEnabled && (level == LevelHint || level == LevelProgress) {
+		return
+	}
+
+	diagMu.Lock()
+	defer diagMu.Unlock()
+	writeDiagnostic(diagOutput, diagnosticsJSON, level, fmt.Sprintf(format, args...))
+}
+
+// WriteDiagnostic writes one diagnostic message to w, as JSON if diagnostics
+// are written as JSON
+func WriteDiagnostic(w io.Writer, level Level, message string) {
+	writeDiagnostic(w, DiagnosticsJSON(), level, message)
+}
+
+// writeDiagnostic writes message as text with its level prefix, or as JSON
+func writeDiagnostic(w io.Writer, asJSON bool, level Level, message string) {
+	message = strings.TrimRight(message, "\n")
+	if asJSON {
+		data, _ := json.Marshal(struct {
+			Level   Level  `json:"level"`
+			Message string `json:"message"`
+		}{level, message})
+		fmt.Fprintf(w, "%s\n", data)
+		return
+	}
+	levelColor(level).Fprintf(w, "%s%s\n", levelPrefixes[level], message)
+}
+
+// Warnf writes a warning
+func Warnf(format string, args ...interface{}) {
+	Diagnose(LevelWarning, format, args...)
+}
+
+// Hintf writes a hint
+func Hintf(format string, args ...interface{}) {
+	Diagnose(LevelHint, format, args...)
+}
+
+// Progressf writes a progress message
+func Progressf(format string, args ...interface{}) {
+	Diagnose(LevelProgress, format, args...)
+}
+
+// levelColor returns the theme color diagnostics of level are written in
+func levelColor(level Level) *color.Color {
+	switch level {
+	case LevelError:
+		return color.New(activeTheme.Error...)
+	case LevelWarning:
+		return color.New(activeTheme.Warning...)
+	default:
+		return color.New(activeTheme.Info...)
+	}
+}
@@ -1,25 +1,76 @@
 package ui
 
 import (
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"strings"
+	"time"
 
 	"github.com/fatih/color"
+	"github.com/hungthai1401/occtx/internal/config"
 	"github.com/hungthai1401/occtx/internal/context"
 	"github.com/manifoldco/promptui"
 )
 
+// Picker names accepted by PickerOptions.Picker
+const (
+	PickerAuto    = "auto"
+	PickerFzf     = "fzf"
+	PickerBuiltin = "builtin"
+)
+
+// fzfAbortExitCode is fzf's exit status when the user presses ESC or CTRL-C
+const fzfAbortExitCode = 130
+
+// ErrAborted is returned when the user cancels the selection
+var ErrAborted = errors.New("selection aborted")
+
+// PickerOptions configures the interactive selector
+type PickerOptions struct {
+	Picker string // PickerAuto (or ""), PickerFzf or PickerBuiltin
+	Fzf    config.FzfConfig
+
+	// PreviewCommand is run by fzf for the highlighted entry when preview is on;
+	// fzf replaces {-1} with the context name
+	PreviewCommand string
+
+	// SearchCommand is run by fzf with content search on to list the contexts
+	// matching the query; fzf appends the quoted query
+	SearchCommand string
+
+	// RefreshInterval is how often the built-in picker checks for contexts
+	// changed by other processes; 0 disables live refresh
+	RefreshInterval time.Duration
+}
+
+// DefaultRefreshInterval is the built-in picker's live refresh interval
+const DefaultRefreshInterval = 500 * time.Millisecond
+
 // InteractiveSelector handles interactive context selection
 type InteractiveSelector struct {
 	manager *context.Manager
+	options PickerOptions
+
+	// The content index is built on the first search; the last query's
+	// matches are kept, as the built-in picker asks once per item
+	index       *context.ContentIndex
+	lastQuery   string
+	lastMatches map[string]bool
 }
 
 // NewInteractiveSelector creates a new interactive selector
 func NewInteractiveSelector(manager *context.Manager) *InteractiveSelector {
+	return NewInteractiveSelectorWithOptions(manager, PickerOptions{})
+}
+
+// NewInteractiveSelectorWithOptions creates an interactive selector with picker options
+func NewInteractiveSelectorWithOptions(manager *context.Manager, options PickerOptions) *InteractiveSelector {
 	return &InteractiveSelector{
 		manager: manager,
+		options: options,
 	}
 }
 
@@ -34,22 +85,79 @@ func (s *InteractiveSelector) SelectContext() (string, error) {
 		return "", fmt.Errorf("no contexts available")
 	}
 
-	// Try fzf first if available
-	if contextName, err := s.selectWithFzf(contexts); err == nil {
-		return contextName, nil
+	fzf, err := s.useFzf()
+	if err != nil {
+		return "", err
+	}
+	if fzf {
+		return s.selectWithFzf(contexts)
 	}
-
-	// Fallback to built-in selector
 	return s.selectWithPromptUI(contexts)
 }
 
-// selectWithFzf uses fzf for context selection if available
-func (s *InteractiveSelector) selectWithFzf(contexts []*context.Context) (string, error) {
-	// Check if fzf is available
-	if !isFzfAvailable() {
-		return "", fmt.Errorf("fzf not available")
+// useFzf reports whether the configured picker is fzf
+func (s *InteractiveSelector) useFzf() (bool, error) {
+	switch s.options.Picker {
+	case PickerFzf:
+		if !isFzfAvailable() {
+			return false, fmt.Errorf("fzf not found in PATH")
+		}
+		return true, nil
+	case PickerBuiltin:
+		return false, nil
+	case PickerAuto, "":
+		// Use fzf if available, otherwise fall back to the built-in selector
+		return isFzfAvailable(), nil
+	default:
+		return false, fmt.Errorf("invalid picker '%s'. Supported pickers: %s, %s, %s", s.options.Picker, PickerAuto, PickerFzf, PickerBuiltin)
 	}
+}
 
+// fzfArgs builds the fzf command line from the configured options
+func (s *InteractiveSelector) fzfArgs() []string {
+	args := s.fzfBaseArgs("Select context: ")
+
+	if s.options.Fzf.Preview && s.options.PreviewCommand != "" {
+		args = append(args, "--preview", s.options.PreviewCommand)
+	}
+
+	if s.options.Fzf.ContentSearch && s.options.SearchCommand != "" {
+		// occtx does the filtering, matching names and contents
+		args = append(args, "--disabled", "--bind", "change:reload:"+s.options.SearchCommand+" {q} || true")
+	}
+
+	for _, bind := range s.options.Fzf.Bind {
+		args = append(args, "--bind", bind)
+	}
+
+	return append(args, s.options.Fzf.Args...)
+}
+
+// fzfBaseArgs are the fzf options shared by every list occtx shows in fzf
+func (s *InteractiveSelector) fzfBaseArgs(prompt string) []string {
+	opts := s.options.Fzf
+
+	height := opts.Height
+	if height == "" {
+		height = "40%"
+	}
+	layout := opts.Layout
+	if layout == "" {
+		layout = "reverse"
+	}
+
+	return []string{
+		"--height", height,
+		"--layout", layout,
+		"--border",
+		"--prompt", prompt,
+		"--header", "Press ESC to cancel",
+		"--ansi", // Enable color support
+	}
+}
+
+// selectWithFzf uses fzf for context selection
+func (s *InteractiveSelector) selectWithFzf(contexts []*context.Context) (string, error) {
 	// Get current context for highlighting
 	currentContext, _ := s.manager.GetCurrentContext()
 
@@ -57,92 +165,225 @@ func (s *InteractiveSelector) selectWithFzf(contexts []*context.Context) (string
 	var items []string
 	for _, ctx := range contexts {
 		if ctx.Name == currentContext {
-			items = append(items, fmt.Sprintf("* %s", ctx.Name))
+			items = append(items, fmt.Sprintf("%s %s", activeTheme.CurrentMarker, ctx.Name))
 		} else {
 			items = append(items, fmt.Sprintf("  %s", ctx.Name))
 		}
 	}
 
-	input := strings.Join(items, "\n")
-
-	// Run fzf
-	cmd := exec.Command("fzf",
-		"--height", "40%",
-		"--reverse",
-		"--border",
-		"--prompt", "Select context: ",
-		"--header", "Press ESC to cancel",
-		"--ansi", // Enable color support
-	)
-
-	cmd.Stdin = strings.NewReader(input)
-	cmd.Stderr = os.Stderr
-
-	output, err := cmd.Output()
+	selected, err := s.runFzf(s.fzfArgs(), items)
 	if err != nil {
 		return "", err
 	}
-
-	selected := strings.TrimSpace(string(output))
 	if selected == "" {
 		return "", fmt.Errorf("no context selected")
 	}
 
 	// Extract context name (remove prefix)
-	contextName := strings.TrimSpace(strings.TrimPrefix(selected, "*"))
+	contextName := strings.TrimSpace(strings.TrimPrefix(selected, activeTheme.CurrentMarker))
 	contextName = strings.TrimSpace(contextName)
 
 	return contextName, nil
 }
 
-// selectWithPromptUI uses the built-in promptui for context selection
+// runFzf shows items in fzf and returns the selected line, trimmed
+func (s *InteractiveSelector) runFzf(args []string, items []string) (string, error) {
+	cmd := exec.CommandContext(s.manager.Context(), "fzf", args...)
+	cmd.Stdin = strings.NewReader(strings.Join(items, "\n"))
+	cmd.Stderr = os.Stderr
+
+	output, err := cmd.Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && exitErr.ExitCode() == fzfAbortExitCode {
+			return "", ErrAborted
+		}
+		if ctxErr := s.manager.Context().Err(); ctxErr != nil {
+			err = ctxErr
+		}
+		return "", fmt.Errorf("fzf failed: %w", err)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// selectWithPromptUI uses the built-in promptui for context selection. With a
+// refresh interval set, the list is redrawn whenever another process adds,
+// removes or rewrites contexts, keeping the highlighted entry.
 func (s *InteractiveSelector) selectWithPromptUI(contexts []*context.Context) (string, error) {
+	if s.options.RefreshInterval <= 0 {
+		result, _, err := s.runPromptUI(contexts, "", nil)
+		return result, err
+	}
+
+	watcher := s.manager.WatchContexts(s.options.RefreshInterval)
+	defer watcher.Stop()
+	input := newRefreshableStdin(os.Stdin)
+
+	highlighted := ""
+	for {
+		round := input.newRound()
+		finished := make(chan struct{})
+		go func() {
+			select {
+			case <-watcher.Changes():
+				round.cancel()
+			case <-finished:
+			}
+		}()
+
+		result, last, err := s.runPromptUI(contexts, highlighted, round)
+		close(finished)
+		if err == nil || !round.cancelled() {
+			return result, err
+		}
+
+		// Contexts changed underneath the picker; redraw with the new list
+		highlighted = last
+		if contexts, err = s.manager.ListContexts(); err != nil {
+			return "", err
+		}
+		if len(contexts) == 0 {
+			return "", fmt.Errorf("no contexts available")
+		}
+	}
+}
+
+// runPromptUI shows the built-in picker once with the cursor on cursorName (if
+// listed), reading keys from stdin (os.Stdin when nil). It also returns the
+// entry that was highlighted last.
+func (s *InteractiveSelector) runPromptUI(contexts []*context.Context, cursorName string, stdin io.ReadCloser) (string, string, error) {
 	// Get current context for highlighting
 	currentContext, _ := s.manager.GetCurrentContext()
 
 	// Create items for promptui
 	items := make([]string, len(contexts))
+	cursor := 0
 	for i, ctx := range contexts {
 		items[i] = ctx.Name
+		if ctx.Name == cursorName {
+			cursor = i
+		}
 	}
 
-	// Custom template with colors
-	templates := &promptui.SelectTemplates{
-		Label:    "{{ . }}?",
-		Active:   "▸ {{ . | cyan }}",
-		Inactive: "  {{ . }}",
-		Selected: "{{ \"✓\" | green }} {{ . | cyan }}",
-	}
+	// Templates use the active theme's colors and symbols
+	currentColor := color.New(activeTheme.Current...)
+	successColor := color.New(activeTheme.Success...)
 
-	// Add current context indicator
+	highlighted := cursorName
 	funcMap := promptui.FuncMap
 	funcMap["current"] = func(name string) string {
 		if name == currentContext {
-			return color.GreenString("* %s", name)
+			return currentColor.Sprintf("%s %s", activeTheme.CurrentMarker, name)
 		}
 		return fmt.Sprintf("  %s", name)
 	}
+	funcMap["success"] = func(s string) string {
+		return successColor.Sprint(s)
+	}
+	funcMap["track"] = func(name string) string {
+		highlighted = name
+		return ""
+	}
+	docs := make(map[string]string)
+	funcMap["doc"] = func(name string) string {
+		doc, ok := docs[name]
+		if !ok {
+			doc = s.docPreview(name)
+			docs[name] = doc
+		}
+		return doc
+	}
 
-	templates.Active = "▸ {{ . | current }}"
-	templates.Inactive = "{{ . | current }}"
+	templates := &promptui.SelectTemplates{
+		Label:    "{{ . }}?",
+		Active:   activeTheme.ActiveSymbol + " {{ . | current }}",
+		Inactive: "{{ . | current }}",
+		Selected: "{{ " + fmt.Sprintf("%q", activeTheme.SelectedSymbol) + " | success }} {{ . }}",
+		Details:  "{{ doc . }}",
+		FuncMap:  funcMap,
+	}
+
+	if custom := activeTheme.Templates; custom.Active != "" {
+		templates.Active = custom.Active
+	}
+	if custom := activeTheme.Templates; custom.Inactive != "" {
+		templates.Inactive = custom.Inactive
+	}
+	if custom := activeTheme.Templates; custom.Selected != "" {
+		templates.Selected = custom.Selected
+	}
+	// Remember the highlighted entry so a refresh can put the cursor back
+	templates.Active = "{{ track . }}" + templates.Active
 
 	prompt := promptui.Select{
 		Label:     "Select context",
 		Items:     items,
 		Templates: templates,
 		Size:      10,
+		Stdin:     stdin,
 		Searcher: func(input string, index int) bool {
 			name := items[index]
-			return strings.Contains(strings.ToLower(name), strings.ToLower(input))
+			return strings.Contains(strings.ToLower(name), strings.ToLower(input)) || s.contentMatches(input)[name]
 		},
 	}
 
-	_, result, err := prompt.Run()
+	scroll := 0
+	if cursor >= prompt.Size {
+		scroll = cursor - prompt.Size + 1
+	}
+
+	_, result, err := prompt.RunCursorAt(cursor, scroll)
+	if errors.Is(err, promptui.ErrInterrupt) || errors.Is(err, promptui.ErrEOF) || errors.Is(err, promptui.ErrAbort) {
+		return "", highlighted, ErrAborted
+	}
 	if err != nil {
-		return "", err
+		return "", highlighted, err
+	}
+
+	return result, highlighted, nil
+}
+
+// docPreviewLines bounds the documentation shown below the built-in picker
+const docPreviewLines = 8
+
+// docPreview is the start of a context's description and rendered README,
+// shown below the built-in picker for the highlighted context
+func (s *InteractiveSelector) docPreview(name string) string {
+	doc, err := s.manager.GetContextDoc(name)
+	if err != nil || doc.Empty() {
+		return ""
+	}
+
+	var lines []string
+	if doc.Description != "" {
+		lines = append(lines, doc.Description)
+	}
+	if doc.Readme != "" {
+		if len(lines) > 0 {
+			lines = append(lines, "")
+		}
+		lines = append(lines, strings.Split(strings.TrimRight(RenderMarkdown(doc.Readme), "\n"), "\n")...)
+	}
+	if len(lines) > docPreviewLines {
+		lines = append(lines[:docPreviewLines], "…")
 	}
+	return "\n" + strings.Join(lines, "\n")
+}
 
-	return result, nil
+// contentMatches returns the contexts whose content matches query; errors
+// leave the picker matching names only
+func (s *InteractiveSelector) contentMatches(query string) map[string]bool {
+	if s.index == nil {
+		s.index = s.manager.NewContentIndex()
+	}
+	if s.lastMatches == nil || query != s.lastQuery {
+		matches, err := s.index.Matches(query)
+		if err != nil {
+			return nil
+		}
+		s.lastQuery, s.lastMatches = query, matches
+	}
+	return s.lastMatches
 }
 
 // isFzfAvailable checks if fzf is available in PATH
@@ -160,14 +401,14 @@ type ColorPrinter struct {
 	Current *color.Color
 }
 
-// NewColorPrinter creates a new color printer
+// NewColorPrinter creates a new color printer using the active theme
 func NewColorPrinter() *ColorPrinter {
 	return &ColorPrinter{
-		Success: color.New(color.FgGreen, color.Bold),
-		Error:   color.New(color.FgRed, color.Bold),
-		Info:    color.New(color.FgBlue),
-		Warning: color.New(color.FgYellow),
-		Current: color.New(color.FgGreen, color.Bold),
+		Success: color.New(activeTheme.Success...),
+		Error:   color.New(activeTheme.Error...),
+		Info:    color.New(activeTheme.Info...),
+		Warning: color.New(activeTheme.Warning...),
+		Current: color.New(activeTheme.Current...),
 	}
 }
 
@@ -199,6 +440,9 @@ func (cp *ColorPrinter) PrintCurrent(format string, args ...interface{}) {
 // ContextListFormatter handles formatting of context lists
 type ContextListFormatter struct {
 	printer *ColorPrinter
+
+	// Numbered prefixes each context with its 1-based position
+	Numbered bool
 }
 
 // NewContextListFormatter creates a new context list formatter
@@ -210,6 +454,11 @@ func NewContextListFormatter() *ContextListFormatter {
 
 // FormatContextList formats and prints a list of contexts
 func (clf *ContextListFormatter) FormatContextList(contexts []*context.Context, currentContext string, useProject bool) {
+	clf.FormatContextListWithNotes(contexts, currentContext, useProject, nil)
+}
+
+// FormatContextListWithNotes prints a list of contexts, appending notes[name] after each name
+func (clf *ContextListFormatter) FormatContextListWithNotes(contexts []*context.Context, currentContext string, useProject bool, notes map[string]string) {
 	if len(contexts) == 0 {
 		levelText := "global"
 		if useProject {
@@ -230,45 +479,111 @@ func (clf *ContextListFormatter) FormatContextList(contexts []*context.Context,
 	fmt.Printf("%s %s contexts:\n", levelEmoji, levelText)
 
 	// Print contexts with current highlighted
-	for _, ctx := range contexts {
+	width := len(fmt.Sprint(len(contexts)))
+	for i, ctx := range contexts {
+		name := ctx.Name
+		if clf.Numbered {
+			name = fmt.Sprintf("%*d  %s", width, i+1, ctx.Name)
+		}
+		suffix := ""
+		if ctx.Shared {
+			suffix = " " + sharedBadge(ctx)
+		}
+		if note := notes[ctx.Name]; note != "" {
+			suffix += clf.printer.Info.Sprintf("  %s", note)
+		}
+
 		if ctx.Name == currentContext {
-			clf.printer.PrintCurrent("* %s\n", ctx.Name)
+			clf.printer.PrintCurrent("%s %s%s\n", activeTheme.CurrentMarker, name, suffix)
 		} else {
-			fmt.Printf("  %s\n", ctx.Name)
+			fmt.Printf("  %s%s\n", name, suffix)
 		}
 	}
 }
 
-// ShowHints displays helpful hints to the user
-func (clf *ContextListFormatter) ShowHints(useProject bool, hasProjectContexts bool) {
-	if !useProject && hasProjectContexts {
-		clf.printer.PrintInfo("\n💡 Hint: Found project-level contexts. Use --in-project to see them.\n")
-	}
+// ContextGroup is one scope's contexts in a grouped listing
+type ContextGroup struct {
+	Scope    string // Prefix of the scope's qualified names, e.g. "global"
+	Project  bool
+	Contexts []*context.Context
+	Current  string
+	Notes    map[string]string
 }
 
-// ProgressIndicator shows progress for long-running operations
-type ProgressIndicator struct {
-	message string
-}
+// FormatContextGroups prints the contexts of several scopes, each under its
+// own header, with scope-qualified names. Shared contexts are listed last,
+// in a group of their own.
+func (clf *ContextListFormatter) FormatContextGroups(groups []ContextGroup) {
+	type entry struct {
+		group *ContextGroup
+		ctx   *context.Context
+	}
+	var shared []entry
+
+	for i := range groups {
+		group := &groups[i]
+		levelEmoji, levelText := "👤", "Global"
+		if group.Project {
+			levelEmoji, levelText = "📁", "Project"
+		}
 
-// NewProgressIndicator creates a new progress indicator
-func NewProgressIndicator(message string) *ProgressIndicator {
-	return &ProgressIndicator{message: message}
+		var own []*context.Context
+		for _, ctx := range group.Contexts {
+			if ctx.Shared {
+				shared = append(shared, entry{group, ctx})
+			} else {
+				own = append(own, ctx)
+			}
+		}
+		if i > 0 {
+			fmt.Println()
+		}
+		if len(own) == 0 {
+			fmt.Printf("%s No %s contexts\n", levelEmoji, strings.ToLower(levelText))
+			continue
+		}
+		fmt.Printf("%s %s contexts:\n", levelEmoji, levelText)
+		for _, ctx := range own {
+			clf.printGroupEntry(group, ctx)
+		}
+	}
+
+	if len(shared) > 0 {
+		fmt.Printf("\n🔒 Shared contexts (read-only):\n")
+		for _, e := range shared {
+			clf.printGroupEntry(e.group, e.ctx)
+		}
+	}
 }
 
-// Show displays the progress message
-func (pi *ProgressIndicator) Show() {
-	fmt.Printf("⏳ %s...\n", pi.message)
+// printGroupEntry prints one context of a grouped listing
+func (clf *ContextListFormatter) printGroupEntry(group *ContextGroup, ctx *context.Context) {
+	name := group.Scope + ":" + ctx.Name
+	suffix := ""
+	if ctx.Mirror != "" {
+		suffix = " (" + ctx.Mirror + ")"
+	}
+	if note := group.Notes[ctx.Name]; note != "" {
+		suffix += clf.printer.Info.Sprintf("  %s", note)
+	}
+	if ctx.Name == group.Current {
+		clf.printer.PrintCurrent("%s %s%s\n", activeTheme.CurrentMarker, name, suffix)
+	} else {
+		fmt.Printf("  %s%s\n", name, suffix)
+	}
 }
 
-// Success shows success message
-func (pi *ProgressIndicator) Success(message string) {
-	printer := NewColorPrinter()
-	printer.PrintSuccess("✓ %s\n", message)
+// sharedBadge marks a read-only context with the mirror it comes from, or as shared
+func sharedBadge(ctx *context.Context) string {
+	if ctx.Mirror != "" {
+		return "🔒 (" + ctx.Mirror + ")"
+	}
+	return "🔒 (shared)"
 }
 
-// Error shows error message
-func (pi *ProgressIndicator) Error(message string) {
-	printer := NewColorPrinter()
-	printer.PrintError("✗ %s\n", message)
+// ShowHints displays helpful hints to the user
+func (clf *ContextListFormatter) ShowHints(useProject bool, hasProjectContexts bool) {
+	if !useProject && hasProjectContexts {
+		Hintf("Found project-level contexts. Use --in-project to see them.")
+	}
 }
@@ -0,0 +1,99 @@
+package ui
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/fatih/color"
+)
+
+// Inline markdown rendered by RenderMarkdown, in the order it is applied
+var (
+	markdownCode   = regexp.MustCompile("`([^`]+)`")
+	markdownLink   = regexp.MustCompile(`\[([^\]]+)\]\(([^)\s]+)\)`)
+	markdownBold   = regexp.MustCompile(`\*\*([^*]+)\*\*|__([^_]+)__`)
+	markdownItalic = regexp.MustCompile(`(^|[\s(])[*_]([^*_\s][^*_]*)[*_]`)
+	markdownList   = regexp.MustCompile(`^(\s*)([-*+]|\d+[.)])\s+(.*)$`)
+	markdownRule   = regexp.MustCompile(`^\s*([-*_]\s*){3,}$`)
+)
+
+// RenderMarkdown renders basic markdown for the terminal in the active
+// theme: headings, lists, block quotes, code blocks and spans, emphasis and
+// links. Anything else is printed as written. Without color, the markup is
+// still removed.
+func RenderMarkdown(text string) string {
+	heading := color.New(append([]color.Attribute{color.Bold}, activeTheme.Info...)...)
+	code := color.New(activeTheme.Warning...)
+	quote := color.New(color.Faint)
+
+	var out strings.Builder
+	inFence := false
+	for _, line := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			inFence = !inFence
+			continue
+		}
+		if inFence {
+			out.WriteString("    " + code.Sprint(line) + "\n")
+			continue
+		}
+
+		switch {
+		case strings.HasPrefix(trimmed, "#"):
+			title := strings.TrimSpace(strings.TrimLeft(trimmed, "#"))
+			out.WriteString(heading.Sprint(renderInline(title)) + "\n")
+		case markdownRule.MatchString(line):
+			out.WriteString(quote.Sprint(strings.Repeat("─", 40)) + "\n")
+		case strings.HasPrefix(trimmed, ">"):
+			body := strings.TrimSpace(strings.TrimPrefix(trimmed, ">"))
+			out.WriteString(quote.Sprint("│ ") + renderInline(body) + "\n")
+		case markdownList.MatchString(line):
+			parts := markdownList.FindStringSubmatch(line)
+			bullet := parts[2]
+			if bullet == "-" || bullet == "*" || bullet == "+" {
+				bullet = "•"
+			}
+			out.WriteString(parts[1] + bullet + " " + renderInline(parts[3]) + "\n")
+		default:
+			out.WriteString(renderInline(line) + "\n")
+		}
+	}
+	return strings.TrimRight(out.String(), "\n") + "\n"
+}
+
+// renderInline renders code spans, links, bold and italic text in a line.
+// Code spans are set aside first so their content stays literal.
+func renderInline(line string) string {
+	code := color.New(activeTheme.Warning...)
+	bold := color.New(color.Bold)
+	italic := color.New(color.Italic)
+	underline := color.New(color.Underline)
+
+	var spans []string
+	line = markdownCode.ReplaceAllStringFunc(line, func(match string) string {
+		spans = append(spans, code.Sprint(markdownCode.FindStringSubmatch(match)[1]))
+		return "\x00"
+	})
+
+	line = markdownLink.ReplaceAllStringFunc(line, func(match string) string {
+		parts := markdownLink.FindStringSubmatch(match)
+		if parts[1] == parts[2] {
+			return underline.Sprint(parts[2])
+		}
+		return parts[1] + " (" + underline.Sprint(parts[2]) + ")"
+	})
+	line = markdownBold.ReplaceAllStringFunc(line, func(match string) string {
+		parts := markdownBold.FindStringSubmatch(match)
+		return bold.Sprint(parts[1] + parts[2])
+	})
+	line = markdownItalic.ReplaceAllStringFunc(line, func(match string) string {
+		parts := markdownItalic.FindStringSubmatch(match)
+		return parts[1] + italic.Sprint(parts[2])
+	})
+
+	for _, span := range spans {
+		line = strings.Replace(line, "\x00", span, 1)
+	}
+	return line
+}
@@ -0,0 +1,49 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/manifoldco/promptui"
+)
+
+// Choose asks the user to pick one of items with the same picker contexts
+// are selected with, and returns the index of the chosen item. ErrAborted is
+// returned when the user cancels.
+func (s *InteractiveSelector) Choose(label string, items []string) (int, error) {
+	if len(items) == 0 {
+		return -1, fmt.Errorf("nothing to choose from")
+	}
+
+	fzf, err := s.useFzf()
+	if err != nil {
+		return -1, err
+	}
+	if fzf {
+		args := s.fzfBaseArgs(label + ": ")
+		for _, bind := range s.options.Fzf.Bind {
+			args = append(args, "--bind", bind)
+		}
+		selected, err := s.runFzf(append(args, s.options.Fzf.Args...), items)
+		if err != nil {
+			return -1, err
+		}
+		for i, item := range items {
+			if item == selected {
+				return i, nil
+			}
+		}
+		return -1, fmt.Errorf("nothing selected")
+	}
+
+	prompt := promptui.Select{
+		Label: label,
+		Items: items,
+		Size:  10,
+	}
+	index, _, err := prompt.Run()
+	if errors.Is(err, promptui.ErrInterrupt) || errors.Is(err, promptui.ErrEOF) || errors.Is(err, promptui.ErrAbort) {
+		return -1, ErrAborted
+	}
+	return index, err
+}
@@ -0,0 +1,115 @@
+package ui
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/mattn/go-isatty"
+)
+
+// spinnerFrames are drawn in turn while a Progress is running
+var spinnerFrames = []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
+
+// spinnerInterval is how often the spinner redraws
+const spinnerInterval = 100 * time.Millisecond
+
+// progressEnabled is turned off by --quiet
+var progressEnabled = true
+
+// SetProgressEnabled turns the spinner on or off for all subsequent operations
+func SetProgressEnabled(enabled bool) {
+	progressEnabled = enabled
+}
+
+// Progress shows a spinner on stderr for a long-running operation, redrawn in
+// place. It draws nothing when stderr is not a terminal or progress is
+// disabled, so piped output and logs stay clean; Success and Error are always
+// printed.
+type Progress struct {
+	w       io.Writer
+	enabled bool
+
+	mu      sync.Mutex
+	message string
+	stop    chan struct{}
+	done    chan struct{}
+}
+
+// NewProgress creates a progress indicator for message
+func NewProgress(message string) *Progress {
+	return &Progress{
+		w:       os.Stderr,
+		enabled: progressEnabled && isTerminal(os.Stderr),
+		message: message,
+	}
+}
+
+// isTerminal reports whether f is an interactive terminal
+func isTerminal(f *os.File) bool {
+	return isatty.IsTerminal(f.Fd()) || isatty.IsCygwinTerminal(f.Fd())
+}
+
+// Start begins drawing the spinner
+func (p *Progress) Start() {
+	if !p.enabled || p.stop != nil {
+		return
+	}
+
+	p.stop = make(chan struct{})
+	p.done = make(chan struct{})
+	go p.spin()
+}
+
+// Update replaces the message shown next to the spinner
+func (p *Progress) Update(format string, args ...interface{}) {
+	p.mu.Lock()
+	p.message = fmt.Sprintf(format, args...)
+	p.mu.Unlock()
+}
+
+// Stop removes the spinner, leaving the cursor at the start of an empty line
+func (p *Progress) Stop() {
+	if p.stop == nil {
+		return
+	}
+
+	close(p.stop)
+	<-p.done
+	p.stop = nil
+}
+
+// Success stops the spinner and reports success
+func (p *Progress) Success(format string, args ...interface{}) {
+	p.Stop()
+	NewColorPrinter().PrintSuccess("✓ %s\n", fmt.Sprintf(format, args...))
+}
+
+// Error stops the spinner and reports failure
+func (p *Progress) Error(format string, args ...interface{}) {
+	p.Stop()
+	NewColorPrinter().PrintError("✗ %s\n", fmt.Sprintf(format, args...))
+}
+
+// spin redraws the spinner until Stop is called
+func (p *Progress) spin() {
+	defer close(p.done)
+
+	ticker := time.NewTicker(spinnerInterval)
+	defer ticker.Stop()
+
+	for frame := 0; ; frame++ {
+		p.mu.Lock()
+		fmt.Fprintf(p.w, "\r\033[K%s %s", spinnerFrames[frame%len(spinnerFrames)], p.message)
+		p.mu.Unlock()
+
+		select {
+		case <-p.stop:
+			fmt.Fprint(p.w, "\r\033[K")
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package ui
+
+import (
+	"io"
+	"sync"
+)
+
+// refreshableStdin lets the built-in picker be restarted without losing
+// keystrokes. A single goroutine reads the terminal; each picker run reads
+// through a round, and cancelling a round ends that run with io.EOF.
+type refreshableStdin struct {
+	chunks chan []byte
+
+	mu      sync.Mutex
+	pending []byte // Read from the terminal but not consumed yet
+}
+
+// newRefreshableStdin starts reading r in the background
+func newRefreshableStdin(r io.Reader) *refreshableStdin {
+	in := &refreshableStdin{chunks: make(chan []byte)}
+	go in.pump(r)
+	return in
+}
+
+// pump forwards everything read from r until it fails
+func (in *refreshableStdin) pump(r io.Reader) {
+	for {
+		buf := make([]byte, 256)
+		n, err := r.Read(buf)
+		if n > 0 {
+			in.chunks <- buf[:n]
+		}
+		if err != nil {
+			close(in.chunks)
+			return
+		}
+	}
+}
+
+// newRound returns a reader for one picker run
+func (in *refreshableStdin) newRound() *stdinRound {
+	return &stdinRound{in: in, done: make(chan struct{})}
+}
+
+// stdinRound is one picker run's view of stdin
+type stdinRound struct {
+	in   *refreshableStdin
+	done chan struct{}
+	once sync.Once
+}
+
+// cancel makes pending and future reads return io.EOF
+func (r *stdinRound) cancel() {
+	r.once.Do(func() { close(r.done) })
+}
+
+// cancelled reports whether cancel was called
+func (r *stdinRound) cancelled() bool {
+	select {
+	case <-r.done:
+		return true
+	default:
+		return false
+	}
+}
+
+// Read returns buffered input, waiting for more unless the round is cancelled
+func (r *stdinRound) Read(b []byte) (int, error) {
+	if r.cancelled() {
+		return 0, io.EOF
+	}
+
+	r.in.mu.Lock()
+	if len(r.in.pending) == 0 {
+		r.in.mu.Unlock()
+		select {
+		case chunk, ok := <-r.in.chunks:
+			if !ok {
+				return 0, io.EOF
+			}
+			r.in.mu.Lock()
+			r.in.pending = append(r.in.pending, chunk...)
+			if r.cancelled() {
+				// Keep the keys for the next round
+				r.in.mu.Unlock()
+				return 0, io.EOF
+			}
+		case <-r.done:
+			return 0, io.EOF
+		}
+	}
+	defer r.in.mu.Unlock()
+
+	n := copy(b, r.in.pending)
+	r.in.pending = r.in.pending[n:]
+	return n, nil
+}
+
+// Close does nothing; the terminal stays open for the next round
+func (r *stdinRound) Close() error {
+	return nil
+}
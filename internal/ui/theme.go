@@ -0,0 +1,157 @@
+package ui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/hungthai1401/occtx/internal/config"
+)
+
+// Theme is the palette and symbols used for all occtx output
+type Theme struct {
+	Success []color.Attribute
+	Error   []color.Attribute
+	Info    []color.Attribute
+	Warning []color.Attribute
+	Current []color.Attribute
+
+	CurrentMarker  string // Prefix for the current context in lists
+	ActiveSymbol   string // Cursor in the built-in picker
+	SelectedSymbol string // Shown after picking in the built-in picker
+
+	// Templates override the built-in picker's promptui templates when set
+	Templates config.TemplatesConfig
+}
+
+// builtinThemes are the themes selectable by name
+var builtinThemes = map[string]Theme{
+	"default": {
+		Success:        []color.Attribute{color.FgGreen, color.Bold},
+		Error:          []color.Attribute{color.FgRed, color.Bold},
+		Info:           []color.Attribute{color.FgBlue},
+		Warning:        []color.Attribute{color.FgYellow},
+		Current:        []color.Attribute{color.FgGreen, color.Bold},
+		CurrentMarker:  "*",
+		ActiveSymbol:   "▸",
+		SelectedSymbol: "✓",
+	},
+	// Readable on both light and dark backgrounds
+	"high-contrast": {
+		Success:        []color.Attribute{color.FgBlue, color.Bold},
+		Error:          []color.Attribute{color.FgRed, color.Bold, color.Underline},
+		Info:           []color.Attribute{color.FgMagenta, color.Bold},
+		Warning:        []color.Attribute{color.FgRed, color.Bold},
+		Current:        []color.Attribute{color.ReverseVideo, color.Bold},
+		CurrentMarker:  ">",
+		ActiveSymbol:   ">",
+		SelectedSymbol: "+",
+	},
+	// No colors, only text attributes
+	"monochrome": {
+		Success:        []color.Attribute{color.Bold},
+		Error:          []color.Attribute{color.Bold},
+		Info:           nil,
+		Warning:        []color.Attribute{color.Underline},
+		Current:        []color.Attribute{color.Bold},
+		CurrentMarker:  "*",
+		ActiveSymbol:   ">",
+		SelectedSymbol: "*",
+	},
+}
+
+// activeTheme is used by NewColorPrinter and the built-in picker
+var activeTheme = builtinThemes["default"]
+
+// colorAttributes maps names accepted in the config file to color attributes
+var colorAttributes = map[string]color.Attribute{
+	"black": color.FgBlack, "red": color.FgRed, "green": color.FgGreen, "yellow": color.FgYellow,
+	"blue": color.FgBlue, "magenta": color.FgMagenta, "cyan": color.FgCyan, "white": color.FgWhite,
+	"hi-black": color.FgHiBlack, "hi-red": color.FgHiRed, "hi-green": color.FgHiGreen, "hi-yellow": color.FgHiYellow,
+	"hi-blue": color.FgHiBlue, "hi-magenta": color.FgHiMagenta, "hi-cyan": color.FgHiCyan, "hi-white": color.FgHiWhite,
+	"bold": color.Bold, "faint": color.Faint, "italic": color.Italic, "underline": color.Underline,
+	"reverse": color.ReverseVideo,
+}
+
+// ThemeNames returns the names of the built-in themes
+func ThemeNames() []string {
+	names := make([]string, 0, len(builtinThemes))
+	for name := range builtinThemes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// SetTheme selects the theme used by all subsequent output
+func SetTheme(theme Theme) {
+	activeTheme = theme
+}
+
+// CurrentTheme returns the theme in use
+func CurrentTheme() Theme {
+	return activeTheme
+}
+
+// ThemeFromConfig builds a theme from a built-in base plus config overrides
+func ThemeFromConfig(cfg config.UIConfig) (Theme, error) {
+	name := cfg.Theme
+	if name == "" {
+		name = "default"
+	}
+
+	theme, ok := builtinThemes[name]
+	if !ok {
+		return Theme{}, fmt.Errorf("unknown theme '%s'. Available themes: %s", name, strings.Join(ThemeNames(), ", "))
+	}
+
+	targets := map[string]*[]color.Attribute{
+		"success": &theme.Success,
+		"error":   &theme.Error,
+		"info":    &theme.Info,
+		"warning": &theme.Warning,
+		"current": &theme.Current,
+	}
+	for role, spec := range cfg.Colors {
+		target, ok := targets[role]
+		if !ok {
+			return Theme{}, fmt.Errorf("unknown color role '%s' (expected success, error, info, warning or current)", role)
+		}
+		attrs, err := parseColorSpec(spec)
+		if err != nil {
+			return Theme{}, fmt.Errorf("invalid color for '%s': %v", role, err)
+		}
+		*target = attrs
+	}
+
+	if cfg.Symbols.Current != "" {
+		theme.CurrentMarker = cfg.Symbols.Current
+	}
+	if cfg.Symbols.Active != "" {
+		theme.ActiveSymbol = cfg.Symbols.Active
+	}
+	if cfg.Symbols.Selected != "" {
+		theme.SelectedSymbol = cfg.Symbols.Selected
+	}
+
+	theme.Templates = cfg.Templates
+	return theme, nil
+}
+
+// parseColorSpec parses a comma-separated list such as "blue,bold"
+func parseColorSpec(spec string) ([]color.Attribute, error) {
+	var attrs []color.Attribute
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.ToLower(strings.TrimSpace(part))
+		if part == "" || part == "none" {
+			continue
+		}
+		attr, ok := colorAttributes[part]
+		if !ok {
+			return nil, fmt.Errorf("unknown color or attribute '%s'", part)
+		}
+		attrs = append(attrs, attr)
+	}
+	return attrs, nil
+}
@@ -0,0 +1,45 @@
+// Package util holds small string and JSON helpers shared across occtx
+package util
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+)
+
+// IsJSONCPath reports whether path names a JSONC file
+func IsJSONCPath(path string) bool {
+	return strings.HasSuffix(path, ".jsonc")
+}
+
+// StripJSONCComments removes the lines of JSONC data that start with "//",
+// the comments occtx supports. Data without any "//" is returned as is,
+// without copying.
+func StripJSONCComments(data []byte) []byte {
+	if !bytes.Contains(data, []byte("//")) {
+		return data
+	}
+
+	clean := make([]byte, 0, len(data))
+	for len(data) > 0 {
+		line := data
+		if i := bytes.IndexByte(data, '\n'); i >= 0 {
+			line, data = data[:i+1], data[i+1:]
+		} else {
+			data = nil
+		}
+		if !bytes.HasPrefix(bytes.TrimSpace(line), []byte("//")) {
+			clean = append(clean, line...)
+		}
+	}
+	return clean
+}
+
+// ParseJSONC decodes a JSONC object, stripping its comments first
+func ParseJSONC(data []byte) (map[string]interface{}, error) {
+	var parsed map[string]interface{}
+	if err := json.Unmarshal(StripJSONCComments(data), &parsed); err != nil {
+		return nil, err
+	}
+	return parsed, nil
+}
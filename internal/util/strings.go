@@ -0,0 +1,32 @@
+package util
+
+import (
+	"sort"
+	"strings"
+)
+
+// Contains reports whether values contains s
+func Contains(values []string, s string) bool {
+	for _, value := range values {
+		if value == s {
+			return true
+		}
+	}
+	return false
+}
+
+// ContainsFold reports whether values contains s, ignoring case
+func ContainsFold(values []string, s string) bool {
+	for _, value := range values {
+		if strings.EqualFold(value, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// ContainsSorted reports whether the sorted values contain s, by binary search
+func ContainsSorted(sorted []string, s string) bool {
+	i := sort.SearchStrings(sorted, s)
+	return i < len(sorted) && sorted[i] == s
+}
@@ -0,0 +1,250 @@
+// Package keypath selects, reads and writes values inside decoded JSON, such
+// as an opencode config, by dotted key paths ("provider.*.options.apiKey") or
+// JSON pointers ("/provider/anthropic/api"). It is the path engine occtx uses
+// for --strip-keys, --json-path and list filters, exported for plugins and
+// hooks written in Go.
+//
+// Paths work on the values encoding/json decodes into interface{}:
+// map[string]interface{} for objects and []interface{} for arrays. A "*"
+// segment matches every key of an object and every element of an array; a
+// segment of decimal digits also selects that element of an array.
+package keypath
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Wildcard is the segment that matches every key or element
+const Wildcard = "*"
+
+// Path is a parsed key path, one segment per key
+type Path []string
+
+// Match is one value a path selected, with the concrete path to it
+type Match struct {
+	Path  Path
+	Value interface{}
+}
+
+// Parse parses a dotted key path, or a JSON pointer when expr starts with "/"
+func Parse(expr string) (Path, error) {
+	expr = strings.TrimSpace(expr)
+	if strings.HasPrefix(expr, "/") {
+		return ParsePointer(expr)
+	}
+	if expr == "" {
+		return nil, fmt.Errorf("empty key path")
+	}
+
+	path := Path(strings.Split(expr, "."))
+	for _, segment := range path {
+		if segment == "" {
+			return nil, fmt.Errorf("invalid key path '%s': empty segment", expr)
+		}
+	}
+	return path, nil
+}
+
+// ParsePointer parses a JSON pointer (RFC 6901), e.g. "/provider/a~1b" for
+// the key "a/b". Unlike dotted paths, pointers can name keys containing dots.
+func ParsePointer(expr string) (Path, error) {
+	if expr == "" || expr == "/" {
+		return nil, fmt.Errorf("empty key path")
+	}
+	if !strings.HasPrefix(expr, "/") {
+		return nil, fmt.Errorf("invalid JSON pointer '%s': must start with '/'", expr)
+	}
+
+	segments := strings.Split(expr[1:], "/")
+	path := make(Path, len(segments))
+	for i, segment := range segments {
+		if strings.Contains(strings.NewReplacer("~0", "", "~1", "").Replace(segment), "~") {
+			return nil, fmt.Errorf("invalid JSON pointer '%s': '~' must be followed by 0 or 1", expr)
+		}
+		path[i] = strings.NewReplacer("~1", "/", "~0", "~").Replace(segment)
+	}
+	return path, nil
+}
+
+// String returns the dotted form of the path
+func (p Path) String() string {
+	return strings.Join(p, ".")
+}
+
+// Pointer returns the JSON pointer form of the path
+func (p Path) Pointer() string {
+	escape := strings.NewReplacer("~", "~0", "/", "~1")
+	var b strings.Builder
+	for _, segment := range p {
+		b.WriteByte('/')
+		b.WriteString(escape.Replace(segment))
+	}
+	return b.String()
+}
+
+// HasWildcard reports whether the path can select more than one value
+func (p Path) HasWildcard() bool {
+	for _, segment := range p {
+		if segment == Wildcard {
+			return true
+		}
+	}
+	return false
+}
+
+// Select returns every value the path selects in node, objects in key order
+// and arrays in element order
+func (p Path) Select(node interface{}) []Match {
+	var matches []Match
+	p.selectInto(nil, node, &matches)
+	return matches
+}
+
+func (p Path) selectInto(prefix Path, node interface{}, matches *[]Match) {
+	if len(p) == 0 {
+		*matches = append(*matches, Match{Path: append(Path{}, prefix...), Value: node})
+		return
+	}
+
+	switch v := node.(type) {
+	case map[string]interface{}:
+		if p[0] != Wildcard {
+			if child, ok := v[p[0]]; ok {
+				p[1:].selectInto(append(prefix, p[0]), child, matches)
+			}
+			return
+		}
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			p[1:].selectInto(append(prefix, key), v[key], matches)
+		}
+	case []interface{}:
+		if p[0] != Wildcard {
+			if i, ok := index(p[0], len(v)); ok {
+				p[1:].selectInto(append(prefix, p[0]), v[i], matches)
+			}
+			return
+		}
+		for i, item := range v {
+			p[1:].selectInto(append(prefix, strconv.Itoa(i)), item, matches)
+		}
+	}
+}
+
+// Get returns the value at the path in node and whether there is one. With
+// "*" segments, it returns the first value Select would.
+func (p Path) Get(node interface{}) (interface{}, bool) {
+	matches := p.Select(node)
+	if len(matches) == 0 {
+		return nil, false
+	}
+	return matches[0].Value, true
+}
+
+// Matches reports whether the path selects at least one value in node
+func (p Path) Matches(node interface{}) bool {
+	return len(p.Select(node)) > 0
+}
+
+// Set stores value at the path in node, creating the objects on the way
+// that are missing. Array elements can be replaced but not added, and the
+// path can't contain "*".
+func (p Path) Set(node interface{}, value interface{}) error {
+	if len(p) == 0 {
+		return fmt.Errorf("empty key path")
+	}
+	if p.HasWildcard() {
+		return fmt.Errorf("cannot set '%s': '*' selects more than one value", p)
+	}
+
+	for i, segment := range p {
+		last := i == len(p)-1
+		switch v := node.(type) {
+		case map[string]interface{}:
+			if last {
+				v[segment] = value
+				return nil
+			}
+			child, ok := v[segment]
+			if !ok || child == nil {
+				child = map[string]interface{}{}
+				v[segment] = child
+			}
+			node = child
+		case []interface{}:
+			j, ok := index(segment, len(v))
+			if !ok {
+				return fmt.Errorf("cannot set '%s': no element '%s' in an array of %d", p, segment, len(v))
+			}
+			if last {
+				v[j] = value
+				return nil
+			}
+			node = v[j]
+		default:
+			return fmt.Errorf("cannot set '%s': '%s' is not an object or array", p, p[:i])
+		}
+	}
+	return nil
+}
+
+// Delete removes every value the path selects from node and returns how many
+// were removed. Array elements are only descended into, never removed.
+func (p Path) Delete(node interface{}) int {
+	if len(p) == 0 {
+		return 0
+	}
+
+	switch v := node.(type) {
+	case map[string]interface{}:
+		removed := 0
+		for key, child := range v {
+			if p[0] != Wildcard && p[0] != key {
+				continue
+			}
+			if len(p) == 1 {
+				delete(v, key)
+				removed++
+			} else {
+				removed += p[1:].Delete(child)
+			}
+		}
+		return removed
+	case []interface{}:
+		if len(p) == 1 {
+			return 0
+		}
+		if p[0] != Wildcard {
+			if i, ok := index(p[0], len(v)); ok {
+				return p[1:].Delete(v[i])
+			}
+			return 0
+		}
+		removed := 0
+		for _, item := range v {
+			removed += p[1:].Delete(item)
+		}
+		return removed
+	default:
+		return 0
+	}
+}
+
+// index parses segment as an element index of an array of length n
+func index(segment string, n int) (int, bool) {
+	if segment == "" || strings.TrimLeft(segment, "0123456789") != "" {
+		return 0, false
+	}
+	i, err := strconv.Atoi(segment)
+	if err != nil || i >= n {
+		return 0, false
+	}
+	return i, true
+}
@@ -0,0 +1,88 @@
+package test
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestManager_SwitchKeepsActiveConfigMode(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("file modes are not enforced on Windows")
+	}
+
+	th := NewTestHelper(t)
+	defer th.Cleanup()
+
+	th.CreateSampleConfig()
+	manager := th.CreateManagerWithTempDir()
+	if err := manager.CreateContext("work"); err != nil {
+		t.Fatal(err)
+	}
+
+	active := filepath.Join(th.ConfigDir, "opencode.json")
+	if err := os.Chmod(active, 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := manager.SwitchToContext("work"); err != nil {
+		t.Fatalf("Switch failed: %v", err)
+	}
+	if mode := fileMode(t, active); mode != 0600 {
+		t.Errorf("Expected the switch to keep mode 0600, got %04o", mode)
+	}
+
+	// A missing active config is created with the default mode
+	if err := os.Remove(active); err != nil {
+		t.Fatal(err)
+	}
+	if err := manager.SwitchToContext("work"); err != nil {
+		t.Fatal(err)
+	}
+	if mode := fileMode(t, active); mode != 0644 {
+		t.Errorf("Expected a new active config to get mode 0644, got %04o", mode)
+	}
+}
+
+func TestManager_SwitchNormalizesActiveConfigMode(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("file modes are not enforced on Windows")
+	}
+
+	th := NewTestHelper(t)
+	defer th.Cleanup()
+
+	th.CreateSampleConfig()
+	writeOcctxConfig(t, th.ConfigDir, `{"active_config": {"mode": "0640", "preserve_owner": true}}`)
+	manager := th.CreateManagerWithTempDir()
+	if err := manager.CreateContext("work"); err != nil {
+		t.Fatal(err)
+	}
+
+	active := filepath.Join(th.ConfigDir, "opencode.json")
+	if err := os.Chmod(active, 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := manager.SwitchToContext("work"); err != nil {
+		t.Fatalf("Switch failed: %v", err)
+	}
+	if mode := fileMode(t, active); mode != 0640 {
+		t.Errorf("Expected active_config.mode to set 0640, got %04o", mode)
+	}
+
+	writeOcctxConfig(t, th.ConfigDir, `{"active_config": {"mode": "rw-r--r--"}}`)
+	manager = th.CreateManagerWithTempDir()
+	if err := manager.SwitchToContext("work"); err == nil || !strings.Contains(err.Error(), "active_config.mode") {
+		t.Errorf("Expected an invalid mode to be reported, got %v", err)
+	}
+}
+
+func fileMode(t *testing.T, path string) os.FileMode {
+	t.Helper()
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return info.Mode().Perm()
+}
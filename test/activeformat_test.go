@@ -0,0 +1,113 @@
+package test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const commentedContext = `// Team setup
+{
+  // Dark everywhere
+  "theme": "dark"
+}
+`
+
+func TestManager_SwitchKeepsJSONCComments(t *testing.T) {
+	th := NewTestHelper(t)
+	defer th.Cleanup()
+	th.CreateSampleConfig()
+
+	settingsDir := filepath.Join(th.ConfigDir, "settings")
+	if err := os.MkdirAll(settingsDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(settingsDir, "team.jsonc"), []byte(commentedContext), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	manager := th.CreateManagerWithTempDir()
+	manager.SetForce(true)
+	if err := manager.CreateContext("plain"); err != nil {
+		t.Fatal(err)
+	}
+
+	jsonPath := filepath.Join(th.ConfigDir, "opencode.json")
+	jsoncPath := filepath.Join(th.ConfigDir, "opencode.jsonc")
+
+	plan, err := manager.PlanSwitch("team")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(plan.Files) != 1 || plan.Files[0].Path != jsoncPath || len(plan.Files[0].Changes) == 0 {
+		t.Errorf("Expected a planned change to %s, got %+v", jsoncPath, plan.Files)
+	}
+
+	if err := manager.SwitchToContext("team"); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(jsoncPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != commentedContext {
+		t.Errorf("Expected the context as written, comments included, got:\n%s", data)
+	}
+	if _, err := os.Stat(jsonPath); !os.IsNotExist(err) {
+		t.Errorf("Expected opencode.json to be replaced by opencode.jsonc, got %v", err)
+	}
+	if drifted, err := manager.ActiveDrifted(); err != nil || drifted {
+		t.Errorf("Expected no drift after the switch, got %v, %v", drifted, err)
+	}
+
+	// JSON is valid JSONC, so a JSON context stays in opencode.jsonc
+	if err := manager.SwitchToContext("plain"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(jsoncPath); err != nil {
+		t.Errorf("Expected opencode.jsonc to stay, got %v", err)
+	}
+	if _, err := os.Stat(jsonPath); !os.IsNotExist(err) {
+		t.Errorf("Expected no opencode.json, got %v", err)
+	}
+}
+
+func TestManager_SwitchStrictJSON(t *testing.T) {
+	th := NewTestHelper(t)
+	defer th.Cleanup()
+	th.CreateSampleConfig()
+
+	settingsDir := filepath.Join(th.ConfigDir, "settings")
+	if err := os.MkdirAll(settingsDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(settingsDir, "team.jsonc"), []byte(commentedContext), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(th.ConfigDir, ".occtx-config.json"), []byte(`{"active_config": {"strict_json": true}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	manager := th.CreateManagerWithTempDir()
+	manager.SetForce(true)
+	if err := manager.SwitchToContext("team"); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(th.ConfigDir, "opencode.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var active map[string]interface{}
+	if err := json.Unmarshal(data, &active); err != nil || strings.Contains(string(data), "//") {
+		t.Fatalf("Expected plain JSON, got %v:\n%s", err, data)
+	}
+	if active["theme"] != "dark" {
+		t.Errorf("Expected the context's content, got %v", active)
+	}
+	if _, err := os.Stat(filepath.Join(th.ConfigDir, "opencode.jsonc")); !os.IsNotExist(err) {
+		t.Errorf("Expected no opencode.jsonc, got %v", err)
+	}
+}
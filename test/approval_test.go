@@ -0,0 +1,121 @@
+package test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hungthai1401/occtx/internal/context"
+)
+
+func TestManager_ApprovalWorkflow(t *testing.T) {
+	th := NewTestHelper(t)
+	defer th.Cleanup()
+
+	th.CreateSampleConfig()
+	writeOcctxConfig(t, th.ConfigDir, `{"approval": {"required": true}}`)
+	manager := th.CreateManagerWithTempDir()
+
+	if err := manager.CreateContext("work"); err != nil {
+		t.Fatal(err)
+	}
+
+	status := func() context.ApprovalStatus {
+		ctx, err := manager.GetContext("work")
+		if err != nil {
+			t.Fatal(err)
+		}
+		status, err := manager.ApprovalStatusOf(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return status
+	}
+
+	if got := status(); got != context.StatusDraft {
+		t.Errorf("New context status = %s, want draft", got)
+	}
+
+	// Drafts can be used outside strict mode
+	if err := manager.SwitchToContext("work"); err != nil {
+		t.Fatalf("Non-strict switch to a draft failed: %v", err)
+	}
+
+	manager.SetStrict(true)
+	if err := manager.SwitchToContext("work"); err == nil || !strings.Contains(err.Error(), "draft") {
+		t.Errorf("Expected strict switch to a draft to fail, got %v", err)
+	}
+
+	approval, err := manager.ApproveContext("work")
+	if err != nil {
+		t.Fatalf("ApproveContext failed: %v", err)
+	}
+	if approval.By == "" || status() != context.StatusApproved {
+		t.Errorf("Expected approved status, got %s by %q", status(), approval.By)
+	}
+	if err := manager.SwitchToContext("work"); err != nil {
+		t.Errorf("Strict switch to an approved context failed: %v", err)
+	}
+
+	// Any change invalidates the approval
+	path := filepath.Join(th.SettingsDir, "work.json")
+	if err := os.WriteFile(path, []byte(`{"theme": "changed"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if got := status(); got != context.StatusModified {
+		t.Errorf("Status after edit = %s, want modified", got)
+	}
+	if err := manager.SwitchToContext("work"); err == nil || !strings.Contains(err.Error(), "re-approve") {
+		t.Errorf("Expected strict switch to a modified context to fail, got %v", err)
+	}
+
+	if _, err := manager.ApproveContext("work"); err != nil {
+		t.Fatal(err)
+	}
+	if err := manager.RevokeApproval("work"); err != nil {
+		t.Fatal(err)
+	}
+	if got := status(); got != context.StatusDraft {
+		t.Errorf("Status after revoke = %s, want draft", got)
+	}
+}
+
+func TestIntegration_Approve(t *testing.T) {
+	ith := NewIntegrationTestHelper(t)
+	defer ith.Cleanup()
+
+	ith.CreateSampleConfig()
+	if _, _, err := ith.RunCommand("-n", "work"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Without the approval workflow there is no status to show
+	stdout, _, err := ith.RunCommand("-s", "work", "--meta")
+	if err != nil || strings.Contains(stdout, "Status:") {
+		t.Errorf("Expected no approval status without approval.required, got %s (%v)", stdout, err)
+	}
+
+	writeOcctxConfig(t, ith.ConfigDir, `{"approval": {"required": true}}`)
+	stdout, _, err = ith.RunCommand("-s", "work", "--meta")
+	if err != nil || !strings.Contains(stdout, "Status:  draft") {
+		t.Errorf("Expected a draft status, got %s (%v)", stdout, err)
+	}
+
+	if _, stderr, err := ith.RunCommand("--strict", "work"); err == nil || !strings.Contains(stderr, "occtx approve work") {
+		t.Errorf("Expected strict switch to a draft to fail, got err=%v stderr=%s", err, stderr)
+	}
+
+	if _, stderr, err := ith.RunCommand("approve", "work"); err != nil {
+		t.Fatalf("approve failed: %v\n%s", err, stderr)
+	}
+
+	stdout, _, err = ith.RunCommand("-s", "work", "--meta")
+	if err != nil || !strings.Contains(stdout, "Status:  approved by") {
+		t.Errorf("Expected approval in metadata, got %s (%v)", stdout, err)
+	}
+
+	if _, stderr, err := ith.RunCommand("--strict", "work"); err != nil {
+		t.Errorf("Strict switch to an approved context failed: %v\n%s", err, stderr)
+	}
+}
@@ -0,0 +1,164 @@
+package test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hungthai1401/occtx/internal/context"
+)
+
+func TestManager_ArchiveContext(t *testing.T) {
+	th := NewTestHelper(t)
+	defer th.Cleanup()
+
+	th.CreateSampleConfig()
+	manager := th.CreateManagerWithTempDir()
+	for _, name := range []string{"work", "old"} {
+		if err := manager.CreateContext(name); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := manager.SwitchToContext("old"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := manager.ArchiveContext("old"); err == nil {
+		t.Error("Archiving the current context should fail")
+	}
+	if err := manager.SwitchToContext("work"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := manager.ArchiveContext("old"); err != nil {
+		t.Fatalf("ArchiveContext failed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(th.SettingsDir, "archive", "old.json")); err != nil {
+		t.Errorf("Expected the file in the archive: %v", err)
+	}
+
+	// Gone from listing and switching, with a pointer to unarchive
+	contexts, _ := manager.ListContexts()
+	for _, ctx := range contexts {
+		if ctx.Name == "old" {
+			t.Error("Archived context should not be listed")
+		}
+	}
+	err := manager.SwitchToContext("old")
+	var notFound *context.NotFoundError
+	if !errors.As(err, &notFound) || !notFound.Archived || !strings.Contains(err.Error(), "occtx unarchive old") {
+		t.Errorf("Expected an archived hint, got %v", err)
+	}
+
+	// `occtx -` no longer points at it
+	if err := manager.SwitchToPrevious(); err == nil {
+		t.Error("Previous should have been cleared when its context was archived")
+	}
+
+	archived, err := manager.ListArchivedContexts()
+	if err != nil || len(archived) != 1 || archived[0].Name != "old" {
+		t.Fatalf("Expected 'old' in the archive, got %v (%v)", archived, err)
+	}
+	if meta, _ := manager.GetContextMeta("old"); meta == nil || meta.ArchivedAt == nil {
+		t.Error("Expected the archive time in the metadata")
+	}
+
+	// Verify doesn't report archived files as missing
+	results, err := manager.VerifyContexts()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, result := range results {
+		if result.Status != context.VerifyOK {
+			t.Errorf("Unexpected verify result %+v", result)
+		}
+	}
+
+	if err := manager.UnarchiveContext("old"); err != nil {
+		t.Fatalf("UnarchiveContext failed: %v", err)
+	}
+	if err := manager.SwitchToContext("old"); err != nil {
+		t.Errorf("Switching to an unarchived context failed: %v", err)
+	}
+	if meta, _ := manager.GetContextMeta("old"); meta == nil || meta.ArchivedAt != nil {
+		t.Error("Expected the archive time to be cleared")
+	}
+
+	if err := manager.UnarchiveContext("old"); err == nil {
+		t.Error("Unarchiving a context that isn't archived should fail")
+	}
+}
+
+func TestManager_UnarchiveConflict(t *testing.T) {
+	th := NewTestHelper(t)
+	defer th.Cleanup()
+
+	th.CreateSampleConfig()
+	manager := th.CreateManagerWithTempDir()
+	if err := manager.CreateContext("demo"); err != nil {
+		t.Fatal(err)
+	}
+	if err := manager.ArchiveContext("demo"); err != nil {
+		t.Fatal(err)
+	}
+
+	// A new context took the name meanwhile
+	if err := manager.CreateContext("demo"); err != nil {
+		t.Fatal(err)
+	}
+	if err := manager.UnarchiveContext("demo"); err == nil || !strings.Contains(err.Error(), "already exists") {
+		t.Errorf("Expected a conflict, got %v", err)
+	}
+	if err := manager.ArchiveContext("demo"); err == nil {
+		t.Error("Archiving over an archived context of the same name should fail")
+	}
+}
+
+func TestIntegration_Archive(t *testing.T) {
+	ith := NewIntegrationTestHelper(t)
+	defer ith.Cleanup()
+
+	ith.CreateSampleConfig()
+	for _, name := range []string{"work", "demo-1", "demo-2"} {
+		if _, _, err := ith.RunCommand("-n", name); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if _, stderr, err := ith.RunCommand("archive", "demo-1", "demo-2"); err != nil {
+		t.Fatalf("archive failed: %v\n%s", err, stderr)
+	}
+
+	stdout, _, err := ith.RunCommand()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(stdout, "demo-") {
+		t.Errorf("Archived contexts should not be listed, got:\n%s", stdout)
+	}
+
+	stdout, _, err = ith.RunCommand("archive", "--list")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(stdout, "demo-1 (archived ") || !strings.Contains(stdout, "demo-2") {
+		t.Errorf("Expected archived contexts, got:\n%s", stdout)
+	}
+
+	if _, stderr, err := ith.RunCommand("demo-1"); err == nil || !strings.Contains(stderr, "is archived") {
+		t.Errorf("Expected switching to an archived context to fail with a hint, got %v\n%s", err, stderr)
+	}
+
+	if _, stderr, err := ith.RunCommand("unarchive", "demo-1"); err != nil {
+		t.Fatalf("unarchive failed: %v\n%s", err, stderr)
+	}
+	if _, _, err := ith.RunCommand("demo-1"); err != nil {
+		t.Errorf("switch after unarchive failed: %v", err)
+	}
+
+	if _, _, err := ith.RunCommand("archive"); err == nil {
+		t.Error("archive without names or --list should fail")
+	}
+}
@@ -0,0 +1,258 @@
+package test
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// backupEntries lists the file names inside a backup archive
+func backupEntries(t *testing.T, path string) []string {
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	gz, err := gzip.NewReader(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tr := tar.NewReader(gz)
+
+	var names []string
+	for {
+		header, err := tr.Next()
+		if err != nil {
+			break
+		}
+		names = append(names, header.Name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func TestManager_Backup(t *testing.T) {
+	th := NewTestHelper(t)
+	defer th.Cleanup()
+
+	th.CreateSampleConfig()
+	if err := os.WriteFile(filepath.Join(th.ConfigDir, ".occtx-config.json"), []byte(`{"backup": {"keep": 5}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	manager := th.CreateManagerWithTempDir()
+	if err := manager.CreateContext("work"); err != nil {
+		t.Fatal(err)
+	}
+	if err := manager.SwitchToContext("work"); err != nil {
+		t.Fatal(err)
+	}
+
+	backup, err := manager.CreateBackup()
+	if err != nil {
+		t.Fatalf("CreateBackup failed: %v", err)
+	}
+	if filepath.Dir(backup.Path) != filepath.Join(th.SettingsDir, "backups") {
+		t.Errorf("Unexpected backup location %s", backup.Path)
+	}
+	if runtime.GOOS != "windows" {
+		if info, err := os.Stat(backup.Path); err != nil || info.Mode().Perm() != 0600 {
+			t.Errorf("Expected a private backup file, got %v (%v)", info.Mode(), err)
+		}
+	}
+
+	entries := strings.Join(backupEntries(t, backup.Path), " ")
+	for _, want := range []string{"opencode.json", ".occtx-config.json", "settings/work.json", "settings/.occtx-state.json"} {
+		if !strings.Contains(entries, want) {
+			t.Errorf("Expected %s in the backup, got %s", want, entries)
+		}
+	}
+
+	// Backups don't contain earlier backups or show up as contexts
+	second, err := manager.CreateBackup()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(strings.Join(backupEntries(t, second.Path), " "), "backups/") {
+		t.Error("A backup should not include earlier backups")
+	}
+	contexts, _ := manager.ListContexts()
+	if len(contexts) != 1 {
+		t.Errorf("Expected only 'work' to be listed, got %d contexts", len(contexts))
+	}
+
+	third, err := manager.CreateBackup()
+	if err != nil {
+		t.Fatal(err)
+	}
+	backups, err := manager.ListBackups()
+	if err != nil || len(backups) != 3 {
+		t.Fatalf("Expected 3 backups, got %d (%v)", len(backups), err)
+	}
+	if backups[0].Name != third.Name || backups[2].Name != backup.Name {
+		t.Errorf("Expected newest first, got %s, %s, %s", backups[0].Name, backups[1].Name, backups[2].Name)
+	}
+
+	pruned, err := manager.PruneBackups(2)
+	if err != nil || len(pruned) != 1 || pruned[0].Name != backup.Name {
+		t.Fatalf("Expected the oldest backup pruned, got %v (%v)", pruned, err)
+	}
+	if _, err := os.Stat(backup.Path); !os.IsNotExist(err) {
+		t.Error("Pruned backup should be deleted")
+	}
+	if _, err := manager.PruneBackups(0); err == nil {
+		t.Error("Pruning every backup should be refused")
+	}
+}
+
+func TestManager_RestoreBackup(t *testing.T) {
+	th := NewTestHelper(t)
+	defer th.Cleanup()
+
+	th.CreateSampleConfig()
+	manager := th.CreateManagerWithTempDir()
+	if err := manager.CreateContext("work"); err != nil {
+		t.Fatal(err)
+	}
+	if err := manager.SwitchToContext("work"); err != nil {
+		t.Fatal(err)
+	}
+	workPath := filepath.Join(th.SettingsDir, "work.json")
+	original, err := os.ReadFile(workPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	backup, err := manager.CreateBackup()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Change things after the backup
+	if err := os.WriteFile(workPath, []byte(`{"theme": "changed"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := manager.CreateContext("later"); err != nil {
+		t.Fatal(err)
+	}
+
+	safety, err := manager.RestoreBackup(backup.Name)
+	if err != nil {
+		t.Fatalf("RestoreBackup failed: %v", err)
+	}
+	if restored, _ := os.ReadFile(workPath); string(restored) != string(original) {
+		t.Errorf("Expected work.json restored, got %s", restored)
+	}
+	if _, err := os.Stat(filepath.Join(th.SettingsDir, "later.json")); err != nil {
+		t.Error("Contexts created after the backup should be left alone")
+	}
+	if current, _ := manager.GetCurrentContext(); current != "work" {
+		t.Errorf("Expected the state restored with current context 'work', got '%s'", current)
+	}
+
+	// The state before the restore was backed up, so the restore can be undone
+	if safety == nil || safety.Name == backup.Name {
+		t.Fatalf("Expected a new backup of the state before the restore, got %+v", safety)
+	}
+	if _, err := manager.RestoreBackup(safety.Name); err != nil {
+		t.Fatal(err)
+	}
+	if undone, _ := os.ReadFile(workPath); string(undone) != `{"theme": "changed"}` {
+		t.Errorf("Expected restoring the safety backup to undo the restore, got %s", undone)
+	}
+
+	if _, err := manager.RestoreBackup("occtx-backup-nope.tar.gz"); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("Expected a not found error, got %v", err)
+	}
+}
+
+func TestIntegration_BackupCreateAndPrune(t *testing.T) {
+	ith := NewIntegrationTestHelper(t)
+	defer ith.Cleanup()
+
+	ith.CreateSampleConfig()
+	if _, _, err := ith.RunCommand("-n", "work"); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(ith.ConfigDir, ".occtx-config.json"), []byte(`{"backup": {"keep": 2}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 3; i++ {
+		if _, stderr, err := ith.RunCommand("backup", "create", "--prune"); err != nil {
+			t.Fatalf("backup create failed: %v\n%s", err, stderr)
+		}
+	}
+
+	stdout, _, err := ith.RunCommand("backup", "list")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lines := strings.Split(strings.TrimSpace(stdout), "\n"); len(lines) != 2 {
+		t.Errorf("Expected backup.keep=2 to leave 2 backups, got:\n%s", stdout)
+	}
+}
+
+func TestIntegration_BackupSchedule(t *testing.T) {
+	// The fake systemctl is a shell script
+	if runtime.GOOS != "linux" {
+		t.Skip("schedule test fakes systemctl")
+	}
+
+	ith := NewIntegrationTestHelper(t)
+	defer ith.Cleanup()
+
+	ith.CreateSampleConfig()
+	_, argsFile := installRecorder(t, ith.TempDir, "systemctl")
+	binDir := filepath.Join(ith.TempDir, "bin")
+	unitDir := filepath.Join(ith.TempDir, ".config", "systemd", "user")
+
+	stdout, _, err := runWithPath(ith, binDir, "doctor")
+	if err != nil || !strings.Contains(stdout, "Backup schedule: not installed") {
+		t.Errorf("Expected doctor to report no schedule, got %v\n%s", err, stdout)
+	}
+
+	if _, stderr, err := runWithPath(ith, binDir, "backup", "install-schedule", "--every", "weekly"); err != nil {
+		t.Fatalf("install-schedule failed: %v\n%s", err, stderr)
+	}
+	if got := readRecordedArgs(t, argsFile); got != "--user\nenable\n--now\nocctx-backup.timer" {
+		t.Errorf("Expected the timer to be enabled, got %q", got)
+	}
+
+	timer, err := os.ReadFile(filepath.Join(unitDir, "occtx-backup.timer"))
+	if err != nil || !strings.Contains(string(timer), "OnCalendar=weekly") {
+		t.Errorf("Expected a weekly timer, got %q (%v)", timer, err)
+	}
+	service, err := os.ReadFile(filepath.Join(unitDir, "occtx-backup.service"))
+	if err != nil || !strings.Contains(string(service), `"backup" "create" "--prune"`) ||
+		!strings.Contains(string(service), `"--config-dir" "`+ith.ConfigDir+`"`) {
+		t.Errorf("Unexpected service unit %q (%v)", service, err)
+	}
+
+	stdout, _, _ = runWithPath(ith, binDir, "doctor")
+	if !strings.Contains(stdout, "Backup schedule: installed with systemd") {
+		t.Errorf("Expected doctor to detect the schedule, got:\n%s", stdout)
+	}
+
+	if _, _, err := runWithPath(ith, binDir, "backup", "install-schedule", "--every", "monthly"); err == nil {
+		t.Error("An unsupported frequency should fail")
+	}
+	if _, _, err := runWithPath(ith, binDir, "--in-project", "backup", "install-schedule"); err == nil {
+		t.Error("Scheduling project backups should fail")
+	}
+
+	if _, stderr, err := runWithPath(ith, binDir, "backup", "install-schedule", "--uninstall"); err != nil {
+		t.Fatalf("uninstall failed: %v\n%s", err, stderr)
+	}
+	if _, err := os.Stat(filepath.Join(unitDir, "occtx-backup.timer")); !os.IsNotExist(err) {
+		t.Error("Expected the timer unit to be removed")
+	}
+	if _, _, err := runWithPath(ith, binDir, "backup", "install-schedule", "--uninstall"); err == nil {
+		t.Error("Uninstalling twice should fail")
+	}
+}
@@ -0,0 +1,148 @@
+package test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hungthai1401/occtx/internal/context"
+)
+
+func TestManager_CompareAndUpdateFromShared(t *testing.T) {
+	th := NewTestHelper(t)
+	defer th.Cleanup()
+
+	th.CreateSampleConfig()
+	sharedDir := createSharedDir(t, th.TempDir, th.ConfigDir)
+	manager := th.CreateManagerWithTempDir()
+
+	if _, err := manager.CompareWithShared("baseline", ""); err == nil {
+		t.Error("Comparing before taking a local copy should fail")
+	}
+	if _, err := manager.CopyContext("baseline", ""); err != nil {
+		t.Fatal(err)
+	}
+
+	comparison, err := manager.CompareWithShared("baseline", "")
+	if err != nil {
+		t.Fatalf("CompareWithShared failed: %v", err)
+	}
+	if !comparison.UpToDate() || comparison.LocalModified || comparison.SharedUpdated {
+		t.Errorf("A fresh copy should match, got %+v", comparison)
+	}
+
+	// The team publishes a new version
+	sharedFile := filepath.Join(sharedDir, "baseline.jsonc")
+	if err := os.WriteFile(sharedFile, []byte("// managed by IT\n{\"theme\": \"corp-2\", \"autoupdate\": true}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	comparison, err = manager.CompareWithShared("baseline", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if comparison.LocalModified || !comparison.SharedUpdated || len(comparison.Changes) != 2 {
+		t.Errorf("Expected an update without local changes, got %+v", comparison)
+	}
+	kinds := map[string]context.ChangeKind{}
+	for _, change := range comparison.Changes {
+		kinds[change.Path] = change.Kind
+	}
+	if kinds["theme"] != context.ChangeChanged || kinds["autoupdate"] != context.ChangeRemoved {
+		t.Errorf("Unexpected changes %v", kinds)
+	}
+
+	if _, err := manager.UpdateFromShared("baseline", "", false); err != nil {
+		t.Fatalf("Fast-forward failed: %v", err)
+	}
+	ctx, err := manager.GetContext("baseline")
+	if err != nil || ctx.Shared || ctx.Data["theme"] != "corp-2" {
+		t.Fatalf("Expected the local copy to be updated, got %+v (%v)", ctx, err)
+	}
+
+	// Local edits block the next fast-forward until forced
+	if err := os.WriteFile(ctx.FilePath, []byte("{\"theme\": \"mine\", \"autoupdate\": true}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(sharedFile, []byte("{\"theme\": \"corp-3\", \"autoupdate\": true}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	comparison, _ = manager.CompareWithShared("baseline", "")
+	if comparison == nil || !comparison.LocalModified || !comparison.SharedUpdated {
+		t.Errorf("Expected diverged copies, got %+v", comparison)
+	}
+	if _, err := manager.UpdateFromShared("baseline", "", false); err == nil || !strings.Contains(err.Error(), "local changes") {
+		t.Errorf("Expected local changes to block the update, got %v", err)
+	}
+	if _, err := manager.UpdateFromShared("baseline", "", true); err != nil {
+		t.Fatalf("Forced update failed: %v", err)
+	}
+	if ctx, _ := manager.GetContext("baseline"); ctx == nil || ctx.Data["theme"] != "corp-3" {
+		t.Errorf("Expected the forced update to win, got %+v", ctx)
+	}
+	trash, _ := os.ReadDir(filepath.Join(th.SettingsDir, "trash"))
+	if len(trash) != 1 {
+		t.Errorf("Expected the local version in the trash, got %d files", len(trash))
+	}
+}
+
+func TestManager_DiffContexts(t *testing.T) {
+	th := NewTestHelper(t)
+	defer th.Cleanup()
+
+	th.CreateSampleConfig()
+	manager := th.CreateManagerWithTempDir()
+	if err := manager.ImportContext("a", []byte(`{"model": "x", "provider": {"one": {"key": 1}}, "tools": [1]}`)); err != nil {
+		t.Fatal(err)
+	}
+	if err := manager.ImportContext("b", []byte(`{"model": "y", "provider": {"one": {"key": 1}, "two": {}}, "tools": [1]}`)); err != nil {
+		t.Fatal(err)
+	}
+
+	changes, err := manager.DiffContexts("a", "b")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(changes) != 2 || changes[0].Path != "model" || changes[1].Path != "provider.two" || changes[1].Kind != context.ChangeAdded {
+		t.Errorf("Unexpected changes %+v", changes)
+	}
+}
+
+func TestIntegration_DiffAndUpdate(t *testing.T) {
+	ith := NewIntegrationTestHelper(t)
+	defer ith.Cleanup()
+
+	ith.CreateSampleConfig()
+	sharedDir := createSharedDir(t, ith.TempDir, ith.ConfigDir)
+	if _, _, err := ith.RunCommand("copy", "baseline"); err != nil {
+		t.Fatal(err)
+	}
+
+	stdout, _, err := ith.RunCommand("diff", "baseline", "--shared")
+	if err != nil || !strings.Contains(stdout, "matches") {
+		t.Errorf("Expected a fresh copy to match, got %v\n%s", err, stdout)
+	}
+
+	if err := os.WriteFile(filepath.Join(sharedDir, "baseline.jsonc"), []byte("{\"theme\": \"corp-2\"}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	stdout, _, err = ith.RunCommand("diff", "baseline", "--shared")
+	if err != nil || !strings.Contains(stdout, `~ theme: "corp-2" -> "corp"`) || !strings.Contains(stdout, "occtx update baseline --from-shared") {
+		t.Errorf("Unexpected diff output (%v):\n%s", err, stdout)
+	}
+
+	if _, _, err := ith.RunCommand("update", "baseline"); err == nil {
+		t.Error("update without a source should fail")
+	}
+	if _, stderr, err := ith.RunCommand("update", "baseline", "--from-shared"); err != nil {
+		t.Fatalf("update failed: %v\n%s", err, stderr)
+	}
+	stdout, _, _ = ith.RunCommand("diff", "baseline", "--shared")
+	if !strings.Contains(stdout, "matches") {
+		t.Errorf("Expected the copy to match after updating, got:\n%s", stdout)
+	}
+
+	if _, _, err := ith.RunCommand("diff", "baseline"); err == nil {
+		t.Error("diff with one context and no --shared should fail")
+	}
+}
@@ -5,10 +5,10 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
-	"strings"
 	"testing"
 
 	"github.com/hungthai1401/occtx/internal/context"
+	"github.com/hungthai1401/occtx/internal/util"
 )
 
 func BenchmarkContextCreation(b *testing.B) {
@@ -159,19 +159,9 @@ func BenchmarkJSONCCommentStripping(b *testing.B) {
 	b.ResetTimer()
 
 	// Benchmark comment stripping
+	data := []byte(jsoncData)
 	for i := 0; i < b.N; i++ {
-		lines := strings.Split(jsoncData, "\n")
-		var cleanLines []string
-		for _, line := range lines {
-			trimmed := strings.TrimSpace(line)
-			if !strings.HasPrefix(trimmed, "//") {
-				cleanLines = append(cleanLines, line)
-			}
-		}
-		cleanData := strings.Join(cleanLines, "\n")
-
-		var parsed map[string]interface{}
-		if err := json.Unmarshal([]byte(cleanData), &parsed); err != nil {
+		if _, err := util.ParseJSONC(data); err != nil {
 			b.Fatalf("JSONC parsing failed: %v", err)
 		}
 	}
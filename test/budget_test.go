@@ -0,0 +1,145 @@
+package test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hungthai1401/occtx/internal/context"
+)
+
+// Latency budgets for the operations run most often. They are checked
+// against the fastest of several runs, so a busy machine doesn't fail them;
+// -short skips them. BenchmarkListBudget and BenchmarkSwitchBudget measure
+// the same operations for profiling.
+const (
+	budgetContexts = 500
+	listBudget     = 30 * time.Millisecond // Listing budgetContexts contexts
+	switchBudget   = 15 * time.Millisecond // Switching among budgetContexts contexts
+	budgetRuns     = 20
+)
+
+func TestPerformanceBudgets(t *testing.T) {
+	if testing.Short() {
+		t.Skip("performance budgets are skipped in short mode")
+	}
+
+	th := NewTestHelper(t)
+	defer th.Cleanup()
+	th.CreateSampleConfig()
+	writeBudgetContexts(t, th.SettingsDir)
+	manager := th.CreateManagerWithTempDir()
+	manager.SetForce(true)
+
+	list := fastestRun(t, func(i int) error {
+		if _, err := manager.ListContexts(); err != nil {
+			return err
+		}
+		_, err := manager.GetUsage()
+		return err
+	})
+	if list > listBudget {
+		t.Errorf("Listing %d contexts took %s, over the %s budget", budgetContexts, list, listBudget)
+	}
+
+	switchTime := fastestRun(t, func(i int) error {
+		return manager.SwitchToContext(budgetContextName(i))
+	})
+	if switchTime > switchBudget {
+		t.Errorf("Switching among %d contexts took %s, over the %s budget", budgetContexts, switchTime, switchBudget)
+	}
+}
+
+func BenchmarkListBudget(b *testing.B) {
+	manager := setupBudgetBenchmark(b)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := manager.ListContexts(); err != nil {
+			b.Fatal(err)
+		}
+		if _, err := manager.GetUsage(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkSwitchBudget(b *testing.B) {
+	manager := setupBudgetBenchmark(b)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := manager.SwitchToContext(budgetContextName(i)); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// writeBudgetContexts writes budgetContexts contexts into settingsDir
+func writeBudgetContexts(tb testing.TB, settingsDir string) {
+	for i := 0; i < budgetContexts; i++ {
+		data := fmt.Sprintf(`{"theme": "theme-%d", "model": "anthropic/claude-sonnet-4"}`, i)
+		if err := os.WriteFile(filepath.Join(settingsDir, budgetContextName(i)+".json"), []byte(data), 0644); err != nil {
+			tb.Fatal(err)
+		}
+	}
+}
+
+// setupBudgetBenchmark returns a manager for budgetContexts contexts
+func setupBudgetBenchmark(b *testing.B) *context.Manager {
+	bh := setupBenchmarkHelper(b, b.TempDir())
+	writeBudgetContexts(b, bh.SettingsDir)
+	manager := bh.CreateManagerWithTempDir()
+	manager.SetForce(true)
+	return manager
+}
+
+// budgetContextName names the i-th budget context, cycling through them
+func budgetContextName(i int) string {
+	return fmt.Sprintf("context-%03d", i%budgetContexts)
+}
+
+// fastestRun runs op budgetRuns times and returns the fastest run
+func fastestRun(t *testing.T, op func(i int) error) time.Duration {
+	var fastest time.Duration
+	for i := 0; i < budgetRuns; i++ {
+		start := time.Now()
+		if err := op(i); err != nil {
+			t.Fatal(err)
+		}
+		if elapsed := time.Since(start); i == 0 || elapsed < fastest {
+			fastest = elapsed
+		}
+	}
+	return fastest
+}
+
+func TestIntegration_Timings(t *testing.T) {
+	ith := NewIntegrationTestHelper(t)
+	defer ith.Cleanup()
+
+	ith.CreateSampleConfig()
+	if _, _, err := ith.RunCommand("-n", "work"); err != nil {
+		t.Fatal(err)
+	}
+
+	stdout, stderr, err := ith.RunCommand("--timings", "work")
+	if err != nil {
+		t.Fatalf("switch --timings failed: %v\n%s", err, stderr)
+	}
+	for _, phase := range []string{"Timings:", "switch/load", "switch/checks", "switch/write", "switch/state", "total"} {
+		if !strings.Contains(stderr, phase) {
+			t.Errorf("Expected %q in the timings, got:\n%s", phase, stderr)
+		}
+	}
+	if strings.Contains(stdout, "Timings:") {
+		t.Error("Timings should go to stderr")
+	}
+
+	if _, stderr, _ := ith.RunCommand("work"); strings.Contains(stderr, "Timings:") {
+		t.Error("Timings should only be printed with --timings")
+	}
+}
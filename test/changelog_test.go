@@ -0,0 +1,105 @@
+package test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hungthai1401/occtx/internal/context"
+)
+
+func TestManager_ContextLog(t *testing.T) {
+	th := NewTestHelper(t)
+	defer th.Cleanup()
+
+	th.CreateSampleConfig()
+	manager := th.CreateManagerWithTempDir()
+
+	if err := manager.CreateContext("work"); err != nil {
+		t.Fatal(err)
+	}
+	if err := manager.AddNote("work", "  Pinned the model  "); err != nil {
+		t.Fatalf("AddNote failed: %v", err)
+	}
+	if err := manager.AddNote("work", " "); err == nil {
+		t.Error("Expected an empty note to be rejected")
+	}
+
+	merge := context.MergeOptions{Arrays: context.ArraysReplace, Nulls: context.NullsDelete}
+	data := []byte(`{"theme": null, "model": "a/b", "provider": {"openai": {"options": {"apiKey": "sk-secret"}}}}`)
+	if _, err := manager.ImportContextWithOptions("work", data, context.Source{Kind: context.SourceImport}, context.ImportOptions{Format: context.FormatJSON, Merge: &merge}); err != nil {
+		t.Fatalf("Merge failed: %v", err)
+	}
+	if err := manager.RenameContext("work", "team"); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err := manager.ContextLog("team")
+	if err != nil {
+		t.Fatalf("ContextLog failed: %v", err)
+	}
+	var kinds []string
+	for _, entry := range entries {
+		if entry.At.IsZero() || entry.Summary == "" {
+			t.Errorf("Incomplete entry %+v", entry)
+		}
+		kinds = append(kinds, string(entry.Kind))
+	}
+	if got := strings.Join(kinds, " "); got != "created note merged renamed" {
+		t.Fatalf("Expected created, note, merged and renamed entries, got %q", got)
+	}
+	if entries[1].Summary != "Pinned the model" {
+		t.Errorf("Expected the trimmed note, got %q", entries[1].Summary)
+	}
+	merged := entries[2].Summary
+	if !strings.Contains(merged, "+provider.openai") || !strings.Contains(merged, "-theme") {
+		t.Errorf("Expected the merge summary to name the changed paths, got %q", merged)
+	}
+	if strings.Contains(merged, "sk-secret") {
+		t.Errorf("Values must stay out of the log, got %q", merged)
+	}
+	if entries[3].Summary != "renamed from 'work'" {
+		t.Errorf("Unexpected rename entry %q", entries[3].Summary)
+	}
+
+	// Edits are logged by what they changed; unchanged content logs nothing
+	ctx, err := manager.GetContext("team")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := manager.RecordEdit("team", ctx.Data); err != nil {
+		t.Fatal(err)
+	}
+	if entries, _ := manager.ContextLog("team"); len(entries) != 4 {
+		t.Errorf("Expected no entry for an edit without changes, got %d entries", len(entries))
+	}
+
+	if _, err := manager.ContextLog("missing"); err == nil {
+		t.Error("Expected the log of a missing context to fail")
+	}
+}
+
+func TestIntegration_LogAndNote(t *testing.T) {
+	ith := NewIntegrationTestHelper(t)
+	defer ith.Cleanup()
+
+	ith.CreateSampleConfig()
+	if _, _, err := ith.RunCommand("-n", "work"); err != nil {
+		t.Fatal(err)
+	}
+	if _, stderr, err := ith.RunCommand("note", "add", "work", "Ask", "the", "platform", "team"); err != nil {
+		t.Fatalf("note add failed: %v\n%s", err, stderr)
+	}
+
+	stdout, stderr, err := ith.RunCommand("log", "work")
+	if err != nil {
+		t.Fatalf("log failed: %v\n%s", err, stderr)
+	}
+	if !strings.Contains(stdout, "created from active config") || !strings.Contains(stdout, "note  Ask the platform team") {
+		t.Errorf("Expected the creation and the note in the log, got:\n%s", stdout)
+	}
+
+	stdout, _, err = ith.RunCommand("log", "work", "-o", "json")
+	if err != nil || !strings.Contains(stdout, `"kind": "note"`) {
+		t.Errorf("Expected JSON entries, got %v:\n%s", err, stdout)
+	}
+}
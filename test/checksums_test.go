@@ -0,0 +1,240 @@
+package test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hungthai1401/occtx/internal/context"
+)
+
+func TestManager_VerifyContexts(t *testing.T) {
+	th := NewTestHelper(t)
+	defer th.Cleanup()
+
+	th.CreateSampleConfig()
+	manager := th.CreateManagerWithTempDir()
+
+	for _, name := range []string{"intact", "edited", "removed", "broken"} {
+		if err := manager.CreateContext(name); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := manager.RenameContext("intact", "renamed"); err != nil {
+		t.Fatal(err)
+	}
+
+	os.WriteFile(filepath.Join(th.SettingsDir, "edited.json"), []byte(`{"theme": "changed"}`), 0644)
+	os.Remove(filepath.Join(th.SettingsDir, "removed.json"))
+	os.WriteFile(filepath.Join(th.SettingsDir, "broken.json"), []byte(`{not json`), 0644)
+	os.WriteFile(filepath.Join(th.SettingsDir, "manual.json"), []byte(`{}`), 0644)
+
+	results, err := manager.VerifyContexts()
+	if err != nil {
+		t.Fatalf("VerifyContexts failed: %v", err)
+	}
+
+	expected := map[string]context.VerifyStatus{
+		"renamed": context.VerifyOK,
+		"edited":  context.VerifyModified,
+		"removed": context.VerifyMissing,
+		"broken":  context.VerifyCorrupt,
+		"manual":  context.VerifyUntracked,
+	}
+	if len(results) != len(expected) {
+		t.Errorf("Expected %d results, got %+v", len(expected), results)
+	}
+	for _, result := range results {
+		if expected[result.Name] != result.Status {
+			t.Errorf("%s: got status %s, want %s", result.Name, result.Status, expected[result.Name])
+		}
+	}
+
+	// The corrupt file is refused, the rest are still accepted
+	if err := manager.AcceptChecksums(results); err == nil || !strings.Contains(err.Error(), "broken") {
+		t.Fatalf("AcceptChecksums should refuse the corrupt context, got %v", err)
+	}
+
+	results, _ = manager.VerifyContexts()
+	for _, result := range results {
+		if result.Status != context.VerifyOK && result.Name != "broken" {
+			t.Errorf("%s: expected ok after accept, got %s", result.Name, result.Status)
+		}
+	}
+
+	// Deleting a context drops it from the manifest
+	if err := manager.DeleteContext("manual"); err != nil {
+		t.Fatal(err)
+	}
+	results, _ = manager.VerifyContexts()
+	for _, result := range results {
+		if result.Name == "manual" {
+			t.Error("Deleted context should not be reported")
+		}
+	}
+}
+
+func TestIntegration_Verify(t *testing.T) {
+	ith := NewIntegrationTestHelper(t)
+	defer ith.Cleanup()
+
+	ith.CreateSampleConfig()
+
+	if _, _, err := ith.RunCommand("-n", "work"); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, _, err := ith.RunCommand("verify", "--all"); err != nil {
+		t.Fatalf("verify should pass for untouched contexts: %v", err)
+	}
+
+	os.WriteFile(filepath.Join(ith.SettingsDir, "work.json"), []byte(`{"theme": "tampered"}`), 0644)
+
+	stdout, stderr, err := ith.RunCommand("verify", "work")
+	if err == nil {
+		t.Fatal("verify should fail for a modified context")
+	}
+	if !strings.Contains(stdout, "work (modified)") || !strings.Contains(stderr, "failed verification") {
+		t.Errorf("Unexpected output: %s %s", stdout, stderr)
+	}
+
+	if _, _, err := ith.RunCommand("verify", "--all", "--accept"); err != nil {
+		t.Fatalf("verify --accept failed: %v", err)
+	}
+	if _, _, err := ith.RunCommand("verify", "--all"); err != nil {
+		t.Errorf("verify should pass after accepting: %v", err)
+	}
+}
+
+func TestManager_RepairContexts(t *testing.T) {
+	th := NewTestHelper(t)
+	defer th.Cleanup()
+
+	th.CreateSampleConfig()
+	manager := th.CreateManagerWithTempDir()
+
+	good := []byte(`{"theme": "dark"}`)
+	for _, name := range []string{"edited", "broken", "work", "work-dev"} {
+		if err := manager.ImportContext(name, good); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := manager.CreateBackup(); err != nil {
+		t.Fatal(err)
+	}
+	saved, _ := os.ReadFile(filepath.Join(th.SettingsDir, "edited.json"))
+
+	edited := filepath.Join(th.SettingsDir, "edited.json")
+	broken := filepath.Join(th.SettingsDir, "broken.json")
+	os.WriteFile(edited, []byte(`{"theme": "changed"}`), 0644)
+	os.WriteFile(broken, []byte(`{not json`), 0644)
+
+	// A context occtx never recorded can only come back from the trash, and
+	// another context's trashed copy must not be used for it
+	untracked := filepath.Join(th.SettingsDir, "manual.json")
+	trashDir := filepath.Join(th.SettingsDir, "trash")
+	os.MkdirAll(trashDir, 0755)
+	os.WriteFile(filepath.Join(trashDir, "manual-20260101-120000.000000000.json"), []byte(`{"theme": "manual"}`), 0644)
+	os.WriteFile(filepath.Join(trashDir, "work-dev-20260102-120000.000000000.json"), []byte(`{"theme": "other"}`), 0644)
+	os.WriteFile(untracked, []byte(`{not json`), 0644)
+	os.Remove(filepath.Join(th.SettingsDir, "work.json"))
+
+	results, err := manager.VerifyContexts()
+	if err != nil {
+		t.Fatal(err)
+	}
+	repaired, err := manager.RepairContexts(results)
+	if err != nil {
+		t.Fatalf("RepairContexts failed: %v", err)
+	}
+	if len(repaired) != 4 {
+		t.Fatalf("Expected 4 repairs, got %+v", repaired)
+	}
+	for _, repair := range repaired {
+		if repair.From == "" {
+			t.Errorf("%s was not restored", repair.Name)
+		}
+	}
+
+	expected := map[string]string{
+		"edited.json": string(saved),
+		"broken.json": string(saved),
+		"work.json":   string(saved),
+		"manual.json": `{"theme": "manual"}`,
+	}
+	for file, content := range expected {
+		data, err := os.ReadFile(filepath.Join(th.SettingsDir, file))
+		if err != nil || string(data) != content {
+			t.Errorf("%s: got %q (%v), want %q", file, data, err, content)
+		}
+	}
+
+	results, _ = manager.VerifyContexts()
+	for _, result := range results {
+		if result.Status != context.VerifyOK {
+			t.Errorf("%s: expected ok after repair, got %s", result.Name, result.Status)
+		}
+	}
+
+	// The replaced files are kept in the trash
+	entries, _ := os.ReadDir(trashDir)
+	var kept []string
+	for _, entry := range entries {
+		data, _ := os.ReadFile(filepath.Join(trashDir, entry.Name()))
+		kept = append(kept, string(data))
+	}
+	joined := strings.Join(kept, "\n")
+	if !strings.Contains(joined, `{"theme": "changed"}`) || strings.Count(joined, `{not json`) != 2 {
+		t.Errorf("Replaced files were not kept in the trash: %q", kept)
+	}
+}
+
+func TestIntegration_VerifyRestore(t *testing.T) {
+	ith := NewIntegrationTestHelper(t)
+	defer ith.Cleanup()
+
+	ith.CreateSampleConfig()
+
+	if _, _, err := ith.RunCommand("-n", "work"); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(ith.SettingsDir, "work.json")
+	original, _ := os.ReadFile(path)
+	os.WriteFile(path, []byte(`{not json`), 0644)
+
+	// Without a backup there is nothing to restore from
+	stdout, stderr, err := ith.RunCommand("verify", "--all", "--restore")
+	if err == nil || !strings.Contains(stdout+stderr, "No good copy of work") {
+		t.Fatalf("verify --restore without backups should fail: %v %s %s", err, stdout, stderr)
+	}
+
+	// --accept must not re-baseline a file that doesn't parse
+	if _, stderr, err := ith.RunCommand("verify", "--all", "--accept"); err == nil || !strings.Contains(stderr, "cannot accept corrupt") {
+		t.Fatalf("verify --accept should refuse a corrupt context: %v %s", err, stderr)
+	}
+
+	os.WriteFile(path, original, 0644)
+	if _, _, err := ith.RunCommand("backup", "create"); err != nil {
+		t.Fatal(err)
+	}
+	os.WriteFile(path, []byte(`{not json`), 0644)
+
+	stdout, stderr, err = ith.RunCommand("verify", "--all", "--restore")
+	if err != nil {
+		t.Fatalf("verify --restore failed: %v %s %s", err, stdout, stderr)
+	}
+	if !strings.Contains(stdout, "Restored work from") {
+		t.Errorf("Expected a restore message, got: %s", stdout)
+	}
+	if data, _ := os.ReadFile(path); string(data) != string(original) {
+		t.Errorf("work.json was not restored: %s", data)
+	}
+	if _, _, err := ith.RunCommand("verify", "--all"); err != nil {
+		t.Errorf("verify should pass after restoring: %v", err)
+	}
+
+	if _, _, err := ith.RunCommand("verify", "--all", "--accept", "--restore"); err == nil {
+		t.Error("--accept and --restore should be mutually exclusive")
+	}
+}
@@ -0,0 +1,90 @@
+package test
+
+import (
+	"encoding/json"
+	"errors"
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+// runCI runs occtx with extra environment entries and returns stderr and the exit code
+func runCI(t *testing.T, ith *IntegrationTestHelper, env []string, args ...string) (string, int) {
+	t.Helper()
+
+	cmd := exec.Command(ith.BinaryPath, args...)
+	cmd.Env = ith.Env(env...)
+	var stderr strings.Builder
+	cmd.Stderr = &stderr
+	err := cmd.Run()
+
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return stderr.String(), exitErr.ExitCode()
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	return stderr.String(), 0
+}
+
+func TestIntegration_CIMode(t *testing.T) {
+	ith := NewIntegrationTestHelper(t)
+	defer ith.Cleanup()
+
+	ith.CreateSampleConfig()
+	if _, _, err := ith.RunCommand("-n", "opus"); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := ith.RunCommand("tier", "opus", "expensive"); err != nil {
+		t.Fatal(err)
+	}
+	writeOcctxConfig(t, ith.ConfigDir, `{"cost": {"guarded_tiers": ["expensive"], "action": "confirm"}}`)
+
+	tests := []struct {
+		name string
+		env  []string
+		args []string
+		code int
+		kind string
+	}{
+		{"not found", nil, []string{"--ci", "nosuch"}, 3, "not_found"},
+		{"usage", []string{"CI=true"}, []string{"--no-such-flag"}, 2, "usage"},
+		{"no picker", []string{"OCCTX_CI=1"}, []string{"-i"}, 2, "usage"},
+		{"read-only", []string{"CI=1"}, []string{"--read-only", "-n", "other"}, 5, "read_only"},
+		{"offline", []string{"CI=1"}, []string{"--offline", "models", "update"}, 7, "offline"},
+		{"no confirmation", []string{"CI=true"}, []string{"opus"}, 4, "rejected"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stderr, code := runCI(t, ith, tt.env, tt.args...)
+			if code != tt.code {
+				t.Errorf("Expected exit code %d, got %d\n%s", tt.code, code, stderr)
+			}
+
+			var report struct {
+				Error    string `json:"error"`
+				Kind     string `json:"kind"`
+				ExitCode int    `json:"exit_code"`
+			}
+			if err := json.Unmarshal([]byte(strings.TrimSpace(stderr)), &report); err != nil {
+				t.Fatalf("Expected a JSON error, got %q", stderr)
+			}
+			if report.Kind != tt.kind || report.ExitCode != tt.code || report.Error == "" {
+				t.Errorf("Unexpected error report %+v", report)
+			}
+		})
+	}
+
+	// --yes still allows a guarded switch
+	if stderr, code := runCI(t, ith, []string{"CI=true"}, "opus", "--yes"); code != 0 {
+		t.Errorf("Expected the switch to succeed, got %d\n%s", code, stderr)
+	}
+
+	// OCCTX_CI=0 overrides CI, and errors are reported once as text
+	stderr, code := runCI(t, ith, []string{"CI=true", "OCCTX_CI=0"}, "nosuch")
+	if code != 1 || strings.Count(stderr, "Error: ") != 1 {
+		t.Errorf("Expected one plain error and exit code 1, got %d\n%s", code, stderr)
+	}
+}
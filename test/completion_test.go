@@ -0,0 +1,65 @@
+package test
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestIntegration_FlagCompletion(t *testing.T) {
+	ith := NewIntegrationTestHelper(t)
+	defer ith.Cleanup()
+
+	ith.CreateSampleConfig()
+	for _, name := range []string{"work", "demo"} {
+		if _, stderr, err := ith.RunCommand("-n", name); err != nil {
+			t.Fatalf("create %s failed: %v\n%s", name, err, stderr)
+		}
+	}
+
+	// complete returns the candidates cobra's hidden __complete command prints
+	complete := func(args ...string) []string {
+		stdout, stderr, err := ith.RunCommand(append([]string{"__complete"}, args...)...)
+		if err != nil {
+			t.Fatalf("__complete %v failed: %v\n%s", args, err, stderr)
+		}
+		var candidates []string
+		for _, line := range strings.Split(strings.TrimSpace(stdout), "\n") {
+			if line != "" && !strings.HasPrefix(line, ":") {
+				name, _, _ := strings.Cut(line, "\t")
+				candidates = append(candidates, name)
+			}
+		}
+		return candidates
+	}
+
+	tests := []struct {
+		args     []string
+		expected string
+	}{
+		{[]string{"-d", ""}, "demo,work"},
+		{[]string{"-e", ""}, "demo,work"},
+		{[]string{"-s", "w"}, "work"},
+		{[]string{"--export", ""}, "demo,work"},
+		{[]string{"--format", ""}, "json,jsonc"},
+		{[]string{"--sort", ""}, "name,used,frequency"},
+		{[]string{"switch", ""}, "demo,work"},
+		{[]string{"diff", "work", ""}, "demo"},
+		{[]string{"validate", "demo", ""}, "work"},
+	}
+	for _, tt := range tests {
+		if got := strings.Join(complete(tt.args...), ","); got != tt.expected {
+			t.Errorf("Completing %v: expected %s, got %s", tt.args, tt.expected, got)
+		}
+	}
+
+	// Root offers context names alongside subcommands
+	candidates := strings.Join(complete(""), ",")
+	if !strings.Contains(candidates, "work") || !strings.Contains(candidates, "switch") {
+		t.Errorf("Expected contexts and subcommands, got %s", candidates)
+	}
+
+	// Only the first argument of single-name commands is a context
+	if got := complete("which", "work", ""); len(got) != 0 {
+		t.Errorf("Expected no context completion after the name, got %v", got)
+	}
+}
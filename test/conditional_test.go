@@ -0,0 +1,143 @@
+package test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/hungthai1401/occtx/internal/context"
+)
+
+func TestResolveConditionals(t *testing.T) {
+	var data map[string]interface{}
+	source := `{
+		"model": "ollama/qwen",
+		"provider": {
+			"ollama": {
+				"options": {"baseURL": "http://localhost:11434/v1"},
+				"$overrides": [
+					{"$when": {"os": "darwin"}, "options": {"baseURL": "http://localhost:1234/v1"}},
+					{"$when": {"host": "DESK-*"}, "options": {"timeout": 600}}
+				]
+			},
+			"lmstudio": {"$when": {"os": ["darwin", "windows"]}, "npm": "@ai-sdk/openai-compatible"}
+		},
+		"instructions": ["common.md", {"$when": {"arch": "arm64"}, "path": "arm.md"}]
+	}`
+	if err := json.Unmarshal([]byte(source), &data); err != nil {
+		t.Fatal(err)
+	}
+	if !context.HasConditionals(data) {
+		t.Fatal("Expected conditionals to be detected")
+	}
+
+	tests := []struct {
+		name     string
+		platform context.Platform
+		baseURL  string
+		timeout  interface{}
+		lmstudio bool
+		items    int
+	}{
+		{"linux desktop", context.Platform{OS: "linux", Arch: "amd64", Host: "desk-01"}, "http://localhost:11434/v1", 600.0, false, 1},
+		{"mac laptop", context.Platform{OS: "darwin", Arch: "arm64", Host: "laptop"}, "http://localhost:1234/v1", nil, true, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resolved, err := context.ResolveConditionals(data, tt.platform)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if context.HasConditionals(resolved) {
+				t.Errorf("Expected no conditionals left, got %v", resolved)
+			}
+
+			providers := resolved["provider"].(map[string]interface{})
+			options := providers["ollama"].(map[string]interface{})["options"].(map[string]interface{})
+			if options["baseURL"] != tt.baseURL {
+				t.Errorf("Expected baseURL %s, got %v", tt.baseURL, options["baseURL"])
+			}
+			if options["timeout"] != tt.timeout {
+				t.Errorf("Expected timeout %v, got %v", tt.timeout, options["timeout"])
+			}
+			if _, ok := providers["lmstudio"]; ok != tt.lmstudio {
+				t.Errorf("Expected lmstudio present=%v, got %v", tt.lmstudio, providers)
+			}
+			if items := resolved["instructions"].([]interface{}); len(items) != tt.items {
+				t.Errorf("Expected %d instructions, got %v", tt.items, items)
+			}
+		})
+	}
+
+	// The input is left untouched
+	if _, ok := data["provider"].(map[string]interface{})["lmstudio"]; !ok {
+		t.Error("Expected the input to be left untouched")
+	}
+
+	for _, invalid := range []string{
+		`{"$when": {"os": "linux"}}`,
+		`{"a": {"$when": {"kernel": "6"}}}`,
+		`{"a": {"$when": "linux"}}`,
+		`{"a": {"$overrides": [{"b": 1}]}}`,
+	} {
+		var data map[string]interface{}
+		json.Unmarshal([]byte(invalid), &data)
+		if _, err := context.ResolveConditionals(data, context.CurrentPlatform()); err == nil {
+			t.Errorf("Expected an error for %s", invalid)
+		}
+	}
+}
+
+func TestManager_SwitchResolvesConditionals(t *testing.T) {
+	th := NewTestHelper(t)
+	defer th.Cleanup()
+
+	settingsDir := filepath.Join(th.ConfigDir, "settings")
+	if err := os.MkdirAll(settingsDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	shared := `{
+  // Works on every machine
+  "theme": "dark",
+  "$overrides": [{"$when": {"os": "` + runtime.GOOS + `"}, "theme": "light"}],
+  "provider": {"local": {"$when": {"os": "plan9"}, "npm": "x"}}
+}`
+	if err := os.WriteFile(filepath.Join(settingsDir, "shared.jsonc"), []byte(shared), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	manager := th.CreateManagerWithTempDir()
+	manager.SetForce(true)
+
+	results, err := manager.ValidateContexts([]string{"shared"})
+	if err != nil || !results[0].Valid() {
+		t.Errorf("Expected $overrides to pass validation, got %+v, %v", results, err)
+	}
+
+	if err := manager.SwitchToContext("shared"); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(filepath.Join(th.ConfigDir, "opencode.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var active map[string]interface{}
+	if err := json.Unmarshal(data, &active); err != nil {
+		t.Fatalf("Expected plain JSON in the active config, got %v:\n%s", err, data)
+	}
+	if active["theme"] != "light" || strings.Contains(string(data), "$") {
+		t.Errorf("Expected resolved content, got:\n%s", data)
+	}
+	if provider, _ := active["provider"].(map[string]interface{}); len(provider) != 0 {
+		t.Errorf("Expected the plan9-only provider to be dropped, got %v", provider)
+	}
+
+	// The switch is not mistaken for drift
+	if drifted, err := manager.ActiveDrifted(); err != nil || drifted {
+		t.Errorf("Expected no drift after switching, got %v, %v", drifted, err)
+	}
+}
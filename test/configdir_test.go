@@ -0,0 +1,216 @@
+package test
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/hungthai1401/occtx/internal/config"
+)
+
+func TestIntegration_ConfigDirFlag(t *testing.T) {
+	ith := NewIntegrationTestHelper(t)
+	defer ith.Cleanup()
+
+	configDir := filepath.Join(ith.TempDir, "custom-opencode")
+
+	if _, stderr, err := ith.RunCommandWithInput(`{"theme": "dark"}`, "--config-dir", configDir, "--import", "work"); err != nil {
+		t.Fatalf("--config-dir import failed: %v\n%s", err, stderr)
+	}
+	if _, err := os.Stat(filepath.Join(configDir, "settings", "work.json")); err != nil {
+		t.Errorf("Expected context under --config-dir: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(ith.SettingsDir, "work.json")); !os.IsNotExist(err) {
+		t.Error("Context should not land in the default config dir")
+	}
+
+	// The environment variable works the same way
+	cmd := exec.Command(ith.BinaryPath)
+	cmd.Env = ith.Env("OCCTX_CONFIG_DIR=" + configDir)
+	out, err := cmd.Output()
+	if err != nil || !strings.Contains(string(out), "work") {
+		t.Errorf("Expected OCCTX_CONFIG_DIR listing to show 'work', got %q (%v)", out, err)
+	}
+}
+
+func TestIntegration_UnwritableConfigDir(t *testing.T) {
+	ith := NewIntegrationTestHelper(t)
+	defer ith.Cleanup()
+
+	// A settings path that is a regular file can never hold contexts,
+	// even for root, which ignores directory permissions
+	configDir := filepath.Join(ith.TempDir, "blocked")
+	if err := os.MkdirAll(configDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(configDir, "settings"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	_, stderr, err := ith.RunCommandWithInput(`{}`, "--config-dir", configDir, "--import", "work")
+	if err == nil || !strings.Contains(stderr, "is not writable") || !strings.Contains(stderr, "--config-dir") {
+		t.Errorf("Expected a clear not-writable error, got err=%v stderr=%s", err, stderr)
+	}
+}
+
+func TestIntegration_MissingHome(t *testing.T) {
+	ith := NewIntegrationTestHelper(t)
+	defer ith.Cleanup()
+
+	tmpDir := filepath.Join(ith.TempDir, "tmp")
+	if err := os.MkdirAll(tmpDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	var env []string
+	for _, entry := range ith.Env("TMPDIR="+tmpDir, "TMP="+tmpDir, "TEMP="+tmpDir) {
+		key, _, _ := strings.Cut(entry, "=")
+		if key != "HOME" && !strings.EqualFold(key, "USERPROFILE") {
+			env = append(env, entry)
+		}
+	}
+
+	cmd := exec.Command(ith.BinaryPath, "--import", "work")
+	cmd.Env = env
+	cmd.Stdin = strings.NewReader(`{"theme": "dark"}`)
+	var stderr strings.Builder
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		t.Fatalf("import without HOME failed: %v\n%s", err, stderr.String())
+	}
+
+	if !strings.Contains(stderr.String(), "using temporary config dir") {
+		t.Errorf("Expected the temporary mode to be reported, got %s", stderr.String())
+	}
+
+	contextPath := filepath.Join(temporaryConfigDir(t, tmpDir), "settings", "work.json")
+	if _, err := os.Stat(contextPath); err != nil {
+		t.Errorf("Expected context in the temporary config dir: %v", err)
+	}
+}
+
+func TestIntegration_UnwritableHomeConfigDir(t *testing.T) {
+	ith := NewIntegrationTestHelper(t)
+	defer ith.Cleanup()
+
+	tmpDir := filepath.Join(ith.TempDir, "tmp")
+	if err := os.MkdirAll(tmpDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	// A settings path that is a regular file refuses new files, even for root
+	settingsPath := filepath.Join(ith.ConfigDir, "settings")
+	if err := os.RemoveAll(settingsPath); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(settingsPath, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := exec.Command(ith.BinaryPath, "--import", "work")
+	cmd.Env = ith.Env("TMPDIR="+tmpDir, "TMP="+tmpDir, "TEMP="+tmpDir)
+	cmd.Stdin = strings.NewReader(`{"theme": "dark"}`)
+	var stderr strings.Builder
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		t.Fatalf("import with an unwritable config dir failed: %v\n%s", err, stderr.String())
+	}
+	if !strings.Contains(stderr.String(), "is not writable") || !strings.Contains(stderr.String(), "using temporary config dir") {
+		t.Errorf("Expected the temporary mode to be reported, got %s", stderr.String())
+	}
+
+	contextPath := filepath.Join(temporaryConfigDir(t, tmpDir), "settings", "work.json")
+	if _, err := os.Stat(contextPath); err != nil {
+		t.Errorf("Expected context in the temporary config dir: %v", err)
+	}
+
+	// Read-only mode never writes, so it never falls back
+	for _, args := range [][]string{{"--read-only", "ls"}, {"ls"}} {
+		cmd := exec.Command(ith.BinaryPath, args...)
+		cmd.Env = ith.Env("TMPDIR="+tmpDir, "TMP="+tmpDir, "TEMP="+tmpDir, "OCCTX_READONLY="+strconv.FormatBool(len(args) == 1))
+		out, _ := cmd.CombinedOutput()
+		if strings.Contains(string(out), "using temporary config dir") || strings.Contains(string(out), "work") {
+			t.Errorf("Expected %v in read-only mode to stay in the config dir, got %s", args, out)
+		}
+	}
+}
+
+func TestIntegration_ReadOnlyHomeConfigDir(t *testing.T) {
+	if runtime.GOOS == "windows" || os.Geteuid() == 0 {
+		t.Skip("needs directory permissions that apply to the current user")
+	}
+	ith := NewIntegrationTestHelper(t)
+	defer ith.Cleanup()
+
+	ith.CreateSampleConfig()
+	if _, stderr, err := ith.RunCommand("-n", "work"); err != nil {
+		t.Fatalf("-n failed: %v\n%s", err, stderr)
+	}
+	settingsDir := filepath.Join(ith.ConfigDir, "settings")
+	if err := os.Chmod(settingsDir, 0555); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chmod(settingsDir, 0755)
+
+	// Contexts in a readable config dir are still listed...
+	out, stderr, err := ith.RunCommand("ls")
+	if err != nil || !strings.Contains(out, "work") || strings.Contains(stderr, "using temporary config dir") {
+		t.Errorf("Expected the contexts of the read-only config dir, got err=%v\n%s\n%s", err, out, stderr)
+	}
+	// ...and changes to it are refused
+	if _, stderr, err := ith.RunCommandWithInput(`{}`, "--import", "other"); err == nil || !strings.Contains(stderr, "is not writable") {
+		t.Errorf("Expected a clear not-writable error, got err=%v stderr=%s", err, stderr)
+	}
+}
+
+func TestIntegration_SharedTemporaryConfigDir(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the temp dir is per user on Windows")
+	}
+	ith := NewIntegrationTestHelper(t)
+	defer ith.Cleanup()
+
+	tmpDir := filepath.Join(ith.TempDir, "tmp")
+	if err := os.MkdirAll(tmpDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	// Another user could have created it first, open to everyone
+	parent := filepath.Dir(temporaryConfigDir(t, tmpDir))
+	if err := os.Chmod(parent, 0777); err != nil {
+		t.Fatal(err)
+	}
+
+	var env []string
+	for _, entry := range ith.Env("TMPDIR="+tmpDir, "TMP="+tmpDir, "TEMP="+tmpDir) {
+		if key, _, _ := strings.Cut(entry, "="); key != "HOME" {
+			env = append(env, entry)
+		}
+	}
+	cmd := exec.Command(ith.BinaryPath, "--import", "work")
+	cmd.Env = env
+	cmd.Stdin = strings.NewReader(`{"theme": "dark"}`)
+	out, err := cmd.CombinedOutput()
+	if err == nil || !strings.Contains(string(out), "expected 0700") {
+		t.Errorf("Expected a shared temporary config dir to be refused, got err=%v\n%s", err, out)
+	}
+	if _, err := os.Stat(filepath.Join(parent, "opencode", "settings", "work.json")); !os.IsNotExist(err) {
+		t.Error("Expected nothing to be written to a shared temporary config dir")
+	}
+}
+
+// temporaryConfigDir is the config directory occtx falls back to with tmpDir
+// as the temp directory
+func temporaryConfigDir(t *testing.T, tmpDir string) string {
+	t.Helper()
+	t.Setenv("TMPDIR", tmpDir)
+	t.Setenv("TMP", tmpDir)
+	t.Setenv("TEMP", tmpDir)
+	dir, err := config.TemporaryConfigDir()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
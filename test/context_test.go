@@ -5,6 +5,7 @@ import (
 	"os"
 	"path/filepath"
 	"runtime"
+	"strings"
 	"testing"
 
 	"github.com/hungthai1401/occtx/internal/context"
@@ -51,6 +52,10 @@ func (th *TestHelper) Cleanup() {
 func (th *TestHelper) SetupEnvironment() (func(), error) {
 	var oldVars map[string]string = make(map[string]string)
 
+	// XDG_CONFIG_HOME would move the config dir out of the temp home
+	xdgConfigHome, hadXDG := os.LookupEnv("XDG_CONFIG_HOME")
+	os.Unsetenv("XDG_CONFIG_HOME")
+
 	if runtime.GOOS == "windows" {
 		// On Windows, set USERPROFILE and APPDATA
 		if val := os.Getenv("USERPROFILE"); val != "" {
@@ -76,6 +81,9 @@ func (th *TestHelper) SetupEnvironment() (func(), error) {
 
 	// Return cleanup function
 	return func() {
+		if hadXDG {
+			os.Setenv("XDG_CONFIG_HOME", xdgConfigHome)
+		}
 		if runtime.GOOS == "windows" {
 			if val, ok := oldVars["USERPROFILE"]; ok {
 				os.Setenv("USERPROFILE", val)
@@ -307,10 +315,10 @@ func TestManager_CreateContextWithFormat_WithMockedPaths(t *testing.T) {
 	}
 
 	content := string(data)
-	if !containsString(content, "// opencode context: test-jsonc") {
+	if !strings.Contains(content, "// opencode context: test-jsonc") {
 		t.Error("JSONC context file missing expected comment header")
 	}
-	if !containsString(content, "// Format: JSONC") {
+	if !strings.Contains(content, "// Format: JSONC") {
 		t.Error("JSONC context file missing format comment")
 	}
 }
@@ -500,11 +508,3 @@ func TestManager_RenameContext_WithMockedPaths(t *testing.T) {
 		t.Error("New context file was not created")
 	}
 }
-
-// Helper function
-func containsString(s, substr string) bool {
-	return len(s) >= len(substr) &&
-		(s == substr ||
-			containsString(s[1:], substr) ||
-			(len(s) > 0 && s[:len(substr)] == substr))
-}
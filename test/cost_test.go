@@ -0,0 +1,152 @@
+package test
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hungthai1401/occtx/internal/context"
+)
+
+// hoursWindow formats a working-hours window relative to now
+func hoursWindow(from, to time.Duration) string {
+	now := time.Now()
+	return now.Add(from).Format("15:04") + "-" + now.Add(to).Format("15:04")
+}
+
+func TestManager_CostTier(t *testing.T) {
+	th := NewTestHelper(t)
+	defer th.Cleanup()
+
+	th.CreateSampleConfig()
+	manager := th.CreateManagerWithTempDir()
+	if err := manager.CreateContext("opus"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := manager.SetCostTier("opus", "Expensive"); err != nil {
+		t.Fatalf("SetCostTier failed: %v", err)
+	}
+	if tier, _ := manager.GetCostTier("opus"); tier != "expensive" {
+		t.Errorf("Expected 'expensive', got %q", tier)
+	}
+	if err := manager.SetCostTier("opus", ""); err != nil {
+		t.Fatal(err)
+	}
+	if tier, _ := manager.GetCostTier("opus"); tier != "" {
+		t.Errorf("Expected the tier to be removed, got %q", tier)
+	}
+	if err := manager.SetCostTier("missing", "cheap"); err == nil {
+		t.Error("Tagging a missing context should fail")
+	}
+}
+
+func TestManager_CostGuard(t *testing.T) {
+	th := NewTestHelper(t)
+	defer th.Cleanup()
+
+	th.CreateSampleConfig()
+	setup := th.CreateManagerWithTempDir()
+	for _, name := range []string{"opus", "haiku"} {
+		if err := setup.CreateContext(name); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := setup.SetCostTier("opus", "expensive"); err != nil {
+		t.Fatal(err)
+	}
+	if err := setup.SetCostTier("haiku", "cheap"); err != nil {
+		t.Fatal(err)
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		config  string
+		context string
+		confirm bool
+		warned  bool
+		blocked bool
+	}{
+		{"inside working hours", `{"guarded_tiers": ["expensive"], "working_hours": "` + hoursWindow(-time.Hour, time.Hour) + `"}`, "opus", false, false, false},
+		{"outside working hours", `{"guarded_tiers": ["expensive"], "working_hours": "` + hoursWindow(time.Hour, 2*time.Hour) + `"}`, "opus", false, true, false},
+		{"guarded directory", `{"guarded_tiers": ["expensive"], "dirs": ["` + strings.ReplaceAll(cwd, `\`, `\\`) + `"]}`, "opus", false, true, false},
+		{"other directory", `{"guarded_tiers": ["expensive"], "dirs": ["` + strings.ReplaceAll(th.TempDir, `\`, `\\`) + `"]}`, "opus", false, false, false},
+		{"unguarded tier", `{"guarded_tiers": ["expensive"]}`, "haiku", false, false, false},
+		{"confirm declined", `{"guarded_tiers": ["expensive"], "action": "confirm"}`, "opus", false, false, true},
+		{"confirm accepted", `{"guarded_tiers": ["expensive"], "action": "confirm"}`, "opus", true, false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			writeOcctxConfig(t, th.ConfigDir, `{"cost": `+tt.config+`}`)
+			manager := th.CreateManagerWithTempDir()
+			manager.SetForce(true)
+
+			var warnings []string
+			manager.SetWarningHandler(func(message string) { warnings = append(warnings, message) })
+			manager.SetConfirmHandler(func(question string) bool { return tt.confirm })
+
+			err := manager.SwitchToContext(tt.context)
+			var guardErr *context.CostGuardError
+			if blocked := errors.As(err, &guardErr); blocked != tt.blocked {
+				t.Errorf("Expected blocked=%t, got %v", tt.blocked, err)
+			}
+			if !tt.blocked && err != nil {
+				t.Errorf("Switch failed: %v", err)
+			}
+			if warned := len(warnings) > 0; warned != tt.warned {
+				t.Errorf("Expected warned=%t, got %v", tt.warned, warnings)
+			}
+		})
+	}
+}
+
+func TestIntegration_CostGuard(t *testing.T) {
+	ith := NewIntegrationTestHelper(t)
+	defer ith.Cleanup()
+
+	ith.CreateSampleConfig()
+	if _, _, err := ith.RunCommand("-n", "opus"); err != nil {
+		t.Fatal(err)
+	}
+	if _, stderr, err := ith.RunCommand("tier", "opus", "expensive"); err != nil {
+		t.Fatalf("tier failed: %v\n%s", err, stderr)
+	}
+	stdout, _, _ := ith.RunCommand("tier", "opus")
+	if strings.TrimSpace(stdout) != "expensive" {
+		t.Errorf("Expected 'expensive', got %q", stdout)
+	}
+
+	writeOcctxConfig(t, ith.ConfigDir, `{"cost": {"guarded_tiers": ["expensive"], "action": "confirm"}}`)
+
+	// Nobody can answer the prompt without a terminal
+	if _, stderr, err := ith.RunCommand("opus"); err == nil || !strings.Contains(stderr, "--yes") {
+		t.Errorf("Expected the switch to need confirmation, got %v\n%s", err, stderr)
+	}
+	if _, stderr, err := ith.RunCommand("opus", "--yes"); err != nil {
+		t.Errorf("switch with --yes failed: %v\n%s", err, stderr)
+	}
+	if _, stderr, err := ith.RunCommand("switch", "opus", "-y"); err != nil {
+		t.Errorf("switch -y failed: %v\n%s", err, stderr)
+	}
+
+	writeOcctxConfig(t, ith.ConfigDir, `{"cost": {"guarded_tiers": ["expensive"]}}`)
+	_, stderr, err := ith.RunCommand("opus")
+	if err != nil || !strings.Contains(stderr, "expensive-tier") {
+		t.Errorf("Expected a warning, got %v\n%s", err, stderr)
+	}
+
+	if _, _, err := ith.RunCommand("tier", "opus", "--clear"); err != nil {
+		t.Fatal(err)
+	}
+	if _, stderr, _ := ith.RunCommand("opus"); strings.Contains(stderr, "tier") {
+		t.Errorf("Untagged context should not warn, got:\n%s", stderr)
+	}
+}
@@ -0,0 +1,49 @@
+package test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestIntegration_CreateMissing(t *testing.T) {
+	ith := NewIntegrationTestHelper(t)
+	defer ith.Cleanup()
+
+	// Without --create-missing and without a terminal to ask on, nothing is created
+	ith.CreateSampleConfig()
+	if _, _, err := ith.RunCommand("snapshot"); err == nil {
+		t.Fatal("Expected switching to a missing context to fail")
+	}
+	if _, err := os.Stat(filepath.Join(ith.SettingsDir, "snapshot.json")); !os.IsNotExist(err) {
+		t.Fatal("Context should not be created without --create-missing")
+	}
+
+	out, stderr, err := ith.RunCommand("snapshot", "--create-missing")
+	if err != nil {
+		t.Fatalf("--create-missing failed: %v\n%s", err, stderr)
+	}
+	if !strings.Contains(out, "Created context 'snapshot'") || !strings.Contains(out, "Switched to context: snapshot") {
+		t.Errorf("Expected the context to be created and switched to, got:\n%s", out)
+	}
+	if out, _, _ := ith.RunCommand("-c"); strings.TrimSpace(out) != "snapshot" {
+		t.Errorf("Expected 'snapshot' to be current, got %q", out)
+	}
+
+	// An existing context is switched to as usual
+	out, stderr, err = ith.RunCommand("snapshot", "--create-missing")
+	if err != nil || strings.Contains(out, "Created") {
+		t.Errorf("Expected a plain switch to an existing context: %v\n%s%s", err, out, stderr)
+	}
+}
+
+func TestIntegration_CreateMissingWithoutActiveConfig(t *testing.T) {
+	ith := NewIntegrationTestHelper(t)
+	defer ith.Cleanup()
+
+	_, stderr, err := ith.RunCommand("snapshot", "--create-missing")
+	if err == nil || !strings.Contains(stderr, "not found") {
+		t.Errorf("Expected the not-found error without an active config, got %v\n%s", err, stderr)
+	}
+}
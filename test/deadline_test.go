@@ -0,0 +1,151 @@
+package test
+
+import (
+	gocontext "context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/hungthai1401/occtx/internal/context"
+)
+
+func TestManager_CanceledContext(t *testing.T) {
+	th := NewTestHelper(t)
+	defer th.Cleanup()
+
+	server, requests := serveModelCatalog(t)
+	th.CreateSampleConfig()
+	writeOcctxConfig(t, th.ConfigDir, `{"models": {"url": "`+server.URL+`"}}`)
+	manager := th.CreateManagerWithTempDir()
+	manager.SetForce(true)
+	if err := manager.ImportContext("work", []byte(`{"theme": "work"}`)); err != nil {
+		t.Fatal(err)
+	}
+	activePath := filepath.Join(th.ConfigDir, "opencode.json")
+	before, err := os.ReadFile(activePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := gocontext.WithCancel(gocontext.Background())
+	cancel()
+	manager.SetContext(ctx)
+
+	if err := manager.SwitchToContext("work"); !errors.Is(err, gocontext.Canceled) {
+		t.Errorf("Expected the switch to be canceled, got %v", err)
+	}
+	if after, _ := os.ReadFile(activePath); string(after) != string(before) {
+		t.Error("A canceled switch should leave the active config alone")
+	}
+
+	if _, err := manager.UpdateModelCatalog(true); !errors.Is(err, gocontext.Canceled) {
+		t.Errorf("Expected the download to be canceled, got %v", err)
+	}
+	if n := atomic.LoadInt32(requests); n != 0 {
+		t.Errorf("Expected no requests after cancellation, got %d", n)
+	}
+
+	if _, err := manager.VerifyContexts(); !errors.Is(err, gocontext.Canceled) {
+		t.Errorf("Expected verify to be canceled, got %v", err)
+	}
+	if _, err := context.DiscoverProjects(ctx, []string{th.TempDir}, 3); !errors.Is(err, gocontext.Canceled) {
+		t.Errorf("Expected the project scan to be canceled, got %v", err)
+	}
+
+	// Quick reads don't need the context
+	if _, err := manager.GetContext("work"); err != nil {
+		t.Errorf("GetContext failed: %v", err)
+	}
+}
+
+func TestIntegration_TimeoutKillsEditor(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("fake editor requires a POSIX shell")
+	}
+
+	ith := NewIntegrationTestHelper(t)
+	defer ith.Cleanup()
+
+	ith.CreateSampleConfig()
+	if _, _, err := ith.RunCommand("-n", "work"); err != nil {
+		t.Fatal(err)
+	}
+
+	editor := filepath.Join(ith.TempDir, "stuck-editor")
+	if err := os.WriteFile(editor, []byte("#!/bin/sh\nexec sleep 10\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	start := time.Now()
+	cmd := exec.Command(ith.BinaryPath, "-e", "work")
+	cmd.Env = ith.Env("EDITOR="+editor, "OCCTX_TIMEOUT=200ms")
+	output, err := cmd.CombinedOutput()
+	if err == nil || !strings.Contains(string(output), "--timeout 200ms") {
+		t.Errorf("Expected the editor to be stopped by the timeout, got %v\n%s", err, output)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("Expected occtx to give up after the timeout, took %s", elapsed)
+	}
+
+	if _, stderr, err := ith.RunCommand("--timeout", "-1s", "-c"); err == nil || !strings.Contains(stderr, "negative") {
+		t.Errorf("Expected a negative timeout to be rejected, got %v\n%s", err, stderr)
+	}
+}
+
+func TestIntegration_TimeoutStopsWatch(t *testing.T) {
+	ith := NewIntegrationTestHelper(t)
+	defer ith.Cleanup()
+
+	ith.CreateSampleConfig()
+	for _, args := range [][]string{{"-n", "work"}, {"-n", "demo"}, {"work"}, {"switch", "demo", "--for", "2h"}} {
+		if _, stderr, err := ith.RunCommand(args...); err != nil {
+			t.Fatalf("%v failed: %v\n%s", args, err, stderr)
+		}
+	}
+
+	start := time.Now()
+	_, stderr, err := ith.RunCommand("watch", "--timeout", "200ms")
+	if err == nil || !strings.Contains(stderr, "--timeout 200ms") {
+		t.Errorf("Expected watch to stop at the timeout, got %v\n%s", err, stderr)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("Expected watch to give up after the timeout, took %s", elapsed)
+	}
+}
+
+func TestIntegration_InterruptStopsWatch(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("needs a Unix interrupt signal")
+	}
+	ith := NewIntegrationTestHelper(t)
+	defer ith.Cleanup()
+
+	ith.CreateSampleConfig()
+	for _, args := range [][]string{{"-n", "work"}, {"-n", "demo"}, {"work"}, {"switch", "demo", "--for", "2h"}} {
+		if _, stderr, err := ith.RunCommand(args...); err != nil {
+			t.Fatalf("%v failed: %v\n%s", args, err, stderr)
+		}
+	}
+
+	cmd := exec.Command(ith.BinaryPath, "watch")
+	cmd.Env = ith.Env()
+	var stderr strings.Builder
+	cmd.Stderr = &stderr
+	if err := cmd.Start(); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(500 * time.Millisecond)
+	if err := cmd.Process.Signal(os.Interrupt); err != nil {
+		t.Fatal(err)
+	}
+	// Ctrl-C is the normal way to stop a foreground watch
+	if err := cmd.Wait(); err != nil || strings.Contains(stderr.String(), "Error") {
+		t.Errorf("Expected watch to stop cleanly on interrupt, got %v\n%s", err, stderr.String())
+	}
+}
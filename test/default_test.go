@@ -0,0 +1,120 @@
+package test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/hungthai1401/occtx/internal/context"
+)
+
+func TestManager_DefaultContext(t *testing.T) {
+	th := NewTestHelper(t)
+	defer th.Cleanup()
+
+	th.CreateSampleConfig()
+	manager := th.CreateManagerWithTempDir()
+	for _, name := range []string{"work", "demo"} {
+		if err := manager.CreateContext(name); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if _, err := manager.ResetToDefault(); !errors.Is(err, context.ErrNoDefault) {
+		t.Errorf("Expected ErrNoDefault, got %v", err)
+	}
+	if err := manager.SetDefaultContext("missing"); err == nil {
+		t.Error("Setting a missing context as default should fail")
+	}
+
+	if err := manager.SetDefaultContext("work"); err != nil {
+		t.Fatalf("SetDefaultContext failed: %v", err)
+	}
+	if err := manager.SwitchToContext("demo"); err != nil {
+		t.Fatal(err)
+	}
+
+	name, err := manager.ResetToDefault()
+	if err != nil || name != "work" {
+		t.Fatalf("Expected a reset to 'work', got %q (%v)", name, err)
+	}
+	if current, _ := manager.GetCurrentContext(); current != "work" {
+		t.Errorf("Expected 'work' to be current, got %q", current)
+	}
+
+	// The pin follows renames and blocks deletion and archiving
+	if err := manager.RenameContext("work", "main"); err != nil {
+		t.Fatal(err)
+	}
+	if name, _ := manager.GetDefaultContext(); name != "main" {
+		t.Errorf("Expected the default to follow the rename, got %q", name)
+	}
+	if err := manager.SwitchToContext("demo"); err != nil {
+		t.Fatal(err)
+	}
+	if err := manager.DeleteContext("main"); err == nil || !strings.Contains(err.Error(), "default unset") {
+		t.Errorf("Deleting the default context should fail, got %v", err)
+	}
+	if err := manager.ArchiveContext("main"); err == nil {
+		t.Error("Archiving the default context should fail")
+	}
+
+	if err := manager.UnsetDefaultContext(); err != nil {
+		t.Fatalf("UnsetDefaultContext failed: %v", err)
+	}
+	if name, _ := manager.GetDefaultContext(); name != "" {
+		t.Errorf("Expected no default, got %q", name)
+	}
+	if err := manager.DeleteContext("main"); err != nil {
+		t.Errorf("Deleting a context that is no longer the default failed: %v", err)
+	}
+}
+
+func TestIntegration_DefaultAndReset(t *testing.T) {
+	ith := NewIntegrationTestHelper(t)
+	defer ith.Cleanup()
+
+	ith.CreateSampleConfig()
+	for _, name := range []string{"work", "demo"} {
+		if _, _, err := ith.RunCommand("-n", name); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	stdout, _, err := ith.RunCommand("default")
+	if err != nil || !strings.Contains(stdout, "No default context set") {
+		t.Errorf("Expected no default, got %q (%v)", stdout, err)
+	}
+	if _, _, err := ith.RunCommand("reset"); err == nil {
+		t.Error("reset without a default should fail")
+	}
+	if _, stderr, err := ith.RunCommand("reset", "--if-set"); err != nil {
+		t.Errorf("reset --if-set without a default should be a no-op, got %v\n%s", err, stderr)
+	}
+
+	if _, stderr, err := ith.RunCommand("default", "set", "work"); err != nil {
+		t.Fatalf("default set failed: %v\n%s", err, stderr)
+	}
+	stdout, _, _ = ith.RunCommand("default")
+	if strings.TrimSpace(stdout) != "work" {
+		t.Errorf("Expected 'work', got %q", stdout)
+	}
+
+	if _, _, err := ith.RunCommand("demo"); err != nil {
+		t.Fatal(err)
+	}
+	if _, stderr, err := ith.RunCommand("reset", "--if-set"); err != nil {
+		t.Fatalf("reset failed: %v\n%s", err, stderr)
+	}
+	stdout, _, _ = ith.RunCommand("-c")
+	if strings.TrimSpace(stdout) != "work" {
+		t.Errorf("Expected 'work' after reset, got %q", stdout)
+	}
+
+	if _, _, err := ith.RunCommand("default", "unset"); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := ith.RunCommand("reset"); err == nil {
+		t.Error("reset after unset should fail")
+	}
+}
@@ -0,0 +1,95 @@
+package test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestManager_DeleteContextSwitchingTo(t *testing.T) {
+	tests := []struct {
+		name        string
+		fallback    string
+		wantCurrent string
+		wantErr     bool
+	}{
+		{"named context", "home", "home", false},
+		{"previous", "previous", "personal", false},
+		{"none", "none", "", false},
+		{"missing context", "nope", "work", true},
+		{"itself", "work", "work", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			th := NewTestHelper(t)
+			defer th.Cleanup()
+
+			th.CreateSampleConfig()
+			manager := th.CreateManagerWithTempDir()
+
+			for _, name := range []string{"work", "home", "personal"} {
+				if err := manager.CreateContext(name); err != nil {
+					t.Fatal(err)
+				}
+			}
+			manager.SwitchToContext("personal")
+			manager.SwitchToContext("work")
+
+			err := manager.DeleteContextSwitchingTo("work", tt.fallback)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("DeleteContextSwitchingTo(%q) error = %v, wantErr %v", tt.fallback, err, tt.wantErr)
+			}
+
+			current, _ := manager.GetCurrentContext()
+			if current != tt.wantCurrent {
+				t.Errorf("current = %q, want %q", current, tt.wantCurrent)
+			}
+
+			_, statErr := os.Stat(filepath.Join(th.SettingsDir, "work.json"))
+			if tt.wantErr == os.IsNotExist(statErr) {
+				t.Errorf("work.json exists = %v, want %v", statErr == nil, tt.wantErr)
+			}
+
+			// Switching back must not target the deleted context
+			if !tt.wantErr && tt.fallback != "none" {
+				if err := manager.SwitchToPrevious(); err == nil {
+					if current, _ := manager.GetCurrentContext(); current == "work" {
+						t.Error("Previous context still points at the deleted context")
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestIntegration_DeleteCurrentWithSwitchTo(t *testing.T) {
+	ith := NewIntegrationTestHelper(t)
+	defer ith.Cleanup()
+
+	ith.CreateSampleConfig()
+
+	for _, name := range []string{"work", "home"} {
+		if _, _, err := ith.RunCommand("-n", name); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, _, err := ith.RunCommand("work"); err != nil {
+		t.Fatal(err)
+	}
+
+	_, stderr, err := ith.RunCommand("-d", "work")
+	if err == nil || !strings.Contains(stderr, "--switch-to") {
+		t.Errorf("Expected deleting the current context to suggest --switch-to, got err=%v stderr=%s", err, stderr)
+	}
+
+	if _, stderr, err := ith.RunCommand("-d", "work", "--switch-to", "home"); err != nil {
+		t.Fatalf("-d --switch-to failed: %v\n%s", err, stderr)
+	}
+
+	stdout, _, err := ith.RunCommand("-c")
+	if err != nil || strings.TrimSpace(stdout) != "home" {
+		t.Errorf("Expected current context 'home', got %q (%v)", stdout, err)
+	}
+}
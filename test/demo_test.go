@@ -0,0 +1,67 @@
+package test
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestIntegration_DemoSandbox(t *testing.T) {
+	ith := NewIntegrationTestHelper(t)
+	defer ith.Cleanup()
+
+	ith.CreateSampleConfig()
+	active := filepath.Join(ith.ConfigDir, "opencode.json")
+	before, err := os.ReadFile(active)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tmp := filepath.Join(ith.TempDir, "tmp")
+	if err := os.MkdirAll(tmp, 0755); err != nil {
+		t.Fatal(err)
+	}
+	run := func(args ...string) string {
+		t.Helper()
+		cmd := exec.Command(ith.BinaryPath, args...)
+		cmd.Env = ith.Env("TMPDIR="+tmp, "OCCTX_READONLY=1")
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("occtx %s failed: %v\n%s", strings.Join(args, " "), err, out)
+		}
+		return string(out)
+	}
+
+	// Read-only mode from the environment applies to the user's config, not the sandbox
+	out := run("demo")
+	for _, want := range []string{"Switched to context: work", "~ model:", "~theme", "Switched to context: personal"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Expected %q in the walkthrough, got:\n%s", want, out)
+		}
+	}
+
+	sandboxes, _ := filepath.Glob(filepath.Join(tmp, "occtx-demo-*"))
+	if len(sandboxes) != 1 {
+		t.Fatalf("Expected one sandbox to be kept, got %v", sandboxes)
+	}
+	if _, err := os.Stat(filepath.Join(sandboxes[0], "settings", "work.json")); err != nil {
+		t.Errorf("Expected the sample contexts in the sandbox: %v", err)
+	}
+
+	// The real configuration is untouched
+	if after, _ := os.ReadFile(active); string(after) != string(before) {
+		t.Error("The demo must not change the active config")
+	}
+	if entries, _ := filepath.Glob(filepath.Join(ith.SettingsDir, "*.json")); len(entries) != 0 {
+		t.Errorf("The demo must not create contexts, found %v", entries)
+	}
+
+	if out := run("demo", "--clean"); !strings.Contains(out, "Removed 1 demo sandbox") {
+		t.Errorf("Unexpected --clean output:\n%s", out)
+	}
+	if _, err := os.Stat(sandboxes[0]); !os.IsNotExist(err) {
+		t.Errorf("Expected --clean to remove %s", sandboxes[0])
+	}
+}
@@ -0,0 +1,120 @@
+package test
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/fatih/color"
+	"github.com/hungthai1401/occtx/internal/ui"
+)
+
+// captureDiagnostics sends diagnostics to a buffer for the rest of the test
+func captureDiagnostics(t *testing.T, asJSON bool) *bytes.Buffer {
+	t.Helper()
+	var buf bytes.Buffer
+	noColor := color.NoColor
+	color.NoColor = true
+	ui.SetDiagnosticsOutput(&buf)
+	ui.SetDiagnosticsJSON(asJSON)
+	t.Cleanup(func() {
+		color.NoColor = noColor
+		ui.SetDiagnosticsOutput(os.Stderr)
+		ui.SetDiagnosticsJSON(false)
+		ui.SetProgressEnabled(true)
+	})
+	return &buf
+}
+
+func TestDiagnostics_TextPrefixes(t *testing.T) {
+	buf := captureDiagnostics(t, false)
+
+	ui.Warnf("config %s is stale\n", "a")
+	ui.Hintf("Run 'occtx %s'", "b")
+	ui.Progressf("Fetching")
+	ui.Diagnose(ui.LevelDebug, "read 10 bytes")
+
+	want := "Warning: config a is stale\nHint: Run 'occtx b'\nFetching\ndebug: read 10 bytes\n"
+	if buf.String() != want {
+		t.Errorf("Expected:\n%s\ngot:\n%s", want, buf.String())
+	}
+}
+
+func TestDiagnostics_JSONEnvelopes(t *testing.T) {
+	buf := captureDiagnostics(t, true)
+
+	ui.Warnf("multi\nline")
+	ui.Hintf("try this")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Expected one JSON object per diagnostic, got:\n%s", buf.String())
+	}
+	var first struct {
+		Level   string `json:"level"`
+		Message string `json:"message"`
+	}
+	if err := json.Unmarshal([]byte(lines[0]), &first); err != nil {
+		t.Fatalf("Invalid JSON diagnostic %q: %v", lines[0], err)
+	}
+	if first.Level != "warning" || first.Message != "multi\nline" {
+		t.Errorf("Unexpected diagnostic %+v", first)
+	}
+	if !strings.Contains(lines[1], `"level":"hint"`) {
+		t.Errorf("Expected a hint, got %s", lines[1])
+	}
+}
+
+func TestDiagnostics_QuietDropsHintsAndProgress(t *testing.T) {
+	buf := captureDiagnostics(t, false)
+	ui.SetProgressEnabled(false)
+
+	ui.Hintf("hint")
+	ui.Progressf("progress")
+	ui.Warnf("still shown")
+
+	if buf.String() != "Warning: still shown\n" {
+		t.Errorf("Expected only the warning in quiet mode, got:\n%s", buf.String())
+	}
+}
+
+func TestIntegration_DiagnosticsStayOffStdout(t *testing.T) {
+	ith := NewIntegrationTestHelper(t)
+	defer ith.Cleanup()
+
+	ith.CreateSampleConfig()
+	if _, _, err := ith.RunCommand("-n", "work"); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := ith.RunCommand("work"); err != nil {
+		t.Fatal(err)
+	}
+	// Modify the active config so the next command warns about drift
+	active := filepath.Join(ith.ConfigDir, "opencode.json")
+	if err := os.WriteFile(active, []byte(`{"theme": "edited"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	stdout, stderr, err := ith.RunCommand("-o", "json")
+	if err != nil {
+		t.Fatalf("listing failed: %v\n%s", err, stderr)
+	}
+	var entries []map[string]interface{}
+	if err := json.Unmarshal([]byte(stdout), &entries); err != nil {
+		t.Errorf("Expected only the listing on stdout, got %v:\n%s", err, stdout)
+	}
+	if !strings.Contains(stderr, `{"level":"warning","message":"active config was modified`) {
+		t.Errorf("Expected a JSON drift warning on stderr, got:\n%s", stderr)
+	}
+
+	_, stderr, err = ith.RunCommand("stats", "--reset", "-o", "json")
+	if err == nil {
+		t.Fatal("Expected --reset without --self to fail")
+	}
+	if !strings.Contains(stderr, `{"level":"error","message":"--reset requires --self"}`) {
+		t.Errorf("Expected a JSON error on stderr, got:\n%s", stderr)
+	}
+}
@@ -0,0 +1,140 @@
+package test
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hungthai1401/occtx/internal/context"
+)
+
+func TestDirMap_Match(t *testing.T) {
+	dirMap := context.DirMap{
+		".":                  "work",
+		"packages/api":       "api",
+		"packages/api/v2":    "api-v2",
+		"packages/apiclient": "client",
+		"packages/web":       "",
+	}
+
+	tests := []struct {
+		rel    string
+		prefix string
+		name   string
+	}{
+		{".", ".", "work"},
+		{"docs", ".", "work"},
+		{"packages/api", "packages/api", "api"},
+		{"packages/api/src/handlers", "packages/api", "api"},
+		{"packages/api/v2/src", "packages/api/v2", "api-v2"},
+		{"packages/apiclient", "packages/apiclient", "client"},
+		{"packages/web/src", ".", "work"}, // Blank entries are skipped
+	}
+	for _, tt := range tests {
+		prefix, name, ok := dirMap.Match(tt.rel)
+		if !ok || prefix != tt.prefix || name != tt.name {
+			t.Errorf("Match(%q) = %q, %q, %v; expected %q, %q", tt.rel, prefix, name, ok, tt.prefix, tt.name)
+		}
+	}
+
+	if _, _, ok := (context.DirMap{"packages/api": "api"}).Match("packages/web"); ok {
+		t.Error("Expected no match outside every prefix")
+	}
+}
+
+func TestFindDirContext_DirMap(t *testing.T) {
+	root := t.TempDir()
+	api := filepath.Join(root, "packages", "api", "src")
+	if err := os.MkdirAll(api, 0755); err != nil {
+		t.Fatal(err)
+	}
+	mapPath := filepath.Join(root, ".occtx-map.json")
+	if err := os.WriteFile(mapPath, []byte(`{".": "work", "packages/api/": "api"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if name, path, err := context.FindDirContext(api); err != nil || name != "api" || path != mapPath {
+		t.Errorf("Expected 'api' from the map, got %q from %s (%v)", name, path, err)
+	}
+	if name, _, _ := context.FindDirContext(root); name != "work" {
+		t.Errorf("Expected the root entry at the root, got %q", name)
+	}
+
+	// A nearer context file wins over the map
+	if err := os.WriteFile(filepath.Join(root, "packages", ".occtx-context"), []byte("demo\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if name, _, _ := context.FindDirContext(api); name != "demo" {
+		t.Errorf("Expected the nearer context file to win, got %q", name)
+	}
+}
+
+func TestIntegration_MapAndAuto(t *testing.T) {
+	ith := NewIntegrationTestHelper(t)
+	defer ith.Cleanup()
+
+	settingsDir := filepath.Join(ith.ConfigDir, "settings")
+	if err := os.MkdirAll(settingsDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"work", "api"} {
+		if err := os.WriteFile(filepath.Join(settingsDir, name+".json"), []byte(`{"theme": "`+name+`"}`), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	repo := filepath.Join(ith.TempDir, "repo")
+	api := filepath.Join(repo, "packages", "api")
+	for _, dir := range []string{filepath.Join(repo, ".git"), api} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	run := func(dir string, args ...string) (string, error) {
+		cmd := exec.Command(ith.BinaryPath, args...)
+		cmd.Dir = dir
+		cmd.Env = ith.Env()
+		out, err := cmd.CombinedOutput()
+		return string(out), err
+	}
+
+	// Created at the repository root, with paths relative to the working directory
+	if out, err := run(api, "map", "add", "../..", "work"); err != nil {
+		t.Fatalf("map add failed: %v\n%s", err, out)
+	}
+	if out, err := run(api, "map", "add", ".", "api"); err != nil {
+		t.Fatalf("map add failed: %v\n%s", err, out)
+	}
+	if out, err := run(api, "map", "add", "../../..", "work"); err == nil {
+		t.Errorf("Expected a path outside the repository to be rejected, got %s", out)
+	}
+	if _, err := os.Stat(filepath.Join(repo, ".occtx-map.json")); err != nil {
+		t.Fatalf("Expected the map at the repository root: %v", err)
+	}
+
+	out, err := run(api, "map", "list")
+	if err != nil || !strings.Contains(out, "* packages/api -> api") || !strings.Contains(out, "  . -> work") {
+		t.Errorf("Expected the api mapping marked, got %v\n%s", err, out)
+	}
+
+	if out, err := run(api, "auto"); err != nil || !strings.Contains(out, "Switched to context: api") {
+		t.Errorf("Expected auto to switch to api, got %v\n%s", err, out)
+	}
+	if out, err := run(repo, "auto"); err != nil || !strings.Contains(out, "Switched to context: work") {
+		t.Errorf("Expected auto to switch to work at the root, got %v\n%s", err, out)
+	}
+
+	if out, err := run(api, "map", "remove", "."); err != nil {
+		t.Fatalf("map remove failed: %v\n%s", err, out)
+	}
+	if out, _ := run(api, "auto", "--print"); !strings.HasPrefix(out, "work ") {
+		t.Errorf("Expected the root entry after removing the package's, got %s", out)
+	}
+
+	if out, err := run(ith.TempDir, "auto"); err == nil || !strings.Contains(out, "no context is mapped") {
+		t.Errorf("Expected an error outside any mapping, got %v\n%s", err, out)
+	}
+}
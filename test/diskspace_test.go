@@ -0,0 +1,66 @@
+package test
+
+import (
+	"errors"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hungthai1401/occtx/internal/context"
+)
+
+func TestCheckFreeSpace(t *testing.T) {
+	dir := t.TempDir()
+
+	if err := context.CheckFreeSpace(dir, 1); err != nil {
+		t.Errorf("A byte should fit: %v", err)
+	}
+
+	// Paths that don't exist yet are checked on their nearest existing parent
+	if err := context.CheckFreeSpace(filepath.Join(dir, "missing", "export"), 1); err != nil {
+		t.Errorf("Expected the parent to be checked: %v", err)
+	}
+
+	err := context.CheckFreeSpace(dir, math.MaxUint64/2)
+	if err == nil {
+		t.Skip("filesystem does not report free space")
+	}
+	if !errors.Is(err, context.ErrDiskFull) {
+		t.Fatalf("Expected ErrDiskFull, got %v", err)
+	}
+	var diskFull *context.DiskFullError
+	if !errors.As(err, &diskFull) || diskFull.Path != dir || diskFull.Available == 0 {
+		t.Errorf("Expected path and available bytes in the error, got %+v", diskFull)
+	}
+	if !strings.Contains(err.Error(), "bytes needed") || !strings.Contains(err.Error(), "--config-dir") {
+		t.Errorf("Expected an actionable message, got %s", err)
+	}
+}
+
+func TestSaveState_NoTempFilesLeft(t *testing.T) {
+	dir := t.TempDir()
+	stateFile := filepath.Join(dir, "state.json")
+
+	stale, _ := context.LoadState(stateFile)
+	if err := (&context.State{Current: "work"}).SaveState(stateFile); err != nil {
+		t.Fatal(err)
+	}
+
+	// A write that fails after the temp file was written must clean it up
+	stale.SetCurrent("other")
+	if err := stale.SaveState(stateFile); !errors.Is(err, context.ErrStateConflict) {
+		t.Fatalf("Expected ErrStateConflict, got %v", err)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, entry := range entries {
+		if strings.HasSuffix(entry.Name(), ".tmp") {
+			t.Errorf("Temp file %s left behind", entry.Name())
+		}
+	}
+}
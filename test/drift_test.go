@@ -0,0 +1,87 @@
+package test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestManager_ActiveDrifted(t *testing.T) {
+	th := NewTestHelper(t)
+	defer th.Cleanup()
+
+	th.CreateSampleConfig()
+	manager := th.CreateManagerWithTempDir()
+
+	if drifted, err := manager.ActiveDrifted(); err != nil || drifted {
+		t.Fatalf("Expected no drift without a switch, got %v, %v", drifted, err)
+	}
+
+	manager.CreateContext("work")
+	if err := manager.SwitchToContext("work"); err != nil {
+		t.Fatal(err)
+	}
+
+	if drifted, err := manager.ActiveDrifted(); err != nil || drifted {
+		t.Fatalf("Expected no drift right after switch, got %v, %v", drifted, err)
+	}
+
+	activeConfigPath := filepath.Join(th.ConfigDir, "opencode.json")
+
+	// Touching the file without changing it is not drift
+	later := time.Now().Add(time.Minute)
+	os.Chtimes(activeConfigPath, later, later)
+	if drifted, _ := manager.ActiveDrifted(); drifted {
+		t.Error("Touching the active config should not count as drift")
+	}
+
+	os.WriteFile(activeConfigPath, []byte(`{"theme": "edited"}`), 0644)
+	if drifted, _ := manager.ActiveDrifted(); !drifted {
+		t.Error("Expected drift after editing the active config")
+	}
+
+	if err := manager.UnsetCurrentContext(); err != nil {
+		t.Fatal(err)
+	}
+	if drifted, _ := manager.ActiveDrifted(); drifted {
+		t.Error("Expected no drift after unset")
+	}
+}
+
+func TestIntegration_DriftWarning(t *testing.T) {
+	ith := NewIntegrationTestHelper(t)
+	defer ith.Cleanup()
+
+	ith.CreateSampleConfig()
+
+	ith.RunCommand("-n", "work")
+	ith.RunCommand("work")
+
+	_, stderr, err := ith.RunCommand("-c")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(stderr, "modified since switching") {
+		t.Errorf("Unexpected drift warning: %s", stderr)
+	}
+
+	os.WriteFile(filepath.Join(ith.ConfigDir, "opencode.json"), []byte(`{"theme": "edited"}`), 0644)
+
+	_, stderr, _ = ith.RunCommand("-c")
+	if !strings.Contains(stderr, "active config was modified since switching to 'work'") {
+		t.Errorf("Expected drift warning, got: %s", stderr)
+	}
+
+	_, stderr, _ = ith.RunCommand("-c", "--quiet")
+	if strings.Contains(stderr, "modified since switching") {
+		t.Errorf("--quiet should suppress the drift warning, got: %s", stderr)
+	}
+
+	writeOcctxConfig(t, ith.ConfigDir, `{"warnings": {"drift": false}}`)
+	_, stderr, _ = ith.RunCommand("-c")
+	if strings.Contains(stderr, "modified since switching") {
+		t.Errorf("warnings.drift=false should suppress the drift warning, got: %s", stderr)
+	}
+}
@@ -0,0 +1,175 @@
+package test
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/hungthai1401/occtx/internal/config"
+	"github.com/hungthai1401/occtx/internal/context"
+)
+
+func TestManager_ContextEnv(t *testing.T) {
+	th := NewTestHelper(t)
+	defer th.Cleanup()
+
+	th.CreateSampleConfig()
+	manager := th.CreateManagerWithTempDir()
+	manager.SetForce(true)
+	for _, name := range []string{"work", "personal"} {
+		if err := manager.CreateContext(name); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	err := manager.SetContextEnv("work", map[string]string{"HTTPS_PROXY": "http://proxy:3128", "ANTHROPIC_BASE_URL": "https://gw"})
+	if err != nil {
+		t.Fatalf("SetContextEnv failed: %v", err)
+	}
+	for _, name := range []string{"1BAD", "WITH-DASH", "OCCTX_ENV_KEYS", "OPENCODE_CONFIG"} {
+		if err := manager.SetContextEnv("work", map[string]string{name: "x"}); err == nil {
+			t.Errorf("Expected %s to be rejected", name)
+		}
+	}
+	if err := manager.SetContextEnv("missing", map[string]string{"A": "b"}); err == nil {
+		t.Error("Expected an error for a missing context")
+	}
+
+	env, err := manager.GetContextEnv("work")
+	if err != nil || len(env) != 2 || env["HTTPS_PROXY"] != "http://proxy:3128" {
+		t.Errorf("Unexpected env %v (%v)", env, err)
+	}
+
+	// The hook exports the current context's variables...
+	t.Setenv(context.SessionContextEnvVar, "")
+	if err := manager.SwitchToContext("work"); err != nil {
+		t.Fatal(err)
+	}
+	change, err := manager.HookEnv("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if keys := strings.Join(change.Keys(), ","); keys != "ANTHROPIC_BASE_URL,HTTPS_PROXY" || len(change.Unset) != 0 {
+		t.Errorf("Unexpected change %v / %v", keys, change.Unset)
+	}
+
+	// ...and unsets them when the context changes
+	if err := manager.SwitchToContext("personal"); err != nil {
+		t.Fatal(err)
+	}
+	change, err = manager.HookEnv("ANTHROPIC_BASE_URL:HTTPS_PROXY")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(change.Set) != 0 || strings.Join(change.Unset, ",") != "ANTHROPIC_BASE_URL,HTTPS_PROXY" {
+		t.Errorf("Unexpected change %v / %v", change.Set, change.Unset)
+	}
+
+	if err := manager.UnsetContextEnv("work", []string{"HTTPS_PROXY"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := manager.UnsetContextEnv("work", []string{"HTTPS_PROXY"}); err == nil {
+		t.Error("Expected an error removing an undeclared variable")
+	}
+
+	// Names that couldn't be declared never reach the shell
+	metadataPath := filepath.Join(th.SettingsDir, config.MetadataFileName)
+	data, err := os.ReadFile(metadataPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	injected := strings.Replace(string(data), `"ANTHROPIC_BASE_URL"`, `"X=1;touch /tmp/pwned;Y": "v", "OCCTX_CURRENT": "v", "ANTHROPIC_BASE_URL"`, 1)
+	if injected == string(data) {
+		t.Fatalf("Expected the metadata to declare ANTHROPIC_BASE_URL, got %s", data)
+	}
+	if err := os.WriteFile(metadataPath, []byte(injected), 0644); err != nil {
+		t.Fatal(err)
+	}
+	var warnings []string
+	manager.SetWarningHandler(func(message string) { warnings = append(warnings, message) })
+	if err := manager.SwitchToContext("work"); err != nil {
+		t.Fatal(err)
+	}
+	change, err = manager.HookEnv("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if keys := strings.Join(change.Keys(), ","); keys != "ANTHROPIC_BASE_URL" {
+		t.Errorf("Expected invalid names to be skipped, got %s", keys)
+	}
+	if len(warnings) != 2 || !strings.Contains(warnings[0], "ignoring") {
+		t.Errorf("Expected a warning per skipped name, got %v", warnings)
+	}
+
+	// Sessions carry the variables too
+	session, err := manager.NewSessionConfig("work")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer session.Remove()
+	if env := strings.Join(session.Env(), "\n"); !strings.Contains(env, "ANTHROPIC_BASE_URL=https://gw") {
+		t.Errorf("Expected the session env to include the context's variables, got %s", env)
+	}
+}
+
+func TestIntegration_EnvHook(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the hook targets POSIX shells")
+	}
+
+	ith := NewIntegrationTestHelper(t)
+	defer ith.Cleanup()
+
+	ith.CreateSampleConfig()
+	for _, name := range []string{"work", "personal"} {
+		if _, _, err := ith.RunCommand("-n", name); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, stderr, err := ith.RunCommand("env", "set", "work", "HTTPS_PROXY=http://it's:3128"); err != nil {
+		t.Fatalf("env set failed: %v\n%s", err, stderr)
+	}
+	if stdout, _, _ := ith.RunCommand("env", "list", "work"); strings.TrimSpace(stdout) != "HTTPS_PROXY=http://it's:3128" {
+		t.Errorf("Unexpected env list output %q", stdout)
+	}
+	if _, _, err := ith.RunCommand("work"); err != nil {
+		t.Fatal(err)
+	}
+
+	stdout, stderr, err := ith.RunCommand("hook-env", "bash")
+	if err != nil {
+		t.Fatalf("hook-env failed: %v\n%s", err, stderr)
+	}
+	if !strings.Contains(stdout, `export HTTPS_PROXY='http://it'\''s:3128'`) || !strings.Contains(stdout, "export OCCTX_ENV_KEYS='HTTPS_PROXY'") {
+		t.Errorf("Unexpected hook-env output:\n%s", stdout)
+	}
+
+	// The output is valid shell that sets the variable
+	if sh, err := exec.LookPath("sh"); err == nil {
+		out, err := exec.Command(sh, "-c", stdout+`printf %s "$HTTPS_PROXY"`).Output()
+		if err != nil || string(out) != "http://it's:3128" {
+			t.Errorf("Evaluating hook-env output gave %q (%v)", out, err)
+		}
+	}
+
+	if _, _, err := ith.RunCommand("personal"); err != nil {
+		t.Fatal(err)
+	}
+	cmd := exec.Command(ith.BinaryPath, "hook-env", "zsh")
+	cmd.Env = ith.Env(context.AppliedEnvVar + "=HTTPS_PROXY")
+	out, err := cmd.Output()
+	if err != nil || string(out) != "unset HTTPS_PROXY\nunset OCCTX_ENV_KEYS\n" {
+		t.Errorf("Expected the variables to be unset, got %q (%v)", out, err)
+	}
+
+	stdout, _, err = ith.RunCommand("hook", "zsh")
+	if err != nil || !strings.Contains(stdout, "precmd_functions") || !strings.Contains(stdout, "hook-env zsh") {
+		t.Errorf("Unexpected hook output %q (%v)", stdout, err)
+	}
+	if _, _, err := ith.RunCommand("hook", "tcsh"); err == nil {
+		t.Error("Expected an error for an unsupported shell")
+	}
+}
@@ -0,0 +1,157 @@
+package test
+
+import (
+	"encoding/json"
+	"sort"
+	"testing"
+
+	"github.com/hungthai1401/occtx/internal/context"
+)
+
+func TestParseFieldFilter(t *testing.T) {
+	tests := []struct {
+		expr        string
+		expectError bool
+	}{
+		{"provider=anthropic", false},
+		{"agent.default.model=claude-4-sonnet", false},
+		{"theme=", false},
+		{"provider", true},
+		{"=value", true},
+	}
+
+	for _, tt := range tests {
+		_, err := context.ParseFieldFilter(tt.expr)
+		if tt.expectError && err == nil {
+			t.Errorf("ParseFieldFilter(%q) expected error, got nil", tt.expr)
+		}
+		if !tt.expectError && err != nil {
+			t.Errorf("ParseFieldFilter(%q) unexpected error: %v", tt.expr, err)
+		}
+	}
+}
+
+func TestFieldFilter_Matches(t *testing.T) {
+	data := map[string]interface{}{
+		"theme":    "dark",
+		"share":    false,
+		"provider": map[string]interface{}{"anthropic": map[string]interface{}{}},
+		"plugins":  []interface{}{"a", "b"},
+		"agent": map[string]interface{}{
+			"default": map[string]interface{}{"model": "claude-4-sonnet"},
+		},
+	}
+
+	tests := []struct {
+		expr     string
+		expected bool
+	}{
+		{"theme=dark", true},
+		{"theme=light", false},
+		{"share=false", true},
+		{"provider=anthropic", true},
+		{"provider=openai", false},
+		{"plugins=b", true},
+		{"agent.default.model=claude-4-sonnet", true},
+		{"agent.missing.model=claude-4-sonnet", false},
+	}
+
+	for _, tt := range tests {
+		filter, err := context.ParseFieldFilter(tt.expr)
+		if err != nil {
+			t.Fatalf("ParseFieldFilter(%q) failed: %v", tt.expr, err)
+		}
+		if got := filter.Matches(data); got != tt.expected {
+			t.Errorf("%q.Matches() = %v, want %v", tt.expr, got, tt.expected)
+		}
+	}
+}
+
+func TestManager_FilterContexts(t *testing.T) {
+	th := NewTestHelper(t)
+	defer th.Cleanup()
+
+	th.CreateSampleConfig()
+	manager := th.CreateManagerWithTempDir()
+
+	manager.CreateContext("work-a")
+	manager.CreateContextWithFormat("work-b", context.FormatJSONC)
+	manager.ImportContext("personal", []byte(`{"provider": {"openai": {}}}`))
+
+	jsonc := context.FormatJSONC
+	providerFilter, _ := context.ParseFieldFilter("provider=anthropic")
+
+	tests := []struct {
+		name     string
+		filter   context.ListFilter
+		expected []string
+	}{
+		{"none", context.ListFilter{}, []string{"personal", "work-a", "work-b"}},
+		{"glob", context.ListFilter{NameGlob: "work-*"}, []string{"work-a", "work-b"}},
+		{"format", context.ListFilter{Format: &jsonc}, []string{"work-b"}},
+		{"field", context.ListFilter{Fields: []context.FieldFilter{providerFilter}}, []string{"work-a", "work-b"}},
+		{"combined", context.ListFilter{NameGlob: "*-a", Fields: []context.FieldFilter{providerFilter}}, []string{"work-a"}},
+	}
+
+	for _, tt := range tests {
+		contexts, err := manager.FilterContexts(tt.filter)
+		if err != nil {
+			t.Fatalf("%s: FilterContexts failed: %v", tt.name, err)
+		}
+
+		var names []string
+		for _, ctx := range contexts {
+			names = append(names, ctx.Name)
+		}
+		sort.Strings(names)
+
+		if len(names) != len(tt.expected) {
+			t.Errorf("%s: got %v, want %v", tt.name, names, tt.expected)
+			continue
+		}
+		for i := range names {
+			if names[i] != tt.expected[i] {
+				t.Errorf("%s: got %v, want %v", tt.name, names, tt.expected)
+				break
+			}
+		}
+	}
+
+	if _, err := manager.FilterContexts(context.ListFilter{NameGlob: "["}); err == nil {
+		t.Error("Expected error for malformed glob")
+	}
+}
+
+func TestIntegration_ListFilterJSON(t *testing.T) {
+	ith := NewIntegrationTestHelper(t)
+	defer ith.Cleanup()
+
+	ith.CreateSampleConfig()
+
+	ith.RunCommand("-n", "work-a")
+	ith.RunCommand("-n", "work-b", "-f", "jsonc")
+	ith.RunCommand("-n", "personal")
+	ith.RunCommand("work-b")
+
+	stdout, _, err := ith.RunCommand("--name-glob", "work-*", "--format", "jsonc", "--filter", "provider=anthropic", "-o", "json")
+	if err != nil {
+		t.Fatalf("Filtered list failed: %v", err)
+	}
+
+	var entries []struct {
+		Name    string `json:"name"`
+		Format  string `json:"format"`
+		Current bool   `json:"current"`
+	}
+	if err := json.Unmarshal([]byte(stdout), &entries); err != nil {
+		t.Fatalf("Expected JSON output, got %q: %v", stdout, err)
+	}
+
+	if len(entries) != 1 || entries[0].Name != "work-b" || entries[0].Format != "jsonc" || !entries[0].Current {
+		t.Errorf("Unexpected entries: %+v", entries)
+	}
+
+	if _, _, err := ith.RunCommand("-o", "yaml"); err == nil {
+		t.Error("Expected error for unsupported output format")
+	}
+}
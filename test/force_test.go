@@ -0,0 +1,94 @@
+package test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hungthai1401/occtx/internal/context"
+)
+
+func TestManager_ForceReplacesContext(t *testing.T) {
+	th := NewTestHelper(t)
+	defer th.Cleanup()
+
+	th.CreateSampleConfig()
+	manager := th.CreateManagerWithTempDir()
+
+	if err := manager.CreateContextWithFormat("work", context.FormatJSONC); err != nil {
+		t.Fatal(err)
+	}
+
+	// Without force, an existing context in any format is an error
+	if err := manager.ImportContext("work", []byte(`{"model": "new"}`)); err == nil {
+		t.Fatal("Expected import over an existing JSONC context to fail")
+	}
+
+	manager.SetForce(true)
+
+	// Invalid input must not touch the existing context
+	if err := manager.ImportContext("work", []byte(`{not json`)); err == nil {
+		t.Fatal("Expected invalid JSON to be rejected")
+	}
+	if _, err := os.Stat(filepath.Join(th.SettingsDir, "work.jsonc")); err != nil {
+		t.Fatal("Existing context should survive a failed forced import")
+	}
+
+	if err := manager.ImportContext("work", []byte(`{"model": "new"}`)); err != nil {
+		t.Fatalf("Forced import failed: %v", err)
+	}
+
+	ctx, err := manager.GetContext("work")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ctx.Format != context.FormatJSON || ctx.Data["model"] != "new" {
+		t.Errorf("Expected replaced JSON context, got %s %v", ctx.Format, ctx.Data)
+	}
+
+	trashed, err := filepath.Glob(filepath.Join(th.SettingsDir, "trash", "work-*.jsonc"))
+	if err != nil || len(trashed) != 1 {
+		t.Fatalf("Expected one trashed backup, got %v (%v)", trashed, err)
+	}
+
+	// The trash is not listed as a context
+	contexts, _ := manager.ListContexts()
+	if len(contexts) != 1 {
+		t.Errorf("Expected only the replaced context, got %d", len(contexts))
+	}
+}
+
+func TestIntegration_Force(t *testing.T) {
+	ith := NewIntegrationTestHelper(t)
+	defer ith.Cleanup()
+
+	ith.CreateSampleConfig()
+
+	if _, _, err := ith.RunCommand("-n", "work"); err != nil {
+		t.Fatal(err)
+	}
+
+	_, stderr, err := ith.RunCommand("-n", "work")
+	if err == nil || !strings.Contains(stderr, "--force") {
+		t.Errorf("Expected create to fail with a --force hint, got err=%v stderr=%s", err, stderr)
+	}
+
+	if _, stderr, err := ith.RunCommand("-n", "work", "--force"); err != nil {
+		t.Fatalf("-n --force failed: %v\n%s", err, stderr)
+	}
+
+	if _, stderr, err := ith.RunCommandWithInput(`{"model": "ci"}`, "--import", "work", "--force"); err != nil {
+		t.Fatalf("--import --force failed: %v\n%s", err, stderr)
+	}
+
+	data, err := os.ReadFile(filepath.Join(ith.SettingsDir, "work.json"))
+	if err != nil || !strings.Contains(string(data), `"ci"`) {
+		t.Errorf("Expected imported content, got %s (%v)", data, err)
+	}
+
+	trashed, _ := filepath.Glob(filepath.Join(ith.SettingsDir, "trash", "work-*.json"))
+	if len(trashed) != 2 {
+		t.Errorf("Expected two backups in the trash, got %v", trashed)
+	}
+}
@@ -0,0 +1,111 @@
+package test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hungthai1401/occtx/internal/context"
+)
+
+func TestManager_ContextFreshness(t *testing.T) {
+	th := NewTestHelper(t)
+	defer th.Cleanup()
+
+	th.CreateSampleConfig()
+	sharedDir := createSharedDir(t, th.TempDir, th.ConfigDir)
+	manager := th.CreateManagerWithTempDir()
+	if err := manager.CreateContext("local"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := manager.CopyContext("baseline", "mine"); err != nil {
+		t.Fatal(err)
+	}
+
+	freshness := func() map[string]context.Freshness {
+		t.Helper()
+		contexts, err := manager.ListContexts()
+		if err != nil {
+			t.Fatal(err)
+		}
+		freshness, err := manager.ContextFreshness(contexts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return freshness
+	}
+
+	if got := freshness(); len(got) != 1 || got["mine"] != context.FreshnessInSync {
+		t.Errorf("Expected only 'mine' to be in sync, got %v", got)
+	}
+
+	// Comments and formatting don't count as changes
+	sharedPath := filepath.Join(sharedDir, "baseline.jsonc")
+	if err := os.WriteFile(sharedPath, []byte("// reformatted\n{ \"theme\": \"corp\" }\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if got := freshness()["mine"]; got != context.FreshnessInSync {
+		t.Errorf("Expected in-sync after reformatting, got %q", got)
+	}
+
+	if err := os.WriteFile(sharedPath, []byte(`{"theme": "corp-2"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if got := freshness()["mine"]; got != context.FreshnessRemoteAhead {
+		t.Errorf("Expected remote-ahead, got %q", got)
+	}
+
+	minePath := filepath.Join(th.SettingsDir, "mine.jsonc")
+	if err := os.WriteFile(minePath, []byte(`{"theme": "mine"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if got := freshness()["mine"]; got != context.FreshnessConflict {
+		t.Errorf("Expected conflict, got %q", got)
+	}
+
+	if err := os.WriteFile(sharedPath, []byte(`{"theme": "corp"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if got := freshness()["mine"]; got != context.FreshnessLocalAhead {
+		t.Errorf("Expected local-ahead, got %q", got)
+	}
+
+	// A source that is gone gives no indicator
+	if err := os.Remove(sharedPath); err != nil {
+		t.Fatal(err)
+	}
+	if got, ok := freshness()["mine"]; ok {
+		t.Errorf("Expected no freshness without a source, got %q", got)
+	}
+}
+
+func TestIntegration_FreshnessInListing(t *testing.T) {
+	ith := NewIntegrationTestHelper(t)
+	defer ith.Cleanup()
+
+	ith.CreateSampleConfig()
+	sharedDir := createSharedDir(t, ith.TempDir, ith.ConfigDir)
+	if _, stderr, err := ith.RunCommand("copy", "baseline", "mine"); err != nil {
+		t.Fatalf("copy failed: %v\n%s", err, stderr)
+	}
+	if err := os.WriteFile(filepath.Join(sharedDir, "baseline.jsonc"), []byte(`{"theme": "corp-2"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	stdout, _, err := ith.RunCommand()
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	if !strings.Contains(stdout, "mine  remote-ahead") {
+		t.Errorf("Expected the freshness in the listing, got:\n%s", stdout)
+	}
+
+	stdout, _, err = ith.RunCommand("-o", "json")
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	if !strings.Contains(stdout, `"freshness": "remote-ahead"`) {
+		t.Errorf("Expected the freshness in the JSON listing, got:\n%s", stdout)
+	}
+}
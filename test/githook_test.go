@@ -0,0 +1,153 @@
+package test
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestManager_ValidateContexts(t *testing.T) {
+	th := NewTestHelper(t)
+	defer th.Cleanup()
+
+	settingsDir := filepath.Join(th.ConfigDir, "settings")
+	if err := os.MkdirAll(settingsDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	files := map[string]string{
+		"clean.json":  `{"theme": "dark", "model": "anthropic/claude-sonnet-4"}`,
+		"secret.json": `{"provider": {"anthropic": {"options": {"apiKey": "sk-literal"}}}}`,
+		"schema.json": `{"theme": 3, "model": "sonnet"}`,
+		"broken.json": `{"theme": `,
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(settingsDir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	manager := th.CreateManagerWithTempDir()
+	results, err := manager.ValidateContexts(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != len(files) {
+		t.Fatalf("Expected %d results, got %d", len(files), len(results))
+	}
+
+	want := map[string]string{
+		"clean":  "",
+		"secret": "secret: literal value at 'provider.anthropic.options.apiKey'",
+		"schema": "schema: 'model' must be provider/model",
+		"broken": "invalid JSON",
+	}
+	for _, result := range results {
+		expected := want[result.Context]
+		if expected == "" {
+			if !result.Valid() {
+				t.Errorf("Expected '%s' to be valid, got %v", result.Context, result.Findings)
+			}
+			continue
+		}
+		if !strings.Contains(strings.Join(result.Findings, "\n"), expected) {
+			t.Errorf("Expected a finding containing %q for '%s', got %v", expected, result.Context, result.Findings)
+		}
+	}
+
+	// Named contexts are validated on their own
+	results, err = manager.ValidateContexts([]string{"clean"})
+	if err != nil || len(results) != 1 || !results[0].Valid() {
+		t.Errorf("Expected 'clean' alone to validate, got %v, %v", results, err)
+	}
+	if _, err := manager.ValidateContexts([]string{"nosuch"}); err == nil {
+		t.Error("Expected an error for an unknown context")
+	}
+}
+
+func TestIntegration_PreCommitHook(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+
+	ith := NewIntegrationTestHelper(t)
+	defer ith.Cleanup()
+
+	repo := filepath.Join(ith.TempDir, "repo")
+	if err := os.MkdirAll(repo, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if out, err := exec.Command("git", "init", "-q", repo).CombinedOutput(); err != nil {
+		t.Fatalf("git init failed: %v\n%s", err, out)
+	}
+
+	run := func(args ...string) (string, error) {
+		cmd := exec.Command(ith.BinaryPath, args...)
+		cmd.Dir = repo
+		cmd.Env = ith.Env()
+		out, err := cmd.CombinedOutput()
+		return string(out), err
+	}
+
+	hookPath := filepath.Join(repo, ".git", "hooks", "pre-commit")
+
+	// A hook occtx didn't write is left alone without --force
+	if err := os.MkdirAll(filepath.Dir(hookPath), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(hookPath, []byte("#!/bin/sh\nexit 0\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if out, err := run("hook", "install", "pre-commit"); err == nil || !strings.Contains(out, "--force") {
+		t.Fatalf("Expected install to refuse a foreign hook, got %v: %s", err, out)
+	}
+
+	if out, err := run("hook", "install", "pre-commit", "--force"); err != nil {
+		t.Fatalf("install --force failed: %v\n%s", err, out)
+	}
+	script, err := os.ReadFile(hookPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(script), "validate --all") {
+		t.Errorf("Expected the hook to run validate, got:\n%s", script)
+	}
+	if info, err := os.Stat(hookPath); err != nil || info.Mode().Perm()&0100 == 0 {
+		t.Errorf("Expected an executable hook, got %v, %v", info, err)
+	}
+	if _, err := os.Stat(hookPath + ".orig"); err != nil {
+		t.Errorf("Expected the previous hook to be kept: %v", err)
+	}
+
+	// Reinstalling over our own hook needs no --force
+	if out, err := run("hook", "install", "pre-commit"); err != nil {
+		t.Fatalf("reinstall failed: %v\n%s", err, out)
+	}
+
+	// A project context with a literal key fails validation
+	projectSettings := filepath.Join(repo, "opencode", "settings")
+	if err := os.MkdirAll(projectSettings, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(projectSettings, "team.json"), []byte(`{"provider": {"openai": {"options": {"apiKey": "sk-literal"}}}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if out, err := run("--in-project", "validate", "--all"); err == nil || !strings.Contains(out, "apiKey") {
+		t.Errorf("Expected validation to fail on the literal key, got %v: %s", err, out)
+	}
+	if err := os.WriteFile(filepath.Join(projectSettings, "team.json"), []byte(`{"provider": {"openai": {"options": {"apiKey": "{env:OPENAI_API_KEY}"}}}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if out, err := run("--in-project", "validate", "--all"); err != nil {
+		t.Errorf("Expected validation to pass, got %v: %s", err, out)
+	}
+
+	// Uninstalling restores the hook set aside by --force
+	if out, err := run("hook", "uninstall", "pre-commit"); err != nil {
+		t.Fatalf("uninstall failed: %v\n%s", err, out)
+	}
+	if script, err := os.ReadFile(hookPath); err != nil || strings.Contains(string(script), "occtx") {
+		t.Errorf("Expected the previous hook to be restored, got %q, %v", script, err)
+	}
+}
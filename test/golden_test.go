@@ -0,0 +1,105 @@
+package test
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// updateGolden rewrites the golden files: go test ./test -run Golden -update
+var updateGolden = flag.Bool("update", false, "rewrite the golden files in testdata/golden")
+
+// goldenCase is one invocation whose stdout, stderr and exit code are part
+// of the CLI contract
+type goldenCase struct {
+	name string
+	args []string
+	env  []string
+}
+
+// goldenCases run in order against one home directory. Changing their
+// output breaks wrappers; if a change is intended, bump ContractVersion in
+// cmd/contract.go and rerun with -update.
+var goldenCases = []goldenCase{
+	{name: "list-empty"},
+	{name: "create", args: []string{"-n", "work"}},
+	{name: "list", args: []string{}},
+	{name: "list-json", args: []string{"-o", "json"}},
+	{name: "switch", args: []string{"work"}},
+	{name: "current", args: []string{"-c"}},
+	{name: "show", args: []string{"-s", "work"}},
+	{name: "switch-not-found", args: []string{"wrok"}},
+	{name: "switch-not-found-strict", args: []string{"--strict", "wrok"}},
+	{name: "ci-not-found", args: []string{"--ci", "wrok"}},
+	{name: "ci-usage", args: []string{"--ci", "stats", "--reset"}},
+	{name: "json-error", args: []string{"stats", "--reset", "-o", "json"}},
+	{name: "read-only", args: []string{"--ci", "--read-only", "-n", "other"}},
+	{name: "contract", args: []string{"contract"}},
+	{name: "contract-json", args: []string{"contract", "-o", "json"}},
+}
+
+func TestIntegration_GoldenOutput(t *testing.T) {
+	ith := NewIntegrationTestHelper(t)
+	defer ith.Cleanup()
+
+	active := filepath.Join(ith.ConfigDir, "opencode.json")
+	if err := os.WriteFile(active, []byte("{\n  \"theme\": \"default\"\n}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, gc := range goldenCases {
+		got := runGolden(t, ith, gc)
+		path := filepath.Join("testdata", "golden", gc.name+".golden")
+
+		if *updateGolden {
+			if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(path, []byte(got), 0644); err != nil {
+				t.Fatal(err)
+			}
+			continue
+		}
+
+		want, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("%s: %v (run with -update to create it)", gc.name, err)
+		}
+		if got != string(want) {
+			t.Errorf("%s: output differs from %s\n--- want\n%s--- got\n%s", gc.name, path, want, got)
+		}
+	}
+}
+
+// runGolden runs one case and renders what it did the way golden files store it
+func runGolden(t *testing.T, ith *IntegrationTestHelper, gc goldenCase) string {
+	t.Helper()
+
+	cmd := exec.Command(ith.BinaryPath, gc.args...)
+	cmd.Env = ith.Env(gc.env...)
+	var stdout, stderr strings.Builder
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	code := 0
+	if err := cmd.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if !errors.As(err, &exitErr) {
+			t.Fatalf("%s: %v", gc.name, err)
+		}
+		code = exitErr.ExitCode()
+	}
+
+	// Paths differ per run and per platform
+	normalize := func(s string) string {
+		s = strings.ReplaceAll(s, filepath.ToSlash(ith.TempDir), "$HOME")
+		return strings.ReplaceAll(s, ith.TempDir, "$HOME")
+	}
+	return fmt.Sprintf("$ %s\nexit: %d\n--- stdout\n%s\n--- stderr\n%s\n",
+		strings.Join(append([]string{"occtx"}, gc.args...), " "), code, normalize(stdout.String()), normalize(stderr.String()))
+}
@@ -0,0 +1,167 @@
+package test
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hungthai1401/occtx/internal/config"
+	"github.com/hungthai1401/occtx/internal/context"
+)
+
+// writeHooksConfig writes hooks to the occtx config in configDir
+func writeHooksConfig(t *testing.T, configDir string, hooks []config.HookConfig) {
+	t.Helper()
+	data, err := json.Marshal(map[string]interface{}{"hooks": hooks})
+	if err != nil {
+		t.Fatal(err)
+	}
+	writeOcctxConfig(t, configDir, string(data))
+}
+
+func TestManager_SwitchHooks(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("hooks in this test are shell commands")
+	}
+	th := NewTestHelper(t)
+	defer th.Cleanup()
+
+	th.CreateSampleConfig()
+	setup := th.CreateManagerWithTempDir()
+	for _, name := range []string{"work", "blocked"} {
+		if err := setup.CreateContext(name); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	record := filepath.Join(th.TempDir, "hooks.log")
+	appendLine := func(line string) []string {
+		return []string{"sh", "-c", `echo "` + line + `" >> "` + record + `"`}
+	}
+	t.Setenv("OCCTX_TEST_SECRET", "secret")
+	t.Setenv("OCCTX_TEST_AWS_REGION", "eu-west-1")
+	writeHooksConfig(t, th.ConfigDir, []config.HookConfig{
+		{Name: "post", On: config.HookPostSwitch, Run: appendLine("post $OCCTX_PREVIOUS_CONTEXT->$OCCTX_CONTEXT")},
+		{Name: "second", On: config.HookPreSwitch, Run: appendLine("second"), Order: 5},
+		{Name: "env", On: config.HookPreSwitch, Env: []string{"PATH", "OCCTX_TEST_AWS_*"},
+			Run: appendLine("env secret=$OCCTX_TEST_SECRET region=$OCCTX_TEST_AWS_REGION")},
+		{Name: "slow", On: config.HookPreSwitch, Run: []string{"sleep", "5"}, Timeout: "100ms", OnFailure: config.HookWarn, Order: -1},
+		{Name: "guard", On: config.HookPreSwitch, Run: []string{"sh", "-c", `test "$OCCTX_CONTEXT" != blocked`}, Order: 10},
+	})
+
+	manager := th.CreateManagerWithTempDir()
+	var warnings []string
+	manager.SetWarningHandler(func(message string) { warnings = append(warnings, message) })
+	if err := manager.SwitchToContext("work"); err != nil {
+		t.Fatalf("SwitchToContext failed: %v", err)
+	}
+
+	data, _ := os.ReadFile(record)
+	want := "env secret= region=eu-west-1\nsecond\npost ->work\n"
+	if string(data) != want {
+		t.Errorf("Expected hooks in order with their allowlisted env:\n%s\ngot:\n%s", want, data)
+	}
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "'slow' failed: timed out") {
+		t.Errorf("Expected a timeout warning for the warn hook, got %v", warnings)
+	}
+
+	// An aborting pre-switch hook leaves the switch undone
+	err := manager.SwitchToContext("blocked")
+	var hookErr *context.HookError
+	if !errors.As(err, &hookErr) || hookErr.Hook != "guard" {
+		t.Fatalf("Expected a HookError from 'guard', got %v", err)
+	}
+	if current, _ := manager.GetCurrentContext(); current != "work" {
+		t.Errorf("Expected to stay on 'work', got %q", current)
+	}
+
+	// Strict mode turns the failing warn hook into an abort
+	manager.SetStrict(true)
+	if err := manager.SwitchToContext("work"); err == nil {
+		t.Error("Expected strict mode to refuse the switch after a hook warning")
+	}
+}
+
+func TestManager_HookTimeoutKillsChildren(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("hooks in this test are shell commands")
+	}
+	th := NewTestHelper(t)
+	defer th.Cleanup()
+
+	th.CreateSampleConfig()
+	// The shell's sleep keeps the output pipe open after the shell is killed
+	writeHooksConfig(t, th.ConfigDir, []config.HookConfig{
+		{Name: "slow", On: config.HookPreSwitch, Run: []string{"sh", "-c", "sleep 8; echo done"}, Timeout: "500ms"},
+	})
+	manager := th.CreateManagerWithTempDir()
+
+	start := time.Now()
+	out, err := manager.TestHook("slow", "work")
+	if err == nil || !strings.Contains(err.Error(), "timed out after 500ms") {
+		t.Errorf("Expected a timeout, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 4*time.Second {
+		t.Errorf("Expected the hook to stop soon after its timeout, took %s", elapsed)
+	}
+	if strings.Contains(string(out), "done") {
+		t.Errorf("Expected the hook's children to be killed, got output %q", out)
+	}
+}
+
+func TestManager_HookConfigValidation(t *testing.T) {
+	tests := []struct {
+		name  string
+		hooks []config.HookConfig
+	}{
+		{"missing name", []config.HookConfig{{On: config.HookPreSwitch, Run: []string{"true"}}}},
+		{"duplicate name", []config.HookConfig{{Name: "a", On: config.HookPreSwitch, Run: []string{"true"}}, {Name: "a", On: config.HookPostSwitch, Run: []string{"true"}}}},
+		{"unknown event", []config.HookConfig{{Name: "a", On: "pre-delete", Run: []string{"true"}}}},
+		{"empty run", []config.HookConfig{{Name: "a", On: config.HookPreSwitch}}},
+		{"bad timeout", []config.HookConfig{{Name: "a", On: config.HookPreSwitch, Run: []string{"true"}, Timeout: "soon"}}},
+		{"bad policy", []config.HookConfig{{Name: "a", On: config.HookPreSwitch, Run: []string{"true"}, OnFailure: "ignore"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := config.ValidateHooks(tt.hooks); err == nil {
+				t.Error("Expected a validation error")
+			}
+		})
+	}
+}
+
+func TestIntegration_HooksCommand(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("hooks in this test are shell commands")
+	}
+	ith := NewIntegrationTestHelper(t)
+	defer ith.Cleanup()
+
+	ith.CreateSampleConfig()
+	writeHooksConfig(t, ith.ConfigDir, []config.HookConfig{
+		{Name: "late", On: config.HookPostSwitch, Run: []string{"true"}, Order: 2},
+		{Name: "greet", On: config.HookPreSwitch, Run: []string{"sh", "-c", "echo hello $OCCTX_CONTEXT"}, OnFailure: config.HookWarn},
+	})
+
+	out, stderr, err := ith.RunCommand("hooks", "list")
+	if err != nil {
+		t.Fatalf("hooks list failed: %v\n%s", err, stderr)
+	}
+	if strings.Index(out, "greet") > strings.Index(out, "late") || !strings.Contains(out, "warn") {
+		t.Errorf("Expected hooks in order with their policy, got:\n%s", out)
+	}
+
+	out, stderr, err = ith.RunCommand("hooks", "test", "greet", "--context", "work")
+	if err != nil || !strings.Contains(out, "hello work") {
+		t.Errorf("Expected hooks test to run the hook, got %v:\n%s%s", err, out, stderr)
+	}
+
+	if _, stderr, err := ith.RunCommand("hooks", "test", "missing"); err == nil || !strings.Contains(stderr, "no hook named 'missing'") {
+		t.Errorf("Expected an error for an unknown hook, got %v:\n%s", err, stderr)
+	}
+}
@@ -0,0 +1,60 @@
+package test
+
+import (
+	"encoding/xml"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestIntegration_IdeaInit(t *testing.T) {
+	ith := NewIntegrationTestHelper(t)
+	defer ith.Cleanup()
+
+	stdout, stderr, err := ith.RunCommand("--in-project", "idea", "init")
+	if err != nil {
+		t.Fatalf("idea init failed: %v\n%s", err, stderr)
+	}
+
+	var set struct {
+		Name  string `xml:"name,attr"`
+		Tools []struct {
+			Name    string `xml:"name,attr"`
+			Options []struct {
+				Name  string `xml:"name,attr"`
+				Value string `xml:"value,attr"`
+			} `xml:"exec>option"`
+		} `xml:"tool"`
+	}
+	if err := xml.Unmarshal([]byte(stdout), &set); err != nil {
+		t.Fatalf("idea init output is not valid XML: %v\n%s", err, stdout)
+	}
+	if set.Name != "occtx" || len(set.Tools) != 5 {
+		t.Fatalf("Expected the occtx group with 5 tools, got %q with %d", set.Name, len(set.Tools))
+	}
+	for _, option := range set.Tools[0].Options {
+		if option.Name == "PARAMETERS" && option.Value != "--in-project $Prompt$" {
+			t.Errorf("Expected the switch tool to prompt in the project scope, got %q", option.Value)
+		}
+	}
+
+	if runtime.GOOS != "linux" {
+		return
+	}
+	configHome := filepath.Join(ith.TempDir, "xdg")
+	product := filepath.Join(configHome, "JetBrains", "GoLand2024.2")
+	if err := os.MkdirAll(product, 0755); err != nil {
+		t.Fatal(err)
+	}
+	cmd := exec.Command(ith.BinaryPath, "idea", "init", "--install")
+	cmd.Env = ith.Env("XDG_CONFIG_HOME=" + configHome)
+	if out, err := cmd.CombinedOutput(); err != nil || !strings.Contains(string(out), "Installed") {
+		t.Fatalf("idea init --install failed: %v\n%s", err, out)
+	}
+	if _, err := os.Stat(filepath.Join(product, "tools", "occtx.xml")); err != nil {
+		t.Errorf("Expected the tools file to be installed: %v", err)
+	}
+}
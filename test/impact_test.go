@@ -0,0 +1,77 @@
+package test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hungthai1401/occtx/internal/context"
+)
+
+func TestManager_Impact(t *testing.T) {
+	th := NewTestHelper(t)
+	defer th.Cleanup()
+
+	th.CreateSampleConfig()
+	manager := th.CreateManagerWithTempDir()
+	manager.SetForce(true)
+
+	if err := manager.CreateContext("base"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := manager.CopyContext("base", "team-a"); err != nil {
+		t.Fatal(err)
+	}
+	if err := manager.CreateContext("unrelated"); err != nil {
+		t.Fatal(err)
+	}
+
+	dependents, err := manager.Impact("base")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(dependents) != 1 || dependents[0].Kind != context.DependentCopy || dependents[0].Name != "team-a" || len(dependents[0].Changes) != 0 {
+		t.Fatalf("Expected an up-to-date copy, got %+v", dependents)
+	}
+
+	// Editing the base shows up as pending changes for the copy and the active config
+	if err := manager.SwitchToContext("base"); err != nil {
+		t.Fatal(err)
+	}
+	settingsDir := filepath.Join(th.ConfigDir, "settings")
+	if err := os.WriteFile(filepath.Join(settingsDir, "base.json"), []byte(`{"theme": "light"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(settingsDir, "base.local.json"), []byte(`{"username": "me"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	dependents, err = manager.Impact("base")
+	if err != nil {
+		t.Fatal(err)
+	}
+	kinds := map[context.DependentKind]context.Dependent{}
+	for _, dependent := range dependents {
+		kinds[dependent.Kind] = dependent
+	}
+	if len(dependents) != 3 {
+		t.Fatalf("Expected a copy, an override and the active config, got %+v", dependents)
+	}
+	if len(kinds[context.DependentCopy].Changes) == 0 {
+		t.Error("Expected pending changes for the copy")
+	}
+	active := kinds[context.DependentActive]
+	found := false
+	for _, change := range active.Changes {
+		if change.Path == "username" && change.Kind == context.ChangeAdded {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("Expected the active config changes to include the override, got %+v", active.Changes)
+	}
+
+	if dependents, err := manager.Impact("unrelated"); err != nil || len(dependents) != 0 {
+		t.Errorf("Expected no dependents, got %+v, %v", dependents, err)
+	}
+}
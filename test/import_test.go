@@ -0,0 +1,115 @@
+package test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/hungthai1401/occtx/internal/context"
+)
+
+func TestManager_ImportContextWithOptions(t *testing.T) {
+	valid := `{"$schema": "https://opencode.ai/config.json", "model": "anthropic/claude-sonnet"}`
+	badSchema := `{"model": "sonnet", "share": "always", "instructions": "README.md", "colour": "red"}`
+	secret := `{"provider": {"openai": {"options": {"apiKey": "sk-123"}}, "anthropic": {"options": {"apiKey": "{env:KEY}"}}}}`
+
+	tests := []struct {
+		name         string
+		input        string
+		opts         context.ImportOptions
+		wantWarnings int
+		wantRejected int
+	}{
+		{"checks off", badSchema, context.ImportOptions{}, 0, 0},
+		{"valid config", valid, context.ImportOptions{Validate: context.PolicyReject, Secrets: context.PolicyReject}, 0, 0},
+		{"schema warn", badSchema, context.ImportOptions{Validate: context.PolicyWarn}, 4, 0},
+		{"schema reject", badSchema, context.ImportOptions{Validate: context.PolicyReject}, 0, 4},
+		{"secret warn", secret, context.ImportOptions{Secrets: context.PolicyWarn}, 1, 0},
+		{"secret reject", secret, context.ImportOptions{Validate: context.PolicyWarn, Secrets: context.PolicyReject}, 0, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			th := NewTestHelper(t)
+			defer th.Cleanup()
+
+			manager := th.CreateManagerWithTempDir()
+			warnings, err := manager.ImportContextWithOptions("imported", []byte(tt.input), context.Source{Kind: context.SourceImport}, tt.opts)
+
+			if len(warnings) != tt.wantWarnings {
+				t.Errorf("got %d warnings, want %d: %v", len(warnings), tt.wantWarnings, warnings)
+			}
+
+			var rejected *context.ImportRejectedError
+			if tt.wantRejected > 0 {
+				if !errors.As(err, &rejected) || len(rejected.Findings) != tt.wantRejected {
+					t.Fatalf("expected %d rejected findings, got %v", tt.wantRejected, err)
+				}
+				if _, err := manager.GetContext("imported"); err == nil {
+					t.Error("Rejected import should not create a context")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("import failed: %v", err)
+			}
+		})
+	}
+}
+
+func TestManager_ImportConvertsFormat(t *testing.T) {
+	th := NewTestHelper(t)
+	defer th.Cleanup()
+
+	manager := th.CreateManagerWithTempDir()
+	input := "// team config\n{\"theme\": \"dark\", \"$schema\": \"https://opencode.ai/config.json\"}\n"
+	if _, err := manager.ImportContextWithOptions("team", []byte(input), context.Source{Kind: context.SourceImport}, context.ImportOptions{Format: context.FormatJSONC}); err != nil {
+		t.Fatalf("JSONC import failed: %v", err)
+	}
+
+	ctx, err := manager.GetContext("team")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ctx.Format != context.FormatJSONC || ctx.Data["theme"] != "dark" {
+		t.Errorf("Expected JSONC context with imported data, got %s %v", ctx.Format, ctx.Data)
+	}
+}
+
+func TestParsePolicy(t *testing.T) {
+	for input, want := range map[string]context.Policy{"": context.PolicyOff, "off": context.PolicyOff, "WARN": context.PolicyWarn, "reject": context.PolicyReject} {
+		if got, err := context.ParsePolicy(input); err != nil || got != want {
+			t.Errorf("ParsePolicy(%q) = %q, %v; want %q", input, got, err, want)
+		}
+	}
+	if _, err := context.ParsePolicy("block"); err == nil {
+		t.Error("Expected error for unknown policy")
+	}
+}
+
+func TestIntegration_ImportPolicy(t *testing.T) {
+	ith := NewIntegrationTestHelper(t)
+	defer ith.Cleanup()
+
+	secret := `{"model": "openai/gpt-4.1", "provider": {"openai": {"options": {"apiKey": "sk-123"}}}}`
+
+	_, stderr, err := ith.RunCommandWithInput(secret, "--import", "team", "--secrets")
+	if err == nil || !strings.Contains(stderr, "provider.openai.options.apiKey") {
+		t.Errorf("Expected --secrets to reject the import, got err=%v stderr=%s", err, stderr)
+	}
+
+	// Policy from the occtx config, flag still overrides format
+	writeOcctxConfig(t, ith.ConfigDir, `{"import": {"secrets": "warn", "validate": "reject"}}`)
+	stdout, stderr, err := ith.RunCommandWithInput(secret, "--import", "team", "-f", "jsonc")
+	if err != nil {
+		t.Fatalf("import with warn policy failed: %v\n%s", err, stderr)
+	}
+	if !strings.Contains(stderr, "Warning: secret") || !strings.Contains(stdout, "JSONC") {
+		t.Errorf("Expected secret warning and JSONC import, got stdout=%s stderr=%s", stdout, stderr)
+	}
+
+	_, stderr, err = ith.RunCommandWithInput(`{"colour": "red"}`, "--import", "bad")
+	if err == nil || !strings.Contains(stderr, "unknown key 'colour'") {
+		t.Errorf("Expected configured schema policy to reject, got err=%v stderr=%s", err, stderr)
+	}
+}
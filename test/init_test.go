@@ -0,0 +1,162 @@
+package test
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hungthai1401/occtx/internal/context"
+)
+
+func TestFindDirContext(t *testing.T) {
+	root := t.TempDir()
+	nested := filepath.Join(root, "project", "src", "pkg")
+	if err := os.MkdirAll(nested, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	if name, _, err := context.FindDirContext(nested); err != nil || name != "" {
+		t.Fatalf("Expected no directory context, got %q (%v)", name, err)
+	}
+
+	file := filepath.Join(root, "project", ".occtx-context")
+	if err := os.WriteFile(file, []byte("  work \n# ignored\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	name, path, err := context.FindDirContext(nested)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if name != "work" || path != file {
+		t.Errorf("Expected 'work' from %s, got %q from %s", file, name, path)
+	}
+
+	// A nearer file wins; a blank one is skipped
+	if err := os.WriteFile(filepath.Join(root, "project", "src", ".occtx-context"), []byte("demo\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(nested, ".occtx-context"), []byte("\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if name, _, _ := context.FindDirContext(nested); name != "demo" {
+		t.Errorf("Expected the nearest non-blank file to win, got %q", name)
+	}
+}
+
+func TestIntegration_HookEnvAutoSwitch(t *testing.T) {
+	ith := NewIntegrationTestHelper(t)
+	defer ith.Cleanup()
+
+	ith.CreateSampleConfig()
+	for _, name := range []string{"work", "demo"} {
+		if _, stderr, err := ith.RunCommand("-n", name); err != nil {
+			t.Fatalf("create %s failed: %v\n%s", name, err, stderr)
+		}
+	}
+	if _, stderr, err := ith.RunCommand("work"); err != nil {
+		t.Fatalf("switch failed: %v\n%s", err, stderr)
+	}
+
+	project := filepath.Join(ith.TempDir, "project")
+	if err := os.MkdirAll(project, 0755); err != nil {
+		t.Fatal(err)
+	}
+	file := filepath.Join(project, ".occtx-context")
+	if err := os.WriteFile(file, []byte("demo\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	hookEnv := func(extra ...string) (string, string) {
+		cmd := exec.Command(ith.BinaryPath, "hook-env", "bash", "--auto-switch")
+		cmd.Dir = project
+		cmd.Env = ith.Env(extra...)
+		var stdout, stderr strings.Builder
+		cmd.Stdout = &stdout
+		cmd.Stderr = &stderr
+		if err := cmd.Run(); err != nil {
+			t.Fatalf("hook-env failed: %v\n%s", err, stderr.String())
+		}
+		return stdout.String(), stderr.String()
+	}
+
+	stdout, stderr := hookEnv()
+	if !strings.Contains(stderr, "switched to context 'demo'") {
+		t.Errorf("Expected a switch notice, got %q", stderr)
+	}
+	// The path may be spelled differently through symlinked temp dirs
+	if !strings.Contains(stdout, "export OCCTX_AUTO_SWITCHED=") || !strings.Contains(stdout, ".occtx-context=demo'") {
+		t.Errorf("Expected the handled file to be remembered, got:\n%s", stdout)
+	}
+	current, _, _ := ith.RunCommand("-c")
+	if strings.TrimSpace(current) != "demo" {
+		t.Errorf("Expected 'demo' to be current, got %q", current)
+	}
+
+	// Once handled, a manual switch away sticks
+	if _, stderr, err := ith.RunCommand("work"); err != nil {
+		t.Fatalf("switch failed: %v\n%s", err, stderr)
+	}
+	var handled string
+	for _, line := range strings.Split(stdout, "\n") {
+		if value, ok := strings.CutPrefix(line, "export OCCTX_AUTO_SWITCHED="); ok {
+			handled = strings.Trim(value, "'")
+		}
+	}
+	_, stderr = hookEnv(context.AutoSwitchEnvVar + "=" + handled)
+	if stderr != "" {
+		t.Errorf("Expected no switch for a handled file, got %q", stderr)
+	}
+	current, _, _ = ith.RunCommand("-c")
+	if strings.TrimSpace(current) != "work" {
+		t.Errorf("Expected 'work' to stay current, got %q", current)
+	}
+
+	// A missing context is reported without failing the hook
+	if err := os.WriteFile(file, []byte("missing\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	_, stderr = hookEnv()
+	if !strings.Contains(stderr, "can't switch to context 'missing'") {
+		t.Errorf("Expected a failure notice, got %q", stderr)
+	}
+}
+
+func TestIntegration_InitSnippet(t *testing.T) {
+	ith := NewIntegrationTestHelper(t)
+	defer ith.Cleanup()
+
+	stdout, stderr, err := ith.RunCommand("init", "bash", "--aliases")
+	if err != nil {
+		t.Fatalf("init failed: %v\n%s", err, stderr)
+	}
+	for _, want := range []string{"__start_occtx", "hook-env bash --auto-switch", "occtx_prompt_info()", "PS1=", "alias ocxi="} {
+		if !strings.Contains(stdout, want) {
+			t.Errorf("Expected init output to contain %q", want)
+		}
+	}
+
+	stdout, _, _ = ith.RunCommand("init", "zsh", "--no-completion", "--no-auto-switch", "--no-prompt")
+	if strings.Contains(stdout, "compdef") || strings.Contains(stdout, "--auto-switch") || strings.Contains(stdout, "PROMPT=") {
+		t.Errorf("Expected the left-out parts to be missing:\n%s", stdout)
+	}
+	if !strings.Contains(stdout, "hook-env zsh") || !strings.Contains(stdout, "occtx_prompt_info()") {
+		t.Errorf("Expected the env hook and prompt function:\n%s", stdout)
+	}
+
+	if _, _, err := ith.RunCommand("init", "powershell"); err == nil {
+		t.Error("Expected error for an unsupported shell")
+	}
+
+	bash, err := exec.LookPath("bash")
+	if err != nil {
+		return
+	}
+	stdout, _, _ = ith.RunCommand("init", "bash")
+	check := exec.Command(bash, "-n")
+	check.Stdin = strings.NewReader(stdout)
+	if out, err := check.CombinedOutput(); err != nil {
+		t.Errorf("init bash output doesn't parse: %v\n%s", err, out)
+	}
+}
@@ -84,11 +84,54 @@ func (ith *IntegrationTestHelper) CreateSampleConfig() {
 	}
 }
 
+// isolatedEnvVars are replaced or dropped so the binary only sees the temp home
+var isolatedEnvVars = []string{
+	"HOME", "USERPROFILE", "APPDATA", "LOCALAPPDATA", "XDG_CONFIG_HOME",
+	"OCCTX_PROFILE", "OCCTX_READONLY", "OCCTX_STRICT", "OCCTX_ACTIVE_CONTEXT",
+	"OCCTX_CI", "CI",
+}
+
+// Env returns the environment for running the binary against the temp home
+// directory, plus extra "KEY=value" entries. On Windows the home directory
+// comes from USERPROFILE rather than HOME.
+func (ith *IntegrationTestHelper) Env(extra ...string) []string {
+	var env []string
+	for _, entry := range os.Environ() {
+		key, _, _ := strings.Cut(entry, "=")
+		isolated := false
+		for _, name := range isolatedEnvVars {
+			if strings.EqualFold(key, name) {
+				isolated = true
+				break
+			}
+		}
+		if !isolated {
+			env = append(env, entry)
+		}
+	}
+
+	env = append(env, "HOME="+ith.TempDir)
+	if runtime.GOOS == "windows" {
+		env = append(env,
+			"USERPROFILE="+ith.TempDir,
+			"APPDATA="+filepath.Join(ith.TempDir, "AppData", "Roaming"),
+			"LOCALAPPDATA="+filepath.Join(ith.TempDir, "AppData", "Local"),
+		)
+	}
+
+	return append(env, extra...)
+}
+
 func (ith *IntegrationTestHelper) RunCommand(args ...string) (string, string, error) {
+	return ith.RunCommandWithInput("", args...)
+}
+
+// RunCommandWithInput runs occtx with input fed to its stdin
+func (ith *IntegrationTestHelper) RunCommandWithInput(input string, args ...string) (string, string, error) {
 	cmd := exec.Command(ith.BinaryPath, args...)
+	cmd.Stdin = strings.NewReader(input)
 
-	// Set HOME to temp directory so it uses our test config
-	cmd.Env = append(os.Environ(), "HOME="+ith.TempDir)
+	cmd.Env = ith.Env()
 
 	var stdout, stderr strings.Builder
 	cmd.Stdout = &stdout
@@ -99,11 +142,6 @@ func (ith *IntegrationTestHelper) RunCommand(args ...string) (string, string, er
 }
 
 func TestIntegration_BasicWorkflow(t *testing.T) {
-	// Skip integration tests on Windows due to path and binary execution complexities
-	if runtime.GOOS == "windows" {
-		t.Skip("Integration tests skipped on Windows")
-	}
-
 	ith := NewIntegrationTestHelper(t)
 	defer ith.Cleanup()
 
@@ -156,11 +194,6 @@ func TestIntegration_BasicWorkflow(t *testing.T) {
 }
 
 func TestIntegration_FormatSupport(t *testing.T) {
-	// Skip integration tests on Windows due to path and binary execution complexities
-	if runtime.GOOS == "windows" {
-		t.Skip("Integration tests skipped on Windows")
-	}
-
 	ith := NewIntegrationTestHelper(t)
 	defer ith.Cleanup()
 
@@ -210,11 +243,6 @@ func TestIntegration_FormatSupport(t *testing.T) {
 }
 
 func TestIntegration_ContextManagement(t *testing.T) {
-	// Skip integration tests on Windows due to path and binary execution complexities
-	if runtime.GOOS == "windows" {
-		t.Skip("Integration tests skipped on Windows")
-	}
-
 	ith := NewIntegrationTestHelper(t)
 	defer ith.Cleanup()
 
@@ -322,11 +350,6 @@ func TestIntegration_ContextManagement(t *testing.T) {
 }
 
 func TestIntegration_ShowAndExport(t *testing.T) {
-	// Skip integration tests on Windows due to path and binary execution complexities
-	if runtime.GOOS == "windows" {
-		t.Skip("Integration tests skipped on Windows")
-	}
-
 	ith := NewIntegrationTestHelper(t)
 	defer ith.Cleanup()
 
@@ -367,11 +390,6 @@ func TestIntegration_ShowAndExport(t *testing.T) {
 }
 
 func TestIntegration_ErrorHandling(t *testing.T) {
-	// Skip integration tests on Windows due to path and binary execution complexities
-	if runtime.GOOS == "windows" {
-		t.Skip("Integration tests skipped on Windows")
-	}
-
 	ith := NewIntegrationTestHelper(t)
 	defer ith.Cleanup()
 
@@ -406,11 +424,6 @@ func TestIntegration_ErrorHandling(t *testing.T) {
 }
 
 func TestIntegration_StateManagement(t *testing.T) {
-	// Skip integration tests on Windows due to path and binary execution complexities
-	if runtime.GOOS == "windows" {
-		t.Skip("Integration tests skipped on Windows")
-	}
-
 	ith := NewIntegrationTestHelper(t)
 	defer ith.Cleanup()
 
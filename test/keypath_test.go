@@ -0,0 +1,115 @@
+package test
+
+import (
+	"encoding/json"
+	"os/exec"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hungthai1401/occtx/pkg/keypath"
+)
+
+func TestKeyPath_ParsePointer(t *testing.T) {
+	path, err := keypath.Parse("/provider/a~1b/options~0x")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (keypath.Path{"provider", "a/b", "options~x"}); !reflect.DeepEqual(path, want) {
+		t.Errorf("Parse gave %q, want %q", path, want)
+	}
+	if pointer := path.Pointer(); pointer != "/provider/a~1b/options~0x" {
+		t.Errorf("Pointer gave %q", pointer)
+	}
+
+	for _, invalid := range []string{"/", "/a~2b"} {
+		if _, err := keypath.Parse(invalid); err == nil {
+			t.Errorf("Parse(%q) should fail", invalid)
+		}
+	}
+}
+
+func TestKeyPath_SelectGetSet(t *testing.T) {
+	var data map[string]interface{}
+	if err := json.Unmarshal([]byte(exportSample), &data); err != nil {
+		t.Fatal(err)
+	}
+
+	path, _ := keypath.Parse("provider.*.options.apiKey")
+	matches := path.Select(data)
+	if len(matches) != 2 || matches[0].Path.String() != "provider.anthropic.options.apiKey" {
+		t.Errorf("Unexpected matches: %v", matches)
+	}
+
+	path, _ = keypath.Parse("mcp.0.name")
+	if value, ok := path.Get(data); !ok || value != "search" {
+		t.Errorf("Get(mcp.0.name) = %v, %v", value, ok)
+	}
+	path, _ = keypath.Parse("mcp.1.name")
+	if _, ok := path.Get(data); ok {
+		t.Error("Expected no value past the end of an array")
+	}
+
+	path, _ = keypath.Parse("agent.build.model")
+	if err := path.Set(data, "openai/gpt-4.1"); err != nil {
+		t.Fatal(err)
+	}
+	if value, ok := path.Get(data); !ok || value != "openai/gpt-4.1" {
+		t.Errorf("Expected the set value with missing objects created, got %v, %v", value, ok)
+	}
+
+	for _, expr := range []string{"provider.*.options", "model.nested", "mcp.3.name"} {
+		path, _ := keypath.Parse(expr)
+		if err := path.Set(data, true); err == nil {
+			t.Errorf("Set(%s) should fail", expr)
+		}
+	}
+}
+
+func TestIntegration_PlumbingGet(t *testing.T) {
+	ith := NewIntegrationTestHelper(t)
+	defer ith.Cleanup()
+
+	ith.CreateSampleConfig()
+	if _, stderr, err := ith.RunCommand("-n", "work"); err != nil {
+		t.Fatalf("-n failed: %v\n%s", err, stderr)
+	}
+	if _, stderr, err := ith.RunCommand("work"); err != nil {
+		t.Fatalf("switch failed: %v\n%s", err, stderr)
+	}
+
+	out, stderr, err := ith.RunCommand("__get", "current", "provider.anthropic.api")
+	if err != nil || strings.TrimSpace(out) != `"https://api.anthropic.com"` {
+		t.Errorf("Unexpected __get output: %q, %v\n%s", out, err, stderr)
+	}
+	out, _, err = ith.RunCommand("__get", "work", "/agent/default/model", "--raw")
+	if err != nil || strings.TrimSpace(out) != "claude-4-sonnet" {
+		t.Errorf("Unexpected __get --raw output: %q, %v", out, err)
+	}
+	if _, _, err := ith.RunCommand("__get", "work", "provider.missing"); err == nil {
+		t.Error("Expected __get of a missing value to fail")
+	}
+
+	out, _, err = ith.RunCommand("__select", "current", "provider.*.options.timeout")
+	if err != nil {
+		t.Fatalf("__select failed: %v", err)
+	}
+	var selected []struct {
+		Path    string      `json:"path"`
+		Pointer string      `json:"pointer"`
+		Value   interface{} `json:"value"`
+	}
+	if err := json.Unmarshal([]byte(out), &selected); err != nil {
+		t.Fatalf("Invalid __select output: %v\n%s", err, out)
+	}
+	if len(selected) != 1 || selected[0].Pointer != "/provider/anthropic/options/timeout" || selected[0].Value != 30000.0 {
+		t.Errorf("Unexpected __select output: %+v", selected)
+	}
+
+	// Plumbing is left out of help
+	cmd := exec.Command(ith.BinaryPath, "--help")
+	cmd.Env = ith.Env()
+	if help, _ := cmd.CombinedOutput(); strings.Contains(string(help), "__get") {
+		t.Error("Expected __get to be hidden from help")
+	}
+}
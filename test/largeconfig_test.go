@@ -0,0 +1,127 @@
+package test
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// largeContext returns a context file of at least size bytes, shaped like a
+// config with many providers and models
+func largeContext(size int, comments bool) []byte {
+	var buf bytes.Buffer
+	buf.WriteString("{\n")
+	if comments {
+		buf.WriteString("  // Generated for the large config tests\n")
+	}
+	buf.WriteString("  \"$schema\": \"https://opencode.ai/config.json\",\n  \"provider\": {\n")
+	for i := 0; buf.Len() < size; i++ {
+		if i > 0 {
+			buf.WriteString(",\n")
+		}
+		if comments && i%100 == 0 {
+			buf.WriteString("    // Provider batch\n")
+		}
+		fmt.Fprintf(&buf, "    \"provider-%d\": {\"options\": {\"baseURL\": \"https://p%d.example.com/v1\"}, \"models\": {", i, i)
+		for j := 0; j < 10; j++ {
+			if j > 0 {
+				buf.WriteString(", ")
+			}
+			fmt.Fprintf(&buf, "\"model-%d\": {\"name\": \"Model %d of provider %d\", \"limit\": {\"context\": 200000, \"output\": 8192}}", j, j, i)
+		}
+		buf.WriteString("}}")
+	}
+	buf.WriteString("\n  }\n}\n")
+	return buf.Bytes()
+}
+
+func TestSwitchLargeContext_WritesStoredBytes(t *testing.T) {
+	th := NewTestHelper(t)
+	defer th.Cleanup()
+	th.CreateSampleConfig()
+
+	data := largeContext(6<<20, false)
+	if err := os.WriteFile(filepath.Join(th.SettingsDir, "big.json"), data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	manager := th.CreateManagerWithTempDir()
+	manager.SetForce(true)
+
+	var before, after runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&before)
+	if err := manager.SwitchToContext("big"); err != nil {
+		t.Fatalf("Failed to switch to a large context: %v", err)
+	}
+	runtime.ReadMemStats(&after)
+
+	written, err := os.ReadFile(filepath.Join(th.ConfigDir, "opencode.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(written, data) {
+		t.Error("Expected the large context to be written as stored")
+	}
+
+	// Reading the file and checksumming it is fine; decoding it into a map
+	// allocates many times its size
+	if allocated := after.TotalAlloc - before.TotalAlloc; allocated > uint64(4*len(data)) {
+		t.Errorf("Switching a %d byte context allocated %d bytes; it shouldn't be decoded", len(data), allocated)
+	}
+}
+
+func TestSwitchLargeContext_JSONCKeepsComments(t *testing.T) {
+	th := NewTestHelper(t)
+	defer th.Cleanup()
+	th.CreateSampleConfig()
+
+	data := largeContext(1<<20, true)
+	if err := os.WriteFile(filepath.Join(th.SettingsDir, "big.jsonc"), data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	manager := th.CreateManagerWithTempDir()
+	manager.SetForce(true)
+
+	if err := manager.SwitchToContext("big"); err != nil {
+		t.Fatalf("Failed to switch to a large JSONC context: %v", err)
+	}
+	written, err := os.ReadFile(filepath.Join(th.ConfigDir, "opencode.jsonc"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(written, data) {
+		t.Error("Expected the JSONC context to be written as stored, comments included")
+	}
+}
+
+func TestSwitchContext_InvalidJSONStillRejected(t *testing.T) {
+	cases := map[string]string{
+		"truncated.json": `{"provider": {"a": `,
+		"array.json":     `[1, 2, 3]`,
+		"trailing.jsonc": "{\n  // comment\n  \"model\": \"x\",\n}\n",
+	}
+
+	for file, content := range cases {
+		t.Run(file, func(t *testing.T) {
+			th := NewTestHelper(t)
+			defer th.Cleanup()
+			th.CreateSampleConfig()
+
+			if err := os.WriteFile(filepath.Join(th.SettingsDir, file), []byte(content), 0644); err != nil {
+				t.Fatal(err)
+			}
+			manager := th.CreateManagerWithTempDir()
+			manager.SetForce(true)
+
+			name := strings.TrimSuffix(strings.TrimSuffix(file, ".jsonc"), ".json")
+			err := manager.SwitchToContext(name)
+			if err == nil || !strings.Contains(err.Error(), "invalid JSON in context") {
+				t.Errorf("Expected an invalid JSON error, got %v", err)
+			}
+		})
+	}
+}
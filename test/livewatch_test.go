@@ -0,0 +1,67 @@
+package test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/hungthai1401/occtx/internal/config"
+)
+
+func TestManager_WatchContexts(t *testing.T) {
+	th := NewTestHelper(t)
+	defer th.Cleanup()
+
+	th.CreateSampleConfig()
+	manager := th.CreateManagerWithTempDir()
+	if err := manager.CreateContext("work"); err != nil {
+		t.Fatal(err)
+	}
+
+	watcher := manager.WatchContexts(10 * time.Millisecond)
+	defer watcher.Stop()
+
+	// Hidden files such as the state file are not contexts
+	if err := manager.SwitchToContext("work"); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-watcher.Changes():
+		t.Fatal("Switching should not be reported as a context change")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	// Another process adds a context
+	if err := os.WriteFile(filepath.Join(th.SettingsDir, "pulled.json"), []byte(`{}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-watcher.Changes():
+	case <-time.After(2 * time.Second):
+		t.Fatal("Expected a change after a context was added")
+	}
+
+	// ...and removes one
+	if err := os.Remove(filepath.Join(th.SettingsDir, "work.json")); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-watcher.Changes():
+	case <-time.After(2 * time.Second):
+		t.Fatal("Expected a change after a context was removed")
+	}
+}
+
+func TestInteractiveConfig_LiveRefresh(t *testing.T) {
+	var cfg config.InteractiveConfig
+	if !cfg.LiveRefreshEnabled() {
+		t.Error("Live refresh should default to on")
+	}
+
+	off := false
+	cfg.LiveRefresh = &off
+	if cfg.LiveRefreshEnabled() {
+		t.Error("live_refresh: false should turn it off")
+	}
+}
@@ -0,0 +1,89 @@
+package test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hungthai1401/occtx/internal/context"
+)
+
+func TestManager_LocalOverride(t *testing.T) {
+	th := NewTestHelper(t)
+	defer th.Cleanup()
+
+	settingsDir := filepath.Join(th.ConfigDir, "settings")
+	if err := os.MkdirAll(settingsDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	writeFile := func(name, content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(settingsDir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeFile("team.json", `{"theme": "dark", "provider": {"ollama": {"options": {"baseURL": "http://localhost:11434/v1", "timeout": 30}}}}`)
+	writeFile("team.local.jsonc", `{
+  // This laptop goes through a proxy
+  "provider": {"ollama": {"options": {"baseURL": "http://proxy:8080/v1"}}}
+}`)
+
+	manager := th.CreateManagerWithTempDir()
+	manager.SetForce(true)
+
+	// Overrides are not contexts and their names are reserved
+	contexts, err := manager.ListContexts()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(contexts) != 1 || contexts[0].Name != "team" {
+		t.Errorf("Expected only 'team' to be listed, got %v", contexts)
+	}
+	if err := manager.CreateContext("work.local"); err == nil || !strings.Contains(err.Error(), "reserved") {
+		t.Errorf("Expected '.local' names to be rejected, got %v", err)
+	}
+
+	// The override is merged when switching
+	if err := manager.SwitchToContext("team"); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(filepath.Join(th.ConfigDir, "opencode.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var active map[string]interface{}
+	if err := json.Unmarshal(data, &active); err != nil {
+		t.Fatal(err)
+	}
+	options := active["provider"].(map[string]interface{})["ollama"].(map[string]interface{})["options"].(map[string]interface{})
+	if options["baseURL"] != "http://proxy:8080/v1" || options["timeout"] != 30.0 {
+		t.Errorf("Expected the override merged over the context, got %v", options)
+	}
+
+	// Exports leave it out unless asked
+	exported, err := manager.ExportContext("team", context.ExportOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(exported), "proxy") {
+		t.Errorf("Expected the override to be left out of the export, got %s", exported)
+	}
+	exported, err = manager.ExportContext("team", context.ExportOptions{IncludeLocal: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(exported), "proxy") {
+		t.Errorf("Expected the override in the export, got %s", exported)
+	}
+
+	// It follows renames
+	if err := manager.RenameContext("team", "shared-team"); err != nil {
+		t.Fatal(err)
+	}
+	path, ok := manager.LocalOverridePath("shared-team")
+	if !ok || filepath.Base(path) != "shared-team.local.jsonc" {
+		t.Errorf("Expected the override to be renamed, got %s (exists: %v)", path, ok)
+	}
+}
@@ -0,0 +1,110 @@
+package test
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hungthai1401/occtx/internal/config"
+	"github.com/hungthai1401/occtx/internal/context"
+)
+
+func TestMergeData(t *testing.T) {
+	base := `{"theme": "dark", "plugins": ["a", "b"], "agent": {"build": {"model": "x", "temperature": 0.2}}, "share": "auto"}`
+	overlay := `{"plugins": ["b", "c"], "agent": {"build": {"model": "y"}}, "share": null, "theme": "__delete__", "layout": {"mode": "__delete__", "width": 80}}`
+
+	tests := []struct {
+		name   string
+		config config.MergeConfig
+		want   string
+	}{
+		{"defaults", config.MergeConfig{},
+			`{"theme": "__delete__", "plugins": ["b", "c"], "agent": {"build": {"model": "y", "temperature": 0.2}}, "share": null, "layout": {"mode": "__delete__", "width": 80}}`},
+		{"append and delete nulls", config.MergeConfig{Arrays: "append", Nulls: "delete"},
+			`{"theme": "__delete__", "plugins": ["a", "b", "b", "c"], "agent": {"build": {"model": "y", "temperature": 0.2}}, "layout": {"mode": "__delete__", "width": 80}}`},
+		{"union, ignore nulls and sentinel", config.MergeConfig{Arrays: "union", Nulls: "ignore", DeleteSentinel: "__delete__"},
+			`{"plugins": ["a", "b", "c"], "agent": {"build": {"model": "y", "temperature": 0.2}}, "share": "auto", "layout": {"width": 80}}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts, err := context.ParseMergeOptions(tt.config)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var baseData, overlayData, want map[string]interface{}
+			for _, doc := range []struct {
+				src    string
+				target *map[string]interface{}
+			}{{base, &baseData}, {overlay, &overlayData}, {tt.want, &want}} {
+				if err := json.Unmarshal([]byte(doc.src), doc.target); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			got := context.MergeData(baseData, overlayData, opts)
+			if !reflect.DeepEqual(got, want) {
+				gotJSON, _ := json.Marshal(got)
+				t.Errorf("MergeData() = %s", gotJSON)
+			}
+			if _, ok := baseData["theme"]; !ok {
+				t.Error("MergeData must not modify its inputs")
+			}
+		})
+	}
+
+	if _, err := context.ParseMergeOptions(config.MergeConfig{Arrays: "zip"}); err == nil {
+		t.Error("Expected an invalid array strategy to be rejected")
+	}
+	if _, err := context.ParseMergeOptions(config.MergeConfig{Nulls: "drop"}); err == nil {
+		t.Error("Expected an invalid null strategy to be rejected")
+	}
+}
+
+func TestIntegration_ImportMerge(t *testing.T) {
+	ith := NewIntegrationTestHelper(t)
+	defer ith.Cleanup()
+
+	if _, _, err := ith.RunCommandWithInput(`{"theme": "dark", "plugins": ["a"], "share": "auto"}`, "--import", "work"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Without --merge an existing context is left alone
+	if _, _, err := ith.RunCommandWithInput(`{"plugins": ["b"]}`, "--import", "work"); err == nil {
+		t.Error("Expected import over an existing context to fail without --merge")
+	}
+
+	writeOcctxConfig(t, ith.ConfigDir, `{"merge": {"arrays": "union", "delete_sentinel": "__delete__"}}`)
+	_, stderr, err := ith.RunCommandWithInput(`{"plugins": ["a", "b"], "share": "__delete__"}`, "--import", "work", "--merge")
+	if err != nil {
+		t.Fatalf("merge import failed: %v\n%s", err, stderr)
+	}
+	stdout, _, _ := ith.RunCommand("--export", "work", "--minify")
+	if strings.TrimSpace(stdout) != `{"plugins":["a","b"],"theme":"dark"}` {
+		t.Errorf("Unexpected merged context %s", stdout)
+	}
+
+	// Flags override the config per operation
+	if _, stderr, err := ith.RunCommandWithInput(`{"plugins": ["c"]}`, "--import", "work", "--merge", "--merge-arrays", "replace"); err != nil {
+		t.Fatalf("merge import failed: %v\n%s", err, stderr)
+	}
+	stdout, _, _ = ith.RunCommand("--export", "work", "--minify")
+	if strings.TrimSpace(stdout) != `{"plugins":["c"],"theme":"dark"}` {
+		t.Errorf("Unexpected merged context %s", stdout)
+	}
+
+	if _, _, err := ith.RunCommandWithInput(`{}`, "--import", "work", "--merge", "--merge-nulls", "drop"); err == nil {
+		t.Error("Expected an invalid --merge-nulls to fail")
+	}
+
+	// Merging into a missing context creates it
+	if _, _, err := ith.RunCommandWithInput(`{"theme": "light", "share": "__delete__"}`, "--import", "fresh", "--merge"); err != nil {
+		t.Fatal(err)
+	}
+	stdout, _, _ = ith.RunCommand("--export", "fresh", "--minify")
+	if strings.TrimSpace(stdout) != `{"theme":"light"}` {
+		t.Errorf("Unexpected new context %s", stdout)
+	}
+}
@@ -0,0 +1,104 @@
+package test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hungthai1401/occtx/internal/context"
+)
+
+func TestManager_ContextProvenance(t *testing.T) {
+	th := NewTestHelper(t)
+	defer th.Cleanup()
+
+	th.CreateSampleConfig()
+	manager := th.CreateManagerWithTempDir()
+
+	if err := manager.CreateContext("work"); err != nil {
+		t.Fatal(err)
+	}
+	source := context.Source{Kind: context.SourceImport, From: "https://example.com/team.json"}
+	if err := manager.ImportContextWithSource("team", []byte(`{}`), source); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := manager.CopyContext("work", "work-copy"); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		expected context.Source
+	}{
+		{"work", context.Source{Kind: context.SourceActive}},
+		{"team", source},
+		{"work-copy", context.Source{Kind: context.SourceCopy, From: "work"}},
+	}
+
+	for _, tt := range tests {
+		meta, err := manager.GetContextMeta(tt.name)
+		if err != nil {
+			t.Fatalf("GetContextMeta(%q) failed: %v", tt.name, err)
+		}
+		if meta == nil || meta.Source != tt.expected {
+			t.Errorf("GetContextMeta(%q) = %+v, want source %+v", tt.name, meta, tt.expected)
+		}
+	}
+
+	// Metadata follows renames and is dropped on delete
+	if err := manager.RenameContext("team", "shared-team"); err != nil {
+		t.Fatal(err)
+	}
+	if meta, _ := manager.GetContextMeta("shared-team"); meta == nil || meta.Source != source {
+		t.Errorf("Expected metadata to follow rename, got %+v", meta)
+	}
+	if meta, _ := manager.GetContextMeta("team"); meta != nil {
+		t.Error("Old name should have no metadata after rename")
+	}
+
+	if err := manager.DeleteContext("work-copy"); err != nil {
+		t.Fatal(err)
+	}
+	if meta, _ := manager.GetContextMeta("work-copy"); meta != nil {
+		t.Error("Deleted context should have no metadata")
+	}
+
+	// The metadata file is hidden from listings
+	if _, err := os.Stat(filepath.Join(th.SettingsDir, ".occtx-meta.json")); err != nil {
+		t.Fatal("Expected metadata file in settings dir")
+	}
+	contexts, _ := manager.ListContexts()
+	for _, ctx := range contexts {
+		if strings.HasPrefix(ctx.Name, ".") {
+			t.Errorf("Hidden file listed as context: %s", ctx.Name)
+		}
+	}
+}
+
+func TestIntegration_ShowMeta(t *testing.T) {
+	ith := NewIntegrationTestHelper(t)
+	defer ith.Cleanup()
+
+	ith.CreateSampleConfig()
+
+	if _, _, err := ith.RunCommand("-n", "work"); err != nil {
+		t.Fatal(err)
+	}
+
+	stdout, _, err := ith.RunCommand("-s", "work", "--meta")
+	if err != nil {
+		t.Fatalf("show --meta failed: %v", err)
+	}
+	if !strings.Contains(stdout, "Source:  created from active config") {
+		t.Errorf("Expected source in metadata output, got: %s", stdout)
+	}
+
+	stdout, _, err = ith.RunCommand("-v")
+	if err != nil {
+		t.Fatalf("verbose list failed: %v", err)
+	}
+	if !strings.Contains(stdout, "created from active config") {
+		t.Errorf("Expected source in verbose listing, got: %s", stdout)
+	}
+}
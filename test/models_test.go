@@ -0,0 +1,162 @@
+package test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/hungthai1401/occtx/internal/context"
+)
+
+// sampleModelCatalog is a trimmed models.dev document
+const sampleModelCatalog = `{
+  "anthropic": {"id": "anthropic", "models": {"claude-sonnet-4": {}, "claude-opus-4": {}}},
+  "openai": {"id": "openai", "models": {"gpt-4.1": {}}}
+}`
+
+// serveModelCatalog starts a catalog server and counts its requests
+func serveModelCatalog(t *testing.T) (*httptest.Server, *int32) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.Write([]byte(sampleModelCatalog))
+	}))
+	t.Cleanup(server.Close)
+	return server, &requests
+}
+
+func TestModelCatalog_CheckModels(t *testing.T) {
+	catalog := &context.ModelCatalog{Providers: map[string][]string{
+		"anthropic": {"claude-opus-4", "claude-sonnet-4"},
+		"openai":    {"gpt-4.1"},
+	}}
+
+	findings := catalog.CheckModels(map[string]interface{}{
+		"model":       "anthropic/claude-sonet-4",
+		"small_model": "openai/gpt-4.1",
+		"agent": map[string]interface{}{
+			"review": map[string]interface{}{"model": "antropic/claude-opus-4"},
+			"local":  map[string]interface{}{"model": "ollama/llama3"},
+			"custom": map[string]interface{}{"model": "anthropic/my-finetune"},
+		},
+		"provider": map[string]interface{}{
+			"anthropic": map[string]interface{}{"models": map[string]interface{}{"my-finetune": map[string]interface{}{}}},
+		},
+	})
+
+	joined := strings.Join(findings, "\n")
+	if len(findings) != 2 {
+		t.Fatalf("Expected 2 findings, got:\n%s", joined)
+	}
+	if !strings.Contains(joined, "agent.review.model: unknown provider 'antropic'; did you mean 'anthropic'?") {
+		t.Errorf("Expected a provider typo, got:\n%s", joined)
+	}
+	if !strings.Contains(joined, "model: unknown model 'claude-sonet-4' for provider 'anthropic'; did you mean 'claude-sonnet-4'?") {
+		t.Errorf("Expected a model typo, got:\n%s", joined)
+	}
+
+	if findings := catalog.CheckModels(map[string]interface{}{"model": "gpt-4.1"}); len(findings) != 1 {
+		t.Errorf("Expected a missing provider prefix to be reported, got %v", findings)
+	}
+}
+
+func TestManager_ModelCatalogUpdateAndSwitch(t *testing.T) {
+	th := NewTestHelper(t)
+	defer th.Cleanup()
+
+	server, requests := serveModelCatalog(t)
+	th.CreateSampleConfig()
+	writeOcctxConfig(t, th.ConfigDir, `{"models": {"validate": "warn", "url": "`+server.URL+`"}}`)
+	manager := th.CreateManagerWithTempDir()
+	manager.SetForce(true)
+
+	if catalog, err := manager.LoadModelCatalog(); err != nil || catalog != nil {
+		t.Fatalf("Expected no catalog yet, got %v (%v)", catalog, err)
+	}
+	if err := manager.ImportContext("typo", []byte(`{"model": "anthropic/claude-sonet-4"}`)); err != nil {
+		t.Fatal(err)
+	}
+	// Without a catalog there is nothing to validate against
+	if err := manager.SwitchToContext("typo"); err != nil {
+		t.Fatalf("Switch without a catalog failed: %v", err)
+	}
+
+	catalog, err := manager.UpdateModelCatalog(false)
+	if err != nil {
+		t.Fatalf("UpdateModelCatalog failed: %v", err)
+	}
+	if len(catalog.Providers["anthropic"]) != 2 || catalog.Source != server.URL {
+		t.Errorf("Unexpected catalog %+v", catalog)
+	}
+
+	// Rate-limited unless forced
+	var tooSoon *context.RefreshTooSoonError
+	if _, err := manager.UpdateModelCatalog(false); !errors.As(err, &tooSoon) {
+		t.Errorf("Expected RefreshTooSoonError, got %v", err)
+	}
+	if _, err := manager.UpdateModelCatalog(true); err != nil {
+		t.Errorf("Forced update failed: %v", err)
+	}
+	if got := atomic.LoadInt32(requests); got != 2 {
+		t.Errorf("Expected 2 downloads, got %d", got)
+	}
+
+	var warnings []string
+	manager.SetWarningHandler(func(message string) { warnings = append(warnings, message) })
+	if err := manager.SwitchToContext("typo"); err != nil {
+		t.Fatalf("Switch with validate=warn failed: %v", err)
+	}
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "claude-sonet-4") {
+		t.Errorf("Expected a model warning, got %v", warnings)
+	}
+
+	writeOcctxConfig(t, th.ConfigDir, `{"models": {"validate": "reject", "url": "`+server.URL+`"}}`)
+	strict := th.CreateManagerWithTempDir()
+	if err := strict.SwitchToContext("typo"); err == nil || !strings.Contains(err.Error(), "unknown models") {
+		t.Errorf("Expected validate=reject to block the switch, got %v", err)
+	}
+}
+
+func TestIntegration_Models(t *testing.T) {
+	ith := NewIntegrationTestHelper(t)
+	defer ith.Cleanup()
+
+	server, _ := serveModelCatalog(t)
+	ith.CreateSampleConfig()
+	writeOcctxConfig(t, ith.ConfigDir, `{"models": {"url": "`+server.URL+`"}}`)
+	if _, _, err := ith.RunCommandWithInput(`{"model": "openai/gpt-4.1"}`, "--import", "good"); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := ith.RunCommandWithInput(`{"model": "anthropic/claude-sonet-4"}`, "--import", "typo"); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, _, err := ith.RunCommand("models", "check"); err == nil {
+		t.Error("check without a catalog should fail")
+	}
+	if _, stderr, err := ith.RunCommand("models", "update"); err != nil {
+		t.Fatalf("models update failed: %v\n%s", err, stderr)
+	}
+	if _, stderr, err := ith.RunCommand("models", "update"); err == nil || !strings.Contains(stderr, "--force") {
+		t.Errorf("Expected the second update to be rate-limited, got %v\n%s", err, stderr)
+	}
+
+	stdout, _, err := ith.RunCommand("models")
+	if err != nil || !strings.Contains(stdout, "2 providers, 3 models") {
+		t.Errorf("Unexpected catalog summary (%v):\n%s", err, stdout)
+	}
+
+	stdout, _, err = ith.RunCommand("models", "check")
+	if err == nil {
+		t.Error("check should fail when a context has an unknown model")
+	}
+	if !strings.Contains(stdout, "✓ good") || !strings.Contains(stdout, "did you mean 'claude-sonnet-4'?") {
+		t.Errorf("Unexpected check output:\n%s", stdout)
+	}
+	if _, _, err := ith.RunCommand("models", "check", "good"); err != nil {
+		t.Errorf("check of a valid context failed: %v", err)
+	}
+}
@@ -0,0 +1,144 @@
+package test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hungthai1401/occtx/internal/context"
+)
+
+func TestManager_CreateContext_NameValidation(t *testing.T) {
+	th := NewTestHelper(t)
+	defer th.Cleanup()
+
+	th.CreateSampleConfig()
+	manager := th.CreateManagerWithTempDir()
+
+	tests := []struct {
+		name        string
+		expectError bool
+	}{
+		{"work", false},
+		{"project-alpha_2.0", false},
+		{"CON", true},
+		{"nul", true},
+		{"com1.backup", true},
+		{"tab\tname", true},
+		{"what?", true},
+		{"a:b", true},
+		{"trailing.", true},
+		{"trailing ", true},
+		{strings.Repeat("a", 64), false},
+		{strings.Repeat("b", 65), true},
+		{"Work", true}, // differs only by case from "work"
+	}
+
+	for _, tt := range tests {
+		err := manager.CreateContext(tt.name)
+		if tt.expectError && err == nil {
+			t.Errorf("CreateContext(%q) expected error, got nil", tt.name)
+		}
+		if !tt.expectError && err != nil {
+			t.Errorf("CreateContext(%q) unexpected error: %v", tt.name, err)
+		}
+	}
+}
+
+func TestManager_RenameContext_CaseOnly(t *testing.T) {
+	th := NewTestHelper(t)
+	defer th.Cleanup()
+
+	th.CreateSampleConfig()
+	manager := th.CreateManagerWithTempDir()
+
+	manager.CreateContext("dev")
+	manager.CreateContext("prod")
+
+	if err := manager.RenameContext("prod", "DEV"); err == nil {
+		t.Error("Expected error renaming onto a name that differs only by case")
+	}
+}
+
+func TestManager_ConfiguredMaxNameLength(t *testing.T) {
+	th := NewTestHelper(t)
+	defer th.Cleanup()
+
+	th.CreateSampleConfig()
+	configPath := filepath.Join(th.ConfigDir, ".occtx-config.json")
+	if err := os.WriteFile(configPath, []byte(`{"names": {"max_length": 8}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	manager := th.CreateManagerWithTempDir()
+
+	if err := manager.CreateContext("short"); err != nil {
+		t.Errorf("CreateContext failed: %v", err)
+	}
+	if err := manager.CreateContext("much-too-long"); err == nil {
+		t.Error("Expected error for name longer than configured max length")
+	}
+}
+
+func TestSlugify(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"work", "work"},
+		{"My Work Context", "my-work-context"},
+		{"  --Project: Alpha!!", "project-alpha"},
+		{"v2.0_beta", "v2.0_beta"},
+		{"café/menu", "caf-menu"},
+		{".hidden", "hidden"},
+	}
+
+	for _, tt := range tests {
+		if got := context.Slugify(tt.input); got != tt.expected {
+			t.Errorf("Slugify(%q) = %q, want %q", tt.input, got, tt.expected)
+		}
+	}
+}
+
+func TestManager_CaseInsensitiveLookup(t *testing.T) {
+	th := NewTestHelper(t)
+	defer th.Cleanup()
+
+	th.CreateSampleConfig()
+	configPath := filepath.Join(th.ConfigDir, ".occtx-config.json")
+	if err := os.WriteFile(configPath, []byte(`{"names": {"case_insensitive": true}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	manager := th.CreateManagerWithTempDir()
+
+	if err := manager.CreateContext("Dev"); err != nil {
+		t.Fatalf("CreateContext failed: %v", err)
+	}
+
+	ctx, err := manager.GetContext("dev")
+	if err != nil {
+		t.Fatalf("GetContext failed: %v", err)
+	}
+	if ctx.Name != "Dev" {
+		t.Errorf("Expected resolved name 'Dev', got '%s'", ctx.Name)
+	}
+
+	if err := manager.SwitchToContext("DEV"); err != nil {
+		t.Fatalf("SwitchToContext failed: %v", err)
+	}
+	current, _ := manager.GetCurrentContext()
+	if current != "Dev" {
+		t.Errorf("Expected current context 'Dev', got '%s'", current)
+	}
+
+	// Contexts created out-of-band that differ only by case are ambiguous
+	os.WriteFile(filepath.Join(th.SettingsDir, "DEV.json"), []byte(`{}`), 0644)
+	if _, err := manager.GetContext("dev"); err == nil || !strings.Contains(err.Error(), "ambiguous") {
+		t.Errorf("Expected ambiguity error, got %v", err)
+	}
+
+	// Exact matches always win
+	if ctx, err := manager.GetContext("DEV"); err != nil || ctx.Name != "DEV" {
+		t.Errorf("Expected exact match 'DEV', got %v, %v", ctx, err)
+	}
+}
@@ -0,0 +1,79 @@
+package test
+
+import (
+	"errors"
+	"net"
+	"os/exec"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/hungthai1401/occtx/internal/context"
+)
+
+func TestManager_Offline(t *testing.T) {
+	th := NewTestHelper(t)
+	defer th.Cleanup()
+
+	server, requests := serveModelCatalog(t)
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+
+	th.CreateSampleConfig()
+	writeOcctxConfig(t, th.ConfigDir, `{"models": {"url": "`+server.URL+`"}}`)
+	manager := th.CreateManagerWithTempDir()
+	manager.SetForce(true)
+	if err := manager.CreateContext("work"); err != nil {
+		t.Fatal(err)
+	}
+	if err := manager.AddRequirements("work", []string{"tcp:" + listener.Addr().String()}); err != nil {
+		t.Fatal(err)
+	}
+	if err := manager.SwitchToContext("work"); err != nil {
+		t.Fatalf("Expected the listener to be reachable online, got %v", err)
+	}
+
+	manager.SetOffline(true)
+	if _, err := manager.UpdateModelCatalog(true); !errors.Is(err, context.ErrOffline) {
+		t.Errorf("Expected ErrOffline, got %v", err)
+	}
+	if n := atomic.LoadInt32(requests); n != 0 {
+		t.Errorf("Expected no requests in offline mode, got %d", n)
+	}
+
+	// Unchecked requirements count as unmet, reachable or not
+	var requiresErr *context.RequirementsError
+	if err := manager.SwitchToContext("work"); !errors.As(err, &requiresErr) || !strings.Contains(err.Error(), "offline") {
+		t.Errorf("Expected the tcp requirement to fail offline, got %v", err)
+	}
+
+	// Local operations keep working
+	if err := manager.CreateContext("other"); err != nil {
+		t.Errorf("CreateContext failed in offline mode: %v", err)
+	}
+	if err := manager.SwitchToContext("other"); err != nil {
+		t.Errorf("Switch failed in offline mode: %v", err)
+	}
+}
+
+func TestIntegration_OfflineEnv(t *testing.T) {
+	ith := NewIntegrationTestHelper(t)
+	defer ith.Cleanup()
+
+	ith.CreateSampleConfig()
+	writeOcctxConfig(t, ith.ConfigDir, `{"models": {"url": "http://127.0.0.1:1/api.json"}}`)
+
+	cmd := exec.Command(ith.BinaryPath, "models", "update")
+	cmd.Env = ith.Env("OCCTX_OFFLINE=1")
+	output, err := cmd.CombinedOutput()
+	if err == nil || !strings.Contains(string(output), "offline mode") {
+		t.Errorf("Expected models update to fail in offline mode, got %v\n%s", err, output)
+	}
+
+	if _, stderr, err := ith.RunCommand("--offline", "-n", "work"); err != nil {
+		t.Errorf("Creating a context should work offline: %v\n%s", err, stderr)
+	}
+}
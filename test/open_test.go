@@ -0,0 +1,106 @@
+package test
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// installRecorder writes a shell script named name into dir/bin that records
+// its arguments in dir/<name>-args, returning the script and the args file
+func installRecorder(t *testing.T, dir, name string) (string, string) {
+	binDir := filepath.Join(dir, "bin")
+	if err := os.MkdirAll(binDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	argsFile := filepath.Join(dir, name+"-args")
+	script := filepath.Join(binDir, name)
+	content := "#!/bin/sh\nprintf '%s\\n' \"$@\" > '" + argsFile + "'\n"
+	if err := os.WriteFile(script, []byte(content), 0755); err != nil {
+		t.Fatal(err)
+	}
+	return script, argsFile
+}
+
+func readRecordedArgs(t *testing.T, argsFile string) string {
+	data, err := os.ReadFile(argsFile)
+	if err != nil {
+		t.Fatalf("opener was not run: %v", err)
+	}
+	return strings.TrimSpace(string(data))
+}
+
+func TestIntegration_Open(t *testing.T) {
+	// The fake openers are shell scripts
+	if runtime.GOOS == "windows" {
+		t.Skip("fake opener requires a POSIX shell")
+	}
+
+	ith := NewIntegrationTestHelper(t)
+	defer ith.Cleanup()
+
+	if _, _, err := ith.RunCommand("open"); err == nil {
+		t.Error("open should fail before the contexts directory exists")
+	}
+
+	ith.CreateSampleConfig()
+	if _, _, err := ith.RunCommand("-n", "work"); err != nil {
+		t.Fatal(err)
+	}
+
+	openerName := "xdg-open"
+	if runtime.GOOS == "darwin" {
+		openerName = "open"
+	}
+	_, argsFile := installRecorder(t, ith.TempDir, openerName)
+	binDir := filepath.Join(ith.TempDir, "bin")
+
+	if _, stderr, err := runWithPath(ith, binDir, "open"); err != nil {
+		t.Fatalf("open failed: %v\n%s", err, stderr)
+	}
+	if got := readRecordedArgs(t, argsFile); got != ith.SettingsDir {
+		t.Errorf("Expected opener to get %s, got %s", ith.SettingsDir, got)
+	}
+
+	if _, stderr, err := runWithPath(ith, binDir, "open", "work"); err != nil {
+		t.Fatalf("open work failed: %v\n%s", err, stderr)
+	}
+	if got, want := readRecordedArgs(t, argsFile), filepath.Join(ith.SettingsDir, "work.json"); got != want {
+		t.Errorf("Expected opener to get %s, got %s", want, got)
+	}
+
+	// No opener on PATH points at --editor
+	_, stderr, err := runWithPath(ith, filepath.Join(ith.TempDir, "empty"), "open")
+	if err == nil || !strings.Contains(stderr, "--editor") {
+		t.Errorf("Expected a hint about --editor, got err=%v stderr=%s", err, stderr)
+	}
+}
+
+func TestIntegration_OpenEditor(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("fake editor requires a POSIX shell")
+	}
+
+	ith := NewIntegrationTestHelper(t)
+	defer ith.Cleanup()
+
+	ith.CreateSampleConfig()
+	if _, _, err := ith.RunCommand("-n", "work"); err != nil {
+		t.Fatal(err)
+	}
+
+	editor, argsFile := installRecorder(t, ith.TempDir, "editor")
+	cmd := exec.Command(ith.BinaryPath, "open", "--editor", "work")
+	cmd.Env = ith.Env("EDITOR=" + editor)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("open --editor failed: %v\n%s", err, out)
+	}
+
+	if got, want := readRecordedArgs(t, argsFile), filepath.Join(ith.SettingsDir, "work.json"); got != want {
+		t.Errorf("Expected editor to get %s, got %s", want, got)
+	}
+}
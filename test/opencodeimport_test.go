@@ -0,0 +1,175 @@
+package test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hungthai1401/occtx/internal/context"
+)
+
+func writeTestFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func decodeImport(t *testing.T, imported *context.OpencodeImport) map[string]interface{} {
+	t.Helper()
+	var data map[string]interface{}
+	if err := json.Unmarshal(imported.Data, &data); err != nil {
+		t.Fatalf("import is not JSON: %v\n%s", err, imported.Data)
+	}
+	return data
+}
+
+func TestReadOpencodeExport_ConfigDir(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, filepath.Join(dir, "opencode.jsonc"), `{
+  // Team defaults
+  "model": "anthropic/claude-sonnet",
+  "agent": {"plan": {"model": "openai/gpt-4.1"}}
+}`)
+	writeTestFile(t, filepath.Join(dir, "agent", "review.md"), `---
+description: "Reviews code"
+mode: subagent
+temperature: 0.1
+tools:
+  write: false
+  bash: false
+---
+You review code for bugs.
+`)
+	writeTestFile(t, filepath.Join(dir, "agent", "plan.md"), "Ignored: the config defines plan\n")
+	writeTestFile(t, filepath.Join(dir, "command", "test.md"), "---\ndescription: Run tests\n---\nRun the full test suite.\n")
+	writeTestFile(t, filepath.Join(dir, "auth.json"), `{"openai": {"type": "api", "key": "sk-secret"}}`)
+
+	imported, err := context.ReadOpencodeExport(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if imported.Kind != "opencode config directory" {
+		t.Errorf("unexpected kind %q", imported.Kind)
+	}
+	if strings.Contains(string(imported.Data), "sk-secret") {
+		t.Error("credentials from auth.json were imported")
+	}
+
+	data := decodeImport(t, imported)
+	if data["model"] != "anthropic/claude-sonnet" {
+		t.Errorf("expected the config's model, got %v", data["model"])
+	}
+	agents := data["agent"].(map[string]interface{})
+	review, ok := agents["review"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("expected the markdown agent to be inlined, got %v", agents)
+	}
+	if review["prompt"] != "You review code for bugs." || review["mode"] != "subagent" || review["temperature"] != 0.1 {
+		t.Errorf("unexpected agent: %v", review)
+	}
+	if tools, _ := review["tools"].(map[string]interface{}); tools["write"] != false || tools["bash"] != false {
+		t.Errorf("unexpected agent tools: %v", review["tools"])
+	}
+	if plan := agents["plan"].(map[string]interface{}); plan["model"] != "openai/gpt-4.1" || plan["prompt"] != nil {
+		t.Errorf("expected the config's plan agent to win, got %v", plan)
+	}
+	command := data["command"].(map[string]interface{})["test"].(map[string]interface{})
+	if command["template"] != "Run the full test suite." || command["description"] != "Run tests" {
+		t.Errorf("unexpected command: %v", command)
+	}
+
+	notes := strings.Join(imported.Notes, "\n")
+	for _, want := range []string{"config from opencode.jsonc", "kept agent 'plan'", "auth.json"} {
+		if !strings.Contains(notes, want) {
+			t.Errorf("expected a note about %q, got:\n%s", want, notes)
+		}
+	}
+}
+
+func TestReadOpencodeExport_Files(t *testing.T) {
+	dir := t.TempDir()
+	session := filepath.Join(dir, "session.json")
+	writeTestFile(t, session, `{
+  "info": {"id": "ses_1", "title": "Fix the parser"},
+  "messages": [
+    {"info": {"role": "user"}, "parts": [{"type": "text", "text": "hi"}]},
+    {"info": {"role": "assistant", "providerID": "anthropic", "modelID": "claude-sonnet"}},
+    {"info": {"role": "assistant", "providerID": "openai", "modelID": "gpt-4.1"}, "parts": []},
+    {"info": {"role": "user"}}
+  ]
+}`)
+	bundle := filepath.Join(dir, "bundle.json")
+	writeTestFile(t, bundle, `{"version": 1, "config": {"model": "openai/gpt-4.1", "theme": "tokyonight"}}`)
+	config := filepath.Join(dir, "opencode.json")
+	writeTestFile(t, config, `{"model": "anthropic/claude-sonnet", "share": "disabled"}`)
+
+	tests := []struct {
+		path      string
+		wantKind  string
+		wantModel string
+	}{
+		{session, "opencode session export", "openai/gpt-4.1"},
+		{bundle, "opencode settings bundle", "openai/gpt-4.1"},
+		{config, "opencode config file", "anthropic/claude-sonnet"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.wantKind, func(t *testing.T) {
+			imported, err := context.ReadOpencodeExport(tt.path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if imported.Kind != tt.wantKind {
+				t.Errorf("got kind %q, want %q", imported.Kind, tt.wantKind)
+			}
+			if model := decodeImport(t, imported)["model"]; model != tt.wantModel {
+				t.Errorf("got model %v, want %s", model, tt.wantModel)
+			}
+		})
+	}
+
+	imported, _ := context.ReadOpencodeExport(session)
+	if strings.Contains(string(imported.Data), "hi") || !strings.Contains(strings.Join(imported.Notes, "\n"), "message 3 of 4") {
+		t.Errorf("expected only the model of message 3, got %s %v", imported.Data, imported.Notes)
+	}
+
+	noModel := filepath.Join(dir, "empty-session.json")
+	writeTestFile(t, noModel, `{"info": {"id": "ses_2"}, "messages": []}`)
+	if _, err := context.ReadOpencodeExport(noModel); err == nil {
+		t.Error("expected a session without a model to fail")
+	}
+	if _, err := context.ReadOpencodeExport(t.TempDir()); err == nil {
+		t.Error("expected an empty directory to fail")
+	}
+}
+
+func TestIntegration_ImportFromOpencode(t *testing.T) {
+	ith := NewIntegrationTestHelper(t)
+	defer ith.Cleanup()
+
+	ith.CreateSampleConfig()
+	dir := filepath.Join(ith.TempDir, "opencode-export")
+	writeTestFile(t, filepath.Join(dir, "opencode.json"), `{"model": "openai/gpt-4.1"}`)
+	writeTestFile(t, filepath.Join(dir, "command", "deploy.md"), "Deploy to staging.\n")
+
+	out, stderr, err := ith.RunCommand("--import", "team", "--from-opencode", dir)
+	if err != nil {
+		t.Fatalf("import failed: %v\n%s", err, stderr)
+	}
+	if !strings.Contains(out, "Read the opencode config directory") || !strings.Contains(out, "command 'deploy'") {
+		t.Errorf("expected the import notes, got:\n%s", out)
+	}
+
+	out, stderr, err = ith.RunCommand("-s", "team")
+	if err != nil {
+		t.Fatalf("show failed: %v\n%s", err, stderr)
+	}
+	if !strings.Contains(out, "Deploy to staging.") {
+		t.Errorf("expected the command template in the context, got:\n%s", out)
+	}
+}
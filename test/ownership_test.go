@@ -0,0 +1,84 @@
+package test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hungthai1401/occtx/internal/context"
+)
+
+func TestManager_ForeignActiveConfig(t *testing.T) {
+	th := NewTestHelper(t)
+	defer th.Cleanup()
+
+	th.CreateSampleConfig()
+	manager := th.CreateManagerWithTempDir()
+
+	// Saved as a context, the active config is known
+	if err := manager.CreateContext("work"); err != nil {
+		t.Fatal(err)
+	}
+	if err := manager.ImportContext("home", []byte(`{"theme": "light"}`)); err != nil {
+		t.Fatal(err)
+	}
+	if foreign, err := manager.ActiveIsForeign(); err != nil || foreign {
+		t.Fatalf("ActiveIsForeign() = %v, %v; want false for a saved config", foreign, err)
+	}
+	if err := manager.SwitchToContext("home"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Another tool rewrites the active config
+	activePath := filepath.Join(th.ConfigDir, "opencode.json")
+	if err := os.WriteFile(activePath, []byte(`{"theme": "other-tool"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	err := manager.SwitchToContext("work")
+	if !errors.Is(err, context.ErrForeignActiveConfig) {
+		t.Fatalf("Expected ErrForeignActiveConfig, got %v", err)
+	}
+	if data, _ := os.ReadFile(activePath); !strings.Contains(string(data), "other-tool") {
+		t.Error("Refused switch must not touch the active config")
+	}
+
+	manager.SetForce(true)
+	if err := manager.SwitchToContext("work"); err != nil {
+		t.Errorf("Forced switch failed: %v", err)
+	}
+}
+
+func TestIntegration_ForeignActiveConfig(t *testing.T) {
+	ith := NewIntegrationTestHelper(t)
+	defer ith.Cleanup()
+
+	if _, _, err := ith.RunCommandWithInput(`{"theme": "light"}`, "--import", "home"); err != nil {
+		t.Fatal(err)
+	}
+
+	// An active config written by something else
+	activePath := filepath.Join(ith.ConfigDir, "opencode.json")
+	if err := os.WriteFile(activePath, []byte(`{"theme": "other-tool"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	_, stderr, err := ith.RunCommand("home")
+	if err == nil || !strings.Contains(stderr, "--save-as") {
+		t.Errorf("Expected switch to refuse a foreign config, got err=%v stderr=%s", err, stderr)
+	}
+
+	if _, stderr, err := ith.RunCommand("switch", "home", "--save-as", "other-tool"); err != nil {
+		t.Fatalf("switch --save-as failed: %v\n%s", err, stderr)
+	}
+
+	saved, err := os.ReadFile(filepath.Join(ith.SettingsDir, "other-tool.json"))
+	if err != nil || !strings.Contains(string(saved), `"other-tool"`) {
+		t.Errorf("Expected foreign config saved as a context, got %s (%v)", saved, err)
+	}
+	if data, _ := os.ReadFile(activePath); !strings.Contains(string(data), "light") {
+		t.Errorf("Expected switch to proceed after saving, active config is %s", data)
+	}
+}
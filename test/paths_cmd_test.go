@@ -0,0 +1,66 @@
+package test
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestIntegration_Which(t *testing.T) {
+	ith := NewIntegrationTestHelper(t)
+	defer ith.Cleanup()
+
+	ith.CreateSampleConfig()
+	if _, _, err := ith.RunCommand("-n", "work", "--format", "jsonc"); err != nil {
+		t.Fatal(err)
+	}
+
+	stdout, stderr, err := ith.RunCommand("which", "work")
+	if err != nil {
+		t.Fatalf("which failed: %v\n%s", err, stderr)
+	}
+	if got, want := strings.TrimSpace(stdout), filepath.Join(ith.SettingsDir, "work.jsonc"); got != want {
+		t.Errorf("Expected %s, got %s", want, got)
+	}
+
+	if _, _, err := ith.RunCommand("which", "missing"); err == nil {
+		t.Error("which should fail for a missing context")
+	}
+}
+
+func TestIntegration_Path(t *testing.T) {
+	ith := NewIntegrationTestHelper(t)
+	defer ith.Cleanup()
+
+	tests := []struct {
+		flag string
+		want string
+	}{
+		{"--contexts", ith.SettingsDir},
+		{"--active", filepath.Join(ith.ConfigDir, "opencode.json")},
+		{"--state", filepath.Join(ith.SettingsDir, ".occtx-state.json")},
+	}
+	for _, tt := range tests {
+		stdout, stderr, err := ith.RunCommand("path", tt.flag)
+		if err != nil {
+			t.Fatalf("path %s failed: %v\n%s", tt.flag, err, stderr)
+		}
+		if got := strings.TrimSpace(stdout); got != tt.want {
+			t.Errorf("path %s: expected %s, got %s", tt.flag, tt.want, got)
+		}
+	}
+
+	stdout, _, err := ith.RunCommand("path")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"contexts", "active", "state", "config", ith.SettingsDir} {
+		if !strings.Contains(stdout, want) {
+			t.Errorf("Expected %q in path output, got:\n%s", want, stdout)
+		}
+	}
+
+	if _, _, err := ith.RunCommand("path", "--contexts", "--state"); err == nil {
+		t.Error("path should reject more than one selector")
+	}
+}
@@ -250,3 +250,182 @@ func TestPaths_DirectoryPermissions(t *testing.T) {
 		t.Errorf("Expected permissions %v, got %v", expectedPerms, settingsInfo.Mode().Perm())
 	}
 }
+
+func TestOpenCodeGlobalConfigDir(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+
+	// Same location opencode uses on every OS, including Windows
+	t.Setenv("XDG_CONFIG_HOME", "")
+	dir, err := config.OpenCodeGlobalConfigDir()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := filepath.Join(home, ".config", "opencode"); dir != expected {
+		t.Errorf("Expected %s, got %s", expected, dir)
+	}
+
+	xdg := filepath.Join(home, "xdg")
+	t.Setenv("XDG_CONFIG_HOME", xdg)
+	if dir, _ := config.OpenCodeGlobalConfigDir(); dir != filepath.Join(xdg, "opencode") {
+		t.Errorf("Expected XDG_CONFIG_HOME to be honored, got %s", dir)
+	}
+
+	// Relative values are invalid per the XDG spec and ignored
+	t.Setenv("XDG_CONFIG_HOME", "relative")
+	if dir, _ := config.OpenCodeGlobalConfigDir(); dir != filepath.Join(home, ".config", "opencode") {
+		t.Errorf("Expected relative XDG_CONFIG_HOME to be ignored, got %s", dir)
+	}
+}
+
+func TestPaths_ResolveActiveConfigs(t *testing.T) {
+	globalDir := t.TempDir()
+	projectDir := t.TempDir()
+	paths := &config.Paths{GlobalConfigDir: globalDir, ProjectConfigDir: filepath.Join(projectDir, "opencode")}
+
+	write := func(path string) {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("{}"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Nothing exists yet: the defaults
+	if err := paths.ResolveActiveConfigs(config.ActiveConfigConfig{}); err != nil {
+		t.Fatal(err)
+	}
+	if paths.GlobalActiveConfig != filepath.Join(globalDir, "opencode.json") || paths.ProjectActiveConfig != filepath.Join(projectDir, "opencode.json") {
+		t.Errorf("Expected opencode.json defaults, got %s and %s", paths.GlobalActiveConfig, paths.ProjectActiveConfig)
+	}
+
+	// Detected when only an alternative exists
+	write(filepath.Join(globalDir, "opencode.jsonc"))
+	write(filepath.Join(projectDir, ".opencode", "opencode.json"))
+	if err := paths.ResolveActiveConfigs(config.ActiveConfigConfig{}); err != nil {
+		t.Fatal(err)
+	}
+	if paths.GlobalActiveConfig != filepath.Join(globalDir, "opencode.jsonc") {
+		t.Errorf("Expected opencode.jsonc to be detected, got %s", paths.GlobalActiveConfig)
+	}
+	if paths.ProjectActiveConfig != filepath.Join(projectDir, ".opencode", "opencode.json") {
+		t.Errorf("Expected .opencode/opencode.json to be detected, got %s", paths.ProjectActiveConfig)
+	}
+
+	// opencode.json wins when both exist
+	write(filepath.Join(globalDir, "opencode.json"))
+	if err := paths.ResolveActiveConfigs(config.ActiveConfigConfig{}); err != nil {
+		t.Fatal(err)
+	}
+	if paths.GlobalActiveConfig != filepath.Join(globalDir, "opencode.json") {
+		t.Errorf("Expected opencode.json to win, got %s", paths.GlobalActiveConfig)
+	}
+
+	// Configured names are used as given
+	if err := paths.ResolveActiveConfigs(config.ActiveConfigConfig{Global: "opencode.jsonc", Project: ".opencode/opencode.jsonc"}); err != nil {
+		t.Fatal(err)
+	}
+	if paths.GlobalActiveConfig != filepath.Join(globalDir, "opencode.jsonc") || paths.ProjectActiveConfig != filepath.Join(projectDir, ".opencode", "opencode.jsonc") {
+		t.Errorf("Expected the configured files, got %s and %s", paths.GlobalActiveConfig, paths.ProjectActiveConfig)
+	}
+
+	for _, bad := range []config.ActiveConfigConfig{{Global: "../opencode.json"}, {Project: "opencode.yaml"}, {Global: filepath.Join(globalDir, "opencode.json")}} {
+		if err := paths.ResolveActiveConfigs(bad); err == nil {
+			t.Errorf("Expected error for %+v", bad)
+		}
+	}
+}
+
+func TestManager_DetectedJSONCActiveConfig(t *testing.T) {
+	th := NewTestHelper(t)
+	defer th.Cleanup()
+
+	jsoncPath := filepath.Join(th.ConfigDir, "opencode.jsonc")
+	if err := os.WriteFile(jsoncPath, []byte("{\n  // My setup\n  \"theme\": \"dark\"\n}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	manager := th.CreateManagerWithTempDir()
+	manager.SetForce(true)
+
+	if got := manager.GetPaths().GetActiveConfigPath(false); got != jsoncPath {
+		t.Fatalf("Expected the active config to be %s, got %s", jsoncPath, got)
+	}
+	if err := manager.CreateContext("work"); err != nil {
+		t.Fatalf("CreateContext from opencode.jsonc failed: %v", err)
+	}
+	if err := manager.SwitchToContext("work"); err != nil {
+		t.Fatalf("SwitchToContext failed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(th.ConfigDir, "opencode.json")); !os.IsNotExist(err) {
+		t.Errorf("Expected no opencode.json to be written, got %v", err)
+	}
+}
+
+func TestMainWorktreeRoot(t *testing.T) {
+	tempDir := t.TempDir()
+	main := filepath.Join(tempDir, "main")
+	feature := filepath.Join(tempDir, "feature")
+	worktreeGitDir := filepath.Join(main, ".git", "worktrees", "feature")
+	for _, dir := range []string{worktreeGitDir, feature} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(worktreeGitDir, "commondir"), []byte("../..\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(feature, ".git"), []byte("gitdir: ../main/.git/worktrees/feature\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if root, err := config.MainWorktreeRoot(feature); err != nil || root != main {
+		t.Errorf("Expected %s for a linked worktree, got %q, %v", main, root, err)
+	}
+	if root, err := config.MainWorktreeRoot(main); err != nil || root != "" {
+		t.Errorf("Expected nothing for the main worktree, got %q, %v", root, err)
+	}
+	if root, err := config.MainWorktreeRoot(tempDir); err != nil || root != "" {
+		t.Errorf("Expected nothing outside a repository, got %q, %v", root, err)
+	}
+
+	// A submodule's gitdir has no commondir
+	submodule := filepath.Join(main, "sub")
+	if err := os.MkdirAll(filepath.Join(main, ".git", "modules", "sub"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(submodule, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(submodule, ".git"), []byte("gitdir: ../.git/modules/sub\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if root, err := config.MainWorktreeRoot(submodule); err != nil || root != "" {
+		t.Errorf("Expected nothing for a submodule, got %q, %v", root, err)
+	}
+
+	paths := &config.Paths{
+		ProjectConfigDir:    filepath.Join(feature, "opencode"),
+		ProjectSettingsDir:  filepath.Join(feature, "opencode", "settings"),
+		ProjectActiveConfig: filepath.Join(feature, "opencode.json"),
+	}
+	if root, err := paths.UseMainWorktree(); err != nil || root != main {
+		t.Fatalf("Expected the main worktree to be used, got %q, %v", root, err)
+	}
+	if paths.ProjectSettingsDir != filepath.Join(main, "opencode", "settings") || paths.ProjectStateFile != filepath.Join(main, "opencode", "settings", ".occtx-state.json") {
+		t.Errorf("Expected contexts and state in the main worktree, got %s and %s", paths.ProjectSettingsDir, paths.ProjectStateFile)
+	}
+	if paths.ProjectActiveConfig != filepath.Join(feature, "opencode.json") {
+		t.Errorf("Expected the active config to stay in the worktree, got %s", paths.ProjectActiveConfig)
+	}
+
+	// A worktree with contexts of its own keeps them
+	own := &config.Paths{ProjectConfigDir: filepath.Join(feature, "opencode"), ProjectSettingsDir: filepath.Join(feature, "opencode", "settings")}
+	if err := os.MkdirAll(own.ProjectSettingsDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if root, err := own.UseMainWorktree(); err != nil || root != "" {
+		t.Errorf("Expected the worktree's own contexts to be kept, got %q, %v", root, err)
+	}
+}
@@ -0,0 +1,81 @@
+package test
+
+import (
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestManager_PermissionsConfig(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("file modes are not enforced on Windows")
+	}
+
+	th := NewTestHelper(t)
+	defer th.Cleanup()
+
+	th.CreateSampleConfig()
+	writeOcctxConfig(t, th.ConfigDir, `{"permissions": {"files": "0600", "dirs": "0700"}}`)
+	manager := th.CreateManagerWithTempDir()
+
+	if err := manager.CreateContext("work"); err != nil {
+		t.Fatal(err)
+	}
+	if err := manager.SwitchToContext("work"); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"work.json", ".occtx-meta.json", ".occtx-state.json", ".occtx-checksums.json"} {
+		if mode := fileMode(t, filepath.Join(th.SettingsDir, name)); mode != 0600 {
+			t.Errorf("Expected %s to get mode 0600, got %04o", name, mode)
+		}
+	}
+	if _, err := manager.StashSave(""); err != nil {
+		t.Fatal(err)
+	}
+	if mode := fileMode(t, filepath.Join(th.SettingsDir, "stash")); mode != 0700 {
+		t.Errorf("Expected the stash dir to get mode 0700, got %04o", mode)
+	}
+
+	// The settings dir was created by the test helper before the config
+	report, err := manager.CheckPermissions()
+	if err != nil {
+		t.Fatalf("CheckPermissions failed: %v", err)
+	}
+	if len(report.Deviations) != 1 || report.Deviations[0].Path != th.SettingsDir || report.Deviations[0].Want != 0700 {
+		t.Errorf("Expected only the settings dir to deviate, got %+v", report.Deviations)
+	}
+}
+
+func TestIntegration_PermissionsHonorUmask(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Windows has no umask")
+	}
+
+	ith := NewIntegrationTestHelper(t)
+	defer ith.Cleanup()
+
+	ith.CreateSampleConfig()
+	cmd := exec.Command("sh", "-c", `umask 077 && exec "$0" -n work`, ith.BinaryPath)
+	cmd.Env = ith.Env()
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("create failed: %v\n%s", err, out)
+	}
+	if mode := fileMode(t, filepath.Join(ith.SettingsDir, "work.json")); mode != 0600 {
+		t.Errorf("Expected umask 077 to give the context mode 0600, got %04o", mode)
+	}
+
+	stdout, _, err := ith.RunCommand("doctor")
+	if err != nil {
+		t.Fatalf("doctor failed: %v", err)
+	}
+	if !strings.Contains(stdout, "work.json: 0600, expected 0644") {
+		t.Errorf("Expected doctor to report the deviating context, got:\n%s", stdout)
+	}
+
+	writeOcctxConfig(t, ith.ConfigDir, `{"permissions": {"dirs": "0600"}}`)
+	if _, stderr, err := ith.RunCommand("-c"); err == nil || !strings.Contains(stderr, "permissions.dirs") {
+		t.Errorf("Expected an invalid dirs mode to be rejected, got %v:\n%s", err, stderr)
+	}
+}
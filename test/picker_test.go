@@ -0,0 +1,159 @@
+package test
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// installFakeFzf writes an fzf stand-in that logs its arguments and runs script
+func installFakeFzf(t *testing.T, dir, script string) string {
+	binDir := filepath.Join(dir, "bin")
+	if err := os.MkdirAll(binDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	argsFile := filepath.Join(dir, "fzf-args")
+	content := "#!/bin/sh\nprintf '%s\\n' \"$@\" > '" + argsFile + "'\n" + script + "\n"
+	if err := os.WriteFile(filepath.Join(binDir, "fzf"), []byte(content), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	return argsFile
+}
+
+// runWithPath runs the binary with HOME set to the temp dir and the given PATH
+func runWithPath(ith *IntegrationTestHelper, path string, args ...string) (string, string, error) {
+	cmd := exec.Command(ith.BinaryPath, args...)
+	cmd.Env = ith.Env("PATH=" + path)
+
+	var stdout, stderr strings.Builder
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	err := cmd.Run()
+	return stdout.String(), stderr.String(), err
+}
+
+func TestIntegration_FzfPicker(t *testing.T) {
+	// The fake fzf is a shell script
+	if runtime.GOOS == "windows" {
+		t.Skip("fake fzf requires a POSIX shell")
+	}
+
+	ith := NewIntegrationTestHelper(t)
+	defer ith.Cleanup()
+
+	ith.CreateSampleConfig()
+	ith.RunCommand("-n", "work")
+	ith.RunCommand("-n", "personal")
+
+	binDir := filepath.Join(ith.TempDir, "bin")
+	path := binDir + string(os.PathListSeparator) + os.Getenv("PATH")
+
+	// Options from the config file and flags reach fzf
+	writeOcctxConfig(t, ith.ConfigDir, `{"interactive": {"fzf": {"height": "20", "bind": ["ctrl-j:down"]}}}`)
+	argsFile := installFakeFzf(t, ith.TempDir, "echo '  personal'")
+
+	stdout, stderr, err := runWithPath(ith, path, "-i", "--preview", "--fzf-layout", "default")
+	if err != nil {
+		t.Fatalf("Interactive selection failed: %v\n%s", err, stderr)
+	}
+	if !strings.Contains(stdout, "Switched to context: personal") {
+		t.Errorf("Expected switch to personal, got: %s", stdout)
+	}
+
+	args, _ := os.ReadFile(argsFile)
+	for _, expected := range []string{"--height\n20\n", "--layout\ndefault\n", "--bind\nctrl-j:down\n", "--preview\n"} {
+		if !strings.Contains(string(args), expected) {
+			t.Errorf("Expected fzf args to contain %q, got:\n%s", expected, args)
+		}
+	}
+
+	// ESC in fzf is a clean abort, not a fallback or an error
+	installFakeFzf(t, ith.TempDir, "exit 130")
+	_, stderr, err = runWithPath(ith, path, "-i")
+	if err != nil {
+		t.Errorf("Aborting fzf should exit cleanly, got: %v\n%s", err, stderr)
+	}
+	if !strings.Contains(stderr, "Aborted") {
+		t.Errorf("Expected 'Aborted', got: %s", stderr)
+	}
+
+	// Forcing fzf without it installed is an error
+	os.Remove(filepath.Join(binDir, "fzf"))
+	_, stderr, err = runWithPath(ith, binDir, "interactive", "--picker", "fzf")
+	if err == nil || !strings.Contains(stderr, "fzf not found") {
+		t.Errorf("Expected fzf not found error, got: %v %s", err, stderr)
+	}
+}
+
+func TestIntegration_InteractiveMenu(t *testing.T) {
+	// The fake fzf is a shell script
+	if runtime.GOOS == "windows" {
+		t.Skip("fake fzf requires a POSIX shell")
+	}
+
+	ith := NewIntegrationTestHelper(t)
+	defer ith.Cleanup()
+
+	ith.CreateSampleConfig()
+	ith.RunCommand("-n", "work")
+	ith.RunCommand("work")
+
+	binDir := filepath.Join(ith.TempDir, "bin")
+	path := binDir + string(os.PathListSeparator) + os.Getenv("PATH")
+
+	// menu answers the action menu with action and takes the first entry of any other list
+	menu := func(action string) (string, string) {
+		t.Helper()
+		installFakeFzf(t, ith.TempDir, `case "$*" in *"Action: "*) echo '`+action+`' ;; *) head -n 1 ;; esac`)
+		stdout, stderr, err := runWithPath(ith, path, "-i", "--menu")
+		if err != nil {
+			t.Fatalf("%s failed: %v\n%s", action, err, stderr)
+		}
+		return stdout, stderr
+	}
+
+	menu("Unset the current context")
+	if stdout, _, _ := ith.RunCommand("-c"); strings.Contains(stdout, "work") {
+		t.Errorf("Expected no current context, got %q", stdout)
+	}
+
+	if stdout, _ := menu("Switch context"); !strings.Contains(stdout, "Switched to context: work") {
+		t.Errorf("Expected a switch to work, got: %s", stdout)
+	}
+
+	if stdout, _ := menu("Stash the active config"); !strings.Contains(stdout, "Saved active config to stash") {
+		t.Errorf("Expected a stash, got: %s", stdout)
+	}
+	if stdout, _ := menu("Restore a stash"); !strings.Contains(stdout, "Restored and dropped stash") {
+		t.Errorf("Expected the stash restored, got: %s", stdout)
+	}
+	if stdout, _, _ := ith.RunCommand("stash", "list"); !strings.Contains(stdout, "No stashes found") {
+		t.Errorf("Expected the restored stash dropped, got: %s", stdout)
+	}
+
+	if stdout, _ := menu("Restore a backup"); !strings.Contains(stdout, "No backups") {
+		t.Errorf("Expected no backups to restore, got: %s", stdout)
+	}
+	if _, stderr, err := ith.RunCommand("backup", "create"); err != nil {
+		t.Fatalf("backup create failed: %v\n%s", err, stderr)
+	}
+	stdout, stderr := menu("Restore a backup")
+	if !strings.Contains(stdout, "Restored backup") || !strings.Contains(stdout, "Backed up the current state") {
+		t.Errorf("Expected the backup restored, got: %s", stdout)
+	}
+	if !strings.Contains(stderr, "Hint: Undo with 'occtx backup restore") {
+		t.Errorf("Expected an undo hint, got: %s", stderr)
+	}
+
+	// Without --menu, -i still goes straight to the context picker
+	installFakeFzf(t, ith.TempDir, "head -n 1")
+	if stdout, _, err := runWithPath(ith, path, "-i"); err != nil || !strings.Contains(stdout, "Switched to context: work") {
+		t.Errorf("Expected -i to switch directly, got: %v %s", err, stdout)
+	}
+}
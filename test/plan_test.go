@@ -0,0 +1,174 @@
+package test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hungthai1401/occtx/internal/config"
+	"github.com/hungthai1401/occtx/internal/context"
+)
+
+func TestManager_Plans(t *testing.T) {
+	th := NewTestHelper(t)
+	defer th.Cleanup()
+
+	th.CreateSampleConfig()
+	manager := th.CreateManagerWithTempDir()
+	manager.SetForce(true)
+	if err := manager.CreateContext("work"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := manager.ImportContextWithOptions("personal", []byte(`{"theme": "light"}`), context.Source{Kind: context.SourceImport}, context.ImportOptions{Format: context.FormatJSON}); err != nil {
+		t.Fatal(err)
+	}
+	if err := manager.SwitchToContext("work"); err != nil {
+		t.Fatal(err)
+	}
+	activePath := filepath.Join(th.ConfigDir, "opencode.json")
+	before, _ := os.ReadFile(activePath)
+
+	plan, err := manager.PlanSwitch("personal")
+	if err != nil {
+		t.Fatalf("PlanSwitch failed: %v", err)
+	}
+	if len(plan.Files) != 1 || plan.Files[0].Path != activePath || plan.Files[0].Action != context.PlanModify {
+		t.Fatalf("Unexpected plan files %+v", plan.Files)
+	}
+	var themeChange *context.ContextChange
+	for i, change := range plan.Files[0].Changes {
+		if change.Path == "theme" {
+			themeChange = &plan.Files[0].Changes[i]
+		}
+	}
+	if themeChange == nil || themeChange.Baseline != "default" || themeChange.Local != "light" {
+		t.Errorf("Expected a theme change from default to light, got %+v", plan.Files[0].Changes)
+	}
+
+	// Switching to the current context changes nothing
+	if plan, err := manager.PlanSwitch("work"); err != nil || len(plan.Files) != 0 {
+		t.Errorf("Expected an empty plan, got %+v (%v)", plan, err)
+	}
+
+	plan, err = manager.PlanDelete("work", context.FallbackNone)
+	if err != nil {
+		t.Fatalf("PlanDelete failed: %v", err)
+	}
+	if len(plan.Files) != 2 || plan.Files[0].Path != activePath || plan.Files[0].Action != context.PlanDelete {
+		t.Errorf("Unexpected delete plan %+v", plan.Files)
+	}
+	if _, err := manager.PlanDelete("work", ""); err == nil {
+		t.Error("Expected planning to delete the current context without --switch-to to fail")
+	}
+
+	merge := context.MergeOptions{Arrays: context.ArraysReplace, Nulls: context.NullsDelete}
+	plan, err = manager.PlanImport("personal", []byte(`{"theme": null, "model": "a/b"}`), context.ImportOptions{Format: context.FormatJSON, Merge: &merge})
+	if err != nil {
+		t.Fatalf("PlanImport failed: %v", err)
+	}
+	if plan.Operation != "merge" || len(plan.Files) != 1 || len(plan.Files[0].Changes) != 2 {
+		t.Errorf("Unexpected merge plan %+v", plan)
+	}
+	if _, err := manager.PlanImport("personal", []byte(`{}`), context.ImportOptions{Format: context.FormatJSON}); err != nil {
+		t.Errorf("With force, planning to replace a context should succeed: %v", err)
+	}
+
+	// Nothing was written
+	if after, _ := os.ReadFile(activePath); string(after) != string(before) {
+		t.Error("Plans must not write the active config")
+	}
+	if current, _ := manager.GetCurrentContext(); current != "work" {
+		t.Errorf("Plans must not change the current context, got %q", current)
+	}
+	if ctx, _ := manager.GetContext("personal"); ctx.Data["theme"] != "light" {
+		t.Error("Plans must not write contexts")
+	}
+}
+
+func TestIntegration_DryRun(t *testing.T) {
+	ith := NewIntegrationTestHelper(t)
+	defer ith.Cleanup()
+
+	ith.CreateSampleConfig()
+	if _, _, err := ith.RunCommand("-n", "work"); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := ith.RunCommandWithInput(`{"theme": "light"}`, "--import", "personal"); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := ith.RunCommand("work"); err != nil {
+		t.Fatal(err)
+	}
+
+	stdout, stderr, err := ith.RunCommand("personal", "--dry-run", "-o", "json")
+	if err != nil {
+		t.Fatalf("dry run failed: %v\n%s", err, stderr)
+	}
+	var plan context.Plan
+	if err := json.Unmarshal([]byte(stdout), &plan); err != nil {
+		t.Fatalf("Expected a JSON plan, got %s", stdout)
+	}
+	if plan.Operation != "switch" || plan.Context != "personal" || len(plan.Files) != 1 || len(plan.Files[0].Changes) == 0 {
+		t.Errorf("Unexpected plan %+v", plan)
+	}
+	if current, _, _ := ith.RunCommand("-c"); strings.TrimSpace(current) != "work" {
+		t.Errorf("A dry run must not switch, current is %q", current)
+	}
+
+	stdout, _, err = ith.RunCommand("switch", "personal", "--dry-run")
+	if err != nil || !strings.Contains(stdout, "~ theme: \"default\" -> \"light\"") || !strings.Contains(stdout, "nothing was written") {
+		t.Errorf("Unexpected text plan %q (%v)", stdout, err)
+	}
+
+	stdout, _, err = ith.RunCommandWithInput(`{"theme": "dark"}`, "--import", "new", "--dry-run", "-o", "json")
+	if err != nil || !strings.Contains(stdout, `"action": "create"`) {
+		t.Errorf("Unexpected import plan %q (%v)", stdout, err)
+	}
+	if _, err := os.Stat(filepath.Join(ith.SettingsDir, "new.json")); !os.IsNotExist(err) {
+		t.Error("A dry run must not create the context")
+	}
+
+	stdout, _, err = ith.RunCommand("-d", "work", "--switch-to", "personal", "--dry-run")
+	if err != nil || !strings.Contains(stdout, "- delete") || !strings.Contains(stdout, "~ modify") {
+		t.Errorf("Unexpected delete plan %q (%v)", stdout, err)
+	}
+	if _, err := os.Stat(filepath.Join(ith.SettingsDir, "work.json")); err != nil {
+		t.Error("A dry run must not delete the context")
+	}
+
+	// The hooks a switch would run are listed, but not run
+	record := filepath.Join(ith.TempDir, "hooks.log")
+	writeHooksConfig(t, ith.ConfigDir, []config.HookConfig{
+		{Name: "notify", On: config.HookPostSwitch, Run: []string{"touch", record}},
+		{Name: "vpn", On: config.HookPreSwitch, Run: []string{"touch", record}, Timeout: "3s"},
+	})
+	stdout, stderr, err = ith.RunCommand("personal", "--dry-run")
+	if err != nil {
+		t.Fatalf("dry run failed: %v\n%s", err, stderr)
+	}
+	if !strings.Contains(stdout, "run pre-switch hook 'vpn' (timeout 3s): touch "+record) ||
+		!strings.Contains(stdout, "run post-switch hook 'notify' (timeout 10s)") ||
+		strings.Index(stdout, "'vpn'") > strings.Index(stdout, "'notify'") {
+		t.Errorf("Expected the hooks in the text plan, got %q", stdout)
+	}
+	stdout, _, err = ith.RunCommand("personal", "--dry-run", "-o", "json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	plan = context.Plan{}
+	if err := json.Unmarshal([]byte(stdout), &plan); err != nil {
+		t.Fatalf("Expected a JSON plan, got %s", stdout)
+	}
+	if len(plan.Hooks) != 2 || plan.Hooks[0].Name != "vpn" || plan.Hooks[0].Timeout != "3s" || plan.Hooks[1].On != config.HookPostSwitch {
+		t.Errorf("Unexpected planned hooks %+v", plan.Hooks)
+	}
+	if _, err := os.Stat(record); !os.IsNotExist(err) {
+		t.Error("A dry run must not run hooks")
+	}
+
+	if _, _, err := ith.RunCommand("personal", "--dry-run", "-o", "yaml"); err == nil {
+		t.Error("Expected an invalid output format to fail")
+	}
+}
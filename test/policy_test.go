@@ -0,0 +1,170 @@
+package test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hungthai1401/occtx/internal/context"
+)
+
+// writePolicy writes the organization policy file into configDir
+func writePolicy(t *testing.T, configDir, content string) {
+	if err := os.WriteFile(filepath.Join(configDir, ".occtx-policy.json"), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPolicyFile_Check(t *testing.T) {
+	data := map[string]interface{}{
+		"model": "openai/gpt-4.1",
+		"provider": map[string]interface{}{
+			"anthropic": map[string]interface{}{"options": map[string]interface{}{"apiKey": "{env:KEY}", "timeout": 30000.0}},
+			"openai":    map[string]interface{}{"options": map[string]interface{}{"apiKey": "sk-123", "baseURL": "https://proxy"}},
+		},
+		"mcp": map[string]interface{}{"search": map[string]interface{}{"timeout": 900000.0}},
+	}
+
+	tests := []struct {
+		name string
+		rule context.PolicyRule
+		want int
+	}{
+		{"secrets", context.PolicyRule{Type: context.RuleForbidPlaintextSecrets}, 1},
+		{"allowlist", context.PolicyRule{Type: context.RuleProviderAllowlist, Providers: []string{"anthropic"}}, 2},
+		{"allowlist ok", context.PolicyRule{Type: context.RuleProviderAllowlist, Providers: []string{"anthropic", "openai"}}, 0},
+		{"timeout", context.PolicyRule{Type: context.RuleMaxTimeout, Max: 60000}, 1},
+		{"forbidden keys", context.PolicyRule{Type: context.RuleForbiddenKeys, Keys: []string{"provider.*.options.baseURL", "share"}}, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			deny := &context.PolicyFile{Rules: []context.PolicyRule{tt.rule}}
+			warnings, violations := deny.Check(data)
+			if len(warnings) != 0 || len(violations) != tt.want {
+				t.Errorf("deny mode: got warnings %v, violations %v; want %d violations", warnings, violations, tt.want)
+			}
+
+			tt.rule.Mode = "warn"
+			warn := &context.PolicyFile{Rules: []context.PolicyRule{tt.rule}}
+			warnings, violations = warn.Check(data)
+			if len(violations) != 0 || len(warnings) != tt.want {
+				t.Errorf("warn mode: got warnings %v, violations %v; want %d warnings", warnings, violations, tt.want)
+			}
+		})
+	}
+}
+
+func TestManager_PolicyEnforced(t *testing.T) {
+	th := NewTestHelper(t)
+	defer th.Cleanup()
+
+	th.CreateSampleConfig()
+	manager := th.CreateManagerWithTempDir()
+
+	// Created before the policy existed
+	if err := manager.CreateContext("legacy"); err != nil {
+		t.Fatal(err)
+	}
+
+	writePolicy(t, th.ConfigDir, `{"rules": [
+		{"type": "forbid_plaintext_secrets"},
+		{"type": "max_timeout", "max": 10000, "mode": "warn"}
+	]}`)
+
+	var warnings []string
+	manager.SetWarningHandler(func(message string) { warnings = append(warnings, message) })
+
+	var violation *context.PolicyViolationError
+
+	// create: the sample config holds a literal apiKey
+	if err := manager.CreateContext("work"); !errors.As(err, &violation) || violation.Operation != "create" {
+		t.Errorf("Expected create to be denied, got %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(th.SettingsDir, "work.json")); !os.IsNotExist(err) {
+		t.Error("Denied create should not write the context")
+	}
+
+	// import
+	if err := manager.ImportContext("team", []byte(`{"provider": {"x": {"options": {"apiKey": "abc"}}}}`)); !errors.As(err, &violation) {
+		t.Errorf("Expected import to be denied, got %v", err)
+	}
+
+	// switch
+	if err := manager.SwitchToContext("legacy"); !errors.As(err, &violation) || violation.Operation != "switch" {
+		t.Errorf("Expected switch to be denied, got %v", err)
+	}
+
+	// warn rules only report
+	warnings = nil
+	if err := manager.ImportContext("slow", []byte(`{"mcp": {"a": {"timeout": 20000}}}`)); err != nil {
+		t.Fatalf("Expected warn-only import to succeed: %v", err)
+	}
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "mcp.a.timeout") {
+		t.Errorf("Expected one timeout warning, got %v", warnings)
+	}
+}
+
+func TestManager_PolicyForceKeepsExisting(t *testing.T) {
+	th := NewTestHelper(t)
+	defer th.Cleanup()
+
+	manager := th.CreateManagerWithTempDir()
+	if err := manager.ImportContext("team", []byte(`{"theme": "dark"}`)); err != nil {
+		t.Fatal(err)
+	}
+
+	writePolicy(t, th.ConfigDir, `{"rules": [{"type": "forbidden_keys", "keys": ["share"]}]}`)
+	manager.SetForce(true)
+
+	if err := manager.ImportContext("team", []byte(`{"share": "auto"}`)); err == nil {
+		t.Fatal("Expected forced import to be denied")
+	}
+	if _, err := os.Stat(filepath.Join(th.SettingsDir, "team.json")); err != nil {
+		t.Error("A denied forced import must not move the existing context to the trash")
+	}
+}
+
+func TestManager_InvalidPolicy(t *testing.T) {
+	th := NewTestHelper(t)
+	defer th.Cleanup()
+
+	manager := th.CreateManagerWithTempDir()
+
+	for _, policy := range []string{
+		`{"rules": [{"type": "no_such_rule"}]}`,
+		`{"rules": [{"type": "provider_allowlist"}]}`,
+		`{"rules": [{"type": "forbid_plaintext_secrets", "mode": "block"}]}`,
+		`{not json`,
+	} {
+		writePolicy(t, th.ConfigDir, policy)
+		if _, err := manager.LoadPolicy(); err == nil {
+			t.Errorf("Expected LoadPolicy to reject %s", policy)
+		}
+	}
+}
+
+func TestIntegration_Policy(t *testing.T) {
+	ith := NewIntegrationTestHelper(t)
+	defer ith.Cleanup()
+
+	writePolicy(t, ith.ConfigDir, `{"rules": [
+		{"type": "provider_allowlist", "providers": ["anthropic"]},
+		{"type": "forbid_plaintext_secrets", "mode": "warn"}
+	]}`)
+
+	_, stderr, err := ith.RunCommandWithInput(`{"model": "openai/gpt-4.1"}`, "--import", "work")
+	if err == nil || !strings.Contains(stderr, "provider 'openai'") {
+		t.Errorf("Expected allowlist denial, got err=%v stderr=%s", err, stderr)
+	}
+
+	_, stderr, err = ith.RunCommandWithInput(`{"provider": {"anthropic": {"options": {"apiKey": "abc"}}}}`, "--import", "work")
+	if err != nil {
+		t.Fatalf("Expected warn-only import to succeed: %v\n%s", err, stderr)
+	}
+	if !strings.Contains(stderr, "Warning: policy: secret") {
+		t.Errorf("Expected policy warning, got %s", stderr)
+	}
+}
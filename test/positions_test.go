@@ -0,0 +1,140 @@
+package test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hungthai1401/occtx/internal/config"
+)
+
+func TestManager_ResolvePosition(t *testing.T) {
+	th := NewTestHelper(t)
+	defer th.Cleanup()
+
+	th.CreateSampleConfig()
+	manager := th.CreateManagerWithTempDir()
+	for _, name := range []string{"alpha", "beta", "gamma", "2024"} {
+		if err := manager.CreateContext(name); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Before any listing, positions count the most recently used contexts
+	for _, name := range []string{"alpha", "gamma"} {
+		if err := manager.SwitchToContext(name); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if name, err := manager.ResolvePosition("1"); err != nil || name != "gamma" {
+		t.Errorf("Expected position 1 to be the last used context, got %q (%v)", name, err)
+	}
+
+	if err := manager.RecordListing([]string{"beta", "alpha", "gamma", "2024"}); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		arg  string
+		want string
+	}{
+		{"1", "beta"},
+		{"3", "gamma"},
+		{"2024", "2024"}, // A context of that name wins
+		{"alpha", "alpha"},
+		{"-", "-"},
+		{"02", "02"}, // Not a plain position
+	}
+	for _, tt := range tests {
+		if name, err := manager.ResolvePosition(tt.arg); err != nil || name != tt.want {
+			t.Errorf("ResolvePosition(%q) = %q, %v; want %q", tt.arg, name, err, tt.want)
+		}
+	}
+
+	for _, arg := range []string{"0", "5"} {
+		if _, err := manager.ResolvePosition(arg); err == nil || !strings.Contains(err.Error(), "occtx ls") {
+			t.Errorf("Expected position %s to be out of range, got %v", arg, err)
+		}
+	}
+
+	// A context of that name wins even when it doesn't load
+	if err := os.WriteFile(filepath.Join(th.SettingsDir, "2.json"), []byte("{broken"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if name, err := manager.ResolvePosition("2"); err != nil || name != "2" {
+		t.Errorf("Expected the broken context '2' to win, got %q (%v)", name, err)
+	}
+	if err := os.Remove(filepath.Join(th.SettingsDir, "2.json")); err != nil {
+		t.Fatal(err)
+	}
+
+	// Renames and deletes carry over to the listing
+	if err := manager.RenameContext("beta", "bravo"); err != nil {
+		t.Fatal(err)
+	}
+	if err := manager.DeleteContext("alpha"); err != nil {
+		t.Fatal(err)
+	}
+	for arg, want := range map[string]string{"1": "bravo", "2": "gamma", "3": "2024"} {
+		if name, err := manager.ResolvePosition(arg); err != nil || name != want {
+			t.Errorf("ResolvePosition(%q) = %q, %v; want %q", arg, name, err, want)
+		}
+	}
+}
+
+func TestIntegration_NumberedSwitch(t *testing.T) {
+	ith := NewIntegrationTestHelper(t)
+	defer ith.Cleanup()
+
+	ith.CreateSampleConfig()
+	for _, name := range []string{"dev", "prod", "staging"} {
+		if _, stderr, err := ith.RunCommand("-n", name); err != nil {
+			t.Fatalf("-n %s failed: %v\n%s", name, err, stderr)
+		}
+	}
+
+	out, stderr, err := ith.RunCommand("ls")
+	if err != nil {
+		t.Fatalf("ls failed: %v\n%s", err, stderr)
+	}
+	for _, line := range []string{"1  dev", "2  prod", "3  staging"} {
+		if !strings.Contains(out, line) {
+			t.Errorf("Expected %q in the numbered listing, got:\n%s", line, out)
+		}
+	}
+	if out, _, _ := ith.RunCommand(); strings.Contains(out, "1  dev") {
+		t.Errorf("Expected the plain listing to stay unnumbered, got:\n%s", out)
+	}
+
+	if _, stderr, err := ith.RunCommand("3"); err != nil {
+		t.Fatalf("switching by position failed: %v\n%s", err, stderr)
+	}
+	if out, _, _ := ith.RunCommand("-c"); strings.TrimSpace(out) != "staging" {
+		t.Errorf("Expected 'staging' to be current, got %q", out)
+	}
+
+	// Listing the same order again doesn't rewrite the state
+	stateFile := filepath.Join(ith.SettingsDir, config.StateFileName)
+	old := time.Now().Add(-time.Hour).Truncate(time.Second)
+	if err := os.Chtimes(stateFile, old, old); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := ith.RunCommand("ls"); err != nil {
+		t.Fatal(err)
+	}
+	if info, err := os.Stat(stateFile); err != nil || !info.ModTime().Equal(old) {
+		t.Errorf("Expected an unchanged listing not to save the state (%v)", err)
+	}
+
+	// The order of the last listing counts, not the current sort
+	if _, _, err := ith.RunCommand("ls", "--sort", "used"); err != nil {
+		t.Fatal(err)
+	}
+	if _, stderr, err := ith.RunCommand("switch", "1"); err != nil {
+		t.Fatalf("switch by position failed: %v\n%s", err, stderr)
+	}
+	if out, _, _ := ith.RunCommand("-c"); strings.TrimSpace(out) != "staging" {
+		t.Errorf("Expected position 1 of the used-order listing to be 'staging', got %q", out)
+	}
+}
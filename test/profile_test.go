@@ -0,0 +1,100 @@
+package test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeOcctxConfig writes the occtx config file into the test config dir
+func writeOcctxConfig(t *testing.T, configDir, content string) {
+	if err := os.WriteFile(filepath.Join(configDir, ".occtx-config.json"), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestManager_UseProfile(t *testing.T) {
+	th := NewTestHelper(t)
+	defer th.Cleanup()
+
+	workDir := filepath.Join(th.TempDir, "opencode-work")
+	writeOcctxConfig(t, th.ConfigDir, `{"profiles": {"work": {"config_dir": "`+filepath.ToSlash(workDir)+`"}}}`)
+
+	manager := th.CreateManagerWithTempDir()
+	if err := manager.UseProfile("work"); err != nil {
+		t.Fatalf("UseProfile failed: %v", err)
+	}
+
+	if manager.Profile() != "work" {
+		t.Errorf("Expected profile 'work', got %q", manager.Profile())
+	}
+
+	paths := manager.GetPaths()
+	if paths.GlobalActiveConfig != filepath.Join(workDir, "opencode.json") {
+		t.Errorf("Unexpected active config path: %s", paths.GlobalActiveConfig)
+	}
+	if paths.GlobalStateFile != filepath.Join(workDir, "settings", ".occtx-state.json") {
+		t.Errorf("Unexpected state file path: %s", paths.GlobalStateFile)
+	}
+
+	if err := os.MkdirAll(workDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(paths.GlobalActiveConfig, []byte(`{"theme": "work"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := manager.CreateContext("corp"); err != nil {
+		t.Fatalf("CreateContext failed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(workDir, "settings", "corp.json")); err != nil {
+		t.Error("Expected context in the profile's settings dir")
+	}
+	if _, err := os.Stat(filepath.Join(th.SettingsDir, "corp.json")); err == nil {
+		t.Error("Context should not be created in the default settings dir")
+	}
+
+	if err := manager.UseProfile("missing"); err == nil || !strings.Contains(err.Error(), "available: work") {
+		t.Errorf("Expected unknown profile error listing profiles, got %v", err)
+	}
+}
+
+func TestIntegration_ProfileEnv(t *testing.T) {
+	ith := NewIntegrationTestHelper(t)
+	defer ith.Cleanup()
+
+	ith.CreateSampleConfig()
+
+	personalDir := filepath.Join(ith.TempDir, "opencode-personal")
+	writeOcctxConfig(t, ith.ConfigDir, `{"profiles": {"personal": {"config_dir": "~/opencode-personal"}}}`)
+	if err := os.MkdirAll(personalDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(personalDir, "opencode.json"), []byte(`{"theme": "personal"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, _, err := ith.RunCommand("--profile", "personal", "-n", "home"); err != nil {
+		t.Fatalf("Create in profile failed: %v", err)
+	}
+
+	stdout, _, err := ith.RunCommand("--profile", "personal")
+	if err != nil {
+		t.Fatalf("List in profile failed: %v", err)
+	}
+	if !strings.Contains(stdout, "home") {
+		t.Errorf("Expected 'home' in profile listing, got: %s", stdout)
+	}
+
+	stdout, _, err = ith.RunCommand()
+	if err != nil {
+		t.Fatalf("Default list failed: %v", err)
+	}
+	if strings.Contains(stdout, "home") {
+		t.Errorf("Default listing should not include profile contexts, got: %s", stdout)
+	}
+
+	if _, _, err := ith.RunCommand("--profile", "nope"); err == nil {
+		t.Error("Expected error for unknown profile")
+	}
+}
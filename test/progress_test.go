@@ -0,0 +1,44 @@
+package test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hungthai1401/occtx/internal/ui"
+)
+
+func TestProgress_Lifecycle(t *testing.T) {
+	progress := ui.NewProgress("Working")
+
+	// Stop before Start and repeated calls must be harmless
+	progress.Stop()
+	progress.Start()
+	progress.Start()
+	progress.Update("Working on %s", "work")
+	progress.Stop()
+	progress.Stop()
+
+	ui.SetProgressEnabled(false)
+	defer ui.SetProgressEnabled(true)
+	disabled := ui.NewProgress("Quiet")
+	disabled.Start()
+	disabled.Stop()
+}
+
+func TestIntegration_ProgressNotOnPipes(t *testing.T) {
+	ith := NewIntegrationTestHelper(t)
+	defer ith.Cleanup()
+
+	if _, _, err := ith.RunCommandWithInput(`{"theme": "dark"}`, "--import", "work"); err != nil {
+		t.Fatalf("import failed: %v", err)
+	}
+
+	// stderr is a pipe here, so the spinner must not draw anything
+	_, stderr, err := ith.RunCommand("verify", "--all")
+	if err != nil {
+		t.Fatalf("verify failed: %v\n%s", err, stderr)
+	}
+	if strings.Contains(stderr, "\r") || strings.Contains(stderr, "Verifying") {
+		t.Errorf("Expected no spinner output on a pipe, got %q", stderr)
+	}
+}
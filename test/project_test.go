@@ -0,0 +1,87 @@
+package test
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestIntegration_ProjectInit(t *testing.T) {
+	ith := NewIntegrationTestHelper(t)
+	defer ith.Cleanup()
+
+	globalSettings := filepath.Join(ith.ConfigDir, "settings")
+	if err := os.MkdirAll(globalSettings, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(globalSettings, "work.json"), []byte(`{"theme": "dark"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	project := filepath.Join(ith.TempDir, "project")
+	if err := os.MkdirAll(project, 0755); err != nil {
+		t.Fatal(err)
+	}
+	gitignore := filepath.Join(project, ".gitignore")
+	if err := os.WriteFile(gitignore, []byte("node_modules"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	run := func(args ...string) (string, error) {
+		cmd := exec.Command(ith.BinaryPath, args...)
+		cmd.Dir = project
+		cmd.Env = ith.Env()
+		out, err := cmd.CombinedOutput()
+		return string(out), err
+	}
+
+	// Seeding needs a current global context
+	if out, err := run("project", "init", "--copy-current"); err == nil || !strings.Contains(out, "no global context is current") {
+		t.Errorf("Expected --copy-current to need a current context, got %v\n%s", err, out)
+	}
+
+	if out, err := run("work"); err != nil {
+		t.Fatalf("switch failed: %v\n%s", err, out)
+	}
+	if out, err := run("project", "init", "--copy-current", "--name", "team"); err != nil {
+		t.Fatalf("project init failed: %v\n%s", err, out)
+	}
+	if out, err := run("project", "init"); err != nil {
+		t.Fatalf("second project init failed: %v\n%s", err, out)
+	}
+
+	data, err := os.ReadFile(filepath.Join(project, "opencode", "settings", "team.json"))
+	if err != nil || !strings.Contains(string(data), "dark") {
+		t.Errorf("Expected the global context copied as team, got %v\n%s", err, data)
+	}
+	if data, _ := os.ReadFile(filepath.Join(project, ".occtx-context")); string(data) != "proj:team\n" {
+		t.Errorf("Expected .occtx-context to name proj:team, got %q", data)
+	}
+
+	data, err = os.ReadFile(gitignore)
+	if err != nil {
+		t.Fatal(err)
+	}
+	content := string(data)
+	if !strings.HasPrefix(content, "node_modules\n\n# occtx") {
+		t.Errorf("Expected the block appended after the existing entries, got:\n%s", content)
+	}
+	if strings.Count(content, "# occtx") != 1 {
+		t.Errorf("Expected the block once, got:\n%s", content)
+	}
+	for _, want := range []string{"/opencode/settings/.occtx-state.json\n", "/opencode/settings/*.local.json\n", "/opencode/settings/current-context\n"} {
+		if !strings.Contains(content, want) {
+			t.Errorf("Expected %q in .gitignore, got:\n%s", want, content)
+		}
+	}
+
+	// The shell hook switches the project scope to the named context
+	if out, err := run("hook-env", "bash", "--auto-switch"); err != nil || !strings.Contains(out, "switched to context 'team'") {
+		t.Errorf("Expected the hook to switch to team, got %v\n%s", err, out)
+	}
+	if out, _ := run("--in-project", "current"); strings.TrimSpace(out) != "team" {
+		t.Errorf("Expected the project scope on team, got %q", out)
+	}
+}
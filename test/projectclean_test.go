@@ -0,0 +1,94 @@
+package test
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestIntegration_UnsetCleanProject(t *testing.T) {
+	ith := NewIntegrationTestHelper(t)
+	defer ith.Cleanup()
+
+	project := filepath.Join(ith.TempDir, "project")
+	if err := os.MkdirAll(project, 0755); err != nil {
+		t.Fatal(err)
+	}
+	run := func(input string, args ...string) (string, error) {
+		cmd := exec.Command(ith.BinaryPath, args...)
+		cmd.Dir = project
+		cmd.Env = ith.Env()
+		cmd.Stdin = strings.NewReader(input)
+		out, err := cmd.CombinedOutput()
+		return string(out), err
+	}
+	projectDir := filepath.Join(project, "opencode")
+
+	if out, err := run("", "-u", "--clean"); err == nil || !strings.Contains(out, "--in-project") {
+		t.Errorf("Expected --clean to need --in-project, got %v\n%s", err, out)
+	}
+
+	if out, err := run(`{"theme": "dark"}`, "--in-project", "--import", "scratch"); err != nil {
+		t.Fatalf("import failed: %v\n%s", err, out)
+	}
+	if out, err := run("", "--in-project", "scratch"); err != nil {
+		t.Fatalf("switch failed: %v\n%s", err, out)
+	}
+
+	// A context is more than state
+	out, err := run("", "--in-project", "-u", "--clean", "--yes")
+	if err == nil || !strings.Contains(out, "settings/scratch.json") {
+		t.Errorf("Expected the context to keep the directory, got %v\n%s", err, out)
+	}
+	if _, err := os.Stat(filepath.Join(project, "opencode.json")); !os.IsNotExist(err) {
+		t.Error("Expected the context to be unset anyway")
+	}
+
+	if out, err := run("", "--in-project", "-d", "scratch"); err != nil {
+		t.Fatalf("delete failed: %v\n%s", err, out)
+	}
+	if err := os.RemoveAll(filepath.Join(projectDir, "settings", "trash")); err != nil {
+		t.Fatal(err)
+	}
+
+	// A current context whose file is gone leaves only state behind
+	if out, err := run(`{"theme": "dark"}`, "--in-project", "--import", "gone"); err != nil {
+		t.Fatalf("import failed: %v\n%s", err, out)
+	}
+	if out, err := run("", "--in-project", "gone"); err != nil {
+		t.Fatalf("switch failed: %v\n%s", err, out)
+	}
+	if err := os.Remove(filepath.Join(projectDir, "settings", "gone.json")); err != nil {
+		t.Fatal(err)
+	}
+
+	// Without a terminal to confirm on, --yes is needed, and nothing is
+	// unset before confirming
+	if out, err := run("", "--in-project", "-u", "--clean"); err == nil || !strings.Contains(out, "needs confirmation") {
+		t.Errorf("Expected a refusal without confirmation, got %v\n%s", err, out)
+	}
+	if _, err := os.Stat(projectDir); err != nil {
+		t.Fatal("Expected the directory to be kept without confirmation")
+	}
+	if _, err := os.Stat(filepath.Join(project, "opencode.json")); err != nil {
+		t.Error("Expected the context to stay active without confirmation")
+	}
+	if out, _ := run("", "--in-project", "-c"); !strings.Contains(out, "gone") {
+		t.Errorf("Expected 'gone' to stay current without confirmation, got:\n%s", out)
+	}
+
+	if out, err := run("", "--in-project", "-u", "--clean", "--yes"); err != nil || !strings.Contains(out, "Current context unset") || !strings.Contains(out, "Removed") {
+		t.Fatalf("clean failed: %v\n%s", err, out)
+	}
+	if _, err := os.Stat(filepath.Join(project, "opencode.json")); !os.IsNotExist(err) {
+		t.Error("Expected the context to be unset")
+	}
+	if _, err := os.Stat(projectDir); !os.IsNotExist(err) {
+		t.Error("Expected the project directory to be removed")
+	}
+	if _, err := os.Stat(project); err != nil {
+		t.Error("Expected the project itself to be kept")
+	}
+}
@@ -0,0 +1,72 @@
+package test
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestIntegration_ProjectGuard(t *testing.T) {
+	ith := NewIntegrationTestHelper(t)
+	defer ith.Cleanup()
+
+	ith.CreateSampleConfig()
+	runIn := func(dir string, args ...string) (string, error) {
+		cmd := exec.Command(ith.BinaryPath, args...)
+		cmd.Dir = dir
+		cmd.Env = ith.Env()
+		cmd.Stdin = strings.NewReader(`{"theme": "dark"}`)
+		out, err := cmd.CombinedOutput()
+		return string(out), err
+	}
+	projectTree := func(dir string) bool {
+		_, err := os.Stat(filepath.Join(dir, "opencode"))
+		return err == nil
+	}
+
+	// The home directory is refused, and nothing is created there; reading
+	// the project scope still works
+	if out, err := runIn(ith.TempDir, "--in-project"); err != nil || strings.Contains(out, "Warning") {
+		t.Errorf("Expected listing the project scope to work quietly, got %v\n%s", err, out)
+	}
+	out, err := runIn(ith.TempDir, "--in-project", "--import", "scratch")
+	if err == nil || !strings.Contains(out, "it is the home directory") {
+		t.Errorf("Expected a project scope in the home directory to be refused, got %v\n%s", err, out)
+	}
+	if projectTree(ith.TempDir) {
+		t.Error("Expected no opencode directory in the home directory")
+	}
+
+	// Outside the allowed roots
+	code := filepath.Join(ith.TempDir, "code", "app")
+	other := filepath.Join(ith.TempDir, "downloads")
+	for _, dir := range []string{code, other} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeOcctxConfig(t, ith.ConfigDir, `{"projects": {"allowed_roots": ["~/code"]}}`)
+	if out, err := runIn(other, "--in-project", "--import", "scratch"); err == nil || !strings.Contains(out, "outside projects.allowed_roots") {
+		t.Errorf("Expected a project scope outside the allowed roots to be refused, got %v\n%s", err, out)
+	}
+	if out, err := runIn(code, "--in-project", "--import", "app"); err != nil {
+		t.Errorf("Expected a project scope inside the allowed roots, got %v\n%s", err, out)
+	}
+
+	// An existing project scope is used as it is
+	if err := os.MkdirAll(filepath.Join(other, "opencode"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if out, err := runIn(other, "--in-project", "--import", "scratch"); err != nil {
+		t.Errorf("Expected an existing project scope to be allowed, got %v\n%s", err, out)
+	}
+
+	// Warn instead of refusing
+	writeOcctxConfig(t, ith.ConfigDir, `{"projects": {"guard": "warn"}}`)
+	out, err = runIn(ith.TempDir, "--in-project", "--import", "scratch")
+	if err != nil || strings.Count(out, "Warning: creating a project scope") != 1 {
+		t.Errorf("Expected one warning, got %v\n%s", err, out)
+	}
+}
@@ -0,0 +1,95 @@
+package test
+
+import (
+	gocontext "context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hungthai1401/occtx/internal/context"
+)
+
+// createProjectContexts creates a project directory under root with the given contexts
+func createProjectContexts(t *testing.T, root, project, current string, names ...string) string {
+	dir := filepath.Join(root, project)
+	settingsDir := filepath.Join(dir, "opencode", "settings")
+	if err := os.MkdirAll(settingsDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, name := range names {
+		if err := os.WriteFile(filepath.Join(settingsDir, name+".json"), []byte(`{}`), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if current != "" {
+		state := []byte(`{"current": "` + current + `"}`)
+		if err := os.WriteFile(filepath.Join(settingsDir, ".occtx-state.json"), state, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return dir
+}
+
+func TestDiscoverProjects(t *testing.T) {
+	root := t.TempDir()
+
+	alpha := createProjectContexts(t, root, "alpha", "dev", "dev", "prod")
+	nested := createProjectContexts(t, root, filepath.Join("group", "beta"), "", "local")
+	createProjectContexts(t, root, filepath.Join("a", "b", "c", "d", "too-deep"), "", "x")
+	createProjectContexts(t, root, filepath.Join("node_modules", "dep"), "", "x")
+	createProjectContexts(t, root, ".hidden", "", "x")
+	createProjectContexts(t, root, "empty", "")
+
+	projects, err := context.DiscoverProjects(gocontext.Background(), []string{root}, 3)
+	if err != nil {
+		t.Fatalf("DiscoverProjects failed: %v", err)
+	}
+
+	if len(projects) != 2 {
+		t.Fatalf("Expected 2 projects, got %+v", projects)
+	}
+
+	if projects[0].Path != alpha || projects[0].Contexts != 2 || projects[0].Current != "dev" {
+		t.Errorf("Unexpected alpha project: %+v", projects[0])
+	}
+	if projects[1].Path != nested || projects[1].Contexts != 1 || projects[1].Current != "" {
+		t.Errorf("Unexpected nested project: %+v", projects[1])
+	}
+
+	// Overlapping roots report each project once
+	projects, err = context.DiscoverProjects(gocontext.Background(), []string{root, filepath.Join(root, "group")}, 3)
+	if err != nil {
+		t.Fatalf("DiscoverProjects failed: %v", err)
+	}
+	if len(projects) != 2 {
+		t.Errorf("Expected 2 projects with overlapping roots, got %+v", projects)
+	}
+
+	if _, err := context.DiscoverProjects(gocontext.Background(), []string{filepath.Join(root, "missing")}, 3); err == nil {
+		t.Error("Expected error for missing root")
+	}
+}
+
+func TestIntegration_Projects(t *testing.T) {
+	ith := NewIntegrationTestHelper(t)
+	defer ith.Cleanup()
+
+	if _, _, err := ith.RunCommand("projects"); err == nil {
+		t.Error("Expected error without configured roots")
+	}
+
+	root := filepath.Join(ith.TempDir, "code")
+	project := createProjectContexts(t, root, "repo", "dev", "dev")
+
+	stdout, _, err := ith.RunCommand("projects", "--root", root)
+	if err != nil {
+		t.Fatalf("projects failed: %v", err)
+	}
+	if !strings.Contains(stdout, project) || !strings.Contains(stdout, "dev") {
+		t.Errorf("Expected project row in output, got: %s", stdout)
+	}
+}
@@ -0,0 +1,120 @@
+package test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/hungthai1401/occtx/internal/context"
+)
+
+func TestManager_ExportApplyProvider(t *testing.T) {
+	th := NewTestHelper(t)
+	defer th.Cleanup()
+
+	th.CreateSampleConfig()
+	manager := th.CreateManagerWithTempDir()
+	contexts := map[string]string{
+		"work":     `{"provider": {"anthropic": {"options": {"apiKey": "{env:ANTHROPIC_API_KEY}", "baseURL": "https://proxy.example.com"}}}, "model": "anthropic/claude-sonnet"}`,
+		"personal": `{"provider": {"anthropic": {"options": {"apiKey": "old"}}, "openai": {"options": {}}}, "theme": "dark"}`,
+		"legacy":   `{"provider": {"anthropic": {"options": {"apiKey": "sk-ant-123"}}}}`,
+	}
+	for name, data := range contexts {
+		if err := manager.ImportContext(name, []byte(data)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	profile, err := manager.ExportProvider("work", "anthropic", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := manager.ExportProvider("work", "openai", false); err == nil || !strings.Contains(err.Error(), "anthropic") {
+		t.Errorf("Expected a missing provider to fail and name the existing ones, got %v", err)
+	}
+
+	applied, err := manager.ApplyProviders("personal", profile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(applied) != 1 || applied[0] != "anthropic" {
+		t.Errorf("Unexpected applied providers: %v", applied)
+	}
+
+	ctx, err := manager.GetContext("personal")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := (context.KeyPath{"provider", "anthropic", "options", "baseURL"}).Get(ctx.Data); got != "https://proxy.example.com" {
+		t.Errorf("Expected the provider block to be applied, got %v", ctx.Data)
+	}
+	if _, ok := (context.KeyPath{"provider", "openai"}).Get(ctx.Data); !ok || ctx.Data["theme"] != "dark" {
+		t.Errorf("Expected the rest of the context to be kept, got %v", ctx.Data)
+	}
+	if _, ok := ctx.Data["model"]; ok {
+		t.Error("Expected only the provider block to be copied")
+	}
+
+	log, err := manager.ContextLog("personal")
+	if err != nil {
+		t.Fatal(err)
+	}
+	last := log[len(log)-1]
+	if last.Kind != context.LogApplied || !strings.HasPrefix(last.Summary, "provider anthropic: ") {
+		t.Errorf("Unexpected changelog entry: %+v", last)
+	}
+
+	// A redacted export can't be applied as is
+	redacted, err := manager.ExportProvider("legacy", "anthropic", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(redacted), context.RedactedValue) {
+		t.Fatalf("Expected the API key to be redacted:\n%s", redacted)
+	}
+	if _, err := manager.ApplyProviders("work", redacted); err == nil {
+		t.Error("Expected a redacted provider to be refused")
+	}
+	if _, err := manager.ApplyProviders("work", []byte(`{"anthropic": "x"}`)); err == nil {
+		t.Error("Expected a provider that isn't an object to be refused")
+	}
+}
+
+func TestIntegration_ProviderExportApply(t *testing.T) {
+	ith := NewIntegrationTestHelper(t)
+	defer ith.Cleanup()
+
+	ith.CreateSampleConfig()
+	if _, stderr, err := ith.RunCommand("-n", "work"); err != nil {
+		t.Fatalf("-n failed: %v\n%s", err, stderr)
+	}
+	if _, stderr, err := ith.RunCommandWithInput(`{"model": "openai/gpt-4.1"}`, "--import", "personal"); err != nil {
+		t.Fatalf("--import failed: %v\n%s", err, stderr)
+	}
+
+	profile, stderr, err := ith.RunCommand("provider", "export", "work", "anthropic")
+	if err != nil {
+		t.Fatalf("provider export failed: %v\n%s", err, stderr)
+	}
+	var parsed map[string]interface{}
+	if err := json.Unmarshal([]byte(profile), &parsed); err != nil || len(parsed) != 1 || parsed["anthropic"] == nil {
+		t.Fatalf("Unexpected provider export: %v\n%s", err, profile)
+	}
+
+	out, stderr, err := ith.RunCommandWithInput(profile, "provider", "apply", "personal", "-")
+	if err != nil {
+		t.Fatalf("provider apply failed: %v\n%s", err, stderr)
+	}
+	if !strings.Contains(out, "Applied provider anthropic to 'personal'") {
+		t.Errorf("Unexpected provider apply output: %q", out)
+	}
+
+	out, _, err = ith.RunCommand("__get", "personal", "provider.anthropic.api", "--raw")
+	if err != nil || strings.TrimSpace(out) != "https://api.anthropic.com" {
+		t.Errorf("Expected the provider in 'personal', got %q, %v", out, err)
+	}
+	out, _, _ = ith.RunCommand("__get", "personal", "model", "--raw")
+	if strings.TrimSpace(out) != "openai/gpt-4.1" {
+		t.Errorf("Expected the model of 'personal' to be kept, got %q", out)
+	}
+}
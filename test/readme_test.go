@@ -0,0 +1,112 @@
+package test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/fatih/color"
+	"github.com/hungthai1401/occtx/internal/ui"
+)
+
+const sampleReadme = "# Work\n\nFor **client** work, see [the wiki](https://example.com).\n\n- run `occtx diff` first\n\n```\nocctx work\n```\n"
+
+func TestManager_ContextDoc(t *testing.T) {
+	th := NewTestHelper(t)
+	defer th.Cleanup()
+
+	th.CreateSampleConfig()
+	manager := th.CreateManagerWithTempDir()
+	if err := manager.CreateContext("work"); err != nil {
+		t.Fatal(err)
+	}
+
+	doc, err := manager.GetContextDoc("work")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !doc.Empty() {
+		t.Errorf("Expected no documentation, got %+v", doc)
+	}
+
+	if err := manager.SetDescription("work", "  Client   work "); err != nil {
+		t.Fatal(err)
+	}
+	readme := filepath.Join(th.SettingsDir, "work.md")
+	if err := os.WriteFile(readme, []byte(sampleReadme), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if contexts, _ := manager.ListContexts(); len(contexts) != 1 {
+		t.Errorf("A README must not be listed as a context, got %d contexts", len(contexts))
+	}
+
+	doc, err = manager.GetContextDoc("work")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if doc.Description != "Client work" || doc.Readme != sampleReadme || doc.ReadmePath != readme {
+		t.Errorf("Unexpected documentation: %+v", doc)
+	}
+
+	// The README moves with the context
+	if err := manager.RenameContext("work", "client"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(th.SettingsDir, "client.md")); err != nil {
+		t.Errorf("Expected the README to be renamed: %v", err)
+	}
+	if doc, _ := manager.GetContextDoc("client"); doc.Description != "Client work" || doc.Readme == "" {
+		t.Errorf("Expected the documentation to follow the rename, got %+v", doc)
+	}
+}
+
+func TestRenderMarkdown(t *testing.T) {
+	noColor := color.NoColor
+	color.NoColor = true
+	defer func() { color.NoColor = noColor }()
+
+	want := "Work\n\nFor client work, see the wiki (https://example.com).\n\n• run occtx diff first\n\n    occtx work\n"
+	if got := ui.RenderMarkdown(sampleReadme); got != want {
+		t.Errorf("Unexpected rendering:\n got %q\nwant %q", got, want)
+	}
+	// Identifiers keep their underscores and code spans stay literal
+	if got := ui.RenderMarkdown("set my_api_key with `**not bold**`"); got != "set my_api_key with **not bold**\n" {
+		t.Errorf("Unexpected rendering: %q", got)
+	}
+}
+
+func TestIntegration_ContextDocShown(t *testing.T) {
+	ith := NewIntegrationTestHelper(t)
+	defer ith.Cleanup()
+
+	ith.CreateSampleConfig()
+	if _, stderr, err := ith.RunCommand("-n", "work"); err != nil {
+		t.Fatalf("-n failed: %v\n%s", err, stderr)
+	}
+	if _, stderr, err := ith.RunCommand("describe", "work", "Client", "work"); err != nil {
+		t.Fatalf("describe failed: %v\n%s", err, stderr)
+	}
+	if err := os.WriteFile(filepath.Join(ith.SettingsDir, "work.md"), []byte(sampleReadme), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	out, stderr, err := ith.RunCommand("-s", "work", "--meta")
+	if err != nil {
+		t.Fatalf("-s --meta failed: %v\n%s", err, stderr)
+	}
+	for _, want := range []string{"About:   Client work", "README:  ", "• run occtx diff first"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Expected %q in:\n%s", want, out)
+		}
+	}
+
+	// The fzf preview shows the docs, then the content
+	out, stderr, err = ith.RunCommand("preview", "work")
+	if err != nil {
+		t.Fatalf("preview failed: %v\n%s", err, stderr)
+	}
+	if doc, content := strings.Index(out, "Client work"), strings.Index(out, "{"); doc < 0 || content < doc {
+		t.Errorf("Expected the description before the content:\n%s", out)
+	}
+}
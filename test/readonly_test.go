@@ -0,0 +1,94 @@
+package test
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hungthai1401/occtx/internal/context"
+)
+
+func TestManager_ReadOnly(t *testing.T) {
+	th := NewTestHelper(t)
+	defer th.Cleanup()
+
+	th.CreateSampleConfig()
+	manager := th.CreateManagerWithTempDir()
+
+	if err := manager.CreateContext("work"); err != nil {
+		t.Fatalf("CreateContext failed: %v", err)
+	}
+
+	manager.SetReadOnly(true)
+
+	operations := map[string]func() error{
+		"create": func() error { return manager.CreateContext("other") },
+		"switch": func() error { return manager.SwitchToContext("work") },
+		"delete": func() error { return manager.DeleteContext("work") },
+		"rename": func() error { return manager.RenameContext("work", "renamed") },
+		"unset":  func() error { return manager.UnsetCurrentContext() },
+		"import": func() error { return manager.ImportContext("imported", []byte(`{}`)) },
+		"stash": func() error {
+			_, err := manager.StashSave("")
+			return err
+		},
+	}
+
+	for name, operation := range operations {
+		if err := operation(); !errors.Is(err, context.ErrReadOnly) {
+			t.Errorf("%s: expected ErrReadOnly, got %v", name, err)
+		}
+	}
+
+	// Reads keep working
+	if _, err := manager.ListContexts(); err != nil {
+		t.Errorf("ListContexts failed in read-only mode: %v", err)
+	}
+	if _, err := manager.GetContext("work"); err != nil {
+		t.Errorf("GetContext failed in read-only mode: %v", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(th.SettingsDir, "work.json")); err != nil {
+		t.Error("Context should still exist after rejected delete")
+	}
+}
+
+func TestIntegration_ReadOnlyEnv(t *testing.T) {
+	ith := NewIntegrationTestHelper(t)
+	defer ith.Cleanup()
+
+	ith.CreateSampleConfig()
+
+	if _, _, err := ith.RunCommand("-n", "work"); err != nil {
+		t.Fatalf("Failed to create context: %v", err)
+	}
+
+	cmd := exec.Command(ith.BinaryPath, "work")
+	cmd.Env = ith.Env("OCCTX_READONLY=1")
+	output, err := cmd.CombinedOutput()
+	if err == nil {
+		t.Fatal("Switch should fail with OCCTX_READONLY=1")
+	}
+	if !strings.Contains(string(output), "read-only mode") {
+		t.Errorf("Expected read-only error message, got: %s", output)
+	}
+
+	_, stderr, err := ith.RunCommand("--read-only", "-d", "work")
+	if err == nil {
+		t.Fatal("Delete should fail with --read-only")
+	}
+	if !strings.Contains(stderr, "cannot delete context") {
+		t.Errorf("Expected operation in error message, got: %s", stderr)
+	}
+
+	stdout, _, err := ith.RunCommand("--read-only")
+	if err != nil {
+		t.Fatalf("Listing should work in read-only mode: %v", err)
+	}
+	if !strings.Contains(stdout, "work") {
+		t.Error("Expected 'work' context in list")
+	}
+}
@@ -0,0 +1,89 @@
+package test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/hungthai1401/occtx/internal/context"
+)
+
+func TestManager_RenameChecksAllFormats(t *testing.T) {
+	th := NewTestHelper(t)
+	defer th.Cleanup()
+
+	th.CreateSampleConfig()
+	manager := th.CreateManagerWithTempDir()
+
+	if err := manager.CreateContext("work"); err != nil {
+		t.Fatal(err)
+	}
+	if err := manager.CreateContextWithFormat("personal", context.FormatJSONC); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := manager.RenameContext("work", "personal"); err == nil {
+		t.Fatal("Expected rename onto an existing JSONC context to fail")
+	}
+	if _, err := os.Stat(filepath.Join(th.SettingsDir, "work.json")); err != nil {
+		t.Error("Source context should be untouched after a rejected rename")
+	}
+}
+
+func TestManager_RenameMovesAllArtifacts(t *testing.T) {
+	th := NewTestHelper(t)
+	defer th.Cleanup()
+
+	th.CreateSampleConfig()
+	manager := th.CreateManagerWithTempDir()
+
+	if err := manager.CreateContextWithFormat("work", context.FormatJSONC); err != nil {
+		t.Fatal(err)
+	}
+	if err := manager.CreateContext("home"); err != nil {
+		t.Fatal(err)
+	}
+	if err := manager.SwitchToContext("work"); err != nil {
+		t.Fatal(err)
+	}
+	if err := manager.SwitchToContextFor("home", time.Hour); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := manager.RenameContext("work", "office"); err != nil {
+		t.Fatalf("RenameContext failed: %v", err)
+	}
+
+	// The format is kept
+	ctx, err := manager.GetContext("office")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ctx.Format != context.FormatJSONC {
+		t.Errorf("Expected rename to keep JSONC format, got %s", ctx.Format)
+	}
+
+	// Checksum and metadata follow the file
+	results, err := manager.VerifyContexts()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, result := range results {
+		if result.Status != context.VerifyOK {
+			t.Errorf("Expected %s to verify after rename, got %s", result.File, result.Status)
+		}
+	}
+	if meta, _ := manager.GetContextMeta("office"); meta == nil {
+		t.Error("Expected metadata to follow rename")
+	}
+
+	// State references are updated, including the pending revert
+	temporary, err := manager.GetTemporarySwitch()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if temporary == nil || temporary.RevertTo != "office" {
+		t.Errorf("Expected temporary switch to revert to 'office', got %+v", temporary)
+	}
+}
@@ -0,0 +1,84 @@
+package test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestIntegration_Render(t *testing.T) {
+	ith := NewIntegrationTestHelper(t)
+	defer ith.Cleanup()
+
+	ith.CreateSampleConfig()
+	if _, stderr, err := ith.RunCommand("-n", "work"); err != nil {
+		t.Fatalf("-n failed: %v\n%s", err, stderr)
+	}
+	if _, stderr, err := ith.RunCommand("work"); err != nil {
+		t.Fatalf("switch failed: %v\n%s", err, stderr)
+	}
+
+	// A plain context renders as exactly what the switch wrote
+	activeConfigPath := filepath.Join(ith.ConfigDir, "opencode.json")
+	active, err := os.ReadFile(activeConfigPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, stderr, err := ith.RunCommand("render", "work")
+	if err != nil {
+		t.Fatalf("render failed: %v\n%s", err, stderr)
+	}
+	if out != string(active) {
+		t.Errorf("Expected render to match the active config, got:\n%s\nwant:\n%s", out, active)
+	}
+
+	// Overlays go on top of the context, the local override on top of them
+	review := `{"model": "openai/gpt-4.1", "theme": "review", "agent": {"review": {"model": "openai/gpt-4.1"}}}`
+	if _, stderr, err := ith.RunCommandWithInput(review, "--import", "review"); err != nil {
+		t.Fatalf("--import failed: %v\n%s", err, stderr)
+	}
+	settingsDir := filepath.Join(ith.ConfigDir, "settings")
+	if err := os.WriteFile(filepath.Join(settingsDir, "work.local.json"), []byte(`{"theme": "local"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	out, stderr, err = ith.RunCommand("render", "work", "--overlay", "review")
+	if err != nil {
+		t.Fatalf("render --overlay failed: %v\n%s", err, stderr)
+	}
+	var rendered map[string]interface{}
+	if err := json.Unmarshal([]byte(out), &rendered); err != nil {
+		t.Fatalf("Invalid render output: %v\n%s", err, out)
+	}
+	agents, _ := rendered["agent"].(map[string]interface{})
+	if rendered["model"] != "openai/gpt-4.1" || rendered["theme"] != "local" || agents["default"] == nil || agents["review"] == nil {
+		t.Errorf("Unexpected rendered config: %v", rendered)
+	}
+	if after, _ := os.ReadFile(activeConfigPath); string(after) != string(active) {
+		t.Error("Expected render to leave the active config alone")
+	}
+
+	// Templates render under the name with their variables
+	templatesDir := filepath.Join(settingsDir, "templates")
+	if err := os.MkdirAll(templatesDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(templatesDir, "team.json"), []byte(`{"model": "{{ .model }}"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	out, stderr, err = ith.RunCommand("render", "team-api", "--from-template", "team", "--var", "model=anthropic/claude-sonnet")
+	if err != nil {
+		t.Fatalf("render --from-template failed: %v\n%s", err, stderr)
+	}
+	if !strings.Contains(out, `"model": "anthropic/claude-sonnet"`) {
+		t.Errorf("Unexpected rendered template: %s", out)
+	}
+
+	if _, _, err := ith.RunCommand("render", "work", "--var", "model=x"); err == nil {
+		t.Error("Expected --var without --from-template to fail")
+	}
+	if _, _, err := ith.RunCommand("render", "missing"); err == nil {
+		t.Error("Expected rendering a missing context to fail")
+	}
+}
@@ -0,0 +1,115 @@
+package test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hungthai1401/occtx/internal/context"
+)
+
+func TestValidateRequirement(t *testing.T) {
+	valid := []string{"work-vpn", "env:ANTHROPIC_API_KEY", "cmd:git", "file:~/.ssh/id_work", "tcp:proxy.corp:3128", "tcp:[::1]:8080"}
+	for _, requirement := range valid {
+		if err := context.ValidateRequirement(requirement); err != nil {
+			t.Errorf("Expected %q to be valid, got %v", requirement, err)
+		}
+	}
+
+	invalid := []string{"", "two words", "env:", "env:1BAD", "tcp:proxy.corp", "vpn:corp"}
+	for _, requirement := range invalid {
+		if err := context.ValidateRequirement(requirement); err == nil {
+			t.Errorf("Expected %q to be rejected", requirement)
+		}
+	}
+}
+
+func TestManager_Requirements(t *testing.T) {
+	th := NewTestHelper(t)
+	defer th.Cleanup()
+
+	th.CreateSampleConfig()
+	keyFile := filepath.Join(th.TempDir, "key")
+	if err := os.WriteFile(keyFile, []byte("key"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	writeOcctxConfig(t, th.ConfigDir, `{"provides": ["Work-VPN"]}`)
+
+	manager := th.CreateManagerWithTempDir()
+	manager.SetForce(true)
+	if err := manager.CreateContext("work"); err != nil {
+		t.Fatal(err)
+	}
+
+	// env:TEST_PROXY is met by the variable the context exports itself
+	if err := manager.SetContextEnv("work", map[string]string{"TEST_PROXY": "http://proxy:3128"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := manager.AddRequirements("work", []string{"work-vpn", "file:" + keyFile, "env:TEST_PROXY"}); err != nil {
+		t.Fatalf("AddRequirements failed: %v", err)
+	}
+	if err := manager.SwitchToContext("work"); err != nil {
+		t.Fatalf("Expected the requirements to be met, got %v", err)
+	}
+
+	if err := manager.AddRequirements("work", []string{"env:OCCTX_TEST_UNSET_VAR", "file:" + filepath.Join(th.TempDir, "missing")}); err != nil {
+		t.Fatal(err)
+	}
+	err := manager.SwitchToContext("work")
+	var requiresErr *context.RequirementsError
+	if !errors.As(err, &requiresErr) || len(requiresErr.Missing) != 2 {
+		t.Fatalf("Expected two unmet requirements, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "env:OCCTX_TEST_UNSET_VAR: not set") {
+		t.Errorf("Expected the unset variable to be named, got %v", err)
+	}
+
+	manager.SetSkipRequirements(true)
+	if err := manager.SwitchToContext("work"); err != nil {
+		t.Errorf("Expected the switch to skip requirements, got %v", err)
+	}
+
+	if err := manager.RemoveRequirements("work", []string{"env:OCCTX_TEST_UNSET_VAR", "cmd:nope"}); err == nil || !strings.Contains(err.Error(), "cmd:nope") {
+		t.Errorf("Expected removing an undeclared requirement to fail, got %v", err)
+	}
+	if err := manager.RemoveRequirements("work", nil); err != nil {
+		t.Fatal(err)
+	}
+	if requirements, _ := manager.GetRequirements("work"); len(requirements) != 0 {
+		t.Errorf("Expected no requirements, got %v", requirements)
+	}
+}
+
+func TestIntegration_Require(t *testing.T) {
+	ith := NewIntegrationTestHelper(t)
+	defer ith.Cleanup()
+
+	ith.CreateSampleConfig()
+	if _, _, err := ith.RunCommand("-n", "work"); err != nil {
+		t.Fatal(err)
+	}
+	if _, stderr, err := ith.RunCommand("require", "work", "work-vpn", "env:OCCTX_TEST_UNSET_VAR"); err != nil {
+		t.Fatalf("require failed: %v\n%s", err, stderr)
+	}
+
+	stdout, _, err := ith.RunCommand("require", "work")
+	if err == nil || !strings.Contains(stdout, "work-vpn") {
+		t.Errorf("Expected the check to fail and list work-vpn, got %v\n%s", err, stdout)
+	}
+	if _, stderr, err := ith.RunCommand("work"); err == nil || !strings.Contains(stderr, "--skip-requires") {
+		t.Errorf("Expected the switch to fail, got %v\n%s", err, stderr)
+	}
+	if _, stderr, err := ith.RunCommand("switch", "work", "--skip-requires"); err != nil {
+		t.Errorf("switch --skip-requires failed: %v\n%s", err, stderr)
+	}
+
+	writeOcctxConfig(t, ith.ConfigDir, `{"provides": ["work-vpn"]}`)
+	if _, stderr, err := ith.RunCommand("require", "work", "--remove", "env:OCCTX_TEST_UNSET_VAR"); err != nil {
+		t.Fatalf("require --remove failed: %v\n%s", err, stderr)
+	}
+	if _, stderr, err := ith.RunCommand("work"); err != nil {
+		t.Errorf("Expected the provided capability to satisfy the requirement, got %v\n%s", err, stderr)
+	}
+}
@@ -0,0 +1,85 @@
+package test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hungthai1401/occtx/internal/context"
+)
+
+func TestManager_ReviewSwitch(t *testing.T) {
+	th := NewTestHelper(t)
+	defer th.Cleanup()
+
+	th.CreateSampleConfig()
+	manager := th.CreateManagerWithTempDir()
+	if err := manager.CreateContext("work"); err != nil {
+		t.Fatal(err)
+	}
+	if err := manager.ImportContext("demo", []byte(`{"model": "openai/gpt-4.1", "theme": "dark"}`)); err != nil {
+		t.Fatal(err)
+	}
+	if err := manager.SwitchToContext("work"); err != nil {
+		t.Fatal(err)
+	}
+
+	var reviewed []context.ContextChange
+	approve := false
+	manager.SetReview(true)
+	manager.SetReviewHandler(func(name string, changes []context.ContextChange) bool {
+		reviewed = changes
+		return approve
+	})
+
+	var declined *context.ReviewDeclinedError
+	if err := manager.SwitchToContext("demo"); !errors.As(err, &declined) {
+		t.Fatalf("Expected the declined review to stop the switch, got %v", err)
+	}
+	if len(reviewed) == 0 || declined.Changes != len(reviewed) {
+		t.Errorf("Expected the handler to see the changes, got %v", reviewed)
+	}
+	if current, _ := manager.GetCurrentContext(); current != "work" {
+		t.Errorf("Expected 'work' to stay current, got %q", current)
+	}
+
+	approve = true
+	if err := manager.SwitchToContext("demo"); err != nil {
+		t.Fatalf("Expected the confirmed switch to go ahead: %v", err)
+	}
+
+	// A switch that changes nothing isn't reviewed
+	reviewed = nil
+	approve = false
+	if err := manager.SwitchToContext("demo"); err != nil || reviewed != nil {
+		t.Errorf("Expected no review for an unchanged active config: %v %v", err, reviewed)
+	}
+}
+
+func TestManager_ReviewFromConfig(t *testing.T) {
+	th := NewTestHelper(t)
+	defer th.Cleanup()
+
+	th.CreateSampleConfig()
+	setup := th.CreateManagerWithTempDir()
+	if err := setup.CreateContext("work"); err != nil {
+		t.Fatal(err)
+	}
+	if err := setup.ImportContext("demo", []byte(`{"model": "openai/gpt-4.1"}`)); err != nil {
+		t.Fatal(err)
+	}
+	writeOcctxConfig(t, th.ConfigDir, `{"switch": {"review": true}}`)
+
+	// Without a handler, reviewed switches are declined
+	manager := th.CreateManagerWithTempDir()
+	var declined *context.ReviewDeclinedError
+	if err := manager.SwitchToContext("demo"); !errors.As(err, &declined) {
+		t.Fatalf("Expected switch.review to require confirmation, got %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(th.ConfigDir, "opencode.json"))
+	if err != nil || strings.Contains(string(data), "gpt-4.1") {
+		t.Errorf("Expected the active config to be left alone: %v\n%s", err, data)
+	}
+}
@@ -0,0 +1,99 @@
+package test
+
+import (
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestIntegration_AllScopesAndQualifiedNames(t *testing.T) {
+	ith := NewIntegrationTestHelper(t)
+	defer ith.Cleanup()
+
+	project := filepath.Join(ith.TempDir, "project")
+	projectSettings := filepath.Join(project, "opencode", "settings")
+	globalSettings := filepath.Join(ith.ConfigDir, "settings")
+	for _, dir := range []string{projectSettings, globalSettings} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write := func(path, content string) {
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write(filepath.Join(globalSettings, "work.json"), `{"theme": "global-work"}`)
+	write(filepath.Join(projectSettings, "demo.json"), `{"theme": "project-demo"}`)
+
+	run := func(args ...string) (string, error) {
+		cmd := exec.Command(ith.BinaryPath, args...)
+		cmd.Dir = project
+		cmd.Env = ith.Env()
+		out, err := cmd.CombinedOutput()
+		return string(out), err
+	}
+
+	// Qualified names select the scope
+	if out, err := run("switch", "proj:demo"); err != nil {
+		t.Fatalf("switch proj:demo failed: %v\n%s", err, out)
+	}
+	if out, err := run("global:work"); err != nil {
+		t.Fatalf("occtx global:work failed: %v\n%s", err, out)
+	}
+	if out, _ := run("--in-project", "current"); strings.TrimSpace(out) != "demo" {
+		t.Errorf("Expected the project scope on demo, got %q", out)
+	}
+	if out, _ := run("current"); strings.TrimSpace(out) != "work" {
+		t.Errorf("Expected the global scope on work, got %q", out)
+	}
+	if out, err := run("-s", "project:demo"); err != nil || !strings.Contains(out, "project-demo") {
+		t.Errorf("Expected -s to show the project context, got %v\n%s", err, out)
+	}
+	if out, err := run("diff", "global:work", "proj:demo"); err == nil || !strings.Contains(out, "different scopes") {
+		t.Errorf("Expected mixed scopes to be rejected, got %v\n%s", err, out)
+	}
+	if out, err := run("--in-project", "global:work"); err == nil || !strings.Contains(out, "contradicts --in-project") {
+		t.Errorf("Expected a contradiction with --in-project, got %v\n%s", err, out)
+	}
+	// Only context names are qualified
+	if out, err := run("exec", "global:work", "--", "echo", "proj:demo"); err != nil || strings.TrimSpace(out) != "proj:demo" {
+		t.Errorf("Expected the command's arguments to be left alone, got %v\n%s", err, out)
+	}
+
+	out, err := run("ls", "--all-scopes")
+	if err != nil {
+		t.Fatalf("ls --all-scopes failed: %v\n%s", err, out)
+	}
+	for _, want := range []string{"Global contexts", "global:work", "Project contexts", "project:demo"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Expected %q in the listing, got:\n%s", want, out)
+		}
+	}
+
+	out, err = run("list", "--all-scopes", "-o", "json")
+	if err != nil {
+		t.Fatalf("list --all-scopes -o json failed: %v\n%s", err, out)
+	}
+	var entries []struct {
+		Name    string `json:"name"`
+		Scope   string `json:"scope"`
+		Current bool   `json:"current"`
+	}
+	if err := json.Unmarshal([]byte(out), &entries); err != nil {
+		t.Fatalf("Invalid JSON: %v\n%s", err, out)
+	}
+	scopes := map[string]string{}
+	for _, entry := range entries {
+		scopes[entry.Name] = entry.Scope
+		if !entry.Current {
+			t.Errorf("Expected %s to be current in its scope", entry.Name)
+		}
+	}
+	if scopes["work"] != "global" || scopes["demo"] != "project" {
+		t.Errorf("Expected work in global and demo in project, got %v", scopes)
+	}
+}
@@ -0,0 +1,143 @@
+package test
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+const scopedEditContext = `{
+  // Team config
+  "model": "anthropic/claude-sonnet",
+  "provider": {
+    "anthropic": {
+      // Keys come from the environment
+      "options": {"apiKey": "{env:KEY}"}
+    },
+    "openai": {"options": {}}
+  }
+}
+`
+
+func TestManager_ScopedEdit(t *testing.T) {
+	th := NewTestHelper(t)
+	defer th.Cleanup()
+
+	path := filepath.Join(th.SettingsDir, "team.jsonc")
+	if err := os.WriteFile(path, []byte(scopedEditContext), 0644); err != nil {
+		t.Fatal(err)
+	}
+	manager := th.CreateManagerWithTempDir()
+
+	edit, err := manager.BeginScopedEdit("team", "provider.anthropic")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "{\n  // Keys come from the environment\n  \"options\": {\"apiKey\": \"{env:KEY}\"}\n}"
+	if string(edit.Value) != want || !edit.JSONC {
+		t.Errorf("Unexpected extracted value:\n%s", edit.Value)
+	}
+
+	edited := strings.Replace(string(edit.Value), "{env:KEY}", "{env:ANTHROPIC_KEY}", 1)
+	changed, err := manager.FinishScopedEdit(edit, []byte(edited))
+	if err != nil || !changed {
+		t.Fatalf("FinishScopedEdit = %v, %v", changed, err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != strings.Replace(scopedEditContext, "{env:KEY}", "{env:ANTHROPIC_KEY}", 1) {
+		t.Errorf("Expected only the value to change, got:\n%s", data)
+	}
+
+	// An unchanged value writes nothing
+	edit, err = manager.BeginScopedEdit("team", "model")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if changed, err := manager.FinishScopedEdit(edit, append(edit.Value, '\n')); err != nil || changed {
+		t.Errorf("Expected no change, got %v, %v", changed, err)
+	}
+}
+
+func TestManager_ScopedEditErrors(t *testing.T) {
+	th := NewTestHelper(t)
+	defer th.Cleanup()
+
+	path := filepath.Join(th.SettingsDir, "team.jsonc")
+	if err := os.WriteFile(path, []byte(scopedEditContext), 0644); err != nil {
+		t.Fatal(err)
+	}
+	manager := th.CreateManagerWithTempDir()
+
+	for _, expr := range []string{"provider.missing", "provider.*", "model.name", ""} {
+		if _, err := manager.BeginScopedEdit("team", expr); err == nil {
+			t.Errorf("Expected key path %q to be rejected", expr)
+		}
+	}
+
+	edit, err := manager.BeginScopedEdit("team", "provider.openai")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := manager.FinishScopedEdit(edit, []byte(`{"options": `)); err == nil {
+		t.Error("Expected an invalid value to be rejected")
+	}
+
+	// The file changed while the value was being edited
+	if err := os.WriteFile(path, []byte(`{"model": "other"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := manager.FinishScopedEdit(edit, []byte(`{"options": {"timeout": 5}}`)); err == nil || !strings.Contains(err.Error(), "changed") {
+		t.Errorf("Expected a concurrent change to be detected, got %v", err)
+	}
+	if data, _ := os.ReadFile(path); string(data) != `{"model": "other"}` {
+		t.Errorf("Expected the changed file to be left alone, got %s", data)
+	}
+}
+
+func TestIntegration_EditJSONPath(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("fake editor requires a POSIX shell")
+	}
+
+	ith := NewIntegrationTestHelper(t)
+	defer ith.Cleanup()
+
+	path := filepath.Join(ith.SettingsDir, "team.jsonc")
+	if err := os.MkdirAll(ith.SettingsDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(scopedEditContext), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// The editor sees only the subtree and sets a timeout in it
+	seen := filepath.Join(ith.TempDir, "seen")
+	editor := filepath.Join(ith.TempDir, "editor.sh")
+	script := "#!/bin/sh\ncp \"$1\" " + seen + "\nprintf '{\"options\": {\"timeout\": 5}}' > \"$1\"\n"
+	if err := os.WriteFile(editor, []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := exec.Command(ith.BinaryPath, "-e", "team", "--json-path", "provider.openai")
+	cmd.Env = ith.Env("EDITOR=" + editor)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("-e --json-path failed: %v\n%s", err, out)
+	}
+
+	if data, _ := os.ReadFile(seen); strings.TrimSpace(string(data)) != `{"options": {}}` {
+		t.Errorf("Expected the editor to get only the subtree, got %q", data)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `"openai": {"options": {"timeout": 5}}`) || !strings.Contains(string(data), "// Team config") {
+		t.Errorf("Expected the subtree replaced and comments kept, got:\n%s", data)
+	}
+}
@@ -0,0 +1,117 @@
+package test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestContentIndex_Search(t *testing.T) {
+	th := NewTestHelper(t)
+	defer th.Cleanup()
+
+	settingsDir := filepath.Join(th.ConfigDir, "settings")
+	if err := os.MkdirAll(settingsDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	writeContext := func(name, content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(settingsDir, name+".json"), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeContext("work", `{"model": "openai/gpt-4.1", "provider": {"openai": {"options": {"baseURL": "http://old-proxy.internal:8080"}}}}`)
+	writeContext("home", `{"model": "anthropic/claude-sonnet-4", "provider": {"anthropic": {}}, "theme": "OpenAI-Dark"}`)
+	writeContext("broken", `{"model": `)
+
+	manager := th.CreateManagerWithTempDir()
+	index := manager.NewContentIndex()
+
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{"old-proxy", []string{"work"}},
+		{"http://old-proxy.internal", []string{"work"}}, // Not a key:text term
+		{"provider:openai", []string{"work"}},
+		{"provider:anthropic", []string{"home"}},
+		{"openai", []string{"home", "work"}}, // Free text also matches values elsewhere
+		{"model:claude theme:dark", []string{"home"}},
+		{"model:claude provider:openai", nil},
+		{"PROVIDER:OpenAI", []string{"work"}},
+		{"nothing-like-this", nil},
+	}
+	for _, tt := range tests {
+		matches, err := index.Search(tt.query)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got []string
+		for _, match := range matches {
+			got = append(got, match.Context)
+		}
+		if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+			t.Errorf("Search(%q) = %v, want %v", tt.query, got, tt.want)
+		}
+	}
+
+	matches, err := index.Search("baseurl:old-proxy")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(matches) != 1 || strings.Join(matches[0].Paths, ",") != "provider.openai.options.baseURL" {
+		t.Errorf("Expected the matching path, got %+v", matches)
+	}
+
+	// Changed and removed files are picked up on the next search
+	writeContext("home", `{"model": "anthropic/claude-sonnet-4", "provider": {"ollama": {"options": {"baseURL": "http://old-proxy.internal:8080"}}}}`)
+	future := time.Now().Add(time.Minute)
+	os.Chtimes(filepath.Join(settingsDir, "home.json"), future, future)
+	os.Remove(filepath.Join(settingsDir, "work.json"))
+
+	found, err := index.Matches("old-proxy")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(found) != 1 || !found["home"] {
+		t.Errorf("Expected the refreshed index to find only 'home', got %v", found)
+	}
+}
+
+func TestIntegration_Search(t *testing.T) {
+	ith := NewIntegrationTestHelper(t)
+	defer ith.Cleanup()
+
+	for name, content := range map[string]string{
+		"work": `{"provider": {"openai": {}}}`,
+		"home": `{"provider": {"anthropic": {}}}`,
+	} {
+		if err := os.WriteFile(filepath.Join(ith.SettingsDir, name+".json"), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	stdout, _, err := ith.RunCommand("search", "provider:openai")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(stdout, "work") || strings.Contains(stdout, "home") {
+		t.Errorf("Unexpected search output:\n%s", stdout)
+	}
+
+	// The picker's list matches names too, and lists everything for an empty query
+	stdout, _, err = ith.RunCommand("search", "--names", "hom")
+	if err != nil || strings.TrimSpace(stdout) != "home" {
+		t.Errorf("Expected 'home', got %q, %v", stdout, err)
+	}
+	stdout, _, err = ith.RunCommand("search", "--names", "")
+	if err != nil || strings.TrimSpace(stdout) != "home\nwork" {
+		t.Errorf("Expected every context, got %q, %v", stdout, err)
+	}
+
+	if _, _, err := ith.RunCommand("search"); err == nil {
+		t.Error("Expected an error without a query")
+	}
+}
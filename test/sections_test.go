@@ -0,0 +1,78 @@
+package test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestIntegration_ApplySections(t *testing.T) {
+	ith := NewIntegrationTestHelper(t)
+	defer ith.Cleanup()
+
+	ith.CreateSampleConfig()
+	if _, stderr, err := ith.RunCommand("-n", "work"); err != nil {
+		t.Fatalf("-n failed: %v\n%s", err, stderr)
+	}
+	if _, stderr, err := ith.RunCommand("work"); err != nil {
+		t.Fatalf("switch failed: %v\n%s", err, stderr)
+	}
+	review := `{"theme": "tokyonight", "agent": {"review": {"model": "openai/gpt-4.1"}}}`
+	if _, stderr, err := ith.RunCommandWithInput(review, "--import", "review"); err != nil {
+		t.Fatalf("--import failed: %v\n%s", err, stderr)
+	}
+
+	activeConfigPath := filepath.Join(ith.ConfigDir, "opencode.json")
+	before, err := os.ReadFile(activeConfigPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	out, stderr, err := ith.RunCommand("apply", "review", "--only", "agent", "--dry-run")
+	if err != nil {
+		t.Fatalf("apply --dry-run failed: %v\n%s", err, stderr)
+	}
+	if !strings.Contains(out, "Plan: apply 'review'") || !strings.Contains(out, "+ agent.review") {
+		t.Errorf("Unexpected plan:\n%s", out)
+	}
+	if after, _ := os.ReadFile(activeConfigPath); string(after) != string(before) {
+		t.Error("Expected --dry-run to leave the active config alone")
+	}
+
+	if _, stderr, err := ith.RunCommand("apply", "review", "--only", "agent"); err != nil {
+		t.Fatalf("apply failed: %v\n%s", err, stderr)
+	}
+	data, err := os.ReadFile(activeConfigPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var active map[string]interface{}
+	if err := json.Unmarshal(data, &active); err != nil {
+		t.Fatal(err)
+	}
+	agents, _ := active["agent"].(map[string]interface{})
+	if _, ok := agents["review"]; !ok || len(agents) != 1 {
+		t.Errorf("Expected the agents of 'review', got %v", active["agent"])
+	}
+	if active["theme"] != "default" || active["provider"] == nil {
+		t.Errorf("Expected the rest of the active config to be kept, got %v", active)
+	}
+
+	// The current context stays, and the applied config isn't drift
+	out, stderr, _ = ith.RunCommand("-c")
+	if strings.TrimSpace(out) != "work" {
+		t.Errorf("Expected 'work' to stay current, got %q", out)
+	}
+	if strings.Contains(stderr, "modified") {
+		t.Errorf("Expected no drift warning after apply, got %q", stderr)
+	}
+
+	if _, _, err := ith.RunCommand("apply", "review", "--only", "mcp"); err == nil {
+		t.Error("Expected a section the context lacks to fail")
+	}
+	if _, _, err := ith.RunCommand("apply", "review"); err == nil {
+		t.Error("Expected apply without --only to fail")
+	}
+}
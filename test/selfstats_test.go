@@ -0,0 +1,125 @@
+package test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestManager_SelfStats(t *testing.T) {
+	th := NewTestHelper(t)
+	defer th.Cleanup()
+
+	th.CreateSampleConfig()
+	manager := th.CreateManagerWithTempDir()
+
+	if stats, err := manager.LoadSelfStats(); err != nil || len(stats.Commands) != 0 {
+		t.Fatalf("Expected no statistics yet, got %v (%v)", stats, err)
+	}
+
+	for _, run := range []struct {
+		command string
+		elapsed time.Duration
+		failed  bool
+	}{
+		{"switch", 2 * time.Millisecond, false},
+		{"switch", 4 * time.Millisecond, true},
+		{"list", time.Millisecond, false},
+	} {
+		if err := manager.RecordCommand(run.command, run.elapsed, run.failed); err != nil {
+			t.Fatalf("RecordCommand failed: %v", err)
+		}
+	}
+
+	stats, err := manager.LoadSelfStats()
+	if err != nil {
+		t.Fatal(err)
+	}
+	switchStats := stats.Commands["switch"]
+	if switchStats == nil || switchStats.Count != 2 || switchStats.Failures != 1 {
+		t.Fatalf("Expected 2 switches with 1 failure, got %+v", switchStats)
+	}
+	if switchStats.Mean() != 3*time.Millisecond || time.Duration(switchStats.Max) != 4*time.Millisecond {
+		t.Errorf("Expected mean 3ms and max 4ms, got %s and %s", switchStats.Mean(), time.Duration(switchStats.Max))
+	}
+	if got := stats.SortedCommands(); len(got) != 2 || got[0] != "switch" {
+		t.Errorf("Expected switch first, got %v", got)
+	}
+
+	// Durations are stored readably
+	data, err := os.ReadFile(filepath.Join(th.ConfigDir, ".occtx-stats.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var raw map[string]interface{}
+	if err := json.Unmarshal(data, &raw); err != nil || !strings.Contains(string(data), `"max": "4ms"`) {
+		t.Errorf("Expected a readable statistics file, got %v\n%s", err, data)
+	}
+
+	if err := manager.ResetSelfStats(); err != nil {
+		t.Fatal(err)
+	}
+	if stats, _ := manager.LoadSelfStats(); len(stats.Commands) != 0 {
+		t.Errorf("Expected reset statistics, got %v", stats.Commands)
+	}
+
+	writeOcctxConfig(t, th.ConfigDir, `{"stats": {"disabled": true}}`)
+	disabled := th.CreateManagerWithTempDir()
+	if err := disabled.RecordCommand("switch", time.Millisecond, false); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(th.ConfigDir, ".occtx-stats.json")); !os.IsNotExist(err) {
+		t.Error("Expected nothing to be recorded with stats.disabled")
+	}
+}
+
+func TestIntegration_StatsSelf(t *testing.T) {
+	ith := NewIntegrationTestHelper(t)
+	defer ith.Cleanup()
+
+	ith.CreateSampleConfig()
+	for _, args := range [][]string{{"-n", "work"}, {"work"}, {"switch", "work"}, {"nosuch"}, {"tmux-status"}, {"stats"}} {
+		ith.RunCommand(args...)
+	}
+
+	stdout, stderr, err := ith.RunCommand("stats", "--self", "-o", "json")
+	if err != nil {
+		t.Fatalf("stats --self failed: %v\n%s", err, stderr)
+	}
+	var stats struct {
+		Commands map[string]struct {
+			Count    int `json:"count"`
+			Failures int `json:"failures"`
+		} `json:"commands"`
+	}
+	if err := json.Unmarshal([]byte(stdout), &stats); err != nil {
+		t.Fatalf("Invalid JSON: %v\n%s", err, stdout)
+	}
+	if got := stats.Commands["switch"]; got.Count != 3 || got.Failures != 1 {
+		t.Errorf("Expected 3 switches with 1 failure, got %+v", got)
+	}
+	if got := stats.Commands["new"]; got.Count != 1 {
+		t.Errorf("Expected 1 new, got %+v", got)
+	}
+	if _, ok := stats.Commands["tmux-status"]; ok {
+		t.Error("tmux-status should not be recorded")
+	}
+
+	stdout, _, err = ith.RunCommand("stats")
+	if err != nil || !strings.Contains(stdout, "work") {
+		t.Errorf("Expected the switches of 'work', got %v\n%s", err, stdout)
+	}
+
+	if _, stderr, err := ith.RunCommand("stats", "--reset"); err == nil || !strings.Contains(stderr, "--self") {
+		t.Errorf("Expected --reset to need --self, got %v\n%s", err, stderr)
+	}
+	if _, _, err := ith.RunCommand("stats", "--self", "--reset"); err != nil {
+		t.Fatal(err)
+	}
+	if stdout, _, _ := ith.RunCommand("stats", "--self"); !strings.Contains(stdout, "No commands recorded") {
+		t.Errorf("Expected reset statistics, got %s", stdout)
+	}
+}
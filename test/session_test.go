@@ -0,0 +1,123 @@
+package test
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/hungthai1401/occtx/internal/context"
+)
+
+func TestManager_SessionConfig(t *testing.T) {
+	th := NewTestHelper(t)
+	defer th.Cleanup()
+
+	th.CreateSampleConfig()
+	manager := th.CreateManagerWithTempDir()
+
+	if err := manager.CreateContextWithFormat("work", context.FormatJSONC); err != nil {
+		t.Fatal(err)
+	}
+
+	session, err := manager.NewSessionConfig("work")
+	if err != nil {
+		t.Fatalf("NewSessionConfig failed: %v", err)
+	}
+
+	info, err := os.Stat(session.Path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if runtime.GOOS != "windows" && info.Mode().Perm() != 0600 {
+		t.Errorf("Session config should be private, got %v", info.Mode().Perm())
+	}
+
+	// JSONC contexts are written as plain JSON
+	data, _ := os.ReadFile(session.Path)
+	if strings.HasPrefix(strings.TrimSpace(string(data)), "//") {
+		t.Error("Session config should not contain JSONC comments")
+	}
+
+	env := strings.Join(session.Env(), "\n")
+	if !strings.Contains(env, "OCCTX_ACTIVE_CONTEXT=work") || !strings.Contains(env, "OPENCODE_CONFIG="+session.Path) {
+		t.Errorf("Unexpected session env: %s", env)
+	}
+
+	// The global state is untouched
+	if current, _ := manager.GetCurrentContext(); current != "" {
+		t.Errorf("Session must not change the global current context, got %q", current)
+	}
+
+	if err := session.Remove(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(session.Path); !os.IsNotExist(err) {
+		t.Error("Expected session config to be removed")
+	}
+}
+
+func TestManager_EffectiveCurrentContext(t *testing.T) {
+	th := NewTestHelper(t)
+	defer th.Cleanup()
+
+	th.CreateSampleConfig()
+	manager := th.CreateManagerWithTempDir()
+	manager.CreateContext("work")
+	manager.SwitchToContext("work")
+
+	t.Setenv(context.SessionContextEnvVar, "")
+	if name, session, _ := manager.EffectiveCurrentContext(); name != "work" || session {
+		t.Errorf("EffectiveCurrentContext() = %q, %v; want global 'work'", name, session)
+	}
+
+	t.Setenv(context.SessionContextEnvVar, "personal")
+	if name, session, _ := manager.EffectiveCurrentContext(); name != "personal" || !session {
+		t.Errorf("EffectiveCurrentContext() = %q, %v; want session 'personal'", name, session)
+	}
+}
+
+func TestIntegration_Exec(t *testing.T) {
+	// The commands run through exec use a POSIX shell
+	if runtime.GOOS == "windows" {
+		t.Skip("exec test commands require a POSIX shell")
+	}
+
+	ith := NewIntegrationTestHelper(t)
+	defer ith.Cleanup()
+
+	if _, _, err := ith.RunCommandWithInput(`{"theme": "session-theme"}`, "--import", "work"); err != nil {
+		t.Fatal(err)
+	}
+
+	stdout, stderr, err := ith.RunCommand("exec", "work", "--", "sh", "-c", `cat "$OPENCODE_CONFIG"; echo "$OCCTX_ACTIVE_CONTEXT"`)
+	if err != nil {
+		t.Fatalf("exec failed: %v\n%s", err, stderr)
+	}
+	if !strings.Contains(stdout, "session-theme") || !strings.HasSuffix(strings.TrimSpace(stdout), "work") {
+		t.Errorf("Expected the command to see the session config, got %s", stdout)
+	}
+
+	// The global active config was not written
+	if _, err := os.Stat(filepath.Join(ith.ConfigDir, "opencode.json")); !os.IsNotExist(err) {
+		t.Error("exec must not write the global active config")
+	}
+
+	// The command's exit code is passed through
+	_, _, err = ith.RunCommand("exec", "work", "--", "sh", "-c", "exit 3")
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) || exitErr.ExitCode() != 3 {
+		t.Errorf("Expected exit code 3, got %v", err)
+	}
+
+	// current honors the session variable
+	cmd := exec.Command(ith.BinaryPath, "current")
+	cmd.Env = ith.Env("OCCTX_ACTIVE_CONTEXT=work")
+	out, err := cmd.Output()
+	if err != nil || strings.TrimSpace(string(out)) != "work" {
+		t.Errorf("Expected current to report the session context, got %q (%v)", out, err)
+	}
+}
@@ -0,0 +1,164 @@
+package test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hungthai1401/occtx/internal/context"
+)
+
+func TestManager_SettingsRoundTrip(t *testing.T) {
+	source := NewTestHelper(t)
+	defer source.Cleanup()
+
+	source.CreateSampleConfig()
+	configJSON := `{"names": {"max_length": 32}, "backup": {"keep": 3}}`
+	if err := os.WriteFile(filepath.Join(source.ConfigDir, ".occtx-config.json"), []byte(configJSON), 0644); err != nil {
+		t.Fatal(err)
+	}
+	manager := source.CreateManagerWithTempDir()
+	for _, name := range []string{"work", "scratch"} {
+		if err := manager.CreateContext(name); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := manager.SetDefaultContext("work"); err != nil {
+		t.Fatal(err)
+	}
+
+	bundle, err := manager.ExportSettings()
+	if err != nil {
+		t.Fatalf("ExportSettings failed: %v", err)
+	}
+	if bundle.Default != "work" || len(bundle.Contexts) != 2 || len(bundle.Config) == 0 {
+		t.Fatalf("Incomplete bundle: %+v", bundle)
+	}
+
+	data, err := json.Marshal(bundle)
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsed, err := context.ParseSettingsBundle(data)
+	if err != nil {
+		t.Fatalf("ParseSettingsBundle failed: %v", err)
+	}
+
+	// A new machine that has restored only 'work'
+	target := NewTestHelper(t)
+	defer target.Cleanup()
+
+	target.CreateSampleConfig()
+	restored := target.CreateManagerWithTempDir()
+	if err := restored.ImportContext("work", []byte(`{"theme": "dark"}`)); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := restored.ImportSettings(parsed, false)
+	if err != nil {
+		t.Fatalf("ImportSettings failed: %v", err)
+	}
+	if !result.ConfigWritten || result.Default != "work" || result.Metadata != 1 {
+		t.Errorf("Unexpected result %+v", result)
+	}
+	if len(result.Skipped) != 1 || result.Skipped[0] != "scratch" {
+		t.Errorf("Expected 'scratch' to be skipped, got %v", result.Skipped)
+	}
+
+	if name, _ := restored.GetDefaultContext(); name != "work" {
+		t.Errorf("Expected the default to be restored, got %q", name)
+	}
+	written, err := os.ReadFile(filepath.Join(target.ConfigDir, ".occtx-config.json"))
+	if err != nil || !strings.Contains(string(written), `"max_length": 32`) {
+		t.Errorf("Expected the occtx config to be restored, got %q (%v)", written, err)
+	}
+	meta, _ := restored.GetContextMeta("work")
+	if meta == nil || meta.Source.Kind != context.SourceActive {
+		t.Errorf("Expected the exported metadata for 'work', got %+v", meta)
+	}
+
+	// Importing the same bundle again leaves the identical config alone;
+	// a different local config needs --force
+	if _, err := restored.ImportSettings(parsed, false); err != nil {
+		t.Errorf("Re-importing the same bundle failed: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(target.ConfigDir, ".occtx-config.json"), []byte(`{"backup": {"keep": 1}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := restored.ImportSettings(parsed, false); err == nil || !strings.Contains(err.Error(), "--force") {
+		t.Errorf("Expected a differing config to be kept without force, got %v", err)
+	}
+	if _, err := restored.ImportSettings(parsed, true); err != nil {
+		t.Errorf("ImportSettings with force failed: %v", err)
+	}
+}
+
+func TestParseSettingsBundle_Invalid(t *testing.T) {
+	for _, input := range []string{
+		`not json`,
+		`{"config": {}}`,
+		`{"version": 99}`,
+		`{"version": 1, "config": {"names": "oops"}}`,
+		// Metadata that the env, requires and tag commands would refuse
+		`{"version": 1, "contexts": {"work": {"env": {"X=1;touch /tmp/pwned;Y": "v"}}}}`,
+		`{"version": 1, "contexts": {"work": {"env": {"OCCTX_ENV_KEYS": "v"}}}}`,
+		`{"version": 1, "contexts": {"work": {"requires": ["tcp:nowhere"]}}}`,
+		`{"version": 1, "contexts": {"work": {"tags": {"a=b": "c"}}}}`,
+		`{"version": 1, "contexts": {"../work": {}}}`,
+	} {
+		if _, err := context.ParseSettingsBundle([]byte(input)); err == nil {
+			t.Errorf("Expected %s to be rejected", input)
+		}
+	}
+}
+
+func TestIntegration_Settings(t *testing.T) {
+	ith := NewIntegrationTestHelper(t)
+	defer ith.Cleanup()
+
+	ith.CreateSampleConfig()
+	if _, _, err := ith.RunCommand("-n", "work"); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := ith.RunCommand("default", "set", "work"); err != nil {
+		t.Fatal(err)
+	}
+	configPath := filepath.Join(ith.ConfigDir, ".occtx-config.json")
+	if err := os.WriteFile(configPath, []byte(`{"backup": {"keep": 4}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	bundlePath := filepath.Join(ith.TempDir, "settings.json")
+	if _, stderr, err := ith.RunCommand("settings", "export", "-o", bundlePath); err != nil {
+		t.Fatalf("settings export failed: %v\n%s", err, stderr)
+	}
+
+	// Lose the config and pin, then restore them from stdin
+	if err := os.Remove(configPath); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := ith.RunCommand("default", "unset"); err != nil {
+		t.Fatal(err)
+	}
+	bundle, err := os.ReadFile(bundlePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout, stderr, err := ith.RunCommandWithInput(string(bundle), "settings", "import")
+	if err != nil {
+		t.Fatalf("settings import failed: %v\n%s", err, stderr)
+	}
+	if !strings.Contains(stdout, "Restored occtx config") || !strings.Contains(stdout, "Default context set to 'work'") {
+		t.Errorf("Unexpected import output:\n%s", stdout)
+	}
+
+	stdout, _, _ = ith.RunCommand("default")
+	if strings.TrimSpace(stdout) != "work" {
+		t.Errorf("Expected the default to be restored, got %q", stdout)
+	}
+	if data, err := os.ReadFile(configPath); err != nil || !strings.Contains(string(data), `"keep": 4`) {
+		t.Errorf("Expected the config to be restored, got %q (%v)", data, err)
+	}
+}
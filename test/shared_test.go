@@ -0,0 +1,163 @@
+package test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// createSharedDir writes a shared contexts dir with one context and points the occtx config at it
+func createSharedDir(t *testing.T, tempDir, configDir string) string {
+	sharedDir := filepath.Join(tempDir, "shared")
+	if err := os.MkdirAll(sharedDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(sharedDir, "baseline.jsonc"), []byte("// managed by IT\n{\"theme\": \"corp\"}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	writeOcctxConfig(t, configDir, `{"shared_dirs": ["`+filepath.ToSlash(sharedDir)+`"]}`)
+	return sharedDir
+}
+
+func TestManager_SharedContexts(t *testing.T) {
+	th := NewTestHelper(t)
+	defer th.Cleanup()
+
+	th.CreateSampleConfig()
+	sharedDir := createSharedDir(t, th.TempDir, th.ConfigDir)
+	manager := th.CreateManagerWithTempDir()
+
+	manager.CreateContext("local")
+
+	contexts, err := manager.ListContexts()
+	if err != nil {
+		t.Fatalf("ListContexts failed: %v", err)
+	}
+	shared := map[string]bool{}
+	for _, ctx := range contexts {
+		shared[ctx.Name] = ctx.Shared
+	}
+	if len(shared) != 2 || !shared["baseline"] || shared["local"] {
+		t.Errorf("Unexpected listing: %v", shared)
+	}
+
+	if err := manager.SwitchToContext("baseline"); err != nil {
+		t.Fatalf("Switch to shared context failed: %v", err)
+	}
+
+	if err := manager.SwitchToContext("local"); err != nil {
+		t.Fatal(err)
+	}
+	if err := manager.DeleteContext("baseline"); err == nil || !strings.Contains(err.Error(), "shared read-only") {
+		t.Errorf("Expected shared delete to fail, got %v", err)
+	}
+	if err := manager.RenameContext("baseline", "other"); err == nil {
+		t.Error("Expected shared rename to fail")
+	}
+	if _, err := os.Stat(filepath.Join(sharedDir, "baseline.jsonc")); err != nil {
+		t.Error("Shared context should be untouched")
+	}
+
+	copied, err := manager.CopyContext("baseline", "")
+	if err != nil {
+		t.Fatalf("CopyContext failed: %v", err)
+	}
+	if copied.FilePath != filepath.Join(th.SettingsDir, "baseline.jsonc") {
+		t.Errorf("Unexpected copy path: %s", copied.FilePath)
+	}
+	data, _ := os.ReadFile(copied.FilePath)
+	if !strings.Contains(string(data), "// managed by IT") {
+		t.Error("Copy should preserve content byte for byte")
+	}
+
+	// The local copy now shadows the shared one
+	ctx, err := manager.GetContext("baseline")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ctx.Shared {
+		t.Error("Local copy should take precedence over the shared context")
+	}
+
+	if _, err := manager.CopyContext("local", "baseline"); err == nil {
+		t.Error("Expected error copying onto an existing context")
+	}
+}
+
+func TestIntegration_SharedContexts(t *testing.T) {
+	ith := NewIntegrationTestHelper(t)
+	defer ith.Cleanup()
+
+	ith.CreateSampleConfig()
+	createSharedDir(t, ith.TempDir, ith.ConfigDir)
+
+	stdout, _, err := ith.RunCommand()
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	if !strings.Contains(stdout, "baseline 🔒 (shared)") {
+		t.Errorf("Expected shared marker in listing, got: %s", stdout)
+	}
+
+	// The sample active config was never saved as a context, so it must be overwritten explicitly
+	if _, _, err := ith.RunCommand("baseline", "--force"); err != nil {
+		t.Fatalf("Switch to shared context failed: %v", err)
+	}
+
+	if _, _, err := ith.RunCommand("copy", "baseline", "mine"); err != nil {
+		t.Fatalf("copy failed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(ith.SettingsDir, "mine.jsonc")); err != nil {
+		t.Error("Expected local copy in settings dir")
+	}
+}
+
+func TestIntegration_MirroredContexts(t *testing.T) {
+	ith := NewIntegrationTestHelper(t)
+	defer ith.Cleanup()
+
+	ith.CreateSampleConfig()
+	mirrorDir := filepath.Join(ith.TempDir, "dropbox", "team")
+	if err := os.MkdirAll(mirrorDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(mirrorDir, "platform.json"), []byte(`{"theme": "team"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	missingDir := filepath.Join(ith.TempDir, "unmounted")
+	writeOcctxConfig(t, ith.ConfigDir, `{"shared_dirs": [], "mirrors": [
+		{"name": "gone", "dir": "`+filepath.ToSlash(missingDir)+`"},
+		{"name": "team", "dir": "`+filepath.ToSlash(mirrorDir)+`"}
+	]}`)
+
+	stdout, _, err := ith.RunCommand()
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	if !strings.Contains(stdout, "platform 🔒 (team)") {
+		t.Errorf("Expected the mirror badge in the listing, got: %s", stdout)
+	}
+
+	stdout, _, err = ith.RunCommand("-o", "json")
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	if !strings.Contains(stdout, `"mirror": "team"`) {
+		t.Errorf("Expected the mirror in the JSON listing, got: %s", stdout)
+	}
+
+	if _, _, err := ith.RunCommand("platform", "--force"); err != nil {
+		t.Fatalf("Switch to a mirrored context failed: %v", err)
+	}
+	if _, _, err := ith.RunCommand("-d", "platform"); err == nil {
+		t.Error("Expected deleting a mirrored context to fail")
+	}
+	if _, _, err := ith.RunCommand("copy", "platform", "mine"); err != nil {
+		t.Fatalf("copy failed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(ith.SettingsDir, "mine.json")); err != nil {
+		t.Error("Expected a local copy in the settings dir")
+	}
+}
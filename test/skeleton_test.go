@@ -0,0 +1,107 @@
+package test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hungthai1401/occtx/internal/context"
+)
+
+func TestSkeleton(t *testing.T) {
+	tests := []struct {
+		name      string
+		opts      context.SkeletonOptions
+		wantModel string
+		wantErr   bool
+	}{
+		{"bare", context.SkeletonOptions{}, "", false},
+		{"provider default model", context.SkeletonOptions{Provider: "anthropic"}, "anthropic/claude-sonnet-4-20250514", false},
+		{"provider and model", context.SkeletonOptions{Provider: "anthropic", Model: "claude-sonnet"}, "anthropic/claude-sonnet", false},
+		{"prefixed model", context.SkeletonOptions{Provider: "openai", Model: "openai/gpt-4o"}, "openai/gpt-4o", false},
+		{"qualified model only", context.SkeletonOptions{Model: "google/gemini-2.5-flash"}, "google/gemini-2.5-flash", false},
+		{"unqualified model only", context.SkeletonOptions{Model: "gpt-4o"}, "", true},
+		{"unknown provider without model", context.SkeletonOptions{Provider: "acme"}, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := context.Skeleton(tt.opts)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Skeleton(%+v) error = %v, wantErr %v", tt.opts, err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if data["$schema"] != "https://opencode.ai/config.json" {
+				t.Errorf("Expected $schema in skeleton, got %v", data)
+			}
+			model, _ := data["model"].(string)
+			if model != tt.wantModel {
+				t.Errorf("model = %q, want %q", model, tt.wantModel)
+			}
+		})
+	}
+}
+
+func TestManager_CreateContextFromSkeleton(t *testing.T) {
+	th := NewTestHelper(t)
+	defer th.Cleanup()
+
+	// No active config is needed
+	manager := th.CreateManagerWithTempDir()
+
+	opts := context.SkeletonOptions{Provider: "anthropic", Model: "claude-sonnet"}
+	if err := manager.CreateContextFromSkeleton("fresh", context.FormatJSON, opts); err != nil {
+		t.Fatalf("CreateContextFromSkeleton failed: %v", err)
+	}
+
+	ctx, err := manager.GetContext("fresh")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ctx.Data["model"] != "anthropic/claude-sonnet" {
+		t.Errorf("Unexpected skeleton data: %v", ctx.Data)
+	}
+
+	meta, _ := manager.GetContextMeta("fresh")
+	if meta == nil || meta.Source.Kind != context.SourceSkeleton {
+		t.Errorf("Expected skeleton source, got %+v", meta)
+	}
+
+	if err := manager.CreateContextFromSkeleton("fresh", context.FormatJSON, context.SkeletonOptions{}); err == nil {
+		t.Error("Expected error creating a skeleton over an existing context")
+	}
+}
+
+func TestIntegration_CreateEmpty(t *testing.T) {
+	ith := NewIntegrationTestHelper(t)
+	defer ith.Cleanup()
+
+	// Without --empty, creating needs an active config
+	if _, _, err := ith.RunCommand("-n", "work"); err == nil {
+		t.Fatal("Expected -n to fail without an active config")
+	}
+
+	if _, stderr, err := ith.RunCommand("-n", "work", "--empty", "--provider", "anthropic", "--model", "claude-sonnet"); err != nil {
+		t.Fatalf("-n --empty failed: %v\n%s", err, stderr)
+	}
+
+	data, err := os.ReadFile(filepath.Join(ith.SettingsDir, "work.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var cfg map[string]interface{}
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		t.Fatalf("Skeleton is not valid JSON: %v", err)
+	}
+	if cfg["model"] != "anthropic/claude-sonnet" {
+		t.Errorf("Unexpected skeleton: %s", data)
+	}
+
+	if _, stderr, err := ith.RunCommand("-n", "other", "--model", "x"); err == nil || !strings.Contains(stderr, "require --empty") {
+		t.Errorf("Expected --model without --empty to fail, got err=%v stderr=%s", err, stderr)
+	}
+}
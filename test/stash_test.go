@@ -0,0 +1,185 @@
+package test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestManager_StashSaveAndPop(t *testing.T) {
+	th := NewTestHelper(t)
+	defer th.Cleanup()
+
+	th.CreateSampleConfig()
+	manager := th.CreateManagerWithTempDir()
+
+	if err := manager.CreateContext("work"); err != nil {
+		t.Fatalf("CreateContext failed: %v", err)
+	}
+	if err := manager.SwitchToContext("work"); err != nil {
+		t.Fatalf("SwitchToContext failed: %v", err)
+	}
+
+	// Modify the active config to simulate an experiment
+	activeConfigPath := filepath.Join(th.ConfigDir, "opencode.json")
+	experiment := []byte(`{"theme": "experiment"}`)
+	if err := os.WriteFile(activeConfigPath, experiment, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	entry, err := manager.StashSave("try-theme")
+	if err != nil {
+		t.Fatalf("StashSave failed: %v", err)
+	}
+	if entry.Context != "work" {
+		t.Errorf("Expected stash context 'work', got '%s'", entry.Context)
+	}
+
+	// Active config should be restored from the current context
+	ctx, err := manager.GetContext("work")
+	if err != nil {
+		t.Fatalf("GetContext failed: %v", err)
+	}
+	contextData, _ := os.ReadFile(ctx.FilePath)
+	activeData, _ := os.ReadFile(activeConfigPath)
+	if string(activeData) != string(contextData) {
+		t.Error("Active config was not restored from current context")
+	}
+
+	entries, err := manager.ListStashes()
+	if err != nil {
+		t.Fatalf("ListStashes failed: %v", err)
+	}
+	if len(entries) != 1 || entries[0].Name != "try-theme" {
+		t.Fatalf("Expected one stash 'try-theme', got %v", entries)
+	}
+
+	if _, err := manager.PopStash(""); err != nil {
+		t.Fatalf("PopStash failed: %v", err)
+	}
+
+	activeData, _ = os.ReadFile(activeConfigPath)
+	if string(activeData) != string(experiment) {
+		t.Error("Active config was not restored from stash")
+	}
+
+	entries, _ = manager.ListStashes()
+	if len(entries) != 0 {
+		t.Errorf("Expected no stashes after pop, got %d", len(entries))
+	}
+}
+
+func TestManager_StashRefs(t *testing.T) {
+	th := NewTestHelper(t)
+	defer th.Cleanup()
+
+	th.CreateSampleConfig()
+	manager := th.CreateManagerWithTempDir()
+
+	for _, name := range []string{"first", "second", "third"} {
+		if _, err := manager.StashSave(name); err != nil {
+			t.Fatalf("StashSave(%s) failed: %v", name, err)
+		}
+	}
+
+	if _, err := manager.StashSave("second"); err == nil {
+		t.Error("Expected error for duplicate stash name")
+	}
+
+	tests := []struct {
+		ref      string
+		expected string
+	}{
+		{"", "third"},
+		{"1", "second"},
+		{"stash@{2}", "first"},
+		{"second", "second"},
+	}
+
+	for _, tt := range tests {
+		entry, err := manager.ApplyStash(tt.ref)
+		if err != nil {
+			t.Errorf("ApplyStash(%q) failed: %v", tt.ref, err)
+			continue
+		}
+		if entry.Name != tt.expected {
+			t.Errorf("ApplyStash(%q) = %s, want %s", tt.ref, entry.Name, tt.expected)
+		}
+	}
+
+	if _, err := manager.DropStash("stash@{5}"); err == nil {
+		t.Error("Expected error for out-of-range stash ref")
+	}
+
+	if _, err := manager.DropStash("second"); err != nil {
+		t.Fatalf("DropStash failed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(th.SettingsDir, "stash", "second.json")); !os.IsNotExist(err) {
+		t.Error("Stash file was not removed")
+	}
+
+	// Stash directory must not show up as a context
+	contexts, err := manager.ListContexts()
+	if err != nil {
+		t.Fatalf("ListContexts failed: %v", err)
+	}
+	if len(contexts) != 0 {
+		t.Errorf("Expected no contexts, got %d", len(contexts))
+	}
+}
+
+func TestManager_StashWithoutActiveConfig(t *testing.T) {
+	th := NewTestHelper(t)
+	defer th.Cleanup()
+
+	manager := th.CreateManagerWithTempDir()
+
+	if _, err := manager.StashSave(""); err == nil {
+		t.Error("Expected error when no active config exists")
+	}
+
+	if _, err := manager.PopStash(""); err == nil {
+		t.Error("Expected error when no stashes exist")
+	}
+}
+
+func TestManager_StashKeepsJSONC(t *testing.T) {
+	th := NewTestHelper(t)
+	defer th.Cleanup()
+
+	jsoncPath := filepath.Join(th.ConfigDir, "opencode.jsonc")
+	experiment := "// Trying a theme\n{\"theme\": \"experiment\"}\n"
+	if err := os.WriteFile(jsoncPath, []byte(experiment), 0644); err != nil {
+		t.Fatal(err)
+	}
+	manager := th.CreateManagerWithTempDir()
+
+	entry, err := manager.StashSave("try-theme")
+	if err != nil {
+		t.Fatalf("StashSave failed: %v", err)
+	}
+	if filepath.Ext(entry.File) != ".jsonc" {
+		t.Errorf("Expected a .jsonc stash file, got %s", entry.File)
+	}
+
+	// Meanwhile the active config became plain JSON
+	if err := os.Remove(jsoncPath); err != nil {
+		t.Fatal(err)
+	}
+	jsonPath := filepath.Join(th.ConfigDir, "opencode.json")
+	if err := os.WriteFile(jsonPath, []byte(`{"theme": "default"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	manager = th.CreateManagerWithTempDir()
+	if _, err := manager.ApplyStash("try-theme"); err != nil {
+		t.Fatalf("ApplyStash failed: %v", err)
+	}
+	data, err := os.ReadFile(jsoncPath)
+	if err != nil || string(data) != experiment {
+		t.Errorf("Expected the stash restored as JSONC with its comments, got %q, %v", data, err)
+	}
+	if _, err := os.Stat(jsonPath); !os.IsNotExist(err) {
+		t.Error("Expected opencode.json to be replaced by opencode.jsonc")
+	}
+}
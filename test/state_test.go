@@ -2,8 +2,13 @@ package test
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
 	"testing"
 
 	"github.com/hungthai1401/occtx/internal/context"
@@ -249,3 +254,266 @@ func TestState_AtomicSave(t *testing.T) {
 		t.Errorf("Expected 'initial', got '%s'", loadedState.Current)
 	}
 }
+
+func TestState_ConcurrentModification(t *testing.T) {
+	stateFile := filepath.Join(t.TempDir(), "state.json")
+	if err := (&context.State{Current: "base"}).SaveState(stateFile); err != nil {
+		t.Fatal(err)
+	}
+
+	first, err := context.LoadState(stateFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := context.LoadState(stateFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	first.SetCurrent("first")
+	if err := first.SaveState(stateFile); err != nil {
+		t.Fatalf("First save failed: %v", err)
+	}
+
+	// The second writer loaded before the first saved and must not clobber it
+	second.SetCurrent("second")
+	if err := second.SaveState(stateFile); !errors.Is(err, context.ErrStateConflict) {
+		t.Fatalf("Expected ErrStateConflict, got %v", err)
+	}
+
+	loaded, err := context.LoadState(stateFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded.Current != "first" || loaded.Previous != "base" {
+		t.Errorf("Expected first/base to survive, got %s/%s", loaded.Current, loaded.Previous)
+	}
+
+	// A state saved again after its own save is not a conflict
+	first.SetCurrent("again")
+	if err := first.SaveState(stateFile); err != nil {
+		t.Errorf("Saving twice from one process failed: %v", err)
+	}
+
+	// Nor is saving over a file that appeared after loading a missing one
+	missing := filepath.Join(t.TempDir(), "state.json")
+	fresh, _ := context.LoadState(missing)
+	if err := (&context.State{Current: "other"}).SaveState(missing); err != nil {
+		t.Fatal(err)
+	}
+	if err := fresh.SaveState(missing); !errors.Is(err, context.ErrStateConflict) {
+		t.Errorf("Expected ErrStateConflict when the file was created meanwhile, got %v", err)
+	}
+}
+
+func TestIntegration_ConcurrentSwitches(t *testing.T) {
+	ith := NewIntegrationTestHelper(t)
+	defer ith.Cleanup()
+
+	ith.CreateSampleConfig()
+	names := []string{"a", "b", "c", "d"}
+	for _, name := range names {
+		if _, _, err := ith.RunCommand("-n", name); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, _, err := ith.RunCommand("a"); err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 2*len(names))
+	for round := 0; round < 2; round++ {
+		for _, name := range names {
+			wg.Add(1)
+			go func(name string) {
+				defer wg.Done()
+				if _, stderr, err := ith.RunCommand(name); err != nil {
+					errs <- fmt.Errorf("switch to %s: %v\n%s", name, err, stderr)
+				}
+			}(name)
+		}
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
+
+	state, err := context.LoadState(filepath.Join(ith.SettingsDir, ".occtx-state.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	valid := map[string]bool{"a": true, "b": true, "c": true, "d": true}
+	if !valid[state.Current] || !valid[state.Previous] {
+		t.Errorf("Expected current and previous to name contexts, got %q/%q", state.Current, state.Previous)
+	}
+}
+
+func TestLoadState_QuarantineAndRecover(t *testing.T) {
+	dir := t.TempDir()
+	stateFile := filepath.Join(dir, "state.json")
+
+	state := &context.State{Current: "work", Previous: "personal"}
+	if err := state.SaveState(stateFile); err != nil {
+		t.Fatal(err)
+	}
+
+	// Truncated by a full disk
+	if err := os.WriteFile(stateFile, []byte(`{"current": "wo`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	recovered, err := context.LoadState(stateFile)
+	if err != nil {
+		t.Fatalf("LoadState failed: %v", err)
+	}
+	if recovered.Recovery == nil || !recovered.Recovery.FromBackup {
+		t.Fatalf("Expected recovery from backup, got %+v", recovered.Recovery)
+	}
+	if recovered.Current != "work" || recovered.Previous != "personal" {
+		t.Errorf("Expected work/personal from the backup, got %s/%s", recovered.Current, recovered.Previous)
+	}
+
+	// The corrupt file is kept for inspection and the good state is back in place
+	quarantined, err := os.ReadFile(recovered.Recovery.QuarantinedTo)
+	if err != nil || string(quarantined) != `{"current": "wo` {
+		t.Errorf("Expected corrupt content in %s, got %q (%v)", recovered.Recovery.QuarantinedTo, quarantined, err)
+	}
+	if !strings.HasPrefix(filepath.Base(recovered.Recovery.QuarantinedTo), "state.json.corrupt-") {
+		t.Errorf("Unexpected quarantine name %s", recovered.Recovery.QuarantinedTo)
+	}
+	reloaded, err := context.LoadState(stateFile)
+	if err != nil || reloaded.Recovery != nil || reloaded.Current != "work" {
+		t.Errorf("Expected the restored state on reload, got %+v (%v)", reloaded, err)
+	}
+
+	// Without a usable backup the state is reset, but still reported
+	if err := os.Remove(stateFile + ".bak"); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(stateFile, []byte("garbage"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	reset, err := context.LoadState(stateFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reset.Recovery == nil || reset.Recovery.FromBackup || reset.Current != "" {
+		t.Errorf("Expected a reported reset, got %+v", reset)
+	}
+}
+
+func TestIntegration_CorruptStateWarning(t *testing.T) {
+	ith := NewIntegrationTestHelper(t)
+	defer ith.Cleanup()
+
+	ith.CreateSampleConfig()
+	for _, args := range [][]string{{"-n", "work"}, {"-n", "personal"}, {"work"}, {"personal"}} {
+		if _, _, err := ith.RunCommand(args...); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	stateFile := filepath.Join(ith.SettingsDir, ".occtx-state.json")
+	if err := os.WriteFile(stateFile, []byte("{"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// Read-only mode reports it and reads the backup, but changes nothing
+	stdout, stderr, err := ith.RunCommand("--read-only", "-c")
+	if err != nil {
+		t.Fatalf("read-only current failed: %v\n%s", err, stderr)
+	}
+	if !strings.Contains(stderr, "state file is corrupt") || !strings.Contains(stdout, "personal") {
+		t.Errorf("Expected a read-only recovery warning and the backup's context, got %s\n%s", stdout, stderr)
+	}
+	if data, _ := os.ReadFile(stateFile); string(data) != "{" {
+		t.Errorf("Expected read-only mode to leave the state file alone, got %q", data)
+	}
+	if quarantined, _ := filepath.Glob(stateFile + ".corrupt-*"); len(quarantined) != 0 {
+		t.Errorf("Expected no quarantine copy in read-only mode, got %v", quarantined)
+	}
+
+	// Restored with the configured file mode
+	writeOcctxConfig(t, ith.ConfigDir, `{"permissions": {"files": "0600"}}`)
+	stdout, stderr, err = ith.RunCommand("-c")
+	if err != nil {
+		t.Fatalf("current failed: %v\n%s", err, stderr)
+	}
+	if !strings.Contains(stderr, "state file was corrupt") || !strings.Contains(stderr, "restored the last backup") {
+		t.Errorf("Expected a recovery warning, got %s", stderr)
+	}
+	if !strings.Contains(stdout, "personal") {
+		t.Errorf("Expected the recovered current context, got %s", stdout)
+	}
+	if info, err := os.Stat(stateFile); err != nil {
+		t.Fatal(err)
+	} else if runtime.GOOS != "windows" && info.Mode().Perm() != 0600 {
+		t.Errorf("Expected the restored state file to get the configured mode, got %v", info.Mode().Perm())
+	}
+
+	// `occtx -` still knows the previous context
+	if _, stderr, err := ith.RunCommand("-"); err != nil {
+		t.Errorf("switching to previous after recovery failed: %v\n%s", err, stderr)
+	}
+}
+
+func TestManager_CurrentMarker(t *testing.T) {
+	th := NewTestHelper(t)
+	defer th.Cleanup()
+
+	th.CreateSampleConfig()
+	manager := th.CreateManagerWithTempDir()
+	manager.SetForce(true)
+
+	manager.CreateContext("work")
+	manager.CreateContext("demo")
+
+	markerPath := manager.GetPaths().GetCurrentMarkerPath(false)
+	readMarker := func() string {
+		data, err := os.ReadFile(markerPath)
+		if os.IsNotExist(err) {
+			return ""
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(data)
+	}
+
+	if err := manager.SwitchToContext("work"); err != nil {
+		t.Fatalf("SwitchToContext failed: %v", err)
+	}
+	if got := readMarker(); got != "work\n" {
+		t.Errorf("Expected marker 'work\\n', got %q", got)
+	}
+
+	if err := manager.SwitchToContext("demo"); err != nil {
+		t.Fatalf("SwitchToContext failed: %v", err)
+	}
+	if got := readMarker(); got != "demo\n" {
+		t.Errorf("Expected marker 'demo\\n', got %q", got)
+	}
+
+	// Renaming the current context renames it in the marker too
+	if err := manager.RenameContext("demo", "staging"); err != nil {
+		t.Fatalf("RenameContext failed: %v", err)
+	}
+	if got := readMarker(); got != "staging\n" {
+		t.Errorf("Expected marker 'staging\\n', got %q", got)
+	}
+
+	// The marker is not mistaken for a context
+	contexts, _ := manager.ListContexts()
+	if len(contexts) != 2 {
+		t.Errorf("Expected 2 contexts, got %d", len(contexts))
+	}
+
+	if err := manager.UnsetCurrentContext(); err != nil {
+		t.Fatalf("UnsetCurrentContext failed: %v", err)
+	}
+	if _, err := os.Stat(markerPath); !os.IsNotExist(err) {
+		t.Errorf("Expected marker to be removed after unset, got %v", err)
+	}
+}
@@ -0,0 +1,101 @@
+package test
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hungthai1401/occtx/internal/context"
+)
+
+func TestManager_StrictWarnings(t *testing.T) {
+	th := NewTestHelper(t)
+	defer th.Cleanup()
+
+	th.CreateSampleConfig()
+	setup := th.CreateManagerWithTempDir()
+	setup.SetForce(true)
+	if err := setup.CreateContext("opus"); err != nil {
+		t.Fatal(err)
+	}
+	if err := setup.SetCostTier("opus", "expensive"); err != nil {
+		t.Fatal(err)
+	}
+	writeOcctxConfig(t, th.ConfigDir, `{"cost": {"guarded_tiers": ["expensive"]}}`)
+
+	manager := th.CreateManagerWithTempDir()
+	manager.SetForce(true)
+	manager.SetStrict(true)
+	var warnings []string
+	manager.SetWarningHandler(func(message string) { warnings = append(warnings, message) })
+
+	// The cost guard only warns, which is fatal in strict mode
+	err := manager.SwitchToContext("opus")
+	var strictErr *context.StrictWarningsError
+	if !errors.As(err, &strictErr) || strictErr.Operation != "switch" || len(strictErr.Warnings) != 1 {
+		t.Fatalf("Expected a strict warnings error, got %v", err)
+	}
+	if len(warnings) != 1 {
+		t.Errorf("The warning should still be reported, got %v", warnings)
+	}
+	if current, _ := manager.GetCurrentContext(); current == "opus" {
+		t.Error("The switch must not happen in strict mode")
+	}
+
+	// Import findings in warn mode are fatal too
+	secret := `{"provider": {"openai": {"options": {"apiKey": "sk-123"}}}}`
+	found, err := manager.ImportContextWithOptions("leaky", []byte(secret), context.Source{Kind: context.SourceImport},
+		context.ImportOptions{Format: context.FormatJSON, Secrets: context.PolicyWarn})
+	if !errors.As(err, &strictErr) || len(found) != 1 {
+		t.Errorf("Expected the import to fail with its finding, got %v (%v)", err, found)
+	}
+	if _, err := manager.GetContext("leaky"); err == nil {
+		t.Error("The import must not create the context in strict mode")
+	}
+
+	// Without strict mode both go through
+	manager.SetStrict(false)
+	if err := manager.SwitchToContext("opus"); err != nil {
+		t.Errorf("Switch failed: %v", err)
+	}
+}
+
+func TestIntegration_StrictDrift(t *testing.T) {
+	ith := NewIntegrationTestHelper(t)
+	defer ith.Cleanup()
+
+	ith.CreateSampleConfig()
+	if _, _, err := ith.RunCommand("-n", "work"); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := ith.RunCommand("work"); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(ith.ConfigDir, "opencode.json"), []byte(`{"theme": "edited"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, stderr, err := ith.RunCommand("-c"); err != nil || !strings.Contains(stderr, "Warning: active config was modified") {
+		t.Errorf("Expected a drift warning only, got %v\n%s", err, stderr)
+	}
+
+	stderr, code := runCI(t, ith, nil, "--strict", "--quiet", "-c")
+	if code != 6 || !strings.Contains(stderr, "refused in strict mode") || strings.Contains(stderr, "Warning:") {
+		t.Errorf("Expected exit code 6 without the warning text, got %d\n%s", code, stderr)
+	}
+
+	stderr, code = runCI(t, ith, []string{"OCCTX_STRICT=1", "CI=true"}, "-c")
+	var report struct {
+		Kind     string   `json:"kind"`
+		Warnings []string `json:"warnings"`
+	}
+	if err := json.Unmarshal([]byte(strings.TrimSpace(stderr)), &report); err != nil || code != 6 {
+		t.Fatalf("Expected a JSON error and exit code 6, got %d\n%s", code, stderr)
+	}
+	if report.Kind != "warnings" || len(report.Warnings) != 1 {
+		t.Errorf("Unexpected report %+v", report)
+	}
+}
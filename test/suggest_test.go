@@ -0,0 +1,109 @@
+package test
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hungthai1401/occtx/internal/context"
+	"github.com/hungthai1401/occtx/internal/suggest"
+)
+
+func TestSuggest_Distance(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		expected int
+	}{
+		{"", "", 0},
+		{"work", "work", 0},
+		{"work", "Work", 0},
+		{"wrok", "work", 2},
+		{"stsh", "stash", 1},
+		{"", "abc", 3},
+		{"kitten", "sitting", 3},
+	}
+
+	for _, tt := range tests {
+		if got := suggest.Distance(tt.a, tt.b); got != tt.expected {
+			t.Errorf("Distance(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.expected)
+		}
+	}
+}
+
+func TestSuggest_Closest(t *testing.T) {
+	candidates := []string{"work", "personal", "worker", "staging", "prod"}
+
+	tests := []struct {
+		input    string
+		expected []string
+	}{
+		{"wrk", []string{"work"}},
+		{"wokr", []string{"work", "worker"}},
+		{"persnal", []string{"personal"}},
+		{"stg", nil},
+		{"xyz", nil},
+		{"work", []string{"worker"}},
+	}
+
+	for _, tt := range tests {
+		if got := suggest.Closest(tt.input, candidates); !reflect.DeepEqual(got, tt.expected) {
+			t.Errorf("Closest(%q) = %v, want %v", tt.input, got, tt.expected)
+		}
+	}
+}
+
+func TestManager_GetContext_Suggestions(t *testing.T) {
+	th := NewTestHelper(t)
+	defer th.Cleanup()
+
+	th.CreateSampleConfig()
+	manager := th.CreateManagerWithTempDir()
+
+	manager.CreateContext("work")
+	manager.CreateContext("personal")
+
+	_, err := manager.GetContext("wrok")
+	var notFound *context.NotFoundError
+	if !errors.As(err, &notFound) {
+		t.Fatalf("Expected NotFoundError, got %v", err)
+	}
+	if !reflect.DeepEqual(notFound.Suggestions, []string{"work"}) {
+		t.Errorf("Expected suggestion 'work', got %v", notFound.Suggestions)
+	}
+	if !strings.Contains(err.Error(), "did you mean 'work'?") {
+		t.Errorf("Expected suggestion in error message, got: %v", err)
+	}
+
+	_, err = manager.GetContext("unrelated")
+	if err == nil || strings.Contains(err.Error(), "did you mean") {
+		t.Errorf("Expected plain not-found error, got: %v", err)
+	}
+}
+
+func TestIntegration_TypoSuggestions(t *testing.T) {
+	ith := NewIntegrationTestHelper(t)
+	defer ith.Cleanup()
+
+	ith.CreateSampleConfig()
+
+	tests := []struct {
+		args     []string
+		expected string
+	}{
+		{[]string{"--verbos"}, "did you mean '--verbose'?"},
+		{[]string{"stash", "lst"}, "did you mean 'list'?"},
+		{[]string{"stsh"}, "did you mean the 'stash' command?"},
+	}
+
+	for _, tt := range tests {
+		_, stderr, err := ith.RunCommand(tt.args...)
+		if err == nil {
+			t.Errorf("%v: expected failure", tt.args)
+			continue
+		}
+		if !strings.Contains(stderr, tt.expected) {
+			t.Errorf("%v: expected %q in stderr, got: %s", tt.args, tt.expected, stderr)
+		}
+	}
+}
@@ -0,0 +1,135 @@
+package test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hungthai1401/occtx/internal/context"
+)
+
+func summarize(t *testing.T, file, content string) (*context.Summary, error) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), file)
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	format := context.FormatJSON
+	if strings.HasSuffix(file, ".jsonc") {
+		format = context.FormatJSONC
+	}
+	return context.SummarizeContext(&context.Context{Name: "c", FilePath: path, Format: format})
+}
+
+func TestSummarizeContext(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		content string
+		want    context.Summary
+	}{
+		{
+			name: "model and providers",
+			file: "c.json",
+			content: `{"theme": "dark", "agent": {"build": {"model": "nested/ignored"}},
+				"provider": {"openai": {"models": {"a": {}}}, "anthropic": {"options": [1, {"x": 2}]}},
+				"model": "anthropic/claude-sonnet-4"}`,
+			want: context.Summary{Model: "anthropic/claude-sonnet-4", Providers: []string{"anthropic", "openai"}},
+		},
+		{
+			name:    "neither",
+			file:    "c.json",
+			content: `{"theme": "dark"}`,
+			want:    context.Summary{},
+		},
+		{
+			name:    "unexpected types",
+			file:    "c.json",
+			content: `{"model": {"id": "x"}, "provider": ["anthropic"]}`,
+			want:    context.Summary{},
+		},
+		{
+			name:    "jsonc",
+			file:    "c.jsonc",
+			content: "{\n  // Work setup\n  \"model\": \"openai/gpt-5\",\n  // Providers\n  \"provider\": {\"openai\": {}}\n}\n",
+			want:    context.Summary{Model: "openai/gpt-5", Providers: []string{"openai"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := summarize(t, tt.file, tt.content)
+			if err != nil {
+				t.Fatalf("SummarizeContext failed: %v", err)
+			}
+			if !reflect.DeepEqual(*got, tt.want) {
+				t.Errorf("Expected %+v, got %+v", tt.want, *got)
+			}
+		})
+	}
+}
+
+func TestSummarizeContext_StopsAfterKeys(t *testing.T) {
+	// Nothing after both keys is read, so the truncated tail doesn't matter
+	got, err := summarize(t, "c.json", `{"model": "a/b", "provider": {"a": {}}, "agent": {"build": [`)
+	if err != nil {
+		t.Fatalf("Expected the summary to stop after model and provider, got %v", err)
+	}
+	if got.Model != "a/b" || len(got.Providers) != 1 {
+		t.Errorf("Unexpected summary %+v", got)
+	}
+}
+
+func TestSummarizeContext_InvalidJSON(t *testing.T) {
+	for _, content := range []string{`{"theme": `, `[]`, ``} {
+		if _, err := summarize(t, "c.json", content); err == nil || !strings.Contains(err.Error(), "invalid JSON") {
+			t.Errorf("Expected an invalid JSON error for %q, got %v", content, err)
+		}
+	}
+}
+
+func TestIntegration_LongListingSummary(t *testing.T) {
+	ith := NewIntegrationTestHelper(t)
+	defer ith.Cleanup()
+
+	ith.CreateSampleConfig()
+	if _, _, err := ith.RunCommand("-n", "work"); err != nil {
+		t.Fatal(err)
+	}
+	broken := filepath.Join(ith.SettingsDir, "broken.json")
+	if err := os.WriteFile(broken, []byte(`{"provider": `), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	stdout, stderr, err := ith.RunCommand("-l")
+	if err != nil {
+		t.Fatalf("occtx -l failed: %v\n%s", err, stderr)
+	}
+	if !strings.Contains(stdout, "providers anthropic") {
+		t.Errorf("Expected the providers in the long listing, got:\n%s", stdout)
+	}
+	if !strings.Contains(stdout, "unreadable") {
+		t.Errorf("Expected the broken context to be listed as unreadable, got:\n%s", stdout)
+	}
+
+	stdout, _, err = ith.RunCommand("-l", "-o", "json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var entries []map[string]interface{}
+	if err := json.Unmarshal([]byte(stdout), &entries); err != nil {
+		t.Fatalf("Invalid JSON listing: %v\n%s", err, stdout)
+	}
+	found := false
+	for _, entry := range entries {
+		if entry["name"] == "work" {
+			found = reflect.DeepEqual(entry["providers"], []interface{}{"anthropic"})
+		}
+	}
+	if !found {
+		t.Errorf("Expected providers in the JSON listing of 'work', got:\n%s", stdout)
+	}
+}
@@ -0,0 +1,109 @@
+package test
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/hungthai1401/occtx/internal/config"
+)
+
+func TestIntegration_SwitchBoth(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("hooks in this test are shell commands")
+	}
+	ith := NewIntegrationTestHelper(t)
+	defer ith.Cleanup()
+
+	project := filepath.Join(ith.TempDir, "project")
+	projectSettings := filepath.Join(project, "opencode", "settings")
+	globalSettings := filepath.Join(ith.ConfigDir, "settings")
+	for _, dir := range []string{projectSettings, globalSettings} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write := func(path, content string) {
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write(filepath.Join(globalSettings, "work.json"), `{"theme": "global-work"}`)
+	write(filepath.Join(globalSettings, "home.json"), `{"theme": "global-home"}`)
+	write(filepath.Join(globalSettings, "broken.json"), `{"theme": "global-broken"}`)
+	write(filepath.Join(projectSettings, "work.json"), `{"theme": "project-work"}`)
+	// Resolving its conditional section fails, after the global scope was checked
+	write(filepath.Join(projectSettings, "broken.json"), `{"theme": "x", "a": {"$when": {"kernel": "6"}}}`)
+
+	record := filepath.Join(ith.TempDir, "hooks.log")
+	logEvent := []string{"sh", "-c", `echo "$OCCTX_HOOK_EVENT $OCCTX_CONTEXT" >> "` + record + `"`}
+	writeHooksConfig(t, ith.ConfigDir, []config.HookConfig{
+		{Name: "pre", On: config.HookPreSwitch, Run: logEvent},
+		{Name: "post", On: config.HookPostSwitch, Run: logEvent},
+	})
+	hookLog := func() string {
+		data, _ := os.ReadFile(record)
+		_ = os.Remove(record)
+		return string(data)
+	}
+
+	run := func(args ...string) (string, error) {
+		cmd := exec.Command(ith.BinaryPath, args...)
+		cmd.Dir = project
+		cmd.Env = ith.Env()
+		out, err := cmd.CombinedOutput()
+		return string(out), err
+	}
+	current := func(args ...string) string {
+		out, err := run(append(args, "current")...)
+		if err != nil {
+			t.Fatalf("current failed: %v\n%s", err, out)
+		}
+		return strings.TrimSpace(out)
+	}
+
+	if out, err := run("switch", "work", "--both"); err != nil {
+		t.Fatalf("switch --both failed: %v\n%s", err, out)
+	}
+	if global, proj := current(), current("--in-project"); global != "work" || proj != "work" {
+		t.Errorf("Expected both scopes on work, got %q and %q", global, proj)
+	}
+	data, _ := os.ReadFile(filepath.Join(project, "opencode.json"))
+	if !strings.Contains(string(data), "project-work") {
+		t.Errorf("Expected the project context in the project active config, got %s", data)
+	}
+	// Both scopes are checked before either switches
+	if log := hookLog(); log != "pre-switch work\npre-switch work\npost-switch work\npost-switch work\n" {
+		t.Errorf("Expected the pre-switch hooks of both scopes to run first, got:\n%s", log)
+	}
+
+	// Missing in one scope: nothing is written
+	if out, err := run("switch", "home", "--both"); err == nil || !strings.Contains(out, "project scope") {
+		t.Errorf("Expected the project scope to be reported, got %v\n%s", err, out)
+	}
+
+	// Failing in the second scope leaves the first alone
+	globalActive := filepath.Join(ith.ConfigDir, "opencode.json")
+	before, _ := os.ReadFile(globalActive)
+	hookLog()
+	if out, err := run("switch", "broken", "--both"); err == nil || !strings.Contains(out, "project scope") {
+		t.Errorf("Expected the project scope to be reported, got %v\n%s", err, out)
+	}
+	if log := hookLog(); log != "pre-switch broken\n" {
+		t.Errorf("Expected only the global pre-switch hook to run, got:\n%s", log)
+	}
+	// current's output would carry a drift warning too
+	if global := current(); global != "work" {
+		t.Errorf("Expected the global scope to stay on work without drift, got %q", global)
+	}
+	if after, _ := os.ReadFile(globalActive); string(after) != string(before) {
+		t.Errorf("Expected the global active config to be restored, got %s", after)
+	}
+
+	if out, err := run("switch", "work", "--both", "--for", "1h"); err == nil {
+		t.Errorf("Expected --for to be rejected with --both, got %s", out)
+	}
+}
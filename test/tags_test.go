@@ -0,0 +1,136 @@
+package test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hungthai1401/occtx/internal/context"
+)
+
+func TestManager_ContextTags(t *testing.T) {
+	th := NewTestHelper(t)
+	defer th.Cleanup()
+
+	th.CreateSampleConfig()
+	manager := th.CreateManagerWithTempDir()
+	if err := manager.CreateContext("work"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := manager.SetContextTags("work", map[string]string{"team": "platform", "env": "prod"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := manager.SetContextTags("work", map[string]string{"bad key": "x"}); err == nil {
+		t.Error("Expected a key with a space to be rejected")
+	}
+	if err := manager.UntagContext("work", []string{"env"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := manager.UntagContext("work", []string{"env"}); err == nil {
+		t.Error("Expected removing a missing tag to fail")
+	}
+
+	// Tags follow a rename
+	if err := manager.RenameContext("work", "platform"); err != nil {
+		t.Fatal(err)
+	}
+	tags, err := manager.GetContextTags("platform")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tags) != 1 || tags["team"] != "platform" {
+		t.Errorf("Unexpected tags after rename: %v", tags)
+	}
+}
+
+func TestManager_ExportTree(t *testing.T) {
+	th := NewTestHelper(t)
+	defer th.Cleanup()
+
+	th.CreateSampleConfig()
+	manager := th.CreateManagerWithTempDir()
+	contexts := map[string]string{
+		"api":   `{"provider": {"openai": {"options": {"apiKey": "sk-live-123"}}}}`,
+		"web":   `{"model": "openai/gpt-4.1"}`,
+		"other": `{"model": "anthropic/claude-sonnet"}`,
+	}
+	for name, data := range contexts {
+		if err := manager.ImportContext(name, []byte(data)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, name := range []string{"api", "web"} {
+		if err := manager.SetContextTags(name, map[string]string{"team": "platform"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	dir := filepath.Join(th.TempDir, "shared-contexts")
+	tag, err := context.ParseTagSelector("team=platform")
+	if err != nil {
+		t.Fatal(err)
+	}
+	exported, err := manager.ExportTree(dir, []context.TagSelector{tag}, context.ExportOptions{Redact: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(exported) != 2 {
+		t.Fatalf("Expected 2 exported contexts, got %v", exported)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "api.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "sk-live-123") || !strings.Contains(string(data), context.RedactedValue) {
+		t.Errorf("Expected the secret to be redacted, got:\n%s", data)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "other.json")); !os.IsNotExist(err) {
+		t.Error("Expected the untagged context to be left out")
+	}
+
+	// Nothing matches
+	none, err := context.ParseTagSelector("team=data")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exported, err := manager.ExportTree(filepath.Join(th.TempDir, "empty"), []context.TagSelector{none}, context.ExportOptions{}); err != nil || len(exported) != 0 {
+		t.Errorf("Expected nothing to be exported, got %v, %v", exported, err)
+	}
+}
+
+func TestIntegration_ExportByTag(t *testing.T) {
+	ith := NewIntegrationTestHelper(t)
+	defer ith.Cleanup()
+
+	ith.CreateSampleConfig()
+	for _, name := range []string{"dev", "prod"} {
+		if _, stderr, err := ith.RunCommand("-n", name); err != nil {
+			t.Fatalf("-n %s failed: %v\n%s", name, err, stderr)
+		}
+	}
+	if _, stderr, err := ith.RunCommand("tag", "set", "prod", "team=platform"); err != nil {
+		t.Fatalf("tag set failed: %v\n%s", err, stderr)
+	}
+	if out, _, _ := ith.RunCommand("tag", "list", "prod"); strings.TrimSpace(out) != "team=platform" {
+		t.Errorf("Unexpected tag list: %q", out)
+	}
+
+	dir := filepath.Join(ith.TempDir, "out")
+	out, stderr, err := ith.RunCommand("export", "--tag", "team=platform", "--out-dir", dir, "--git-safe")
+	if err != nil {
+		t.Fatalf("export failed: %v\n%s", err, stderr)
+	}
+	if !strings.Contains(out, "Exported 1 context(s)") {
+		t.Errorf("Unexpected export output:\n%s", out)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "prod.jsonc")); err != nil {
+		t.Errorf("Expected prod.jsonc in the output directory: %v", err)
+	}
+
+	if _, _, err := ith.RunCommand("export", "--tag", "team", "--out-dir", dir); err == nil {
+		t.Error("Expected a tag without a value to be rejected")
+	}
+}
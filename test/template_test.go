@@ -0,0 +1,78 @@
+package test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hungthai1401/occtx/internal/context"
+)
+
+func TestManager_CreateContextFromTemplate(t *testing.T) {
+	th := NewTestHelper(t)
+	defer th.Cleanup()
+
+	templatesDir := filepath.Join(th.ConfigDir, "settings", "templates")
+	if err := os.MkdirAll(templatesDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	template := `{
+  // Rendered once per service
+  "model": "anthropic/claude-sonnet-4",
+  "instructions": ["docs/{{ .project_name }}.md"],
+  "agent": {"build": {"prompt": "{{ if .team }}Owned by {{ .team }}{{ end }}"}}
+}`
+	if err := os.WriteFile(filepath.Join(templatesDir, "service.jsonc"), []byte(template), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	manager := th.CreateManagerWithTempDir()
+
+	templates, err := manager.ListTemplates()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(templates) != 1 || templates[0].Name != "service" || strings.Join(templates[0].Variables, ",") != "project_name,team" {
+		t.Fatalf("Unexpected templates: %+v", templates)
+	}
+
+	// Every variable must be given
+	err = manager.CreateContextFromTemplate("billing", context.FormatJSON, "service", map[string]string{"project_name": "billing"})
+	if err == nil || !strings.Contains(err.Error(), "team") {
+		t.Fatalf("Expected an error naming the missing variable, got %v", err)
+	}
+
+	// Values are JSON-escaped
+	vars := map[string]string{"project_name": "billing", "team": `pay "ments"`}
+	if err := manager.CreateContextFromTemplate("billing", context.FormatJSON, "service", vars); err != nil {
+		t.Fatal(err)
+	}
+	ctx, err := manager.GetContext("billing")
+	if err != nil {
+		t.Fatal(err)
+	}
+	instructions, _ := ctx.Data["instructions"].([]interface{})
+	if len(instructions) != 1 || instructions[0] != "docs/billing.md" {
+		t.Errorf("Unexpected instructions: %v", ctx.Data["instructions"])
+	}
+	agent := ctx.Data["agent"].(map[string]interface{})["build"].(map[string]interface{})
+	if agent["prompt"] != `Owned by pay "ments"` {
+		t.Errorf("Unexpected prompt: %v", agent["prompt"])
+	}
+
+	meta, err := manager.GetContextMeta("billing")
+	if err != nil || meta == nil || meta.Source.Kind != context.SourceTemplate || meta.Source.From != "service" {
+		t.Errorf("Expected the template to be recorded as the source, got %+v, %v", meta, err)
+	}
+
+	// Templates are not contexts
+	if _, err := manager.GetContext("service"); err == nil {
+		t.Error("Expected the template not to be listed as a context")
+	}
+
+	_, err = manager.RenderTemplate("servce", nil)
+	if err == nil || !strings.Contains(err.Error(), "did you mean 'service'") {
+		t.Errorf("Expected a suggestion for an unknown template, got %v", err)
+	}
+}
@@ -0,0 +1,144 @@
+package test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hungthai1401/occtx/internal/context"
+)
+
+func TestManager_SwitchToContextFor(t *testing.T) {
+	th := NewTestHelper(t)
+	defer th.Cleanup()
+
+	th.CreateSampleConfig()
+	manager := th.CreateManagerWithTempDir()
+
+	manager.CreateContext("work")
+	manager.CreateContext("demo")
+	if err := manager.SwitchToContext("work"); err != nil {
+		t.Fatalf("SwitchToContext failed: %v", err)
+	}
+
+	if err := manager.SwitchToContextFor("demo", 0); err == nil {
+		t.Error("Expected error for non-positive duration")
+	}
+
+	if err := manager.SwitchToContextFor("demo", 2*time.Hour); err != nil {
+		t.Fatalf("SwitchToContextFor failed: %v", err)
+	}
+
+	temporary, err := manager.GetTemporarySwitch()
+	if err != nil {
+		t.Fatalf("GetTemporarySwitch failed: %v", err)
+	}
+	if temporary == nil || temporary.Context != "demo" || temporary.RevertTo != "work" {
+		t.Fatalf("Unexpected temporary switch: %+v", temporary)
+	}
+
+	// Not yet expired: nothing happens
+	reverted, err := manager.RevertExpired()
+	if err != nil {
+		t.Fatalf("RevertExpired failed: %v", err)
+	}
+	if reverted != nil {
+		t.Error("Expected no revert before expiry")
+	}
+
+	// Force expiry
+	state, _ := context.LoadState(th.StateFile)
+	state.Temporary.ExpiresAt = time.Now().Add(-time.Minute)
+	if err := state.SaveState(th.StateFile); err != nil {
+		t.Fatal(err)
+	}
+
+	reverted, err = manager.RevertExpired()
+	if err != nil {
+		t.Fatalf("RevertExpired failed: %v", err)
+	}
+	if reverted == nil {
+		t.Fatal("Expected expired switch to be reverted")
+	}
+
+	current, _ := manager.GetCurrentContext()
+	if current != "work" {
+		t.Errorf("Expected current context 'work' after revert, got '%s'", current)
+	}
+
+	temporary, _ = manager.GetTemporarySwitch()
+	if temporary != nil {
+		t.Error("Temporary switch should be cleared after revert")
+	}
+}
+
+func TestManager_RevertExpired_ManualSwitchWins(t *testing.T) {
+	th := NewTestHelper(t)
+	defer th.Cleanup()
+
+	th.CreateSampleConfig()
+	manager := th.CreateManagerWithTempDir()
+
+	manager.CreateContext("demo")
+	manager.CreateContext("other")
+	if err := manager.SwitchToContextFor("demo", time.Hour); err != nil {
+		t.Fatalf("SwitchToContextFor failed: %v", err)
+	}
+
+	// A manual switch cancels the pending revert
+	if err := manager.SwitchToContext("other"); err != nil {
+		t.Fatalf("SwitchToContext failed: %v", err)
+	}
+
+	temporary, _ := manager.GetTemporarySwitch()
+	if temporary != nil {
+		t.Error("Manual switch should cancel the temporary switch")
+	}
+}
+
+func TestTemporarySwitch_Expired(t *testing.T) {
+	now := time.Now()
+	temporary := &context.TemporarySwitch{Context: "demo", ExpiresAt: now}
+
+	if !temporary.Expired(now) {
+		t.Error("Switch should be expired at its expiry time")
+	}
+	if temporary.Expired(now.Add(-time.Second)) {
+		t.Error("Switch should not be expired before its expiry time")
+	}
+}
+
+func TestIntegration_ExpiredSwitchOnlyRevertedByChanges(t *testing.T) {
+	ith := NewIntegrationTestHelper(t)
+	defer ith.Cleanup()
+
+	ith.CreateSampleConfig()
+	for _, args := range [][]string{{"-n", "work"}, {"-n", "demo"}, {"work"}, {"switch", "demo", "--for", "1ms"}} {
+		if _, stderr, err := ith.RunCommand(args...); err != nil {
+			t.Fatalf("%v failed: %v\n%s", args, err, stderr)
+		}
+	}
+	time.Sleep(10 * time.Millisecond)
+
+	// Looking, including a dry run, reports the expiry but doesn't revert it
+	for _, args := range [][]string{{"-c"}, {"ls"}, {"work", "--dry-run"}} {
+		_, stderr, err := ith.RunCommand(args...)
+		if err != nil {
+			t.Fatalf("%v failed: %v\n%s", args, err, stderr)
+		}
+		if !strings.Contains(stderr, "expired at") {
+			t.Errorf("Expected %v to report the expired switch, got %s", args, stderr)
+		}
+		if current, _, _ := ith.RunCommand("current"); strings.TrimSpace(current) != "demo" {
+			t.Fatalf("Expected %v to leave 'demo' current, got %q", args, current)
+		}
+	}
+
+	// The next change reverts it
+	if _, stderr, err := ith.RunCommand("-n", "other"); err != nil || !strings.Contains(stderr, "reverted to context 'work'") {
+		t.Errorf("Expected -n to revert the expired switch, got %v\n%s", err, stderr)
+	}
+	if current, _, _ := ith.RunCommand("current"); strings.TrimSpace(current) != "work" {
+		t.Errorf("Expected 'work' after the revert, got %q", current)
+	}
+}
@@ -0,0 +1,79 @@
+package test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/fatih/color"
+	"github.com/hungthai1401/occtx/internal/config"
+	"github.com/hungthai1401/occtx/internal/ui"
+)
+
+func TestThemeFromConfig(t *testing.T) {
+	theme, err := ui.ThemeFromConfig(config.UIConfig{})
+	if err != nil {
+		t.Fatalf("Default theme failed: %v", err)
+	}
+	if theme.CurrentMarker != "*" {
+		t.Errorf("Expected default marker '*', got %q", theme.CurrentMarker)
+	}
+
+	theme, err = ui.ThemeFromConfig(config.UIConfig{
+		Theme:   "monochrome",
+		Colors:  map[string]string{"current": "blue, bold", "info": "none"},
+		Symbols: config.SymbolsConfig{Current: "→"},
+	})
+	if err != nil {
+		t.Fatalf("ThemeFromConfig failed: %v", err)
+	}
+	if !reflect.DeepEqual(theme.Current, []color.Attribute{color.FgBlue, color.Bold}) {
+		t.Errorf("Unexpected current color: %v", theme.Current)
+	}
+	if len(theme.Info) != 0 {
+		t.Errorf("Expected no info attributes, got %v", theme.Info)
+	}
+	if theme.CurrentMarker != "→" || theme.ActiveSymbol != ">" {
+		t.Errorf("Unexpected symbols: %q %q", theme.CurrentMarker, theme.ActiveSymbol)
+	}
+
+	invalid := []config.UIConfig{
+		{Theme: "neon"},
+		{Colors: map[string]string{"current": "sparkly"}},
+		{Colors: map[string]string{"border": "red"}},
+	}
+	for _, cfg := range invalid {
+		if _, err := ui.ThemeFromConfig(cfg); err == nil {
+			t.Errorf("Expected error for %+v", cfg)
+		}
+	}
+
+	for _, name := range ui.ThemeNames() {
+		if _, err := ui.ThemeFromConfig(config.UIConfig{Theme: name}); err != nil {
+			t.Errorf("Built-in theme %s failed: %v", name, err)
+		}
+	}
+}
+
+func TestIntegration_ThemeSymbols(t *testing.T) {
+	ith := NewIntegrationTestHelper(t)
+	defer ith.Cleanup()
+
+	ith.CreateSampleConfig()
+	ith.RunCommand("-n", "work")
+	ith.RunCommand("work")
+
+	writeOcctxConfig(t, ith.ConfigDir, `{"ui": {"theme": "high-contrast"}}`)
+	stdout, _, err := ith.RunCommand()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(stdout, "> work") {
+		t.Errorf("Expected high-contrast marker, got: %s", stdout)
+	}
+
+	writeOcctxConfig(t, ith.ConfigDir, `{"ui": {"theme": "neon"}}`)
+	if _, stderr, err := ith.RunCommand(); err == nil || !strings.Contains(stderr, "unknown theme") {
+		t.Errorf("Expected unknown theme error, got: %v %s", err, stderr)
+	}
+}
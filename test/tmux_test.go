@@ -0,0 +1,55 @@
+package test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestIntegration_TmuxStatus(t *testing.T) {
+	ith := NewIntegrationTestHelper(t)
+	defer ith.Cleanup()
+
+	ith.CreateSampleConfig()
+
+	// Nothing is printed before any switch
+	stdout, stderr, err := ith.RunCommand("tmux-status")
+	if err != nil {
+		t.Fatalf("tmux-status failed: %v\n%s", err, stderr)
+	}
+	if stdout != "" {
+		t.Errorf("Expected no output without a current context, got %q", stdout)
+	}
+
+	if _, stderr, err := ith.RunCommand("-n", "work"); err != nil {
+		t.Fatalf("create failed: %v\n%s", err, stderr)
+	}
+	if _, stderr, err := ith.RunCommand("work"); err != nil {
+		t.Fatalf("switch failed: %v\n%s", err, stderr)
+	}
+
+	stdout, _, _ = ith.RunCommand("tmux-status")
+	if stdout != "work" {
+		t.Errorf("Expected 'work', got %q", stdout)
+	}
+	stdout, _, _ = ith.RunCommand("tmux-status", "--format", "#[fg=green]#{ctx}#[default] ")
+	if stdout != "#[fg=green]work#[default] " {
+		t.Errorf("Unexpected formatted output %q", stdout)
+	}
+
+	// Whatever the marker holds can't inject tmux formats or break the line
+	marker := filepath.Join(ith.SettingsDir, "current-context")
+	if err := os.WriteFile(marker, []byte("a#[fg=red]\x1b#{b}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	stdout, _, _ = ith.RunCommand("tmux-status")
+	if stdout != "a##[fg=red]##{b}" {
+		t.Errorf("Expected escaped output, got %q", stdout)
+	}
+
+	stdout, _, err = ith.RunCommand("init", "tmux")
+	if err != nil || !strings.Contains(stdout, "status-right") || !strings.Contains(stdout, "tmux-status)") {
+		t.Errorf("Unexpected init tmux output (%v):\n%s", err, stdout)
+	}
+}
@@ -0,0 +1,116 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/hungthai1401/occtx/internal/context"
+)
+
+func TestManager_SwitchRecordsUsage(t *testing.T) {
+	th := NewTestHelper(t)
+	defer th.Cleanup()
+
+	th.CreateSampleConfig()
+	manager := th.CreateManagerWithTempDir()
+	manager.SetForce(true)
+
+	for _, name := range []string{"work", "demo", "idle"} {
+		if err := manager.CreateContext(name); err != nil {
+			t.Fatalf("CreateContext failed: %v", err)
+		}
+	}
+	for _, name := range []string{"work", "demo", "work", "work", "demo"} {
+		if err := manager.SwitchToContext(name); err != nil {
+			t.Fatalf("SwitchToContext failed: %v", err)
+		}
+	}
+
+	usage, err := manager.GetUsage()
+	if err != nil {
+		t.Fatalf("GetUsage failed: %v", err)
+	}
+	if usage["work"].Count != 3 || usage["demo"].Count != 2 {
+		t.Errorf("Unexpected use counts: %+v", usage)
+	}
+	if _, ok := usage["idle"]; ok {
+		t.Error("Expected no usage for a context never switched to")
+	}
+	if usage["demo"].LastUsed == nil || usage["work"].LastUsed == nil || usage["demo"].LastUsed.Before(*usage["work"].LastUsed) {
+		t.Errorf("Expected demo to be the most recently used: %+v", usage)
+	}
+
+	meta, err := manager.GetContextMeta("work")
+	if err != nil {
+		t.Fatalf("GetContextMeta failed: %v", err)
+	}
+	if meta.UseCount != 3 || meta.Source.Kind != context.SourceActive {
+		t.Errorf("Expected usage recorded alongside the source, got %+v", meta)
+	}
+
+	// Usage follows a renamed context
+	if err := manager.RenameContext("work", "office"); err != nil {
+		t.Fatalf("RenameContext failed: %v", err)
+	}
+	usage, _ = manager.GetUsage()
+	if usage["office"].Count != 3 {
+		t.Errorf("Expected usage to move with the rename, got %+v", usage)
+	}
+}
+
+func TestSortContexts(t *testing.T) {
+	th := NewTestHelper(t)
+	defer th.Cleanup()
+
+	th.CreateSampleConfig()
+	manager := th.CreateManagerWithTempDir()
+	manager.SetForce(true)
+
+	for _, name := range []string{"alpha", "beta", "gamma", "delta"} {
+		if err := manager.CreateContext(name); err != nil {
+			t.Fatalf("CreateContext failed: %v", err)
+		}
+	}
+	for _, name := range []string{"gamma", "gamma", "gamma", "beta", "beta", "alpha"} {
+		if err := manager.SwitchToContext(name); err != nil {
+			t.Fatalf("SwitchToContext failed: %v", err)
+		}
+	}
+	usage, err := manager.GetUsage()
+	if err != nil {
+		t.Fatalf("GetUsage failed: %v", err)
+	}
+
+	tests := []struct {
+		order    context.ListSort
+		expected []string
+	}{
+		{context.SortName, []string{"alpha", "beta", "delta", "gamma"}},
+		{context.SortUsed, []string{"alpha", "beta", "gamma", "delta"}},
+		{context.SortFrequency, []string{"gamma", "beta", "alpha", "delta"}},
+	}
+	for _, tt := range tests {
+		contexts, err := manager.ListContexts()
+		if err != nil {
+			t.Fatalf("ListContexts failed: %v", err)
+		}
+		context.SortContexts(contexts, tt.order, usage)
+
+		var names []string
+		for _, ctx := range contexts {
+			names = append(names, ctx.Name)
+		}
+		if len(names) != len(tt.expected) {
+			t.Fatalf("%s: expected %v, got %v", tt.order, tt.expected, names)
+		}
+		for i := range names {
+			if names[i] != tt.expected[i] {
+				t.Errorf("%s: expected %v, got %v", tt.order, tt.expected, names)
+				break
+			}
+		}
+	}
+
+	if _, err := context.ParseListSort("recent"); err == nil {
+		t.Error("Expected error for an unknown sort order")
+	}
+}
@@ -0,0 +1,74 @@
+package test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hungthai1401/occtx/internal/util"
+)
+
+func TestStripJSONCComments(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"no comments", "{\"a\": 1}\n", "{\"a\": 1}\n"},
+		{"comment lines", "// header\n{\n  // inside\n  \"a\": 1\n}\n", "{\n  \"a\": 1\n}\n"},
+		{"indented with tabs", "{\n\t\t// tab\n\t\"a\": 1\n}", "{\n\t\"a\": 1\n}"},
+		{"slashes in strings kept", "{\"url\": \"https://example.com\"}\n", "{\"url\": \"https://example.com\"}\n"},
+		{"trailing comment without newline", "{}\n// end", "{}\n"},
+		{"empty", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := string(util.StripJSONCComments([]byte(tt.input))); got != tt.want {
+				t.Errorf("StripJSONCComments(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseJSONC(t *testing.T) {
+	got, err := util.ParseJSONC([]byte("// opencode context\n{\n  // theme\n  \"theme\": \"dark\"\n}\n"))
+	if err != nil {
+		t.Fatalf("ParseJSONC failed: %v", err)
+	}
+	if !reflect.DeepEqual(got, map[string]interface{}{"theme": "dark"}) {
+		t.Errorf("Unexpected result %v", got)
+	}
+
+	if _, err := util.ParseJSONC([]byte("{\"theme\": /* block */ \"dark\"}")); err == nil {
+		t.Error("Expected block comments to be rejected")
+	}
+}
+
+func TestIsJSONCPath(t *testing.T) {
+	if !util.IsJSONCPath("/a/work.jsonc") || util.IsJSONCPath("/a/work.json") || util.IsJSONCPath("jsonc") {
+		t.Error("IsJSONCPath should only match the .jsonc extension")
+	}
+}
+
+func TestContainsHelpers(t *testing.T) {
+	values := []string{"alpha", "Beta", "gamma"}
+
+	if !util.Contains(values, "Beta") || util.Contains(values, "beta") || util.Contains(nil, "") {
+		t.Error("Contains should match exactly")
+	}
+	if !util.ContainsFold(values, "BETA") || util.ContainsFold(values, "delta") {
+		t.Error("ContainsFold should match ignoring case")
+	}
+
+	sorted := []string{"a", "c", "e"}
+	for _, s := range sorted {
+		if !util.ContainsSorted(sorted, s) {
+			t.Errorf("ContainsSorted should find %q", s)
+		}
+	}
+	for _, s := range []string{"", "b", "f"} {
+		if util.ContainsSorted(sorted, s) {
+			t.Errorf("ContainsSorted shouldn't find %q", s)
+		}
+	}
+}
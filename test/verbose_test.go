@@ -0,0 +1,92 @@
+package test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hungthai1401/occtx/internal/context"
+)
+
+func TestManager_Verbosity(t *testing.T) {
+	th := NewTestHelper(t)
+	defer th.Cleanup()
+
+	th.CreateSampleConfig()
+	manager := th.CreateManagerWithTempDir()
+
+	var messages []string
+	collect := func(level context.Verbosity, message string) {
+		messages = append(messages, message)
+	}
+
+	// Normal verbosity reports nothing
+	manager.SetVerbosity(context.VerbosityNormal, collect)
+	if err := manager.CreateContext("work"); err != nil {
+		t.Fatal(err)
+	}
+	if len(messages) != 0 {
+		t.Errorf("Expected no diagnostics at normal verbosity, got %v", messages)
+	}
+
+	manager.SetVerbosity(context.VerbosityVerbose, collect)
+	if err := manager.SwitchToContext("work"); err != nil {
+		t.Fatal(err)
+	}
+	output := strings.Join(messages, "\n")
+	for _, want := range []string{"context 'work': ", "(JSON)", "switch took"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected %q in verbose output, got:\n%s", want, output)
+		}
+	}
+	if strings.Contains(output, "wrote ") {
+		t.Errorf("File writes are debug detail, got:\n%s", output)
+	}
+
+	messages = nil
+	manager.SetVerbosity(context.VerbosityDebug, collect)
+	if err := manager.SwitchToContext("work"); err != nil {
+		t.Fatal(err)
+	}
+	if output := strings.Join(messages, "\n"); !strings.Contains(output, "wrote ") {
+		t.Errorf("Expected file writes in debug output, got:\n%s", output)
+	}
+}
+
+func TestIntegration_VerboseFlag(t *testing.T) {
+	ith := NewIntegrationTestHelper(t)
+	defer ith.Cleanup()
+
+	ith.CreateSampleConfig()
+	if _, _, err := ith.RunCommand("-n", "work"); err != nil {
+		t.Fatal(err)
+	}
+
+	_, stderr, err := ith.RunCommand("work")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(stderr, "contexts dir:") {
+		t.Errorf("Expected no diagnostics without -v, got %s", stderr)
+	}
+
+	_, stderr, err = ith.RunCommand("-v", "work")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"scope: global", "contexts dir: " + ith.SettingsDir, "switch took"} {
+		if !strings.Contains(stderr, want) {
+			t.Errorf("Expected %q with -v, got:\n%s", want, stderr)
+		}
+	}
+	if strings.Contains(stderr, "debug:") {
+		t.Errorf("Expected no debug lines with -v, got:\n%s", stderr)
+	}
+
+	_, stderr, err = ith.RunCommand("-vv", "work")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(stderr, "debug: state file:") {
+		t.Errorf("Expected debug detail with -vv, got:\n%s", stderr)
+	}
+}
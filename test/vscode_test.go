@@ -0,0 +1,98 @@
+package test
+
+import (
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestIntegration_VSCodeInit(t *testing.T) {
+	ith := NewIntegrationTestHelper(t)
+	defer ith.Cleanup()
+
+	project := filepath.Join(ith.TempDir, "project")
+	settingsDir := filepath.Join(project, "opencode", "settings")
+	if err := os.MkdirAll(settingsDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"work", "demo"} {
+		if err := os.WriteFile(filepath.Join(settingsDir, name+".json"), []byte(`{"theme": "dark"}`), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	run := func(args ...string) (string, error) {
+		cmd := exec.Command(ith.BinaryPath, args...)
+		cmd.Dir = project
+		cmd.Env = ith.Env()
+		out, err := cmd.CombinedOutput()
+		return string(out), err
+	}
+
+	// Existing tasks are kept
+	tasksPath := filepath.Join(project, ".vscode", "tasks.json")
+	if err := os.MkdirAll(filepath.Dir(tasksPath), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(tasksPath, []byte(`{"version": "2.0.0", "tasks": [{"label": "build", "type": "shell", "command": "make"}]}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 2; i++ {
+		if out, err := run("vscode", "init"); err != nil {
+			t.Fatalf("vscode init failed: %v\n%s", err, out)
+		}
+	}
+
+	var tasks struct {
+		Tasks []struct {
+			Label string   `json:"label"`
+			Args  []string `json:"args"`
+		} `json:"tasks"`
+		Inputs []struct {
+			ID      string   `json:"id"`
+			Options []string `json:"options"`
+		} `json:"inputs"`
+	}
+	data, err := os.ReadFile(tasksPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(data, &tasks); err != nil {
+		t.Fatalf("tasks.json is not valid JSON: %v", err)
+	}
+	if len(tasks.Tasks) != 4 || tasks.Tasks[0].Label != "build" {
+		t.Fatalf("Expected the build task and 3 occtx tasks once each, got %+v", tasks.Tasks)
+	}
+	if args := tasks.Tasks[1].Args; len(args) != 2 || args[0] != "--in-project" || args[1] != "${input:occtxContext}" {
+		t.Errorf("Expected the switch task to use project contexts, got %v", args)
+	}
+	if len(tasks.Inputs) != 1 || strings.Join(tasks.Inputs[0].Options, ",") != "demo,work" {
+		t.Errorf("Expected one input listing the contexts, got %+v", tasks.Inputs)
+	}
+
+	data, err = os.ReadFile(filepath.Join(project, ".vscode", "settings.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Count(string(data), "https://opencode.ai/config.json") != 1 {
+		t.Errorf("Expected one schema entry, got:\n%s", data)
+	}
+
+	// Files with comments are left alone; --print still works
+	if err := os.WriteFile(tasksPath, []byte("{\n  // mine\n}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if out, err := run("vscode", "init"); err == nil || !strings.Contains(out, "--print") {
+		t.Errorf("Expected a merge error pointing to --print, got %v\n%s", err, out)
+	}
+	if data, _ := os.ReadFile(tasksPath); !strings.Contains(string(data), "// mine") {
+		t.Error("Expected the commented file to be untouched")
+	}
+	if out, err := run("vscode", "init", "--print"); err != nil || !strings.Contains(out, "occtx: Pick context") {
+		t.Errorf("Expected --print to print the tasks, got %v\n%s", err, out)
+	}
+}
@@ -0,0 +1,225 @@
+package test
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hungthai1401/occtx/internal/config"
+	"github.com/hungthai1401/occtx/internal/context"
+)
+
+// webhookReceiver records the payloads posted to it
+type webhookReceiver struct {
+	mu       sync.Mutex
+	payloads []map[string]interface{}
+	status   int
+}
+
+func (r *webhookReceiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	body, _ := io.ReadAll(req.Body)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.status != 0 {
+		w.WriteHeader(r.status)
+		return
+	}
+	var payload map[string]interface{}
+	json.Unmarshal(body, &payload)
+	r.payloads = append(r.payloads, payload)
+}
+
+func (r *webhookReceiver) setStatus(status int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.status = status
+}
+
+func (r *webhookReceiver) texts() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var texts []string
+	for _, payload := range r.payloads {
+		text, _ := payload["text"].(string)
+		texts = append(texts, text)
+	}
+	return texts
+}
+
+func writeWebhooksConfig(t *testing.T, configDir string, webhooks []config.WebhookConfig) {
+	t.Helper()
+	data, err := json.Marshal(map[string]interface{}{"webhooks": webhooks})
+	if err != nil {
+		t.Fatal(err)
+	}
+	writeOcctxConfig(t, configDir, string(data))
+}
+
+func TestManager_WebhookEvents(t *testing.T) {
+	th := NewTestHelper(t)
+	defer th.Cleanup()
+
+	receiver := &webhookReceiver{}
+	server := httptest.NewServer(receiver)
+	defer server.Close()
+
+	th.CreateSampleConfig()
+	setup := th.CreateManagerWithTempDir()
+	for _, name := range []string{"dev", "prod-eu"} {
+		if err := setup.CreateContext(name); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeWebhooksConfig(t, th.ConfigDir, []config.WebhookConfig{
+		{Name: "prod", URL: server.URL, Contexts: []string{"prod-*"}},
+		{Name: "deletes", URL: server.URL + "/deletes", Events: []string{config.EventDelete}},
+	})
+
+	manager := th.CreateManagerWithTempDir()
+	spooled := 0
+	manager.SetSpoolHandler(func() { spooled++ })
+	manager.SetOffline(true)
+
+	// Offline, events are only spooled
+	for _, name := range []string{"dev", "prod-eu", "dev"} {
+		if err := manager.SwitchToContext(name); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := manager.DeleteContext("prod-eu"); err != nil {
+		t.Fatal(err)
+	}
+	if spooled != 2 {
+		t.Errorf("Expected 2 spooled events (switch to prod-eu, delete), got %d", spooled)
+	}
+	if _, err := manager.FlushWebhooks(); !errors.Is(err, context.ErrOffline) {
+		t.Errorf("Expected flushing offline to fail, got %v", err)
+	}
+	pending, err := manager.PendingWebhookEvents()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pending["prod"] != 2 || pending["deletes"] != 1 {
+		t.Errorf("Expected 2 events for 'prod' and 1 for 'deletes', got %v", pending)
+	}
+
+	// A failing webhook keeps its events
+	receiver.setStatus(http.StatusInternalServerError)
+	manager.SetOffline(false)
+	delivery, err := manager.FlushWebhooks()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if delivery.Delivered != 0 || len(delivery.Failed) != 2 {
+		t.Errorf("Expected both webhooks to fail, got %+v", delivery)
+	}
+
+	receiver.setStatus(0)
+	delivery, err = manager.FlushWebhooks()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if delivery.Delivered != 3 || len(delivery.Failed) != 0 {
+		t.Errorf("Expected 3 deliveries, got %+v", delivery)
+	}
+	texts := receiver.texts()
+	if len(texts) != 3 || !strings.Contains(texts[0], "switched to 'prod-eu' (from 'dev')") {
+		t.Errorf("Expected the switch to be delivered first, got %q", texts)
+	}
+	if pending, _ := manager.PendingWebhookEvents(); len(pending) != 0 {
+		t.Errorf("Expected an empty spool, got %v", pending)
+	}
+}
+
+func TestManager_WebhookPolicyViolation(t *testing.T) {
+	th := NewTestHelper(t)
+	defer th.Cleanup()
+
+	th.CreateSampleConfig()
+	setup := th.CreateManagerWithTempDir()
+	if err := setup.CreateContext("work"); err != nil {
+		t.Fatal(err)
+	}
+	writeWebhooksConfig(t, th.ConfigDir, []config.WebhookConfig{
+		{Name: "audit", URL: "https://example.com/hook", Events: []string{config.EventPolicyViolation}},
+	})
+	policy := `{"rules": [{"type": "forbidden_keys", "keys": ["theme"]}]}`
+	if err := os.WriteFile(filepath.Join(th.ConfigDir, ".occtx-policy.json"), []byte(policy), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	manager := th.CreateManagerWithTempDir()
+	var violation *context.PolicyViolationError
+	if err := manager.SwitchToContext("work"); !errors.As(err, &violation) {
+		t.Fatalf("Expected a policy violation, got %v", err)
+	}
+	if pending, _ := manager.PendingWebhookEvents(); pending["audit"] != 1 {
+		t.Errorf("Expected the violation to be spooled, got %v", pending)
+	}
+}
+
+func TestManager_WebhookConfigValidation(t *testing.T) {
+	tests := []struct {
+		name     string
+		webhooks []config.WebhookConfig
+	}{
+		{"missing name", []config.WebhookConfig{{URL: "https://example.com"}}},
+		{"duplicate name", []config.WebhookConfig{{Name: "a", URL: "https://example.com"}, {Name: "a", URL: "https://example.org"}}},
+		{"not http", []config.WebhookConfig{{Name: "a", URL: "ftp://example.com"}}},
+		{"unknown event", []config.WebhookConfig{{Name: "a", URL: "https://example.com", Events: []string{"rename"}}}},
+		{"bad pattern", []config.WebhookConfig{{Name: "a", URL: "https://example.com", Contexts: []string{"prod-["}}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := config.ValidateWebhooks(tt.webhooks); err == nil {
+				t.Error("Expected a validation error")
+			}
+		})
+	}
+}
+
+func TestIntegration_WebhookBackgroundDelivery(t *testing.T) {
+	ith := NewIntegrationTestHelper(t)
+	defer ith.Cleanup()
+
+	receiver := &webhookReceiver{}
+	server := httptest.NewServer(receiver)
+	defer server.Close()
+
+	ith.CreateSampleConfig()
+	if _, stderr, err := ith.RunCommand("-n", "prod"); err != nil {
+		t.Fatalf("-n failed: %v\n%s", err, stderr)
+	}
+	writeWebhooksConfig(t, ith.ConfigDir, []config.WebhookConfig{{Name: "platform", URL: server.URL}})
+
+	if _, stderr, err := ith.RunCommand("prod"); err != nil {
+		t.Fatalf("switch failed: %v\n%s", err, stderr)
+	}
+
+	deadline := time.Now().Add(10 * time.Second)
+	for len(receiver.texts()) == 0 && time.Now().Before(deadline) {
+		time.Sleep(50 * time.Millisecond)
+	}
+	if texts := receiver.texts(); len(texts) != 1 || !strings.Contains(texts[0], "switched to 'prod'") {
+		t.Errorf("Expected the switch to be delivered in the background, got %q", texts)
+	}
+
+	// The delivery removes the event from the spool after posting it
+	spool := filepath.Join(ith.ConfigDir, ".occtx-spool", "*")
+	for entries, _ := filepath.Glob(spool); len(entries) > 0 && time.Now().Before(deadline); entries, _ = filepath.Glob(spool) {
+		time.Sleep(50 * time.Millisecond)
+	}
+
+	out, stderr, err := ith.RunCommand("webhooks", "list")
+	if err != nil || !strings.Contains(out, "platform") || strings.Contains(out, "pending") {
+		t.Errorf("Unexpected webhooks list: %v\n%s%s", err, out, stderr)
+	}
+}
@@ -0,0 +1,42 @@
+package test
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"golang.org/x/sys/unix"
+)
+
+func TestManager_SwitchKeepsExtendedAttributes(t *testing.T) {
+	th := NewTestHelper(t)
+	defer th.Cleanup()
+
+	th.CreateSampleConfig()
+	manager := th.CreateManagerWithTempDir()
+	if err := manager.CreateContext("work"); err != nil {
+		t.Fatal(err)
+	}
+
+	active := filepath.Join(th.ConfigDir, "opencode.json")
+	err := unix.Setxattr(active, "user.occtx.test", []byte("hardened"), 0)
+	if errors.Is(err, unix.ENOTSUP) || errors.Is(err, unix.EPERM) {
+		t.Skipf("extended attributes are not supported here: %v", err)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := manager.SwitchToContext("work"); err != nil {
+		t.Fatalf("Switch failed: %v", err)
+	}
+
+	value := make([]byte, 64)
+	n, err := unix.Getxattr(active, "user.occtx.test", value)
+	if err != nil {
+		t.Fatalf("Expected the switch to keep the extended attribute: %v", err)
+	}
+	if string(value[:n]) != "hardened" {
+		t.Errorf("Expected the attribute value to be kept, got %q", value[:n])
+	}
+}